@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"os/user"
 	"strings"
 	"syscall"
 	"time"
@@ -15,6 +18,8 @@ import (
 	_ "github.com/breml/rootcerts"
 	"github.com/qdm12/dns/pkg/unbound"
 	"github.com/qdm12/gluetun/internal/alpine"
+	"github.com/qdm12/gluetun/internal/bandwidth"
+	"github.com/qdm12/gluetun/internal/capabilities"
 	"github.com/qdm12/gluetun/internal/cli"
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/configuration/sources/env"
@@ -22,28 +27,52 @@ import (
 	mux "github.com/qdm12/gluetun/internal/configuration/sources/merge"
 	"github.com/qdm12/gluetun/internal/configuration/sources/secrets"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	vpntype "github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/diagnostics"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/dnsleak"
+	"github.com/qdm12/gluetun/internal/eventhook"
 	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/healthcheck"
 	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/localnetworks"
+	"github.com/qdm12/gluetun/internal/logfile"
+	"github.com/qdm12/gluetun/internal/metrics"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
+	"github.com/qdm12/gluetun/internal/notify"
 	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/openvpn/extract"
+	"github.com/qdm12/gluetun/internal/outbound"
 	"github.com/qdm12/gluetun/internal/portforward"
 	"github.com/qdm12/gluetun/internal/pprof"
 	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/provider/mullvad"
+	"github.com/qdm12/gluetun/internal/provider/nordvpn"
+	"github.com/qdm12/gluetun/internal/provider/privateinternetaccess"
+	"github.com/qdm12/gluetun/internal/provider/protonvpn"
 	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/publicip/echoip"
 	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
+	"github.com/qdm12/gluetun/internal/redact"
 	"github.com/qdm12/gluetun/internal/routing"
 	"github.com/qdm12/gluetun/internal/server"
 	"github.com/qdm12/gluetun/internal/shadowsocks"
+	"github.com/qdm12/gluetun/internal/speedtest"
 	"github.com/qdm12/gluetun/internal/storage"
+	"github.com/qdm12/gluetun/internal/sysctl"
+	"github.com/qdm12/gluetun/internal/syslog"
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/qdm12/gluetun/internal/timezone"
+	"github.com/qdm12/gluetun/internal/tracing"
 	"github.com/qdm12/gluetun/internal/tun"
 	updater "github.com/qdm12/gluetun/internal/updater/loop"
 	"github.com/qdm12/gluetun/internal/updater/resolver"
 	"github.com/qdm12/gluetun/internal/updater/unzip"
+	versioncheck "github.com/qdm12/gluetun/internal/version"
 	"github.com/qdm12/gluetun/internal/vpn"
+	"github.com/qdm12/gluetun/internal/wireguard"
 	"github.com/qdm12/golibs/command"
 	"github.com/qdm12/goshutdown"
 	"github.com/qdm12/goshutdown/goroutine"
@@ -73,7 +102,12 @@ func main() {
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
 	ctx, cancel := context.WithCancel(background)
 
-	logger := log.New(log.SetLevel(log.LevelInfo))
+	redactor := redact.New()
+	// Log timestamps are written by the timezone writers, since
+	// the timezone can change at runtime without changing time.Local.
+	programTimezone := timezone.New(time.Local)
+	logger := log.New(log.SetLevel(log.LevelInfo), log.SetTimeFormat(""),
+		log.SetWriters(programTimezone.Wrap(redactor.Wrap(os.Stdout))))
 
 	args := os.Args
 	tun := tun.New()
@@ -83,21 +117,34 @@ func main() {
 	cmder := command.NewCmder()
 
 	envReader := env.New(logger)
-	filesReader := files.New()
+	// The data directory is needed to locate the files read by the files source.
+	envSystem, err := envReader.ReadSystem()
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	filesReader := files.New(*envSystem.WithDefaults().DataDirectory)
 	secretsReader := secrets.New()
 	muxReader := mux.New(envReader, filesReader, secretsReader)
 
 	errorCh := make(chan error)
+	shutdownTimeoutCh := make(chan time.Duration, 1)
+	lifecycle := system.NewLifecycle()
 	go func() {
-		errorCh <- _main(ctx, buildInfo, args, logger, muxReader, tun, netLinker, cmder, cli)
+		errorCh <- _main(ctx, buildInfo, args, logger, redactor, programTimezone, muxReader,
+			tun, netLinker, cmder, cli, lifecycle, shutdownTimeoutCh)
 	}()
 
-	var err error
+	restart := false
 	select {
 	case signal := <-signalCh:
 		fmt.Println("")
 		logger.Warn("Caught OS signal " + signal.String() + ", shutting down")
 		cancel()
+	case request := <-lifecycle.Requests():
+		logger.Warn("Control server requested to " + request.String() + ", shutting down")
+		restart = request == system.RequestRestart
+		cancel()
 	case err = <-errorCh:
 		close(errorCh)
 		if err == nil { // expected exit such as healthcheck
@@ -107,7 +154,15 @@ func main() {
 		cancel()
 	}
 
-	const shutdownGracePeriod = 5 * time.Second
+	shutdownGracePeriod := 5 * time.Second
+	select {
+	case shutdownTimeout := <-shutdownTimeoutCh:
+		// leave time for the deferred teardown operations
+		// running after the ordered shutdown.
+		const teardownMargin = 2 * time.Second
+		shutdownGracePeriod = shutdownTimeout + teardownMargin
+	default:
+	}
 	timer := time.NewTimer(shutdownGracePeriod)
 	select {
 	case shutdownErr := <-errorCh:
@@ -123,6 +178,11 @@ func main() {
 		if err != nil {
 			os.Exit(1)
 		}
+		if restart {
+			err = restartProcess()
+			logger.Error("restarting: " + err.Error())
+			os.Exit(1)
+		}
 		os.Exit(0)
 	case <-timer.C:
 		logger.Warn("Shutdown timed out")
@@ -139,13 +199,18 @@ var (
 
 //nolint:gocognit,gocyclo,maintidx
 func _main(ctx context.Context, buildInfo models.BuildInformation,
-	args []string, logger log.LoggerInterface, source Source,
+	args []string, logger log.LoggerInterface, redactor *redact.Redactor,
+	programTimezone *timezone.Timezone, source Source,
 	tun Tun, netLinker netLinker, cmder command.RunStarter,
-	cli clier) error {
+	cli clier, lifecycle *system.Lifecycle, shutdownTimeout chan<- time.Duration) error {
 	if len(args) > 1 { // cli operation
 		switch args[1] {
 		case "healthcheck":
 			return cli.HealthCheck(ctx, source, logger)
+		case "completion":
+			return cli.Completion(args[2:])
+		case "check-custom":
+			return cli.CheckCustom(ctx, args[2:], source, netLinker)
 		case "clientkey":
 			return cli.ClientKey(args[2:])
 		case "openvpnconfig":
@@ -154,6 +219,28 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 			return cli.Update(ctx, args[2:], logger)
 		case "format-servers":
 			return cli.FormatServers(args[2:])
+		case "list-servers":
+			return cli.ListServers(args[2:])
+		case "import-servers":
+			return cli.ImportServers(ctx, args[2:], logger)
+		case "benchmark":
+			return cli.Benchmark(ctx, args[2:])
+		case "sanitize-ovpn":
+			return cli.SanitizeOpenVPN(args[2:])
+		case "genconfig":
+			return cli.GenConfig(args[2:])
+		case "mullvad-devices":
+			return cli.MullvadDevices(ctx, args[2:])
+		case "wgkey":
+			return cli.WireguardKey(ctx, args[2:])
+		case "speedtest":
+			return cli.SpeedTest(ctx, args[2:])
+		case "dnsleaktest":
+			return cli.DNSLeakTest(ctx, args[2:])
+		case "test":
+			return cli.ConnectivityTest(ctx, args[2:], logger, source, netLinker, cmder, tun)
+		case "diagnose":
+			return cli.Diagnose(ctx, source, netLinker, cmder, tun)
 		default:
 			return fmt.Errorf("%w: %s", errCommandUnknown, args[1])
 		}
@@ -185,6 +272,46 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		return err
 	}
 
+	redactor.AddSecrets(allSettings.Secrets()...)
+	dataDirectory := *allSettings.System.DataDirectory
+	runtimeDirectory := *allSettings.System.RuntimeDirectory
+
+	const clientTimeout = 15 * time.Second
+	httpClient := &http.Client{Timeout: clientTimeout}
+
+	puid, pgid := int(*allSettings.System.PUID), int(*allSettings.System.PGID)
+	runningAsRoot := os.Geteuid() == 0
+	if !runningAsRoot {
+		puid, pgid, err = setupNonRoot(logger, puid, pgid)
+		if err != nil {
+			return fmt.Errorf("setting up to run as non-root user: %w", err)
+		}
+	}
+
+	syscall.Umask(int(*allSettings.System.Umask))
+	fileWriter := system.NewFileWriter(puid, pgid)
+
+	// The private key must be fetched before the firewall
+	// blocks all traffic not going through the VPN.
+	err = fetchWireguardPrivateKey(ctx, httpClient, fileWriter,
+		&allSettings.VPN, netLinker, dataDirectory, logger)
+	if err != nil {
+		return fmt.Errorf("fetching Wireguard private key: %w", err)
+	}
+	redactor.AddSecrets(*allSettings.VPN.Wireguard.PrivateKey)
+
+	err = fetchOpenVPNCredentials(ctx, httpClient, &allSettings.VPN, allSettings.Updater)
+	if err != nil {
+		return fmt.Errorf("fetching OpenVPN credentials: %w", err)
+	}
+	redactor.AddSecrets(*allSettings.VPN.OpenVPN.Password)
+
+	err = checkCredentials(ctx, httpClient, allSettings.VPN, fileWriter,
+		dataDirectory, logger)
+	if err != nil {
+		return fmt.Errorf("checking VPN credentials: %w", err)
+	}
+
 	// Note: no need to validate minimal settings for the firewall:
 	// - global log level is parsed from source
 	// - firewall Debug and Enabled are booleans parsed from source
@@ -192,11 +319,59 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	logger.Patch(log.SetLevel(*allSettings.Log.Level))
 	netLinker.PatchLoggerLevel(*allSettings.Log.Level)
 
+	if allSettings.System.Timezone != "" {
+		location, err := time.LoadLocation(allSettings.System.Timezone)
+		if err != nil {
+			return fmt.Errorf("loading timezone: %w", err)
+		}
+		programTimezone.SetLocation(location)
+	}
+
+	if *allSettings.Log.File.Path != "" {
+		fileSettings := allSettings.Log.File
+		logFileWriter, err := logfile.New(*fileSettings.Path, *fileSettings.MaxSize,
+			*fileSettings.MaxAge, *fileSettings.MaxBackups)
+		if err != nil {
+			return fmt.Errorf("creating log file writer: %w", err)
+		}
+		defer func() {
+			_ = logFileWriter.Close()
+		}()
+		if *fileSettings.Stdout {
+			logger.Patch(log.AddWriters(programTimezone.Wrap(redactor.Wrap(logFileWriter))))
+		} else {
+			logger.Patch(log.SetWriters(programTimezone.Wrap(redactor.Wrap(logFileWriter))))
+		}
+	}
+
+	if *allSettings.Log.Syslog.Address != "" {
+		syslogSettings := allSettings.Log.Syslog
+		syslogWriter, err := syslog.New(syslogSettings.Protocol,
+			*syslogSettings.Address, syslogSettings.Facility)
+		if err != nil {
+			return fmt.Errorf("creating syslog writer: %w", err)
+		}
+		defer func() {
+			_ = syslogWriter.Close()
+		}()
+		logger.Patch(log.AddWriters(programTimezone.Wrap(redactor.Wrap(syslogWriter))))
+	}
+
+	if *allSettings.Diagnostics.Enabled {
+		// Diagnostics run before the firewall blocks
+		// traffic, so the DNS egress check is meaningful.
+		diagnosticsLogger := logger.New(log.SetComponent("diagnostics"))
+		diagnoser := diagnostics.New(tun, netLinker, cmder, sysctl.New(diagnosticsLogger))
+		report := diagnoser.Run(ctx, allSettings)
+		logDiagnosticsReport(diagnosticsLogger, report)
+	}
+
 	routingLogger := logger.New(log.SetComponent("routing"))
 	if *allSettings.Firewall.Debug { // To remove in v4
 		routingLogger.Patch(log.SetLevel(log.LevelDebug))
 	}
 	routingConf := routing.New(netLinker, routingLogger)
+	routingConf.SetInterfaces(allSettings.Firewall.Interfaces)
 
 	defaultRoutes, err := routingConf.DefaultRoutes()
 	if err != nil {
@@ -217,18 +392,55 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	if err != nil {
 		return err
 	}
+	firewallConf.SetRestoreHostRules(*allSettings.Firewall.RestoreHostRules)
+
+	err = firewallConf.SetMulticastDiscovery(ctx, *allSettings.Firewall.MulticastDiscovery)
+	if err != nil {
+		return err
+	}
+
+	if *allSettings.WireguardServer.Enabled {
+		err = firewallConf.SetWireguardServer(ctx, allSettings.WireguardServer.Interface,
+			allSettings.WireguardServer.Address)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *allSettings.LANGateway.Enabled {
+		err = firewallConf.SetLANGateway(ctx, allSettings.LANGateway.Subnets)
+		if err != nil {
+			return err
+		}
+	}
 
 	if *allSettings.Firewall.Enabled {
 		err = firewallConf.SetEnabled(ctx, true)
 		if err != nil {
 			return err
 		}
+		if *allSettings.System.HostMode {
+			// Leave the host without the firewall policies blocking
+			// its traffic once the program exits, restoring its own
+			// rules if FIREWALL_RESTORE_HOST_RULES is on.
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(),
+					allSettings.Shutdown.FirewallTimeout)
+				defer cancel()
+				if err := firewallConf.SetEnabled(ctx, false); err != nil {
+					firewallLogger.Error("cannot disable firewall: " + err.Error())
+				}
+			}()
+		}
 	}
 
 	// TODO run this in a loop or in openvpn to reload from file without restarting
 	storageLogger := logger.New(log.SetComponent("storage"))
-	storage, err := storage.New(storageLogger, constants.ServersData)
+	storage, err := storage.New(storageLogger, constants.ServersData(dataDirectory),
+		*allSettings.System.ServersFilePermissions, fileWriter,
+		allSettings.System.ServersMergeStrategy)
 	if err != nil {
+		logReadOnlyTip(logger, err, dataDirectory, "volume")
 		return err
 	}
 
@@ -248,29 +460,33 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		return fmt.Errorf("creating Pprof server: %w", err)
 	}
 
-	puid, pgid := int(*allSettings.System.PUID), int(*allSettings.System.PGID)
-
-	const clientTimeout = 15 * time.Second
-	httpClient := &http.Client{Timeout: clientTimeout}
 	// Create configurators
 	alpineConf := alpine.New()
 	ovpnConf := openvpn.New(
 		logger.New(log.SetComponent("openvpn configurator")),
-		cmder, puid, pgid)
+		cmder, fileWriter, runtimeDirectory)
 	dnsCrypto := dnscrypto.New(httpClient, "", "")
 	const cacertsPath = "/etc/ssl/certs/ca-certificates.crt"
-	dnsConf := unbound.NewConfigurator(nil, cmder, dnsCrypto,
-		"/etc/unbound", "/usr/sbin/unbound", cacertsPath)
+	unboundProcess := dns.NewProcess(cmder)
+	unboundDirectory := constants.UnboundDirectory(runtimeDirectory)
+	dnsConf := unbound.NewConfigurator(nil, unboundProcess, dnsCrypto,
+		unboundDirectory, "/usr/sbin/unbound", cacertsPath)
 
-	err = printVersions(ctx, logger, []printVersionElement{
-		{name: "Alpine", getVersion: alpineConf.Version},
+	hostMode := *allSettings.System.HostMode
+	versionElements := []printVersionElement{
 		{name: "OpenVPN 2.5", getVersion: ovpnConf.Version25},
 		{name: "OpenVPN 2.6", getVersion: ovpnConf.Version26},
 		{name: "Unbound", getVersion: dnsConf.Version},
 		{name: "IPtables", getVersion: func(ctx context.Context) (version string, err error) {
 			return firewall.Version(ctx, cmder)
 		}},
-	})
+	}
+	if !hostMode {
+		versionElements = append([]printVersionElement{
+			{name: "Alpine", getVersion: alpineConf.Version},
+		}, versionElements...)
+	}
+	err = printVersions(ctx, logger, versionElements)
 	if err != nil {
 		return err
 	}
@@ -281,28 +497,62 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		logger.Warn(warning)
 	}
 
-	if err := os.MkdirAll("/tmp/gluetun", 0644); err != nil {
-		return err
+	const dirPerms = 0755
+	for _, dir := range []string{runtimeDirectory, unboundDirectory} {
+		if err := fileWriter.MkdirAll(dir, dirPerms); err != nil {
+			logReadOnlyTip(logger, err, runtimeDirectory, "tmpfs")
+			return err
+		}
 	}
-	if err := os.MkdirAll("/gluetun", 0644); err != nil {
+	if err := fileWriter.MkdirAll(dataDirectory, dirPerms); err != nil {
+		logReadOnlyTip(logger, err, dataDirectory, "volume")
 		return err
 	}
 
-	const defaultUsername = "nonrootuser"
-	nonRootUsername, err := alpineConf.CreateUser(defaultUsername, puid)
-	if err != nil {
-		return fmt.Errorf("creating user: %w", err)
-	}
-	if nonRootUsername != defaultUsername {
-		logger.Info("using existing username " + nonRootUsername + " corresponding to user id " + fmt.Sprint(puid))
-	}
-	// set it for Unbound
-	// TODO remove this when migrating to qdm12/dns v2
-	allSettings.DNS.DoT.Unbound.Username = nonRootUsername
-	allSettings.VPN.OpenVPN.ProcessUser = nonRootUsername
+	switch {
+	case runningAsRoot && hostMode:
+		// Users of the host are not modified, so the existing
+		// user corresponding to PUID is used if there is one.
+		hostUser, err := user.LookupId(fmt.Sprint(puid))
+		if err != nil {
+			logger.Warn("no host user found for user id " + fmt.Sprint(puid) +
+				", Unbound and OpenVPN keep running as root: " + err.Error())
+			allSettings.DNS.DoT.Unbound.Username = ""
+			allSettings.VPN.OpenVPN.ProcessUser = "root"
+			break
+		}
+		allSettings.DNS.DoT.Unbound.Username = hostUser.Username
+		allSettings.VPN.OpenVPN.ProcessUser = hostUser.Username
 
-	if err := os.Chown("/etc/unbound", puid, pgid); err != nil {
-		return err
+		if err := os.Chown(unboundDirectory, puid, pgid); err != nil {
+			return err
+		}
+	case runningAsRoot:
+		const defaultUsername = "nonrootuser"
+		nonRootUsername, err := alpineConf.CreateUser(defaultUsername, puid, pgid)
+		if err != nil {
+			if errors.Is(err, syscall.EROFS) {
+				logger.Warn("💡 Tip: the root filesystem is read-only, " +
+					"set PUID to 1000 to use the existing user nonrootuser")
+			}
+			return fmt.Errorf("creating user: %w", err)
+		}
+		if nonRootUsername != defaultUsername {
+			logger.Info("using existing username " + nonRootUsername + " corresponding to user id " + fmt.Sprint(puid))
+		}
+		// set it for Unbound
+		// TODO remove this when migrating to qdm12/dns v2
+		allSettings.DNS.DoT.Unbound.Username = nonRootUsername
+		allSettings.VPN.OpenVPN.ProcessUser = nonRootUsername
+
+		if err := os.Chown(unboundDirectory, puid, pgid); err != nil {
+			return err
+		}
+	default:
+		// Privileges cannot be dropped without root, so Unbound
+		// and OpenVPN keep running as the current user.
+		allSettings.DNS.DoT.Unbound.Username = ""
+		allSettings.VPN.OpenVPN.ProcessUser = "root"
 	}
 
 	if err := routingConf.Setup(); err != nil {
@@ -318,10 +568,22 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		}
 	}()
 
-	if err := firewallConf.SetOutboundSubnets(ctx, allSettings.Firewall.OutboundSubnets); err != nil {
-		return err
+	sysctlLogger := logger.New(log.SetComponent("sysctl"))
+	sysctlConf := sysctl.New(sysctlLogger)
+	err = sysctlConf.Apply(sysctl.Required(allSettings.VPN,
+		allSettings.WireguardServer, allSettings.LANGateway))
+	if err != nil {
+		return fmt.Errorf("applying kernel parameters: %w", err)
 	}
-	if err := routingConf.SetOutboundRoutes(allSettings.Firewall.OutboundSubnets); err != nil {
+	defer func() {
+		if err := sysctlConf.Restore(); err != nil {
+			sysctlLogger.Error("cannot restore kernel parameters: " + err.Error())
+		}
+	}()
+
+	outboundUpdater := outbound.New(allSettings.Firewall, net.DefaultResolver,
+		firewallConf, routingConf, logger.New(log.SetComponent("outbound")))
+	if err := outboundUpdater.Update(ctx); err != nil {
 		return err
 	}
 
@@ -331,12 +593,9 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	}
 
 	const tunDevice = "/dev/net/tun"
-	if err := tun.Check(tunDevice); err != nil {
-		logger.Info(err.Error() + "; creating it...")
-		err = tun.Create(tunDevice)
-		if err != nil {
-			return err
-		}
+	err = tun.Setup(tunDevice, logger)
+	if err != nil {
+		return err
 	}
 
 	for _, port := range allSettings.Firewall.InputPorts {
@@ -349,7 +608,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	} // TODO move inside firewall?
 
 	// Shutdown settings
-	const totalShutdownTimeout = 3 * time.Second
+	shutdownTimeout <- allSettings.Shutdown.Timeout
 	const defaultShutdownTimeout = 400 * time.Millisecond
 	defaultShutdownOnSuccess := func(goRoutineName string) {
 		logger.Info(goRoutineName + ": terminated ✔️")
@@ -363,8 +622,23 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	controlGroupHandler := goshutdown.NewGroupHandler("control", defaultGroupOptions...)
 	tickersGroupHandler := goshutdown.NewGroupHandler("tickers", defaultGroupOptions...)
+	proxiesGroupHandler := goshutdown.NewGroupHandler("proxies",
+		group.OptionTimeout(allSettings.Shutdown.ProxiesTimeout),
+		group.OptionOnSuccess(defaultShutdownOnSuccess))
 	otherGroupHandler := goshutdown.NewGroupHandler("other", defaultGroupOptions...)
 
+	outboundHandler, outboundCtx, outboundDone := goshutdown.NewGoRoutineHandler(
+		"outbound hostnames", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go outboundUpdater.Run(outboundCtx, outboundDone)
+	tickersGroupHandler.Add(outboundHandler)
+
+	localNetworksWatcher := localnetworks.New(*allSettings.Firewall.LocalNetworksPeriod,
+		localNetworks, firewallConf, routingConf, logger.New(log.SetComponent("local networks")))
+	localNetworksHandler, localNetworksCtx, localNetworksDone := goshutdown.NewGoRoutineHandler(
+		"local networks", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go localNetworksWatcher.Run(localNetworksCtx, localNetworksDone)
+	tickersGroupHandler.Add(localNetworksHandler)
+
 	if *allSettings.Pprof.Enabled {
 		// TODO run in run loop so this can be patched at runtime
 		pprofReady := make(chan struct{})
@@ -374,31 +648,91 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		<-pprofReady
 	}
 
+	tracer := tracing.New(allSettings.Tracing, httpClient,
+		logger.New(log.SetComponent("tracing")))
+	tracingHandler, tracingCtx, tracingDone := goshutdown.NewGoRoutineHandler(
+		"tracing", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go tracer.Run(tracingCtx, tracingDone)
+	otherGroupHandler.Add(tracingHandler)
+
+	notifier := notify.New(allSettings.Notify, httpClient,
+		logger.New(log.SetComponent("notify")))
+	notifyHandler, notifyCtx, notifyDone := goshutdown.NewGoRoutineHandler(
+		"notify", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go notifier.Run(notifyCtx, notifyDone)
+	otherGroupHandler.Add(notifyHandler)
+
+	eventHooks := eventhook.New(allSettings.EventHooks, cmder,
+		logger.New(log.SetComponent("event hooks")))
+	eventHooksHandler, eventHooksCtx, eventHooksDone := goshutdown.NewGoRoutineHandler(
+		"event hooks", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go eventHooks.Run(eventHooksCtx, eventHooksDone)
+	otherGroupHandler.Add(eventHooksHandler)
+
+	versionChecker := versioncheck.NewChecker(buildInfo, *allSettings.Version.UpdateCheckPeriod,
+		httpClient, notifier, logger.New(log.SetComponent("version")))
+	versionHandler, versionCtx, versionDone := goshutdown.NewGoRoutineHandler(
+		"version checker", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go versionChecker.Run(versionCtx, versionDone)
+	tickersGroupHandler.Add(versionHandler)
+
+	// The Wireguard server listens on the port forwarded once known.
+	wireguardServer := wireguard.NewServer(allSettings.WireguardServer,
+		constants.WireguardServerPeers(dataDirectory), fileWriter, netLinker,
+		logger.New(log.SetComponent("wireguard server")))
+
 	portForwardLogger := logger.New(log.SetComponent("port forwarding"))
 	portForwardLooper := portforward.NewLoop(allSettings.VPN.Provider.PortForwarding,
-		httpClient, firewallConf, portForwardLogger, puid, pgid)
+		httpClient, firewallConf, portForwardLogger, tracer, notifier, eventHooks, fileWriter,
+		wireguardServer)
 	portForwardHandler, portForwardCtx, portForwardDone := goshutdown.NewGoRoutineHandler(
-		"port forwarding", goroutine.OptionTimeout(time.Second))
+		"port forwarding", goroutine.OptionTimeout(allSettings.Shutdown.PortForwardingTimeout))
 	go portForwardLooper.Run(portForwardCtx, portForwardDone)
 
+	bandwidthAccountant := bandwidth.New(routing.DefaultRoutesInterfaces(defaultRoutes),
+		constants.BandwidthData(dataDirectory), fileWriter, logger.New(log.SetComponent("bandwidth")))
+	bandwidthHandler, bandwidthCtx, bandwidthDone := goshutdown.NewGoRoutineHandler(
+		"bandwidth", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go bandwidthAccountant.Run(bandwidthCtx, bandwidthDone)
+	otherGroupHandler.Add(bandwidthHandler)
+
+	metricsEmitter := metrics.New(allSettings.Metrics, portForwardLooper,
+		bandwidthAccountant, logger.New(log.SetComponent("metrics")))
+	metricsHandler, metricsCtx, metricsDone := goshutdown.NewGoRoutineHandler(
+		"metrics", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go metricsEmitter.Run(metricsCtx, metricsDone)
+	otherGroupHandler.Add(metricsHandler)
+
 	unboundLogger := logger.New(log.SetComponent("dns over tls"))
-	unboundLooper := dns.NewLoop(dnsConf, allSettings.DNS, httpClient,
-		unboundLogger)
+	unboundLooper := dns.NewLoop(dnsConf, unboundProcess, allSettings.DNS, httpClient,
+		fileWriter, unboundLogger, *allSettings.System.ResolvConfPath, unboundDirectory)
 	dnsHandler, dnsCtx, dnsDone := goshutdown.NewGoRoutineHandler(
-		"unbound", goroutine.OptionTimeout(defaultShutdownTimeout))
+		"unbound", goroutine.OptionTimeout(allSettings.Shutdown.DNSTimeout))
 	// wait for unboundLooper.Restart or its ticker launched with RunRestartTicker
 	go unboundLooper.Run(dnsCtx, dnsDone)
-	otherGroupHandler.Add(dnsHandler)
 
 	dnsTickerHandler, dnsTickerCtx, dnsTickerDone := goshutdown.NewGoRoutineHandler(
 		"dns ticker", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go unboundLooper.RunRestartTicker(dnsTickerCtx, dnsTickerDone)
 	controlGroupHandler.Add(dnsTickerHandler)
 
+	dnsUpstreamsHandler, dnsUpstreamsCtx, dnsUpstreamsDone := goshutdown.NewGoRoutineHandler(
+		"dns upstreams check", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go unboundLooper.RunUpstreamsCheck(dnsUpstreamsCtx, dnsUpstreamsDone)
+	controlGroupHandler.Add(dnsUpstreamsHandler)
+
 	ipFetcher := ipinfo.New(httpClient)
-	publicIPLooper := publicip.NewLoop(ipFetcher,
+	setTimezoneFromIP := allSettings.System.Timezone == "" &&
+		*allSettings.System.TimezoneFromPublicIP
+	// The public IP loop uses its own fetcher with a client without
+	// timeout since each fetch is bounded by its timeout setting.
+	var publicIPFetcher publicip.Fetcher = ipinfo.New(&http.Client{})
+	if *allSettings.PublicIP.API.URL != "" {
+		publicIPFetcher = echoip.New(&http.Client{}, allSettings.PublicIP.API)
+	}
+	publicIPLooper := publicip.NewLoop(publicIPFetcher,
 		logger.New(log.SetComponent("ip getter")),
-		allSettings.PublicIP, puid, pgid)
+		allSettings.PublicIP, fileWriter, programTimezone, setTimezoneFromIP)
 	pubIPHandler, pubIPCtx, pubIPDone := goshutdown.NewGoRoutineHandler(
 		"public IP", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go publicIPLooper.Run(pubIPCtx, pubIPDone)
@@ -415,19 +749,33 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	parallelResolver := resolver.NewParallelResolver(allSettings.Updater.DNSAddress)
 	openvpnFileExtractor := extract.New()
 	providers := provider.NewProviders(storage, time.Now, updaterLogger,
-		httpClient, unzipper, parallelResolver, ipFetcher, openvpnFileExtractor)
+		httpClient, unzipper, parallelResolver, ipFetcher, openvpnFileExtractor,
+		*allSettings.Updater.ProtonEmail, *allSettings.Updater.ProtonPassword,
+		dataDirectory, runtimeDirectory)
 
 	vpnLogger := logger.New(log.SetComponent("vpn"))
-	vpnLooper := vpn.NewLoop(allSettings.VPN, ipv6Supported, allSettings.Firewall.VPNInputPorts,
+	pauseAllowLAN := allSettings.Firewall.PauseKillSwitch == "allow_lan"
+	vpnInputPorts := allSettings.Firewall.VPNInputPorts
+	if *allSettings.WireguardServer.Enabled {
+		// Wireguard server peers connect through the VPN interface.
+		vpnInputPorts = append(vpnInputPorts, allSettings.WireguardServer.ListenPort)
+	}
+	vpnLooper := vpn.NewLoop(allSettings.VPN, ipv6Supported, vpnInputPorts,
+		pauseAllowLAN, dataDirectory, runtimeDirectory,
 		providers, storage, ovpnConf, netLinker, firewallConf, routingConf, portForwardLooper,
-		cmder, publicIPLooper, unboundLooper, vpnLogger, httpClient,
-		buildInfo, *allSettings.Version.Enabled)
+		cmder, publicIPLooper, unboundLooper, tracer, metricsEmitter, notifier, eventHooks, bandwidthAccountant,
+		fileWriter, vpnLogger, httpClient, buildInfo, *allSettings.Version.Enabled)
 	vpnHandler, vpnCtx, vpnDone := goshutdown.NewGoRoutineHandler(
-		"vpn", goroutine.OptionTimeout(time.Second))
+		"vpn", goroutine.OptionTimeout(allSettings.Shutdown.VPNTimeout))
 	go vpnLooper.Run(vpnCtx, vpnDone)
 
+	vpnScheduleHandler, vpnScheduleCtx, vpnScheduleDone := goshutdown.NewGoRoutineHandler(
+		"vpn schedule", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go vpnLooper.RunSchedule(vpnScheduleCtx, vpnScheduleDone, programTimezone)
+	tickersGroupHandler.Add(vpnScheduleHandler)
+
 	updaterLooper := updater.NewLoop(allSettings.Updater,
-		providers, storage, httpClient, updaterLogger)
+		providers, storage, vpnLooper, httpClient, updaterLogger, tracer, notifier)
 	updaterHandler, updaterCtx, updaterDone := goshutdown.NewGoRoutineHandler(
 		"updater", goroutine.OptionTimeout(defaultShutdownTimeout))
 	// wait for updaterLooper.Restart() or its ticket launched with RunRestartTicker
@@ -441,27 +789,60 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	httpProxyLooper := httpproxy.NewLoop(
 		logger.New(log.SetComponent("http proxy")),
-		allSettings.HTTPProxy)
+		allSettings.HTTPProxy, storage, vpnLooper)
 	httpProxyHandler, httpProxyCtx, httpProxyDone := goshutdown.NewGoRoutineHandler(
 		"http proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go httpProxyLooper.Run(httpProxyCtx, httpProxyDone)
-	otherGroupHandler.Add(httpProxyHandler)
+	proxiesGroupHandler.Add(httpProxyHandler)
 
-	shadowsocksLooper := shadowsocks.NewLoop(allSettings.Shadowsocks,
-		logger.New(log.SetComponent("shadowsocks")))
+	var shadowsocksDemander shadowsocks.VPNDemander
+	if *allSettings.VPN.OnDemand {
+		shadowsocksDemander = vpnLooper
+	}
+	shadowsocksLooper := shadowsocks.NewLoop(allSettings.Shadowsocks, cmder,
+		shadowsocksDemander, logger.New(log.SetComponent("shadowsocks")))
 	shadowsocksHandler, shadowsocksCtx, shadowsocksDone := goshutdown.NewGoRoutineHandler(
 		"shadowsocks proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go shadowsocksLooper.Run(shadowsocksCtx, shadowsocksDone)
-	otherGroupHandler.Add(shadowsocksHandler)
+	proxiesGroupHandler.Add(shadowsocksHandler)
+
+	for _, instance := range allSettings.Shadowsocks.Instances {
+		looper := shadowsocks.NewLoop(allSettings.Shadowsocks.InstanceSettings(instance), cmder,
+			shadowsocksDemander, logger.New(log.SetComponent("shadowsocks "+instance.Name)))
+		handler, instanceCtx, instanceDone := goshutdown.NewGoRoutineHandler(
+			"shadowsocks proxy "+instance.Name, goroutine.OptionTimeout(defaultShutdownTimeout))
+		go looper.Run(instanceCtx, instanceDone)
+		proxiesGroupHandler.Add(handler)
+	}
+
+	wireguardServerHandler, wireguardServerCtx, wireguardServerDone := goshutdown.NewGoRoutineHandler(
+		"wireguard server", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go wireguardServer.Run(wireguardServerCtx, wireguardServerDone)
+	proxiesGroupHandler.Add(wireguardServerHandler)
+
+	// The speed test uses its own client since its duration is
+	// bounded by its timeout setting instead of the client timeout.
+	speedTester := speedtest.New(allSettings.SpeedTest, &http.Client{})
+	dnsLeakTester := dnsleak.New(httpClient)
 
 	controlServerAddress := *allSettings.ControlServer.Address
 	controlServerLogging := *allSettings.ControlServer.Log
 	httpServerHandler, httpServerCtx, httpServerDone := goshutdown.NewGoRoutineHandler(
 		"http server", goroutine.OptionTimeout(defaultShutdownTimeout))
 	httpServer, err := server.New(httpServerCtx, controlServerAddress, controlServerLogging,
-		logger.New(log.SetComponent("http server")),
-		buildInfo, vpnLooper, portForwardLooper, unboundLooper, updaterLooper, publicIPLooper,
-		storage, ipv6Supported)
+		*allSettings.ControlServer.AuditLogPath, fileWriter, logger.New(log.SetComponent("http server")),
+		versionChecker, vpnLooper, portForwardLooper, unboundLooper, updaterLooper, publicIPLooper,
+		bandwidthAccountant, speedTester, dnsLeakTester, wireguardServer, storage,
+		lifecycle,
+		server.RouteGroup{
+			Enabled: *allSettings.ControlServer.ReadOnlyEnabled,
+			APIKey:  *allSettings.ControlServer.ReadOnlyAPIKey,
+		},
+		server.RouteGroup{
+			Enabled: *allSettings.ControlServer.AdminEnabled,
+			APIKey:  *allSettings.ControlServer.AdminAPIKey,
+		},
+		ipv6Supported)
 	if err != nil {
 		return fmt.Errorf("setting up control server: %w", err)
 	}
@@ -471,23 +852,41 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	controlGroupHandler.Add(httpServerHandler)
 
 	healthLogger := logger.New(log.SetComponent("healthcheck"))
-	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger, vpnLooper)
+	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger, vpnLooper,
+		tracer, metricsEmitter)
 	healthServerHandler, healthServerCtx, healthServerDone := goshutdown.NewGoRoutineHandler(
 		"HTTP health server", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go healthcheckServer.Run(healthServerCtx, healthServerDone)
 
 	orderHandler := goshutdown.NewOrderHandler("gluetun",
-		order.OptionTimeout(totalShutdownTimeout),
+		order.OptionTimeout(allSettings.Shutdown.Timeout),
 		order.OptionOnSuccess(defaultShutdownOnSuccess),
 		order.OptionOnFailure(defaultShutdownOnFailure))
+	// Port forwarding, proxies and DNS are stopped before tearing down
+	// the VPN tunnel, and the firewall is disabled last in host mode
+	// by its deferred function.
 	orderHandler.Append(controlGroupHandler, tickersGroupHandler, healthServerHandler,
-		vpnHandler, portForwardHandler, otherGroupHandler)
+		portForwardHandler, proxiesGroupHandler, dnsHandler, vpnHandler, otherGroupHandler)
 
 	// Start VPN for the first time in a blocking call
 	// until the VPN is launched
-	_, _ = vpnLooper.ApplyStatus(ctx, constants.Running) // TODO option to disable with variable
+	if *allSettings.VPN.OnDemand {
+		logger.Info("VPN on demand: waiting for the VPN to be started " +
+			"through the control server or by an HTTP proxy client")
+	} else {
+		_, _ = vpnLooper.ApplyStatus(ctx, constants.Running)
+	}
 
-	<-ctx.Done()
+	select {
+	case <-ctx.Done():
+	case err = <-vpnLooper.Fatal():
+		logger.Error(err.Error())
+		shutdownErr := orderHandler.Shutdown(context.Background())
+		if shutdownErr != nil {
+			return fmt.Errorf("%w (shutdown: %s)", err, shutdownErr)
+		}
+		return err
+	}
 
 	return orderHandler.Shutdown(context.Background())
 }
@@ -497,10 +896,194 @@ type printVersionElement struct {
 	getVersion func(ctx context.Context) (version string, err error)
 }
 
+// logDiagnosticsReport logs each result of the diagnostics report
+// given at a log level matching its status.
+func logDiagnosticsReport(logger log.LoggerInterface, report diagnostics.Report) {
+	for _, result := range report.Results {
+		switch result.Status {
+		case diagnostics.StatusFail:
+			logger.Error(result.String())
+		case diagnostics.StatusWarn:
+			logger.Warn(result.String())
+		default:
+			logger.Info(result.String())
+		}
+	}
+}
+
 type infoer interface {
 	Info(s string)
 }
 
+var errCapabilitiesMissing = errors.New("capabilities are missing")
+
+// fetchWireguardPrivateKey sets the Wireguard private key and default
+// interface addresses if the private key is not set, either using the
+// NordVPN access token if the provider is NordVPN and the access token
+// is set, or by registering a device on the Mullvad account if the
+// provider is Mullvad and the account number is set.
+func fetchWireguardPrivateKey(ctx context.Context, client *http.Client,
+	fileWriter *system.FileWriter, vpnSettings *settings.VPN, ipv6Checker cli.IPv6Checker,
+	dataDirectory string, logger infoer) (err error) {
+	wireguard := &vpnSettings.Wireguard
+	if vpnSettings.Type != vpntype.Wireguard || *wireguard.PrivateKey != "" {
+		return nil
+	}
+
+	switch {
+	case *vpnSettings.Provider.Name == providers.Nordvpn && *wireguard.AccessToken != "":
+		privateKey, err := nordvpn.FetchWireguardPrivateKey(ctx, client, *wireguard.AccessToken)
+		if err != nil {
+			return err
+		}
+		wireguard.PrivateKey = &privateKey
+
+		if len(wireguard.Addresses) == 0 {
+			// NordLynx uses the same interface address for all users
+			wireguard.Addresses = []netip.Prefix{
+				netip.PrefixFrom(netip.AddrFrom4([4]byte{10, 5, 0, 2}), 32), //nolint:gomnd
+			}
+		}
+	case *vpnSettings.Provider.Name == providers.Mullvad && *wireguard.AccountNumber != "":
+		device, privateKey, err := mullvad.SetupDevice(ctx, client, fileWriter,
+			*wireguard.AccountNumber, mullvad.DeviceStatePath(dataDirectory))
+		if err != nil {
+			return fmt.Errorf("setting up Mullvad device: %w", err)
+		}
+		logger.Info("using Mullvad device " + device.Name)
+		wireguard.PrivateKey = &privateKey
+
+		if len(wireguard.Addresses) == 0 {
+			ipv6Supported, err := ipv6Checker.IsIPv6Supported()
+			if err != nil {
+				return fmt.Errorf("checking for IPv6 support: %w", err)
+			}
+			for _, address := range device.Addresses() {
+				if address.Addr().Is4() || ipv6Supported {
+					wireguard.Addresses = append(wireguard.Addresses, address)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fetchOpenVPNCredentials sets the OpenVPN user and password using
+// the ProtonVPN API, if the provider is ProtonVPN, the Proton account
+// credentials are set and the OpenVPN user is not set.
+func fetchOpenVPNCredentials(ctx context.Context, client *http.Client,
+	vpnSettings *settings.VPN, updaterSettings settings.Updater) (err error) {
+	openvpn := &vpnSettings.OpenVPN
+	if vpnSettings.Type != vpntype.OpenVPN ||
+		*vpnSettings.Provider.Name != providers.Protonvpn ||
+		*updaterSettings.ProtonEmail == "" || *openvpn.User != "" {
+		return nil
+	}
+
+	user, password, err := protonvpn.FetchOpenVPNCredentials(ctx, client,
+		*updaterSettings.ProtonEmail, *updaterSettings.ProtonPassword)
+	if err != nil {
+		return err
+	}
+	openvpn.User = &user
+	openvpn.Password = &password
+	return nil
+}
+
+// checkCredentials checks the VPN credentials against the
+// VPN provider API, if the credentials check is enabled.
+func checkCredentials(ctx context.Context, client *http.Client,
+	vpnSettings settings.VPN, fileWriter *system.FileWriter,
+	dataDirectory string, logger log.LoggerInterface) (err error) {
+	if !*vpnSettings.Provider.CredentialsCheck {
+		return nil
+	}
+
+	openvpnUsername, openvpnPassword := "", ""
+	if vpnSettings.Type == vpntype.OpenVPN {
+		openvpnUsername = *vpnSettings.OpenVPN.User
+		openvpnPassword = *vpnSettings.OpenVPN.Password
+	}
+
+	switch *vpnSettings.Provider.Name {
+	case providers.Mullvad:
+		if openvpnUsername == "" {
+			return nil // Wireguard does not use the account number
+		}
+		return mullvad.CheckAccount(ctx, client, openvpnUsername)
+	case providers.Nordvpn:
+		return nordvpn.CheckCredentials(ctx, client,
+			*vpnSettings.Wireguard.AccessToken, openvpnUsername, openvpnPassword)
+	case providers.PrivateInternetAccess:
+		if openvpnUsername == "" {
+			return nil
+		}
+		return privateinternetaccess.CheckCredentials(ctx, client, fileWriter,
+			logger, privateinternetaccess.TokenCachePath(dataDirectory), openvpnUsername, openvpnPassword)
+	default:
+		return nil
+	}
+}
+
+// logReadOnlyTip logs a tip to mount a writable filesystem at
+// the path given if the error is due to a read-only filesystem,
+// which happens when running with a read-only root filesystem.
+func logReadOnlyTip(logger log.LoggerInterface, err error,
+	path, mountType string) {
+	if !errors.Is(err, syscall.EROFS) {
+		return
+	}
+	logger.Warn("💡 Tip: the root filesystem is read-only, " +
+		"mount a " + mountType + " at " + path)
+}
+
+// setupNonRoot checks the program has the capabilities required to
+// run as a non-root user, and raises them in the ambient set so child
+// processes such as openvpn and iptables inherit them. It returns the
+// current user and group IDs to use instead of the PUID and PGID
+// configured, since files can only be given to the current user.
+func setupNonRoot(logger log.LoggerInterface, puid, pgid int) (
+	newPUID, newPGID int, err error) {
+	uid, gid := os.Getuid(), os.Getgid()
+	logger.Info(fmt.Sprintf("running as non-root user id %d and group id %d", uid, gid))
+
+	effective, err := capabilities.Effective()
+	if err != nil {
+		return 0, 0, fmt.Errorf("getting effective capabilities: %w", err)
+	}
+
+	required := []capabilities.Capability{capabilities.NetAdmin, capabilities.NetRaw}
+	missing := effective.Missing(required...)
+	if len(missing) > 0 {
+		missingStrings := make([]string, len(missing))
+		for i, capability := range missing {
+			missingStrings[i] = capability.String()
+		}
+		logger.Warn("💡 Tip: non-root users need the NET_ADMIN and NET_RAW capabilities " +
+			"as ambient capabilities or as file capabilities set on the gluetun binary")
+		return 0, 0, fmt.Errorf("%w: %s", errCapabilitiesMissing, strings.Join(missingStrings, ", "))
+	}
+
+	if effective.Has(capabilities.NetBindService) {
+		required = append(required, capabilities.NetBindService)
+	} else {
+		logger.Warn("NET_BIND_SERVICE capability is missing so servers cannot " +
+			"listen on ports below 1024, such as the DNS server on port 53")
+	}
+
+	err = capabilities.RaiseAmbient(required...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if puid != uid || pgid != gid {
+		logger.Info(fmt.Sprintf("using user id %d and group id %d instead of "+
+			"the configured user id %d and group id %d", uid, gid, puid, pgid))
+	}
+
+	return uid, gid, nil
+}
+
 func printVersions(ctx context.Context, logger infoer,
 	elements []printVersionElement) (err error) {
 	const timeout = 5 * time.Second
@@ -559,16 +1142,33 @@ type Linker interface {
 }
 
 type clier interface {
+	Benchmark(ctx context.Context, args []string) error
+	CheckCustom(ctx context.Context, args []string, source cli.Source,
+		ipv6Checker cli.IPv6Checker) error
 	ClientKey(args []string) error
+	Completion(args []string) error
+	Diagnose(ctx context.Context, source cli.Source, netLinker diagnostics.NetLinker,
+		cmder command.Runner, tun diagnostics.TunChecker) error
 	FormatServers(args []string) error
+	GenConfig(args []string) error
+	ListServers(args []string) error
+	MullvadDevices(ctx context.Context, args []string) error
+	ImportServers(ctx context.Context, args []string, logger cli.ImportLogger) error
+	ConnectivityTest(ctx context.Context, args []string, logger cli.TestLogger,
+		source cli.Source, netLinker cli.TestNetLinker, cmder command.RunStarter,
+		tun cli.TunChecker) error
+	SanitizeOpenVPN(args []string) error
+	SpeedTest(ctx context.Context, args []string) error
+	DNSLeakTest(ctx context.Context, args []string) error
 	OpenvpnConfig(logger cli.OpenvpnConfigLogger, source cli.Source, ipv6Checker cli.IPv6Checker) error
 	HealthCheck(ctx context.Context, source cli.Source, warner cli.Warner) error
 	Update(ctx context.Context, args []string, logger cli.UpdaterLogger) error
+	WireguardKey(ctx context.Context, args []string) error
 }
 
 type Tun interface {
 	Check(tunDevice string) error
-	Create(tunDevice string) error
+	Setup(tunDevice string, logger tun.Logger) error
 }
 
 type Source interface {
@@ -576,3 +1176,14 @@ type Source interface {
 	ReadHealth() (health settings.Health, err error)
 	String() string
 }
+
+// restartProcess replaces the current process with a new
+// instance of the program, using the same arguments and
+// environment. It only returns if an error occurs.
+func restartProcess() (err error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("getting executable path: %w", err)
+	}
+	return syscall.Exec(executable, os.Args, os.Environ()) //nolint:gosec
+}
@@ -0,0 +1,81 @@
+package alpine
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// findEntry returns the fields of the first line of the colon
+// separated file at the path given, which field at the index given
+// equals the value given. It returns nil fields and no error if
+// no entry is found or if the file does not exist.
+func findEntry(path string, index int, value string) (fields []string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lineFields := strings.Split(line, ":")
+		if index < len(lineFields) && lineFields[index] == value {
+			fields = lineFields
+			break
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return fields, file.Close()
+}
+
+// appendLine appends the line given to the file at the path given,
+// creating the file if it does not exist. A new line character is
+// first added if the file does not end with one.
+func appendLine(path, line string) (err error) {
+	const perm = 0644
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, perm)
+	if err != nil {
+		return err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if stat.Size() > 0 {
+		lastByte := make([]byte, 1)
+		_, err = file.ReadAt(lastByte, stat.Size()-1)
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		if lastByte[0] != '\n' {
+			line = "\n" + line
+		}
+	}
+
+	_, err = file.WriteString(line + "\n")
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
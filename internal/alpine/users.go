@@ -3,8 +3,6 @@ package alpine
 import (
 	"errors"
 	"fmt"
-	"os"
-	"os/user"
 	"strconv"
 )
 
@@ -12,43 +10,64 @@ var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 )
 
-// CreateUser creates a user in Alpine with the given UID.
-func (a *Alpine) CreateUser(username string, uid int) (createdUsername string, err error) {
-	UIDStr := strconv.Itoa(uid)
-	u, err := a.lookupID(UIDStr)
-	_, unknownUID := err.(user.UnknownUserIdError)
-	if err != nil && !unknownUID {
-		return "", err
-	}
-
-	if u != nil {
-		if u.Username == username {
+// CreateUser creates a user with the given UID and with the group
+// of the given GID as primary group, by editing the passwd and group
+// files directly. If a user already exists with the UID, nothing is
+// modified and its username is returned, or the empty string if it is
+// the username given. If no group exists with the GID, a group named
+// after the username is created.
+func (a *Alpine) CreateUser(username string, uid, gid int) (createdUsername string, err error) {
+	const uidField, usernameField = 2, 0
+	entry, err := findEntry(a.passwdPath, uidField, strconv.Itoa(uid))
+	if err != nil {
+		return "", fmt.Errorf("finding user with id %d: %w", uid, err)
+	} else if entry != nil {
+		if entry[usernameField] == username {
 			return "", nil
 		}
-		return u.Username, nil
+		return entry[usernameField], nil
 	}
 
-	u, err = a.lookup(username)
-	_, unknownUsername := err.(user.UnknownUserError)
-	if err != nil && !unknownUsername {
-		return "", err
+	entry, err = findEntry(a.passwdPath, usernameField, username)
+	if err != nil {
+		return "", fmt.Errorf("finding user %s: %w", username, err)
+	} else if entry != nil {
+		return "", fmt.Errorf("%w: with name %s for ID %s instead of %d",
+			ErrUserAlreadyExists, username, entry[uidField], uid)
 	}
 
-	if u != nil {
-		return "", fmt.Errorf("%w: with name %s for ID %s instead of %d",
-			ErrUserAlreadyExists, username, u.Uid, uid)
+	err = a.createGroupIfNotExists(username, gid)
+	if err != nil {
+		return "", fmt.Errorf("creating group: %w", err)
 	}
 
-	file, err := os.OpenFile(a.passwdPath, os.O_APPEND|os.O_WRONLY, 0644)
+	line := fmt.Sprintf("%s:x:%d:%d::/dev/null:/sbin/nologin", username, uid, gid)
+	err = appendLine(a.passwdPath, line)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("adding user: %w", err)
 	}
-	s := fmt.Sprintf("%s:x:%d:::/dev/null:/sbin/nologin\n", username, uid)
-	_, err = file.WriteString(s)
+
+	return username, nil
+}
+
+func (a *Alpine) createGroupIfNotExists(name string, gid int) (err error) {
+	const gidField, nameField = 2, 0
+	entry, err := findEntry(a.groupPath, gidField, strconv.Itoa(gid))
+	if err != nil {
+		return fmt.Errorf("finding group with id %d: %w", gid, err)
+	} else if entry != nil {
+		return nil
+	}
+
+	entry, err = findEntry(a.groupPath, nameField, name)
 	if err != nil {
-		_ = file.Close()
-		return "", err
+		return fmt.Errorf("finding group %s: %w", name, err)
+	} else if entry != nil {
+		// a group with the same name but a different
+		// id already exists, so only the id is used.
+		return nil
 	}
 
-	return username, file.Close()
+	line := fmt.Sprintf("%s:x:%d:", name, gid)
+	return appendLine(a.groupPath, line)
 }
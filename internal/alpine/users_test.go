@@ -0,0 +1,116 @@
+package alpine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Alpine_CreateUser(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		passwd          string
+		group           string
+		username        string
+		uid, gid        int
+		createdUsername string
+		errWrapped      error
+		errMessage      string
+		expectedPasswd  string
+		expectedGroup   string
+		groupNotCreated bool
+	}{
+		"files do not exist": {
+			username:        "nonrootuser",
+			uid:             1000,
+			gid:             1000,
+			createdUsername: "nonrootuser",
+			expectedPasswd:  "nonrootuser:x:1000:1000::/dev/null:/sbin/nologin\n",
+			expectedGroup:   "nonrootuser:x:1000:\n",
+		},
+		"user exists with uid": {
+			passwd:          "root:x:0:0:root:/root:/bin/sh\nbob:x:1000:1000::/home/bob:/bin/sh\n",
+			username:        "nonrootuser",
+			uid:             1000,
+			gid:             1000,
+			createdUsername: "bob",
+			expectedPasswd:  "root:x:0:0:root:/root:/bin/sh\nbob:x:1000:1000::/home/bob:/bin/sh\n",
+			groupNotCreated: true,
+		},
+		"user exists with uid and username": {
+			passwd:          "nonrootuser:x:1000:1000::/dev/null:/sbin/nologin\n",
+			username:        "nonrootuser",
+			uid:             1000,
+			gid:             1000,
+			expectedPasswd:  "nonrootuser:x:1000:1000::/dev/null:/sbin/nologin\n",
+			groupNotCreated: true,
+		},
+		"username exists with other uid": {
+			passwd:          "nonrootuser:x:1001:1001::/dev/null:/sbin/nologin\n",
+			username:        "nonrootuser",
+			uid:             1000,
+			gid:             1000,
+			errWrapped:      ErrUserAlreadyExists,
+			errMessage:      "user already exists: with name nonrootuser for ID 1001 instead of 1000",
+			expectedPasswd:  "nonrootuser:x:1001:1001::/dev/null:/sbin/nologin\n",
+			groupNotCreated: true,
+		},
+		"group exists with gid": {
+			passwd:          "root:x:0:0:root:/root:/bin/sh",
+			group:           "root:x:0:root\nusers:x:100:\n",
+			username:        "nonrootuser",
+			uid:             1000,
+			gid:             100,
+			createdUsername: "nonrootuser",
+			expectedPasswd: "root:x:0:0:root:/root:/bin/sh\n" +
+				"nonrootuser:x:1000:100::/dev/null:/sbin/nologin\n",
+			expectedGroup: "root:x:0:root\nusers:x:100:\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			alpine := &Alpine{
+				passwdPath: filepath.Join(dir, "passwd"),
+				groupPath:  filepath.Join(dir, "group"),
+			}
+			if testCase.passwd != "" {
+				err := os.WriteFile(alpine.passwdPath, []byte(testCase.passwd), 0600)
+				require.NoError(t, err)
+			}
+			if testCase.group != "" {
+				err := os.WriteFile(alpine.groupPath, []byte(testCase.group), 0600)
+				require.NoError(t, err)
+			}
+
+			createdUsername, err := alpine.CreateUser(testCase.username,
+				testCase.uid, testCase.gid)
+
+			assert.Equal(t, testCase.createdUsername, createdUsername)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+
+			passwd, err := os.ReadFile(alpine.passwdPath)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedPasswd, string(passwd))
+
+			group, err := os.ReadFile(alpine.groupPath)
+			if testCase.groupNotCreated && testCase.group == "" {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedGroup, string(group))
+		})
+	}
+}
@@ -2,14 +2,21 @@ package alpine
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"strings"
 )
 
+// Version returns the Alpine version, or "none" if the
+// program does not run on Alpine, for example in an image
+// built from scratch.
 func (a *Alpine) Version(context.Context) (version string, err error) {
 	file, err := os.OpenFile(a.alpineReleasePath, os.O_RDONLY, 0)
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "none", nil
+		}
 		return "", err
 	}
 
@@ -0,0 +1,143 @@
+// Package bandwidth accounts for the bytes received and sent
+// on the VPN tunnel interface and the default route interfaces.
+package bandwidth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// Accountant periodically reads network interfaces byte counters
+// and maintains session and cumulative totals for each of them.
+type Accountant struct {
+	// Fixed parameters
+	filepath string
+	// Objects
+	fileWriter FileWriter
+	logger     Logger
+	// Internal state
+	interfaces map[string]*counters
+	mutex      sync.RWMutex
+	// Mock functions
+	readByteCount func(interfaceName, direction string) (count uint64, err error)
+}
+
+type counters struct {
+	tunnel    bool
+	seen      bool
+	lastRx    uint64
+	lastTx    uint64
+	sessionRx uint64
+	sessionTx uint64
+	totalRx   uint64
+	totalTx   uint64
+}
+
+// New creates a bandwidth accountant for the default interfaces
+// given, persisting cumulative totals to the filepath given.
+func New(defaultInterfaces []string, filepath string,
+	fileWriter FileWriter, logger Logger) *Accountant {
+	interfaces := make(map[string]*counters, len(defaultInterfaces))
+	for _, name := range defaultInterfaces {
+		interfaces[name] = &counters{}
+	}
+
+	return &Accountant{
+		filepath:      filepath,
+		fileWriter:    fileWriter,
+		logger:        logger,
+		interfaces:    interfaces,
+		readByteCount: readByteCount,
+	}
+}
+
+// SetTunnelInterface sets the VPN tunnel interface and resets
+// its session counters. It should be called each time the tunnel
+// is up, since the tunnel interface counters are reset when the
+// interface is re-created.
+func (a *Accountant) SetTunnelInterface(name string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, c := range a.interfaces {
+		c.tunnel = false
+	}
+
+	c, ok := a.interfaces[name]
+	if !ok {
+		c = &counters{}
+		a.interfaces[name] = c
+	}
+	c.tunnel = true
+	// count all bytes from the interface creation
+	c.seen = true
+	c.lastRx, c.lastTx = 0, 0
+	c.sessionRx, c.sessionTx = 0, 0
+}
+
+// GetBandwidth returns the bandwidth counted for each interface,
+// sorted by interface name.
+func (a *Accountant) GetBandwidth() (bandwidth []models.InterfaceBandwidth) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	bandwidth = make([]models.InterfaceBandwidth, 0, len(a.interfaces))
+	for name, c := range a.interfaces {
+		bandwidth = append(bandwidth, models.InterfaceBandwidth{
+			Interface:      name,
+			Tunnel:         c.tunnel,
+			SessionRxBytes: c.sessionRx,
+			SessionTxBytes: c.sessionTx,
+			TotalRxBytes:   c.totalRx,
+			TotalTxBytes:   c.totalTx,
+		})
+	}
+
+	sort.Slice(bandwidth, func(i, j int) bool {
+		return bandwidth[i].Interface < bandwidth[j].Interface
+	})
+
+	return bandwidth
+}
+
+func (a *Accountant) update() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for name, c := range a.interfaces {
+		rx, err := a.readByteCount(name, "rx")
+		if err != nil {
+			continue // interface does not exist (yet)
+		}
+		tx, err := a.readByteCount(name, "tx")
+		if err != nil {
+			continue
+		}
+		c.update(rx, tx)
+	}
+}
+
+func (c *counters) update(rx, tx uint64) {
+	if !c.seen {
+		// ignore bytes counted before the program started
+		c.seen = true
+		c.lastRx, c.lastTx = rx, tx
+		return
+	}
+
+	rxDelta, txDelta := delta(c.lastRx, rx), delta(c.lastTx, tx)
+	c.lastRx, c.lastTx = rx, tx
+	c.sessionRx += rxDelta
+	c.sessionTx += txDelta
+	c.totalRx += rxDelta
+	c.totalTx += txDelta
+}
+
+func delta(previous, current uint64) uint64 {
+	if current < previous { // counter got reset
+		return current
+	}
+	return current - previous
+}
@@ -0,0 +1,54 @@
+package bandwidth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Accountant(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bandwidth.json")
+	accountant := New([]string{"eth0"}, path, system.NewFileWriter(os.Getuid(), os.Getgid()), nil)
+
+	byteCounts := map[string]uint64{"eth0": 1000, "tun0": 10}
+	accountant.readByteCount = func(interfaceName, _ string) (uint64, error) {
+		return byteCounts[interfaceName], nil
+	}
+
+	accountant.update() // first eth0 reading is ignored
+	accountant.SetTunnelInterface("tun0")
+	byteCounts["eth0"] = 1500
+	byteCounts["tun0"] = 50
+	accountant.update()
+
+	// tunnel re-created with its counters reset
+	accountant.SetTunnelInterface("tun0")
+	byteCounts["tun0"] = 20
+	accountant.update()
+
+	expected := []models.InterfaceBandwidth{
+		{Interface: "eth0", SessionRxBytes: 500, SessionTxBytes: 500, TotalRxBytes: 500, TotalTxBytes: 500},
+		{Interface: "tun0", Tunnel: true, SessionRxBytes: 20, SessionTxBytes: 20, TotalRxBytes: 70, TotalTxBytes: 70},
+	}
+	assert.Equal(t, expected, accountant.GetBandwidth())
+
+	err := accountant.save()
+	require.NoError(t, err)
+
+	restarted := New([]string{"eth0"}, path, system.NewFileWriter(os.Getuid(), os.Getgid()), nil)
+	err = restarted.load()
+	require.NoError(t, err)
+
+	expected = []models.InterfaceBandwidth{
+		{Interface: "eth0", TotalRxBytes: 500, TotalTxBytes: 500},
+		{Interface: "tun0", TotalRxBytes: 70, TotalTxBytes: 70},
+	}
+	assert.Equal(t, expected, restarted.GetBandwidth())
+}
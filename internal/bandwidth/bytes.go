@@ -0,0 +1,26 @@
+package bandwidth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readByteCount reads the bytes count for the network interface
+// given, where direction can be "rx" or "tx".
+func readByteCount(interfaceName, direction string) (count uint64, err error) {
+	path := filepath.Join("/sys/class/net", interfaceName, "statistics", direction+"_bytes")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	const base, bits = 10, 64
+	count, err = strconv.ParseUint(strings.TrimSpace(string(data)), base, bits)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return count, nil
+}
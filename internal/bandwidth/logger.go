@@ -0,0 +1,5 @@
+package bandwidth
+
+type Logger interface {
+	Warn(s string)
+}
@@ -0,0 +1,47 @@
+package bandwidth
+
+import (
+	"context"
+	"time"
+)
+
+// Run loads the cumulative totals previously saved and then
+// periodically updates the counters, saving the cumulative totals
+// every few minutes and once the context is canceled.
+func (a *Accountant) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	err := a.load()
+	if err != nil {
+		a.logger.Warn("loading bandwidth totals: " + err.Error())
+	}
+
+	const updatePeriod = 10 * time.Second
+	ticker := time.NewTicker(updatePeriod)
+	defer ticker.Stop()
+
+	const updatesPerSave = 30
+	updates := 0
+	for {
+		select {
+		case <-ctx.Done():
+			a.update()
+			a.saveAndLog()
+			return
+		case <-ticker.C:
+			a.update()
+			updates++
+			if updates == updatesPerSave {
+				updates = 0
+				a.saveAndLog()
+			}
+		}
+	}
+}
+
+func (a *Accountant) saveAndLog() {
+	err := a.save()
+	if err != nil {
+		a.logger.Warn("saving bandwidth totals: " + err.Error())
+	}
+}
@@ -0,0 +1,64 @@
+package bandwidth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type persistedTotals struct {
+	RxBytes uint64 `json:"rx_bytes"`
+	TxBytes uint64 `json:"tx_bytes"`
+}
+
+// load loads cumulative totals from the accountant file, if it exists.
+func (a *Accountant) load() (err error) {
+	data, err := os.ReadFile(a.filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var interfaceToTotals map[string]persistedTotals
+	err = json.Unmarshal(data, &interfaceToTotals)
+	if err != nil {
+		return fmt.Errorf("decoding JSON data: %w", err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for name, totals := range interfaceToTotals {
+		c, ok := a.interfaces[name]
+		if !ok {
+			c = &counters{}
+			a.interfaces[name] = c
+		}
+		c.totalRx += totals.RxBytes
+		c.totalTx += totals.TxBytes
+	}
+
+	return nil
+}
+
+// save writes cumulative totals to the accountant file.
+func (a *Accountant) save() (err error) {
+	a.mutex.RLock()
+	interfaceToTotals := make(map[string]persistedTotals, len(a.interfaces))
+	for name, c := range a.interfaces {
+		interfaceToTotals[name] = persistedTotals{
+			RxBytes: c.totalRx,
+			TxBytes: c.totalTx,
+		}
+	}
+	a.mutex.RUnlock()
+
+	data, err := json.Marshal(interfaceToTotals)
+	if err != nil {
+		return fmt.Errorf("encoding JSON data: %w", err)
+	}
+
+	const perms = 0600
+	return a.fileWriter.WriteFile(a.filepath, data, perms)
+}
@@ -0,0 +1,46 @@
+package capabilities
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// RaiseAmbient adds the capabilities given to the inheritable and
+// ambient sets of all the threads of the process, such that child
+// processes such as openvpn and iptables inherit them when the
+// program runs as a non-root user. The capabilities must be in
+// the permitted set of the process.
+func RaiseAmbient(capabilities ...Capability) (err error) {
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	err = unix.Capget(&header, &data[0])
+	if err != nil {
+		return fmt.Errorf("getting capabilities: %w", err)
+	}
+
+	const bitsPerWord = 32
+	for _, capability := range capabilities {
+		data[capability/bitsPerWord].Inheritable |= 1 << (capability % bitsPerWord)
+	}
+
+	// Thread capabilities are per thread, so the system calls must
+	// be run on all the threads of the Go runtime.
+	_, _, errno := syscall.AllThreadsSyscall(unix.SYS_CAPSET,
+		uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0)
+	if errno != 0 {
+		return fmt.Errorf("setting inheritable capabilities: %w", errno)
+	}
+
+	for _, capability := range capabilities {
+		_, _, errno = syscall.AllThreadsSyscall6(unix.SYS_PRCTL, unix.PR_CAP_AMBIENT,
+			unix.PR_CAP_AMBIENT_RAISE, uintptr(capability), 0, 0, 0)
+		if errno != 0 {
+			return fmt.Errorf("raising ambient capability %s: %w", capability, errno)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Package capabilities detects and raises Linux capabilities,
+// to run the program as a non-root user.
+package capabilities
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Capability is a Linux capability number, as defined
+// in linux/capability.h.
+type Capability uint
+
+const (
+	Chown          Capability = 0
+	DacOverride    Capability = 1
+	Setgid         Capability = 6
+	Setuid         Capability = 7
+	NetBindService Capability = 10
+	NetAdmin       Capability = 12
+	NetRaw         Capability = 13
+	Mknod          Capability = 27
+)
+
+func (c Capability) String() string {
+	switch c {
+	case Chown:
+		return "CAP_CHOWN"
+	case DacOverride:
+		return "CAP_DAC_OVERRIDE"
+	case Setgid:
+		return "CAP_SETGID"
+	case Setuid:
+		return "CAP_SETUID"
+	case NetBindService:
+		return "CAP_NET_BIND_SERVICE"
+	case NetAdmin:
+		return "CAP_NET_ADMIN"
+	case NetRaw:
+		return "CAP_NET_RAW"
+	case Mknod:
+		return "CAP_MKNOD"
+	default:
+		return "CAP_" + strconv.Itoa(int(c))
+	}
+}
+
+// Set is a set of capabilities stored as a bit mask.
+type Set uint64
+
+// Has returns true if the set contains the capability given.
+func (s Set) Has(c Capability) bool {
+	return s&(1<<c) != 0
+}
+
+// Missing returns the capabilities given not contained in the set.
+func (s Set) Missing(capabilities ...Capability) (missing []Capability) {
+	for _, capability := range capabilities {
+		if !s.Has(capability) {
+			missing = append(missing, capability)
+		}
+	}
+	return missing
+}
+
+// Effective returns the effective capabilities of the process.
+func Effective() (effective Set, err error) {
+	const statusPath = "/proc/self/status"
+	file, err := os.Open(statusPath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	effective, err = parseStatus(file, "CapEff")
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", statusPath, err)
+	}
+
+	return effective, file.Close()
+}
+
+var ErrFieldNotFound = errors.New("field not found")
+
+func parseStatus(reader io.Reader, field string) (set Set, err error) {
+	scanner := bufio.NewScanner(reader)
+	prefix := field + ":"
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		mask, err := strconv.ParseUint(value, 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parsing %s value: %w", field, err)
+		}
+		return Set(mask), nil
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+}
+
+// ListeningUID returns the user ID to use to validate listening
+// addresses. It is 0 if the process can bind to privileged ports,
+// either because it runs as root or has the CAP_NET_BIND_SERVICE
+// capability, and the process user ID otherwise.
+func ListeningUID() (uid int) {
+	uid = os.Getuid()
+	if uid == 0 {
+		return 0
+	}
+	effective, err := Effective()
+	if err == nil && effective.Has(NetBindService) {
+		return 0
+	}
+	return uid
+}
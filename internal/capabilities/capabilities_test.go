@@ -0,0 +1,57 @@
+package capabilities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		set        Set
+		errMessage string
+	}{
+		"field_not_found": {
+			content:    "Name:\tgluetun\n",
+			errMessage: "field not found: CapEff",
+		},
+		"malformed_value": {
+			content:    "CapEff:\txyz\n",
+			errMessage: `parsing CapEff value: strconv.ParseUint: parsing "xyz": invalid syntax`,
+		},
+		"net_admin_and_net_raw": {
+			content: "Name:\tgluetun\nCapPrm:\t0000000000003000\nCapEff:\t0000000000003000\n",
+			set:     1<<NetAdmin | 1<<NetRaw,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			set, err := parseStatus(strings.NewReader(testCase.content), "CapEff")
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.set, set)
+		})
+	}
+}
+
+func Test_Set_Missing(t *testing.T) {
+	t.Parallel()
+
+	set := Set(1 << NetAdmin)
+
+	missing := set.Missing(NetAdmin, NetRaw)
+
+	assert.Equal(t, []Capability{NetRaw}, missing)
+}
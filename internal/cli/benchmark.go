@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrNoServerFound     = errors.New("no server found")
+	ErrNoServerReachable = errors.New("no server is reachable")
+	ErrPortNotValid      = errors.New("port is not valid")
+	ErrServerHasNoIP     = errors.New("server has no IP address")
+)
+
+// Benchmark measures the TCP connection latency to each server
+// of a provider matching the filters given as flags, and prints
+// a table of the servers ranked by latency.
+func (c *CLI) Benchmark(ctx context.Context, args []string) error {
+	var provider, vpnType, countriesCSV, regionsCSV, citiesCSV, envFile string
+	var portForwardOnly bool
+	var port uint
+	var parallel, top int
+	var timeout time.Duration
+	flagSet := flag.NewFlagSet("benchmark", flag.ExitOnError)
+	flagSet.StringVar(&provider, "provider", "", "VPN provider to benchmark servers for")
+	flagSet.StringVar(&vpnType, "vpn-type", "", "Only benchmark servers supporting this VPN type, "+
+		"which can be 'openvpn' or 'wireguard'")
+	flagSet.StringVar(&countriesCSV, "country", "", "Comma separated countries to filter servers with")
+	flagSet.StringVar(&regionsCSV, "region", "", "Comma separated regions to filter servers with")
+	flagSet.StringVar(&citiesCSV, "city", "", "Comma separated cities to filter servers with")
+	flagSet.BoolVar(&portForwardOnly, "port-forwarding", false, "Only benchmark servers supporting port forwarding")
+	flagSet.UintVar(&port, "port", 443, "TCP port to connect to on each server")         //nolint:gomnd
+	flagSet.IntVar(&parallel, "parallel", 16, "Number of servers to probe concurrently") //nolint:gomnd
+	flagSet.IntVar(&top, "top", 10, "Number of best servers to print, 0 to print all")   //nolint:gomnd
+	flagSet.DurationVar(&timeout, "timeout", 2*time.Second, "Timeout for each server probe")
+	flagSet.StringVar(&envFile, "env-file", "", "Optional file path to write an env file snippet "+
+		"selecting the best server")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	provider = strings.ToLower(provider)
+	switch {
+	case provider == "":
+		return fmt.Errorf("%w", ErrProviderUnspecified)
+	case provider == providers.Custom || !isValidProvider(provider):
+		return fmt.Errorf("%w: %s", ErrProviderNotValid, provider)
+	case port == 0 || port > 65535:
+		return fmt.Errorf("%w: %d", ErrPortNotValid, port)
+	case parallel < 1:
+		parallel = 1
+	}
+
+	logger := newNoopLogger()
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+
+	filter := serversFilter{
+		vpnType:         vpnType,
+		countries:       splitCSV(countriesCSV),
+		regions:         splitCSV(regionsCSV),
+		cities:          splitCSV(citiesCSV),
+		portForwardOnly: portForwardOnly,
+	}
+	servers := filter.apply(storage.GetServers(provider))
+	if len(servers) == 0 {
+		return fmt.Errorf("%w", ErrNoServerFound)
+	}
+
+	results := benchmarkServers(ctx, servers, uint16(port), parallel, timeout)
+	reachable := rankResults(results)
+	if len(reachable) == 0 {
+		return fmt.Errorf("%w: out of %d servers", ErrNoServerReachable, len(servers))
+	}
+
+	printed := reachable
+	if top > 0 && len(printed) > top {
+		printed = printed[:top]
+	}
+	err = printBenchmarkTable(printed)
+	if err != nil {
+		return fmt.Errorf("printing results: %w", err)
+	}
+	fmt.Printf("%d of %d servers reachable\n", len(reachable), len(results))
+
+	if envFile != "" {
+		variables := makeBenchmarkVariables(provider, vpnType, reachable[0].server)
+		const perms = 0600
+		err = writeToFile(envFile, formatEnvFile(variables), perms)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type benchmarkResult struct {
+	server  models.Server
+	latency time.Duration
+	err     error
+}
+
+func benchmarkServers(ctx context.Context, servers []models.Server,
+	port uint16, parallel int, timeout time.Duration) (results []benchmarkResult) {
+	results = make([]benchmarkResult, len(servers))
+	semaphore := make(chan struct{}, parallel)
+	wg := new(sync.WaitGroup)
+	for i, server := range servers {
+		results[i].server = server
+		wg.Add(1)
+		go func(result *benchmarkResult) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			result.latency, result.err = probeServer(ctx, result.server, port, timeout)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func probeServer(ctx context.Context, server models.Server, port uint16,
+	timeout time.Duration) (latency time.Duration, err error) {
+	if len(server.IPs) == 0 {
+		return 0, fmt.Errorf("%w", ErrServerHasNoIP)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := netip.AddrPortFrom(server.IPs[0], port).String()
+	dialer := net.Dialer{}
+	start := time.Now()
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	latency = time.Since(start)
+	_ = connection.Close()
+	return latency, nil
+}
+
+// rankResults returns the results without error sorted
+// by increasing latency.
+func rankResults(results []benchmarkResult) (reachable []benchmarkResult) {
+	reachable = make([]benchmarkResult, 0, len(results))
+	for _, result := range results {
+		if result.err == nil {
+			reachable = append(reachable, result)
+		}
+	}
+	sort.SliceStable(reachable, func(i, j int) bool {
+		return reachable[i].latency < reachable[j].latency
+	})
+	return reachable
+}
+
+func printBenchmarkTable(results []benchmarkResult) (err error) {
+	const minWidth, tabWidth, padding = 0, 0, 2
+	writer := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, ' ', 0)
+	_, err = fmt.Fprintln(writer, "RANK\tSERVER\tCOUNTRY\tREGION\tCITY\tIP\tLATENCY")
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		server := result.server
+		_, err = fmt.Fprintln(writer, strings.Join([]string{
+			strconv.Itoa(i + 1),
+			serverIdentifier(server),
+			dashIfEmpty(server.Country),
+			dashIfEmpty(server.Region),
+			dashIfEmpty(server.City),
+			server.IPs[0].String(),
+			result.latency.Round(time.Millisecond / 10).String(), //nolint:gomnd
+		}, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func serverIdentifier(server models.Server) string {
+	if server.Hostname != "" {
+		return server.Hostname
+	}
+	return dashIfEmpty(server.ServerName)
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func makeBenchmarkVariables(provider, vpnType string,
+	best models.Server) (variables []variable) {
+	variables = []variable{
+		{key: "VPN_SERVICE_PROVIDER", value: provider},
+	}
+	if vpnType != "" {
+		variables = append(variables, variable{key: "VPN_TYPE", value: vpnType})
+	}
+	if best.Hostname != "" {
+		variables = append(variables, variable{key: "SERVER_HOSTNAMES", value: best.Hostname})
+	} else {
+		variables = append(variables, variable{key: "SERVER_NAMES", value: best.ServerName})
+	}
+	return variables
+}
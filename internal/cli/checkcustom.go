@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/openvpn/extract"
+	"github.com/qdm12/gluetun/internal/openvpn/sanitize"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var (
+	ErrCustomCheckFailed  = errors.New("custom provider configuration check failed")
+	ErrProviderNotCustom  = errors.New("VPN service provider is not custom")
+	ErrEndpointPortClosed = errors.New("endpoint port is closed")
+)
+
+// CheckCustom checks the custom provider settings, reporting actionable
+// messages for each problem found, and probes the server endpoint.
+func (c *CLI) CheckCustom(ctx context.Context, args []string, source Source,
+	ipv6Checker IPv6Checker) error {
+	flagSet := flag.NewFlagSet("check-custom", flag.ExitOnError)
+	const defaultTimeout = 5 * time.Second
+	timeout := flagSet.Duration("timeout", defaultTimeout, "Timeout to probe the server endpoint")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	allSettings, err := source.Read()
+	if err != nil {
+		return err
+	}
+
+	vpnSettings := allSettings.VPN
+	if *vpnSettings.Provider.Name != providers.Custom {
+		return fmt.Errorf("%w: %s", ErrProviderNotCustom, *vpnSettings.Provider.Name)
+	}
+
+	ipv6Supported, err := ipv6Checker.IsIPv6Supported()
+	if err != nil {
+		return fmt.Errorf("checking for IPv6 support: %w", err)
+	}
+
+	report := testReport{failedErr: ErrCustomCheckFailed}
+
+	logger := newNoopLogger()
+	storage, err := newStorage(logger, *allSettings.System.DataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+	err = vpnSettings.Validate(storage, ipv6Supported)
+	if err != nil {
+		report.fail("settings validation", err)
+	} else {
+		report.pass("settings validation", "settings are valid")
+	}
+
+	var endpoint netip.AddrPort
+	var protocol string
+	switch vpnSettings.Type {
+	case vpn.Wireguard:
+		endpoint, protocol = checkCustomWireguard(&report, vpnSettings,
+			allSettings.Firewall, ipv6Supported)
+	default:
+		endpoint, protocol = checkCustomOpenVPN(&report, vpnSettings.OpenVPN,
+			vpnSettings.Provider.ServerSelection.OpenVPN)
+	}
+
+	if !endpoint.IsValid() {
+		report.skip("endpoint reachability")
+		return report.print()
+	}
+
+	err = probeEndpoint(ctx, endpoint, protocol, *timeout)
+	if err != nil {
+		report.fail("endpoint reachability", fmt.Errorf("%s %s: %w", endpoint, protocol, err))
+	} else if protocol == constants.UDP {
+		report.pass("endpoint reachability", fmt.Sprintf("no ICMP error received from %s %s, "+
+			"note UDP endpoints can only be partially checked", endpoint, protocol))
+	} else {
+		report.pass("endpoint reachability", fmt.Sprintf("connected to %s %s", endpoint, protocol))
+	}
+
+	return report.print()
+}
+
+func checkCustomWireguard(report *testReport, vpnSettings settings.VPN,
+	firewall settings.Firewall, ipv6Supported bool) (
+	endpoint netip.AddrPort, protocol string) {
+	wireguard := vpnSettings.Wireguard
+	selection := vpnSettings.Provider.ServerSelection.Wireguard
+
+	publicKeyChecked := false
+	privateKey, err := wgtypes.ParseKey(*wireguard.PrivateKey)
+	switch {
+	case *wireguard.PrivateKey == "":
+		report.fail("private key", errors.New("WIREGUARD_PRIVATE_KEY is not set, "+
+			"use the PrivateKey value from the [Interface] section of your configuration"))
+	case err != nil:
+		report.fail("private key", fmt.Errorf("WIREGUARD_PRIVATE_KEY must be a base64 encoded "+
+			"32 bytes key such as the output of 'wg genkey': %w", err))
+	case selection.PublicKey == *wireguard.PrivateKey:
+		report.fail("private key", errors.New("WIREGUARD_PRIVATE_KEY is equal to WIREGUARD_PUBLIC_KEY, "+
+			"the private key must be your own key from the [Interface] section"))
+	case selection.PublicKey == privateKey.PublicKey().String():
+		publicKeyChecked = true
+		report.fail("public key", errors.New("WIREGUARD_PUBLIC_KEY is the public key of "+
+			"your own private key, it must be the server key from the [Peer] section"))
+	default:
+		report.pass("private key", "valid, with public key "+privateKey.PublicKey().String())
+	}
+
+	_, err = wgtypes.ParseKey(selection.PublicKey)
+	switch {
+	case publicKeyChecked:
+	case selection.PublicKey == "":
+		report.fail("public key", errors.New("WIREGUARD_PUBLIC_KEY is not set, "+
+			"use the PublicKey value from the [Peer] section of your configuration"))
+	case err != nil:
+		report.fail("public key", fmt.Errorf("WIREGUARD_PUBLIC_KEY must be a base64 encoded "+
+			"32 bytes key: %w", err))
+	default:
+		report.pass("public key", "valid")
+	}
+
+	if *wireguard.PreSharedKey != "" {
+		_, err = wgtypes.ParseKey(*wireguard.PreSharedKey)
+		if err != nil {
+			report.fail("pre-shared key", fmt.Errorf("WIREGUARD_PRESHARED_KEY must be a base64 "+
+				"encoded 32 bytes key: %w", err))
+		} else {
+			report.pass("pre-shared key", "valid")
+		}
+	}
+
+	checkWireguardAddresses(report, wireguard.Addresses, ipv6Supported)
+
+	switch {
+	case !selection.EndpointIP.IsValid() || selection.EndpointIP.IsUnspecified():
+		report.fail("endpoint", errors.New("VPN_ENDPOINT_IP is not set, "+
+			"use the IP address from the Endpoint value of the [Peer] section"))
+	case *selection.EndpointPort == 0:
+		report.fail("endpoint", errors.New("VPN_ENDPOINT_PORT is not set, "+
+			"use the port from the Endpoint value of the [Peer] section"))
+	default:
+		endpoint = netip.AddrPortFrom(selection.EndpointIP, *selection.EndpointPort)
+		if selection.EndpointIP.IsPrivate() || selection.EndpointIP.IsLoopback() {
+			report.warn("endpoint", endpoint.String()+" is a private address, "+
+				"make sure this is intended")
+		} else {
+			report.pass("endpoint", endpoint.String())
+		}
+	}
+
+	checkWireguardAllowedIPs(report, wireguard.Addresses, wireguard.AllowedIPs,
+		endpoint.Addr(), firewall.OutboundSubnets)
+
+	return endpoint, constants.UDP
+}
+
+func checkWireguardAddresses(report *testReport, addresses []netip.Prefix,
+	ipv6Supported bool) {
+	if len(addresses) == 0 {
+		report.fail("addresses", errors.New("WIREGUARD_ADDRESSES is not set, "+
+			"use the Address value from the [Interface] section of your configuration"))
+		return
+	}
+
+	addressStrings := make([]string, len(addresses))
+	for i, address := range addresses {
+		addressStrings[i] = address.String()
+		switch {
+		case address.Addr().Is6() && !ipv6Supported:
+			report.fail("addresses", fmt.Errorf("address %s is IPv6 but IPv6 is not supported, "+
+				"remove it from WIREGUARD_ADDRESSES", address))
+			return
+		case address.Bits() != address.Addr().BitLen():
+			report.warn("addresses", fmt.Sprintf("address %s is usually a single IP address "+
+				"such as %s/%d", address, address.Addr(), address.Addr().BitLen()))
+		}
+	}
+	report.pass("addresses", strings.Join(addressStrings, ", "))
+}
+
+func checkWireguardAllowedIPs(report *testReport, addresses, allowedIPs []netip.Prefix,
+	endpointIP netip.Addr, outboundSubnets []netip.Prefix) {
+	for _, address := range addresses {
+		if endpointIP.IsValid() && address.Contains(endpointIP) {
+			report.fail("allowed IPs", fmt.Errorf("endpoint IP %s is within the interface "+
+				"address %s, which would route the tunnel through itself", endpointIP, address))
+			return
+		}
+		for _, subnet := range outboundSubnets {
+			if subnet.Overlaps(address) {
+				report.fail("allowed IPs", fmt.Errorf("interface address %s overlaps with "+
+					"FIREWALL_OUTBOUND_SUBNETS subnet %s", address, subnet))
+				return
+			}
+		}
+	}
+	if len(allowedIPs) > 0 {
+		allowedIPStrings := make([]string, len(allowedIPs))
+		for i, allowedIP := range allowedIPs {
+			allowedIPStrings[i] = allowedIP.String()
+		}
+		report.pass("allowed IPs", "only "+strings.Join(allowedIPStrings, ", ")+
+			" is routed through the tunnel")
+		return
+	}
+	report.pass("allowed IPs", "all traffic is routed through the tunnel "+
+		"except for the endpoint and outbound subnets")
+}
+
+func checkCustomOpenVPN(report *testReport, openvpnSettings settings.OpenVPN,
+	selection settings.OpenVPNSelection) (endpoint netip.AddrPort, protocol string) {
+	confFile := *openvpnSettings.ConfFile
+	if confFile == "" {
+		report.fail("configuration file", errors.New("OPENVPN_CUSTOM_CONFIG is not set, "+
+			"set it to the path of your .ovpn file bind mounted in the container"))
+		return endpoint, ""
+	}
+
+	lines, connection, err := extract.New().Data(confFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("%w: make sure the file is bind mounted in the container", err)
+		}
+		report.fail("configuration file", err)
+		return endpoint, ""
+	}
+	report.pass("configuration file", confFile)
+
+	result, err := sanitize.Sanitize(lines, "")
+	if err != nil {
+		report.fail("directives", err)
+	} else {
+		checkOpenVPNDirectives(report, result, *openvpnSettings.User != "")
+	}
+
+	protocol = connection.Protocol
+	if strings.HasPrefix(protocol, constants.TCP) {
+		protocol = constants.TCP
+	} else {
+		protocol = constants.UDP
+	}
+
+	port := connection.Port
+	configuredProtocol := constants.UDP
+	if *selection.TCP {
+		configuredProtocol = constants.TCP
+	}
+	switch {
+	case configuredProtocol != protocol:
+		report.warn("protocol", fmt.Sprintf("OPENVPN_PROTOCOL=%s is ignored, "+
+			"the protocol %s from the configuration file is used", configuredProtocol, protocol))
+	case *selection.CustomPort != 0 && *selection.CustomPort != connection.Port:
+		port = *selection.CustomPort
+		report.warn("port", fmt.Sprintf("VPN_ENDPOINT_PORT=%d overrides the port %d "+
+			"of the configuration file", port, connection.Port))
+	case protocol == constants.TCP && port == 1194, protocol == constants.UDP && port == 443:
+		report.warn("port", fmt.Sprintf("port %d is unusual for %s, "+
+			"make sure the protocol matches the server port", port, protocol))
+	default:
+		report.pass("protocol and port", fmt.Sprintf("%s port %d", protocol, port))
+	}
+
+	return netip.AddrPortFrom(connection.IP, port), protocol
+}
+
+func checkOpenVPNDirectives(report *testReport, result sanitize.Result, userSet bool) {
+	hasCA := false
+	for _, directive := range result.Directives {
+		switch {
+		case directive.Name == "ca":
+			hasCA = true
+		case directive.Action == sanitize.ActionRemoved:
+			report.warn("directives", fmt.Sprintf("line %d: %s is not supported "+
+				"and must be removed: %s", directive.Line, directive.Name, directive.Reason))
+		}
+	}
+
+	if !hasCA {
+		report.fail("directives", errors.New("no ca directive or <ca> block found, "+
+			"the server certificate authority is required"))
+	}
+
+	switch {
+	case result.AuthUserPass && !userSet:
+		report.fail("credentials", errors.New("the configuration uses auth-user-pass "+
+			"but OPENVPN_USER is not set"))
+	case !result.AuthUserPass && userSet:
+		report.warn("credentials", "OPENVPN_USER is set but the configuration "+
+			"has no auth-user-pass directive")
+	default:
+		report.pass("credentials", "coherent with the configuration")
+	}
+}
+
+// probeEndpoint connects to the TCP endpoint, or sends a UDP datagram
+// to the UDP endpoint and waits for an ICMP port unreachable error.
+func probeEndpoint(ctx context.Context, endpoint netip.AddrPort,
+	protocol string, timeout time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	connection, err := dialer.DialContext(ctx, protocol, endpoint.String())
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	if protocol == constants.TCP {
+		return nil
+	}
+
+	_, err = connection.Write([]byte{0})
+	if err != nil {
+		return err
+	}
+
+	const udpWait = time.Second
+	err = connection.SetReadDeadline(time.Now().Add(udpWait))
+	if err != nil {
+		return err
+	}
+	_, err = connection.Read(make([]byte, 1))
+	var netErr net.Error
+	switch {
+	case err == nil, errors.As(err, &netErr) && netErr.Timeout():
+		return nil
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return fmt.Errorf("%w", ErrEndpointPortClosed)
+	default:
+		return err
+	}
+}
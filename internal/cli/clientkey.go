@@ -8,11 +8,13 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/sources/files"
+	"github.com/qdm12/gluetun/internal/constants"
 )
 
 func (c *CLI) ClientKey(args []string) error {
 	flagSet := flag.NewFlagSet("clientkey", flag.ExitOnError)
-	filepath := flagSet.String("path", files.OpenVPNClientKeyPath, "file path to the client.key file")
+	filepath := flagSet.String("path", files.OpenVPNClientKeyPath(constants.DefaultDataDirectory),
+		"file path to the client.key file")
 	if err := flagSet.Parse(args); err != nil {
 		return err
 	}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+)
+
+var (
+	ErrShellNotSupported = errors.New("shell is not supported")
+	ErrShellUnspecified  = errors.New("shell was not specified")
+)
+
+// Completion prints a shell completion script for the shell
+// given as first argument, which can be 'bash', 'zsh' or 'fish'.
+func (c *CLI) Completion(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: it can be 'bash', 'zsh' or 'fish'", ErrShellUnspecified)
+	}
+
+	logger := newNoopLogger()
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+
+	allProviders := providers.All()
+	countriesSet := make(map[string]struct{})
+	for _, provider := range allProviders {
+		for _, country := range storage.GetFilterChoices(provider).Countries {
+			countriesSet[strings.ToLower(country)] = struct{}{}
+		}
+	}
+	countries := make([]string, 0, len(countriesSet))
+	for country := range countriesSet {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+
+	commands := makeCompletionCommands(allProviders, countries)
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion(commands))
+	case "zsh":
+		fmt.Print("autoload -U +X bashcompinit && bashcompinit\n" + bashCompletion(commands))
+	case "fish":
+		fmt.Print(fishCompletion(commands))
+	default:
+		return fmt.Errorf("%w: %s", ErrShellNotSupported, args[0])
+	}
+	return nil
+}
+
+type completionCommand struct {
+	name        string
+	subcommands []string
+	flags       []completionFlag
+}
+
+type completionFlag struct {
+	name   string
+	values []string
+}
+
+// makeCompletionCommands returns the CLI commands and their flags,
+// and must be kept in sync with the commands flag sets.
+func makeCompletionCommands(allProviders, countries []string) (
+	commands []completionCommand) {
+	provider := completionFlag{name: "provider", values: allProviders}
+	vpnType := completionFlag{name: "vpn-type", values: []string{vpn.OpenVPN, vpn.Wireguard}}
+	country := completionFlag{name: "country", values: countries}
+	flags := func(names ...string) (flags []completionFlag) {
+		flags = make([]completionFlag, len(names))
+		for i, name := range names {
+			flags[i] = completionFlag{name: name}
+		}
+		return flags
+	}
+
+	return []completionCommand{
+		{name: "benchmark", flags: append([]completionFlag{provider, vpnType, country},
+			flags("region", "city", "port-forwarding", "port", "parallel",
+				"top", "timeout", "env-file")...)},
+		{name: "check-custom", flags: flags("timeout")},
+		{name: "clientkey", flags: flags("path")},
+		{name: "completion", subcommands: []string{"bash", "zsh", "fish"}},
+		{name: "diagnose"},
+		{name: "dnsleaktest", flags: flags("timeout")},
+		{name: "format-servers", flags: append([]completionFlag{
+			{name: "format", values: []string{"markdown", "json", "csv", "table"}},
+		}, append(flags("columns", "output"), flags(allProviders...)...)...)},
+		{name: "genconfig", flags: append([]completionFlag{
+			provider, vpnType,
+			{name: "countries", values: countries},
+			{name: "format", values: []string{"compose", "env"}},
+		}, flags("openvpn-user", "openvpn-password", "wireguard-private-key",
+			"wireguard-addresses", "cities", "hostnames", "output", "interactive")...)},
+		{name: "healthcheck"},
+		{name: "import-servers", flags: append([]completionFlag{provider},
+			flags("source", "dry-run")...)},
+		{name: "list-servers", flags: append([]completionFlag{
+			provider, vpnType, country,
+			{name: "list", values: []string{"countries", "regions", "cities", "isps", "names", "hostnames"}},
+		}, flags("region", "city", "port-forwarding")...)},
+		{name: "mullvad-devices", subcommands: []string{"list", "remove", "rotate"},
+			flags: flags("account", "state")},
+		{name: "openvpnconfig"},
+		{name: "sanitize-ovpn", flags: flags("input", "output-dir", "container-dir")},
+		{name: "speedtest", flags: flags("download-url", "upload-url", "upload-mb", "timeout")},
+		{name: "test", flags: append([]completionFlag{provider, vpnType, country},
+			flags("hostname", "city", "timeout")...)},
+		{name: "update", flags: append([]completionFlag{
+			{name: "providers", values: allProviders},
+		}, flags("enduser", "maintainer", "dns", "minratio", "all",
+			"proton-email", "proton-password")...)},
+		{name: "wgkey", subcommands: []string{"rotate"},
+			flags: append([]completionFlag{provider}, flags("account", "output")...)},
+	}
+}
+
+func bashCompletion(commands []completionCommand) string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.name
+	}
+
+	lines := []string{
+		"_gluetun() {",
+		`	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"`,
+		"	local IFS=$'\\n'",
+		`	if [ "$COMP_CWORD" -eq 1 ]; then`,
+		`		COMPREPLY=($(compgen -W "` + strings.Join(names, "\n") + `" -- "$cur"))`,
+		"		return",
+		"	fi",
+		`	case "${COMP_WORDS[1]}" in`,
+	}
+	for _, command := range commands {
+		lines = append(lines, "	"+command.name+")")
+		if len(command.subcommands) > 0 {
+			lines = append(lines,
+				`		if [ "$COMP_CWORD" -eq 2 ]; then`,
+				`			COMPREPLY=($(compgen -W "`+strings.Join(command.subcommands, "\n")+`" -- "$cur"))`,
+				"			return",
+				"		fi")
+		}
+		flagNames := make([]string, len(command.flags))
+		lines = append(lines, `		case "$prev" in`)
+		for i, flag := range command.flags {
+			flagNames[i] = "-" + flag.name
+			if len(flag.values) == 0 {
+				continue
+			}
+			lines = append(lines,
+				"		"+bashQuote("-"+flag.name)+")",
+				`			COMPREPLY=($(compgen -W "`+strings.Join(flag.values, "\n")+`" -- "$cur" | `+
+					`while read -r value; do printf '%q\n' "$value"; done))`,
+				"			return",
+				"			;;")
+		}
+		lines = append(lines, "		esac")
+		if len(flagNames) > 0 {
+			lines = append(lines,
+				`		COMPREPLY=($(compgen -W "`+strings.Join(flagNames, "\n")+`" -- "$cur"))`)
+		}
+		lines = append(lines, "		;;")
+	}
+	lines = append(lines,
+		"	esac",
+		"}",
+		"complete -F _gluetun gluetun",
+	)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func bashQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func fishCompletion(commands []completionCommand) string {
+	lines := []string{"complete -c gluetun -f"}
+	for _, command := range commands {
+		lines = append(lines, "complete -c gluetun -n __fish_use_subcommand -a "+command.name)
+		condition := "'__fish_seen_subcommand_from " + command.name + "'"
+		for _, subcommand := range command.subcommands {
+			lines = append(lines, "complete -c gluetun -n "+condition+" -a "+subcommand)
+		}
+		for _, flag := range command.flags {
+			line := "complete -c gluetun -n " + condition + " -o " + fishQuote(flag.name)
+			if len(flag.values) > 0 {
+				quoted := make([]string, len(flag.values))
+				for i, value := range flag.values {
+					quoted[i] = fishQuote(value)
+				}
+				line += " -r -a " + fishQuote(strings.Join(quoted, " "))
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "'", `\'`)
+	return "'" + s + "'"
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/diagnostics"
+	"github.com/qdm12/gluetun/internal/sysctl"
+	"github.com/qdm12/golibs/command"
+)
+
+var ErrDiagnosticsFailed = errors.New("diagnostics failed")
+
+// Diagnose runs the pre-flight diagnostics for the settings read
+// from the source and prints a pass or fail report for each check.
+func (c *CLI) Diagnose(ctx context.Context, source Source,
+	netLinker diagnostics.NetLinker, cmder command.Runner,
+	tun diagnostics.TunChecker) error {
+	allSettings, err := source.Read()
+	if err != nil {
+		return err
+	}
+
+	// Kernel parameters are only read so nothing is logged.
+	sysctl := sysctl.New(newNoopLogger())
+	report := diagnostics.New(tun, netLinker, cmder, sysctl).Run(ctx, allSettings)
+	fmt.Println(report)
+	if report.Failed() {
+		return fmt.Errorf("%w", ErrDiagnosticsFailed)
+	}
+	return nil
+}
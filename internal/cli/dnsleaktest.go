@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/dnsleak"
+)
+
+// DNSLeakTest runs a DNS leak test and prints the resolvers which
+// answered. It is meant to be run in the container, so the queries
+// go through the DNS configuration of the container.
+func (c *CLI) DNSLeakTest(ctx context.Context, args []string) error {
+	flagSet := flag.NewFlagSet("dnsleaktest", flag.ExitOnError)
+	timeout := flagSet.Duration("timeout", time.Minute, "Timeout for the leak test")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	const httpTimeout = 15 * time.Second
+	tester := dnsleak.New(&http.Client{Timeout: httpTimeout})
+	result, err := tester.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.PublicIP != nil {
+		fmt.Printf("Public IP: %s %s %s\n", result.PublicIP.IP,
+			result.PublicIP.Country, result.PublicIP.ASN)
+	}
+	fmt.Printf("Resolvers (%d):\n", len(result.Resolvers))
+	for _, resolver := range result.Resolvers {
+		marker := ""
+		if resolver.NonVPN {
+			marker = " [NOT VPN]"
+		}
+		fmt.Printf("  %s %s %s%s\n", resolver.IP, resolver.Country, resolver.ASN, marker)
+	}
+	if result.Conclusion != "" {
+		fmt.Println("Conclusion: " + result.Conclusion)
+	}
+	return nil
+}
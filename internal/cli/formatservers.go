@@ -10,7 +10,6 @@ import (
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/providers"
-	"github.com/qdm12/gluetun/internal/storage"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -31,14 +30,17 @@ func addProviderFlag(flagSet *flag.FlagSet, providerToFormat map[string]*bool,
 }
 
 func (c *CLI) FormatServers(args []string) error {
-	var format, output string
+	var format, output, columnsCSV string
 	allProviders := providers.All()
 	providersToFormat := make(map[string]*bool, len(allProviders))
 	for _, provider := range allProviders {
 		providersToFormat[provider] = new(bool)
 	}
 	flagSet := flag.NewFlagSet("markdown", flag.ExitOnError)
-	flagSet.StringVar(&format, "format", "markdown", "Format to use which can be: 'markdown'")
+	flagSet.StringVar(&format, "format", "markdown",
+		"Format to use which can be: 'markdown', 'json', 'csv' or 'table'")
+	flagSet.StringVar(&columnsCSV, "columns", "", "Comma separated columns to output, "+
+		"for example 'country,city,hostname'. Defaults to the columns relevant to the provider")
 	flagSet.StringVar(&output, "output", "/dev/stdout", "Output file to write the formatted data to")
 	titleCaser := cases.Title(language.English)
 	for _, provider := range allProviders {
@@ -48,7 +50,9 @@ func (c *CLI) FormatServers(args []string) error {
 		return err
 	}
 
-	if format != "markdown" {
+	switch format {
+	case "markdown", "json", "csv", "table":
+	default:
 		return fmt.Errorf("%w: %s", ErrFormatNotRecognized, format)
 	}
 
@@ -71,12 +75,15 @@ func (c *CLI) FormatServers(args []string) error {
 	providerToFormat := providers[0]
 
 	logger := newNoopLogger()
-	storage, err := storage.New(logger, constants.ServersData)
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
 	if err != nil {
 		return fmt.Errorf("creating servers storage: %w", err)
 	}
 
-	formatted := storage.FormatToMarkdown(providerToFormat)
+	formatted, err := storage.FormatServers(providerToFormat, format, splitCSV(columnsCSV))
+	if err != nil {
+		return fmt.Errorf("formatting servers: %w", err)
+	}
 
 	output = filepath.Clean(output)
 	file, err := os.OpenFile(output, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
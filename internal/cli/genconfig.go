@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+)
+
+var ErrGenConfigFormatNotValid = errors.New("output format is not valid")
+
+type genConfigValues struct {
+	provider            string
+	vpnType             string
+	openvpnUser         string
+	openvpnPassword     string
+	wireguardPrivateKey string
+	wireguardAddresses  string
+	countries           string
+	cities              string
+	hostnames           string
+}
+
+// GenConfig generates a ready to run docker-compose snippet or
+// environment variables file, using values from flags or asked
+// interactively, and validated by the settings package.
+func (c *CLI) GenConfig(args []string) error {
+	var values genConfigValues
+	var format, output string
+	var interactive bool
+	flagSet := flag.NewFlagSet("genconfig", flag.ExitOnError)
+	flagSet.StringVar(&values.provider, "provider", "", "VPN service provider")
+	flagSet.StringVar(&values.vpnType, "vpn-type", "", "VPN type which can be 'openvpn' or 'wireguard', defaults to 'openvpn'")
+	flagSet.StringVar(&values.openvpnUser, "openvpn-user", "", "OpenVPN user")
+	flagSet.StringVar(&values.openvpnPassword, "openvpn-password", "", "OpenVPN password")
+	flagSet.StringVar(&values.wireguardPrivateKey, "wireguard-private-key", "", "Wireguard private key")
+	flagSet.StringVar(&values.wireguardAddresses, "wireguard-addresses", "",
+		"Comma separated Wireguard interface addresses")
+	flagSet.StringVar(&values.countries, "countries", "", "Comma separated server countries")
+	flagSet.StringVar(&values.cities, "cities", "", "Comma separated server cities")
+	flagSet.StringVar(&values.hostnames, "hostnames", "", "Comma separated server hostnames")
+	flagSet.StringVar(&format, "format", "compose", "Output format which can be 'compose' or 'env'")
+	flagSet.StringVar(&output, "output", "/dev/stdout", "Output file to write the configuration to")
+	flagSet.BoolVar(&interactive, "interactive", false, "Ask for values not set with flags")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if format != "compose" && format != "env" {
+		return fmt.Errorf("%w: %s", ErrGenConfigFormatNotValid, format)
+	}
+
+	if interactive {
+		err := askGenConfigValues(bufio.NewReader(os.Stdin), os.Stderr, &values)
+		if err != nil {
+			return fmt.Errorf("asking for values: %w", err)
+		}
+	}
+
+	if values.vpnType == "" {
+		values.vpnType = vpn.OpenVPN
+	}
+
+	vpnSettings, err := makeGenConfigVPNSettings(values)
+	if err != nil {
+		return err
+	}
+
+	logger := newNoopLogger()
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+
+	const ipv6Supported = false
+	err = vpnSettings.Validate(storage, ipv6Supported)
+	if err != nil {
+		return fmt.Errorf("validating settings: %w", err)
+	}
+
+	variables := makeGenConfigVariables(values)
+	var formatted string
+	if format == "env" {
+		formatted = formatEnvFile(variables)
+	} else {
+		formatted = formatComposeSnippet(variables)
+	}
+
+	const perms = 0600
+	return writeToFile(filepath.Clean(output), formatted, perms)
+}
+
+func askGenConfigValues(reader *bufio.Reader, writer io.Writer,
+	values *genConfigValues) (err error) {
+	err = ask(reader, writer, "VPN service provider", &values.provider)
+	if err != nil {
+		return err
+	}
+
+	err = ask(reader, writer, "VPN type (openvpn or wireguard)", &values.vpnType)
+	if err != nil {
+		return err
+	}
+
+	type question struct {
+		question string
+		value    *string
+	}
+	var questions []question
+	if values.vpnType == vpn.Wireguard {
+		questions = []question{
+			{question: "Wireguard private key", value: &values.wireguardPrivateKey},
+			{question: "Wireguard addresses", value: &values.wireguardAddresses},
+		}
+	} else {
+		questions = []question{
+			{question: "OpenVPN user", value: &values.openvpnUser},
+			{question: "OpenVPN password", value: &values.openvpnPassword},
+		}
+	}
+	questions = append(questions,
+		question{question: "Server countries (optional)", value: &values.countries},
+		question{question: "Server cities (optional)", value: &values.cities},
+	)
+
+	for _, question := range questions {
+		err = ask(reader, writer, question.question, question.value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ask asks the question to the user if the value is not already set.
+func ask(reader *bufio.Reader, writer io.Writer, question string, value *string) (err error) {
+	if *value != "" {
+		return nil
+	}
+
+	_, err = fmt.Fprint(writer, question+": ")
+	if err != nil {
+		return err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	*value = strings.TrimSpace(line)
+	return nil
+}
+
+func makeGenConfigVPNSettings(values genConfigValues) (
+	vpnSettings settings.VPN, err error) {
+	vpnSettings.Type = values.vpnType
+	vpnSettings.Provider.Name = &values.provider
+	vpnSettings.Provider.ServerSelection.VPN = values.vpnType
+	// Server selection values are lowercased as done when reading them
+	// from environment variables.
+	vpnSettings.Provider.ServerSelection.Countries = splitCSV(strings.ToLower(values.countries))
+	vpnSettings.Provider.ServerSelection.Cities = splitCSV(strings.ToLower(values.cities))
+	vpnSettings.Provider.ServerSelection.Hostnames = splitCSV(strings.ToLower(values.hostnames))
+	// Leave unset values as nil so defaults apply, such as the
+	// Mullvad OpenVPN password.
+	vpnSettings.OpenVPN.User = nilIfEmpty(values.openvpnUser)
+	vpnSettings.OpenVPN.Password = nilIfEmpty(values.openvpnPassword)
+	vpnSettings.Wireguard.PrivateKey = nilIfEmpty(values.wireguardPrivateKey)
+
+	for _, address := range splitCSV(values.wireguardAddresses) {
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return vpnSettings, fmt.Errorf("parsing Wireguard address: %w", err)
+		}
+		vpnSettings.Wireguard.Addresses = append(vpnSettings.Wireguard.Addresses, prefix)
+	}
+
+	allSettings := settings.Settings{VPN: vpnSettings}
+	allSettings.SetDefaults()
+	return allSettings.VPN, nil
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func splitCSV(csv string) (values []string) {
+	if csv == "" {
+		return nil
+	}
+	values = strings.Split(csv, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+type variable struct {
+	key   string
+	value string
+}
+
+func makeGenConfigVariables(values genConfigValues) (variables []variable) {
+	variables = []variable{
+		{key: "VPN_SERVICE_PROVIDER", value: values.provider},
+		{key: "VPN_TYPE", value: values.vpnType},
+	}
+
+	if values.vpnType == vpn.Wireguard {
+		variables = append(variables,
+			variable{key: "WIREGUARD_PRIVATE_KEY", value: values.wireguardPrivateKey},
+			variable{key: "WIREGUARD_ADDRESSES", value: values.wireguardAddresses})
+	} else {
+		variables = append(variables,
+			variable{key: "OPENVPN_USER", value: values.openvpnUser})
+		if values.openvpnPassword != "" {
+			variables = append(variables,
+				variable{key: "OPENVPN_PASSWORD", value: values.openvpnPassword})
+		}
+	}
+
+	optional := []variable{
+		{key: "SERVER_COUNTRIES", value: values.countries},
+		{key: "SERVER_CITIES", value: values.cities},
+		{key: "SERVER_HOSTNAMES", value: values.hostnames},
+	}
+	for _, variable := range optional {
+		if variable.value != "" {
+			variables = append(variables, variable)
+		}
+	}
+
+	return variables
+}
+
+func formatEnvFile(variables []variable) string {
+	lines := make([]string, len(variables))
+	for i, variable := range variables {
+		lines[i] = variable.key + "=" + variable.value
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func formatComposeSnippet(variables []variable) string {
+	lines := []string{
+		"services:",
+		"  gluetun:",
+		"    image: qmcgaw/gluetun",
+		"    container_name: gluetun",
+		"    cap_add:",
+		"      - NET_ADMIN",
+		"    devices:",
+		"      - /dev/net/tun:/dev/net/tun",
+		"    volumes:",
+		"      - ./gluetun:/gluetun",
+		"    environment:",
+	}
+	for _, variable := range variables {
+		lines = append(lines, "      "+variable.key+": "+strconv.Quote(variable.value))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func writeToFile(path, data string, perm fs.FileMode) (err error) {
+	file, err := os.OpenFile(path, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, perm)
+	if err != nil {
+		return fmt.Errorf("opening output file: %w", err)
+	}
+
+	// Set the permissions explicitly in case the file
+	// already existed or the umask restricted them.
+	err = file.Chmod(perm)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("setting output file permissions: %w", err)
+	}
+
+	_, err = fmt.Fprint(file, data)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("writing to output file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("closing output file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrImportSourceUnspecified = errors.New("servers source to import was not specified")
+	ErrNoServersToImport       = errors.New("no servers to import")
+	ErrHTTPStatusCodeNotOK     = errors.New("HTTP status code is not OK")
+)
+
+type ImportLogger interface {
+	Info(s string)
+}
+
+// ImportServers imports servers data from a local file or an URL
+// into the servers data file, to update servers without the updater,
+// for example on hosts without Internet access.
+func (c *CLI) ImportServers(ctx context.Context, args []string, logger ImportLogger) error {
+	var source, provider string
+	var dryRun bool
+	flagSet := flag.NewFlagSet("import-servers", flag.ExitOnError)
+	flagSet.StringVar(&source, "source", "",
+		"File path or http(s) URL of the servers data to import")
+	flagSet.StringVar(&provider, "provider", "", "VPN provider of the servers data to import, "+
+		"if the data contains only the servers of this provider instead of all providers")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "Only show the changes without writing them")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if source == "" {
+		return fmt.Errorf("%w", ErrImportSourceUnspecified)
+	}
+
+	provider = strings.ToLower(provider)
+	if provider != "" && (provider == providers.Custom || !isValidProvider(provider)) {
+		return fmt.Errorf("%w: %s", ErrProviderNotValid, provider)
+	}
+
+	data, err := readImportSource(ctx, source)
+	if err != nil {
+		return fmt.Errorf("reading servers data: %w", err)
+	}
+
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+
+	allServers, err := storage.ParseServers(data, provider)
+	if err != nil {
+		return fmt.Errorf("parsing servers data: %w", err)
+	}
+
+	importProviders := make([]string, 0, len(allServers.ProviderToServers))
+	for provider, servers := range allServers.ProviderToServers {
+		for _, server := range servers.Servers {
+			err = server.HasMinimumInformation()
+			if err != nil {
+				return fmt.Errorf("%s server %s: %w", provider, server.Key(), err)
+			}
+		}
+		importProviders = append(importProviders, provider)
+	}
+	if len(importProviders) == 0 {
+		return fmt.Errorf("%w", ErrNoServersToImport)
+	}
+	sort.Strings(importProviders)
+
+	for _, provider := range importProviders {
+		servers := allServers.ProviderToServers[provider].Servers
+		diff := diffServers(storage.GetServers(provider), servers)
+		logger.Info(provider + ": " + diff.String())
+		if dryRun {
+			continue
+		}
+
+		err = storage.SetServers(provider, servers)
+		if err != nil {
+			return fmt.Errorf("setting %s servers: %w", provider, err)
+		}
+	}
+
+	return nil
+}
+
+func readImportSource(ctx context.Context, source string) (data []byte, err error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	const timeout = 30 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	return io.ReadAll(response.Body)
+}
+
+type serversDiff struct {
+	added, removed, changed, unchanged int
+}
+
+func (d serversDiff) String() string {
+	return fmt.Sprintf("%d servers added, %d removed, %d changed and %d unchanged",
+		d.added, d.removed, d.changed, d.unchanged)
+}
+
+// diffServers compares the existing servers with the imported servers,
+// matching servers using their key.
+func diffServers(existing, imported []models.Server) (diff serversDiff) {
+	keyToExisting := make(map[string]models.Server, len(existing))
+	for _, server := range existing {
+		keyToExisting[server.Key()] = server
+	}
+
+	for _, server := range imported {
+		key := server.Key()
+		existingServer, ok := keyToExisting[key]
+		switch {
+		case !ok:
+			diff.added++
+		case existingServer.Equal(server):
+			diff.unchanged++
+		default:
+			diff.changed++
+		}
+		delete(keyToExisting, key)
+	}
+	diff.removed = len(keyToExisting)
+
+	return diff
+}
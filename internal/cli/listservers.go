@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/validation"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrListFieldNotValid = errors.New("field to list is not valid")
+	ErrProviderNotValid  = errors.New("VPN provider is not valid")
+	ErrVPNTypeNotValid   = errors.New("VPN type is not valid")
+)
+
+// ListServers prints the distinct values of a server field, such as
+// countries or cities, for the servers of a provider matching the
+// filters given as flags.
+func (c *CLI) ListServers(args []string) error {
+	var field, provider, vpnType, countriesCSV, regionsCSV, citiesCSV string
+	var portForwardOnly bool
+	flagSet := flag.NewFlagSet("list-servers", flag.ExitOnError)
+	flagSet.StringVar(&provider, "provider", "", "VPN provider to list servers values for")
+	flagSet.StringVar(&field, "list", "countries",
+		"Field to list which can be: 'countries', 'regions', 'cities', 'isps', 'names' or 'hostnames'")
+	flagSet.StringVar(&vpnType, "vpn-type", "", "Only list servers supporting this VPN type, "+
+		"which can be 'openvpn' or 'wireguard'")
+	flagSet.StringVar(&countriesCSV, "country", "", "Comma separated countries to filter servers with")
+	flagSet.StringVar(&regionsCSV, "region", "", "Comma separated regions to filter servers with")
+	flagSet.StringVar(&citiesCSV, "city", "", "Comma separated cities to filter servers with")
+	flagSet.BoolVar(&portForwardOnly, "port-forwarding", false, "Only list servers supporting port forwarding")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	provider = strings.ToLower(provider)
+	if provider == "" {
+		return fmt.Errorf("%w", ErrProviderUnspecified)
+	} else if provider == providers.Custom || !isValidProvider(provider) {
+		return fmt.Errorf("%w: %s", ErrProviderNotValid, provider)
+	}
+
+	switch vpnType {
+	case "", vpn.OpenVPN, vpn.Wireguard:
+	default:
+		return fmt.Errorf("%w: %s", ErrVPNTypeNotValid, vpnType)
+	}
+
+	extract, err := getExtractFunction(field)
+	if err != nil {
+		return err
+	}
+
+	logger := newNoopLogger()
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+
+	filter := serversFilter{
+		vpnType:         vpnType,
+		countries:       splitCSV(countriesCSV),
+		regions:         splitCSV(regionsCSV),
+		cities:          splitCSV(citiesCSV),
+		portForwardOnly: portForwardOnly,
+	}
+	servers := filter.apply(storage.GetServers(provider))
+
+	for _, value := range extract(servers) {
+		fmt.Println(value)
+	}
+
+	return nil
+}
+
+func isValidProvider(provider string) (valid bool) {
+	for _, candidate := range providers.All() {
+		if candidate == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func getExtractFunction(field string) (
+	extract func(servers []models.Server) (values []string), err error) {
+	switch strings.ToLower(field) {
+	case "countries":
+		return validation.ExtractCountries, nil
+	case "regions":
+		return validation.ExtractRegions, nil
+	case "cities":
+		return validation.ExtractCities, nil
+	case "isps":
+		return validation.ExtractISPs, nil
+	case "names":
+		return validation.ExtractServerNames, nil
+	case "hostnames":
+		return validation.ExtractHostnames, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrListFieldNotValid, field)
+	}
+}
+
+type serversFilter struct {
+	vpnType         string
+	countries       []string
+	regions         []string
+	cities          []string
+	portForwardOnly bool
+}
+
+func (f serversFilter) apply(servers []models.Server) (filtered []models.Server) {
+	filtered = make([]models.Server, 0, len(servers))
+	for _, server := range servers {
+		switch {
+		case f.vpnType != "" && server.VPN != f.vpnType,
+			f.portForwardOnly && !server.PortForward,
+			!matchesAny(server.Country, f.countries),
+			!matchesAny(server.Region, f.regions),
+			!matchesAny(server.City, f.cities):
+			continue
+		}
+		filtered = append(filtered, server)
+	}
+	return filtered
+}
+
+func matchesAny(value string, possibilities []string) (matches bool) {
+	if len(possibilities) == 0 {
+		return true
+	}
+	for _, possibility := range possibilities {
+		if strings.EqualFold(value, possibility) {
+			return true
+		}
+	}
+	return false
+}
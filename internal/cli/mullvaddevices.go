@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/provider/mullvad"
+	"github.com/qdm12/gluetun/internal/system"
+)
+
+// MullvadDevices runs Mullvad devices subcommands, which are 'list'
+// to list the devices registered on the account, 'remove' to remove
+// devices by ID or name, and 'rotate' to replace the Wireguard key of
+// the device registered by the program.
+func (c *CLI) MullvadDevices(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: it can be 'list', 'remove' or 'rotate'",
+			ErrSubcommandUnspecified)
+	}
+
+	subcommand := args[0]
+	flagSet := flag.NewFlagSet("mullvad-devices "+subcommand, flag.ExitOnError)
+	account := flagSet.String("account", os.Getenv("WIREGUARD_ACCOUNT_NUMBER"),
+		"Mullvad account number, defaulting to WIREGUARD_ACCOUNT_NUMBER")
+	statePath := flagSet.String("state", mullvad.DeviceStatePath(constants.DefaultDataDirectory),
+		"File path of the device registered by the program")
+	if err := flagSet.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *account == "" {
+		return fmt.Errorf("%w", ErrAccountUnspecified)
+	}
+
+	const clientTimeout = 10 * time.Second
+	client := &http.Client{Timeout: clientTimeout}
+
+	switch subcommand {
+	case "list":
+		return mullvadDevicesList(ctx, client, *account, *statePath)
+	case "remove":
+		return mullvadDevicesRemove(ctx, client, *account, flagSet.Args())
+	case "rotate":
+		fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+		device, err := mullvad.RotateDevice(ctx, client, fileWriter, *account, *statePath)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Rotated key of device " + device.Name + ", restart to use the new key")
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrSubcommandUnknown, subcommand)
+	}
+}
+
+func mullvadDevicesList(ctx context.Context, client *http.Client,
+	account, statePath string) (err error) {
+	state, err := mullvad.ReadDeviceState(statePath)
+	if err != nil {
+		return fmt.Errorf("reading device state: %w", err)
+	}
+
+	accessToken, err := mullvad.FetchAccessToken(ctx, client, account)
+	if err != nil {
+		return fmt.Errorf("fetching access token: %w", err)
+	}
+
+	devices, err := mullvad.ListDevices(ctx, client, accessToken)
+	if err != nil {
+		return fmt.Errorf("listing devices: %w", err)
+	}
+
+	const minWidth, tabWidth, padding = 0, 0, 2
+	writer := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, ' ', 0)
+	_, err = fmt.Fprintln(writer, "ID\tNAME\tCREATED\tPUBLIC KEY\tADDRESSES\tTHIS")
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		addresses := make([]string, 0, len(device.Addresses()))
+		for _, address := range device.Addresses() {
+			addresses = append(addresses, address.String())
+		}
+		this := ""
+		if device.ID == state.ID {
+			this = "*"
+		}
+		_, err = fmt.Fprintln(writer, strings.Join([]string{
+			device.ID,
+			device.Name,
+			device.Created.Format(time.DateOnly),
+			device.PublicKey,
+			dashIfEmpty(strings.Join(addresses, ",")),
+			this,
+		}, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func mullvadDevicesRemove(ctx context.Context, client *http.Client,
+	account string, idsOrNames []string) (err error) {
+	if len(idsOrNames) == 0 {
+		return fmt.Errorf("%w: specify device IDs or names to remove",
+			ErrSubcommandUnspecified)
+	}
+
+	accessToken, err := mullvad.FetchAccessToken(ctx, client, account)
+	if err != nil {
+		return fmt.Errorf("fetching access token: %w", err)
+	}
+
+	devices, err := mullvad.ListDevices(ctx, client, accessToken)
+	if err != nil {
+		return fmt.Errorf("listing devices: %w", err)
+	}
+
+	for _, idOrName := range idsOrNames {
+		device, found := findMullvadDevice(devices, idOrName)
+		if !found {
+			return fmt.Errorf("%w: %s", mullvad.ErrDeviceNotFound, idOrName)
+		}
+		err = mullvad.RemoveDevice(ctx, client, accessToken, device.ID)
+		if err != nil {
+			return fmt.Errorf("removing device %s: %w", device.Name, err)
+		}
+		fmt.Println("Removed device " + device.Name)
+	}
+	return nil
+}
+
+func findMullvadDevice(devices []mullvad.Device, idOrName string) (
+	device mullvad.Device, found bool) {
+	for _, device := range devices {
+		if device.ID == idOrName || strings.EqualFold(device.Name, idOrName) {
+			return device, true
+		}
+	}
+	return device, false
+}
@@ -8,11 +8,10 @@ import (
 	"strings"
 	"time"
 
-	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/openvpn"
 	"github.com/qdm12/gluetun/internal/openvpn/extract"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
-	"github.com/qdm12/gluetun/internal/storage"
 	"github.com/qdm12/gluetun/internal/updater/resolver"
 )
 
@@ -41,12 +40,12 @@ type IPv6Checker interface {
 
 func (c *CLI) OpenvpnConfig(logger OpenvpnConfigLogger, source Source,
 	ipv6Checker IPv6Checker) error {
-	storage, err := storage.New(logger, constants.ServersData)
+	allSettings, err := source.Read()
 	if err != nil {
 		return err
 	}
 
-	allSettings, err := source.Read()
+	storage, err := newStorage(logger, *allSettings.System.DataDirectory)
 	if err != nil {
 		return err
 	}
@@ -69,7 +68,8 @@ func (c *CLI) OpenvpnConfig(logger OpenvpnConfigLogger, source Source,
 	openvpnFileExtractor := extract.New()
 
 	providers := provider.NewProviders(storage, time.Now, warner, client,
-		unzipper, parallelResolver, ipFetcher, openvpnFileExtractor)
+		unzipper, parallelResolver, ipFetcher, openvpnFileExtractor, "", "",
+		*allSettings.System.DataDirectory, *allSettings.System.RuntimeDirectory)
 	providerConf := providers.Get(*allSettings.VPN.Provider.Name)
 	connection, err := providerConf.GetConnection(
 		allSettings.VPN.Provider.ServerSelection, ipv6Supported)
@@ -79,6 +79,13 @@ func (c *CLI) OpenvpnConfig(logger OpenvpnConfigLogger, source Source,
 
 	lines := providerConf.OpenVPNConfig(connection,
 		allSettings.VPN.OpenVPN, ipv6Supported)
+	runtimeDirectory := *allSettings.System.RuntimeDirectory
+	if *allSettings.VPN.OpenVPN.User != "" {
+		lines = append(lines, "auth-user-pass "+openvpn.AuthConf(runtimeDirectory))
+	}
+	if *allSettings.VPN.OpenVPN.EncryptedKey != "" {
+		lines = append(lines, "askpass "+openvpn.AskPassPath(runtimeDirectory))
+	}
 
 	fmt.Println(strings.Join(lines, "\n"))
 	return nil
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/openvpn/sanitize"
+)
+
+var ErrInputUnspecified = errors.New("input file was not specified")
+
+// SanitizeOpenVPN reads an OpenVPN configuration file, reports which
+// directives are kept, overridden or removed, extracts its inline blocks
+// to files and writes a cleaned configuration to be used with the
+// custom provider, printing the matching environment variables.
+func (c *CLI) SanitizeOpenVPN(args []string) error {
+	var input, outputDir, containerDir string
+	flagSet := flag.NewFlagSet("sanitize-ovpn", flag.ExitOnError)
+	flagSet.StringVar(&input, "input", "", "OpenVPN configuration file path to sanitize")
+	flagSet.StringVar(&outputDir, "output-dir", ".",
+		"Directory to write the sanitized configuration and extracted files to")
+	flagSet.StringVar(&containerDir, "container-dir", constants.DefaultDataDirectory,
+		"Directory where the output directory is bind mounted in the container")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if input == "" {
+		return fmt.Errorf("%w", ErrInputUnspecified)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	result, err := sanitize.Sanitize(lines, containerDir)
+	if err != nil {
+		return fmt.Errorf("sanitizing configuration: %w", err)
+	}
+
+	err = printDirectivesReport(result.Directives)
+	if err != nil {
+		return fmt.Errorf("printing report: %w", err)
+	}
+
+	const configFilename = "custom.conf"
+	const perms = 0600
+	err = writeToFile(filepath.Join(outputDir, configFilename), strings.Join(result.Lines, "\n"), perms)
+	if err != nil {
+		return err
+	}
+	for _, file := range result.Files {
+		err = writeToFile(filepath.Join(outputDir, file.Name), file.Content, perms)
+		if err != nil {
+			return err
+		}
+	}
+
+	variables := []variable{
+		{key: "VPN_SERVICE_PROVIDER", value: providers.Custom},
+		{key: "VPN_TYPE", value: vpn.OpenVPN},
+		{key: "OPENVPN_CUSTOM_CONFIG", value: path.Join(containerDir, configFilename)},
+	}
+	if result.AuthUserPass {
+		variables = append(variables,
+			variable{key: "OPENVPN_USER"},
+			variable{key: "OPENVPN_PASSWORD"})
+	}
+	fmt.Println()
+	fmt.Print(formatEnvFile(variables))
+
+	return nil
+}
+
+func printDirectivesReport(directives []sanitize.Directive) (err error) {
+	const minWidth, tabWidth, padding = 0, 0, 2
+	writer := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, ' ', 0)
+	_, err = fmt.Fprintln(writer, "LINE\tDIRECTIVE\tACTION\tREASON")
+	if err != nil {
+		return err
+	}
+	for _, directive := range directives {
+		_, err = fmt.Fprintln(writer, strings.Join([]string{
+			strconv.Itoa(directive.Line),
+			directive.Name,
+			string(directive.Action),
+			dashIfEmpty(directive.Reason),
+		}, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/speedtest"
+)
+
+// SpeedTest runs a speed test and prints its results. It is meant
+// to be run in the container, so the traffic goes through the tunnel.
+func (c *CLI) SpeedTest(ctx context.Context, args []string) error {
+	var defaults settings.Settings
+	defaults.SetDefaults()
+	speedTestSettings := defaults.SpeedTest
+
+	flagSet := flag.NewFlagSet("speedtest", flag.ExitOnError)
+	flagSet.StringVar(&speedTestSettings.DownloadURL, "download-url",
+		speedTestSettings.DownloadURL, "URL to download data from")
+	flagSet.StringVar(speedTestSettings.UploadURL, "upload-url",
+		*speedTestSettings.UploadURL, "URL to upload data to, leave empty to skip the upload test")
+	flagSet.Func("upload-mb", fmt.Sprintf("Megabytes to upload (default %d)",
+		speedTestSettings.UploadMegabytes), func(s string) (err error) {
+		_, err = fmt.Sscan(s, &speedTestSettings.UploadMegabytes)
+		return err
+	})
+	flagSet.DurationVar(&speedTestSettings.Timeout, "timeout",
+		speedTestSettings.Timeout, "Timeout for each of the download and upload tests")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	tester := speedtest.New(speedTestSettings, &http.Client{})
+	result, err := tester.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Latency: %.1fms\n", result.LatencyMilliseconds)
+	fmt.Printf("Download: %.2f Mbps (%d bytes)\n", result.DownloadMbps, result.DownloadBytes)
+	if result.UploadBytes > 0 {
+		fmt.Printf("Upload: %.2f Mbps (%d bytes)\n", result.UploadMbps, result.UploadBytes)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/storage"
+	"github.com/qdm12/gluetun/internal/system"
+)
+
+// newStorage creates the servers storage used by commands,
+// writing the servers data file in the data directory given
+// as the current user.
+func newStorage(logger storage.Infoer, dataDirectory string) (*storage.Storage, error) {
+	const filePermissions = 0644
+	fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+	return storage.New(logger, constants.ServersData(dataDirectory), filePermissions, fileWriter,
+		constants.ServersMergeNewest)
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/openvpn/extract"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/qdm12/gluetun/internal/tun"
+	"github.com/qdm12/gluetun/internal/wireguard"
+	"github.com/qdm12/golibs/command"
+)
+
+var ErrConnectivityTestFailed = errors.New("connectivity test failed")
+
+type TestLogger interface {
+	Debug(s string)
+	Debugf(format string, args ...interface{})
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+	Errorf(format string, args ...interface{})
+}
+
+type TestNetLinker interface {
+	wireguard.NetLinker
+	IPv6Checker
+}
+
+type TunChecker interface {
+	Setup(tunDevice string, logger tun.Logger) error
+}
+
+type vpnRunner interface {
+	Run(ctx context.Context, waitError chan<- error, tunnelReady chan<- struct{})
+}
+
+// ConnectivityTest resolves a VPN server, connects to it, checks the
+// public IP address through the tunnel and then tears everything down,
+// printing a pass or fail report for each step.
+func (c *CLI) ConnectivityTest(ctx context.Context, args []string, logger TestLogger,
+	source Source, netLinker TestNetLinker, cmder command.RunStarter, tun TunChecker) error {
+	flagSet := flag.NewFlagSet("test", flag.ExitOnError)
+	// Flags override their corresponding environment variable, so
+	// settings defaults depending on them are correctly set.
+	flagToEnvKey := map[string]string{
+		"provider": "VPN_SERVICE_PROVIDER",
+		"vpn-type": "VPN_TYPE",
+		"hostname": "SERVER_HOSTNAMES",
+		"country":  "SERVER_COUNTRIES",
+		"city":     "SERVER_CITIES",
+	}
+	flagValues := make(map[string]*string, len(flagToEnvKey))
+	for flagName, envKey := range flagToEnvKey {
+		flagValues[flagName] = flagSet.String(flagName, "", "Overrides "+envKey)
+	}
+	const defaultTimeout = 30 * time.Second
+	timeout := flagSet.Duration("timeout", defaultTimeout, "Timeout for the VPN connection attempt")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	for flagName, value := range flagValues {
+		if *value == "" {
+			continue
+		}
+		err := os.Setenv(flagToEnvKey[flagName], *value)
+		if err != nil {
+			return fmt.Errorf("setting environment variable: %w", err)
+		}
+	}
+
+	allSettings, err := source.Read()
+	if err != nil {
+		return err
+	}
+
+	ipv6Supported, err := netLinker.IsIPv6Supported()
+	if err != nil {
+		return fmt.Errorf("checking for IPv6 support: %w", err)
+	}
+
+	storage, err := newStorage(logger, *allSettings.System.DataDirectory)
+	if err != nil {
+		return fmt.Errorf("creating servers storage: %w", err)
+	}
+
+	err = allSettings.VPN.Validate(storage, ipv6Supported)
+	if err != nil {
+		return fmt.Errorf("validating VPN settings: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: *timeout}
+	report := testReport{failedErr: ErrConnectivityTestFailed}
+
+	vpnSettings := allSettings.VPN
+	providers := provider.NewProviders(storage, time.Now, logger, httpClient,
+		nil, nil, nil, extract.New(), "", "",
+		*allSettings.System.DataDirectory, *allSettings.System.RuntimeDirectory)
+	providerConf := providers.Get(*vpnSettings.Provider.Name)
+	connection, err := providerConf.GetConnection(vpnSettings.Provider.ServerSelection, ipv6Supported)
+	if err != nil {
+		report.fail("server resolution", err)
+		return report.print()
+	}
+	report.pass("server resolution", formatConnection(connection))
+
+	runner, err := setupTestRunner(vpnSettings, allSettings.System, providerConf, connection,
+		ipv6Supported, netLinker, cmder, tun, logger)
+	if err != nil {
+		report.fail("connection", err)
+		report.skip("public IP check")
+		return report.print()
+	}
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	waitError := make(chan error)
+	tunnelReady := make(chan struct{})
+	go runner.Run(runCtx, waitError, tunnelReady)
+
+	timer := time.NewTimer(*timeout)
+	select {
+	case <-tunnelReady:
+		timer.Stop()
+		report.pass("connection", "tunnel is up")
+		report.checkPublicIP(ctx, httpClient)
+	case err := <-waitError:
+		timer.Stop()
+		runCancel()
+		report.fail("connection", err)
+		report.skip("public IP check")
+		return report.print()
+	case <-timer.C:
+		report.fail("connection", fmt.Errorf("%w: tunnel not up after %s",
+			context.DeadlineExceeded, *timeout))
+		report.skip("public IP check")
+	}
+
+	// Tear down
+	runCancel()
+	<-waitError
+
+	return report.print()
+}
+
+func setupTestRunner(vpnSettings settings.VPN, systemSettings settings.System,
+	providerConf provider.Provider, connection models.Connection,
+	ipv6Supported bool, netLinker TestNetLinker, cmder command.RunStarter,
+	tun TunChecker, logger TestLogger) (runner vpnRunner, err error) {
+	if vpnSettings.Type == vpn.Wireguard {
+		wireguardSettings := utils.BuildWireguardSettings(connection,
+			vpnSettings.Wireguard, ipv6Supported)
+		return wireguard.New(wireguardSettings, netLinker, logger)
+	}
+
+	const tunDevice = "/dev/net/tun"
+	err = tun.Setup(tunDevice, logger)
+	if err != nil {
+		return nil, fmt.Errorf("setting up tun device: %w", err)
+	}
+
+	puid, pgid := int(*systemSettings.PUID), int(*systemSettings.PGID)
+	fileWriter := system.NewFileWriter(puid, pgid)
+	configurator := openvpn.New(logger, cmder, fileWriter, *systemSettings.RuntimeDirectory)
+	lines := providerConf.OpenVPNConfig(connection, vpnSettings.OpenVPN, ipv6Supported)
+
+	if *vpnSettings.OpenVPN.User != "" {
+		path, err := configurator.WriteAuthFile(*vpnSettings.OpenVPN.User, *vpnSettings.OpenVPN.Password)
+		if err != nil {
+			return nil, fmt.Errorf("writing auth to file: %w", err)
+		}
+		lines = append(lines, "auth-user-pass "+path)
+	}
+
+	if *vpnSettings.OpenVPN.KeyPassphrase != "" {
+		path, err := configurator.WriteAskPassFile(*vpnSettings.OpenVPN.KeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("writing askpass file: %w", err)
+		}
+		if *vpnSettings.OpenVPN.EncryptedKey != "" {
+			lines = append(lines, "askpass "+path)
+		}
+	}
+
+	configPath, err := configurator.WriteConfig(lines)
+	if err != nil {
+		return nil, fmt.Errorf("writing configuration to file: %w", err)
+	}
+
+	return openvpn.NewRunner(vpnSettings.OpenVPN, configPath, cmder, logger), nil
+}
+
+func formatConnection(connection models.Connection) string {
+	s := connection.IP.String() + ":" + fmt.Sprint(connection.Port) + " " + connection.Protocol
+	if connection.Hostname != "" {
+		s = connection.Hostname + " " + s
+	}
+	if connection.ServerName != "" {
+		s += " (" + connection.ServerName + ")"
+	}
+	return s
+}
+
+type testReport struct {
+	lines  []string
+	failed bool
+	// failedErr is the error returned by print if any step failed.
+	failedErr error
+}
+
+func (r *testReport) pass(step, details string) {
+	r.lines = append(r.lines, "PASS "+step+": "+details)
+}
+
+func (r *testReport) fail(step string, err error) {
+	r.failed = true
+	r.lines = append(r.lines, "FAIL "+step+": "+err.Error())
+}
+
+func (r *testReport) warn(step, details string) {
+	r.lines = append(r.lines, "WARN "+step+": "+details)
+}
+
+func (r *testReport) skip(step string) {
+	r.lines = append(r.lines, "SKIP "+step)
+}
+
+func (r *testReport) checkPublicIP(ctx context.Context, client *http.Client) {
+	fetcher := ipinfo.New(client)
+	result, err := fetcher.FetchInfo(ctx, netip.Addr{})
+	if err != nil {
+		r.fail("public IP check", err)
+		return
+	}
+	publicIP := result.ToPublicIPModel()
+	r.pass("public IP check", publicIP.IP.String()+" in "+publicIP.Country)
+}
+
+// print prints the report and returns an error if any step failed.
+func (r *testReport) print() error {
+	for _, line := range r.lines {
+		fmt.Println(line)
+	}
+	if r.failed {
+		return fmt.Errorf("%w", r.failedErr)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,7 +16,6 @@ import (
 	"github.com/qdm12/gluetun/internal/openvpn/extract"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
-	"github.com/qdm12/gluetun/internal/storage"
 	"github.com/qdm12/gluetun/internal/updater"
 	"github.com/qdm12/gluetun/internal/updater/resolver"
 	"github.com/qdm12/gluetun/internal/updater/unzip"
@@ -24,6 +24,7 @@ import (
 var (
 	ErrModeUnspecified     = errors.New("at least one of -enduser or -maintainer must be specified")
 	ErrNoProviderSpecified = errors.New("no provider was specified")
+	ErrMinServersFormat    = errors.New("minimum servers format is not valid")
 )
 
 type UpdaterLogger interface {
@@ -35,7 +36,7 @@ type UpdaterLogger interface {
 func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) error {
 	options := settings.Updater{}
 	var endUserMode, maintainerMode, updateAll bool
-	var csvProviders string
+	var csvProviders, csvMinServers string
 	flagSet := flag.NewFlagSet("update", flag.ExitOnError)
 	flagSet.BoolVar(&endUserMode, "enduser", false, "Write results to /gluetun/servers.json (for end users)")
 	flagSet.BoolVar(&maintainerMode, "maintainer", false,
@@ -44,8 +45,12 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 	const defaultMinRatio = 0.8
 	flagSet.Float64Var(&options.MinRatio, "minratio", defaultMinRatio,
 		"Minimum ratio of servers to find for the update to succeed")
+	flagSet.StringVar(&csvMinServers, "minservers", "",
+		"CSV string of provider:count minimum numbers of servers to find, taking precedence over -minratio")
 	flagSet.BoolVar(&updateAll, "all", false, "Update servers for all VPN providers")
 	flagSet.StringVar(&csvProviders, "providers", "", "CSV string of VPN providers to update server data for")
+	options.ProtonEmail = flagSet.String("proton-email", "", "Proton account email to fetch ProtonVPN servers")
+	options.ProtonPassword = flagSet.String("proton-password", "", "Proton account password")
 	if err := flagSet.Parse(args); err != nil {
 		return err
 	}
@@ -63,14 +68,20 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 		options.Providers = strings.Split(csvProviders, ",")
 	}
 
+	var err error
+	options.MinServers, err = parseMinServers(csvMinServers)
+	if err != nil {
+		return fmt.Errorf("parsing minimum servers: %w", err)
+	}
+
 	options.SetDefaults(options.Providers[0])
 
-	err := options.Validate()
+	err = options.Validate()
 	if err != nil {
 		return fmt.Errorf("options validation failed: %w", err)
 	}
 
-	storage, err := storage.New(logger, constants.ServersData)
+	storage, err := newStorage(logger, constants.DefaultDataDirectory)
 	if err != nil {
 		return fmt.Errorf("creating servers storage: %w", err)
 	}
@@ -83,10 +94,13 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 	openvpnFileExtractor := extract.New()
 
 	providers := provider.NewProviders(storage, time.Now, logger, httpClient,
-		unzipper, parallelResolver, ipFetcher, openvpnFileExtractor)
+		unzipper, parallelResolver, ipFetcher, openvpnFileExtractor,
+		*options.ProtonEmail, *options.ProtonPassword,
+		constants.DefaultDataDirectory, constants.DefaultRuntimeDirectory)
 
 	updater := updater.New(httpClient, storage, providers, logger)
-	err = updater.UpdateServers(ctx, options.Providers, options.MinRatio)
+	err = updater.UpdateServers(ctx, options.Providers,
+		options.MinRatio, options.MinServers)
 	if err != nil {
 		return fmt.Errorf("updating server information: %w", err)
 	}
@@ -100,3 +114,28 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 
 	return nil
 }
+
+// parseMinServers parses a CSV string such as "nordvpn:100,mullvad:0"
+// into a map of provider name to minimum number of servers.
+func parseMinServers(csv string) (minServers map[string]uint, err error) {
+	if csv == "" {
+		return nil, nil
+	}
+
+	values := strings.Split(csv, ",")
+	minServers = make(map[string]uint, len(values))
+	for _, value := range values {
+		provider, countString, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be in the form provider:count",
+				ErrMinServersFormat, value)
+		}
+		const base, bitSize = 10, 32
+		count, err := strconv.ParseUint(countString, base, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", provider, err)
+		}
+		minServers[provider] = uint(count)
+	}
+	return minServers, nil
+}
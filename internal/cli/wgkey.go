@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/provider/mullvad"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var (
+	ErrAccountUnspecified    = errors.New("account number was not specified")
+	ErrPermissionsNotValid   = errors.New("file permissions are not valid")
+	ErrSubcommandUnknown     = errors.New("subcommand is unknown")
+	ErrSubcommandUnspecified = errors.New("subcommand was not specified")
+)
+
+// WireguardKey runs Wireguard key subcommands. The only subcommand
+// is currently 'rotate', which generates a new Wireguard key pair,
+// registers its public key with the VPN provider if the provider
+// offers an API to do so, and prints the resulting configuration.
+func (c *CLI) WireguardKey(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: it can be 'rotate'", ErrSubcommandUnspecified)
+	}
+
+	switch args[0] {
+	case "rotate":
+		return c.wireguardKeyRotate(ctx, args[1:])
+	default:
+		return fmt.Errorf("%w: %s", ErrSubcommandUnknown, args[0])
+	}
+}
+
+func (c *CLI) wireguardKeyRotate(ctx context.Context, args []string) error {
+	var provider, account, output, permissions string
+	flagSet := flag.NewFlagSet("wgkey rotate", flag.ExitOnError)
+	flagSet.StringVar(&provider, "provider", "", "VPN provider to register the public key with, "+
+		"only 'mullvad' is supported for registration")
+	flagSet.StringVar(&account, "account", "", "Account number to register the public key with")
+	flagSet.StringVar(&output, "output", "", "Optional env file path to write the configuration to")
+	flagSet.StringVar(&permissions, "permissions", "0600", "Octal permission bits of the output file")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	outputPermissions, err := parseFilePermissions(permissions)
+	if err != nil {
+		return err
+	}
+
+	provider = strings.ToLower(provider)
+	if provider != "" && !isValidProvider(provider) {
+		return fmt.Errorf("%w: %s", ErrProviderNotValid, provider)
+	}
+
+	privateKey, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return fmt.Errorf("generating private key: %w", err)
+	}
+	publicKey := privateKey.PublicKey().String()
+
+	variables := []variable{
+		{key: "VPN_TYPE", value: vpn.Wireguard},
+		{key: "WIREGUARD_PRIVATE_KEY", value: privateKey.String()},
+	}
+	if provider != "" {
+		variables = append([]variable{{key: "VPN_SERVICE_PROVIDER", value: provider}},
+			variables...)
+	}
+
+	switch provider {
+	case providers.Mullvad:
+		if account == "" {
+			return fmt.Errorf("%w", ErrAccountUnspecified)
+		}
+		const clientTimeout = 10 * time.Second
+		client := &http.Client{Timeout: clientTimeout}
+		device, err := mullvad.RegisterDevice(ctx, client, account, publicKey)
+		if err != nil {
+			return fmt.Errorf("registering public key: %w", err)
+		}
+		addresses := device.Addresses()
+		addressStrings := make([]string, len(addresses))
+		for i, address := range addresses {
+			addressStrings[i] = address.String()
+		}
+		variables = append(variables, variable{
+			key:   "WIREGUARD_ADDRESSES",
+			value: strings.Join(addressStrings, ","),
+		})
+		fmt.Fprintln(os.Stderr, "Public key "+publicKey+" registered as device "+device.Name)
+	default:
+		fmt.Fprintln(os.Stderr, "Public key "+publicKey+" must be uploaded on your "+
+			"VPN provider website to obtain your Wireguard addresses")
+	}
+
+	envFile := formatEnvFile(variables)
+	fmt.Print(envFile)
+
+	if output != "" {
+		err = writeToFile(output, envFile, outputPermissions)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseFilePermissions(s string) (permissions fs.FileMode, err error) {
+	const base, bitSize = 8, 32
+	value, err := strconv.ParseUint(s, base, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrPermissionsNotValid, err)
+	} else if value > uint64(fs.ModePerm) {
+		return 0, fmt.Errorf("%w: %s must be at most %04o",
+			ErrPermissionsNotValid, s, uint32(fs.ModePerm))
+	}
+	return fs.FileMode(value), nil
+}
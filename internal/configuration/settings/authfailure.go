@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// AuthFailure contains settings to configure the behavior
+// of the VPN loop when the VPN server rejects the credentials.
+type AuthFailure struct {
+	// Policy is the action to take after an authentication
+	// failure, and can be:
+	// - "rotate" to reconnect to a different server
+	// - "retry" to reconnect to the same server
+	// - "exit" to exit the program after Attempts
+	// consecutive authentication failures.
+	// It defaults to "rotate" and cannot be the empty
+	// string in the internal state.
+	Policy string
+	// Attempts is the number of consecutive authentication
+	// failures after which the program exits, for the "exit"
+	// policy. It defaults to 3 and cannot be zero in the
+	// internal state.
+	Attempts uint8
+}
+
+func (a AuthFailure) validate() (err error) {
+	validPolicies := []string{"rotate", "retry", "exit"}
+	if !helpers.IsOneOf(a.Policy, validPolicies...) {
+		return fmt.Errorf("%w: %q and can only be one of %s",
+			ErrAuthFailurePolicyNotValid, a.Policy, strings.Join(validPolicies, ", "))
+	}
+	return nil
+}
+
+func (a *AuthFailure) copy() (copied AuthFailure) {
+	return AuthFailure{
+		Policy:   a.Policy,
+		Attempts: a.Attempts,
+	}
+}
+
+func (a *AuthFailure) mergeWith(other AuthFailure) {
+	a.Policy = helpers.MergeWithString(a.Policy, other.Policy)
+	a.Attempts = helpers.MergeWithNumber(a.Attempts, other.Attempts)
+}
+
+func (a *AuthFailure) overrideWith(other AuthFailure) {
+	a.Policy = helpers.OverrideWithString(a.Policy, other.Policy)
+	a.Attempts = helpers.OverrideWithNumber(a.Attempts, other.Attempts)
+}
+
+func (a *AuthFailure) setDefaults() {
+	a.Policy = helpers.DefaultString(a.Policy, "rotate")
+	const defaultAttempts = 3
+	a.Attempts = helpers.DefaultNumber(a.Attempts, defaultAttempts)
+}
+
+func (a AuthFailure) String() string {
+	return a.toLinesNode().String()
+}
+
+func (a AuthFailure) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Authentication failure settings:")
+	node.Appendf("Policy: %s", a.Policy)
+	if a.Policy == "exit" {
+		node.Appendf("Attempts before exiting: %d", a.Attempts)
+	}
+	return node
+}
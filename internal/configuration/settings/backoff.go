@@ -0,0 +1,101 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Backoff contains settings to configure the delay
+// between VPN reconnection attempts.
+type Backoff struct {
+	// Initial is the delay before the first reconnection attempt,
+	// and the delay the backoff is reset to once connected.
+	// It cannot be zero in the internal state, and defaults to 15s.
+	Initial time.Duration
+	// Multiplier is the factor the delay is multiplied by after
+	// each failed reconnection attempt. It must be at least 1,
+	// and defaults to 2.
+	Multiplier float64
+	// Maximum is the maximum delay between two reconnection
+	// attempts. It can be set to 0 for no maximum, and cannot
+	// be nil in the internal state.
+	Maximum *time.Duration
+	// Jitter is the fraction of the delay by which the delay is
+	// randomly increased or decreased, to avoid reconnecting at
+	// regular intervals. It must be between 0 and 1, and cannot
+	// be nil in the internal state.
+	Jitter *float64
+}
+
+func (b Backoff) validate() (err error) {
+	if b.Multiplier < 1 {
+		return fmt.Errorf("%w: %g must be at least 1",
+			ErrBackoffMultiplierTooSmall, b.Multiplier)
+	}
+
+	if *b.Maximum != 0 && *b.Maximum < b.Initial {
+		return fmt.Errorf("%w: %s must be at least the initial delay %s",
+			ErrBackoffMaximumTooSmall, *b.Maximum, b.Initial)
+	}
+
+	if *b.Jitter < 0 || *b.Jitter > 1 {
+		return fmt.Errorf("%w: %g must be between 0 and 1",
+			ErrBackoffJitterNotValid, *b.Jitter)
+	}
+
+	return nil
+}
+
+func (b *Backoff) copy() (copied Backoff) {
+	return Backoff{
+		Initial:    b.Initial,
+		Multiplier: b.Multiplier,
+		Maximum:    helpers.CopyPointer(b.Maximum),
+		Jitter:     helpers.CopyPointer(b.Jitter),
+	}
+}
+
+func (b *Backoff) mergeWith(other Backoff) {
+	b.Initial = helpers.MergeWithNumber(b.Initial, other.Initial)
+	b.Multiplier = helpers.MergeWithNumber(b.Multiplier, other.Multiplier)
+	b.Maximum = helpers.MergeWithPointer(b.Maximum, other.Maximum)
+	b.Jitter = helpers.MergeWithPointer(b.Jitter, other.Jitter)
+}
+
+func (b *Backoff) overrideWith(other Backoff) {
+	b.Initial = helpers.OverrideWithNumber(b.Initial, other.Initial)
+	b.Multiplier = helpers.OverrideWithNumber(b.Multiplier, other.Multiplier)
+	b.Maximum = helpers.OverrideWithPointer(b.Maximum, other.Maximum)
+	b.Jitter = helpers.OverrideWithPointer(b.Jitter, other.Jitter)
+}
+
+func (b *Backoff) setDefaults() {
+	const defaultInitial = 15 * time.Second
+	b.Initial = helpers.DefaultNumber(b.Initial, defaultInitial)
+	const defaultMultiplier = 2
+	b.Multiplier = helpers.DefaultNumber(b.Multiplier, defaultMultiplier)
+	b.Maximum = helpers.DefaultPointer(b.Maximum, 0)
+	b.Jitter = helpers.DefaultPointer(b.Jitter, 0)
+}
+
+func (b Backoff) String() string {
+	return b.toLinesNode().String()
+}
+
+func (b Backoff) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Reconnection backoff settings:")
+	node.Appendf("Initial delay: %s", b.Initial)
+	node.Appendf("Multiplier: %g", b.Multiplier)
+	maximum := "none"
+	if *b.Maximum > 0 {
+		maximum = b.Maximum.String()
+	}
+	node.Appendf("Maximum delay: %s", maximum)
+	if *b.Jitter > 0 {
+		node.Appendf("Jitter: %g%%", *b.Jitter*100) //nolint:gomnd
+	}
+	return node
+}
@@ -0,0 +1,67 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Diagnostics contains settings for the pre-flight diagnostics
+// report printed at startup, before the firewall is enabled.
+type Diagnostics struct {
+	// Enabled is true to run the pre-flight diagnostics at startup.
+	// It defaults to false since the DNS egress check sends a plaintext
+	// DNS query outside the VPN tunnel. It cannot be nil in the
+	// internal state.
+	Enabled *bool
+	// DNSServer is the plaintext DNS server address used to check
+	// DNS egress. It defaults to 1.1.1.1:53 and cannot be nil in the
+	// internal state.
+	DNSServer *netip.AddrPort
+}
+
+func (d Diagnostics) validate() (err error) {
+	if !d.DNSServer.IsValid() || d.DNSServer.Port() == 0 {
+		return fmt.Errorf("%w: %s", ErrDiagnosticsDNSServerNotValid, d.DNSServer)
+	}
+	return nil
+}
+
+func (d *Diagnostics) copy() (copied Diagnostics) {
+	return Diagnostics{
+		Enabled:   helpers.CopyPointer(d.Enabled),
+		DNSServer: helpers.CopyPointer(d.DNSServer),
+	}
+}
+
+func (d *Diagnostics) mergeWith(other Diagnostics) {
+	d.Enabled = helpers.MergeWithPointer(d.Enabled, other.Enabled)
+	d.DNSServer = helpers.MergeWithPointer(d.DNSServer, other.DNSServer)
+}
+
+func (d *Diagnostics) overrideWith(other Diagnostics) {
+	d.Enabled = helpers.OverrideWithPointer(d.Enabled, other.Enabled)
+	d.DNSServer = helpers.OverrideWithPointer(d.DNSServer, other.DNSServer)
+}
+
+func (d *Diagnostics) setDefaults() {
+	d.Enabled = helpers.DefaultPointer(d.Enabled, false)
+	defaultDNSServer := netip.AddrPortFrom(netip.AddrFrom4([4]byte{1, 1, 1, 1}), 53) //nolint:gomnd
+	d.DNSServer = helpers.DefaultPointer(d.DNSServer, defaultDNSServer)
+}
+
+func (d Diagnostics) String() string {
+	return d.toLinesNode().String()
+}
+
+func (d Diagnostics) toLinesNode() (node *gotree.Node) {
+	if !*d.Enabled {
+		return nil
+	}
+
+	node = gotree.New("Startup diagnostics settings:")
+	node.Appendf("DNS egress check server: %s", d.DNSServer)
+	return node
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/qdm12/dns/pkg/blacklist"
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
@@ -20,22 +22,38 @@ type DNSBlacklist struct {
 	AddBlockedHosts      []string
 	AddBlockedIPs        []netip.Addr
 	AddBlockedIPPrefixes []netip.Prefix
+	// BlocklistSources are HTTP(S) URLs or file paths of
+	// custom blocklists, each in the plain hostnames list,
+	// hosts file or basic AdBlock format.
+	BlocklistSources []string
+	// RebindingProtection is true if private IP addresses
+	// should be removed from the answers received from the
+	// upstream servers, to protect against DNS rebinding attacks.
+	// It cannot be nil in the internal state.
+	RebindingProtection *bool
+	// RebindingAllowedHosts are hosts, including their
+	// subdomains, which are allowed to resolve to private
+	// IP addresses when the rebinding protection is enabled.
+	RebindingAllowedHosts []string
 }
 
 func (b *DNSBlacklist) setDefaults() {
 	b.BlockMalicious = helpers.DefaultPointer(b.BlockMalicious, true)
 	b.BlockAds = helpers.DefaultPointer(b.BlockAds, false)
 	b.BlockSurveillance = helpers.DefaultPointer(b.BlockSurveillance, true)
+	b.RebindingProtection = helpers.DefaultPointer(b.RebindingProtection, true)
 }
 
 var hostRegex = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9_][a-zA-Z0-9\-_]{0,61}[a-zA-Z0-9_])(\.([a-zA-Z0-9]|[a-zA-Z0-9_][a-zA-Z0-9\-_]{0,61}[a-zA-Z0-9]))*$`) //nolint:lll
 
 var (
-	ErrAllowedHostNotValid = errors.New("allowed host is not valid")
-	ErrBlockedHostNotValid = errors.New("blocked host is not valid")
+	ErrAllowedHostNotValid          = errors.New("allowed host is not valid")
+	ErrBlockedHostNotValid          = errors.New("blocked host is not valid")
+	ErrBlocklistSourceURL           = errors.New("blocklist source URL is not valid")
+	ErrRebindingAllowedHostNotValid = errors.New("rebinding allowed host is not valid")
 )
 
-func (b DNSBlacklist) validate() (err error) {
+func (b DNSBlacklist) Validate() (err error) {
 	for _, host := range b.AllowedHosts {
 		if !hostRegex.MatchString(host) {
 			return fmt.Errorf("%w: %s", ErrAllowedHostNotValid, host)
@@ -48,18 +66,39 @@ func (b DNSBlacklist) validate() (err error) {
 		}
 	}
 
+	for _, host := range b.RebindingAllowedHosts {
+		if !hostRegex.MatchString(host) {
+			return fmt.Errorf("%w: %s", ErrRebindingAllowedHostNotValid, host)
+		}
+	}
+
+	for _, source := range b.BlocklistSources {
+		if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+			continue // file path
+		}
+		parsedURL, err := url.Parse(source)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrBlocklistSourceURL, err)
+		} else if parsedURL.Host == "" {
+			return fmt.Errorf("%w: %s has no host", ErrBlocklistSourceURL, source)
+		}
+	}
+
 	return nil
 }
 
 func (b DNSBlacklist) copy() (copied DNSBlacklist) {
 	return DNSBlacklist{
-		BlockMalicious:       helpers.CopyPointer(b.BlockMalicious),
-		BlockAds:             helpers.CopyPointer(b.BlockAds),
-		BlockSurveillance:    helpers.CopyPointer(b.BlockSurveillance),
-		AllowedHosts:         helpers.CopySlice(b.AllowedHosts),
-		AddBlockedHosts:      helpers.CopySlice(b.AddBlockedHosts),
-		AddBlockedIPs:        helpers.CopySlice(b.AddBlockedIPs),
-		AddBlockedIPPrefixes: helpers.CopySlice(b.AddBlockedIPPrefixes),
+		BlockMalicious:        helpers.CopyPointer(b.BlockMalicious),
+		BlockAds:              helpers.CopyPointer(b.BlockAds),
+		BlockSurveillance:     helpers.CopyPointer(b.BlockSurveillance),
+		AllowedHosts:          helpers.CopySlice(b.AllowedHosts),
+		AddBlockedHosts:       helpers.CopySlice(b.AddBlockedHosts),
+		AddBlockedIPs:         helpers.CopySlice(b.AddBlockedIPs),
+		AddBlockedIPPrefixes:  helpers.CopySlice(b.AddBlockedIPPrefixes),
+		BlocklistSources:      helpers.CopySlice(b.BlocklistSources),
+		RebindingProtection:   helpers.CopyPointer(b.RebindingProtection),
+		RebindingAllowedHosts: helpers.CopySlice(b.RebindingAllowedHosts),
 	}
 }
 
@@ -71,6 +110,9 @@ func (b *DNSBlacklist) mergeWith(other DNSBlacklist) {
 	b.AddBlockedHosts = helpers.MergeSlices(b.AddBlockedHosts, other.AddBlockedHosts)
 	b.AddBlockedIPs = helpers.MergeSlices(b.AddBlockedIPs, other.AddBlockedIPs)
 	b.AddBlockedIPPrefixes = helpers.MergeSlices(b.AddBlockedIPPrefixes, other.AddBlockedIPPrefixes)
+	b.BlocklistSources = helpers.MergeSlices(b.BlocklistSources, other.BlocklistSources)
+	b.RebindingProtection = helpers.MergeWithPointer(b.RebindingProtection, other.RebindingProtection)
+	b.RebindingAllowedHosts = helpers.MergeSlices(b.RebindingAllowedHosts, other.RebindingAllowedHosts)
 }
 
 func (b *DNSBlacklist) overrideWith(other DNSBlacklist) {
@@ -81,9 +123,17 @@ func (b *DNSBlacklist) overrideWith(other DNSBlacklist) {
 	b.AddBlockedHosts = helpers.OverrideWithSlice(b.AddBlockedHosts, other.AddBlockedHosts)
 	b.AddBlockedIPs = helpers.OverrideWithSlice(b.AddBlockedIPs, other.AddBlockedIPs)
 	b.AddBlockedIPPrefixes = helpers.OverrideWithSlice(b.AddBlockedIPPrefixes, other.AddBlockedIPPrefixes)
+	b.BlocklistSources = helpers.OverrideWithSlice(b.BlocklistSources, other.BlocklistSources)
+	b.RebindingProtection = helpers.OverrideWithPointer(b.RebindingProtection, other.RebindingProtection)
+	b.RebindingAllowedHosts = helpers.OverrideWithSlice(b.RebindingAllowedHosts, other.RebindingAllowedHosts)
 }
 
 func (b DNSBlacklist) ToBlacklistFormat() (settings blacklist.BuilderSettings, err error) {
+	blockedIPPrefixes := b.AddBlockedIPPrefixes
+	if *b.RebindingProtection {
+		blockedIPPrefixes = helpers.MergeSlices(blockedIPPrefixes, rebindingIPPrefixes())
+	}
+
 	return blacklist.BuilderSettings{
 		BlockMalicious:       *b.BlockMalicious,
 		BlockAds:             *b.BlockAds,
@@ -91,10 +141,31 @@ func (b DNSBlacklist) ToBlacklistFormat() (settings blacklist.BuilderSettings, e
 		AllowedHosts:         b.AllowedHosts,
 		AddBlockedHosts:      b.AddBlockedHosts,
 		AddBlockedIPs:        netipAddressesToNetaddrIPs(b.AddBlockedIPs),
-		AddBlockedIPPrefixes: netipPrefixesToNetaddrIPPrefixes(b.AddBlockedIPPrefixes),
+		AddBlockedIPPrefixes: netipPrefixesToNetaddrIPPrefixes(blockedIPPrefixes),
 	}, nil
 }
 
+// rebindingIPPrefixes returns the loopback, private and link local
+// IP networks, including their IPv4-mapped IPv6 forms, which are
+// removed from DNS answers when the rebinding protection is enabled.
+func rebindingIPPrefixes() (prefixes []netip.Prefix) {
+	return []netip.Prefix{
+		netip.MustParsePrefix("127.0.0.1/8"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("192.168.0.0/16"),
+		netip.MustParsePrefix("169.254.0.0/16"),
+		netip.MustParsePrefix("::1/128"),
+		netip.MustParsePrefix("fc00::/7"),
+		netip.MustParsePrefix("fe80::/10"),
+		netip.MustParsePrefix("::ffff:7f00:1/104"),
+		netip.MustParsePrefix("::ffff:a00:0/104"),
+		netip.MustParsePrefix("::ffff:a9fe:0/112"),
+		netip.MustParsePrefix("::ffff:ac10:0/108"),
+		netip.MustParsePrefix("::ffff:c0a8:0/112"),
+	}
+}
+
 func (b DNSBlacklist) String() string {
 	return b.toLinesNode().String()
 }
@@ -134,5 +205,21 @@ func (b DNSBlacklist) toLinesNode() (node *gotree.Node) {
 		}
 	}
 
+	if len(b.BlocklistSources) > 0 {
+		sourcesNode := node.Appendf("Blocklist sources:")
+		for _, source := range b.BlocklistSources {
+			sourcesNode.Appendf(source)
+		}
+	}
+
+	rebindingNode := node.Appendf("Rebinding protection: %s",
+		helpers.BoolPtrToYesNo(b.RebindingProtection))
+	if *b.RebindingProtection && len(b.RebindingAllowedHosts) > 0 {
+		allowedHostsNode := rebindingNode.Appendf("Allowed hosts:")
+		for _, host := range b.RebindingAllowedHosts {
+			allowedHostsNode.Appendf(host)
+		}
+	}
+
 	return node
 }
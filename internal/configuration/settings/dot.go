@@ -21,6 +21,12 @@ type DoT struct {
 	// It defaults to 24h and cannot be nil in
 	// the internal state.
 	UpdatePeriod *time.Duration
+	// UpstreamCheckPeriod is the period to health check
+	// each DoT upstream provider, when more than one is set.
+	// Unhealthy upstreams are removed from Unbound until they
+	// recover. It can be set to 0 to disable the health checks.
+	// It defaults to 1m and cannot be nil in the internal state.
+	UpstreamCheckPeriod *time.Duration
 	// Unbound contains settings to configure Unbound.
 	Unbound Unbound
 	// Blacklist contains settings to configure the filter
@@ -29,7 +35,8 @@ type DoT struct {
 }
 
 var (
-	ErrDoTUpdatePeriodTooShort = errors.New("update period is too short")
+	ErrDoTUpdatePeriodTooShort        = errors.New("update period is too short")
+	ErrDoTUpstreamCheckPeriodTooShort = errors.New("upstream check period is too short")
 )
 
 func (d DoT) validate() (err error) {
@@ -39,12 +46,18 @@ func (d DoT) validate() (err error) {
 			ErrDoTUpdatePeriodTooShort, *d.UpdatePeriod, minUpdatePeriod)
 	}
 
+	const minUpstreamCheckPeriod = 10 * time.Second
+	if *d.UpstreamCheckPeriod != 0 && *d.UpstreamCheckPeriod < minUpstreamCheckPeriod {
+		return fmt.Errorf("%w: %s must be bigger than %s",
+			ErrDoTUpstreamCheckPeriodTooShort, *d.UpstreamCheckPeriod, minUpstreamCheckPeriod)
+	}
+
 	err = d.Unbound.validate()
 	if err != nil {
 		return err
 	}
 
-	err = d.Blacklist.validate()
+	err = d.Blacklist.Validate()
 	if err != nil {
 		return err
 	}
@@ -54,10 +67,11 @@ func (d DoT) validate() (err error) {
 
 func (d *DoT) copy() (copied DoT) {
 	return DoT{
-		Enabled:      helpers.CopyPointer(d.Enabled),
-		UpdatePeriod: helpers.CopyPointer(d.UpdatePeriod),
-		Unbound:      d.Unbound.copy(),
-		Blacklist:    d.Blacklist.copy(),
+		Enabled:             helpers.CopyPointer(d.Enabled),
+		UpdatePeriod:        helpers.CopyPointer(d.UpdatePeriod),
+		UpstreamCheckPeriod: helpers.CopyPointer(d.UpstreamCheckPeriod),
+		Unbound:             d.Unbound.copy(),
+		Blacklist:           d.Blacklist.copy(),
 	}
 }
 
@@ -66,6 +80,7 @@ func (d *DoT) copy() (copied DoT) {
 func (d *DoT) mergeWith(other DoT) {
 	d.Enabled = helpers.MergeWithPointer(d.Enabled, other.Enabled)
 	d.UpdatePeriod = helpers.MergeWithPointer(d.UpdatePeriod, other.UpdatePeriod)
+	d.UpstreamCheckPeriod = helpers.MergeWithPointer(d.UpstreamCheckPeriod, other.UpstreamCheckPeriod)
 	d.Unbound.mergeWith(other.Unbound)
 	d.Blacklist.mergeWith(other.Blacklist)
 }
@@ -76,6 +91,7 @@ func (d *DoT) mergeWith(other DoT) {
 func (d *DoT) overrideWith(other DoT) {
 	d.Enabled = helpers.OverrideWithPointer(d.Enabled, other.Enabled)
 	d.UpdatePeriod = helpers.OverrideWithPointer(d.UpdatePeriod, other.UpdatePeriod)
+	d.UpstreamCheckPeriod = helpers.OverrideWithPointer(d.UpstreamCheckPeriod, other.UpstreamCheckPeriod)
 	d.Unbound.overrideWith(other.Unbound)
 	d.Blacklist.overrideWith(other.Blacklist)
 }
@@ -84,6 +100,8 @@ func (d *DoT) setDefaults() {
 	d.Enabled = helpers.DefaultPointer(d.Enabled, true)
 	const defaultUpdatePeriod = 24 * time.Hour
 	d.UpdatePeriod = helpers.DefaultPointer(d.UpdatePeriod, defaultUpdatePeriod)
+	const defaultUpstreamCheckPeriod = time.Minute
+	d.UpstreamCheckPeriod = helpers.DefaultPointer(d.UpstreamCheckPeriod, defaultUpstreamCheckPeriod)
 	d.Unbound.setDefaults()
 	d.Blacklist.setDefaults()
 }
@@ -106,6 +124,12 @@ func (d DoT) toLinesNode() (node *gotree.Node) {
 	}
 	node.Appendf("Update period: %s", update)
 
+	upstreamCheck := "disabled"
+	if *d.UpstreamCheckPeriod > 0 {
+		upstreamCheck = "every " + d.UpstreamCheckPeriod.String()
+	}
+	node.Appendf("Upstream health check period: %s", upstreamCheck)
+
 	node.AppendNode(d.Unbound.toLinesNode())
 	node.AppendNode(d.Blacklist.toLinesNode())
 
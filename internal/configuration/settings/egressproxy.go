@@ -0,0 +1,106 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gotree"
+)
+
+// EgressProxy contains settings of a SOCKS5 proxy to reach the
+// VPN server through, for hosts only able to reach the internet
+// through a SOCKS gateway. OpenVPN uses its socks-proxy option,
+// and Wireguard relays its UDP packets through the proxy using
+// a UDP association, or its TLS connection for TCP connections.
+type EgressProxy struct {
+	// Address is the IP address and port of the SOCKS5 proxy.
+	// It has to be an IP address since DNS is not available before
+	// the VPN is connected. It cannot be nil in the internal state,
+	// and the zero value disables the egress proxy.
+	Address *netip.AddrPort
+	// Username is the username to authenticate with the proxy.
+	// It cannot be nil in the internal state, and can be the
+	// empty string to not authenticate.
+	Username *string
+	// Password is the password to authenticate with the proxy.
+	// It cannot be nil in the internal state.
+	Password *string
+}
+
+func (e EgressProxy) validate(vpnType string, torChain, udp2raw bool) (err error) {
+	if !e.Enabled() {
+		return nil
+	}
+
+	switch {
+	case vpnType == vpn.Tailscale, vpnType == vpn.Tor:
+		return fmt.Errorf("%w: for VPN type %s", ErrEgressProxyNotSupported, vpnType)
+	case torChain:
+		return fmt.Errorf("%w: with Tor chaining", ErrEgressProxyNotSupported)
+	case udp2raw:
+		return fmt.Errorf("%w: with udp2raw", ErrEgressProxyNotSupported)
+	case e.Address.Port() == 0:
+		return fmt.Errorf("%w", ErrEgressProxyPortMissing)
+	}
+
+	const maxCredentialLength = 255
+	if len(*e.Username) > maxCredentialLength {
+		return fmt.Errorf("%w: username length %d is larger than %d",
+			ErrEgressProxyCredentialsNotValid, len(*e.Username), maxCredentialLength)
+	} else if len(*e.Password) > maxCredentialLength {
+		return fmt.Errorf("%w: password length %d is larger than %d",
+			ErrEgressProxyCredentialsNotValid, len(*e.Password), maxCredentialLength)
+	} else if *e.Username == "" && *e.Password != "" {
+		return fmt.Errorf("%w: password is set without a username",
+			ErrEgressProxyCredentialsNotValid)
+	}
+
+	return nil
+}
+
+// Enabled returns true if the egress proxy address is set.
+func (e EgressProxy) Enabled() bool {
+	return e.Address.IsValid()
+}
+
+func (e *EgressProxy) copy() (copied EgressProxy) {
+	return EgressProxy{
+		Address:  helpers.CopyPointer(e.Address),
+		Username: helpers.CopyPointer(e.Username),
+		Password: helpers.CopyPointer(e.Password),
+	}
+}
+
+func (e *EgressProxy) mergeWith(other EgressProxy) {
+	e.Address = helpers.MergeWithPointer(e.Address, other.Address)
+	e.Username = helpers.MergeWithPointer(e.Username, other.Username)
+	e.Password = helpers.MergeWithPointer(e.Password, other.Password)
+}
+
+func (e *EgressProxy) overrideWith(other EgressProxy) {
+	e.Address = helpers.OverrideWithPointer(e.Address, other.Address)
+	e.Username = helpers.OverrideWithPointer(e.Username, other.Username)
+	e.Password = helpers.OverrideWithPointer(e.Password, other.Password)
+}
+
+func (e *EgressProxy) setDefaults() {
+	e.Address = helpers.DefaultPointer(e.Address, netip.AddrPort{})
+	e.Username = helpers.DefaultPointer(e.Username, "")
+	e.Password = helpers.DefaultPointer(e.Password, "")
+}
+
+func (e EgressProxy) String() string {
+	return e.toLinesNode().String()
+}
+
+func (e EgressProxy) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Egress SOCKS5 proxy settings:")
+	node.Appendf("Address: %s", *e.Address)
+	if *e.Username != "" {
+		node.Appendf("Username: %s", *e.Username)
+		node.Appendf("Password: %s", helpers.ObfuscatePassword(*e.Password))
+	}
+	return node
+}
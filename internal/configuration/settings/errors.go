@@ -3,47 +3,136 @@ package settings
 import "errors"
 
 var (
-	ErrCityNotValid                    = errors.New("the city specified is not valid")
-	ErrControlServerPrivilegedPort     = errors.New("cannot use privileged port without running as root")
-	ErrCountryNotValid                 = errors.New("the country specified is not valid")
-	ErrFilepathMissing                 = errors.New("filepath is missing")
-	ErrFirewallZeroPort                = errors.New("cannot have a zero port to block")
-	ErrHostnameNotValid                = errors.New("the hostname specified is not valid")
-	ErrISPNotValid                     = errors.New("the ISP specified is not valid")
-	ErrMinRatioNotValid                = errors.New("minimum ratio is not valid")
-	ErrMissingValue                    = errors.New("missing value")
-	ErrNameNotValid                    = errors.New("the server name specified is not valid")
-	ErrOpenVPNClientKeyMissing         = errors.New("client key is missing")
-	ErrOpenVPNCustomPortNotAllowed     = errors.New("custom endpoint port is not allowed")
-	ErrOpenVPNEncryptionPresetNotValid = errors.New("PIA encryption preset is not valid")
-	ErrOpenVPNInterfaceNotValid        = errors.New("interface name is not valid")
-	ErrOpenVPNKeyPassphraseIsEmpty     = errors.New("key passphrase is empty")
-	ErrOpenVPNMSSFixIsTooHigh          = errors.New("mssfix option value is too high")
-	ErrOpenVPNPasswordIsEmpty          = errors.New("password is empty")
-	ErrOpenVPNTCPNotSupported          = errors.New("TCP protocol is not supported")
-	ErrOpenVPNUserIsEmpty              = errors.New("user is empty")
-	ErrOpenVPNVerbosityIsOutOfBounds   = errors.New("verbosity value is out of bounds")
-	ErrOpenVPNVersionIsNotValid        = errors.New("version is not valid")
-	ErrPortForwardingEnabled           = errors.New("port forwarding cannot be enabled")
-	ErrPublicIPPeriodTooShort          = errors.New("public IP address check period is too short")
-	ErrRegionNotValid                  = errors.New("the region specified is not valid")
-	ErrServerAddressNotValid           = errors.New("server listening address is not valid")
-	ErrSystemPGIDNotValid              = errors.New("process group id is not valid")
-	ErrSystemPUIDNotValid              = errors.New("process user id is not valid")
-	ErrSystemTimezoneNotValid          = errors.New("timezone is not valid")
-	ErrUpdaterPeriodTooSmall           = errors.New("VPN server data updater period is too small")
-	ErrVPNProviderNameNotValid         = errors.New("VPN provider name is not valid")
-	ErrVPNTypeNotValid                 = errors.New("VPN type is not valid")
-	ErrWireguardEndpointIPNotSet       = errors.New("endpoint IP is not set")
-	ErrWireguardEndpointPortNotAllowed = errors.New("endpoint port is not allowed")
-	ErrWireguardEndpointPortNotSet     = errors.New("endpoint port is not set")
-	ErrWireguardEndpointPortSet        = errors.New("endpoint port is set")
-	ErrWireguardInterfaceAddressNotSet = errors.New("interface address is not set")
-	ErrWireguardInterfaceAddressIPv6   = errors.New("interface address is IPv6 but IPv6 is not supported")
-	ErrWireguardInterfaceNotValid      = errors.New("interface name is not valid")
-	ErrWireguardPreSharedKeyNotSet     = errors.New("pre-shared key is not set")
-	ErrWireguardPrivateKeyNotSet       = errors.New("private key is not set")
-	ErrWireguardPublicKeyNotSet        = errors.New("public key is not set")
-	ErrWireguardPublicKeyNotValid      = errors.New("public key is not valid")
-	ErrWireguardImplementationNotValid = errors.New("implementation is not valid")
+	ErrAuthFailurePolicyNotValid           = errors.New("authentication failure policy is not valid")
+	ErrBackoffJitterNotValid               = errors.New("backoff jitter is not valid")
+	ErrBackoffMaximumTooSmall              = errors.New("backoff maximum delay is too small")
+	ErrBackoffMultiplierTooSmall           = errors.New("backoff multiplier is too small")
+	ErrCityNotValid                        = errors.New("the city specified is not valid")
+	ErrControlServerPrivilegedPort         = errors.New("cannot use privileged port without running as root")
+	ErrCountryNotValid                     = errors.New("the country specified is not valid")
+	ErrCredentialsCheckEnabled             = errors.New("credentials check cannot be enabled")
+	ErrDiagnosticsDNSServerNotValid        = errors.New("diagnostics DNS server address is not valid")
+	ErrEgressProxyCredentialsNotValid      = errors.New("credentials are not valid")
+	ErrEgressProxyNotSupported             = errors.New("egress proxy is not supported")
+	ErrEgressProxyPortMissing              = errors.New("port is missing")
+	ErrEventHookCommandNotSet              = errors.New("event hook command is not set")
+	ErrEventHookEventNotValid              = errors.New("event hook event is not valid")
+	ErrEventHookMaxConcurrentNotValid      = errors.New("event hooks maximum concurrent commands is not valid")
+	ErrEventHookTimeoutNotValid            = errors.New("event hooks timeout is not valid")
+	ErrFilePermissionsNotValid             = errors.New("file permissions are not valid")
+	ErrFilepathMissing                     = errors.New("filepath is missing")
+	ErrFirewallInterfaceNotValid           = errors.New("interface name is not valid")
+	ErrFirewallKillSwitchNotValid          = errors.New("kill switch mode is not valid")
+	ErrFirewallOutboundHostnameNotValid    = errors.New("outbound hostname is not valid")
+	ErrFirewallZeroPort                    = errors.New("cannot have a zero port to block")
+	ErrHTTPProxyDestinationNotValid        = errors.New("HTTP proxy destination is not valid")
+	ErrHTTPProxySOCKSNotSupported          = errors.New("provider SOCKS proxy is not supported")
+	ErrHTTPProxyZeroPort                   = errors.New("cannot have a zero HTTP proxy destination port")
+	ErrHealthRecoveryRatioNotValid         = errors.New("health latency recovery ratio is not valid")
+	ErrHealthTargetAddressMissing          = errors.New("health target address is missing")
+	ErrHealthTargetNetworkNotValid         = errors.New("health target network is not valid")
+	ErrHealthTargetTimeoutTooSmall         = errors.New("health target timeout is too small")
+	ErrHookFailurePolicyNotValid           = errors.New("hook failure policy is not valid")
+	ErrHostnameNotValid                    = errors.New("the hostname specified is not valid")
+	ErrISPNotValid                         = errors.New("the ISP specified is not valid")
+	ErrLANGatewayNotSupported              = errors.New("LAN gateway is not supported")
+	ErrLANGatewaySubnetNotValid            = errors.New("LAN gateway subnet is not valid")
+	ErrLANGatewaySubnetsNotSet             = errors.New("LAN gateway subnets are not set")
+	ErrLogFileMaxAgeNotValid               = errors.New("log file max age is not valid")
+	ErrMetricsAddressNotValid              = errors.New("metrics server address is not valid")
+	ErrMetricsFormatNotValid               = errors.New("metrics format is not valid")
+	ErrMetricsPeriodTooSmall               = errors.New("metrics period is too small")
+	ErrMinRatioNotValid                    = errors.New("minimum ratio is not valid")
+	ErrMissingValue                        = errors.New("missing value")
+	ErrNAT64IPv6NotSupported               = errors.New("IPv6 is not supported, which is required for NAT64")
+	ErrNameNotValid                        = errors.New("the server name specified is not valid")
+	ErrNotifyTelegramChatIDMissing         = errors.New("telegram chat ID is missing")
+	ErrNotifyURLNotValid                   = errors.New("notification URL is not valid")
+	ErrOpenVPNClientKeyMissing             = errors.New("client key is missing")
+	ErrOpenVPNCustomPortNotAllowed         = errors.New("custom endpoint port is not allowed")
+	ErrOpenVPNEncryptionPresetNotValid     = errors.New("PIA encryption preset is not valid")
+	ErrOpenVPNFragmentIsTooHigh            = errors.New("fragment option value is too high")
+	ErrOpenVPNInterfaceNotValid            = errors.New("interface name is not valid")
+	ErrOpenVPNKeyPassphraseIsEmpty         = errors.New("key passphrase is empty")
+	ErrOpenVPNMSSFixIsTooHigh              = errors.New("mssfix option value is too high")
+	ErrOpenVPNPasswordIsEmpty              = errors.New("password is empty")
+	ErrOpenVPNStaticKeyNotValid            = errors.New("static key is not valid")
+	ErrOpenVPNStaticKeysConflict           = errors.New("static key and tls-crypt-v2 client key cannot be both set")
+	ErrOpenVPNTCPNotSupported              = errors.New("TCP protocol is not supported")
+	ErrOpenVPNTLSCryptV2KeyNotValid        = errors.New("tls-crypt-v2 client key is not valid")
+	ErrOpenVPNTunMTUIsTooLow               = errors.New("tun-mtu option value is too low")
+	ErrOpenVPNUserIsEmpty                  = errors.New("user is empty")
+	ErrOpenVPNVerbosityIsOutOfBounds       = errors.New("verbosity value is out of bounds")
+	ErrOpenVPNVersionIsNotValid            = errors.New("version is not valid")
+	ErrOpenVPNVersionNotSupported          = errors.New("version is not supported by the VPN provider")
+	ErrPortForwardingDNATNotValid          = errors.New("port forwarding DNAT destination is not valid")
+	ErrPortForwardingEnabled               = errors.New("port forwarding cannot be enabled")
+	ErrPortForwardingPasswordEmpty         = errors.New("port forwarding password is empty")
+	ErrPortForwardingUserEmpty             = errors.New("port forwarding username is empty")
+	ErrPublicIPAPIURLNotValid              = errors.New("public IP API URL is not valid")
+	ErrPublicIPJitterNotValid              = errors.New("public IP address fetch jitter is not valid")
+	ErrPublicIPPeriodTooShort              = errors.New("public IP address check period is too short")
+	ErrPublicIPTimeoutTooShort             = errors.New("public IP address fetch timeout is too short")
+	ErrRegionNotValid                      = errors.New("the region specified is not valid")
+	ErrScheduleWindowNotValid              = errors.New("schedule window is not valid")
+	ErrSecondaryInterfaceConflict          = errors.New("interface name conflicts with the VPN interface")
+	ErrSecondaryNotSupported               = errors.New("secondary tunnel is not supported")
+	ErrSecondarySteeringNotSet             = errors.New("no destination port or source network is set")
+	ErrServerAddressNotValid               = errors.New("server listening address is not valid")
+	ErrServerIPNotSupported                = errors.New("pinning a server IP address is not supported")
+	ErrServerIPTargetIPBothSet             = errors.New("server IP address and target IP address cannot both be set")
+	ErrServersMergeStrategyNotValid        = errors.New("servers merge strategy is not valid")
+	ErrShadowsocksInstanceAddressDuplicate = errors.New("instance listening address is used more than once")
+	ErrShadowsocksInstanceAddressNotSet    = errors.New("instance listening address is not set")
+	ErrShadowsocksInstanceNameDuplicate    = errors.New("instance name is used more than once")
+	ErrShadowsocksInstanceNameNotValid     = errors.New("instance name is not valid")
+	ErrShadowsocksInstancePasswordNotSet   = errors.New("instance password is not set")
+	ErrShadowsocksPluginNotFound           = errors.New("plugin program is not found")
+	ErrShutdownTimeoutTooShort             = errors.New("shutdown timeout is too short")
+	ErrShutdownVPNTimeoutTooShort          = errors.New("VPN shutdown timeout is too short")
+	ErrSpeedTestURLNotValid                = errors.New("speed test URL is not valid")
+	ErrSyslogAddressNotValid               = errors.New("syslog server address is not valid")
+	ErrSyslogFacilityNotValid              = errors.New("syslog facility is not valid")
+	ErrSyslogProtocolNotValid              = errors.New("syslog protocol is not valid")
+	ErrSystemDataDirectoryNotValid         = errors.New("data directory is not valid")
+	ErrSystemPGIDNotValid                  = errors.New("process group id is not valid")
+	ErrSystemPUIDNotValid                  = errors.New("process user id is not valid")
+	ErrSystemResolvConfPathNotValid        = errors.New("resolv.conf path is not valid")
+	ErrSystemRuntimeDirectoryNotValid      = errors.New("runtime directory is not valid")
+	ErrSystemTimezoneNotValid              = errors.New("timezone is not valid")
+	ErrTailscaleAuthKeyNotSet              = errors.New("auth key is not set")
+	ErrTailscaleControlURLNotValid         = errors.New("control URL is not valid")
+	ErrTailscaleExitNodeNotSet             = errors.New("exit node is not set")
+	ErrTailscaleInterfaceNotValid          = errors.New("interface name is not valid")
+	ErrTorChainNotSupported                = errors.New("chaining through Tor is not supported")
+	ErrTorExitCountryNotValid              = errors.New("exit country is not valid")
+	ErrTorPortsConflict                    = errors.New("ports conflict")
+	ErrTracingEndpointNotValid             = errors.New("tracing endpoint is not valid")
+	ErrUDP2RawModeNotValid                 = errors.New("raw mode is not valid")
+	ErrUDP2RawNotSupported                 = errors.New("udp2raw is not supported")
+	ErrUDP2RawPasswordMissing              = errors.New("password is missing")
+	ErrUpdaterPeriodTooSmall               = errors.New("VPN server data updater period is too small")
+	ErrUpdaterProtonPasswordMissing        = errors.New("proton password is missing")
+	ErrVPNProviderNameNotValid             = errors.New("VPN provider name is not valid")
+	ErrVPNTypeNotValid                     = errors.New("VPN type is not valid")
+	ErrVersionUpdateCheckPeriodTooSmall    = errors.New("version update check period is too small")
+	ErrWireguardAccessTokenSet             = errors.New("access token is set")
+	ErrWireguardAccountNumberSet           = errors.New("account number is set")
+	ErrWireguardAllowedIPNotValid          = errors.New("allowed IP network is not valid")
+	ErrWireguardEndpointIPNotSet           = errors.New("endpoint IP is not set")
+	ErrWireguardEndpointPortNotAllowed     = errors.New("endpoint port is not allowed")
+	ErrWireguardEndpointPortNotSet         = errors.New("endpoint port is not set")
+	ErrWireguardEndpointPortSet            = errors.New("endpoint port is set")
+	ErrWireguardInterfaceAddressNotSet     = errors.New("interface address is not set")
+	ErrWireguardInterfaceAddressIPv6       = errors.New("interface address is IPv6 but IPv6 is not supported")
+	ErrWireguardInterfaceNotValid          = errors.New("interface name is not valid")
+	ErrWireguardPreSharedKeyNotSet         = errors.New("pre-shared key is not set")
+	ErrWireguardPrivateKeyNotSet           = errors.New("private key is not set")
+	ErrWireguardProtocolNotSupported       = errors.New("protocol is not supported")
+	ErrWireguardProtocolNotValid           = errors.New("protocol is not valid")
+	ErrWireguardPublicKeyNotSet            = errors.New("public key is not set")
+	ErrWireguardPublicKeyNotValid          = errors.New("public key is not valid")
+	ErrWireguardServerPeerAddressNotValid  = errors.New("peer address is not valid")
+	ErrWireguardServerPeerDuplicate        = errors.New("peer public key is duplicated")
+	ErrWireguardImplementationNotValid     = errors.New("implementation is not valid")
 )
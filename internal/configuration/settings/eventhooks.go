@@ -0,0 +1,112 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gotree"
+	"golang.org/x/exp/maps"
+)
+
+// EventHooks contains settings for commands run on
+// events such as the VPN connecting or a port being
+// forwarded, with the event details set in environment
+// variables.
+type EventHooks struct {
+	// Commands maps event types to the command to run when
+	// the event occurs, which is the path of an executable
+	// optionally followed by space separated arguments. The
+	// command is not run in a shell. Event types can be one
+	// of the values returned by models.HookEvents.
+	Commands map[string]string
+	// Timeout is the maximum duration a command can run for
+	// before being killed. It defaults to 30 seconds and
+	// cannot be zero in the internal state.
+	Timeout time.Duration
+	// MaxConcurrent is the maximum number of commands
+	// running at the same time. It defaults to 2 and
+	// cannot be zero in the internal state.
+	MaxConcurrent uint8
+}
+
+func (e EventHooks) validate() (err error) {
+	validEvents := models.HookEvents()
+	for event, command := range e.Commands {
+		if !helpers.IsOneOf(event, validEvents...) {
+			return fmt.Errorf("%w: %q can only be one of %s",
+				ErrEventHookEventNotValid, event, helpers.ChoicesOrString(validEvents))
+		}
+
+		argv := strings.Fields(command)
+		if len(argv) == 0 {
+			return fmt.Errorf("%w: for event %s", ErrEventHookCommandNotSet, event)
+		}
+		err = helpers.FileExists(argv[0])
+		if err != nil {
+			return fmt.Errorf("%s event hook: %w", event, err)
+		}
+	}
+
+	if e.Timeout <= 0 {
+		return fmt.Errorf("%w: %s must be positive",
+			ErrEventHookTimeoutNotValid, e.Timeout)
+	}
+
+	if e.MaxConcurrent == 0 {
+		return fmt.Errorf("%w: must be at least 1", ErrEventHookMaxConcurrentNotValid)
+	}
+
+	return nil
+}
+
+func (e *EventHooks) copy() (copied EventHooks) {
+	return EventHooks{
+		Commands:      helpers.CopyMap(e.Commands),
+		Timeout:       e.Timeout,
+		MaxConcurrent: e.MaxConcurrent,
+	}
+}
+
+func (e *EventHooks) mergeWith(other EventHooks) {
+	e.Commands = helpers.MergeMaps(e.Commands, other.Commands)
+	e.Timeout = helpers.MergeWithNumber(e.Timeout, other.Timeout)
+	e.MaxConcurrent = helpers.MergeWithNumber(e.MaxConcurrent, other.MaxConcurrent)
+}
+
+func (e *EventHooks) overrideWith(other EventHooks) {
+	e.Commands = helpers.OverrideWithMap(e.Commands, other.Commands)
+	e.Timeout = helpers.OverrideWithNumber(e.Timeout, other.Timeout)
+	e.MaxConcurrent = helpers.OverrideWithNumber(e.MaxConcurrent, other.MaxConcurrent)
+}
+
+func (e *EventHooks) setDefaults() {
+	const defaultTimeout = 30 * time.Second
+	e.Timeout = helpers.DefaultNumber(e.Timeout, defaultTimeout)
+	const defaultMaxConcurrent = 2
+	e.MaxConcurrent = helpers.DefaultNumber(e.MaxConcurrent, defaultMaxConcurrent)
+}
+
+func (e EventHooks) String() string {
+	return e.toLinesNode().String()
+}
+
+func (e EventHooks) toLinesNode() (node *gotree.Node) {
+	if len(e.Commands) == 0 {
+		return nil
+	}
+
+	node = gotree.New("Event hooks settings:")
+	commandsNode := node.Appendf("Commands:")
+	events := maps.Keys(e.Commands)
+	sort.Strings(events)
+	for _, event := range events {
+		commandsNode.Appendf("%s: %s", event, e.Commands[event])
+	}
+	node.Appendf("Timeout: %s", e.Timeout)
+	node.Appendf("Max concurrent commands: %d", e.MaxConcurrent)
+	return node
+}
@@ -3,6 +3,9 @@ package settings
 import (
 	"fmt"
 	"net/netip"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
@@ -13,8 +16,45 @@ type Firewall struct {
 	VPNInputPorts   []uint16
 	InputPorts      []uint16
 	OutboundSubnets []netip.Prefix
-	Enabled         *bool
-	Debug           *bool
+	// OutboundHostnames are hostnames resolved at start and
+	// periodically, whose IPv4 addresses are allowed and routed
+	// as outbound subnets.
+	OutboundHostnames []string
+	// OutboundHostnamesPeriod is the period to resolve the
+	// outbound hostnames again, and can be set to 0 to only
+	// resolve them at start. It defaults to 5 minutes and
+	// cannot be nil in the internal state.
+	OutboundHostnamesPeriod *time.Duration
+	// LocalNetworksPeriod is the period to check for local
+	// networks added after start, such as when the container
+	// is connected to another Docker network, which are then
+	// allowed and routed. It can be set to 0 to disable it,
+	// which is the default, and cannot be nil in the internal state.
+	LocalNetworksPeriod *time.Duration
+	Enabled             *bool
+	Debug               *bool
+	// PauseKillSwitch is the kill switch mode to use while
+	// the VPN is paused through the control server, and can be
+	// "allow_lan" to only allow traffic to local networks, or
+	// "block_all" to block all outbound traffic.
+	// It cannot be the empty string in the internal state.
+	PauseKillSwitch string
+	// MulticastDiscovery is true if multicast discovery
+	// protocols (mDNS and SSDP) are allowed to and from the
+	// local networks, such that services behind the firewall
+	// remain discoverable locally. It cannot be nil in the
+	// internal state.
+	MulticastDiscovery *bool
+	// Interfaces are the network interfaces considered for the
+	// default routes and local networks. It defaults to an empty
+	// slice, meaning all network interfaces are considered, and
+	// is useful on hosts with several network interfaces.
+	Interfaces []string
+	// RestoreHostRules is true if the iptables rules present
+	// before the firewall is enabled are restored when it is
+	// disabled, instead of leaving all chains flushed. It is
+	// useful in host mode, and cannot be nil in the internal state.
+	RestoreHostRules *bool
 }
 
 func (f Firewall) validate() (err error) {
@@ -26,9 +66,30 @@ func (f Firewall) validate() (err error) {
 		return fmt.Errorf("input ports: %w", ErrFirewallZeroPort)
 	}
 
+	for _, hostname := range f.OutboundHostnames {
+		if !hostRegex.MatchString(hostname) {
+			return fmt.Errorf("%w: %s", ErrFirewallOutboundHostnameNotValid, hostname)
+		}
+	}
+
+	if !helpers.IsOneOf(f.PauseKillSwitch, "allow_lan", "block_all") {
+		return fmt.Errorf("%w: %s", ErrFirewallKillSwitchNotValid, f.PauseKillSwitch)
+	}
+
+	for _, intf := range f.Interfaces {
+		if !regexpHostInterfaceName.MatchString(intf) {
+			return fmt.Errorf("%w: '%s' does not match regex '%s'",
+				ErrFirewallInterfaceNotValid, intf, regexpHostInterfaceName)
+		}
+	}
+
 	return nil
 }
 
+// regexpHostInterfaceName matches network interface names of a host,
+// which can contain dots and dashes, such as eth0.100 or br-lan.
+var regexpHostInterfaceName = regexp.MustCompile(`^[a-zA-Z0-9_.\-]{1,15}$`)
+
 func hasZeroPort(ports []uint16) (has bool) {
 	for _, port := range ports {
 		if port == 0 {
@@ -40,11 +101,18 @@ func hasZeroPort(ports []uint16) (has bool) {
 
 func (f *Firewall) copy() (copied Firewall) {
 	return Firewall{
-		VPNInputPorts:   helpers.CopySlice(f.VPNInputPorts),
-		InputPorts:      helpers.CopySlice(f.InputPorts),
-		OutboundSubnets: helpers.CopySlice(f.OutboundSubnets),
-		Enabled:         helpers.CopyPointer(f.Enabled),
-		Debug:           helpers.CopyPointer(f.Debug),
+		VPNInputPorts:           helpers.CopySlice(f.VPNInputPorts),
+		InputPorts:              helpers.CopySlice(f.InputPorts),
+		OutboundSubnets:         helpers.CopySlice(f.OutboundSubnets),
+		OutboundHostnames:       helpers.CopySlice(f.OutboundHostnames),
+		OutboundHostnamesPeriod: helpers.CopyPointer(f.OutboundHostnamesPeriod),
+		LocalNetworksPeriod:     helpers.CopyPointer(f.LocalNetworksPeriod),
+		Enabled:                 helpers.CopyPointer(f.Enabled),
+		Debug:                   helpers.CopyPointer(f.Debug),
+		PauseKillSwitch:         f.PauseKillSwitch,
+		MulticastDiscovery:      helpers.CopyPointer(f.MulticastDiscovery),
+		Interfaces:              helpers.CopySlice(f.Interfaces),
+		RestoreHostRules:        helpers.CopyPointer(f.RestoreHostRules),
 	}
 }
 
@@ -56,8 +124,15 @@ func (f *Firewall) mergeWith(other Firewall) {
 	f.VPNInputPorts = helpers.MergeSlices(f.VPNInputPorts, other.VPNInputPorts)
 	f.InputPorts = helpers.MergeSlices(f.InputPorts, other.InputPorts)
 	f.OutboundSubnets = helpers.MergeSlices(f.OutboundSubnets, other.OutboundSubnets)
+	f.OutboundHostnames = helpers.MergeSlices(f.OutboundHostnames, other.OutboundHostnames)
+	f.OutboundHostnamesPeriod = helpers.MergeWithPointer(f.OutboundHostnamesPeriod, other.OutboundHostnamesPeriod)
+	f.LocalNetworksPeriod = helpers.MergeWithPointer(f.LocalNetworksPeriod, other.LocalNetworksPeriod)
 	f.Enabled = helpers.MergeWithPointer(f.Enabled, other.Enabled)
 	f.Debug = helpers.MergeWithPointer(f.Debug, other.Debug)
+	f.PauseKillSwitch = helpers.MergeWithString(f.PauseKillSwitch, other.PauseKillSwitch)
+	f.MulticastDiscovery = helpers.MergeWithPointer(f.MulticastDiscovery, other.MulticastDiscovery)
+	f.Interfaces = helpers.MergeSlices(f.Interfaces, other.Interfaces)
+	f.RestoreHostRules = helpers.MergeWithPointer(f.RestoreHostRules, other.RestoreHostRules)
 }
 
 // overrideWith overrides fields of the receiver
@@ -67,13 +142,27 @@ func (f *Firewall) overrideWith(other Firewall) {
 	f.VPNInputPorts = helpers.OverrideWithSlice(f.VPNInputPorts, other.VPNInputPorts)
 	f.InputPorts = helpers.OverrideWithSlice(f.InputPorts, other.InputPorts)
 	f.OutboundSubnets = helpers.OverrideWithSlice(f.OutboundSubnets, other.OutboundSubnets)
+	f.OutboundHostnames = helpers.OverrideWithSlice(f.OutboundHostnames, other.OutboundHostnames)
+	f.OutboundHostnamesPeriod = helpers.OverrideWithPointer(f.OutboundHostnamesPeriod, other.OutboundHostnamesPeriod)
+	f.LocalNetworksPeriod = helpers.OverrideWithPointer(f.LocalNetworksPeriod, other.LocalNetworksPeriod)
 	f.Enabled = helpers.OverrideWithPointer(f.Enabled, other.Enabled)
 	f.Debug = helpers.OverrideWithPointer(f.Debug, other.Debug)
+	f.PauseKillSwitch = helpers.OverrideWithString(f.PauseKillSwitch, other.PauseKillSwitch)
+	f.MulticastDiscovery = helpers.OverrideWithPointer(f.MulticastDiscovery, other.MulticastDiscovery)
+	f.Interfaces = helpers.OverrideWithSlice(f.Interfaces, other.Interfaces)
+	f.RestoreHostRules = helpers.OverrideWithPointer(f.RestoreHostRules, other.RestoreHostRules)
 }
 
 func (f *Firewall) setDefaults() {
 	f.Enabled = helpers.DefaultPointer(f.Enabled, true)
+	const defaultOutboundHostnamesPeriod = 5 * time.Minute
+	f.OutboundHostnamesPeriod = helpers.DefaultPointer(f.OutboundHostnamesPeriod,
+		defaultOutboundHostnamesPeriod)
+	f.LocalNetworksPeriod = helpers.DefaultPointer(f.LocalNetworksPeriod, 0)
 	f.Debug = helpers.DefaultPointer(f.Debug, false)
+	f.PauseKillSwitch = helpers.DefaultString(f.PauseKillSwitch, "allow_lan")
+	f.MulticastDiscovery = helpers.DefaultPointer(f.MulticastDiscovery, false)
+	f.RestoreHostRules = helpers.DefaultPointer(f.RestoreHostRules, false)
 }
 
 func (f Firewall) String() string {
@@ -84,14 +173,29 @@ func (f Firewall) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Firewall settings:")
 
 	node.Appendf("Enabled: %s", helpers.BoolPtrToYesNo(f.Enabled))
+
+	if len(f.Interfaces) > 0 {
+		node.Appendf("Network interfaces: %s", strings.Join(f.Interfaces, ", "))
+	}
+
 	if !*f.Enabled {
 		return node
 	}
 
+	if *f.RestoreHostRules {
+		node.Appendf("Restore host rules when disabled: on")
+	}
+
 	if *f.Debug {
 		node.Appendf("Debug mode: on")
 	}
 
+	node.Appendf("Kill switch when VPN is paused: %s", f.PauseKillSwitch)
+
+	if *f.MulticastDiscovery {
+		node.Appendf("Multicast discovery (mDNS, SSDP): on")
+	}
+
 	if len(f.VPNInputPorts) > 0 {
 		vpnInputPortsNode := node.Appendf("VPN input ports:")
 		for _, port := range f.VPNInputPorts {
@@ -114,5 +218,17 @@ func (f Firewall) toLinesNode() (node *gotree.Node) {
 		}
 	}
 
+	if len(f.OutboundHostnames) > 0 {
+		outboundHostnames := node.Appendf("Outbound hostnames:")
+		for _, hostname := range f.OutboundHostnames {
+			outboundHostnames.Appendf(hostname)
+		}
+		outboundHostnames.Appendf("Resolution period: %s", *f.OutboundHostnamesPeriod)
+	}
+
+	if *f.LocalNetworksPeriod > 0 {
+		node.Appendf("New local networks check period: %s", *f.LocalNetworksPeriod)
+	}
+
 	return node
 }
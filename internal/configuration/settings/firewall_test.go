@@ -0,0 +1,51 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Firewall_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		interfaces []string
+		errWrapped error
+		errMessage string
+	}{
+		"all interfaces": {},
+		"host interfaces": {
+			interfaces: []string{"eth0", "enp3s0", "eth0.100", "br-lan"},
+		},
+		"interface with space": {
+			interfaces: []string{"eth0", "eth 1"},
+			errWrapped: ErrFirewallInterfaceNotValid,
+			errMessage: "interface name is not valid: 'eth 1' does not match regex '^[a-zA-Z0-9_.\\-]{1,15}$'",
+		},
+		"interface name too long": {
+			interfaces: []string{"averyverylongname"},
+			errWrapped: ErrFirewallInterfaceNotValid,
+			errMessage: "interface name is not valid: 'averyverylongname' does not match regex '^[a-zA-Z0-9_.\\-]{1,15}$'",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			firewall := Firewall{
+				PauseKillSwitch: "allow_lan",
+				Interfaces:      testCase.interfaces,
+			}
+
+			err := firewall.validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -2,9 +2,9 @@ package settings
 
 import (
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/capabilities"
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/govalid/address"
@@ -22,10 +22,11 @@ type Health struct {
 	// ReadTimeout is the HTTP read timeout duration of the
 	// HTTP server. It defaults to 500 milliseconds.
 	ReadTimeout time.Duration
-	// TargetAddress is the address (host or host:port)
-	// to TCP dial to periodically for the health check.
-	// It cannot be the empty string in the internal state.
-	TargetAddress string
+	// Targets are the targets to dial to concurrently and
+	// periodically for the health check. The health check
+	// succeeds if at least one of the targets can be dialed.
+	// It cannot be empty in the internal state.
+	Targets []HealthTarget
 	// SuccessWait is the duration to wait to re-run the
 	// healthcheck after a successful healthcheck.
 	// It defaults to 5 seconds and cannot be zero in
@@ -33,10 +34,20 @@ type Health struct {
 	SuccessWait time.Duration
 	// VPN has health settings specific to the VPN loop.
 	VPN HealthyWait
+	// Latency has settings to switch VPN server
+	// when the health check latency degrades.
+	Latency HealthLatency
 }
 
 func (h Health) Validate() (err error) {
-	uid := os.Getuid()
+	for _, target := range h.Targets {
+		err = target.validate()
+		if err != nil {
+			return fmt.Errorf("health target: %w", err)
+		}
+	}
+
+	uid := capabilities.ListeningUID()
 	_, err = address.Validate(h.ServerAddress,
 		address.OptionListening(uid))
 	if err != nil {
@@ -48,6 +59,11 @@ func (h Health) Validate() (err error) {
 		return fmt.Errorf("health VPN settings: %w", err)
 	}
 
+	err = h.Latency.validate()
+	if err != nil {
+		return fmt.Errorf("health latency settings: %w", err)
+	}
+
 	return nil
 }
 
@@ -56,9 +72,10 @@ func (h *Health) copy() (copied Health) {
 		ServerAddress:     h.ServerAddress,
 		ReadHeaderTimeout: h.ReadHeaderTimeout,
 		ReadTimeout:       h.ReadTimeout,
-		TargetAddress:     h.TargetAddress,
+		Targets:           copyHealthTargets(h.Targets),
 		SuccessWait:       h.SuccessWait,
 		VPN:               h.VPN.copy(),
+		Latency:           h.Latency.copy(),
 	}
 }
 
@@ -68,9 +85,10 @@ func (h *Health) MergeWith(other Health) {
 	h.ServerAddress = helpers.MergeWithString(h.ServerAddress, other.ServerAddress)
 	h.ReadHeaderTimeout = helpers.MergeWithNumber(h.ReadHeaderTimeout, other.ReadHeaderTimeout)
 	h.ReadTimeout = helpers.MergeWithNumber(h.ReadTimeout, other.ReadTimeout)
-	h.TargetAddress = helpers.MergeWithString(h.TargetAddress, other.TargetAddress)
+	h.Targets = helpers.MergeSlices(h.Targets, other.Targets)
 	h.SuccessWait = helpers.MergeWithNumber(h.SuccessWait, other.SuccessWait)
 	h.VPN.mergeWith(other.VPN)
+	h.Latency.mergeWith(other.Latency)
 }
 
 // OverrideWith overrides fields of the receiver
@@ -80,9 +98,10 @@ func (h *Health) OverrideWith(other Health) {
 	h.ServerAddress = helpers.OverrideWithString(h.ServerAddress, other.ServerAddress)
 	h.ReadHeaderTimeout = helpers.OverrideWithNumber(h.ReadHeaderTimeout, other.ReadHeaderTimeout)
 	h.ReadTimeout = helpers.OverrideWithNumber(h.ReadTimeout, other.ReadTimeout)
-	h.TargetAddress = helpers.OverrideWithString(h.TargetAddress, other.TargetAddress)
+	h.Targets = helpers.OverrideWithSlice(h.Targets, other.Targets)
 	h.SuccessWait = helpers.OverrideWithNumber(h.SuccessWait, other.SuccessWait)
 	h.VPN.overrideWith(other.VPN)
+	h.Latency.overrideWith(other.Latency)
 }
 
 func (h *Health) SetDefaults() {
@@ -91,10 +110,16 @@ func (h *Health) SetDefaults() {
 	h.ReadHeaderTimeout = helpers.DefaultNumber(h.ReadHeaderTimeout, defaultReadHeaderTimeout)
 	const defaultReadTimeout = 500 * time.Millisecond
 	h.ReadTimeout = helpers.DefaultNumber(h.ReadTimeout, defaultReadTimeout)
-	h.TargetAddress = helpers.DefaultString(h.TargetAddress, "cloudflare.com:443")
+	if len(h.Targets) == 0 {
+		h.Targets = []HealthTarget{{Address: "cloudflare.com:443"}}
+	}
+	for i := range h.Targets {
+		h.Targets[i].setDefaults()
+	}
 	const defaultSuccessWait = 5 * time.Second
 	h.SuccessWait = helpers.DefaultNumber(h.SuccessWait, defaultSuccessWait)
 	h.VPN.setDefaults()
+	h.Latency.setDefaults()
 }
 
 func (h Health) String() string {
@@ -104,10 +129,25 @@ func (h Health) String() string {
 func (h Health) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Health settings:")
 	node.Appendf("Server listening address: %s", h.ServerAddress)
-	node.Appendf("Target address: %s", h.TargetAddress)
+	targetsNode := node.Appendf("Targets:")
+	for _, target := range h.Targets {
+		targetsNode.Appendf("%s", target)
+	}
 	node.Appendf("Duration to wait after success: %s", h.SuccessWait)
 	node.Appendf("Read header timeout: %s", h.ReadHeaderTimeout)
 	node.Appendf("Read timeout: %s", h.ReadTimeout)
 	node.AppendNode(h.VPN.toLinesNode("VPN"))
+	if *h.Latency.Threshold > 0 {
+		node.AppendNode(h.Latency.toLinesNode())
+	}
 	return node
 }
+
+func copyHealthTargets(original []HealthTarget) (copied []HealthTarget) {
+	if original == nil {
+		return nil
+	}
+	copied = make([]HealthTarget, len(original))
+	copy(copied, original)
+	return copied
+}
@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// HealthLatency contains settings to switch VPN server when
+// the latency of the health checks degrades for too long.
+type HealthLatency struct {
+	// Threshold is the rolling median health check latency
+	// above which the latency is considered degraded.
+	// It can be set to 0 to disable server switching, and
+	// cannot be nil in the internal state.
+	Threshold *time.Duration
+	// Period is the duration the latency must stay degraded
+	// for before switching server. It defaults to 5 minutes
+	// and cannot be zero in the internal state.
+	Period time.Duration
+	// Samples is the number of latest health check latencies
+	// the rolling median is calculated on. It defaults to 10
+	// and cannot be zero in the internal state.
+	Samples uint
+	// RecoveryRatio is the ratio of the threshold the rolling
+	// median must drop below for the latency to be no longer
+	// degraded, to avoid flapping around the threshold.
+	// It must be between 0 excluded and 1, and defaults to 0.8.
+	RecoveryRatio float64
+}
+
+func (h HealthLatency) validate() (err error) {
+	if h.RecoveryRatio <= 0 || h.RecoveryRatio > 1 {
+		return fmt.Errorf("%w: %g must be between 0 excluded and 1",
+			ErrHealthRecoveryRatioNotValid, h.RecoveryRatio)
+	}
+	return nil
+}
+
+func (h *HealthLatency) copy() (copied HealthLatency) {
+	return HealthLatency{
+		Threshold:     helpers.CopyPointer(h.Threshold),
+		Period:        h.Period,
+		Samples:       h.Samples,
+		RecoveryRatio: h.RecoveryRatio,
+	}
+}
+
+func (h *HealthLatency) mergeWith(other HealthLatency) {
+	h.Threshold = helpers.MergeWithPointer(h.Threshold, other.Threshold)
+	h.Period = helpers.MergeWithNumber(h.Period, other.Period)
+	h.Samples = helpers.MergeWithNumber(h.Samples, other.Samples)
+	h.RecoveryRatio = helpers.MergeWithNumber(h.RecoveryRatio, other.RecoveryRatio)
+}
+
+func (h *HealthLatency) overrideWith(other HealthLatency) {
+	h.Threshold = helpers.OverrideWithPointer(h.Threshold, other.Threshold)
+	h.Period = helpers.OverrideWithNumber(h.Period, other.Period)
+	h.Samples = helpers.OverrideWithNumber(h.Samples, other.Samples)
+	h.RecoveryRatio = helpers.OverrideWithNumber(h.RecoveryRatio, other.RecoveryRatio)
+}
+
+func (h *HealthLatency) setDefaults() {
+	h.Threshold = helpers.DefaultPointer(h.Threshold, 0)
+	const defaultPeriod = 5 * time.Minute
+	h.Period = helpers.DefaultNumber(h.Period, defaultPeriod)
+	const defaultSamples = 10
+	h.Samples = helpers.DefaultNumber(h.Samples, defaultSamples)
+	const defaultRecoveryRatio = 0.8
+	h.RecoveryRatio = helpers.DefaultNumber(h.RecoveryRatio, defaultRecoveryRatio)
+}
+
+func (h HealthLatency) String() string {
+	return h.toLinesNode().String()
+}
+
+func (h HealthLatency) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Latency server switching:")
+	node.Appendf("Median latency threshold: %s", *h.Threshold)
+	node.Appendf("Degraded period before switching: %s", h.Period)
+	node.Appendf("Median samples: %d", h.Samples)
+	node.Appendf("Recovery ratio: %g", h.RecoveryRatio)
+	return node
+}
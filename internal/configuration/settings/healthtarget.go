@@ -0,0 +1,50 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+)
+
+// HealthTarget is a target the health check dials to.
+type HealthTarget struct {
+	// Address is the address (host or host:port) to dial to.
+	// It cannot be the empty string in the internal state.
+	Address string
+	// Network is the network to dial with, which can be
+	// 'tcp', 'tcp4' or 'tcp6'. It defaults to 'tcp4'.
+	Network string
+	// Timeout is the dial timeout for this target.
+	// It defaults to 3 seconds and cannot be zero
+	// in the internal state.
+	Timeout time.Duration
+}
+
+func (h HealthTarget) validate() (err error) {
+	if h.Address == "" {
+		return fmt.Errorf("%w", ErrHealthTargetAddressMissing)
+	}
+
+	if !helpers.IsOneOf(h.Network, "tcp", "tcp4", "tcp6") {
+		return fmt.Errorf("%w: %q for target %s",
+			ErrHealthTargetNetworkNotValid, h.Network, h.Address)
+	}
+
+	if h.Timeout <= 0 {
+		return fmt.Errorf("%w: %s for target %s",
+			ErrHealthTargetTimeoutTooSmall, h.Timeout, h.Address)
+	}
+
+	return nil
+}
+
+func (h *HealthTarget) setDefaults() {
+	h.Network = helpers.DefaultString(h.Network, "tcp4")
+	const defaultTimeout = 3 * time.Second
+	h.Timeout = helpers.DefaultNumber(h.Timeout, defaultTimeout)
+}
+
+func (h HealthTarget) String() string {
+	return fmt.Sprintf("%s (%s, timeout %s)", h.Address, h.Network, h.Timeout)
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"net/netip"
 
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -18,3 +19,10 @@ func CopyPointer[T any](original *T) (copied *T) {
 func CopySlice[T string | uint16 | netip.Addr | netip.Prefix](original []T) (copied []T) {
 	return slices.Clone(original)
 }
+
+func CopyMap[K comparable, V any](original map[K]V) (copied map[K]V) {
+	if original == nil {
+		return nil
+	}
+	return maps.Clone(original)
+}
@@ -37,3 +37,11 @@ func DefaultIP(existing netip.Addr, defaultValue netip.Addr) (
 	}
 	return defaultValue
 }
+
+func DefaultIPPrefix(existing netip.Prefix, defaultValue netip.Prefix) (
+	result netip.Prefix) {
+	if existing.IsValid() {
+		return existing
+	}
+	return defaultValue
+}
@@ -37,6 +37,13 @@ func MergeWithIP(existing, other netip.Addr) (result netip.Addr) {
 	return other
 }
 
+func MergeWithIPPrefix(existing, other netip.Prefix) (result netip.Prefix) {
+	if existing.IsValid() {
+		return existing
+	}
+	return other
+}
+
 func MergeWithHTTPHandler(existing, other http.Handler) (result http.Handler) {
 	if existing != nil {
 		return existing
@@ -67,3 +74,21 @@ func MergeSlices[T comparable](a, b []T) (result []T) {
 	}
 	return result
 }
+
+// MergeMaps returns a map with the keys and values of both maps
+// given, where the value from the first map is used for keys
+// present in both maps.
+func MergeMaps[K comparable, V any](a, b map[K]V) (result map[K]V) {
+	if a == nil && b == nil {
+		return nil
+	}
+
+	result = make(map[K]V, len(a)+len(b))
+	for k, v := range b {
+		result[k] = v
+	}
+	for k, v := range a {
+		result[k] = v
+	}
+	return result
+}
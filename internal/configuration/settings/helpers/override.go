@@ -35,6 +35,13 @@ func OverrideWithIP(existing, other netip.Addr) (result netip.Addr) {
 	return other
 }
 
+func OverrideWithIPPrefix(existing, other netip.Prefix) (result netip.Prefix) {
+	if !other.IsValid() {
+		return existing
+	}
+	return other
+}
+
 func OverrideWithHTTPHandler(existing, other http.Handler) (result http.Handler) {
 	if other != nil {
 		return other
@@ -50,3 +57,10 @@ func OverrideWithSlice[T any](existing, other []T) (result []T) {
 	copy(result, other)
 	return result
 }
+
+func OverrideWithMap[K comparable, V any](existing, other map[K]V) (result map[K]V) {
+	if other == nil {
+		return existing
+	}
+	return CopyMap(other)
+}
@@ -0,0 +1,152 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Hooks contains settings for executables run at
+// defined points of the VPN tunnel lifecycle.
+type Hooks struct {
+	// PreUp is the path of the executable to run after the VPN
+	// is set up and before the tunnel is started. It can be
+	// the empty string to disable it, and cannot be nil in the
+	// internal state.
+	PreUp *string
+	// PostUp is the path of the executable to run once the
+	// tunnel is up and port forwarding is started. It can be
+	// the empty string to disable it, and cannot be nil in the
+	// internal state.
+	PostUp *string
+	// PreDown is the path of the executable to run before the
+	// tunnel is torn down. It can be the empty string to disable
+	// it, and cannot be nil in the internal state.
+	PreDown *string
+	// PostDown is the path of the executable to run after the
+	// tunnel is torn down. It can be the empty string to disable
+	// it, and cannot be nil in the internal state.
+	PostDown *string
+	// Timeout is the maximum duration a hook executable can run
+	// for before being killed. It defaults to 1 second, such that
+	// the down hooks fit in the VPN shutdown timeout, and cannot
+	// be zero in the internal state.
+	Timeout time.Duration
+	// FailurePolicy is the action to take when a hook fails,
+	// and can be:
+	// - "ignore" to log the error and carry on
+	// - "abort" to fail the connection attempt if the pre-up
+	// hook fails.
+	// It defaults to "ignore" and cannot be the empty string
+	// in the internal state.
+	FailurePolicy string
+}
+
+func (h Hooks) validate() (err error) {
+	paths := map[string]string{
+		"pre-up":    *h.PreUp,
+		"post-up":   *h.PostUp,
+		"pre-down":  *h.PreDown,
+		"post-down": *h.PostDown,
+	}
+	for name, path := range paths {
+		if path == "" {
+			continue
+		}
+		err = helpers.FileExists(path)
+		if err != nil {
+			return fmt.Errorf("%s hook: %w", name, err)
+		}
+	}
+
+	validPolicies := []string{"ignore", "abort"}
+	if !helpers.IsOneOf(h.FailurePolicy, validPolicies...) {
+		return fmt.Errorf("%w: %q and can only be one of %s",
+			ErrHookFailurePolicyNotValid, h.FailurePolicy, strings.Join(validPolicies, ", "))
+	}
+	return nil
+}
+
+// Enabled returns true if at least one hook is set.
+func (h Hooks) Enabled() bool {
+	return *h.PreUp != "" || *h.PostUp != "" ||
+		*h.PreDown != "" || *h.PostDown != ""
+}
+
+// downHooksCount returns the number of pre-down
+// and post-down hooks set.
+func (h Hooks) downHooksCount() (count int) {
+	for _, path := range []string{*h.PreDown, *h.PostDown} {
+		if path != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func (h *Hooks) copy() (copied Hooks) {
+	return Hooks{
+		PreUp:         helpers.CopyPointer(h.PreUp),
+		PostUp:        helpers.CopyPointer(h.PostUp),
+		PreDown:       helpers.CopyPointer(h.PreDown),
+		PostDown:      helpers.CopyPointer(h.PostDown),
+		Timeout:       h.Timeout,
+		FailurePolicy: h.FailurePolicy,
+	}
+}
+
+func (h *Hooks) mergeWith(other Hooks) {
+	h.PreUp = helpers.MergeWithPointer(h.PreUp, other.PreUp)
+	h.PostUp = helpers.MergeWithPointer(h.PostUp, other.PostUp)
+	h.PreDown = helpers.MergeWithPointer(h.PreDown, other.PreDown)
+	h.PostDown = helpers.MergeWithPointer(h.PostDown, other.PostDown)
+	h.Timeout = helpers.MergeWithNumber(h.Timeout, other.Timeout)
+	h.FailurePolicy = helpers.MergeWithString(h.FailurePolicy, other.FailurePolicy)
+}
+
+func (h *Hooks) overrideWith(other Hooks) {
+	h.PreUp = helpers.OverrideWithPointer(h.PreUp, other.PreUp)
+	h.PostUp = helpers.OverrideWithPointer(h.PostUp, other.PostUp)
+	h.PreDown = helpers.OverrideWithPointer(h.PreDown, other.PreDown)
+	h.PostDown = helpers.OverrideWithPointer(h.PostDown, other.PostDown)
+	h.Timeout = helpers.OverrideWithNumber(h.Timeout, other.Timeout)
+	h.FailurePolicy = helpers.OverrideWithString(h.FailurePolicy, other.FailurePolicy)
+}
+
+func (h *Hooks) setDefaults() {
+	h.PreUp = helpers.DefaultPointer(h.PreUp, "")
+	h.PostUp = helpers.DefaultPointer(h.PostUp, "")
+	h.PreDown = helpers.DefaultPointer(h.PreDown, "")
+	h.PostDown = helpers.DefaultPointer(h.PostDown, "")
+	const defaultTimeout = time.Second
+	h.Timeout = helpers.DefaultNumber(h.Timeout, defaultTimeout)
+	h.FailurePolicy = helpers.DefaultString(h.FailurePolicy, "ignore")
+}
+
+func (h Hooks) String() string {
+	return h.toLinesNode().String()
+}
+
+func (h Hooks) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Lifecycle hooks:")
+	hooks := []struct {
+		name string
+		path string
+	}{
+		{name: "Pre up", path: *h.PreUp},
+		{name: "Post up", path: *h.PostUp},
+		{name: "Pre down", path: *h.PreDown},
+		{name: "Post down", path: *h.PostDown},
+	}
+	for _, hook := range hooks {
+		if hook.path != "" {
+			node.Appendf("%s: %s", hook.name, hook.path)
+		}
+	}
+	node.Appendf("Timeout: %s", h.Timeout)
+	node.Appendf("Failure policy: %s", h.FailurePolicy)
+	return node
+}
@@ -0,0 +1,152 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Hooks_validate(t *testing.T) {
+	t.Parallel()
+
+	existingPath := filepath.Join(t.TempDir(), "hook.sh")
+	const perm = 0700
+	err := os.WriteFile(existingPath, nil, perm)
+	require.NoError(t, err)
+	missingPath := filepath.Join(t.TempDir(), "missing.sh")
+
+	testCases := map[string]struct {
+		hooks      Hooks
+		errWrapped error
+		errMessage string
+	}{
+		"no hook": {
+			hooks: Hooks{
+				PreUp:         stringPtr(""),
+				PostUp:        stringPtr(""),
+				PreDown:       stringPtr(""),
+				PostDown:      stringPtr(""),
+				FailurePolicy: "ignore",
+			},
+		},
+		"existing hook": {
+			hooks: Hooks{
+				PreUp:         stringPtr(existingPath),
+				PostUp:        stringPtr(""),
+				PreDown:       stringPtr(""),
+				PostDown:      stringPtr(""),
+				FailurePolicy: "abort",
+			},
+		},
+		"missing hook": {
+			hooks: Hooks{
+				PreUp:         stringPtr(""),
+				PostUp:        stringPtr(""),
+				PreDown:       stringPtr(missingPath),
+				PostDown:      stringPtr(""),
+				FailurePolicy: "ignore",
+			},
+			errWrapped: helpers.ErrFileDoesNotExist,
+			errMessage: "pre-down hook: file does not exist: " + missingPath,
+		},
+		"invalid failure policy": {
+			hooks: Hooks{
+				PreUp:         stringPtr(""),
+				PostUp:        stringPtr(""),
+				PreDown:       stringPtr(""),
+				PostDown:      stringPtr(""),
+				FailurePolicy: "retry",
+			},
+			errWrapped: ErrHookFailurePolicyNotValid,
+			errMessage: `hook failure policy is not valid: "retry" and can only be one of ignore, abort`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.hooks.validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Hooks_setDefaults(t *testing.T) {
+	t.Parallel()
+
+	var hooks Hooks
+	hooks.setDefaults()
+
+	expected := Hooks{
+		PreUp:         stringPtr(""),
+		PostUp:        stringPtr(""),
+		PreDown:       stringPtr(""),
+		PostDown:      stringPtr(""),
+		Timeout:       time.Second,
+		FailurePolicy: "ignore",
+	}
+	assert.Equal(t, expected, hooks)
+}
+
+func Test_Shutdown_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		vpnHooks   Hooks
+		errWrapped error
+		errMessage string
+	}{
+		"default down hooks": {
+			vpnHooks: Hooks{
+				PreDown:  stringPtr("/hooks/pre-down"),
+				PostDown: stringPtr("/hooks/post-down"),
+				Timeout:  time.Second,
+			},
+		},
+		"down hooks exceeding VPN timeout": {
+			vpnHooks: Hooks{
+				PreDown:  stringPtr("/hooks/pre-down"),
+				PostDown: stringPtr(""),
+				Timeout:  10 * time.Second,
+			},
+			errWrapped: ErrShutdownVPNTimeoutTooShort,
+			errMessage: "VPN shutdown timeout is too short: 3s must be " +
+				"more than the VPN down hooks timeouts 10s",
+		},
+		"no down hook": {
+			vpnHooks: Hooks{
+				PreDown:  stringPtr(""),
+				PostDown: stringPtr(""),
+				Timeout:  10 * time.Second,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var shutdown Shutdown
+			shutdown.setDefaults()
+
+			err := shutdown.validate(testCase.vpnHooks)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package settings
 
 import (
 	"fmt"
-	"os"
+	"net/netip"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/capabilities"
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/govalid/address"
 )
@@ -40,30 +42,100 @@ type HTTPProxy struct {
 	// ReadTimeout is the HTTP read timeout duration
 	// of the HTTP server. It defaults to 3 seconds if left unset.
 	ReadTimeout time.Duration
+	// ProviderSOCKSServer is the hostname of the VPN server
+	// whose SOCKS5 proxy, only reachable through the VPN tunnel,
+	// the HTTP proxy should chain through. This is used with Mullvad.
+	// It can be the empty string to connect directly through the
+	// VPN tunnel. It cannot be nil in the internal state.
+	ProviderSOCKSServer *string
+	// AllowedDestinations are hostnames, IP addresses and CIDRs
+	// the HTTP proxy is restricted to connect to. A hostname also
+	// matches its subdomains. If it is empty, all destinations not
+	// denied are allowed.
+	AllowedDestinations []string
+	// DeniedDestinations are hostnames, IP addresses and CIDRs
+	// the HTTP proxy refuses to connect to, taking precedence
+	// over the allowed destinations.
+	DeniedDestinations []string
+	// AllowedPorts are the destination ports the HTTP proxy is
+	// restricted to connect to. If it is empty, all destination
+	// ports not denied are allowed.
+	AllowedPorts []uint16
+	// DeniedPorts are the destination ports the HTTP proxy
+	// refuses to connect to.
+	DeniedPorts []uint16
+	// ConnectPorts are the destination ports the HTTP proxy
+	// accepts to tunnel CONNECT requests to, in addition to
+	// port 443 which is always accepted.
+	ConnectPorts []uint16
 }
 
-func (h HTTPProxy) validate() (err error) {
+func (h HTTPProxy) validate(vpnProvider string, storage Storage) (err error) {
 	// Do not validate user and password
 
-	uid := os.Getuid()
+	uid := capabilities.ListeningUID()
 	_, err = address.Validate(h.ListeningAddress, address.OptionListening(uid))
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrServerAddressNotValid, h.ListeningAddress)
 	}
 
+	if *h.ProviderSOCKSServer != "" {
+		if vpnProvider != providers.Mullvad {
+			return fmt.Errorf("%w: for VPN service provider %s",
+				ErrHTTPProxySOCKSNotSupported, vpnProvider)
+		}
+
+		hostnames := storage.GetFilterChoices(vpnProvider).Hostnames
+		err = helpers.AreAllOneOf([]string{*h.ProviderSOCKSServer}, hostnames)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrHostnameNotValid, err)
+		}
+	}
+
+	for _, destinations := range [][]string{h.AllowedDestinations, h.DeniedDestinations} {
+		for _, destination := range destinations {
+			if !isHTTPProxyDestinationValid(destination) {
+				return fmt.Errorf("%w: %s", ErrHTTPProxyDestinationNotValid, destination)
+			}
+		}
+	}
+
+	for _, ports := range [][]uint16{h.AllowedPorts, h.DeniedPorts, h.ConnectPorts} {
+		for _, port := range ports {
+			if port == 0 {
+				return fmt.Errorf("%w", ErrHTTPProxyZeroPort)
+			}
+		}
+	}
+
 	return nil
 }
 
+func isHTTPProxyDestinationValid(destination string) (valid bool) {
+	if _, err := netip.ParseAddr(destination); err == nil {
+		return true
+	} else if _, err := netip.ParsePrefix(destination); err == nil {
+		return true
+	}
+	return hostRegex.MatchString(destination)
+}
+
 func (h *HTTPProxy) copy() (copied HTTPProxy) {
 	return HTTPProxy{
-		User:              helpers.CopyPointer(h.User),
-		Password:          helpers.CopyPointer(h.Password),
-		ListeningAddress:  h.ListeningAddress,
-		Enabled:           helpers.CopyPointer(h.Enabled),
-		Stealth:           helpers.CopyPointer(h.Stealth),
-		Log:               helpers.CopyPointer(h.Log),
-		ReadHeaderTimeout: h.ReadHeaderTimeout,
-		ReadTimeout:       h.ReadTimeout,
+		User:                helpers.CopyPointer(h.User),
+		Password:            helpers.CopyPointer(h.Password),
+		ListeningAddress:    h.ListeningAddress,
+		Enabled:             helpers.CopyPointer(h.Enabled),
+		Stealth:             helpers.CopyPointer(h.Stealth),
+		Log:                 helpers.CopyPointer(h.Log),
+		ReadHeaderTimeout:   h.ReadHeaderTimeout,
+		ReadTimeout:         h.ReadTimeout,
+		ProviderSOCKSServer: helpers.CopyPointer(h.ProviderSOCKSServer),
+		AllowedDestinations: helpers.CopySlice(h.AllowedDestinations),
+		DeniedDestinations:  helpers.CopySlice(h.DeniedDestinations),
+		AllowedPorts:        helpers.CopySlice(h.AllowedPorts),
+		DeniedPorts:         helpers.CopySlice(h.DeniedPorts),
+		ConnectPorts:        helpers.CopySlice(h.ConnectPorts),
 	}
 }
 
@@ -78,6 +150,12 @@ func (h *HTTPProxy) mergeWith(other HTTPProxy) {
 	h.Log = helpers.MergeWithPointer(h.Log, other.Log)
 	h.ReadHeaderTimeout = helpers.MergeWithNumber(h.ReadHeaderTimeout, other.ReadHeaderTimeout)
 	h.ReadTimeout = helpers.MergeWithNumber(h.ReadTimeout, other.ReadTimeout)
+	h.ProviderSOCKSServer = helpers.MergeWithPointer(h.ProviderSOCKSServer, other.ProviderSOCKSServer)
+	h.AllowedDestinations = helpers.MergeSlices(h.AllowedDestinations, other.AllowedDestinations)
+	h.DeniedDestinations = helpers.MergeSlices(h.DeniedDestinations, other.DeniedDestinations)
+	h.AllowedPorts = helpers.MergeSlices(h.AllowedPorts, other.AllowedPorts)
+	h.DeniedPorts = helpers.MergeSlices(h.DeniedPorts, other.DeniedPorts)
+	h.ConnectPorts = helpers.MergeSlices(h.ConnectPorts, other.ConnectPorts)
 }
 
 // overrideWith overrides fields of the receiver
@@ -92,6 +170,12 @@ func (h *HTTPProxy) overrideWith(other HTTPProxy) {
 	h.Log = helpers.OverrideWithPointer(h.Log, other.Log)
 	h.ReadHeaderTimeout = helpers.OverrideWithNumber(h.ReadHeaderTimeout, other.ReadHeaderTimeout)
 	h.ReadTimeout = helpers.OverrideWithNumber(h.ReadTimeout, other.ReadTimeout)
+	h.ProviderSOCKSServer = helpers.OverrideWithPointer(h.ProviderSOCKSServer, other.ProviderSOCKSServer)
+	h.AllowedDestinations = helpers.OverrideWithSlice(h.AllowedDestinations, other.AllowedDestinations)
+	h.DeniedDestinations = helpers.OverrideWithSlice(h.DeniedDestinations, other.DeniedDestinations)
+	h.AllowedPorts = helpers.OverrideWithSlice(h.AllowedPorts, other.AllowedPorts)
+	h.DeniedPorts = helpers.OverrideWithSlice(h.DeniedPorts, other.DeniedPorts)
+	h.ConnectPorts = helpers.OverrideWithSlice(h.ConnectPorts, other.ConnectPorts)
 }
 
 func (h *HTTPProxy) setDefaults() {
@@ -105,6 +189,7 @@ func (h *HTTPProxy) setDefaults() {
 	h.ReadHeaderTimeout = helpers.DefaultNumber(h.ReadHeaderTimeout, defaultReadHeaderTimeout)
 	const defaultReadTimeout = 3 * time.Second
 	h.ReadTimeout = helpers.DefaultNumber(h.ReadTimeout, defaultReadTimeout)
+	h.ProviderSOCKSServer = helpers.DefaultPointer(h.ProviderSOCKSServer, "")
 }
 
 func (h HTTPProxy) String() string {
@@ -125,6 +210,43 @@ func (h HTTPProxy) toLinesNode() (node *gotree.Node) {
 	node.Appendf("Log: %s", helpers.BoolPtrToYesNo(h.Log))
 	node.Appendf("Read header timeout: %s", h.ReadHeaderTimeout)
 	node.Appendf("Read timeout: %s", h.ReadTimeout)
+	if *h.ProviderSOCKSServer != "" {
+		node.Appendf("Chained through SOCKS5 proxy of server: %s", *h.ProviderSOCKSServer)
+	}
+
+	if len(h.AllowedDestinations) > 0 {
+		allowedDestinationsNode := node.Appendf("Allowed destinations:")
+		for _, destination := range h.AllowedDestinations {
+			allowedDestinationsNode.Appendf("%s", destination)
+		}
+	}
+
+	if len(h.DeniedDestinations) > 0 {
+		deniedDestinationsNode := node.Appendf("Denied destinations:")
+		for _, destination := range h.DeniedDestinations {
+			deniedDestinationsNode.Appendf("%s", destination)
+		}
+	}
+
+	if len(h.AllowedPorts) > 0 {
+		allowedPortsNode := node.Appendf("Allowed ports:")
+		for _, port := range h.AllowedPorts {
+			allowedPortsNode.Appendf("%d", port)
+		}
+	}
+
+	if len(h.DeniedPorts) > 0 {
+		deniedPortsNode := node.Appendf("Denied ports:")
+		for _, port := range h.DeniedPorts {
+			deniedPortsNode.Appendf("%d", port)
+		}
+	}
+
+	connectPortsNode := node.Appendf("CONNECT ports:")
+	connectPortsNode.Appendf("443")
+	for _, port := range h.ConnectPorts {
+		connectPortsNode.Appendf("%d", port)
+	}
 
 	return node
 }
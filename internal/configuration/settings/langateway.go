@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gotree"
+)
+
+// LANGateway contains settings to use gluetun as the default
+// gateway of devices on the local network, forwarding their
+// traffic through the VPN tunnel.
+type LANGateway struct {
+	// Enabled is true to forward and masquerade traffic
+	// from the subnets through the VPN interface.
+	// It defaults to false, and cannot be nil in the internal state.
+	Enabled *bool
+	// Subnets are the IPv4 local networks of the devices
+	// using gluetun as their default gateway, for example
+	// 192.168.1.0/24. It cannot be empty if Enabled is true.
+	Subnets []netip.Prefix
+}
+
+func (l LANGateway) validate(vpnType string) (err error) {
+	if !*l.Enabled {
+		return nil
+	}
+
+	if vpnType == vpn.Tor {
+		return fmt.Errorf("%w: for VPN type %s", ErrLANGatewayNotSupported, vpnType)
+	}
+
+	if len(l.Subnets) == 0 {
+		return fmt.Errorf("%w", ErrLANGatewaySubnetsNotSet)
+	}
+
+	for _, subnet := range l.Subnets {
+		if !subnet.IsValid() || !subnet.Addr().Is4() {
+			return fmt.Errorf("%w: %s must be an IPv4 prefix",
+				ErrLANGatewaySubnetNotValid, subnet)
+		}
+	}
+
+	return nil
+}
+
+func (l *LANGateway) copy() (copied LANGateway) {
+	return LANGateway{
+		Enabled: helpers.CopyPointer(l.Enabled),
+		Subnets: helpers.CopySlice(l.Subnets),
+	}
+}
+
+func (l *LANGateway) mergeWith(other LANGateway) {
+	l.Enabled = helpers.MergeWithPointer(l.Enabled, other.Enabled)
+	l.Subnets = helpers.MergeSlices(l.Subnets, other.Subnets)
+}
+
+func (l *LANGateway) overrideWith(other LANGateway) {
+	l.Enabled = helpers.OverrideWithPointer(l.Enabled, other.Enabled)
+	l.Subnets = helpers.OverrideWithSlice(l.Subnets, other.Subnets)
+}
+
+func (l *LANGateway) setDefaults() {
+	l.Enabled = helpers.DefaultPointer(l.Enabled, false)
+}
+
+func (l LANGateway) String() string {
+	return l.toLinesNode().String()
+}
+
+func (l LANGateway) toLinesNode() (node *gotree.Node) {
+	if !*l.Enabled {
+		return nil
+	}
+
+	node = gotree.New("LAN gateway settings:")
+	subnetsNode := node.Appendf("Subnets:")
+	for _, subnet := range l.Subnets {
+		subnetsNode.Appendf(subnet.String())
+	}
+
+	return node
+}
@@ -1,6 +1,8 @@
 package settings
 
 import (
+	"fmt"
+
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/log"
@@ -11,15 +13,33 @@ type Log struct {
 	// Level is the log level of the logger.
 	// It cannot be nil in the internal state.
 	Level *log.Level
+	// Syslog contains settings to configure sending
+	// logs to a remote syslog server.
+	Syslog Syslog
+	// File contains settings to configure writing
+	// logs to a file with rotation.
+	File LogFile
 }
 
 func (l Log) validate() (err error) {
+	err = l.Syslog.validate()
+	if err != nil {
+		return fmt.Errorf("syslog settings: %w", err)
+	}
+
+	err = l.File.validate()
+	if err != nil {
+		return fmt.Errorf("log file settings: %w", err)
+	}
+
 	return nil
 }
 
 func (l *Log) copy() (copied Log) {
 	return Log{
-		Level: helpers.CopyPointer(l.Level),
+		Level:  helpers.CopyPointer(l.Level),
+		Syslog: l.Syslog.copy(),
+		File:   l.File.copy(),
 	}
 }
 
@@ -27,6 +47,8 @@ func (l *Log) copy() (copied Log) {
 // unset field of the receiver settings object.
 func (l *Log) mergeWith(other Log) {
 	l.Level = helpers.MergeWithPointer(l.Level, other.Level)
+	l.Syslog.mergeWith(other.Syslog)
+	l.File.mergeWith(other.File)
 }
 
 // overrideWith overrides fields of the receiver
@@ -34,10 +56,14 @@ func (l *Log) mergeWith(other Log) {
 // settings.
 func (l *Log) overrideWith(other Log) {
 	l.Level = helpers.OverrideWithPointer(l.Level, other.Level)
+	l.Syslog.overrideWith(other.Syslog)
+	l.File.overrideWith(other.File)
 }
 
 func (l *Log) setDefaults() {
 	l.Level = helpers.DefaultPointer(l.Level, log.LevelInfo)
+	l.Syslog.setDefaults()
+	l.File.setDefaults()
 }
 
 func (l Log) String() string {
@@ -47,5 +73,7 @@ func (l Log) String() string {
 func (l Log) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Log settings:")
 	node.Appendf("Log level: %s", l.Level.String())
+	node.AppendNode(l.Syslog.toLinesNode())
+	node.AppendNode(l.File.toLinesNode())
 	return node
 }
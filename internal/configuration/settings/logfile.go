@@ -0,0 +1,115 @@
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// LogFile contains settings to configure writing
+// the logs to a file with rotation.
+type LogFile struct {
+	// Path is the log file path, typically in a mounted
+	// volume. It can be set to the empty string to disable
+	// writing logs to a file. It cannot be nil in the
+	// internal state.
+	Path *string
+	// MaxSize is the maximum size in bytes of the log file
+	// before it is rotated. It can be set to 0 to disable
+	// size based rotation, and cannot be nil in the internal state.
+	MaxSize *uint64
+	// MaxAge is the maximum age of the log file before it is
+	// rotated. It can be set to 0 to disable age based rotation,
+	// and cannot be nil in the internal state.
+	MaxAge *time.Duration
+	// MaxBackups is the maximum number of rotated log files
+	// to keep. It can be set to 0 to remove the log file on
+	// rotation, and cannot be nil in the internal state.
+	MaxBackups *uint8
+	// Stdout is true to also write the logs to stdout.
+	// It cannot be nil in the internal state.
+	Stdout *bool
+}
+
+func (l LogFile) validate() (err error) {
+	if *l.Path == "" {
+		return nil
+	}
+
+	_, err = filepath.Abs(*l.Path)
+	if err != nil {
+		return fmt.Errorf("path is not valid: %w", err)
+	}
+
+	if *l.MaxAge < 0 {
+		return fmt.Errorf("%w: %s must be positive",
+			ErrLogFileMaxAgeNotValid, *l.MaxAge)
+	}
+
+	return nil
+}
+
+func (l *LogFile) copy() (copied LogFile) {
+	return LogFile{
+		Path:       helpers.CopyPointer(l.Path),
+		MaxSize:    helpers.CopyPointer(l.MaxSize),
+		MaxAge:     helpers.CopyPointer(l.MaxAge),
+		MaxBackups: helpers.CopyPointer(l.MaxBackups),
+		Stdout:     helpers.CopyPointer(l.Stdout),
+	}
+}
+
+func (l *LogFile) mergeWith(other LogFile) {
+	l.Path = helpers.MergeWithPointer(l.Path, other.Path)
+	l.MaxSize = helpers.MergeWithPointer(l.MaxSize, other.MaxSize)
+	l.MaxAge = helpers.MergeWithPointer(l.MaxAge, other.MaxAge)
+	l.MaxBackups = helpers.MergeWithPointer(l.MaxBackups, other.MaxBackups)
+	l.Stdout = helpers.MergeWithPointer(l.Stdout, other.Stdout)
+}
+
+func (l *LogFile) overrideWith(other LogFile) {
+	l.Path = helpers.OverrideWithPointer(l.Path, other.Path)
+	l.MaxSize = helpers.OverrideWithPointer(l.MaxSize, other.MaxSize)
+	l.MaxAge = helpers.OverrideWithPointer(l.MaxAge, other.MaxAge)
+	l.MaxBackups = helpers.OverrideWithPointer(l.MaxBackups, other.MaxBackups)
+	l.Stdout = helpers.OverrideWithPointer(l.Stdout, other.Stdout)
+}
+
+func (l *LogFile) setDefaults() {
+	l.Path = helpers.DefaultPointer(l.Path, "")
+	const defaultMaxSize = 10 * 1000 * 1000
+	l.MaxSize = helpers.DefaultPointer(l.MaxSize, defaultMaxSize)
+	l.MaxAge = helpers.DefaultPointer(l.MaxAge, 0)
+	const defaultMaxBackups = 3
+	l.MaxBackups = helpers.DefaultPointer(l.MaxBackups, defaultMaxBackups)
+	l.Stdout = helpers.DefaultPointer(l.Stdout, true)
+}
+
+func (l LogFile) String() string {
+	return l.toLinesNode().String()
+}
+
+func (l LogFile) toLinesNode() (node *gotree.Node) {
+	if *l.Path == "" {
+		return nil
+	}
+
+	node = gotree.New("Log file settings:")
+	node.Appendf("Path: %s", *l.Path)
+	maxSize := "disabled"
+	if *l.MaxSize > 0 {
+		maxSize = fmt.Sprintf("%d bytes", *l.MaxSize)
+	}
+	node.Appendf("Rotation max size: %s", maxSize)
+	maxAge := "disabled"
+	if *l.MaxAge > 0 {
+		maxAge = l.MaxAge.String()
+	}
+	node.Appendf("Rotation max age: %s", maxAge)
+	node.Appendf("Rotated files kept: %d", *l.MaxBackups)
+	node.Appendf("Also write to stdout: %s", helpers.BoolPtrToYesNo(l.Stdout))
+	return node
+}
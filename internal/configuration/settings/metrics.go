@@ -0,0 +1,108 @@
+package settings
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Metrics contains settings to configure pushing metrics
+// to a StatsD or InfluxDB server over UDP.
+type Metrics struct {
+	// Address is the UDP address of the metrics server,
+	// in the form host:port. It can be set to the empty
+	// string to disable pushing metrics.
+	// It cannot be nil in the internal state.
+	Address *string
+	// Format is the metrics format to use, and can be
+	// 'statsd' or 'influx' for the InfluxDB line protocol.
+	// It cannot be the empty string in the internal state.
+	Format string
+	// Prefix is the prefix for metric names when using the
+	// statsd format, and the measurement name when using the
+	// influx format. It cannot be the empty string in the
+	// internal state.
+	Prefix string
+	// Period is the period to push metrics at.
+	// It cannot be zero in the internal state.
+	Period time.Duration
+}
+
+func (m Metrics) validate() (err error) {
+	if *m.Address == "" {
+		return nil
+	}
+
+	_, _, err = net.SplitHostPort(*m.Address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrMetricsAddressNotValid, err)
+	}
+
+	if !helpers.IsOneOf(m.Format, "statsd", "influx") {
+		return fmt.Errorf("%w: %s", ErrMetricsFormatNotValid, m.Format)
+	}
+
+	const minPeriod = time.Second
+	if m.Period < minPeriod {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrMetricsPeriodTooSmall, m.Period, minPeriod)
+	}
+
+	return nil
+}
+
+func (m *Metrics) copy() (copied Metrics) {
+	return Metrics{
+		Address: helpers.CopyPointer(m.Address),
+		Format:  m.Format,
+		Prefix:  m.Prefix,
+		Period:  m.Period,
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (m *Metrics) mergeWith(other Metrics) {
+	m.Address = helpers.MergeWithPointer(m.Address, other.Address)
+	m.Format = helpers.MergeWithString(m.Format, other.Format)
+	m.Prefix = helpers.MergeWithString(m.Prefix, other.Prefix)
+	m.Period = helpers.MergeWithNumber(m.Period, other.Period)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (m *Metrics) overrideWith(other Metrics) {
+	m.Address = helpers.OverrideWithPointer(m.Address, other.Address)
+	m.Format = helpers.OverrideWithString(m.Format, other.Format)
+	m.Prefix = helpers.OverrideWithString(m.Prefix, other.Prefix)
+	m.Period = helpers.OverrideWithNumber(m.Period, other.Period)
+}
+
+func (m *Metrics) setDefaults() {
+	m.Address = helpers.DefaultPointer(m.Address, "")
+	m.Format = helpers.DefaultString(m.Format, "statsd")
+	m.Prefix = helpers.DefaultString(m.Prefix, "gluetun")
+	const defaultPeriod = 10 * time.Second
+	m.Period = helpers.DefaultNumber(m.Period, defaultPeriod)
+}
+
+func (m Metrics) String() string {
+	return m.toLinesNode().String()
+}
+
+func (m Metrics) toLinesNode() (node *gotree.Node) {
+	if *m.Address == "" {
+		return nil
+	}
+
+	node = gotree.New("Metrics settings:")
+	node.Appendf("Server address: %s", *m.Address)
+	node.Appendf("Format: %s", m.Format)
+	node.Appendf("Prefix: %s", m.Prefix)
+	node.Appendf("Period: %s", m.Period)
+	return node
+}
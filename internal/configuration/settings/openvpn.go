@@ -2,6 +2,7 @@ package settings
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strings"
@@ -61,6 +62,20 @@ type OpenVPN struct {
 	// to decrypt the EncryptedPrivateKey. It defaults to the
 	// empty string and must be set if EncryptedPrivateKey is set.
 	KeyPassphrase *string
+	// TLSCryptV2 is the base64 encoded data of an OpenVPN
+	// tls-crypt-v2 client key, for providers which moved to
+	// tls-crypt-v2. It replaces any tls-auth or tls-crypt
+	// static key of the provider configuration.
+	// It can be set to the empty string to be ignored.
+	// It cannot be nil in the internal state.
+	TLSCryptV2 *string
+	// StaticKey is the hex encoded OpenVPN static key V1 to
+	// use instead of the tls-auth or tls-crypt static key of the
+	// provider configuration, for example if the provider rotated
+	// it. It is used for tls-crypt if the provider uses neither.
+	// It can be set to the empty string to be ignored.
+	// It cannot be nil in the internal state.
+	StaticKey *string
 	// PIAEncPreset is the encryption preset for
 	// Private Internet Access. It can be set to an
 	// empty string for other providers.
@@ -69,6 +84,15 @@ type OpenVPN struct {
 	// mssfix option for OpenVPN. It is ignored if set to 0.
 	// It cannot be nil in the internal state.
 	MSSFix *uint16
+	// Fragment is the value (1 to 10000) to set for the
+	// fragment option for OpenVPN. It is ignored if set to 0
+	// and only applies to UDP connections.
+	// It cannot be nil in the internal state.
+	Fragment *uint16
+	// TunMTU is the value (576 to 65535) to set for the
+	// tun-mtu option for OpenVPN. It is ignored if set to 0.
+	// It cannot be nil in the internal state.
+	TunMTU *uint16
 	// Interface is the OpenVPN device interface name.
 	// It cannot be an empty string in the internal state.
 	Interface string
@@ -94,6 +118,12 @@ func (o OpenVPN) validate(vpnProvider string) (err error) {
 			ErrOpenVPNVersionIsNotValid, o.Version, strings.Join(validVersions, ", "))
 	}
 
+	supportedVersions := openvpnVersionsSupported(vpnProvider)
+	if !helpers.IsOneOf(o.Version, supportedVersions...) {
+		return fmt.Errorf("%w: %s supports only version %s",
+			ErrOpenVPNVersionNotSupported, vpnProvider, strings.Join(supportedVersions, ", "))
+	}
+
 	isCustom := vpnProvider == providers.Custom
 	isUserRequired := !isCustom &&
 		vpnProvider != providers.Airvpn &&
@@ -134,12 +164,38 @@ func (o OpenVPN) validate(vpnProvider string) (err error) {
 		return fmt.Errorf("%w", ErrOpenVPNKeyPassphraseIsEmpty)
 	}
 
+	err = validateOpenVPNTLSCryptV2Key(*o.TLSCryptV2)
+	if err != nil {
+		return err
+	}
+
+	err = validateOpenVPNStaticKey(*o.StaticKey)
+	if err != nil {
+		return err
+	}
+
+	if *o.TLSCryptV2 != "" && *o.StaticKey != "" {
+		return fmt.Errorf("%w", ErrOpenVPNStaticKeysConflict)
+	}
+
 	const maxMSSFix = 10000
 	if *o.MSSFix > maxMSSFix {
 		return fmt.Errorf("%w: %d is over the maximum value of %d",
 			ErrOpenVPNMSSFixIsTooHigh, *o.MSSFix, maxMSSFix)
 	}
 
+	const maxFragment = 10000
+	if *o.Fragment > maxFragment {
+		return fmt.Errorf("%w: %d is over the maximum value of %d",
+			ErrOpenVPNFragmentIsTooHigh, *o.Fragment, maxFragment)
+	}
+
+	const minTunMTU = 576
+	if *o.TunMTU != 0 && *o.TunMTU < minTunMTU {
+		return fmt.Errorf("%w: %d is below the minimum value of %d",
+			ErrOpenVPNTunMTUIsTooLow, *o.TunMTU, minTunMTU)
+	}
+
 	if !regexpInterfaceName.MatchString(o.Interface) {
 		return fmt.Errorf("%w: '%s' does not match regex '%s'",
 			ErrOpenVPNInterfaceNotValid, o.Interface, regexpInterfaceName)
@@ -153,6 +209,19 @@ func (o OpenVPN) validate(vpnProvider string) (err error) {
 	return nil
 }
 
+// openvpnVersionsSupported returns the OpenVPN versions
+// the VPN provider given can be used with.
+func openvpnVersionsSupported(vpnProvider string) (versions []string) {
+	switch vpnProvider {
+	case providers.Fastestvpn, providers.VPNSecure, providers.Vyprvpn:
+		// Their configurations enable compression, which OpenVPN 2.6
+		// refuses by default with its allow-compression option.
+		return []string{openvpn.Openvpn25}
+	default:
+		return []string{openvpn.Openvpn25, openvpn.Openvpn26}
+	}
+}
+
 func validateOpenVPNConfigFilepath(isCustom bool,
 	confFile string) (err error) {
 	if !isCustom {
@@ -241,6 +310,45 @@ func validateOpenVPNEncryptedKey(vpnProvider,
 	return nil
 }
 
+func validateOpenVPNTLSCryptV2Key(key string) (err error) {
+	if key == "" {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrOpenVPNTLSCryptV2KeyNotValid, err)
+	}
+
+	// The client key is made of the 256 bytes client key, followed
+	// by the wrapped client key containing a 32 bytes tag, the
+	// encrypted 256 bytes client key and metadata and a 2 bytes length.
+	const minLength = 256 + 32 + 256 + 2
+	if len(data) < minLength {
+		return fmt.Errorf("%w: %d bytes is less than the minimum of %d bytes",
+			ErrOpenVPNTLSCryptV2KeyNotValid, len(data), minLength)
+	}
+	return nil
+}
+
+func validateOpenVPNStaticKey(key string) (err error) {
+	if key == "" {
+		return nil
+	}
+
+	data, err := hex.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrOpenVPNStaticKeyNotValid, err)
+	}
+
+	const length = 256
+	if len(data) != length {
+		return fmt.Errorf("%w: %d bytes instead of %d bytes",
+			ErrOpenVPNStaticKeyNotValid, len(data), length)
+	}
+	return nil
+}
+
 func (o *OpenVPN) copy() (copied OpenVPN) {
 	return OpenVPN{
 		Version:       o.Version,
@@ -253,8 +361,12 @@ func (o *OpenVPN) copy() (copied OpenVPN) {
 		Key:           helpers.CopyPointer(o.Key),
 		EncryptedKey:  helpers.CopyPointer(o.EncryptedKey),
 		KeyPassphrase: helpers.CopyPointer(o.KeyPassphrase),
+		TLSCryptV2:    helpers.CopyPointer(o.TLSCryptV2),
+		StaticKey:     helpers.CopyPointer(o.StaticKey),
 		PIAEncPreset:  helpers.CopyPointer(o.PIAEncPreset),
 		MSSFix:        helpers.CopyPointer(o.MSSFix),
+		Fragment:      helpers.CopyPointer(o.Fragment),
+		TunMTU:        helpers.CopyPointer(o.TunMTU),
 		Interface:     o.Interface,
 		ProcessUser:   o.ProcessUser,
 		Verbosity:     helpers.CopyPointer(o.Verbosity),
@@ -275,8 +387,12 @@ func (o *OpenVPN) mergeWith(other OpenVPN) {
 	o.Key = helpers.MergeWithPointer(o.Key, other.Key)
 	o.EncryptedKey = helpers.MergeWithPointer(o.EncryptedKey, other.EncryptedKey)
 	o.KeyPassphrase = helpers.MergeWithPointer(o.KeyPassphrase, other.KeyPassphrase)
+	o.TLSCryptV2 = helpers.MergeWithPointer(o.TLSCryptV2, other.TLSCryptV2)
+	o.StaticKey = helpers.MergeWithPointer(o.StaticKey, other.StaticKey)
 	o.PIAEncPreset = helpers.MergeWithPointer(o.PIAEncPreset, other.PIAEncPreset)
 	o.MSSFix = helpers.MergeWithPointer(o.MSSFix, other.MSSFix)
+	o.Fragment = helpers.MergeWithPointer(o.Fragment, other.Fragment)
+	o.TunMTU = helpers.MergeWithPointer(o.TunMTU, other.TunMTU)
 	o.Interface = helpers.MergeWithString(o.Interface, other.Interface)
 	o.ProcessUser = helpers.MergeWithString(o.ProcessUser, other.ProcessUser)
 	o.Verbosity = helpers.MergeWithPointer(o.Verbosity, other.Verbosity)
@@ -297,8 +413,12 @@ func (o *OpenVPN) overrideWith(other OpenVPN) {
 	o.Key = helpers.OverrideWithPointer(o.Key, other.Key)
 	o.EncryptedKey = helpers.OverrideWithPointer(o.EncryptedKey, other.EncryptedKey)
 	o.KeyPassphrase = helpers.OverrideWithPointer(o.KeyPassphrase, other.KeyPassphrase)
+	o.TLSCryptV2 = helpers.OverrideWithPointer(o.TLSCryptV2, other.TLSCryptV2)
+	o.StaticKey = helpers.OverrideWithPointer(o.StaticKey, other.StaticKey)
 	o.PIAEncPreset = helpers.OverrideWithPointer(o.PIAEncPreset, other.PIAEncPreset)
 	o.MSSFix = helpers.OverrideWithPointer(o.MSSFix, other.MSSFix)
+	o.Fragment = helpers.OverrideWithPointer(o.Fragment, other.Fragment)
+	o.TunMTU = helpers.OverrideWithPointer(o.TunMTU, other.TunMTU)
 	o.Interface = helpers.OverrideWithString(o.Interface, other.Interface)
 	o.ProcessUser = helpers.OverrideWithString(o.ProcessUser, other.ProcessUser)
 	o.Verbosity = helpers.OverrideWithPointer(o.Verbosity, other.Verbosity)
@@ -320,6 +440,8 @@ func (o *OpenVPN) setDefaults(vpnProvider string) {
 	o.Key = helpers.DefaultPointer(o.Key, "")
 	o.EncryptedKey = helpers.DefaultPointer(o.EncryptedKey, "")
 	o.KeyPassphrase = helpers.DefaultPointer(o.KeyPassphrase, "")
+	o.TLSCryptV2 = helpers.DefaultPointer(o.TLSCryptV2, "")
+	o.StaticKey = helpers.DefaultPointer(o.StaticKey, "")
 
 	var defaultEncPreset string
 	if vpnProvider == providers.PrivateInternetAccess {
@@ -327,6 +449,8 @@ func (o *OpenVPN) setDefaults(vpnProvider string) {
 	}
 	o.PIAEncPreset = helpers.DefaultPointer(o.PIAEncPreset, defaultEncPreset)
 	o.MSSFix = helpers.DefaultPointer(o.MSSFix, 0)
+	o.Fragment = helpers.DefaultPointer(o.Fragment, 0)
+	o.TunMTU = helpers.DefaultPointer(o.TunMTU, 0)
 	o.Interface = helpers.DefaultString(o.Interface, "tun0")
 	o.ProcessUser = helpers.DefaultString(o.ProcessUser, "root")
 	o.Verbosity = helpers.DefaultPointer(o.Verbosity, 1)
@@ -367,6 +491,14 @@ func (o OpenVPN) toLinesNode() (node *gotree.Node) {
 			helpers.ObfuscateData(*o.EncryptedKey), helpers.ObfuscatePassword(*o.KeyPassphrase))
 	}
 
+	if *o.TLSCryptV2 != "" {
+		node.Appendf("tls-crypt-v2 client key: %s", helpers.ObfuscateData(*o.TLSCryptV2))
+	}
+
+	if *o.StaticKey != "" {
+		node.Appendf("Static key: %s", helpers.ObfuscateData(*o.StaticKey))
+	}
+
 	if *o.PIAEncPreset != "" {
 		node.Appendf("Private Internet Access encryption preset: %s", *o.PIAEncPreset)
 	}
@@ -375,6 +507,14 @@ func (o OpenVPN) toLinesNode() (node *gotree.Node) {
 		node.Appendf("MSS Fix: %d", *o.MSSFix)
 	}
 
+	if *o.Fragment > 0 {
+		node.Appendf("Fragment: %d", *o.Fragment)
+	}
+
+	if *o.TunMTU > 0 {
+		node.Appendf("TUN MTU: %d", *o.TunMTU)
+	}
+
 	if o.Interface != "" {
 		node.Appendf("Network interface: %s", o.Interface)
 	}
@@ -1,9 +1,14 @@
 package settings
 
 import (
+	"encoding/base64"
+	"strings"
 	"testing"
 
+	"github.com/qdm12/gluetun/internal/constants/openvpn"
+	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_ivpnAccountID(t *testing.T) {
@@ -42,3 +47,98 @@ func Test_ivpnAccountID(t *testing.T) {
 		})
 	}
 }
+
+func Test_OpenVPN_validate_version(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		version    string
+		provider   string
+		errWrapped error
+		errMessage string
+	}{
+		"invalid version": {
+			version:    "2.4",
+			provider:   providers.Mullvad,
+			errWrapped: ErrOpenVPNVersionIsNotValid,
+			errMessage: `version is not valid: "2.4" can only be one of 2.5, 2.6`,
+		},
+		"version not supported by provider": {
+			version:    openvpn.Openvpn26,
+			provider:   providers.Vyprvpn,
+			errWrapped: ErrOpenVPNVersionNotSupported,
+			errMessage: "version is not supported by the VPN provider: " +
+				"vyprvpn supports only version 2.5",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := OpenVPN{Version: testCase.version}
+
+			err := settings.validate(testCase.provider)
+
+			require.ErrorIs(t, err, testCase.errWrapped)
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
+
+func Test_validateOpenVPNStaticKeys(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		validate   func(key string) error
+		key        string
+		errWrapped error
+		errMessage string
+	}{
+		"empty static key": {
+			validate: validateOpenVPNStaticKey,
+		},
+		"valid static key": {
+			validate: validateOpenVPNStaticKey,
+			key:      strings.Repeat("ab", 256),
+		},
+		"static key not hex": {
+			validate:   validateOpenVPNStaticKey,
+			key:        "xyz",
+			errWrapped: ErrOpenVPNStaticKeyNotValid,
+			errMessage: "static key is not valid: encoding/hex: invalid byte: U+0078 'x'",
+		},
+		"static key too short": {
+			validate:   validateOpenVPNStaticKey,
+			key:        "abcd",
+			errWrapped: ErrOpenVPNStaticKeyNotValid,
+			errMessage: "static key is not valid: 2 bytes instead of 256 bytes",
+		},
+		"valid tls-crypt-v2 key": {
+			validate: validateOpenVPNTLSCryptV2Key,
+			key:      base64.StdEncoding.EncodeToString(make([]byte, 600)),
+		},
+		"tls-crypt-v2 key too short": {
+			validate:   validateOpenVPNTLSCryptV2Key,
+			key:        base64.StdEncoding.EncodeToString(make([]byte, 256)),
+			errWrapped: ErrOpenVPNTLSCryptV2KeyNotValid,
+			errMessage: "tls-crypt-v2 client key is not valid: " +
+				"256 bytes is less than the minimum of 546 bytes",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.validate(testCase.key)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
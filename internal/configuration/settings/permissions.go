@@ -0,0 +1,14 @@
+package settings
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+func validateFilePermissions(permissions fs.FileMode) (err error) {
+	if permissions&^fs.ModePerm != 0 {
+		return fmt.Errorf("%w: %04o has bits outside of %04o",
+			ErrFilePermissionsNotValid, uint32(permissions), uint32(fs.ModePerm))
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package settings
 
 import (
 	"fmt"
+	"io/fs"
+	"net/netip"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +22,25 @@ type PortForwarding struct {
 	// to write to a file. It cannot be nil for the
 	// internal state
 	Filepath *string
+	// FilePermissions are the permission bits of the
+	// port forwarding status file.
+	// It cannot be nil for the internal state.
+	FilePermissions *fs.FileMode
+	// Username is the account username to use for
+	// port forwarding, which is only needed for Windscribe.
+	// It cannot be nil for the internal state.
+	Username *string
+	// Password is the account password to use for
+	// port forwarding, which is only needed for Windscribe.
+	// It cannot be nil for the internal state.
+	Password *string
+	// DNATDestination is the IPv4 address and port to redirect
+	// traffic received on the forwarded port to, for example
+	// a container on another Docker network. Its port can be
+	// zero to use the forwarded port as destination port.
+	// Its address can be the zero value to disable redirection.
+	// It cannot be nil for the internal state.
+	DNATDestination *netip.AddrPort
 }
 
 func (p PortForwarding) validate(vpnProvider string) (err error) {
@@ -28,12 +49,29 @@ func (p PortForwarding) validate(vpnProvider string) (err error) {
 	}
 
 	// Validate Enabled
-	validProviders := []string{providers.PrivateInternetAccess}
+	validProviders := []string{
+		providers.PrivateInternetAccess,
+		providers.Windscribe,
+	}
 	if !helpers.IsOneOf(vpnProvider, validProviders...) {
 		return fmt.Errorf("%w: for provider %s, it is only available for %s",
 			ErrPortForwardingEnabled, vpnProvider, strings.Join(validProviders, ", "))
 	}
 
+	// Validate Username and Password
+	if vpnProvider == providers.Windscribe {
+		if *p.Username == "" {
+			return ErrPortForwardingUserEmpty
+		} else if *p.Password == "" {
+			return ErrPortForwardingPasswordEmpty
+		}
+	}
+
+	if p.DNATDestination.Addr().IsValid() && !p.DNATDestination.Addr().Is4() {
+		return fmt.Errorf("%w: %s must be an IPv4 address",
+			ErrPortForwardingDNATNotValid, p.DNATDestination.Addr())
+	}
+
 	// Validate Filepath
 	if *p.Filepath != "" { // optional
 		_, err := filepath.Abs(*p.Filepath)
@@ -42,29 +80,51 @@ func (p PortForwarding) validate(vpnProvider string) (err error) {
 		}
 	}
 
+	err = validateFilePermissions(*p.FilePermissions)
+	if err != nil {
+		return fmt.Errorf("file permissions: %w", err)
+	}
+
 	return nil
 }
 
 func (p *PortForwarding) copy() (copied PortForwarding) {
 	return PortForwarding{
-		Enabled:  helpers.CopyPointer(p.Enabled),
-		Filepath: helpers.CopyPointer(p.Filepath),
+		Enabled:         helpers.CopyPointer(p.Enabled),
+		Filepath:        helpers.CopyPointer(p.Filepath),
+		FilePermissions: helpers.CopyPointer(p.FilePermissions),
+		Username:        helpers.CopyPointer(p.Username),
+		Password:        helpers.CopyPointer(p.Password),
+		DNATDestination: helpers.CopyPointer(p.DNATDestination),
 	}
 }
 
 func (p *PortForwarding) mergeWith(other PortForwarding) {
 	p.Enabled = helpers.MergeWithPointer(p.Enabled, other.Enabled)
 	p.Filepath = helpers.MergeWithPointer(p.Filepath, other.Filepath)
+	p.FilePermissions = helpers.MergeWithPointer(p.FilePermissions, other.FilePermissions)
+	p.Username = helpers.MergeWithPointer(p.Username, other.Username)
+	p.Password = helpers.MergeWithPointer(p.Password, other.Password)
+	p.DNATDestination = helpers.MergeWithPointer(p.DNATDestination, other.DNATDestination)
 }
 
 func (p *PortForwarding) overrideWith(other PortForwarding) {
 	p.Enabled = helpers.OverrideWithPointer(p.Enabled, other.Enabled)
 	p.Filepath = helpers.OverrideWithPointer(p.Filepath, other.Filepath)
+	p.FilePermissions = helpers.OverrideWithPointer(p.FilePermissions, other.FilePermissions)
+	p.Username = helpers.OverrideWithPointer(p.Username, other.Username)
+	p.Password = helpers.OverrideWithPointer(p.Password, other.Password)
+	p.DNATDestination = helpers.OverrideWithPointer(p.DNATDestination, other.DNATDestination)
 }
 
-func (p *PortForwarding) setDefaults() {
+func (p *PortForwarding) setDefaults(runtimeDirectory string) {
 	p.Enabled = helpers.DefaultPointer(p.Enabled, false)
-	p.Filepath = helpers.DefaultPointer(p.Filepath, "/tmp/gluetun/forwarded_port")
+	p.Filepath = helpers.DefaultPointer(p.Filepath, filepath.Join(runtimeDirectory, "forwarded_port"))
+	const defaultFilePermissions = 0644
+	p.FilePermissions = helpers.DefaultPointer(p.FilePermissions, defaultFilePermissions)
+	p.Username = helpers.DefaultPointer(p.Username, "")
+	p.Password = helpers.DefaultPointer(p.Password, "")
+	p.DNATDestination = helpers.DefaultPointer(p.DNATDestination, netip.AddrPort{})
 }
 
 func (p PortForwarding) String() string {
@@ -84,6 +144,20 @@ func (p PortForwarding) toLinesNode() (node *gotree.Node) {
 		filepath = "[not set]"
 	}
 	node.Appendf("Forwarded port file path: %s", filepath)
+	if *p.Filepath != "" {
+		node.Appendf("Forwarded port file permissions: %04o", uint32(*p.FilePermissions))
+	}
+	if *p.Username != "" {
+		node.Appendf("Username: %s", *p.Username)
+		node.Appendf("Password: %s", helpers.ObfuscatePassword(*p.Password))
+	}
+	if p.DNATDestination.Addr().IsValid() {
+		destination := p.DNATDestination.Addr().String()
+		if p.DNATDestination.Port() != 0 {
+			destination = p.DNATDestination.String()
+		}
+		node.Appendf("DNAT destination: %s", destination)
+	}
 
 	return node
 }
@@ -2,6 +2,7 @@ package settings
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gluetun/internal/constants/providers"
@@ -19,6 +20,10 @@ type Provider struct {
 	ServerSelection ServerSelection
 	// PortForwarding is the settings about port forwarding.
 	PortForwarding PortForwarding
+	// CredentialsCheck is true if the credentials should be
+	// checked against the VPN provider API before connecting.
+	// It cannot be nil in the internal state.
+	CredentialsCheck *bool
 }
 
 // TODO v4 remove pointer for receiver (because of Surfshark).
@@ -26,17 +31,17 @@ func (p *Provider) validate(vpnType string, storage Storage) (err error) {
 	// Validate Name
 	var validNames []string
 	if vpnType == vpn.OpenVPN {
-		validNames = providers.AllWithCustom()
+		allNames := providers.AllWithCustom()
+		validNames = make([]string, 0, len(allNames)+1)
+		for _, name := range allNames {
+			if name == providers.Ovpn {
+				continue // only supports Wireguard
+			}
+			validNames = append(validNames, name)
+		}
 		validNames = append(validNames, "pia") // Retro-compatibility
 	} else { // Wireguard
-		validNames = []string{
-			providers.Airvpn,
-			providers.Custom,
-			providers.Ivpn,
-			providers.Mullvad,
-			providers.Surfshark,
-			providers.Windscribe,
-		}
+		validNames = wireguardProviderNames()
 	}
 	if !helpers.IsOneOf(*p.Name, validNames...) {
 		return fmt.Errorf("%w for Wireguard: %q can only be one of %s",
@@ -53,14 +58,43 @@ func (p *Provider) validate(vpnType string, storage Storage) (err error) {
 		return fmt.Errorf("port forwarding: %w", err)
 	}
 
+	if *p.CredentialsCheck {
+		validProviders := []string{
+			providers.Mullvad,
+			providers.Nordvpn,
+			providers.PrivateInternetAccess,
+		}
+		if !helpers.IsOneOf(*p.Name, validProviders...) {
+			return fmt.Errorf("%w: for provider %s, it is only available for %s",
+				ErrCredentialsCheckEnabled, *p.Name, strings.Join(validProviders, ", "))
+		}
+	}
+
 	return nil
 }
 
+// wireguardProviderNames returns the names of the
+// VPN service providers supporting Wireguard.
+func wireguardProviderNames() (names []string) {
+	return []string{
+		providers.Airvpn,
+		providers.Custom,
+		providers.Ivpn,
+		providers.Mullvad,
+		providers.Nordvpn,
+		providers.Ovpn,
+		providers.Protonvpn,
+		providers.Surfshark,
+		providers.Windscribe,
+	}
+}
+
 func (p *Provider) copy() (copied Provider) {
 	return Provider{
-		Name:            helpers.CopyPointer(p.Name),
-		ServerSelection: p.ServerSelection.copy(),
-		PortForwarding:  p.PortForwarding.copy(),
+		Name:             helpers.CopyPointer(p.Name),
+		ServerSelection:  p.ServerSelection.copy(),
+		PortForwarding:   p.PortForwarding.copy(),
+		CredentialsCheck: helpers.CopyPointer(p.CredentialsCheck),
 	}
 }
 
@@ -68,18 +102,21 @@ func (p *Provider) mergeWith(other Provider) {
 	p.Name = helpers.MergeWithPointer(p.Name, other.Name)
 	p.ServerSelection.mergeWith(other.ServerSelection)
 	p.PortForwarding.mergeWith(other.PortForwarding)
+	p.CredentialsCheck = helpers.MergeWithPointer(p.CredentialsCheck, other.CredentialsCheck)
 }
 
 func (p *Provider) overrideWith(other Provider) {
 	p.Name = helpers.OverrideWithPointer(p.Name, other.Name)
 	p.ServerSelection.overrideWith(other.ServerSelection)
 	p.PortForwarding.overrideWith(other.PortForwarding)
+	p.CredentialsCheck = helpers.OverrideWithPointer(p.CredentialsCheck, other.CredentialsCheck)
 }
 
-func (p *Provider) setDefaults() {
+func (p *Provider) setDefaults(runtimeDirectory string) {
 	p.Name = helpers.DefaultPointer(p.Name, providers.PrivateInternetAccess)
 	p.ServerSelection.setDefaults(*p.Name)
-	p.PortForwarding.setDefaults()
+	p.PortForwarding.setDefaults(runtimeDirectory)
+	p.CredentialsCheck = helpers.DefaultPointer(p.CredentialsCheck, false)
 }
 
 func (p Provider) String() string {
@@ -91,5 +128,8 @@ func (p Provider) toLinesNode() (node *gotree.Node) {
 	node.Appendf("Name: %s", *p.Name)
 	node.AppendNode(p.ServerSelection.toLinesNode())
 	node.AppendNode(p.PortForwarding.toLinesNode())
+	if *p.CredentialsCheck {
+		node.Appendf("Credentials check: yes")
+	}
 	return node
 }
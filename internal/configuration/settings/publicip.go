@@ -2,6 +2,7 @@ package settings
 
 import (
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"time"
 
@@ -21,6 +22,26 @@ type PublicIP struct {
 	// to write to a file. It cannot be nil for the
 	// internal state
 	IPFilepath *string
+	// IPFilePermissions are the permission bits of
+	// the public IP address status file.
+	// It cannot be nil for the internal state.
+	IPFilePermissions *fs.FileMode
+	// Timeout is the timeout for each attempt to fetch
+	// the public IP address information.
+	// It cannot be nil for the internal state.
+	Timeout *time.Duration
+	// Retries is the number of times to retry fetching
+	// the public IP address information before reporting
+	// an error, for example while routes settle after a
+	// VPN reconnection. It cannot be nil for the internal state.
+	Retries *uint8
+	// Jitter is the maximum random delay to wait before
+	// each attempt to fetch the public IP address information.
+	// It can be set to 0 to disable it.
+	// It cannot be nil for the internal state.
+	Jitter *time.Duration
+	// API contains settings to use a custom public IP echo API.
+	API PublicIPAPI
 }
 
 func (p PublicIP) validate() (err error) {
@@ -37,30 +58,74 @@ func (p PublicIP) validate() (err error) {
 		}
 	}
 
+	err = validateFilePermissions(*p.IPFilePermissions)
+	if err != nil {
+		return fmt.Errorf("IP file permissions: %w", err)
+	}
+
+	const minTimeout = time.Second
+	if *p.Timeout < minTimeout {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrPublicIPTimeoutTooShort, p.Timeout, minTimeout)
+	}
+
+	if *p.Jitter < 0 {
+		return fmt.Errorf("%w: %s must be positive",
+			ErrPublicIPJitterNotValid, p.Jitter)
+	}
+
+	err = p.API.validate()
+	if err != nil {
+		return fmt.Errorf("API: %w", err)
+	}
+
 	return nil
 }
 
 func (p *PublicIP) copy() (copied PublicIP) {
 	return PublicIP{
-		Period:     helpers.CopyPointer(p.Period),
-		IPFilepath: helpers.CopyPointer(p.IPFilepath),
+		Period:            helpers.CopyPointer(p.Period),
+		IPFilepath:        helpers.CopyPointer(p.IPFilepath),
+		IPFilePermissions: helpers.CopyPointer(p.IPFilePermissions),
+		Timeout:           helpers.CopyPointer(p.Timeout),
+		Retries:           helpers.CopyPointer(p.Retries),
+		Jitter:            helpers.CopyPointer(p.Jitter),
+		API:               p.API.copy(),
 	}
 }
 
 func (p *PublicIP) mergeWith(other PublicIP) {
 	p.Period = helpers.MergeWithPointer(p.Period, other.Period)
 	p.IPFilepath = helpers.MergeWithPointer(p.IPFilepath, other.IPFilepath)
+	p.IPFilePermissions = helpers.MergeWithPointer(p.IPFilePermissions, other.IPFilePermissions)
+	p.Timeout = helpers.MergeWithPointer(p.Timeout, other.Timeout)
+	p.Retries = helpers.MergeWithPointer(p.Retries, other.Retries)
+	p.Jitter = helpers.MergeWithPointer(p.Jitter, other.Jitter)
+	p.API.mergeWith(other.API)
 }
 
 func (p *PublicIP) overrideWith(other PublicIP) {
 	p.Period = helpers.OverrideWithPointer(p.Period, other.Period)
 	p.IPFilepath = helpers.OverrideWithPointer(p.IPFilepath, other.IPFilepath)
+	p.IPFilePermissions = helpers.OverrideWithPointer(p.IPFilePermissions, other.IPFilePermissions)
+	p.Timeout = helpers.OverrideWithPointer(p.Timeout, other.Timeout)
+	p.Retries = helpers.OverrideWithPointer(p.Retries, other.Retries)
+	p.Jitter = helpers.OverrideWithPointer(p.Jitter, other.Jitter)
+	p.API.overrideWith(other.API)
 }
 
-func (p *PublicIP) setDefaults() {
+func (p *PublicIP) setDefaults(runtimeDirectory string) {
 	const defaultPeriod = 12 * time.Hour
 	p.Period = helpers.DefaultPointer(p.Period, defaultPeriod)
-	p.IPFilepath = helpers.DefaultPointer(p.IPFilepath, "/tmp/gluetun/ip")
+	p.IPFilepath = helpers.DefaultPointer(p.IPFilepath, filepath.Join(runtimeDirectory, "ip"))
+	const defaultIPFilePermissions = 0644
+	p.IPFilePermissions = helpers.DefaultPointer(p.IPFilePermissions, defaultIPFilePermissions)
+	const defaultTimeout = 10 * time.Second
+	p.Timeout = helpers.DefaultPointer(p.Timeout, defaultTimeout)
+	const defaultRetries = 2
+	p.Retries = helpers.DefaultPointer(p.Retries, defaultRetries)
+	p.Jitter = helpers.DefaultPointer(p.Jitter, 0)
+	p.API.setDefaults()
 }
 
 func (p PublicIP) String() string {
@@ -80,9 +145,18 @@ func (p PublicIP) toLinesNode() (node *gotree.Node) {
 		updatePeriod = "every " + p.Period.String()
 	}
 	node.Appendf("Fetching: %s", updatePeriod)
+	node.Appendf("Fetch timeout: %s", *p.Timeout)
+	node.Appendf("Fetch retries: %d", *p.Retries)
+	if *p.Jitter > 0 {
+		node.Appendf("Fetch jitter: up to %s", *p.Jitter)
+	}
+	if *p.API.URL != "" {
+		node.AppendNode(p.API.toLinesNode())
+	}
 
 	if *p.IPFilepath != "" {
 		node.Appendf("IP file path: %s", *p.IPFilepath)
+		node.Appendf("IP file permissions: %04o", uint32(*p.IPFilePermissions))
 	}
 
 	return node
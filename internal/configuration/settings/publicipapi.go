@@ -0,0 +1,101 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// PublicIPAPI contains settings to fetch the public IP address
+// information from a custom echo API instead of ipinfo.io,
+// for example a self-hosted ipify or echoip instance.
+type PublicIPAPI struct {
+	// URL is the URL of the echo API. The API can answer with
+	// the IP address in plain text, or with a JSON object
+	// containing the keys set below. It can be the empty string
+	// to use ipinfo.io, and cannot be nil in the internal state.
+	URL *string
+	// IPKey is the JSON key holding the IP address.
+	// Nested keys are separated by dots, for example
+	// 'location.city'. It defaults to 'ip' and cannot
+	// be the empty string in the internal state.
+	IPKey string
+	// CountryKey is the JSON key holding the country name
+	// or two letters country code. It defaults to 'country'
+	// and cannot be the empty string in the internal state.
+	CountryKey string
+	// RegionKey is the JSON key holding the region.
+	// It defaults to 'region' and cannot be the empty string
+	// in the internal state.
+	RegionKey string
+	// CityKey is the JSON key holding the city.
+	// It defaults to 'city' and cannot be the empty string
+	// in the internal state.
+	CityKey string
+}
+
+func (p PublicIPAPI) validate() (err error) {
+	if *p.URL == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(*p.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPublicIPAPIURLNotValid, err)
+	} else if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q must be http or https",
+			ErrPublicIPAPIURLNotValid, parsed.Scheme)
+	}
+
+	return nil
+}
+
+func (p *PublicIPAPI) copy() (copied PublicIPAPI) {
+	return PublicIPAPI{
+		URL:        helpers.CopyPointer(p.URL),
+		IPKey:      p.IPKey,
+		CountryKey: p.CountryKey,
+		RegionKey:  p.RegionKey,
+		CityKey:    p.CityKey,
+	}
+}
+
+func (p *PublicIPAPI) mergeWith(other PublicIPAPI) {
+	p.URL = helpers.MergeWithPointer(p.URL, other.URL)
+	p.IPKey = helpers.MergeWithString(p.IPKey, other.IPKey)
+	p.CountryKey = helpers.MergeWithString(p.CountryKey, other.CountryKey)
+	p.RegionKey = helpers.MergeWithString(p.RegionKey, other.RegionKey)
+	p.CityKey = helpers.MergeWithString(p.CityKey, other.CityKey)
+}
+
+func (p *PublicIPAPI) overrideWith(other PublicIPAPI) {
+	p.URL = helpers.OverrideWithPointer(p.URL, other.URL)
+	p.IPKey = helpers.OverrideWithString(p.IPKey, other.IPKey)
+	p.CountryKey = helpers.OverrideWithString(p.CountryKey, other.CountryKey)
+	p.RegionKey = helpers.OverrideWithString(p.RegionKey, other.RegionKey)
+	p.CityKey = helpers.OverrideWithString(p.CityKey, other.CityKey)
+}
+
+func (p *PublicIPAPI) setDefaults() {
+	p.URL = helpers.DefaultPointer(p.URL, "")
+	p.IPKey = helpers.DefaultString(p.IPKey, "ip")
+	p.CountryKey = helpers.DefaultString(p.CountryKey, "country")
+	p.RegionKey = helpers.DefaultString(p.RegionKey, "region")
+	p.CityKey = helpers.DefaultString(p.CityKey, "city")
+}
+
+func (p PublicIPAPI) String() string {
+	return p.toLinesNode().String()
+}
+
+func (p PublicIPAPI) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("API: %s", *p.URL)
+	keysNode := node.Appendf("JSON keys:")
+	keysNode.Appendf("IP: %s", p.IPKey)
+	keysNode.Appendf("Country: %s", p.CountryKey)
+	keysNode.Appendf("Region: %s", p.RegionKey)
+	keysNode.Appendf("City: %s", p.CityKey)
+	return node
+}
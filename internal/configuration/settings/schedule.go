@@ -0,0 +1,155 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Schedule contains settings to define time windows during
+// which the VPN is connected, the VPN being intentionally
+// down outside of these windows.
+type Schedule struct {
+	// Windows are the time windows during which the VPN
+	// should be connected. If it is empty, the VPN is
+	// always connected.
+	Windows []ScheduleWindow
+	// KillSwitch is the kill switch mode to use while
+	// the VPN is down outside of the windows, and can be
+	// "allow_lan" to only allow traffic to local networks, or
+	// "block_all" to block all outbound traffic.
+	// It cannot be the empty string in the internal state.
+	KillSwitch string
+}
+
+// ScheduleWindow is a daily time window, in the
+// timezone of the program.
+type ScheduleWindow struct {
+	// Weekdays are the days the window starts on.
+	// If it is empty, the window applies every day.
+	Weekdays []time.Weekday
+	// Start is the start time of the window, as a
+	// duration since midnight.
+	Start time.Duration
+	// End is the end time of the window, as a duration
+	// since midnight. It can be before the start time for
+	// a window spanning midnight, and can be 24h for
+	// a window ending at midnight.
+	End time.Duration
+}
+
+func (s Schedule) validate() (err error) {
+	for i, window := range s.Windows {
+		const day = 24 * time.Hour
+		switch {
+		case window.Start < 0 || window.Start >= day,
+			window.End <= 0 || window.End > day,
+			window.Start == window.End:
+			return fmt.Errorf("%w: window at index %d: %s",
+				ErrScheduleWindowNotValid, i, window)
+		}
+	}
+
+	if !helpers.IsOneOf(s.KillSwitch, "allow_lan", "block_all") {
+		return fmt.Errorf("%w: %s", ErrFirewallKillSwitchNotValid, s.KillSwitch)
+	}
+
+	return nil
+}
+
+// Contains returns true if the time given is within the window.
+func (w ScheduleWindow) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	sinceMidnight := t.Sub(midnight)
+	if w.Start < w.End {
+		return w.startsOn(t.Weekday()) &&
+			sinceMidnight >= w.Start && sinceMidnight < w.End
+	}
+	// Window spanning midnight
+	previousDay := midnight.AddDate(0, 0, -1).Weekday()
+	return (w.startsOn(t.Weekday()) && sinceMidnight >= w.Start) ||
+		(w.startsOn(previousDay) && sinceMidnight < w.End)
+}
+
+func (w ScheduleWindow) startsOn(weekday time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, day := range w.Weekdays {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (w ScheduleWindow) String() string {
+	s := formatTimeOfDay(w.Start) + "-" + formatTimeOfDay(w.End)
+	if len(w.Weekdays) == 0 {
+		return s
+	}
+	days := make([]string, len(w.Weekdays))
+	for i, day := range w.Weekdays {
+		const abbreviationLength = 3
+		days[i] = strings.ToLower(day.String()[:abbreviationLength])
+	}
+	return strings.Join(days, "+") + " " + s
+}
+
+func formatTimeOfDay(duration time.Duration) string {
+	hours := duration / time.Hour
+	minutes := (duration % time.Hour) / time.Minute
+	return fmt.Sprintf("%02d:%02d", hours, minutes)
+}
+
+func (s *Schedule) copy() (copied Schedule) {
+	copied = Schedule{
+		KillSwitch: s.KillSwitch,
+	}
+	if s.Windows != nil {
+		copied.Windows = make([]ScheduleWindow, len(s.Windows))
+		for i, window := range s.Windows {
+			copied.Windows[i] = ScheduleWindow{
+				Weekdays: append([]time.Weekday(nil), window.Weekdays...),
+				Start:    window.Start,
+				End:      window.End,
+			}
+		}
+	}
+	return copied
+}
+
+func (s *Schedule) mergeWith(other Schedule) {
+	if s.Windows == nil {
+		s.Windows = other.Windows
+	}
+	s.KillSwitch = helpers.MergeWithString(s.KillSwitch, other.KillSwitch)
+}
+
+func (s *Schedule) overrideWith(other Schedule) {
+	if other.Windows != nil {
+		s.Windows = other.Windows
+	}
+	s.KillSwitch = helpers.OverrideWithString(s.KillSwitch, other.KillSwitch)
+}
+
+func (s *Schedule) setDefaults() {
+	s.KillSwitch = helpers.DefaultString(s.KillSwitch, "allow_lan")
+}
+
+func (s Schedule) String() string {
+	return s.toLinesNode().String()
+}
+
+func (s Schedule) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Schedule settings:")
+	windowsNode := node.Appendf("Connected windows:")
+	for _, window := range s.Windows {
+		windowsNode.Appendf(window.String())
+	}
+	node.Appendf("Kill switch outside windows: %s", s.KillSwitch)
+	return node
+}
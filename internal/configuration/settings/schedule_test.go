@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScheduleWindow_Contains(t *testing.T) {
+	t.Parallel()
+
+	// 2024-01-01 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tuesday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		window   ScheduleWindow
+		t        time.Time
+		contains bool
+	}{
+		"every day inside": {
+			window:   ScheduleWindow{Start: 8 * time.Hour, End: 18 * time.Hour},
+			t:        monday(8, 0),
+			contains: true,
+		},
+		"every day at end": {
+			window: ScheduleWindow{Start: 8 * time.Hour, End: 18 * time.Hour},
+			t:      monday(18, 0),
+		},
+		"other weekday": {
+			window: ScheduleWindow{
+				Weekdays: []time.Weekday{time.Tuesday},
+				Start:    8 * time.Hour,
+				End:      18 * time.Hour,
+			},
+			t: monday(12, 0),
+		},
+		"until midnight": {
+			window:   ScheduleWindow{Start: 20 * time.Hour, End: 24 * time.Hour},
+			t:        monday(23, 59),
+			contains: true,
+		},
+		"spanning midnight before midnight": {
+			window: ScheduleWindow{
+				Weekdays: []time.Weekday{time.Monday},
+				Start:    22 * time.Hour,
+				End:      6 * time.Hour,
+			},
+			t:        monday(23, 0),
+			contains: true,
+		},
+		"spanning midnight after midnight": {
+			window: ScheduleWindow{
+				Weekdays: []time.Weekday{time.Monday},
+				Start:    22 * time.Hour,
+				End:      6 * time.Hour,
+			},
+			t:        tuesday(5, 59),
+			contains: true,
+		},
+		"spanning midnight started previous week day": {
+			window: ScheduleWindow{
+				Weekdays: []time.Weekday{time.Tuesday},
+				Start:    22 * time.Hour,
+				End:      6 * time.Hour,
+			},
+			t: tuesday(5, 0),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			contains := testCase.window.Contains(testCase.t)
+
+			assert.Equal(t, testCase.contains, contains)
+		})
+	}
+}
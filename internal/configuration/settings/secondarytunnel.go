@@ -0,0 +1,217 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gotree"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// SecondaryTunnel contains settings to configure a second
+// Wireguard tunnel running alongside the main Wireguard tunnel,
+// through which traffic to specific destination ports or
+// from specific source networks is steered.
+type SecondaryTunnel struct {
+	// Enabled is true if the secondary tunnel should be run.
+	// It cannot be nil in the internal state.
+	Enabled *bool
+	// Provider is the VPN service provider of the secondary
+	// tunnel, which can differ from the main tunnel provider.
+	// It cannot be nil in the internal state, and defaults
+	// to the custom provider.
+	Provider *string
+	// ServerSelection contains settings to select the Wireguard
+	// server of the provider. For the custom provider, its
+	// Wireguard endpoint and public key must be set.
+	ServerSelection ServerSelection
+	// PrivateKey is the Wireguard client peer private key.
+	// It cannot be nil in the internal state.
+	PrivateKey *string
+	// PreSharedKey is the Wireguard pre-shared key.
+	// It can be the empty string to indicate there
+	// is no pre-shared key.
+	// It cannot be nil in the internal state.
+	PreSharedKey *string
+	// Addresses are the Wireguard interface addresses.
+	Addresses []netip.Prefix
+	// Interface is the name of the Wireguard interface
+	// to create. It cannot be the empty string in the
+	// internal state, and defaults to wg1.
+	Interface string
+	// DestinationPorts are the TCP and UDP destination
+	// ports for which traffic is sent through the
+	// secondary tunnel.
+	DestinationPorts []uint16
+	// SourceNetworks are the source IP networks, for example
+	// of connected containers, for which traffic is sent
+	// through the secondary tunnel.
+	SourceNetworks []netip.Prefix
+}
+
+// validate validates the secondary tunnel settings.
+// It should only be ran if the VPN type chosen is Wireguard.
+func (s SecondaryTunnel) validate(vpnInterface string, storage Storage,
+	ipv6Supported bool) (err error) {
+	if !*s.Enabled {
+		return nil
+	}
+
+	validNames := wireguardProviderNames()
+	if !helpers.IsOneOf(*s.Provider, validNames...) {
+		return fmt.Errorf("%w for Wireguard: %q can only be one of %s",
+			ErrVPNProviderNameNotValid, *s.Provider, helpers.ChoicesOrString(validNames))
+	}
+
+	err = s.ServerSelection.validate(*s.Provider, storage)
+	if err != nil {
+		return fmt.Errorf("server selection: %w", err)
+	}
+
+	_, err = wgtypes.ParseKey(*s.PrivateKey)
+	if err != nil {
+		if *s.PrivateKey == "" {
+			return fmt.Errorf("%w", ErrWireguardPrivateKeyNotSet)
+		}
+		return fmt.Errorf("private key is not valid: %w", err)
+	}
+
+	if *s.PreSharedKey != "" {
+		_, err = wgtypes.ParseKey(*s.PreSharedKey)
+		if err != nil {
+			return fmt.Errorf("pre-shared key is not valid: %w", err)
+		}
+	}
+
+	if len(s.Addresses) == 0 {
+		return fmt.Errorf("%w", ErrWireguardInterfaceAddressNotSet)
+	}
+	for i, address := range s.Addresses {
+		if !address.IsValid() {
+			return fmt.Errorf("%w: for address at index %d: %s",
+				ErrWireguardInterfaceAddressNotSet, i, address.String())
+		}
+
+		if !ipv6Supported && address.Addr().Is6() {
+			return fmt.Errorf("%w: address %s",
+				ErrWireguardInterfaceAddressIPv6, address)
+		}
+	}
+
+	if !regexpInterfaceName.MatchString(s.Interface) {
+		return fmt.Errorf("%w: '%s' does not match regex '%s'",
+			ErrWireguardInterfaceNotValid, s.Interface, regexpInterfaceName)
+	} else if s.Interface == vpnInterface {
+		return fmt.Errorf("%w: %s", ErrSecondaryInterfaceConflict, s.Interface)
+	}
+
+	if len(s.DestinationPorts) == 0 && len(s.SourceNetworks) == 0 {
+		return fmt.Errorf("%w", ErrSecondarySteeringNotSet)
+	}
+
+	for _, port := range s.DestinationPorts {
+		if port == 0 {
+			return fmt.Errorf("%w: in destination ports", ErrFirewallZeroPort)
+		}
+	}
+
+	return nil
+}
+
+func (s *SecondaryTunnel) copy() (copied SecondaryTunnel) {
+	return SecondaryTunnel{
+		Enabled:          helpers.CopyPointer(s.Enabled),
+		Provider:         helpers.CopyPointer(s.Provider),
+		ServerSelection:  s.ServerSelection.copy(),
+		PrivateKey:       helpers.CopyPointer(s.PrivateKey),
+		PreSharedKey:     helpers.CopyPointer(s.PreSharedKey),
+		Addresses:        helpers.CopySlice(s.Addresses),
+		Interface:        s.Interface,
+		DestinationPorts: helpers.CopySlice(s.DestinationPorts),
+		SourceNetworks:   helpers.CopySlice(s.SourceNetworks),
+	}
+}
+
+func (s *SecondaryTunnel) mergeWith(other SecondaryTunnel) {
+	s.Enabled = helpers.MergeWithPointer(s.Enabled, other.Enabled)
+	s.Provider = helpers.MergeWithPointer(s.Provider, other.Provider)
+	s.ServerSelection.mergeWith(other.ServerSelection)
+	s.PrivateKey = helpers.MergeWithPointer(s.PrivateKey, other.PrivateKey)
+	s.PreSharedKey = helpers.MergeWithPointer(s.PreSharedKey, other.PreSharedKey)
+	s.Addresses = helpers.MergeSlices(s.Addresses, other.Addresses)
+	s.Interface = helpers.MergeWithString(s.Interface, other.Interface)
+	s.DestinationPorts = helpers.MergeSlices(s.DestinationPorts, other.DestinationPorts)
+	s.SourceNetworks = helpers.MergeSlices(s.SourceNetworks, other.SourceNetworks)
+}
+
+func (s *SecondaryTunnel) overrideWith(other SecondaryTunnel) {
+	s.Enabled = helpers.OverrideWithPointer(s.Enabled, other.Enabled)
+	s.Provider = helpers.OverrideWithPointer(s.Provider, other.Provider)
+	s.ServerSelection.overrideWith(other.ServerSelection)
+	s.PrivateKey = helpers.OverrideWithPointer(s.PrivateKey, other.PrivateKey)
+	s.PreSharedKey = helpers.OverrideWithPointer(s.PreSharedKey, other.PreSharedKey)
+	s.Addresses = helpers.OverrideWithSlice(s.Addresses, other.Addresses)
+	s.Interface = helpers.OverrideWithString(s.Interface, other.Interface)
+	s.DestinationPorts = helpers.OverrideWithSlice(s.DestinationPorts, other.DestinationPorts)
+	s.SourceNetworks = helpers.OverrideWithSlice(s.SourceNetworks, other.SourceNetworks)
+}
+
+func (s *SecondaryTunnel) setDefaults() {
+	s.Enabled = helpers.DefaultPointer(s.Enabled, false)
+	s.Provider = helpers.DefaultPointer(s.Provider, providers.Custom)
+	s.ServerSelection.VPN = vpn.Wireguard
+	if *s.Provider == providers.Custom {
+		const defaultEndpointPort = 51820
+		s.ServerSelection.Wireguard.EndpointPort = helpers.DefaultPointer(
+			s.ServerSelection.Wireguard.EndpointPort, defaultEndpointPort)
+	}
+	s.ServerSelection.setDefaults(*s.Provider)
+	s.PrivateKey = helpers.DefaultPointer(s.PrivateKey, "")
+	s.PreSharedKey = helpers.DefaultPointer(s.PreSharedKey, "")
+	s.Interface = helpers.DefaultString(s.Interface, "wg1")
+}
+
+func (s SecondaryTunnel) String() string {
+	return s.toLinesNode().String()
+}
+
+func (s SecondaryTunnel) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Secondary tunnel settings:")
+
+	if !*s.Enabled {
+		node.Appendf("Enabled: no")
+		return node
+	}
+
+	node.Appendf("Provider: %s", *s.Provider)
+	node.AppendNode(s.ServerSelection.toLinesNode())
+	node.Appendf("Private key: %s", helpers.ObfuscateWireguardKey(*s.PrivateKey))
+	if *s.PreSharedKey != "" {
+		node.Appendf("Pre-shared key: %s", helpers.ObfuscateWireguardKey(*s.PreSharedKey))
+	}
+
+	addressesNode := node.Appendf("Interface addresses:")
+	for _, address := range s.Addresses {
+		addressesNode.Appendf(address.String())
+	}
+	node.Appendf("Network interface: %s", s.Interface)
+
+	if len(s.DestinationPorts) > 0 {
+		portsNode := node.Appendf("Destination ports:")
+		for _, port := range s.DestinationPorts {
+			portsNode.Appendf("%d", port)
+		}
+	}
+
+	if len(s.SourceNetworks) > 0 {
+		sourcesNode := node.Appendf("Source networks:")
+		for _, source := range s.SourceNetworks {
+			sourcesNode.Appendf(source.String())
+		}
+	}
+
+	return node
+}
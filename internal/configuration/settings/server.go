@@ -3,9 +3,9 @@ package settings
 import (
 	"fmt"
 	"net"
-	"os"
 	"strconv"
 
+	"github.com/qdm12/gluetun/internal/capabilities"
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
 )
@@ -18,6 +18,32 @@ type ControlServer struct {
 	// Log can be true or false to enable logging on requests.
 	// It cannot be nil in the internal state.
 	Log *bool
+	// AuditLogPath is the file path to append audit entries of
+	// state changing requests to, as JSON lines. It can be set to
+	// the empty string to only keep the last audit entries in memory.
+	// It cannot be nil in the internal state.
+	AuditLogPath *string
+	// ReadOnlyEnabled is true if the read-only routes, which are
+	// the routes not changing any state such as the status routes,
+	// can be used. It defaults to true and cannot be nil in the
+	// internal state.
+	ReadOnlyEnabled *bool
+	// ReadOnlyAPIKey is the key clients must set in the X-API-Key
+	// header to use the read-only routes. The admin API key is also
+	// accepted for these routes. It defaults to the empty string
+	// meaning no key is required, and cannot be nil in the internal state.
+	ReadOnlyAPIKey *string
+	// AdminEnabled is true if the admin routes, which are the routes
+	// changing state such as to restart the VPN or to change settings,
+	// can be used. It defaults to true and cannot be nil in the
+	// internal state.
+	AdminEnabled *bool
+	// AdminAPIKey is the key clients must set in the X-API-Key
+	// header to use the admin routes. It defaults to the empty string
+	// meaning no key is required, except for the system routes to stop
+	// or restart gluetun which are refused if it is empty.
+	// It cannot be nil in the internal state.
+	AdminAPIKey *string
 }
 
 func (c ControlServer) validate() (err error) {
@@ -31,7 +57,7 @@ func (c ControlServer) validate() (err error) {
 		return fmt.Errorf("listening port it not valid: %w", err)
 	}
 
-	uid := os.Getuid()
+	uid := capabilities.ListeningUID()
 	const maxPrivilegedPort = 1023
 	if uid != 0 && port != 0 && port <= maxPrivilegedPort {
 		return fmt.Errorf("%w: %d when running with user ID %d",
@@ -43,8 +69,13 @@ func (c ControlServer) validate() (err error) {
 
 func (c *ControlServer) copy() (copied ControlServer) {
 	return ControlServer{
-		Address: helpers.CopyPointer(c.Address),
-		Log:     helpers.CopyPointer(c.Log),
+		Address:         helpers.CopyPointer(c.Address),
+		Log:             helpers.CopyPointer(c.Log),
+		AuditLogPath:    helpers.CopyPointer(c.AuditLogPath),
+		ReadOnlyEnabled: helpers.CopyPointer(c.ReadOnlyEnabled),
+		ReadOnlyAPIKey:  helpers.CopyPointer(c.ReadOnlyAPIKey),
+		AdminEnabled:    helpers.CopyPointer(c.AdminEnabled),
+		AdminAPIKey:     helpers.CopyPointer(c.AdminAPIKey),
 	}
 }
 
@@ -53,6 +84,11 @@ func (c *ControlServer) copy() (copied ControlServer) {
 func (c *ControlServer) mergeWith(other ControlServer) {
 	c.Address = helpers.MergeWithPointer(c.Address, other.Address)
 	c.Log = helpers.MergeWithPointer(c.Log, other.Log)
+	c.AuditLogPath = helpers.MergeWithPointer(c.AuditLogPath, other.AuditLogPath)
+	c.ReadOnlyEnabled = helpers.MergeWithPointer(c.ReadOnlyEnabled, other.ReadOnlyEnabled)
+	c.ReadOnlyAPIKey = helpers.MergeWithPointer(c.ReadOnlyAPIKey, other.ReadOnlyAPIKey)
+	c.AdminEnabled = helpers.MergeWithPointer(c.AdminEnabled, other.AdminEnabled)
+	c.AdminAPIKey = helpers.MergeWithPointer(c.AdminAPIKey, other.AdminAPIKey)
 }
 
 // overrideWith overrides fields of the receiver
@@ -61,11 +97,21 @@ func (c *ControlServer) mergeWith(other ControlServer) {
 func (c *ControlServer) overrideWith(other ControlServer) {
 	c.Address = helpers.OverrideWithPointer(c.Address, other.Address)
 	c.Log = helpers.OverrideWithPointer(c.Log, other.Log)
+	c.AuditLogPath = helpers.OverrideWithPointer(c.AuditLogPath, other.AuditLogPath)
+	c.ReadOnlyEnabled = helpers.OverrideWithPointer(c.ReadOnlyEnabled, other.ReadOnlyEnabled)
+	c.ReadOnlyAPIKey = helpers.OverrideWithPointer(c.ReadOnlyAPIKey, other.ReadOnlyAPIKey)
+	c.AdminEnabled = helpers.OverrideWithPointer(c.AdminEnabled, other.AdminEnabled)
+	c.AdminAPIKey = helpers.OverrideWithPointer(c.AdminAPIKey, other.AdminAPIKey)
 }
 
 func (c *ControlServer) setDefaults() {
 	c.Address = helpers.DefaultPointer(c.Address, ":8000")
 	c.Log = helpers.DefaultPointer(c.Log, true)
+	c.AuditLogPath = helpers.DefaultPointer(c.AuditLogPath, "")
+	c.ReadOnlyEnabled = helpers.DefaultPointer(c.ReadOnlyEnabled, true)
+	c.ReadOnlyAPIKey = helpers.DefaultPointer(c.ReadOnlyAPIKey, "")
+	c.AdminEnabled = helpers.DefaultPointer(c.AdminEnabled, true)
+	c.AdminAPIKey = helpers.DefaultPointer(c.AdminAPIKey, "")
 }
 
 func (c ControlServer) String() string {
@@ -76,5 +122,18 @@ func (c ControlServer) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Control server settings:")
 	node.Appendf("Listening address: %s", *c.Address)
 	node.Appendf("Logging: %s", helpers.BoolPtrToYesNo(c.Log))
+	if *c.AuditLogPath != "" {
+		node.Appendf("Audit log file: %s", *c.AuditLogPath)
+	}
+	appendRouteGroupNode(node, "Read-only routes", *c.ReadOnlyEnabled, *c.ReadOnlyAPIKey)
+	appendRouteGroupNode(node, "Admin routes", *c.AdminEnabled, *c.AdminAPIKey)
 	return node
 }
+
+func appendRouteGroupNode(parent *gotree.Node, name string,
+	enabled bool, apiKey string) {
+	groupNode := parent.Appendf("%s: %s", name, helpers.BoolPtrToYesNo(&enabled))
+	if enabled && apiKey != "" {
+		groupNode.Appendf("API key: %s", helpers.ObfuscatePassword(apiKey))
+	}
+}
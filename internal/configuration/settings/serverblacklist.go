@@ -0,0 +1,61 @@
+package settings
+
+import (
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// ServerBlacklist contains settings to temporarily exclude
+// VPN servers failing repeatedly from the servers picked.
+type ServerBlacklist struct {
+	// Failures is the number of connection or health failures
+	// of a server, each within the cooldown period of the previous
+	// one, after which the server is blacklisted. It defaults to 3
+	// and can be set to 0 to disable blacklisting.
+	Failures *uint8
+	// Cooldown is the duration a server stays blacklisted.
+	// It defaults to 30 minutes and cannot be nil in the
+	// internal state.
+	Cooldown *time.Duration
+}
+
+func (s *ServerBlacklist) copy() (copied ServerBlacklist) {
+	return ServerBlacklist{
+		Failures: helpers.CopyPointer(s.Failures),
+		Cooldown: helpers.CopyPointer(s.Cooldown),
+	}
+}
+
+func (s *ServerBlacklist) mergeWith(other ServerBlacklist) {
+	s.Failures = helpers.MergeWithPointer(s.Failures, other.Failures)
+	s.Cooldown = helpers.MergeWithPointer(s.Cooldown, other.Cooldown)
+}
+
+func (s *ServerBlacklist) overrideWith(other ServerBlacklist) {
+	s.Failures = helpers.OverrideWithPointer(s.Failures, other.Failures)
+	s.Cooldown = helpers.OverrideWithPointer(s.Cooldown, other.Cooldown)
+}
+
+func (s *ServerBlacklist) setDefaults() {
+	const defaultFailures = 3
+	s.Failures = helpers.DefaultPointer(s.Failures, defaultFailures)
+	const defaultCooldown = 30 * time.Minute
+	s.Cooldown = helpers.DefaultPointer(s.Cooldown, defaultCooldown)
+}
+
+func (s ServerBlacklist) String() string {
+	return s.toLinesNode().String()
+}
+
+func (s ServerBlacklist) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Server blacklist settings:")
+	if *s.Failures == 0 {
+		node.Appendf("Enabled: no")
+		return node
+	}
+	node.Appendf("Failures before blacklisting: %d", *s.Failures)
+	node.Appendf("Cooldown: %s", *s.Cooldown)
+	return node
+}
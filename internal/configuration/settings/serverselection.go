@@ -8,9 +8,11 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gluetun/internal/configuration/settings/validation"
+	"github.com/qdm12/gluetun/internal/constants/features"
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/serverfilter"
 	"github.com/qdm12/gotree"
 )
 
@@ -25,6 +27,13 @@ type ServerSelection struct { //nolint:maligned
 	// state, and can be set to the unspecified address to indicate
 	// there is not target IP address to use.
 	TargetIP netip.Addr
+	// ServerIP is the IP address of the server to pin the
+	// connection to. Unlike TargetIP, it must match the IP address
+	// of a server from the servers data, whose hostname and
+	// Wireguard public key are used for the connection.
+	// It cannot be the empty value in the internal state, and can
+	// be set to the unspecified address to not pin a server.
+	ServerIP netip.Addr
 	// Counties is the list of countries to filter VPN servers with.
 	Countries []string
 	// Regions is the list of regions to filter VPN servers with.
@@ -39,6 +48,24 @@ type ServerSelection struct { //nolint:maligned
 	Numbers []uint16
 	// Hostnames is the list of hostnames to filter VPN servers with.
 	Hostnames []string
+	// ExcludedCountries is the list of countries to exclude VPN
+	// servers with, applied after the filters above.
+	ExcludedCountries []string
+	// ExcludedRegions is the list of regions to exclude VPN
+	// servers with, applied after the filters above.
+	ExcludedRegions []string
+	// ExcludedCities is the list of cities to exclude VPN
+	// servers with, applied after the filters above.
+	ExcludedCities []string
+	// ExcludedISPs is the list of ISP names to exclude VPN
+	// servers with, applied after the filters above.
+	ExcludedISPs []string
+	// ExcludedNames is the list of server names to exclude VPN
+	// servers with, applied after the filters above.
+	ExcludedNames []string
+	// ExcludedHostnames is the list of hostnames to exclude VPN
+	// servers with, applied after the filters above.
+	ExcludedHostnames []string
 	// OwnedOnly is true if VPN provider servers that are not owned
 	// should be filtered. This is used with Mullvad.
 	OwnedOnly *bool
@@ -55,6 +82,21 @@ type ServerSelection struct { //nolint:maligned
 	// MultiHopOnly is true if VPN servers that are not multihop
 	// should be filtered. This is used with Surfshark.
 	MultiHopOnly *bool
+	// PortForwardOnly is true if VPN servers that do not support
+	// port forwarding should be filtered. This is used with
+	// Private Internet Access.
+	PortForwardOnly *bool
+	// Features is the list of features VPN servers must all
+	// support, such as 'p2p', 'streaming' or 'stealth'.
+	Features []string
+	// Filter is an expression to filter VPN servers with,
+	// such as `country=="Netherlands" && number<30 && portforward`.
+	// Supported fields are the string fields vpn, country, region,
+	// city, isp, name and hostname, the number field number and the
+	// boolean fields owned, tcp, udp, multihop, free, premium, stream,
+	// p2p, stealth and portforward. Server load is not available.
+	// It can be the empty string to not filter servers.
+	Filter string
 
 	// OpenVPN contains settings to select OpenVPN servers
 	// and the final connection.
@@ -65,12 +107,15 @@ type ServerSelection struct { //nolint:maligned
 }
 
 var (
-	ErrOwnedOnlyNotSupported    = errors.New("owned only filter is not supported")
-	ErrFreeOnlyNotSupported     = errors.New("free only filter is not supported")
-	ErrPremiumOnlyNotSupported  = errors.New("premium only filter is not supported")
-	ErrStreamOnlyNotSupported   = errors.New("stream only filter is not supported")
-	ErrMultiHopOnlyNotSupported = errors.New("multi hop only filter is not supported")
-	ErrFreePremiumBothSet       = errors.New("free only and premium only filters are both set")
+	ErrOwnedOnlyNotSupported       = errors.New("owned only filter is not supported")
+	ErrFreeOnlyNotSupported        = errors.New("free only filter is not supported")
+	ErrPremiumOnlyNotSupported     = errors.New("premium only filter is not supported")
+	ErrStreamOnlyNotSupported      = errors.New("stream only filter is not supported")
+	ErrMultiHopOnlyNotSupported    = errors.New("multi hop only filter is not supported")
+	ErrPortForwardOnlyNotSupported = errors.New("port forward only filter is not supported")
+	ErrFreePremiumBothSet          = errors.New("free only and premium only filters are both set")
+	ErrFeatureNotValid             = errors.New("server feature is not valid")
+	ErrFilterNotValid              = errors.New("server filter expression is not valid")
 )
 
 func (ss *ServerSelection) validate(vpnServiceProvider string,
@@ -81,6 +126,21 @@ func (ss *ServerSelection) validate(vpnServiceProvider string,
 		return fmt.Errorf("%w: %s", ErrVPNTypeNotValid, ss.VPN)
 	}
 
+	if *ss.PortForwardOnly &&
+		vpnServiceProvider != providers.PrivateInternetAccess {
+		return fmt.Errorf("%w: for VPN service provider %s",
+			ErrPortForwardOnlyNotSupported, vpnServiceProvider)
+	}
+
+	if !ss.ServerIP.IsUnspecified() {
+		if !ss.TargetIP.IsUnspecified() {
+			return fmt.Errorf("%w", ErrServerIPTargetIPBothSet)
+		} else if vpnServiceProvider == providers.Custom {
+			return fmt.Errorf("%w: for VPN service provider %s",
+				ErrServerIPNotSupported, vpnServiceProvider)
+		}
+	}
+
 	filterChoices, err := getLocationFilterChoices(vpnServiceProvider, ss, storage)
 	if err != nil {
 		return err // already wrapped error
@@ -136,6 +196,18 @@ func (ss *ServerSelection) validate(vpnServiceProvider string,
 			ErrMultiHopOnlyNotSupported, vpnServiceProvider)
 	}
 
+	err = helpers.AreAllOneOf(ss.Features, features.All())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrFeatureNotValid, err)
+	}
+
+	if ss.Filter != "" {
+		_, err = serverfilter.Parse(ss.Filter)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrFilterNotValid, err)
+		}
+	}
+
 	if ss.VPN == vpn.OpenVPN {
 		err = ss.OpenVPN.validate(vpnServiceProvider)
 		if err != nil {
@@ -154,7 +226,13 @@ func (ss *ServerSelection) validate(vpnServiceProvider string,
 func getLocationFilterChoices(vpnServiceProvider string,
 	ss *ServerSelection, storage Storage) (filterChoices models.FilterChoices,
 	err error) {
-	filterChoices = storage.GetFilterChoices(vpnServiceProvider)
+	if *ss.PortForwardOnly {
+		// Only accept filter values matching servers
+		// supporting port forwarding, to fail early.
+		filterChoices = storage.GetPortForwardFilterChoices(vpnServiceProvider)
+	} else {
+		filterChoices = storage.GetFilterChoices(vpnServiceProvider)
+	}
 
 	if vpnServiceProvider == providers.Surfshark {
 		// // Retro compatibility
@@ -196,33 +274,68 @@ func validateServerFilters(settings ServerSelection, filterChoices models.Filter
 		return fmt.Errorf("%w: %s", ErrNameNotValid, err)
 	}
 
+	if err := helpers.AreAllOneOf(settings.ExcludedCountries, filterChoices.Countries); err != nil {
+		return fmt.Errorf("excluded countries: %w: %s", ErrCountryNotValid, err)
+	}
+
+	if err := helpers.AreAllOneOf(settings.ExcludedRegions, filterChoices.Regions); err != nil {
+		return fmt.Errorf("excluded regions: %w: %s", ErrRegionNotValid, err)
+	}
+
+	if err := helpers.AreAllOneOf(settings.ExcludedCities, filterChoices.Cities); err != nil {
+		return fmt.Errorf("excluded cities: %w: %s", ErrCityNotValid, err)
+	}
+
+	if err := helpers.AreAllOneOf(settings.ExcludedISPs, filterChoices.ISPs); err != nil {
+		return fmt.Errorf("excluded ISPs: %w: %s", ErrISPNotValid, err)
+	}
+
+	if err := helpers.AreAllOneOf(settings.ExcludedHostnames, filterChoices.Hostnames); err != nil {
+		return fmt.Errorf("excluded hostnames: %w: %s", ErrHostnameNotValid, err)
+	}
+
+	if err := helpers.AreAllOneOf(settings.ExcludedNames, filterChoices.Names); err != nil {
+		return fmt.Errorf("excluded names: %w: %s", ErrNameNotValid, err)
+	}
+
 	return nil
 }
 
 func (ss *ServerSelection) copy() (copied ServerSelection) {
 	return ServerSelection{
-		VPN:          ss.VPN,
-		TargetIP:     ss.TargetIP,
-		Countries:    helpers.CopySlice(ss.Countries),
-		Regions:      helpers.CopySlice(ss.Regions),
-		Cities:       helpers.CopySlice(ss.Cities),
-		ISPs:         helpers.CopySlice(ss.ISPs),
-		Hostnames:    helpers.CopySlice(ss.Hostnames),
-		Names:        helpers.CopySlice(ss.Names),
-		Numbers:      helpers.CopySlice(ss.Numbers),
-		OwnedOnly:    helpers.CopyPointer(ss.OwnedOnly),
-		FreeOnly:     helpers.CopyPointer(ss.FreeOnly),
-		PremiumOnly:  helpers.CopyPointer(ss.PremiumOnly),
-		StreamOnly:   helpers.CopyPointer(ss.StreamOnly),
-		MultiHopOnly: helpers.CopyPointer(ss.MultiHopOnly),
-		OpenVPN:      ss.OpenVPN.copy(),
-		Wireguard:    ss.Wireguard.copy(),
+		VPN:               ss.VPN,
+		TargetIP:          ss.TargetIP,
+		ServerIP:          ss.ServerIP,
+		Countries:         helpers.CopySlice(ss.Countries),
+		Regions:           helpers.CopySlice(ss.Regions),
+		Cities:            helpers.CopySlice(ss.Cities),
+		ISPs:              helpers.CopySlice(ss.ISPs),
+		Hostnames:         helpers.CopySlice(ss.Hostnames),
+		Names:             helpers.CopySlice(ss.Names),
+		Numbers:           helpers.CopySlice(ss.Numbers),
+		ExcludedCountries: helpers.CopySlice(ss.ExcludedCountries),
+		ExcludedRegions:   helpers.CopySlice(ss.ExcludedRegions),
+		ExcludedCities:    helpers.CopySlice(ss.ExcludedCities),
+		ExcludedISPs:      helpers.CopySlice(ss.ExcludedISPs),
+		ExcludedNames:     helpers.CopySlice(ss.ExcludedNames),
+		ExcludedHostnames: helpers.CopySlice(ss.ExcludedHostnames),
+		OwnedOnly:         helpers.CopyPointer(ss.OwnedOnly),
+		FreeOnly:          helpers.CopyPointer(ss.FreeOnly),
+		PremiumOnly:       helpers.CopyPointer(ss.PremiumOnly),
+		StreamOnly:        helpers.CopyPointer(ss.StreamOnly),
+		MultiHopOnly:      helpers.CopyPointer(ss.MultiHopOnly),
+		PortForwardOnly:   helpers.CopyPointer(ss.PortForwardOnly),
+		Features:          helpers.CopySlice(ss.Features),
+		Filter:            ss.Filter,
+		OpenVPN:           ss.OpenVPN.copy(),
+		Wireguard:         ss.Wireguard.copy(),
 	}
 }
 
 func (ss *ServerSelection) mergeWith(other ServerSelection) {
 	ss.VPN = helpers.MergeWithString(ss.VPN, other.VPN)
 	ss.TargetIP = helpers.MergeWithIP(ss.TargetIP, other.TargetIP)
+	ss.ServerIP = helpers.MergeWithIP(ss.ServerIP, other.ServerIP)
 	ss.Countries = helpers.MergeSlices(ss.Countries, other.Countries)
 	ss.Regions = helpers.MergeSlices(ss.Regions, other.Regions)
 	ss.Cities = helpers.MergeSlices(ss.Cities, other.Cities)
@@ -230,11 +343,20 @@ func (ss *ServerSelection) mergeWith(other ServerSelection) {
 	ss.Hostnames = helpers.MergeSlices(ss.Hostnames, other.Hostnames)
 	ss.Names = helpers.MergeSlices(ss.Names, other.Names)
 	ss.Numbers = helpers.MergeSlices(ss.Numbers, other.Numbers)
+	ss.ExcludedCountries = helpers.MergeSlices(ss.ExcludedCountries, other.ExcludedCountries)
+	ss.ExcludedRegions = helpers.MergeSlices(ss.ExcludedRegions, other.ExcludedRegions)
+	ss.ExcludedCities = helpers.MergeSlices(ss.ExcludedCities, other.ExcludedCities)
+	ss.ExcludedISPs = helpers.MergeSlices(ss.ExcludedISPs, other.ExcludedISPs)
+	ss.ExcludedNames = helpers.MergeSlices(ss.ExcludedNames, other.ExcludedNames)
+	ss.ExcludedHostnames = helpers.MergeSlices(ss.ExcludedHostnames, other.ExcludedHostnames)
 	ss.OwnedOnly = helpers.MergeWithPointer(ss.OwnedOnly, other.OwnedOnly)
 	ss.FreeOnly = helpers.MergeWithPointer(ss.FreeOnly, other.FreeOnly)
 	ss.PremiumOnly = helpers.MergeWithPointer(ss.PremiumOnly, other.PremiumOnly)
 	ss.StreamOnly = helpers.MergeWithPointer(ss.StreamOnly, other.StreamOnly)
 	ss.MultiHopOnly = helpers.MergeWithPointer(ss.MultiHopOnly, other.MultiHopOnly)
+	ss.PortForwardOnly = helpers.MergeWithPointer(ss.PortForwardOnly, other.PortForwardOnly)
+	ss.Features = helpers.MergeSlices(ss.Features, other.Features)
+	ss.Filter = helpers.MergeWithString(ss.Filter, other.Filter)
 
 	ss.OpenVPN.mergeWith(other.OpenVPN)
 	ss.Wireguard.mergeWith(other.Wireguard)
@@ -243,6 +365,7 @@ func (ss *ServerSelection) mergeWith(other ServerSelection) {
 func (ss *ServerSelection) overrideWith(other ServerSelection) {
 	ss.VPN = helpers.OverrideWithString(ss.VPN, other.VPN)
 	ss.TargetIP = helpers.OverrideWithIP(ss.TargetIP, other.TargetIP)
+	ss.ServerIP = helpers.OverrideWithIP(ss.ServerIP, other.ServerIP)
 	ss.Countries = helpers.OverrideWithSlice(ss.Countries, other.Countries)
 	ss.Regions = helpers.OverrideWithSlice(ss.Regions, other.Regions)
 	ss.Cities = helpers.OverrideWithSlice(ss.Cities, other.Cities)
@@ -250,11 +373,20 @@ func (ss *ServerSelection) overrideWith(other ServerSelection) {
 	ss.Hostnames = helpers.OverrideWithSlice(ss.Hostnames, other.Hostnames)
 	ss.Names = helpers.OverrideWithSlice(ss.Names, other.Names)
 	ss.Numbers = helpers.OverrideWithSlice(ss.Numbers, other.Numbers)
+	ss.ExcludedCountries = helpers.OverrideWithSlice(ss.ExcludedCountries, other.ExcludedCountries)
+	ss.ExcludedRegions = helpers.OverrideWithSlice(ss.ExcludedRegions, other.ExcludedRegions)
+	ss.ExcludedCities = helpers.OverrideWithSlice(ss.ExcludedCities, other.ExcludedCities)
+	ss.ExcludedISPs = helpers.OverrideWithSlice(ss.ExcludedISPs, other.ExcludedISPs)
+	ss.ExcludedNames = helpers.OverrideWithSlice(ss.ExcludedNames, other.ExcludedNames)
+	ss.ExcludedHostnames = helpers.OverrideWithSlice(ss.ExcludedHostnames, other.ExcludedHostnames)
 	ss.OwnedOnly = helpers.OverrideWithPointer(ss.OwnedOnly, other.OwnedOnly)
 	ss.FreeOnly = helpers.OverrideWithPointer(ss.FreeOnly, other.FreeOnly)
 	ss.PremiumOnly = helpers.OverrideWithPointer(ss.PremiumOnly, other.PremiumOnly)
 	ss.StreamOnly = helpers.OverrideWithPointer(ss.StreamOnly, other.StreamOnly)
 	ss.MultiHopOnly = helpers.OverrideWithPointer(ss.MultiHopOnly, other.MultiHopOnly)
+	ss.PortForwardOnly = helpers.OverrideWithPointer(ss.PortForwardOnly, other.PortForwardOnly)
+	ss.Features = helpers.OverrideWithSlice(ss.Features, other.Features)
+	ss.Filter = helpers.OverrideWithString(ss.Filter, other.Filter)
 	ss.OpenVPN.overrideWith(other.OpenVPN)
 	ss.Wireguard.overrideWith(other.Wireguard)
 }
@@ -262,11 +394,13 @@ func (ss *ServerSelection) overrideWith(other ServerSelection) {
 func (ss *ServerSelection) setDefaults(vpnProvider string) {
 	ss.VPN = helpers.DefaultString(ss.VPN, vpn.OpenVPN)
 	ss.TargetIP = helpers.DefaultIP(ss.TargetIP, netip.IPv4Unspecified())
+	ss.ServerIP = helpers.DefaultIP(ss.ServerIP, netip.IPv4Unspecified())
 	ss.OwnedOnly = helpers.DefaultPointer(ss.OwnedOnly, false)
 	ss.FreeOnly = helpers.DefaultPointer(ss.FreeOnly, false)
 	ss.PremiumOnly = helpers.DefaultPointer(ss.PremiumOnly, false)
 	ss.StreamOnly = helpers.DefaultPointer(ss.StreamOnly, false)
 	ss.MultiHopOnly = helpers.DefaultPointer(ss.MultiHopOnly, false)
+	ss.PortForwardOnly = helpers.DefaultPointer(ss.PortForwardOnly, false)
 	ss.OpenVPN.setDefaults(vpnProvider)
 	ss.Wireguard.setDefaults()
 }
@@ -281,6 +415,9 @@ func (ss ServerSelection) toLinesNode() (node *gotree.Node) {
 	if !ss.TargetIP.IsUnspecified() {
 		node.Appendf("Target IP address: %s", ss.TargetIP)
 	}
+	if !ss.ServerIP.IsUnspecified() {
+		node.Appendf("Pinned server IP address: %s", ss.ServerIP)
+	}
 
 	if len(ss.Countries) > 0 {
 		node.Appendf("Countries: %s", strings.Join(ss.Countries, ", "))
@@ -313,6 +450,30 @@ func (ss ServerSelection) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Hostnames: %s", strings.Join(ss.Hostnames, ", "))
 	}
 
+	if len(ss.ExcludedCountries) > 0 {
+		node.Appendf("Excluded countries: %s", strings.Join(ss.ExcludedCountries, ", "))
+	}
+
+	if len(ss.ExcludedRegions) > 0 {
+		node.Appendf("Excluded regions: %s", strings.Join(ss.ExcludedRegions, ", "))
+	}
+
+	if len(ss.ExcludedCities) > 0 {
+		node.Appendf("Excluded cities: %s", strings.Join(ss.ExcludedCities, ", "))
+	}
+
+	if len(ss.ExcludedISPs) > 0 {
+		node.Appendf("Excluded ISPs: %s", strings.Join(ss.ExcludedISPs, ", "))
+	}
+
+	if len(ss.ExcludedNames) > 0 {
+		node.Appendf("Excluded server names: %s", strings.Join(ss.ExcludedNames, ", "))
+	}
+
+	if len(ss.ExcludedHostnames) > 0 {
+		node.Appendf("Excluded hostnames: %s", strings.Join(ss.ExcludedHostnames, ", "))
+	}
+
 	if *ss.OwnedOnly {
 		node.Appendf("Owned only servers: yes")
 	}
@@ -333,6 +494,18 @@ func (ss ServerSelection) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Multi-hop only servers: yes")
 	}
 
+	if *ss.PortForwardOnly {
+		node.Appendf("Port forwarding only servers: yes")
+	}
+
+	if len(ss.Features) > 0 {
+		node.Appendf("Server features: %s", strings.Join(ss.Features, ", "))
+	}
+
+	if ss.Filter != "" {
+		node.Appendf("Server filter: %s", ss.Filter)
+	}
+
 	if ss.VPN == vpn.OpenVPN {
 		node.AppendNode(ss.OpenVPN.toLinesNode())
 	} else {
@@ -14,21 +14,33 @@ import (
 type Settings struct {
 	ControlServer ControlServer
 	DNS           DNS
+	Diagnostics   Diagnostics
+	EventHooks    EventHooks
 	Firewall      Firewall
 	Health        Health
 	HTTPProxy     HTTPProxy
+	LANGateway    LANGateway
 	Log           Log
+	Metrics       Metrics
+	Notify        Notify
 	PublicIP      PublicIP
 	Shadowsocks   Shadowsocks
+	Shutdown      Shutdown
+	SpeedTest     SpeedTest
 	System        System
+	Tracing       Tracing
 	Updater       Updater
 	Version       Version
 	VPN           VPN
-	Pprof         pprof.Settings
+	// WireguardServer contains settings for the
+	// inbound Wireguard server.
+	WireguardServer WireguardServer
+	Pprof           pprof.Settings
 }
 
 type Storage interface {
 	GetFilterChoices(provider string) models.FilterChoices
+	GetPortForwardFilterChoices(provider string) models.FilterChoices
 }
 
 // Validate validates all the settings and returns an error
@@ -36,21 +48,42 @@ type Storage interface {
 // TODO v4 remove pointer for receiver (because of Surfshark).
 func (s *Settings) Validate(storage Storage, ipv6Supported bool) (err error) {
 	nameToValidation := map[string]func() error{
-		"control server":  s.ControlServer.validate,
-		"dns":             s.DNS.validate,
-		"firewall":        s.Firewall.validate,
-		"health":          s.Health.Validate,
-		"http proxy":      s.HTTPProxy.validate,
+		"control server": s.ControlServer.validate,
+		"dns":            s.DNS.validate,
+		"diagnostics":    s.Diagnostics.validate,
+		"event hooks":    s.EventHooks.validate,
+		"firewall":       s.Firewall.validate,
+		"health":         s.Health.Validate,
+		"http proxy": func() error {
+			return s.HTTPProxy.validate(*s.VPN.Provider.Name, storage)
+		},
+		"lan gateway": func() error {
+			return s.LANGateway.validate(s.VPN.Type)
+		},
 		"log":             s.Log.validate,
+		"metrics":         s.Metrics.validate,
+		"notify":          s.Notify.validate,
 		"public ip check": s.PublicIP.validate,
 		"shadowsocks":     s.Shadowsocks.validate,
-		"system":          s.System.validate,
-		"updater":         s.Updater.Validate,
-		"version":         s.Version.validate,
+		"shutdown": func() error {
+			return s.Shutdown.validate(s.VPN.Hooks)
+		},
+		"speed test": s.SpeedTest.validate,
+		"system":     s.System.validate,
+		"tracing":    s.Tracing.validate,
+		"updater":    s.Updater.Validate,
+		"version":    s.Version.validate,
 		// Pprof validation done in pprof constructor
 		"VPN": func() error {
 			return s.VPN.Validate(storage, ipv6Supported)
 		},
+		"wireguard server": func() error {
+			vpnInterface := s.VPN.Wireguard.Interface
+			if s.VPN.Type == vpn.OpenVPN {
+				vpnInterface = s.VPN.OpenVPN.Interface
+			}
+			return s.WireguardServer.validate(vpnInterface)
+		},
 	}
 
 	for name, validation := range nameToValidation {
@@ -65,35 +98,53 @@ func (s *Settings) Validate(storage Storage, ipv6Supported bool) (err error) {
 
 func (s *Settings) copy() (copied Settings) {
 	return Settings{
-		ControlServer: s.ControlServer.copy(),
-		DNS:           s.DNS.Copy(),
-		Firewall:      s.Firewall.copy(),
-		Health:        s.Health.copy(),
-		HTTPProxy:     s.HTTPProxy.copy(),
-		Log:           s.Log.copy(),
-		PublicIP:      s.PublicIP.copy(),
-		Shadowsocks:   s.Shadowsocks.copy(),
-		System:        s.System.copy(),
-		Updater:       s.Updater.copy(),
-		Version:       s.Version.copy(),
-		VPN:           s.VPN.Copy(),
-		Pprof:         s.Pprof.Copy(),
+		ControlServer:   s.ControlServer.copy(),
+		DNS:             s.DNS.Copy(),
+		Diagnostics:     s.Diagnostics.copy(),
+		EventHooks:      s.EventHooks.copy(),
+		Firewall:        s.Firewall.copy(),
+		Health:          s.Health.copy(),
+		HTTPProxy:       s.HTTPProxy.copy(),
+		LANGateway:      s.LANGateway.copy(),
+		Log:             s.Log.copy(),
+		Metrics:         s.Metrics.copy(),
+		Notify:          s.Notify.copy(),
+		PublicIP:        s.PublicIP.copy(),
+		Shadowsocks:     s.Shadowsocks.copy(),
+		Shutdown:        s.Shutdown.copy(),
+		SpeedTest:       s.SpeedTest.copy(),
+		System:          s.System.copy(),
+		Tracing:         s.Tracing.copy(),
+		Updater:         s.Updater.copy(),
+		Version:         s.Version.copy(),
+		VPN:             s.VPN.Copy(),
+		WireguardServer: s.WireguardServer.copy(),
+		Pprof:           s.Pprof.Copy(),
 	}
 }
 
 func (s *Settings) MergeWith(other Settings) {
 	s.ControlServer.mergeWith(other.ControlServer)
 	s.DNS.mergeWith(other.DNS)
+	s.Diagnostics.mergeWith(other.Diagnostics)
+	s.EventHooks.mergeWith(other.EventHooks)
 	s.Firewall.mergeWith(other.Firewall)
 	s.Health.MergeWith(other.Health)
 	s.HTTPProxy.mergeWith(other.HTTPProxy)
+	s.LANGateway.mergeWith(other.LANGateway)
 	s.Log.mergeWith(other.Log)
+	s.Metrics.mergeWith(other.Metrics)
+	s.Notify.mergeWith(other.Notify)
 	s.PublicIP.mergeWith(other.PublicIP)
 	s.Shadowsocks.mergeWith(other.Shadowsocks)
+	s.Shutdown.mergeWith(other.Shutdown)
+	s.SpeedTest.mergeWith(other.SpeedTest)
 	s.System.mergeWith(other.System)
+	s.Tracing.mergeWith(other.Tracing)
 	s.Updater.mergeWith(other.Updater)
 	s.Version.mergeWith(other.Version)
 	s.VPN.mergeWith(other.VPN)
+	s.WireguardServer.mergeWith(other.WireguardServer)
 	s.Pprof.MergeWith(other.Pprof)
 }
 
@@ -102,16 +153,25 @@ func (s *Settings) OverrideWith(other Settings,
 	patchedSettings := s.copy()
 	patchedSettings.ControlServer.overrideWith(other.ControlServer)
 	patchedSettings.DNS.overrideWith(other.DNS)
+	patchedSettings.Diagnostics.overrideWith(other.Diagnostics)
+	patchedSettings.EventHooks.overrideWith(other.EventHooks)
 	patchedSettings.Firewall.overrideWith(other.Firewall)
 	patchedSettings.Health.OverrideWith(other.Health)
 	patchedSettings.HTTPProxy.overrideWith(other.HTTPProxy)
+	patchedSettings.LANGateway.overrideWith(other.LANGateway)
 	patchedSettings.Log.overrideWith(other.Log)
+	patchedSettings.Metrics.overrideWith(other.Metrics)
+	patchedSettings.Notify.overrideWith(other.Notify)
 	patchedSettings.PublicIP.overrideWith(other.PublicIP)
 	patchedSettings.Shadowsocks.overrideWith(other.Shadowsocks)
+	patchedSettings.Shutdown.overrideWith(other.Shutdown)
+	patchedSettings.SpeedTest.overrideWith(other.SpeedTest)
 	patchedSettings.System.overrideWith(other.System)
+	patchedSettings.Tracing.overrideWith(other.Tracing)
 	patchedSettings.Updater.overrideWith(other.Updater)
 	patchedSettings.Version.overrideWith(other.Version)
 	patchedSettings.VPN.OverrideWith(other.VPN)
+	patchedSettings.WireguardServer.overrideWith(other.WireguardServer)
 	patchedSettings.Pprof.OverrideWith(other.Pprof)
 	err = patchedSettings.Validate(storage, ipv6Supported)
 	if err != nil {
@@ -122,17 +182,26 @@ func (s *Settings) OverrideWith(other Settings,
 }
 
 func (s *Settings) SetDefaults() {
+	s.System.setDefaults()
 	s.ControlServer.setDefaults()
 	s.DNS.setDefaults()
+	s.Diagnostics.setDefaults()
+	s.EventHooks.setDefaults()
 	s.Firewall.setDefaults()
 	s.Health.SetDefaults()
 	s.HTTPProxy.setDefaults()
+	s.LANGateway.setDefaults()
 	s.Log.setDefaults()
-	s.PublicIP.setDefaults()
+	s.Metrics.setDefaults()
+	s.Notify.setDefaults()
+	s.PublicIP.setDefaults(*s.System.RuntimeDirectory)
 	s.Shadowsocks.setDefaults()
-	s.System.setDefaults()
+	s.Shutdown.setDefaults()
+	s.SpeedTest.setDefaults()
+	s.Tracing.setDefaults()
 	s.Version.setDefaults()
-	s.VPN.setDefaults()
+	s.VPN.setDefaults(*s.System.RuntimeDirectory)
+	s.WireguardServer.setDefaults()
 	s.Updater.SetDefaults(*s.VPN.Provider.Name)
 	s.Pprof.SetDefaults()
 }
@@ -150,11 +219,20 @@ func (s Settings) toLinesNode() (node *gotree.Node) {
 	node.AppendNode(s.Log.toLinesNode())
 	node.AppendNode(s.Health.toLinesNode())
 	node.AppendNode(s.Shadowsocks.toLinesNode())
+	node.AppendNode(s.WireguardServer.toLinesNode())
+	node.AppendNode(s.LANGateway.toLinesNode())
 	node.AppendNode(s.HTTPProxy.toLinesNode())
 	node.AppendNode(s.ControlServer.toLinesNode())
 	node.AppendNode(s.System.toLinesNode())
+	node.AppendNode(s.Diagnostics.toLinesNode())
+	node.AppendNode(s.Shutdown.toLinesNode())
 	node.AppendNode(s.PublicIP.toLinesNode())
 	node.AppendNode(s.Updater.toLinesNode())
+	node.AppendNode(s.Tracing.toLinesNode())
+	node.AppendNode(s.Metrics.toLinesNode())
+	node.AppendNode(s.Notify.toLinesNode())
+	node.AppendNode(s.EventHooks.toLinesNode())
+	node.AppendNode(s.SpeedTest.toLinesNode())
 	node.AppendNode(s.Version.toLinesNode())
 	node.AppendNode(s.Pprof.ToLinesNode())
 
@@ -179,5 +257,72 @@ func (s Settings) Warnings() (warnings []string) {
 			"by creating an issue, attaching the new certificate and we will update Gluetun.")
 	}
 
+	if s.VPN.Type == vpn.Tor && !*s.Firewall.Enabled {
+		warnings = append(warnings, "The firewall is disabled so traffic not going "+
+			"through the Tor transparent proxy, such as UDP traffic, is not blocked.")
+	}
+
+	if *s.System.HostMode && *s.Firewall.Enabled && !*s.Firewall.RestoreHostRules {
+		warnings = append(warnings, "In host mode, the firewall replaces all the existing "+
+			"iptables rules and policies of the host, which are not restored once it is disabled. "+
+			"Set FIREWALL_RESTORE_HOST_RULES=on to restore them, or FIREWALL=off if the host "+
+			"firewall is managed separately, keeping in mind traffic can then leak outside the VPN.")
+	}
+
 	return warnings
 }
+
+// Secrets returns all the non empty secret values of the settings,
+// such as passwords and private keys, which should never be logged.
+func (s Settings) Secrets() (secrets []string) {
+	candidates := []*string{
+		s.VPN.OpenVPN.Password,
+		s.VPN.OpenVPN.Key,
+		s.VPN.OpenVPN.TLSCryptV2,
+		s.VPN.OpenVPN.StaticKey,
+		s.VPN.OpenVPN.EncryptedKey,
+		s.VPN.OpenVPN.KeyPassphrase,
+		s.VPN.Wireguard.PrivateKey,
+		s.VPN.Wireguard.AccessToken,
+		s.VPN.Wireguard.AccountNumber,
+		s.VPN.Wireguard.PreSharedKey,
+		s.VPN.Wireguard.UDP2Raw.Password,
+		s.VPN.Tailscale.AuthKey,
+		s.VPN.Secondary.PrivateKey,
+		s.VPN.Secondary.PreSharedKey,
+		s.VPN.Provider.PortForwarding.Password,
+		s.VPN.EgressProxy.Password,
+		s.HTTPProxy.Password,
+		s.Updater.ProtonPassword,
+		s.Shadowsocks.Password,
+		s.WireguardServer.PrivateKey,
+		s.Notify.TelegramToken,
+		s.Notify.DiscordWebhookURL,
+		s.Notify.WebhookURL,
+		s.ControlServer.ReadOnlyAPIKey,
+		s.ControlServer.AdminAPIKey,
+	}
+
+	for _, candidate := range candidates {
+		if candidate == nil || *candidate == "" {
+			continue
+		}
+		secrets = append(secrets, *candidate)
+	}
+
+	secrets = append(secrets, s.Notify.URLs...)
+
+	for _, peer := range s.WireguardServer.Peers {
+		if peer.PreSharedKey != "" {
+			secrets = append(secrets, peer.PreSharedKey)
+		}
+	}
+
+	for _, instance := range s.Shadowsocks.Instances {
+		if instance.Password != "" {
+			secrets = append(secrets, instance.Password)
+		}
+	}
+
+	return secrets
+}
@@ -29,20 +29,30 @@ func Test_Settings_String(t *testing.T) {
 |   |       └── OpenVPN server selection settings:
 |   |           ├── Protocol: UDP
 |   |           └── Private Internet Access encryption preset: strong
-|   └── OpenVPN settings:
-|       ├── OpenVPN version: 2.5
-|       ├── User: [not set]
-|       ├── Password: [not set]
-|       ├── Private Internet Access encryption preset: strong
-|       ├── Network interface: tun0
-|       ├── Run OpenVPN as: root
-|       └── Verbosity level: 1
+|   ├── OpenVPN settings:
+|   |   ├── OpenVPN version: 2.5
+|   |   ├── User: [not set]
+|   |   ├── Password: [not set]
+|   |   ├── Private Internet Access encryption preset: strong
+|   |   ├── Network interface: tun0
+|   |   ├── Run OpenVPN as: root
+|   |   └── Verbosity level: 1
+|   ├── Reconnection backoff settings:
+|   |   ├── Initial delay: 15s
+|   |   ├── Multiplier: 2
+|   |   └── Maximum delay: none
+|   ├── Authentication failure settings:
+|   |   └── Policy: rotate
+|   └── Server blacklist settings:
+|       ├── Failures before blacklisting: 3
+|       └── Cooldown: 30m0s
 ├── DNS settings:
 |   ├── DNS server address to use: 127.0.0.1
 |   ├── Keep existing nameserver(s): no
 |   └── DNS over TLS settings:
 |       ├── Enabled: yes
 |       ├── Update period: every 24h0m0s
+|       ├── Upstream health check period: every 1m0s
 |       ├── Unbound settings:
 |       |   ├── Authoritative servers:
 |       |   |   └── Cloudflare
@@ -58,14 +68,17 @@ func Test_Settings_String(t *testing.T) {
 |       └── DNS filtering settings:
 |           ├── Block malicious: yes
 |           ├── Block ads: no
-|           └── Block surveillance: yes
+|           ├── Block surveillance: yes
+|           └── Rebinding protection: yes
 ├── Firewall settings:
-|   └── Enabled: yes
+|   ├── Enabled: yes
+|   └── Kill switch when VPN is paused: allow_lan
 ├── Log settings:
 |   └── Log level: INFO
 ├── Health settings:
 |   ├── Server listening address: 127.0.0.1:9999
-|   ├── Target address: cloudflare.com:443
+|   ├── Targets:
+|   |   └── cloudflare.com:443 (tcp4, timeout 3s)
 |   ├── Duration to wait after success: 5s
 |   ├── Read header timeout: 100ms
 |   ├── Read timeout: 500ms
@@ -74,17 +87,44 @@ func Test_Settings_String(t *testing.T) {
 |       └── Additional duration: 5s
 ├── Shadowsocks server settings:
 |   └── Enabled: no
+├── Wireguard server settings:
+|   └── Enabled: no
 ├── HTTP proxy settings:
 |   └── Enabled: no
 ├── Control server settings:
 |   ├── Listening address: :8000
-|   └── Logging: yes
+|   ├── Logging: yes
+|   ├── Read-only routes: yes
+|   └── Admin routes: yes
 ├── OS Alpine settings:
 |   ├── Process UID: 1000
-|   └── Process GID: 1000
+|   ├── Process GID: 1000
+|   ├── Timezone from public IP: no
+|   ├── Umask: 0022
+|   ├── Servers file permissions: 0644
+|   ├── Servers merge strategy: newest
+|   ├── Host mode: no
+|   ├── Resolv.conf file: /etc/resolv.conf
+|   ├── Data directory: /gluetun
+|   └── Runtime directory: /tmp/gluetun
+├── Shutdown settings:
+|   ├── Timeout: 8s
+|   ├── Port forwarding timeout: 1s
+|   ├── Proxies timeout: 400ms
+|   ├── DNS timeout: 400ms
+|   ├── VPN timeout: 3s
+|   └── Firewall timeout: 1s
 ├── Public IP settings:
 |   ├── Fetching: every 12h0m0s
-|   └── IP file path: /tmp/gluetun/ip
+|   ├── Fetch timeout: 10s
+|   ├── Fetch retries: 2
+|   ├── IP file path: /tmp/gluetun/ip
+|   └── IP file permissions: 0644
+├── Speed test settings:
+|   ├── Download URL: https://speed.cloudflare.com/__down?bytes=25000000
+|   ├── Upload URL: https://speed.cloudflare.com/__up
+|   ├── Upload size: 10MB
+|   └── Timeout: 30s
 └── Version settings:
     └── Enabled: yes`,
 		},
@@ -101,3 +141,38 @@ func Test_Settings_String(t *testing.T) {
 		})
 	}
 }
+
+func Test_Settings_Secrets(t *testing.T) {
+	t.Parallel()
+
+	settings := Settings{}
+	settings.VPN.Wireguard.UDP2Raw.Password = stringPtr("udp2raw password")
+	settings.VPN.OpenVPN.TLSCryptV2 = stringPtr("tls crypt v2 key")
+	settings.VPN.OpenVPN.StaticKey = stringPtr("static key")
+	settings.WireguardServer.Peers = []WireguardServerPeer{
+		{PreSharedKey: "peer pre-shared key"},
+	}
+
+	secrets := settings.Secrets()
+
+	assert.Contains(t, secrets, "udp2raw password")
+	assert.Contains(t, secrets, "tls crypt v2 key")
+	assert.Contains(t, secrets, "static key")
+	assert.Contains(t, secrets, "peer pre-shared key")
+}
+
+func Test_Settings_SetDefaults_runtimeDirectory(t *testing.T) {
+	t.Parallel()
+
+	settings := Settings{
+		System: System{
+			RuntimeDirectory: stringPtr("/run/gluetun"),
+		},
+	}
+
+	settings.SetDefaults()
+
+	assert.Equal(t, "/run/gluetun/ip", *settings.PublicIP.IPFilepath)
+	assert.Equal(t, "/run/gluetun/forwarded_port",
+		*settings.VPN.Provider.PortForwarding.Filepath)
+}
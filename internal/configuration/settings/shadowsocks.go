@@ -1,6 +1,10 @@
 package settings
 
 import (
+	"fmt"
+	"os/exec"
+	"regexp"
+
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/ss-server/pkg/tcpudp"
@@ -11,26 +15,148 @@ type Shadowsocks struct {
 	// Enabled is true if the server should be running.
 	// It defaults to false, and cannot be nil in the internal state.
 	Enabled *bool
+	// UDP is true if the UDP relay should run alongside
+	// the TCP server. It defaults to true, and cannot be
+	// nil in the internal state.
+	UDP *bool
+	// Plugin is the name or path of a SIP003 plugin program,
+	// such as v2ray-plugin or obfs-server, which listens on the
+	// listening address and relays TCP traffic to the server.
+	// It defaults to the empty string meaning no plugin is used.
+	Plugin string
+	// PluginOptions are the options passed to the plugin in
+	// its SS_PLUGIN_OPTIONS environment variable, for example
+	// "server;tls;host=example.com". It can be the empty string.
+	PluginOptions string
 	// Settings are settings for the TCP+UDP server.
 	tcpudp.Settings
+	// Instances are additional Shadowsocks servers each with their
+	// own listening address and credentials, such that different
+	// devices or users can be given credentials revocable individually.
+	// They share the other settings of the main server.
+	Instances []ShadowsocksInstance
+}
+
+// ShadowsocksInstance is an additional Shadowsocks server
+// with its own listening address and credentials.
+type ShadowsocksInstance struct {
+	// Name is the name of the instance, used in logs.
+	// It must be unique and cannot be empty.
+	Name string
+	// Address is the listening address of the instance,
+	// and must differ from the other listening addresses.
+	Address string
+	// CipherName is the cipher of the instance, and
+	// defaults to the cipher of the main server if empty.
+	CipherName string
+	// Password is the password of the instance,
+	// and cannot be empty.
+	Password string
 }
 
 func (s Shadowsocks) validate() (err error) {
-	return s.Settings.Validate()
+	if s.Plugin != "" {
+		_, err = exec.LookPath(s.Plugin)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrShadowsocksPluginNotFound, err)
+		}
+	}
+
+	err = s.Settings.Validate()
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(s.Instances))
+	addresses := map[string]struct{}{s.Address: {}}
+	for _, instance := range s.Instances {
+		err = instance.validate()
+		if err != nil {
+			return fmt.Errorf("instance %s: %w", instance.Name, err)
+		}
+
+		if _, ok := names[instance.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrShadowsocksInstanceNameDuplicate, instance.Name)
+		}
+		names[instance.Name] = struct{}{}
+
+		if _, ok := addresses[instance.Address]; ok {
+			return fmt.Errorf("%w: %s", ErrShadowsocksInstanceAddressDuplicate, instance.Address)
+		}
+		addresses[instance.Address] = struct{}{}
+
+		instanceSettings := s.InstanceSettings(instance)
+		err = instanceSettings.Settings.Validate()
+		if err != nil {
+			return fmt.Errorf("instance %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+var regexShadowsocksInstanceName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func (s ShadowsocksInstance) validate() (err error) {
+	switch {
+	case !regexShadowsocksInstanceName.MatchString(s.Name):
+		return fmt.Errorf("%w: %q does not match regex %s",
+			ErrShadowsocksInstanceNameNotValid, s.Name, regexShadowsocksInstanceName)
+	case s.Address == "":
+		return fmt.Errorf("%w", ErrShadowsocksInstanceAddressNotSet)
+	case s.Password == "":
+		return fmt.Errorf("%w", ErrShadowsocksInstancePasswordNotSet)
+	}
+	return nil
+}
+
+// InstanceSettings returns the settings of the Shadowsocks server
+// for the instance given, inheriting the main server settings
+// except for the listening address, cipher and password.
+func (s Shadowsocks) InstanceSettings(instance ShadowsocksInstance) (
+	settings Shadowsocks) {
+	settings = Shadowsocks{
+		Enabled:       helpers.CopyPointer(s.Enabled),
+		UDP:           helpers.CopyPointer(s.UDP),
+		Plugin:        s.Plugin,
+		PluginOptions: s.PluginOptions,
+		Settings: tcpudp.Settings{
+			Address:      instance.Address,
+			LogAddresses: helpers.CopyPointer(s.LogAddresses),
+			CipherName:   helpers.DefaultString(instance.CipherName, s.CipherName),
+			Password:     helpers.CopyPointer(&instance.Password),
+		},
+	}
+	settings.Settings.SetDefaults()
+	return settings
 }
 
 func (s *Shadowsocks) copy() (copied Shadowsocks) {
-	return Shadowsocks{
-		Enabled:  helpers.CopyPointer(s.Enabled),
-		Settings: s.Settings.Copy(),
+	copied = Shadowsocks{
+		Enabled:       helpers.CopyPointer(s.Enabled),
+		UDP:           helpers.CopyPointer(s.UDP),
+		Plugin:        s.Plugin,
+		PluginOptions: s.PluginOptions,
+		Settings:      s.Settings.Copy(),
+	}
+	if s.Instances != nil {
+		copied.Instances = make([]ShadowsocksInstance, len(s.Instances))
+		copy(copied.Instances, s.Instances)
 	}
+	return copied
 }
 
 // mergeWith merges the other settings into any
 // unset field of the receiver settings object.
 func (s *Shadowsocks) mergeWith(other Shadowsocks) {
 	s.Enabled = helpers.MergeWithPointer(s.Enabled, other.Enabled)
+	s.UDP = helpers.MergeWithPointer(s.UDP, other.UDP)
+	s.Plugin = helpers.MergeWithString(s.Plugin, other.Plugin)
+	s.PluginOptions = helpers.MergeWithString(s.PluginOptions, other.PluginOptions)
 	s.Settings.MergeWith(other.Settings)
+	if s.Instances == nil {
+		s.Instances = other.Instances
+	}
 }
 
 // overrideWith overrides fields of the receiver
@@ -38,11 +164,16 @@ func (s *Shadowsocks) mergeWith(other Shadowsocks) {
 // settings.
 func (s *Shadowsocks) overrideWith(other Shadowsocks) {
 	s.Enabled = helpers.OverrideWithPointer(s.Enabled, other.Enabled)
+	s.UDP = helpers.OverrideWithPointer(s.UDP, other.UDP)
+	s.Plugin = helpers.OverrideWithString(s.Plugin, other.Plugin)
+	s.PluginOptions = helpers.OverrideWithString(s.PluginOptions, other.PluginOptions)
 	s.Settings.OverrideWith(other.Settings)
+	s.Instances = helpers.OverrideWithSlice(s.Instances, other.Instances)
 }
 
 func (s *Shadowsocks) setDefaults() {
 	s.Enabled = helpers.DefaultPointer(s.Enabled, false)
+	s.UDP = helpers.DefaultPointer(s.UDP, true)
 	s.Settings.SetDefaults()
 }
 
@@ -63,6 +194,23 @@ func (s Shadowsocks) toLinesNode() (node *gotree.Node) {
 	node.Appendf("Cipher: %s", s.CipherName)
 	node.Appendf("Password: %s", helpers.ObfuscatePassword(*s.Password))
 	node.Appendf("Log addresses: %s", helpers.BoolPtrToYesNo(s.LogAddresses))
+	node.Appendf("UDP relay: %s", helpers.BoolPtrToYesNo(s.UDP))
+	if s.Plugin != "" {
+		pluginNode := node.Appendf("Plugin: %s", s.Plugin)
+		if s.PluginOptions != "" {
+			pluginNode.Appendf("Options: %s", s.PluginOptions)
+		}
+	}
+	if len(s.Instances) > 0 {
+		instancesNode := node.Appendf("Instances:")
+		for _, instance := range s.Instances {
+			instanceNode := instancesNode.Appendf("%s:", instance.Name)
+			instanceNode.Appendf("Listening address: %s", instance.Address)
+			instanceNode.Appendf("Cipher: %s",
+				helpers.DefaultString(instance.CipherName, s.CipherName))
+			instanceNode.Appendf("Password: %s", helpers.ObfuscatePassword(instance.Password))
+		}
+	}
 
 	return node
 }
@@ -0,0 +1,121 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Shutdown contains settings to configure the graceful
+// shutdown of the program. Subsystems are stopped in order:
+// port forwarding, proxies, DNS, VPN and finally firewall.
+type Shutdown struct {
+	// Timeout is the overall maximum duration of the shutdown.
+	// It cannot be zero in the internal state.
+	Timeout time.Duration
+	// PortForwardingTimeout is the maximum duration
+	// to stop port forwarding.
+	// It cannot be zero in the internal state.
+	PortForwardingTimeout time.Duration
+	// ProxiesTimeout is the maximum duration to stop
+	// the HTTP proxy and the Shadowsocks server.
+	// It cannot be zero in the internal state.
+	ProxiesTimeout time.Duration
+	// DNSTimeout is the maximum duration to stop the DNS server.
+	// It cannot be zero in the internal state.
+	DNSTimeout time.Duration
+	// VPNTimeout is the maximum duration to tear down
+	// the VPN tunnel, which can be slow for OpenVPN.
+	// It cannot be zero in the internal state.
+	VPNTimeout time.Duration
+	// FirewallTimeout is the maximum duration to restore the
+	// firewall rules and policies, which is only done in host mode.
+	// It cannot be zero in the internal state.
+	FirewallTimeout time.Duration
+}
+
+func (s Shutdown) validate(vpnHooks Hooks) (err error) {
+	subsystemsTimeout := s.PortForwardingTimeout + s.ProxiesTimeout +
+		s.DNSTimeout + s.VPNTimeout + s.FirewallTimeout
+	if s.Timeout < subsystemsTimeout {
+		return fmt.Errorf("%w: %s must be at least the sum of "+
+			"the subsystems timeouts %s",
+			ErrShutdownTimeoutTooShort, s.Timeout, subsystemsTimeout)
+	}
+
+	// The VPN pre-down and post-down hooks run
+	// within the VPN shutdown timeout.
+	downHooksTimeout := time.Duration(vpnHooks.downHooksCount()) * vpnHooks.Timeout
+	if s.VPNTimeout <= downHooksTimeout {
+		return fmt.Errorf("%w: %s must be more than the VPN down hooks timeouts %s",
+			ErrShutdownVPNTimeoutTooShort, s.VPNTimeout, downHooksTimeout)
+	}
+
+	return nil
+}
+
+func (s *Shutdown) copy() (copied Shutdown) {
+	return Shutdown{
+		Timeout:               s.Timeout,
+		PortForwardingTimeout: s.PortForwardingTimeout,
+		ProxiesTimeout:        s.ProxiesTimeout,
+		DNSTimeout:            s.DNSTimeout,
+		VPNTimeout:            s.VPNTimeout,
+		FirewallTimeout:       s.FirewallTimeout,
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (s *Shutdown) mergeWith(other Shutdown) {
+	s.Timeout = helpers.MergeWithNumber(s.Timeout, other.Timeout)
+	s.PortForwardingTimeout = helpers.MergeWithNumber(s.PortForwardingTimeout, other.PortForwardingTimeout)
+	s.ProxiesTimeout = helpers.MergeWithNumber(s.ProxiesTimeout, other.ProxiesTimeout)
+	s.DNSTimeout = helpers.MergeWithNumber(s.DNSTimeout, other.DNSTimeout)
+	s.VPNTimeout = helpers.MergeWithNumber(s.VPNTimeout, other.VPNTimeout)
+	s.FirewallTimeout = helpers.MergeWithNumber(s.FirewallTimeout, other.FirewallTimeout)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (s *Shutdown) overrideWith(other Shutdown) {
+	s.Timeout = helpers.OverrideWithNumber(s.Timeout, other.Timeout)
+	s.PortForwardingTimeout = helpers.OverrideWithNumber(s.PortForwardingTimeout, other.PortForwardingTimeout)
+	s.ProxiesTimeout = helpers.OverrideWithNumber(s.ProxiesTimeout, other.ProxiesTimeout)
+	s.DNSTimeout = helpers.OverrideWithNumber(s.DNSTimeout, other.DNSTimeout)
+	s.VPNTimeout = helpers.OverrideWithNumber(s.VPNTimeout, other.VPNTimeout)
+	s.FirewallTimeout = helpers.OverrideWithNumber(s.FirewallTimeout, other.FirewallTimeout)
+}
+
+func (s *Shutdown) setDefaults() {
+	const defaultTimeout = 8 * time.Second
+	s.Timeout = helpers.DefaultNumber(s.Timeout, defaultTimeout)
+	const defaultPortForwardingTimeout = time.Second
+	s.PortForwardingTimeout = helpers.DefaultNumber(s.PortForwardingTimeout, defaultPortForwardingTimeout)
+	const defaultProxiesTimeout = 400 * time.Millisecond
+	s.ProxiesTimeout = helpers.DefaultNumber(s.ProxiesTimeout, defaultProxiesTimeout)
+	const defaultDNSTimeout = 400 * time.Millisecond
+	s.DNSTimeout = helpers.DefaultNumber(s.DNSTimeout, defaultDNSTimeout)
+	const defaultVPNTimeout = 3 * time.Second
+	s.VPNTimeout = helpers.DefaultNumber(s.VPNTimeout, defaultVPNTimeout)
+	const defaultFirewallTimeout = time.Second
+	s.FirewallTimeout = helpers.DefaultNumber(s.FirewallTimeout, defaultFirewallTimeout)
+}
+
+func (s Shutdown) String() string {
+	return s.toLinesNode().String()
+}
+
+func (s Shutdown) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Shutdown settings:")
+	node.Appendf("Timeout: %s", s.Timeout)
+	node.Appendf("Port forwarding timeout: %s", s.PortForwardingTimeout)
+	node.Appendf("Proxies timeout: %s", s.ProxiesTimeout)
+	node.Appendf("DNS timeout: %s", s.DNSTimeout)
+	node.Appendf("VPN timeout: %s", s.VPNTimeout)
+	node.Appendf("Firewall timeout: %s", s.FirewallTimeout)
+	return node
+}
@@ -0,0 +1,107 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// SpeedTest contains settings to configure the speed test
+// run through the VPN tunnel.
+type SpeedTest struct {
+	// DownloadURL is the URL to download data from
+	// to measure the download throughput and latency.
+	// It cannot be the empty string in the internal state.
+	DownloadURL string
+	// UploadURL is the URL to upload data to using HTTP
+	// POST requests to measure the upload throughput.
+	// It can be the empty string to skip the upload test.
+	// It cannot be nil in the internal state.
+	UploadURL *string
+	// UploadMegabytes is the number of megabytes to upload
+	// to measure the upload throughput.
+	// It cannot be zero in the internal state.
+	UploadMegabytes uint16
+	// Timeout is the maximum duration of each of the
+	// download and upload tests.
+	// It cannot be zero in the internal state.
+	Timeout time.Duration
+}
+
+func (s SpeedTest) validate() (err error) {
+	urls := map[string]string{
+		"download URL": s.DownloadURL,
+		"upload URL":   *s.UploadURL,
+	}
+	for name, rawURL := range urls {
+		if rawURL == "" {
+			continue
+		}
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("%s: %w: %s", name, ErrSpeedTestURLNotValid, err)
+		} else if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return fmt.Errorf("%s: %w: scheme %q must be http or https",
+				name, ErrSpeedTestURLNotValid, parsed.Scheme)
+		}
+	}
+	return nil
+}
+
+func (s *SpeedTest) copy() (copied SpeedTest) {
+	return SpeedTest{
+		DownloadURL:     s.DownloadURL,
+		UploadURL:       helpers.CopyPointer(s.UploadURL),
+		UploadMegabytes: s.UploadMegabytes,
+		Timeout:         s.Timeout,
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (s *SpeedTest) mergeWith(other SpeedTest) {
+	s.DownloadURL = helpers.MergeWithString(s.DownloadURL, other.DownloadURL)
+	s.UploadURL = helpers.MergeWithPointer(s.UploadURL, other.UploadURL)
+	s.UploadMegabytes = helpers.MergeWithNumber(s.UploadMegabytes, other.UploadMegabytes)
+	s.Timeout = helpers.MergeWithNumber(s.Timeout, other.Timeout)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (s *SpeedTest) overrideWith(other SpeedTest) {
+	s.DownloadURL = helpers.OverrideWithString(s.DownloadURL, other.DownloadURL)
+	s.UploadURL = helpers.OverrideWithPointer(s.UploadURL, other.UploadURL)
+	s.UploadMegabytes = helpers.OverrideWithNumber(s.UploadMegabytes, other.UploadMegabytes)
+	s.Timeout = helpers.OverrideWithNumber(s.Timeout, other.Timeout)
+}
+
+func (s *SpeedTest) setDefaults() {
+	s.DownloadURL = helpers.DefaultString(s.DownloadURL,
+		"https://speed.cloudflare.com/__down?bytes=25000000")
+	s.UploadURL = helpers.DefaultPointer(s.UploadURL, "https://speed.cloudflare.com/__up")
+	const defaultUploadMegabytes = 10
+	s.UploadMegabytes = helpers.DefaultNumber(s.UploadMegabytes, defaultUploadMegabytes)
+	const defaultTimeout = 30 * time.Second
+	s.Timeout = helpers.DefaultNumber(s.Timeout, defaultTimeout)
+}
+
+func (s SpeedTest) String() string {
+	return s.toLinesNode().String()
+}
+
+func (s SpeedTest) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Speed test settings:")
+	node.Appendf("Download URL: %s", s.DownloadURL)
+	if *s.UploadURL == "" {
+		node.Appendf("Upload test: disabled")
+	} else {
+		node.Appendf("Upload URL: %s", *s.UploadURL)
+		node.Appendf("Upload size: %dMB", s.UploadMegabytes)
+	}
+	node.Appendf("Timeout: %s", s.Timeout)
+	return node
+}
@@ -0,0 +1,99 @@
+package settings
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/syslog"
+	"github.com/qdm12/gotree"
+)
+
+// Syslog contains settings to configure sending logs
+// to a remote syslog server.
+type Syslog struct {
+	// Address is the remote syslog server address,
+	// in the form host:port. It can be set to the empty
+	// string to disable sending logs to a syslog server.
+	// It cannot be nil in the internal state.
+	Address *string
+	// Protocol is the network protocol to use to reach
+	// the syslog server, and can be 'udp', 'tcp' or 'tls'.
+	// It cannot be the empty string in the internal state.
+	Protocol string
+	// Facility is the syslog facility name to use for
+	// each message, for example 'daemon' or 'local0'.
+	// It cannot be the empty string in the internal state.
+	Facility string
+}
+
+func (s Syslog) validate() (err error) {
+	if *s.Address == "" {
+		return nil
+	}
+
+	_, _, err = net.SplitHostPort(*s.Address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSyslogAddressNotValid, err)
+	}
+
+	if !helpers.IsOneOf(s.Protocol, "udp", "tcp", "tls") {
+		return fmt.Errorf("%w: %s", ErrSyslogProtocolNotValid, s.Protocol)
+	}
+
+	facilities := syslog.FacilityNames()
+	if !helpers.IsOneOf(s.Facility, facilities...) {
+		return fmt.Errorf("%w: %s must be one of %s",
+			ErrSyslogFacilityNotValid, s.Facility,
+			helpers.ChoicesOrString(facilities))
+	}
+
+	return nil
+}
+
+func (s *Syslog) copy() (copied Syslog) {
+	return Syslog{
+		Address:  helpers.CopyPointer(s.Address),
+		Protocol: s.Protocol,
+		Facility: s.Facility,
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (s *Syslog) mergeWith(other Syslog) {
+	s.Address = helpers.MergeWithPointer(s.Address, other.Address)
+	s.Protocol = helpers.MergeWithString(s.Protocol, other.Protocol)
+	s.Facility = helpers.MergeWithString(s.Facility, other.Facility)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (s *Syslog) overrideWith(other Syslog) {
+	s.Address = helpers.OverrideWithPointer(s.Address, other.Address)
+	s.Protocol = helpers.OverrideWithString(s.Protocol, other.Protocol)
+	s.Facility = helpers.OverrideWithString(s.Facility, other.Facility)
+}
+
+func (s *Syslog) setDefaults() {
+	s.Address = helpers.DefaultPointer(s.Address, "")
+	s.Protocol = helpers.DefaultString(s.Protocol, "udp")
+	s.Facility = helpers.DefaultString(s.Facility, "daemon")
+}
+
+func (s Syslog) String() string {
+	return s.toLinesNode().String()
+}
+
+func (s Syslog) toLinesNode() (node *gotree.Node) {
+	if *s.Address == "" {
+		return nil
+	}
+
+	node = gotree.New("Syslog settings:")
+	node.Appendf("Address: %s", *s.Address)
+	node.Appendf("Protocol: %s", s.Protocol)
+	node.Appendf("Facility: %s", s.Facility)
+	return node
+}
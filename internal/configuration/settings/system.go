@@ -1,7 +1,13 @@
 package settings
 
 import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gotree"
 )
 
@@ -10,18 +16,106 @@ type System struct {
 	PUID     *uint32
 	PGID     *uint32
 	Timezone string
+	// TimezoneFromPublicIP is true if the timezone should be
+	// set from the public IP information data when the
+	// Timezone field is empty. The timezone is then the one
+	// of the VPN server location, and is only used for log
+	// timestamps and schedule windows. It cannot be nil in the
+	// internal state.
+	TimezoneFromPublicIP *bool
+	// Umask is the file mode creation mask of the program and
+	// of its subprocesses, applied to files they create such as
+	// OpenVPN and Unbound log or status files. It does not apply
+	// to files written by the program itself, such as the OpenVPN
+	// and Unbound configuration files, which are given explicit
+	// permissions. It cannot be nil in the internal state.
+	Umask *fs.FileMode
+	// ServersFilePermissions are the permission bits
+	// of the servers data file written by the program.
+	// It cannot be nil in the internal state.
+	ServersFilePermissions *fs.FileMode
+	// ServersMergeStrategy is the strategy to merge, for each
+	// provider, the embedded servers with the servers from the
+	// data file. It can be 'newest', 'file' or 'embedded', and
+	// cannot be the empty string in the internal state.
+	ServersMergeStrategy string
+	// HostMode is true if the program runs directly on a host,
+	// for example as a systemd service, instead of in its container
+	// image. In this mode, system users and the host resolv.conf
+	// file are left untouched. It cannot be nil in the internal state.
+	HostMode *bool
+	// ResolvConfPath is the path of the resolv.conf file written
+	// to use the DNS over TLS server. It defaults to /etc/resolv.conf,
+	// or to the empty string in host mode, in which case no file is
+	// written. It cannot be nil in the internal state.
+	ResolvConfPath *string
+	// DataDirectory is the directory holding persisted data,
+	// such as the servers data file. It should be a volume when
+	// running with a read-only root filesystem. It defaults to
+	// /gluetun and cannot be nil in the internal state.
+	DataDirectory *string
+	// RuntimeDirectory is the directory holding files written
+	// at runtime, such as the OpenVPN configuration and auth files.
+	// It can be a tmpfs when running with a read-only root filesystem.
+	// It defaults to /tmp/gluetun and cannot be nil in the internal state.
+	RuntimeDirectory *string
 }
 
 // Validate validates System settings.
 func (s System) validate() (err error) {
+	if s.Timezone != "" {
+		_, err = time.LoadLocation(s.Timezone)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSystemTimezoneNotValid, err)
+		}
+	}
+
+	err = validateFilePermissions(*s.Umask)
+	if err != nil {
+		return fmt.Errorf("umask: %w", err)
+	}
+
+	err = validateFilePermissions(*s.ServersFilePermissions)
+	if err != nil {
+		return fmt.Errorf("servers file permissions: %w", err)
+	}
+
+	if *s.ResolvConfPath != "" && !filepath.IsAbs(*s.ResolvConfPath) {
+		return fmt.Errorf("%w: %s: must be an absolute path",
+			ErrSystemResolvConfPathNotValid, *s.ResolvConfPath)
+	}
+
+	if !filepath.IsAbs(*s.DataDirectory) {
+		return fmt.Errorf("%w: %s: must be an absolute path",
+			ErrSystemDataDirectoryNotValid, *s.DataDirectory)
+	}
+
+	if !filepath.IsAbs(*s.RuntimeDirectory) {
+		return fmt.Errorf("%w: %s: must be an absolute path",
+			ErrSystemRuntimeDirectoryNotValid, *s.RuntimeDirectory)
+	}
+
+	if !helpers.IsOneOf(s.ServersMergeStrategy, constants.ServersMergeNewest,
+		constants.ServersMergeFile, constants.ServersMergeEmbedded) {
+		return fmt.Errorf("%w: %s", ErrServersMergeStrategyNotValid, s.ServersMergeStrategy)
+	}
+
 	return nil
 }
 
 func (s *System) copy() (copied System) {
 	return System{
-		PUID:     helpers.CopyPointer(s.PUID),
-		PGID:     helpers.CopyPointer(s.PGID),
-		Timezone: s.Timezone,
+		PUID:                   helpers.CopyPointer(s.PUID),
+		PGID:                   helpers.CopyPointer(s.PGID),
+		Timezone:               s.Timezone,
+		TimezoneFromPublicIP:   helpers.CopyPointer(s.TimezoneFromPublicIP),
+		Umask:                  helpers.CopyPointer(s.Umask),
+		ServersFilePermissions: helpers.CopyPointer(s.ServersFilePermissions),
+		ServersMergeStrategy:   s.ServersMergeStrategy,
+		HostMode:               helpers.CopyPointer(s.HostMode),
+		ResolvConfPath:         helpers.CopyPointer(s.ResolvConfPath),
+		DataDirectory:          helpers.CopyPointer(s.DataDirectory),
+		RuntimeDirectory:       helpers.CopyPointer(s.RuntimeDirectory),
 	}
 }
 
@@ -29,18 +123,58 @@ func (s *System) mergeWith(other System) {
 	s.PUID = helpers.MergeWithPointer(s.PUID, other.PUID)
 	s.PGID = helpers.MergeWithPointer(s.PGID, other.PGID)
 	s.Timezone = helpers.MergeWithString(s.Timezone, other.Timezone)
+	s.TimezoneFromPublicIP = helpers.MergeWithPointer(s.TimezoneFromPublicIP, other.TimezoneFromPublicIP)
+	s.Umask = helpers.MergeWithPointer(s.Umask, other.Umask)
+	s.ServersFilePermissions = helpers.MergeWithPointer(s.ServersFilePermissions, other.ServersFilePermissions)
+	s.ServersMergeStrategy = helpers.MergeWithString(s.ServersMergeStrategy, other.ServersMergeStrategy)
+	s.HostMode = helpers.MergeWithPointer(s.HostMode, other.HostMode)
+	s.ResolvConfPath = helpers.MergeWithPointer(s.ResolvConfPath, other.ResolvConfPath)
+	s.DataDirectory = helpers.MergeWithPointer(s.DataDirectory, other.DataDirectory)
+	s.RuntimeDirectory = helpers.MergeWithPointer(s.RuntimeDirectory, other.RuntimeDirectory)
 }
 
 func (s *System) overrideWith(other System) {
 	s.PUID = helpers.OverrideWithPointer(s.PUID, other.PUID)
 	s.PGID = helpers.OverrideWithPointer(s.PGID, other.PGID)
 	s.Timezone = helpers.OverrideWithString(s.Timezone, other.Timezone)
+	s.TimezoneFromPublicIP = helpers.OverrideWithPointer(s.TimezoneFromPublicIP, other.TimezoneFromPublicIP)
+	s.Umask = helpers.OverrideWithPointer(s.Umask, other.Umask)
+	s.ServersFilePermissions = helpers.OverrideWithPointer(s.ServersFilePermissions, other.ServersFilePermissions)
+	s.ServersMergeStrategy = helpers.OverrideWithString(s.ServersMergeStrategy, other.ServersMergeStrategy)
+	s.HostMode = helpers.OverrideWithPointer(s.HostMode, other.HostMode)
+	s.ResolvConfPath = helpers.OverrideWithPointer(s.ResolvConfPath, other.ResolvConfPath)
+	s.DataDirectory = helpers.OverrideWithPointer(s.DataDirectory, other.DataDirectory)
+	s.RuntimeDirectory = helpers.OverrideWithPointer(s.RuntimeDirectory, other.RuntimeDirectory)
 }
 
 func (s *System) setDefaults() {
 	const defaultID = 1000
 	s.PUID = helpers.DefaultPointer(s.PUID, defaultID)
 	s.PGID = helpers.DefaultPointer(s.PGID, defaultID)
+	s.TimezoneFromPublicIP = helpers.DefaultPointer(s.TimezoneFromPublicIP, false)
+	const defaultUmask = 0022
+	s.Umask = helpers.DefaultPointer(s.Umask, defaultUmask)
+	const defaultServersFilePermissions = 0644
+	s.ServersFilePermissions = helpers.DefaultPointer(s.ServersFilePermissions, defaultServersFilePermissions)
+	s.ServersMergeStrategy = helpers.DefaultString(s.ServersMergeStrategy, constants.ServersMergeNewest)
+	s.HostMode = helpers.DefaultPointer(s.HostMode, false)
+	defaultResolvConfPath := "/etc/resolv.conf"
+	if *s.HostMode {
+		// Leave the host resolv.conf file, which is often managed
+		// by systemd-resolved or NetworkManager, untouched.
+		defaultResolvConfPath = ""
+	}
+	s.ResolvConfPath = helpers.DefaultPointer(s.ResolvConfPath, defaultResolvConfPath)
+	s.DataDirectory = helpers.DefaultPointer(s.DataDirectory, constants.DefaultDataDirectory)
+	s.RuntimeDirectory = helpers.DefaultPointer(s.RuntimeDirectory, constants.DefaultRuntimeDirectory)
+}
+
+// WithDefaults is a shorthand using setDefaults.
+// It's used to get the data directory before reading
+// settings from the files source.
+func (s System) WithDefaults() System {
+	s.setDefaults()
+	return s
 }
 
 func (s System) String() string {
@@ -55,7 +189,21 @@ func (s System) toLinesNode() (node *gotree.Node) {
 
 	if s.Timezone != "" {
 		node.Appendf("Timezone: %s", s.Timezone)
+	} else {
+		node.Appendf("Timezone from public IP: %s", helpers.BoolPtrToYesNo(s.TimezoneFromPublicIP))
+	}
+
+	node.Appendf("Umask: %04o", uint32(*s.Umask))
+	node.Appendf("Servers file permissions: %04o", uint32(*s.ServersFilePermissions))
+	node.Appendf("Servers merge strategy: %s", s.ServersMergeStrategy)
+	node.Appendf("Host mode: %s", helpers.BoolPtrToYesNo(s.HostMode))
+	if *s.ResolvConfPath == "" {
+		node.Appendf("Resolv.conf file: left untouched")
+	} else {
+		node.Appendf("Resolv.conf file: %s", *s.ResolvConfPath)
 	}
+	node.Appendf("Data directory: %s", *s.DataDirectory)
+	node.Appendf("Runtime directory: %s", *s.RuntimeDirectory)
 
 	return node
 }
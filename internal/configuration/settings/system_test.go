@@ -0,0 +1,122 @@
+package settings
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_System_setDefaults(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		system         System
+		resolvConfPath string
+	}{
+		"container": {
+			resolvConfPath: "/etc/resolv.conf",
+		},
+		"host mode": {
+			system: System{
+				HostMode: boolPtr(true),
+			},
+			resolvConfPath: "",
+		},
+		"host mode with resolv.conf path": {
+			system: System{
+				HostMode:       boolPtr(true),
+				ResolvConfPath: stringPtr("/run/gluetun/resolv.conf"),
+			},
+			resolvConfPath: "/run/gluetun/resolv.conf",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testCase.system.setDefaults()
+
+			assert.Equal(t, testCase.resolvConfPath, *testCase.system.ResolvConfPath)
+		})
+	}
+}
+
+func Test_System_validate(t *testing.T) {
+	t.Parallel()
+
+	validSystem := func() System {
+		umask := fs.FileMode(0022)
+		serversFilePermissions := fs.FileMode(0644)
+		return System{
+			Umask:                  &umask,
+			ServersFilePermissions: &serversFilePermissions,
+			ServersMergeStrategy:   constants.ServersMergeNewest,
+			HostMode:               boolPtr(true),
+			ResolvConfPath:         stringPtr(""),
+			DataDirectory:          stringPtr("/gluetun"),
+			RuntimeDirectory:       stringPtr("/tmp/gluetun"),
+		}
+	}
+
+	testCases := map[string]struct {
+		system     func() System
+		errWrapped error
+		errMessage string
+	}{
+		"resolv.conf left untouched": {
+			system: validSystem,
+		},
+		"absolute resolv.conf path": {
+			system: func() System {
+				system := validSystem()
+				system.ResolvConfPath = stringPtr("/etc/resolv.conf")
+				return system
+			},
+		},
+		"relative resolv.conf path": {
+			system: func() System {
+				system := validSystem()
+				system.ResolvConfPath = stringPtr("resolv.conf")
+				return system
+			},
+			errWrapped: ErrSystemResolvConfPathNotValid,
+			errMessage: "resolv.conf path is not valid: resolv.conf: must be an absolute path",
+		},
+		"relative data directory": {
+			system: func() System {
+				system := validSystem()
+				system.DataDirectory = stringPtr("gluetun")
+				return system
+			},
+			errWrapped: ErrSystemDataDirectoryNotValid,
+			errMessage: "data directory is not valid: gluetun: must be an absolute path",
+		},
+		"relative runtime directory": {
+			system: func() System {
+				system := validSystem()
+				system.RuntimeDirectory = stringPtr("tmp/gluetun")
+				return system
+			},
+			errWrapped: ErrSystemRuntimeDirectoryNotValid,
+			errMessage: "runtime directory is not valid: tmp/gluetun: must be an absolute path",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.system().validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
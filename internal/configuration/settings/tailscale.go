@@ -0,0 +1,102 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Tailscale contains settings to configure the Tailscale
+// client used to route traffic through a Tailscale exit node.
+type Tailscale struct {
+	// AuthKey is the Tailscale authentication key used
+	// to register the node on the tailnet.
+	// It cannot be nil or the empty string in the internal state.
+	AuthKey *string
+	// ExitNode is the Tailscale IP address or name of the
+	// exit node to route all traffic through.
+	// It cannot be the empty string in the internal state.
+	ExitNode string
+	// Hostname is the hostname of the node on the tailnet.
+	// It can be the empty string to use the container hostname.
+	Hostname string
+	// ControlURL is the URL of the coordination server,
+	// which can be changed to use a Headscale server for example.
+	// It cannot be the empty string in the internal state.
+	ControlURL string
+	// Interface is the name of the Tailscale network interface
+	// to create. It cannot be the empty string in the internal state.
+	Interface string
+}
+
+func (t Tailscale) validate() (err error) {
+	if *t.AuthKey == "" {
+		return fmt.Errorf("%w", ErrTailscaleAuthKeyNotSet)
+	}
+
+	if t.ExitNode == "" {
+		return fmt.Errorf("%w", ErrTailscaleExitNodeNotSet)
+	}
+
+	_, err = url.ParseRequestURI(t.ControlURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTailscaleControlURLNotValid, err)
+	}
+
+	if !regexpInterfaceName.MatchString(t.Interface) {
+		return fmt.Errorf("%w: '%s' does not match regex '%s'",
+			ErrTailscaleInterfaceNotValid, t.Interface, regexpInterfaceName)
+	}
+
+	return nil
+}
+
+func (t *Tailscale) copy() (copied Tailscale) {
+	return Tailscale{
+		AuthKey:    helpers.CopyPointer(t.AuthKey),
+		ExitNode:   t.ExitNode,
+		Hostname:   t.Hostname,
+		ControlURL: t.ControlURL,
+		Interface:  t.Interface,
+	}
+}
+
+func (t *Tailscale) mergeWith(other Tailscale) {
+	t.AuthKey = helpers.MergeWithPointer(t.AuthKey, other.AuthKey)
+	t.ExitNode = helpers.MergeWithString(t.ExitNode, other.ExitNode)
+	t.Hostname = helpers.MergeWithString(t.Hostname, other.Hostname)
+	t.ControlURL = helpers.MergeWithString(t.ControlURL, other.ControlURL)
+	t.Interface = helpers.MergeWithString(t.Interface, other.Interface)
+}
+
+func (t *Tailscale) overrideWith(other Tailscale) {
+	t.AuthKey = helpers.OverrideWithPointer(t.AuthKey, other.AuthKey)
+	t.ExitNode = helpers.OverrideWithString(t.ExitNode, other.ExitNode)
+	t.Hostname = helpers.OverrideWithString(t.Hostname, other.Hostname)
+	t.ControlURL = helpers.OverrideWithString(t.ControlURL, other.ControlURL)
+	t.Interface = helpers.OverrideWithString(t.Interface, other.Interface)
+}
+
+func (t *Tailscale) setDefaults() {
+	t.AuthKey = helpers.DefaultPointer(t.AuthKey, "")
+	t.ControlURL = helpers.DefaultString(t.ControlURL, "https://controlplane.tailscale.com")
+	t.Interface = helpers.DefaultString(t.Interface, "tailscale0")
+}
+
+func (t Tailscale) String() string {
+	return t.toLinesNode().String()
+}
+
+func (t Tailscale) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Tailscale settings:")
+	node.Appendf("Auth key: %s", helpers.ObfuscatePassword(*t.AuthKey))
+	node.Appendf("Exit node: %s", t.ExitNode)
+	if t.Hostname != "" {
+		node.Appendf("Hostname: %s", t.Hostname)
+	}
+	node.Appendf("Control URL: %s", t.ControlURL)
+	node.Appendf("Network interface: %s", t.Interface)
+	return node
+}
@@ -0,0 +1,106 @@
+package settings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gotree"
+)
+
+// Tor contains settings to configure the Tor client, used either
+// to route traffic through the Tor network with the VPN type 'tor',
+// or to connect to the OpenVPN server through the Tor network.
+type Tor struct {
+	// Chain is true to connect to the OpenVPN server through
+	// the Tor network, such that the VPN server does not see the
+	// public IP address. It only applies to OpenVPN over TCP.
+	// It cannot be nil in the internal state.
+	Chain *bool
+	// ExitCountries are the two letters country codes of the
+	// Tor exit relays to use. It can be empty to use any exit relay.
+	ExitCountries []string
+	// SocksPort is the local listening port of the Tor SOCKS proxy,
+	// which OpenVPN connects through if Chain is enabled.
+	// It cannot be zero in the internal state.
+	SocksPort uint16
+	// TransPort is the local listening port of the Tor transparent
+	// proxy, which TCP traffic is redirected to for the VPN type 'tor'.
+	// It cannot be zero in the internal state.
+	TransPort uint16
+}
+
+var regexpTorCountryCode = regexp.MustCompile(`^[a-z]{2}$`)
+
+func (t Tor) validate(vpnType string, openvpnTCP bool) (err error) {
+	if *t.Chain && vpnType != vpn.OpenVPN {
+		return fmt.Errorf("%w: for VPN type %s", ErrTorChainNotSupported, vpnType)
+	} else if *t.Chain && !openvpnTCP {
+		return fmt.Errorf("%w: OpenVPN protocol must be TCP", ErrTorChainNotSupported)
+	}
+
+	for _, country := range t.ExitCountries {
+		if !regexpTorCountryCode.MatchString(country) {
+			return fmt.Errorf("%w: %q must be a two letters country code",
+				ErrTorExitCountryNotValid, country)
+		}
+	}
+
+	if t.SocksPort == t.TransPort {
+		return fmt.Errorf("%w: SOCKS and transparent proxy ports are both %d",
+			ErrTorPortsConflict, t.SocksPort)
+	}
+
+	return nil
+}
+
+func (t *Tor) copy() (copied Tor) {
+	return Tor{
+		Chain:         helpers.CopyPointer(t.Chain),
+		ExitCountries: helpers.CopySlice(t.ExitCountries),
+		SocksPort:     t.SocksPort,
+		TransPort:     t.TransPort,
+	}
+}
+
+func (t *Tor) mergeWith(other Tor) {
+	t.Chain = helpers.MergeWithPointer(t.Chain, other.Chain)
+	t.ExitCountries = helpers.MergeSlices(t.ExitCountries, other.ExitCountries)
+	t.SocksPort = helpers.MergeWithNumber(t.SocksPort, other.SocksPort)
+	t.TransPort = helpers.MergeWithNumber(t.TransPort, other.TransPort)
+}
+
+func (t *Tor) overrideWith(other Tor) {
+	t.Chain = helpers.OverrideWithPointer(t.Chain, other.Chain)
+	t.ExitCountries = helpers.OverrideWithSlice(t.ExitCountries, other.ExitCountries)
+	t.SocksPort = helpers.OverrideWithNumber(t.SocksPort, other.SocksPort)
+	t.TransPort = helpers.OverrideWithNumber(t.TransPort, other.TransPort)
+}
+
+func (t *Tor) setDefaults() {
+	t.Chain = helpers.DefaultPointer(t.Chain, false)
+	const defaultSocksPort = 9050
+	t.SocksPort = helpers.DefaultNumber(t.SocksPort, defaultSocksPort)
+	const defaultTransPort = 9040
+	t.TransPort = helpers.DefaultNumber(t.TransPort, defaultTransPort)
+}
+
+func (t Tor) String() string {
+	return t.toLinesNode().String()
+}
+
+func (t Tor) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Tor settings:")
+	if *t.Chain {
+		node.Appendf("Chained before OpenVPN: yes")
+		node.Appendf("SOCKS proxy port: %d", t.SocksPort)
+	} else {
+		node.Appendf("Transparent proxy port: %d", t.TransPort)
+	}
+	if len(t.ExitCountries) > 0 {
+		node.Appendf("Exit countries: %s", strings.Join(t.ExitCountries, ", "))
+	}
+	return node
+}
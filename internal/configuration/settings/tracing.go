@@ -0,0 +1,80 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Tracing contains settings to configure the OpenTelemetry
+// tracing of the program operations.
+type Tracing struct {
+	// Endpoint is the OTLP HTTP collector base URL, for example
+	// http://collector:4318. It can be set to the empty string
+	// to disable tracing. It cannot be nil in the internal state.
+	Endpoint *string
+	// ServiceName is the service name to report for
+	// spans exported. It cannot be the empty string
+	// in the internal state.
+	ServiceName string
+}
+
+func (t Tracing) validate() (err error) {
+	if *t.Endpoint == "" {
+		return nil
+	}
+
+	endpoint, err := url.Parse(*t.Endpoint)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTracingEndpointNotValid, err)
+	} else if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q must be http or https",
+			ErrTracingEndpointNotValid, endpoint.Scheme)
+	}
+
+	return nil
+}
+
+func (t *Tracing) copy() (copied Tracing) {
+	return Tracing{
+		Endpoint:    helpers.CopyPointer(t.Endpoint),
+		ServiceName: t.ServiceName,
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (t *Tracing) mergeWith(other Tracing) {
+	t.Endpoint = helpers.MergeWithPointer(t.Endpoint, other.Endpoint)
+	t.ServiceName = helpers.MergeWithString(t.ServiceName, other.ServiceName)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (t *Tracing) overrideWith(other Tracing) {
+	t.Endpoint = helpers.OverrideWithPointer(t.Endpoint, other.Endpoint)
+	t.ServiceName = helpers.OverrideWithString(t.ServiceName, other.ServiceName)
+}
+
+func (t *Tracing) setDefaults() {
+	t.Endpoint = helpers.DefaultPointer(t.Endpoint, "")
+	t.ServiceName = helpers.DefaultString(t.ServiceName, "gluetun")
+}
+
+func (t Tracing) String() string {
+	return t.toLinesNode().String()
+}
+
+func (t Tracing) toLinesNode() (node *gotree.Node) {
+	if *t.Endpoint == "" {
+		return nil
+	}
+
+	node = gotree.New("Tracing settings:")
+	node.Appendf("OTLP endpoint: %s", *t.Endpoint)
+	node.Appendf("Service name: %s", t.ServiceName)
+	return node
+}
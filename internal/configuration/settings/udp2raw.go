@@ -0,0 +1,100 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gotree"
+)
+
+// UDP2Raw contains settings to disguise the Wireguard UDP packets
+// as TCP packets using a udp2raw client, for networks throttling
+// or dropping UDP traffic. The Wireguard server must run a udp2raw
+// server in front of its Wireguard listening port. Since udp2raw
+// adds its own headers, the Wireguard MTU should be lowered,
+// for example to 1280.
+type UDP2Raw struct {
+	// Enabled is true to tunnel the Wireguard packets through
+	// udp2raw. It cannot be nil in the internal state.
+	Enabled *bool
+	// ServerPort is the listening port of the udp2raw server.
+	// It can be set to 0 to use the Wireguard endpoint port,
+	// and cannot be nil in the internal state.
+	ServerPort *uint16
+	// Password is the key shared with the udp2raw server.
+	// It cannot be nil in the internal state, and cannot be
+	// the empty string if udp2raw is enabled.
+	Password *string
+	// RawMode is the type of packets to disguise the Wireguard
+	// packets as, and can be 'faketcp' or 'udp'. It defaults to
+	// 'faketcp' and cannot be the empty string in the internal state.
+	RawMode string
+}
+
+func (u UDP2Raw) validate(vpnProvider string) (err error) {
+	if !*u.Enabled {
+		return nil
+	}
+
+	if vpnProvider != providers.Custom {
+		return fmt.Errorf("%w: for VPN service provider %s",
+			ErrUDP2RawNotSupported, vpnProvider)
+	}
+
+	if *u.Password == "" {
+		return fmt.Errorf("%w", ErrUDP2RawPasswordMissing)
+	}
+
+	validModes := []string{"faketcp", "udp"}
+	if !helpers.IsOneOf(u.RawMode, validModes...) {
+		return fmt.Errorf("%w: %s must be one of %s", ErrUDP2RawModeNotValid,
+			u.RawMode, helpers.ChoicesOrString(validModes))
+	}
+
+	return nil
+}
+
+func (u *UDP2Raw) copy() (copied UDP2Raw) {
+	return UDP2Raw{
+		Enabled:    helpers.CopyPointer(u.Enabled),
+		ServerPort: helpers.CopyPointer(u.ServerPort),
+		Password:   helpers.CopyPointer(u.Password),
+		RawMode:    u.RawMode,
+	}
+}
+
+func (u *UDP2Raw) mergeWith(other UDP2Raw) {
+	u.Enabled = helpers.MergeWithPointer(u.Enabled, other.Enabled)
+	u.ServerPort = helpers.MergeWithPointer(u.ServerPort, other.ServerPort)
+	u.Password = helpers.MergeWithPointer(u.Password, other.Password)
+	u.RawMode = helpers.MergeWithString(u.RawMode, other.RawMode)
+}
+
+func (u *UDP2Raw) overrideWith(other UDP2Raw) {
+	u.Enabled = helpers.OverrideWithPointer(u.Enabled, other.Enabled)
+	u.ServerPort = helpers.OverrideWithPointer(u.ServerPort, other.ServerPort)
+	u.Password = helpers.OverrideWithPointer(u.Password, other.Password)
+	u.RawMode = helpers.OverrideWithString(u.RawMode, other.RawMode)
+}
+
+func (u *UDP2Raw) setDefaults() {
+	u.Enabled = helpers.DefaultPointer(u.Enabled, false)
+	u.ServerPort = helpers.DefaultPointer(u.ServerPort, 0)
+	u.Password = helpers.DefaultPointer(u.Password, "")
+	u.RawMode = helpers.DefaultString(u.RawMode, "faketcp")
+}
+
+func (u UDP2Raw) String() string {
+	return u.toLinesNode().String()
+}
+
+func (u UDP2Raw) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("udp2raw settings:")
+	if *u.ServerPort != 0 {
+		node.Appendf("Server port: %d", *u.ServerPort)
+	}
+	node.Appendf("Password: %s", helpers.ObfuscatePassword(*u.Password))
+	node.Appendf("Raw mode: %s", u.RawMode)
+	return node
+}
@@ -2,12 +2,14 @@ package settings
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gotree"
+	"golang.org/x/exp/maps"
 )
 
 // Updater contains settings to configure the VPN
@@ -26,9 +28,24 @@ type Updater struct {
 	// find per provider, compared to the total current
 	// number of servers. It defaults to 0.8.
 	MinRatio float64
+	// MinServers maps VPN service provider names to the minimum
+	// number of servers to find for the update to be accepted,
+	// which takes precedence over MinRatio for these providers.
+	// A value of 0 accepts any number of servers found.
+	MinServers map[string]uint
 	// Providers is the list of VPN service providers
 	// to update server information for.
 	Providers []string
+	// ProtonEmail is the email of the Proton account used to
+	// fetch the ProtonVPN servers from the authenticated API, and
+	// to fetch the OpenVPN credentials if they are not set.
+	// It can be the empty string to use the unauthenticated API,
+	// and cannot be nil in the internal state.
+	ProtonEmail *string
+	// ProtonPassword is the password of the Proton account.
+	// It cannot be the empty string if ProtonEmail is set,
+	// and cannot be nil in the internal state.
+	ProtonPassword *string
 }
 
 func (u Updater) Validate() (err error) {
@@ -44,6 +61,17 @@ func (u Updater) Validate() (err error) {
 	}
 
 	validProviders := providers.All()
+	for provider := range u.MinServers {
+		if !helpers.IsOneOf(provider, validProviders...) {
+			return fmt.Errorf("minimum servers: %w: %q can only be one of %s",
+				ErrVPNProviderNameNotValid, provider, helpers.ChoicesOrString(validProviders))
+		}
+	}
+
+	if *u.ProtonEmail != "" && *u.ProtonPassword == "" {
+		return fmt.Errorf("%w", ErrUpdaterProtonPasswordMissing)
+	}
+
 	for _, provider := range u.Providers {
 		valid := false
 		for _, validProvider := range validProviders {
@@ -63,10 +91,13 @@ func (u Updater) Validate() (err error) {
 
 func (u *Updater) copy() (copied Updater) {
 	return Updater{
-		Period:     helpers.CopyPointer(u.Period),
-		DNSAddress: u.DNSAddress,
-		MinRatio:   u.MinRatio,
-		Providers:  helpers.CopySlice(u.Providers),
+		Period:         helpers.CopyPointer(u.Period),
+		DNSAddress:     u.DNSAddress,
+		MinRatio:       u.MinRatio,
+		MinServers:     helpers.CopyMap(u.MinServers),
+		Providers:      helpers.CopySlice(u.Providers),
+		ProtonEmail:    helpers.CopyPointer(u.ProtonEmail),
+		ProtonPassword: helpers.CopyPointer(u.ProtonPassword),
 	}
 }
 
@@ -76,7 +107,10 @@ func (u *Updater) mergeWith(other Updater) {
 	u.Period = helpers.MergeWithPointer(u.Period, other.Period)
 	u.DNSAddress = helpers.MergeWithString(u.DNSAddress, other.DNSAddress)
 	u.MinRatio = helpers.MergeWithNumber(u.MinRatio, other.MinRatio)
+	u.MinServers = helpers.MergeMaps(u.MinServers, other.MinServers)
 	u.Providers = helpers.MergeSlices(u.Providers, other.Providers)
+	u.ProtonEmail = helpers.MergeWithPointer(u.ProtonEmail, other.ProtonEmail)
+	u.ProtonPassword = helpers.MergeWithPointer(u.ProtonPassword, other.ProtonPassword)
 }
 
 // overrideWith overrides fields of the receiver
@@ -86,12 +120,17 @@ func (u *Updater) overrideWith(other Updater) {
 	u.Period = helpers.OverrideWithPointer(u.Period, other.Period)
 	u.DNSAddress = helpers.OverrideWithString(u.DNSAddress, other.DNSAddress)
 	u.MinRatio = helpers.OverrideWithNumber(u.MinRatio, other.MinRatio)
+	u.MinServers = helpers.OverrideWithMap(u.MinServers, other.MinServers)
 	u.Providers = helpers.OverrideWithSlice(u.Providers, other.Providers)
+	u.ProtonEmail = helpers.OverrideWithPointer(u.ProtonEmail, other.ProtonEmail)
+	u.ProtonPassword = helpers.OverrideWithPointer(u.ProtonPassword, other.ProtonPassword)
 }
 
 func (u *Updater) SetDefaults(vpnProvider string) {
 	u.Period = helpers.DefaultPointer(u.Period, 0)
 	u.DNSAddress = helpers.DefaultString(u.DNSAddress, "1.1.1.1:53")
+	u.ProtonEmail = helpers.DefaultPointer(u.ProtonEmail, "")
+	u.ProtonPassword = helpers.DefaultPointer(u.ProtonPassword, "")
 
 	if u.MinRatio == 0 {
 		const defaultMinRatio = 0.8
@@ -116,7 +155,18 @@ func (u Updater) toLinesNode() (node *gotree.Node) {
 	node.Appendf("Update period: %s", *u.Period)
 	node.Appendf("DNS address: %s", u.DNSAddress)
 	node.Appendf("Minimum ratio: %.1f", u.MinRatio)
+	if len(u.MinServers) > 0 {
+		minServersNode := node.Appendf("Minimum servers:")
+		providerNames := maps.Keys(u.MinServers)
+		sort.Strings(providerNames)
+		for _, provider := range providerNames {
+			minServersNode.Appendf("%s: %d", provider, u.MinServers[provider])
+		}
+	}
 	node.Appendf("Providers to update: %s", strings.Join(u.Providers, ", "))
+	if *u.ProtonEmail != "" {
+		node.Appendf("Proton account: %s", *u.ProtonEmail)
+	}
 
 	return node
 }
@@ -1,6 +1,9 @@
 package settings
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
 )
@@ -11,15 +14,26 @@ type Version struct {
 	// Enabled is true if the version information should
 	// be fetched from Github.
 	Enabled *bool
+	// UpdateCheckPeriod is the period to check Github for a newer
+	// release, notifying when one is found. It defaults to 0 which
+	// disables the periodic check. It cannot be nil in the internal
+	// state.
+	UpdateCheckPeriod *time.Duration
 }
 
 func (v Version) validate() (err error) {
+	const minUpdateCheckPeriod = time.Hour
+	if *v.UpdateCheckPeriod != 0 && *v.UpdateCheckPeriod < minUpdateCheckPeriod {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrVersionUpdateCheckPeriodTooSmall, *v.UpdateCheckPeriod, minUpdateCheckPeriod)
+	}
 	return nil
 }
 
 func (v *Version) copy() (copied Version) {
 	return Version{
-		Enabled: helpers.CopyPointer(v.Enabled),
+		Enabled:           helpers.CopyPointer(v.Enabled),
+		UpdateCheckPeriod: helpers.CopyPointer(v.UpdateCheckPeriod),
 	}
 }
 
@@ -27,6 +41,7 @@ func (v *Version) copy() (copied Version) {
 // unset field of the receiver settings object.
 func (v *Version) mergeWith(other Version) {
 	v.Enabled = helpers.MergeWithPointer(v.Enabled, other.Enabled)
+	v.UpdateCheckPeriod = helpers.MergeWithPointer(v.UpdateCheckPeriod, other.UpdateCheckPeriod)
 }
 
 // overrideWith overrides fields of the receiver
@@ -34,10 +49,12 @@ func (v *Version) mergeWith(other Version) {
 // settings.
 func (v *Version) overrideWith(other Version) {
 	v.Enabled = helpers.OverrideWithPointer(v.Enabled, other.Enabled)
+	v.UpdateCheckPeriod = helpers.OverrideWithPointer(v.UpdateCheckPeriod, other.UpdateCheckPeriod)
 }
 
 func (v *Version) setDefaults() {
 	v.Enabled = helpers.DefaultPointer(v.Enabled, true)
+	v.UpdateCheckPeriod = helpers.DefaultPointer(v.UpdateCheckPeriod, 0)
 }
 
 func (v Version) String() string {
@@ -48,6 +65,9 @@ func (v Version) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Version settings:")
 
 	node.Appendf("Enabled: %s", helpers.BoolPtrToYesNo(v.Enabled))
+	if *v.UpdateCheckPeriod > 0 {
+		node.Appendf("Update check period: %s", *v.UpdateCheckPeriod)
+	}
 
 	return node
 }
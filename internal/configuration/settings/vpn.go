@@ -2,37 +2,141 @@ package settings
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/nat64"
 	"github.com/qdm12/gotree"
 )
 
 type VPN struct {
 	// Type is the VPN type and can only be
-	// 'openvpn' or 'wireguard'. It cannot be the
-	// empty string in the internal state.
+	// 'openvpn', 'wireguard', 'tailscale' or 'tor'.
+	// It cannot be the empty string in the internal state.
 	Type      string
 	Provider  Provider
 	OpenVPN   OpenVPN
 	Wireguard Wireguard
+	Tailscale Tailscale
+	Tor       Tor
+	Secondary SecondaryTunnel
+	Backoff   Backoff
+	Schedule  Schedule
+	// AuthFailure contains settings for the
+	// behavior on authentication failures.
+	AuthFailure AuthFailure
+	// ServerBlacklist contains settings to temporarily
+	// exclude servers failing repeatedly.
+	ServerBlacklist ServerBlacklist
+	// Hooks contains settings for executables run
+	// at points of the VPN tunnel lifecycle.
+	Hooks Hooks
+	// EgressProxy contains settings of a SOCKS5 proxy
+	// to connect to the VPN server through.
+	EgressProxy EgressProxy
+	// OnDemand is true if the VPN should not be connected
+	// when the program starts, but only once it is started
+	// through the control server or once a client connects
+	// to the HTTP proxy or over TCP to the Shadowsocks server.
+	// It cannot be nil in the internal state.
+	OnDemand *bool
+	// NAT64Prefix is the NAT64 prefix to reach IPv4 VPN servers
+	// through on IPv6 only networks. It can be the empty string to
+	// disable NAT64, "auto" to discover the prefix using DNS64, or
+	// an IPv6 prefix such as 64:ff9b::/96. It cannot be nil in the
+	// internal state.
+	NAT64Prefix *string
 }
 
 // TODO v4 remove pointer for receiver (because of Surfshark).
 func (v *VPN) Validate(storage Storage, ipv6Supported bool) (err error) {
 	// Validate Type
-	validVPNTypes := []string{vpn.OpenVPN, vpn.Wireguard}
+	validVPNTypes := []string{vpn.OpenVPN, vpn.Wireguard, vpn.Tailscale, vpn.Tor}
 	if !helpers.IsOneOf(v.Type, validVPNTypes...) {
 		return fmt.Errorf("%w: %q and can only be one of %s",
 			ErrVPNTypeNotValid, v.Type, strings.Join(validVPNTypes, ", "))
 	}
 
+	err = v.Backoff.validate()
+	if err != nil {
+		return fmt.Errorf("backoff settings: %w", err)
+	}
+
+	err = v.Schedule.validate()
+	if err != nil {
+		return fmt.Errorf("schedule settings: %w", err)
+	}
+
+	err = v.AuthFailure.validate()
+	if err != nil {
+		return fmt.Errorf("authentication failure settings: %w", err)
+	}
+
+	err = v.Hooks.validate()
+	if err != nil {
+		return fmt.Errorf("hooks settings: %w", err)
+	}
+
+	err = validateNAT64Prefix(*v.NAT64Prefix, ipv6Supported)
+	if err != nil {
+		return fmt.Errorf("NAT64 prefix: %w", err)
+	}
+
+	err = v.EgressProxy.validate(v.Type, *v.Tor.Chain, *v.Wireguard.UDP2Raw.Enabled)
+	if err != nil {
+		return fmt.Errorf("egress proxy settings: %w", err)
+	}
+
+	if *v.Secondary.Enabled && v.Type != vpn.Wireguard {
+		// The secondary tunnel packets bypass the main tunnel
+		// using the firewall mark of the main Wireguard tunnel.
+		return fmt.Errorf("secondary tunnel settings: %w: for VPN type %s",
+			ErrSecondaryNotSupported, v.Type)
+	}
+
+	if v.Type == vpn.Tailscale {
+		// Tailscale does not use a VPN service provider
+		if *v.Provider.PortForwarding.Enabled {
+			return fmt.Errorf("%w: for VPN type %s",
+				ErrPortForwardingEnabled, v.Type)
+		}
+
+		err = v.Tailscale.validate()
+		if err != nil {
+			return fmt.Errorf("Tailscale settings: %w", err)
+		}
+		return nil
+	}
+
+	err = v.Tor.validate(v.Type, *v.Provider.ServerSelection.OpenVPN.TCP)
+	if err != nil {
+		return fmt.Errorf("Tor settings: %w", err)
+	}
+
+	if v.Type == vpn.Tor {
+		// Tor does not use a VPN service provider
+		if *v.Provider.PortForwarding.Enabled {
+			return fmt.Errorf("%w: for VPN type %s",
+				ErrPortForwardingEnabled, v.Type)
+		}
+		return nil
+	}
+
 	err = v.Provider.validate(v.Type, storage)
 	if err != nil {
 		return fmt.Errorf("provider settings: %w", err)
 	}
 
+	if *v.Provider.CredentialsCheck &&
+		*v.Provider.Name == providers.Nordvpn &&
+		*v.Wireguard.AccessToken == "" {
+		return fmt.Errorf("provider settings: %w: "+
+			"the Wireguard access token is required", ErrCredentialsCheckEnabled)
+	}
+
 	if v.Type == vpn.OpenVPN {
 		err := v.OpenVPN.validate(*v.Provider.Name)
 		if err != nil {
@@ -45,15 +149,74 @@ func (v *VPN) Validate(storage Storage, ipv6Supported bool) (err error) {
 		}
 	}
 
+	err = v.Secondary.validate(v.Wireguard.Interface, storage, ipv6Supported)
+	if err != nil {
+		return fmt.Errorf("secondary tunnel settings: %w", err)
+	}
+
+	if v.Type == vpn.Wireguard && *v.Wireguard.OpenVPNFallbackAttempts > 0 {
+		fallback := v.OpenVPNFallback()
+		err = fallback.Validate(storage, ipv6Supported)
+		if err != nil {
+			return fmt.Errorf("OpenVPN fallback settings: %w", err)
+		}
+	}
+
 	return nil
 }
 
+func validateNAT64Prefix(nat64Prefix string, ipv6Supported bool) (err error) {
+	if nat64Prefix == "" {
+		return nil
+	} else if !ipv6Supported {
+		return fmt.Errorf("%w", ErrNAT64IPv6NotSupported)
+	} else if nat64Prefix == "auto" {
+		return nil
+	}
+
+	prefix, err := netip.ParsePrefix(nat64Prefix)
+	if err != nil {
+		return err
+	}
+	return nat64.CheckPrefix(prefix)
+}
+
+// OpenVPNFallback returns a copy of the VPN settings using OpenVPN
+// over TCP instead of Wireguard, with the same provider and server
+// selection. The port 443 is used if the provider allows it.
+func (v *VPN) OpenVPNFallback() (fallback VPN) {
+	fallback = v.Copy()
+	fallback.Type = vpn.OpenVPN
+	// The secondary tunnel requires the main tunnel to be Wireguard.
+	*fallback.Secondary.Enabled = false
+	selection := &fallback.Provider.ServerSelection
+	selection.VPN = vpn.OpenVPN
+	*selection.OpenVPN.TCP = true
+	const httpsPort = 443
+	*selection.OpenVPN.CustomPort = httpsPort
+	if selection.OpenVPN.validate(*fallback.Provider.Name) != nil {
+		*selection.OpenVPN.CustomPort = 0
+	}
+	return fallback
+}
+
 func (v *VPN) Copy() (copied VPN) {
 	return VPN{
-		Type:      v.Type,
-		Provider:  v.Provider.copy(),
-		OpenVPN:   v.OpenVPN.copy(),
-		Wireguard: v.Wireguard.copy(),
+		Type:            v.Type,
+		Provider:        v.Provider.copy(),
+		OpenVPN:         v.OpenVPN.copy(),
+		Wireguard:       v.Wireguard.copy(),
+		Tailscale:       v.Tailscale.copy(),
+		Tor:             v.Tor.copy(),
+		Secondary:       v.Secondary.copy(),
+		Backoff:         v.Backoff.copy(),
+		Schedule:        v.Schedule.copy(),
+		AuthFailure:     v.AuthFailure.copy(),
+		ServerBlacklist: v.ServerBlacklist.copy(),
+		Hooks:           v.Hooks.copy(),
+		EgressProxy:     v.EgressProxy.copy(),
+		OnDemand:        helpers.CopyPointer(v.OnDemand),
+		NAT64Prefix:     helpers.CopyPointer(v.NAT64Prefix),
 	}
 }
 
@@ -62,6 +225,17 @@ func (v *VPN) mergeWith(other VPN) {
 	v.Provider.mergeWith(other.Provider)
 	v.OpenVPN.mergeWith(other.OpenVPN)
 	v.Wireguard.mergeWith(other.Wireguard)
+	v.Tailscale.mergeWith(other.Tailscale)
+	v.Tor.mergeWith(other.Tor)
+	v.Secondary.mergeWith(other.Secondary)
+	v.Backoff.mergeWith(other.Backoff)
+	v.Schedule.mergeWith(other.Schedule)
+	v.AuthFailure.mergeWith(other.AuthFailure)
+	v.ServerBlacklist.mergeWith(other.ServerBlacklist)
+	v.Hooks.mergeWith(other.Hooks)
+	v.EgressProxy.mergeWith(other.EgressProxy)
+	v.OnDemand = helpers.MergeWithPointer(v.OnDemand, other.OnDemand)
+	v.NAT64Prefix = helpers.MergeWithPointer(v.NAT64Prefix, other.NAT64Prefix)
 }
 
 func (v *VPN) OverrideWith(other VPN) {
@@ -69,13 +243,35 @@ func (v *VPN) OverrideWith(other VPN) {
 	v.Provider.overrideWith(other.Provider)
 	v.OpenVPN.overrideWith(other.OpenVPN)
 	v.Wireguard.overrideWith(other.Wireguard)
+	v.Tailscale.overrideWith(other.Tailscale)
+	v.Tor.overrideWith(other.Tor)
+	v.Secondary.overrideWith(other.Secondary)
+	v.Backoff.overrideWith(other.Backoff)
+	v.Schedule.overrideWith(other.Schedule)
+	v.AuthFailure.overrideWith(other.AuthFailure)
+	v.ServerBlacklist.overrideWith(other.ServerBlacklist)
+	v.Hooks.overrideWith(other.Hooks)
+	v.EgressProxy.overrideWith(other.EgressProxy)
+	v.OnDemand = helpers.OverrideWithPointer(v.OnDemand, other.OnDemand)
+	v.NAT64Prefix = helpers.OverrideWithPointer(v.NAT64Prefix, other.NAT64Prefix)
 }
 
-func (v *VPN) setDefaults() {
+func (v *VPN) setDefaults(runtimeDirectory string) {
 	v.Type = helpers.DefaultString(v.Type, vpn.OpenVPN)
-	v.Provider.setDefaults()
+	v.Provider.setDefaults(runtimeDirectory)
 	v.OpenVPN.setDefaults(*v.Provider.Name)
 	v.Wireguard.setDefaults()
+	v.Tailscale.setDefaults()
+	v.Tor.setDefaults()
+	v.Secondary.setDefaults()
+	v.Backoff.setDefaults()
+	v.Schedule.setDefaults()
+	v.AuthFailure.setDefaults()
+	v.ServerBlacklist.setDefaults()
+	v.Hooks.setDefaults()
+	v.EgressProxy.setDefaults()
+	v.OnDemand = helpers.DefaultPointer(v.OnDemand, false)
+	v.NAT64Prefix = helpers.DefaultPointer(v.NAT64Prefix, "")
 }
 
 func (v VPN) String() string {
@@ -85,12 +281,48 @@ func (v VPN) String() string {
 func (v VPN) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("VPN settings:")
 
-	node.AppendNode(v.Provider.toLinesNode())
-
-	if v.Type == vpn.OpenVPN {
+	switch v.Type {
+	case vpn.OpenVPN:
+		node.AppendNode(v.Provider.toLinesNode())
 		node.AppendNode(v.OpenVPN.toLinesNode())
-	} else {
+		if *v.Tor.Chain {
+			node.AppendNode(v.Tor.toLinesNode())
+		}
+	case vpn.Wireguard:
+		node.AppendNode(v.Provider.toLinesNode())
 		node.AppendNode(v.Wireguard.toLinesNode())
+	case vpn.Tailscale:
+		node.AppendNode(v.Tailscale.toLinesNode())
+	case vpn.Tor:
+		node.AppendNode(v.Tor.toLinesNode())
+	}
+
+	if *v.Secondary.Enabled {
+		node.AppendNode(v.Secondary.toLinesNode())
+	}
+
+	if v.EgressProxy.Enabled() {
+		node.AppendNode(v.EgressProxy.toLinesNode())
+	}
+
+	node.AppendNode(v.Backoff.toLinesNode())
+	node.AppendNode(v.AuthFailure.toLinesNode())
+	node.AppendNode(v.ServerBlacklist.toLinesNode())
+
+	if v.Hooks.Enabled() {
+		node.AppendNode(v.Hooks.toLinesNode())
+	}
+
+	if *v.OnDemand {
+		node.Appendf("On demand: yes")
+	}
+
+	if *v.NAT64Prefix != "" {
+		node.Appendf("NAT64 prefix: %s", *v.NAT64Prefix)
+	}
+
+	if len(v.Schedule.Windows) > 0 {
+		node.AppendNode(v.Schedule.toLinesNode())
 	}
 
 	return node
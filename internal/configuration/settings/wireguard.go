@@ -17,6 +17,19 @@ type Wireguard struct {
 	// PrivateKey is the Wireguard client peer private key.
 	// It cannot be nil in the internal state.
 	PrivateKey *string
+	// AccessToken is the VPN provider account access token
+	// used to fetch the Wireguard private key from the provider
+	// API if the private key is not set. It is only supported
+	// for NordVPN. It can be the empty string to indicate it is
+	// not set, and cannot be nil in the internal state.
+	AccessToken *string
+	// AccountNumber is the VPN provider account number used
+	// to register the program as a device with the provider API,
+	// obtaining the Wireguard addresses, if the private key is not
+	// set. It is only supported for Mullvad. It can be the empty
+	// string to indicate it is not set, and cannot be nil in the
+	// internal state.
+	AccountNumber *string
 	// PreSharedKey is the Wireguard pre-shared key.
 	// It can be the empty string to indicate there
 	// is no pre-shared key.
@@ -24,6 +37,11 @@ type Wireguard struct {
 	PreSharedKey *string
 	// Addresses are the Wireguard interface addresses.
 	Addresses []netip.Prefix
+	// AllowedIPs are the IP networks routed through the
+	// Wireguard tunnel, set as the AllowedIPs of the peer.
+	// If empty, all IPv4 and IPv6 traffic is routed through
+	// the tunnel.
+	AllowedIPs []netip.Prefix
 	// Interface is the name of the Wireguard interface
 	// to create. It cannot be the empty string in the
 	// internal state.
@@ -37,6 +55,15 @@ type Wireguard struct {
 	// It defaults to "auto" and cannot be the empty string
 	// in the internal state.
 	Implementation string
+	// OpenVPNFallbackAttempts is the number of consecutive
+	// failed Wireguard handshakes after which OpenVPN over TCP
+	// is used instead, with the same provider and server selection.
+	// It can be set to 0 to disable the fallback, and cannot be nil
+	// in the internal state.
+	OpenVPNFallbackAttempts *uint8
+	// UDP2Raw contains settings to disguise the Wireguard
+	// UDP packets as TCP packets using udp2raw.
+	UDP2Raw UDP2Raw
 }
 
 var regexpInterfaceName = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
@@ -48,6 +75,9 @@ func (w Wireguard) validate(vpnProvider string, ipv6Supported bool) (err error)
 		providers.Custom,
 		providers.Ivpn,
 		providers.Mullvad,
+		providers.Nordvpn,
+		providers.Ovpn,
+		providers.Protonvpn,
 		providers.Surfshark,
 		providers.Windscribe,
 	) {
@@ -55,14 +85,32 @@ func (w Wireguard) validate(vpnProvider string, ipv6Supported bool) (err error)
 		return nil
 	}
 
+	// Validate AccessToken
+	if *w.AccessToken != "" && vpnProvider != providers.Nordvpn {
+		return fmt.Errorf("%w: for VPN service provider %s",
+			ErrWireguardAccessTokenSet, vpnProvider)
+	}
+
+	// Validate AccountNumber
+	if *w.AccountNumber != "" && vpnProvider != providers.Mullvad {
+		return fmt.Errorf("%w: for VPN service provider %s",
+			ErrWireguardAccountNumberSet, vpnProvider)
+	}
+
 	// Validate PrivateKey
-	if *w.PrivateKey == "" {
+	switch {
+	case *w.PrivateKey != "":
+		_, err = wgtypes.ParseKey(*w.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("private key is not valid: %w", err)
+		}
+	case *w.AccessToken != "":
+		// private key is fetched using the access token
+	case *w.AccountNumber != "":
+		// private key is generated and registered using the account number
+	default:
 		return fmt.Errorf("%w", ErrWireguardPrivateKeyNotSet)
 	}
-	_, err = wgtypes.ParseKey(*w.PrivateKey)
-	if err != nil {
-		return fmt.Errorf("private key is not valid: %w", err)
-	}
 
 	if vpnProvider == providers.Airvpn {
 		if *w.PreSharedKey == "" {
@@ -94,6 +142,12 @@ func (w Wireguard) validate(vpnProvider string, ipv6Supported bool) (err error)
 		}
 	}
 
+	for _, allowedIP := range w.AllowedIPs {
+		if !allowedIP.IsValid() {
+			return fmt.Errorf("%w: %s", ErrWireguardAllowedIPNotValid, allowedIP)
+		}
+	}
+
 	// Validate interface
 	if !regexpInterfaceName.MatchString(w.Interface) {
 		return fmt.Errorf("%w: '%s' does not match regex '%s'",
@@ -106,44 +160,68 @@ func (w Wireguard) validate(vpnProvider string, ipv6Supported bool) (err error)
 			w.Implementation, helpers.ChoicesOrString(validImplementations))
 	}
 
+	err = w.UDP2Raw.validate(vpnProvider)
+	if err != nil {
+		return fmt.Errorf("udp2raw settings: %w", err)
+	}
+
 	return nil
 }
 
 func (w *Wireguard) copy() (copied Wireguard) {
 	return Wireguard{
-		PrivateKey:     helpers.CopyPointer(w.PrivateKey),
-		PreSharedKey:   helpers.CopyPointer(w.PreSharedKey),
-		Addresses:      helpers.CopySlice(w.Addresses),
-		Interface:      w.Interface,
-		MTU:            w.MTU,
-		Implementation: w.Implementation,
+		PrivateKey:              helpers.CopyPointer(w.PrivateKey),
+		AccessToken:             helpers.CopyPointer(w.AccessToken),
+		AccountNumber:           helpers.CopyPointer(w.AccountNumber),
+		PreSharedKey:            helpers.CopyPointer(w.PreSharedKey),
+		Addresses:               helpers.CopySlice(w.Addresses),
+		AllowedIPs:              helpers.CopySlice(w.AllowedIPs),
+		Interface:               w.Interface,
+		MTU:                     w.MTU,
+		Implementation:          w.Implementation,
+		OpenVPNFallbackAttempts: helpers.CopyPointer(w.OpenVPNFallbackAttempts),
+		UDP2Raw:                 w.UDP2Raw.copy(),
 	}
 }
 
 func (w *Wireguard) mergeWith(other Wireguard) {
 	w.PrivateKey = helpers.MergeWithPointer(w.PrivateKey, other.PrivateKey)
+	w.AccessToken = helpers.MergeWithPointer(w.AccessToken, other.AccessToken)
+	w.AccountNumber = helpers.MergeWithPointer(w.AccountNumber, other.AccountNumber)
 	w.PreSharedKey = helpers.MergeWithPointer(w.PreSharedKey, other.PreSharedKey)
 	w.Addresses = helpers.MergeSlices(w.Addresses, other.Addresses)
+	w.AllowedIPs = helpers.MergeSlices(w.AllowedIPs, other.AllowedIPs)
 	w.Interface = helpers.MergeWithString(w.Interface, other.Interface)
 	w.MTU = helpers.MergeWithNumber(w.MTU, other.MTU)
 	w.Implementation = helpers.MergeWithString(w.Implementation, other.Implementation)
+	w.OpenVPNFallbackAttempts = helpers.MergeWithPointer(w.OpenVPNFallbackAttempts, other.OpenVPNFallbackAttempts)
+	w.UDP2Raw.mergeWith(other.UDP2Raw)
 }
 
 func (w *Wireguard) overrideWith(other Wireguard) {
 	w.PrivateKey = helpers.OverrideWithPointer(w.PrivateKey, other.PrivateKey)
+	w.AccessToken = helpers.OverrideWithPointer(w.AccessToken, other.AccessToken)
+	w.AccountNumber = helpers.OverrideWithPointer(w.AccountNumber, other.AccountNumber)
 	w.PreSharedKey = helpers.OverrideWithPointer(w.PreSharedKey, other.PreSharedKey)
 	w.Addresses = helpers.OverrideWithSlice(w.Addresses, other.Addresses)
+	w.AllowedIPs = helpers.OverrideWithSlice(w.AllowedIPs, other.AllowedIPs)
 	w.Interface = helpers.OverrideWithString(w.Interface, other.Interface)
 	w.MTU = helpers.OverrideWithNumber(w.MTU, other.MTU)
 	w.Implementation = helpers.OverrideWithString(w.Implementation, other.Implementation)
+	w.OpenVPNFallbackAttempts = helpers.OverrideWithPointer(w.OpenVPNFallbackAttempts, other.OpenVPNFallbackAttempts)
+	w.UDP2Raw.overrideWith(other.UDP2Raw)
 }
 
 func (w *Wireguard) setDefaults() {
 	w.PrivateKey = helpers.DefaultPointer(w.PrivateKey, "")
+	w.AccessToken = helpers.DefaultPointer(w.AccessToken, "")
+	w.AccountNumber = helpers.DefaultPointer(w.AccountNumber, "")
 	w.PreSharedKey = helpers.DefaultPointer(w.PreSharedKey, "")
 	w.Interface = helpers.DefaultString(w.Interface, "wg0")
 	w.MTU = helpers.DefaultNumber(w.MTU, wireguarddevice.DefaultMTU)
 	w.Implementation = helpers.DefaultString(w.Implementation, "auto")
+	w.OpenVPNFallbackAttempts = helpers.DefaultPointer(w.OpenVPNFallbackAttempts, 0)
+	w.UDP2Raw.setDefaults()
 }
 
 func (w Wireguard) String() string {
@@ -158,6 +236,14 @@ func (w Wireguard) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Private key: %s", s)
 	}
 
+	if *w.AccessToken != "" {
+		node.Appendf("Access token: %s", helpers.ObfuscatePassword(*w.AccessToken))
+	}
+
+	if *w.AccountNumber != "" {
+		node.Appendf("Account number: %s", helpers.ObfuscatePassword(*w.AccountNumber))
+	}
+
 	if *w.PreSharedKey != "" {
 		s := helpers.ObfuscateWireguardKey(*w.PreSharedKey)
 		node.Appendf("Pre-shared key: %s", s)
@@ -168,6 +254,13 @@ func (w Wireguard) toLinesNode() (node *gotree.Node) {
 		addressesNode.Appendf(address.String())
 	}
 
+	if len(w.AllowedIPs) > 0 {
+		allowedIPsNode := node.Appendf("Allowed IPs:")
+		for _, allowedIP := range w.AllowedIPs {
+			allowedIPsNode.Appendf(allowedIP.String())
+		}
+	}
+
 	interfaceNode := node.Appendf("Network interface: %s", w.Interface)
 	interfaceNode.Appendf("MTU: %d", w.MTU)
 
@@ -175,5 +268,13 @@ func (w Wireguard) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Implementation: %s", w.Implementation)
 	}
 
+	if *w.OpenVPNFallbackAttempts > 0 {
+		node.Appendf("OpenVPN fallback after failed handshakes: %d", *w.OpenVPNFallbackAttempts)
+	}
+
+	if *w.UDP2Raw.Enabled {
+		node.AppendNode(w.UDP2Raw.toLinesNode())
+	}
+
 	return node
 }
@@ -5,6 +5,7 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gotree"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -29,6 +30,12 @@ type WireguardSelection struct {
 	// It is only used with VPN providers generating Wireguard
 	// configurations specific to each server and user.
 	PublicKey string
+	// Protocol is the transport protocol to use to reach the
+	// Wireguard server. It can be "udp" or "tls", where "tls"
+	// tunnels the Wireguard packets in a TLS over TCP connection
+	// and is only supported by ProtonVPN (Stealth).
+	// It cannot be nil in the internal state.
+	Protocol *string
 }
 
 // Validate validates WireguardSelection settings.
@@ -37,6 +44,7 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 	// Validate EndpointIP
 	switch vpnProvider {
 	case providers.Airvpn, providers.Ivpn, providers.Mullvad,
+		providers.Nordvpn, providers.Ovpn, providers.Protonvpn,
 		providers.Surfshark, providers.Windscribe:
 		// endpoint IP addresses are baked in
 	case providers.Custom:
@@ -54,16 +62,17 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 			return fmt.Errorf("%w", ErrWireguardEndpointPortNotSet)
 		}
 	// EndpointPort cannot be set
-	case providers.Surfshark:
+	case providers.Nordvpn, providers.Surfshark:
 		if *w.EndpointPort != 0 {
 			return fmt.Errorf("%w", ErrWireguardEndpointPortSet)
 		}
-	case providers.Airvpn, providers.Ivpn, providers.Mullvad, providers.Windscribe:
+	case providers.Airvpn, providers.Ivpn, providers.Mullvad,
+		providers.Ovpn, providers.Protonvpn, providers.Windscribe:
 		// EndpointPort is optional and can be 0
 		if *w.EndpointPort == 0 {
 			break // no custom endpoint port set
 		}
-		if vpnProvider == providers.Mullvad {
+		if helpers.IsOneOf(vpnProvider, providers.Mullvad, providers.Ovpn) {
 			break // no restriction on custom endpoint port value
 		}
 		var allowed []uint16
@@ -72,6 +81,8 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 			allowed = []uint16{1637, 47107}
 		case providers.Ivpn:
 			allowed = []uint16{2049, 2050, 53, 30587, 41893, 48574, 58237}
+		case providers.Protonvpn:
+			allowed = []uint16{51820, 88, 443, 500, 1224, 4500}
 		case providers.Windscribe:
 			allowed = []uint16{53, 80, 123, 443, 1194, 65142}
 		}
@@ -87,8 +98,9 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 
 	// Validate PublicKey
 	switch vpnProvider {
-	case providers.Ivpn, providers.Mullvad,
-		providers.Surfshark, providers.Windscribe:
+	case providers.Ivpn, providers.Mullvad, providers.Nordvpn,
+		providers.Ovpn, providers.Protonvpn, providers.Surfshark,
+		providers.Windscribe:
 		// public keys are baked in
 	case providers.Custom:
 		if w.PublicKey == "" {
@@ -104,6 +116,18 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 		}
 	}
 
+	// Validate Protocol
+	switch *w.Protocol {
+	case constants.UDP:
+	case constants.TLS:
+		if vpnProvider != providers.Protonvpn {
+			return fmt.Errorf("%w: %s for VPN service provider %s",
+				ErrWireguardProtocolNotSupported, *w.Protocol, vpnProvider)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrWireguardProtocolNotValid, *w.Protocol)
+	}
+
 	return nil
 }
 
@@ -112,6 +136,7 @@ func (w *WireguardSelection) copy() (copied WireguardSelection) {
 		EndpointIP:   w.EndpointIP,
 		EndpointPort: helpers.CopyPointer(w.EndpointPort),
 		PublicKey:    w.PublicKey,
+		Protocol:     helpers.CopyPointer(w.Protocol),
 	}
 }
 
@@ -119,17 +144,20 @@ func (w *WireguardSelection) mergeWith(other WireguardSelection) {
 	w.EndpointIP = helpers.MergeWithIP(w.EndpointIP, other.EndpointIP)
 	w.EndpointPort = helpers.MergeWithPointer(w.EndpointPort, other.EndpointPort)
 	w.PublicKey = helpers.MergeWithString(w.PublicKey, other.PublicKey)
+	w.Protocol = helpers.MergeWithPointer(w.Protocol, other.Protocol)
 }
 
 func (w *WireguardSelection) overrideWith(other WireguardSelection) {
 	w.EndpointIP = helpers.OverrideWithIP(w.EndpointIP, other.EndpointIP)
 	w.EndpointPort = helpers.OverrideWithPointer(w.EndpointPort, other.EndpointPort)
 	w.PublicKey = helpers.OverrideWithString(w.PublicKey, other.PublicKey)
+	w.Protocol = helpers.OverrideWithPointer(w.Protocol, other.Protocol)
 }
 
 func (w *WireguardSelection) setDefaults() {
 	w.EndpointIP = helpers.DefaultIP(w.EndpointIP, netip.IPv4Unspecified())
 	w.EndpointPort = helpers.DefaultPointer(w.EndpointPort, 0)
+	w.Protocol = helpers.DefaultPointer(w.Protocol, constants.UDP)
 }
 
 func (w WireguardSelection) String() string {
@@ -151,5 +179,7 @@ func (w WireguardSelection) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Server public key: %s", w.PublicKey)
 	}
 
+	node.Appendf("Protocol: %s", *w.Protocol)
+
 	return node
 }
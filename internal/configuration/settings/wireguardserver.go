@@ -0,0 +1,187 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// WireguardServer contains settings to configure an inbound
+// Wireguard server, such that remote devices can connect to
+// it through the VPN forwarded port and have their traffic
+// routed out through the VPN tunnel.
+type WireguardServer struct {
+	// Enabled is true if the server should be running.
+	// It defaults to false, and cannot be nil in the internal state.
+	Enabled *bool
+	// PrivateKey is the Wireguard server private key.
+	// It cannot be nil in the internal state.
+	PrivateKey *string
+	// Interface is the name of the Wireguard interface
+	// to create. It cannot be the empty string in the
+	// internal state, and defaults to wgs0.
+	Interface string
+	// ListenPort is the UDP port the server listens on until
+	// the VPN provider forwards a port, on which the server
+	// then listens instead. It defaults to 51820 and cannot
+	// be zero in the internal state.
+	ListenPort uint16
+	// Address is the server interface address, whose network
+	// contains the addresses of the peers. It defaults to
+	// 10.64.0.1/24 and must be valid in the internal state.
+	Address netip.Prefix
+	// Peers are the remote devices allowed to connect.
+	Peers []WireguardServerPeer
+}
+
+// WireguardServerPeer is a remote device allowed to
+// connect to the Wireguard server.
+type WireguardServerPeer struct {
+	// PublicKey is the Wireguard public key of the device.
+	PublicKey string `json:"public_key"`
+	// PreSharedKey is the Wireguard pre-shared key and can
+	// be the empty string if there is no pre-shared key.
+	PreSharedKey string `json:"preshared_key,omitempty"`
+	// Address is the device interface address, which must be
+	// in the network of the server interface address.
+	Address netip.Prefix `json:"address"`
+}
+
+func (w WireguardServer) validate(vpnInterface string) (err error) {
+	if !*w.Enabled {
+		return nil
+	}
+
+	_, err = wgtypes.ParseKey(*w.PrivateKey)
+	if err != nil {
+		if *w.PrivateKey == "" {
+			return fmt.Errorf("%w", ErrWireguardPrivateKeyNotSet)
+		}
+		return fmt.Errorf("private key is not valid: %w", err)
+	}
+
+	if !regexpInterfaceName.MatchString(w.Interface) {
+		return fmt.Errorf("%w: '%s' does not match regex '%s'",
+			ErrWireguardInterfaceNotValid, w.Interface, regexpInterfaceName)
+	} else if w.Interface == vpnInterface {
+		return fmt.Errorf("%w: %s", ErrSecondaryInterfaceConflict, w.Interface)
+	}
+
+	if !w.Address.IsValid() || !w.Address.Addr().Is4() {
+		return fmt.Errorf("%w: %s must be an IPv4 prefix",
+			ErrWireguardInterfaceAddressNotSet, w.Address)
+	}
+
+	publicKeys := make(map[string]struct{}, len(w.Peers))
+	for i, peer := range w.Peers {
+		err = peer.Validate(w.Address)
+		if err != nil {
+			return fmt.Errorf("peer %d of %d: %w", i+1, len(w.Peers), err)
+		}
+		if _, ok := publicKeys[peer.PublicKey]; ok {
+			return fmt.Errorf("%w: %s", ErrWireguardServerPeerDuplicate, peer.PublicKey)
+		}
+		publicKeys[peer.PublicKey] = struct{}{}
+	}
+
+	return nil
+}
+
+// Validate returns an error if the peer is not valid
+// for the server interface address given.
+func (p WireguardServerPeer) Validate(serverAddress netip.Prefix) (err error) {
+	if p.PublicKey == "" {
+		return fmt.Errorf("%w", ErrWireguardPublicKeyNotSet)
+	}
+	_, err = wgtypes.ParseKey(p.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrWireguardPublicKeyNotValid, p.PublicKey, err)
+	}
+
+	if p.PreSharedKey != "" {
+		_, err = wgtypes.ParseKey(p.PreSharedKey)
+		if err != nil {
+			return fmt.Errorf("pre-shared key is not valid: %w", err)
+		}
+	}
+
+	if !p.Address.IsValid() || !serverAddress.Masked().Contains(p.Address.Addr()) ||
+		p.Address.Addr() == serverAddress.Addr() {
+		return fmt.Errorf("%w: %s is not in the server network %s",
+			ErrWireguardServerPeerAddressNotValid, p.Address, serverAddress.Masked())
+	}
+
+	return nil
+}
+
+func (w *WireguardServer) copy() (copied WireguardServer) {
+	copied = WireguardServer{
+		Enabled:    helpers.CopyPointer(w.Enabled),
+		PrivateKey: helpers.CopyPointer(w.PrivateKey),
+		Interface:  w.Interface,
+		ListenPort: w.ListenPort,
+		Address:    w.Address,
+	}
+	if w.Peers != nil {
+		copied.Peers = make([]WireguardServerPeer, len(w.Peers))
+		copy(copied.Peers, w.Peers)
+	}
+	return copied
+}
+
+func (w *WireguardServer) mergeWith(other WireguardServer) {
+	w.Enabled = helpers.MergeWithPointer(w.Enabled, other.Enabled)
+	w.PrivateKey = helpers.MergeWithPointer(w.PrivateKey, other.PrivateKey)
+	w.Interface = helpers.MergeWithString(w.Interface, other.Interface)
+	w.ListenPort = helpers.MergeWithNumber(w.ListenPort, other.ListenPort)
+	w.Address = helpers.MergeWithIPPrefix(w.Address, other.Address)
+	if w.Peers == nil {
+		w.Peers = other.Peers
+	}
+}
+
+func (w *WireguardServer) overrideWith(other WireguardServer) {
+	w.Enabled = helpers.OverrideWithPointer(w.Enabled, other.Enabled)
+	w.PrivateKey = helpers.OverrideWithPointer(w.PrivateKey, other.PrivateKey)
+	w.Interface = helpers.OverrideWithString(w.Interface, other.Interface)
+	w.ListenPort = helpers.OverrideWithNumber(w.ListenPort, other.ListenPort)
+	w.Address = helpers.OverrideWithIPPrefix(w.Address, other.Address)
+	w.Peers = helpers.OverrideWithSlice(w.Peers, other.Peers)
+}
+
+func (w *WireguardServer) setDefaults() {
+	w.Enabled = helpers.DefaultPointer(w.Enabled, false)
+	w.PrivateKey = helpers.DefaultPointer(w.PrivateKey, "")
+	w.Interface = helpers.DefaultString(w.Interface, "wgs0")
+	const defaultListenPort = 51820
+	w.ListenPort = helpers.DefaultNumber(w.ListenPort, defaultListenPort)
+	defaultAddress := netip.PrefixFrom(netip.AddrFrom4([4]byte{10, 64, 0, 1}), 24) //nolint:gomnd
+	w.Address = helpers.DefaultIPPrefix(w.Address, defaultAddress)
+}
+
+func (w WireguardServer) String() string {
+	return w.toLinesNode().String()
+}
+
+func (w WireguardServer) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Wireguard server settings:")
+
+	node.Appendf("Enabled: %s", helpers.BoolPtrToYesNo(w.Enabled))
+	if !*w.Enabled {
+		return node
+	}
+
+	node.Appendf("Private key: %s", helpers.ObfuscateWireguardKey(*w.PrivateKey))
+	node.Appendf("Interface: %s", w.Interface)
+	node.Appendf("Listening port: %d", w.ListenPort)
+	node.Appendf("Address: %s", w.Address)
+	peersNode := node.Appendf("Peers:")
+	for _, peer := range w.Peers {
+		peersNode.Appendf("%s: %s", helpers.ObfuscateWireguardKey(peer.PublicKey), peer.Address)
+	}
+
+	return node
+}
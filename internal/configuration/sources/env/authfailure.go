@@ -0,0 +1,21 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readAuthFailure() (authFailure settings.AuthFailure, err error) {
+	authFailure.Policy = strings.ToLower(getCleanedEnv("VPN_AUTH_FAILURE_POLICY"))
+
+	attempts, err := envToUint8Ptr("VPN_AUTH_FAILURE_ATTEMPTS")
+	if err != nil {
+		return authFailure, fmt.Errorf("environment variable VPN_AUTH_FAILURE_ATTEMPTS: %w", err)
+	} else if attempts != nil {
+		authFailure.Attempts = *attempts
+	}
+
+	return authFailure, nil
+}
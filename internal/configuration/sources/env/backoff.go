@@ -0,0 +1,36 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readBackoff() (backoff settings.Backoff, err error) {
+	initial, err := envToDurationPtr("VPN_BACKOFF_INITIAL")
+	if err != nil {
+		return backoff, fmt.Errorf("environment variable VPN_BACKOFF_INITIAL: %w", err)
+	} else if initial != nil {
+		backoff.Initial = *initial
+	}
+
+	backoff.Multiplier, err = envToFloat64("VPN_BACKOFF_MULTIPLIER")
+	if err != nil {
+		return backoff, fmt.Errorf("environment variable VPN_BACKOFF_MULTIPLIER: %w", err)
+	}
+
+	backoff.Maximum, err = envToDurationPtr("VPN_BACKOFF_MAXIMUM")
+	if err != nil {
+		return backoff, fmt.Errorf("environment variable VPN_BACKOFF_MAXIMUM: %w", err)
+	}
+
+	if getCleanedEnv("VPN_BACKOFF_JITTER") != "" {
+		jitter, err := envToFloat64("VPN_BACKOFF_JITTER")
+		if err != nil {
+			return backoff, fmt.Errorf("environment variable VPN_BACKOFF_JITTER: %w", err)
+		}
+		backoff.Jitter = &jitter
+	}
+
+	return backoff, nil
+}
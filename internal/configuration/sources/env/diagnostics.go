@@ -0,0 +1,25 @@
+package env
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readDiagnostics() (diagnostics settings.Diagnostics, err error) {
+	diagnostics.Enabled, err = envToBoolPtr("DIAGNOSTICS")
+	if err != nil {
+		return diagnostics, fmt.Errorf("environment variable DIAGNOSTICS: %w", err)
+	}
+
+	if s := getCleanedEnv("DIAGNOSTICS_DNS_SERVER"); s != "" {
+		diagnostics.DNSServer = new(netip.AddrPort)
+		*diagnostics.DNSServer, err = netip.ParseAddrPort(s)
+		if err != nil {
+			return diagnostics, fmt.Errorf("environment variable DIAGNOSTICS_DNS_SERVER: %w", err)
+		}
+	}
+
+	return diagnostics, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/govalid/binary"
@@ -33,6 +34,21 @@ func (s *Source) readDNSBlacklist() (blacklist settings.DNSBlacklist, err error)
 
 	blacklist.AllowedHosts = envToCSV("UNBLOCK") // TODO v4 change name
 
+	// URLs and file paths are case sensitive so envToCSV cannot be used.
+	if sources := getCleanedEnv("BLOCKLIST_SOURCES"); sources != "" {
+		blacklist.BlocklistSources = strings.Split(sources, ",")
+		for i, source := range blacklist.BlocklistSources {
+			blacklist.BlocklistSources[i] = strings.TrimSpace(source)
+		}
+	}
+
+	blacklist.RebindingProtection, err = envToBoolPtr("DOT_REBINDING_PROTECTION")
+	if err != nil {
+		return blacklist, fmt.Errorf("environment variable DOT_REBINDING_PROTECTION: %w", err)
+	}
+
+	blacklist.RebindingAllowedHosts = envToCSV("DOT_REBINDING_ALLOWED_HOSTS")
+
 	return blacklist, nil
 }
 
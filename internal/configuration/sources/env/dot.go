@@ -17,6 +17,11 @@ func (s *Source) readDoT() (dot settings.DoT, err error) {
 		return dot, fmt.Errorf("environment variable DNS_UPDATE_PERIOD: %w", err)
 	}
 
+	dot.UpstreamCheckPeriod, err = envToDurationPtr("DOT_UPSTREAM_CHECK_PERIOD")
+	if err != nil {
+		return dot, fmt.Errorf("environment variable DOT_UPSTREAM_CHECK_PERIOD: %w", err)
+	}
+
 	dot.Unbound, err = readUnbound()
 	if err != nil {
 		return dot, err
@@ -0,0 +1,27 @@
+package env
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readEgressProxy() (egressProxy settings.EgressProxy, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"EGRESS_PROXY_PASSWORD"}, err)
+	}()
+
+	if s := getCleanedEnv("EGRESS_PROXY"); s != "" {
+		egressProxy.Address = new(netip.AddrPort)
+		*egressProxy.Address, err = netip.ParseAddrPort(s)
+		if err != nil {
+			return egressProxy, fmt.Errorf("environment variable EGRESS_PROXY: %w", err)
+		}
+	}
+
+	egressProxy.Username = envToStringPtr("EGRESS_PROXY_USERNAME")
+	egressProxy.Password = envToStringPtr("EGRESS_PROXY_PASSWORD")
+
+	return egressProxy, nil
+}
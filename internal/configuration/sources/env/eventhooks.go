@@ -0,0 +1,38 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+func readEventHooks() (eventHooks settings.EventHooks, err error) {
+	for _, event := range models.HookEvents() {
+		command := getCleanedEnv("EVENT_HOOK_" + strings.ToUpper(event))
+		if command == "" {
+			continue
+		}
+		if eventHooks.Commands == nil {
+			eventHooks.Commands = make(map[string]string)
+		}
+		eventHooks.Commands[event] = command
+	}
+
+	timeout, err := envToDurationPtr("EVENT_HOOKS_TIMEOUT")
+	if err != nil {
+		return eventHooks, fmt.Errorf("environment variable EVENT_HOOKS_TIMEOUT: %w", err)
+	} else if timeout != nil {
+		eventHooks.Timeout = *timeout
+	}
+
+	maxConcurrent, err := envToUint8Ptr("EVENT_HOOKS_MAX_CONCURRENT")
+	if err != nil {
+		return eventHooks, fmt.Errorf("environment variable EVENT_HOOKS_MAX_CONCURRENT: %w", err)
+	} else if maxConcurrent != nil {
+		eventHooks.MaxConcurrent = *maxConcurrent
+	}
+
+	return eventHooks, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/netip"
 	"strconv"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
@@ -23,11 +24,22 @@ func (s *Source) readFirewall() (firewall settings.Firewall, err error) {
 	}
 
 	outboundSubnetsKey, _ := s.getEnvWithRetro("FIREWALL_OUTBOUND_SUBNETS", "EXTRA_SUBNETS")
-	outboundSubnetStrings := envToCSV(outboundSubnetsKey)
+	outboundSubnetStrings, outboundHostnames := splitSubnetsAndHostnames(envToCSV(outboundSubnetsKey))
 	firewall.OutboundSubnets, err = stringsToNetipPrefixes(outboundSubnetStrings)
 	if err != nil {
 		return firewall, fmt.Errorf("environment variable %s: %w", outboundSubnetsKey, err)
 	}
+	firewall.OutboundHostnames = outboundHostnames
+
+	firewall.OutboundHostnamesPeriod, err = envToDurationPtr("FIREWALL_OUTBOUND_HOSTNAMES_PERIOD")
+	if err != nil {
+		return firewall, fmt.Errorf("environment variable FIREWALL_OUTBOUND_HOSTNAMES_PERIOD: %w", err)
+	}
+
+	firewall.LocalNetworksPeriod, err = envToDurationPtr("FIREWALL_LOCAL_NETWORKS_PERIOD")
+	if err != nil {
+		return firewall, fmt.Errorf("environment variable FIREWALL_LOCAL_NETWORKS_PERIOD: %w", err)
+	}
 
 	firewall.Enabled, err = envToBoolPtr("FIREWALL")
 	if err != nil {
@@ -39,6 +51,20 @@ func (s *Source) readFirewall() (firewall settings.Firewall, err error) {
 		return firewall, fmt.Errorf("environment variable FIREWALL_DEBUG: %w", err)
 	}
 
+	firewall.PauseKillSwitch = getCleanedEnv("FIREWALL_PAUSE_KILL_SWITCH")
+
+	firewall.MulticastDiscovery, err = envToBoolPtr("FIREWALL_MULTICAST_DISCOVERY")
+	if err != nil {
+		return firewall, fmt.Errorf("environment variable FIREWALL_MULTICAST_DISCOVERY: %w", err)
+	}
+
+	firewall.Interfaces = envToCSV("FIREWALL_INTERFACES")
+
+	firewall.RestoreHostRules, err = envToBoolPtr("FIREWALL_RESTORE_HOST_RULES")
+	if err != nil {
+		return firewall, fmt.Errorf("environment variable FIREWALL_RESTORE_HOST_RULES: %w", err)
+	}
+
 	return firewall, nil
 }
 
@@ -65,6 +91,22 @@ func stringsToPorts(ss []string) (ports []uint16, err error) {
 	return ports, nil
 }
 
+// splitSubnetsAndHostnames splits values into subnets and hostnames,
+// where a value is considered a hostname if it is neither an IP network
+// nor an IP address, the latter being reported as not valid later on.
+func splitSubnetsAndHostnames(values []string) (subnets, hostnames []string) {
+	for _, value := range values {
+		_, prefixErr := netip.ParsePrefix(value)
+		_, addrErr := netip.ParseAddr(value)
+		if prefixErr != nil && addrErr != nil && !strings.Contains(value, "/") {
+			hostnames = append(hostnames, value)
+			continue
+		}
+		subnets = append(subnets, value)
+	}
+	return subnets, hostnames
+}
+
 func stringsToNetipPrefixes(ss []string) (ipPrefixes []netip.Prefix, err error) {
 	if len(ss) == 0 {
 		return nil, nil
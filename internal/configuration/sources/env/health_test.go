@@ -0,0 +1,50 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHealthTargets(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		targets    []settings.HealthTarget
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"targets with and without options": {
+			s: "cloudflare.com:443, TCP6://1.1.1.1:853@5s,example.com@500ms",
+			targets: []settings.HealthTarget{
+				{Address: "cloudflare.com:443"},
+				{Address: "1.1.1.1:853", Network: "tcp6", Timeout: 5 * time.Second},
+				{Address: "example.com", Timeout: 500 * time.Millisecond},
+			},
+		},
+		"missing address": {
+			s:          "tcp://@1s",
+			errWrapped: ErrHealthTargetNotValid,
+			errMessage: `health target is not valid: "tcp://@1s" does not have the format [network://]address[@timeout]`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			targets, err := parseHealthTargets(testCase.s)
+
+			assert.Equal(t, testCase.targets, targets)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+var ErrSamplesNegative = errors.New("number of samples cannot be negative")
+
+func readHealthLatency() (latency settings.HealthLatency, err error) {
+	latency.Threshold, err = envToDurationPtr("HEALTH_LATENCY_THRESHOLD")
+	if err != nil {
+		return latency, fmt.Errorf("environment variable HEALTH_LATENCY_THRESHOLD: %w", err)
+	}
+
+	period, err := envToDurationPtr("HEALTH_LATENCY_PERIOD")
+	if err != nil {
+		return latency, fmt.Errorf("environment variable HEALTH_LATENCY_PERIOD: %w", err)
+	} else if period != nil {
+		latency.Period = *period
+	}
+
+	samples, err := envToIntPtr("HEALTH_LATENCY_SAMPLES")
+	if err != nil {
+		return latency, fmt.Errorf("environment variable HEALTH_LATENCY_SAMPLES: %w", err)
+	} else if samples != nil {
+		if *samples < 0 {
+			return latency, fmt.Errorf("environment variable HEALTH_LATENCY_SAMPLES: %w: %d",
+				ErrSamplesNegative, *samples)
+		}
+		latency.Samples = uint(*samples)
+	}
+
+	latency.RecoveryRatio, err = envToFloat64("HEALTH_LATENCY_RECOVERY_RATIO")
+	if err != nil {
+		return latency, fmt.Errorf("environment variable HEALTH_LATENCY_RECOVERY_RATIO: %w", err)
+	}
+
+	return latency, nil
+}
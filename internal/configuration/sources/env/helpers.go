@@ -1,7 +1,9 @@
 package env
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"strconv"
 	"strings"
@@ -111,7 +113,7 @@ func envToDurationPtr(envKey string) (durationPtr *time.Duration, err error) {
 	}
 
 	durationPtr = new(time.Duration)
-	*durationPtr, err = time.ParseDuration(s)
+	*durationPtr, err = parseDuration(s)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +121,45 @@ func envToDurationPtr(envKey string) (durationPtr *time.Duration, err error) {
 	return durationPtr, nil
 }
 
+func envToSizePtr(envKey string) (sizePtr *uint64, err error) {
+	s := getCleanedEnv(envKey)
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	sizePtr = new(uint64)
+	*sizePtr, err = parseSize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return sizePtr, nil
+}
+
+var ErrFileModeNotValid = errors.New("file mode is not valid")
+
+// envToFileModePtr parses the octal permission bits
+// from the environment variable, for example 0644.
+func envToFileModePtr(envKey string) (fileModePtr *fs.FileMode, err error) {
+	s := getCleanedEnv(envKey)
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	const base, bitSize = 8, 32
+	value, err := strconv.ParseUint(s, base, bitSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFileModeNotValid, err)
+	} else if value > uint64(fs.ModePerm) {
+		return nil, fmt.Errorf("%w: %s must be at most %04o",
+			ErrFileModeNotValid, s, uint32(fs.ModePerm))
+	}
+
+	fileModePtr = new(fs.FileMode)
+	*fileModePtr = fs.FileMode(value)
+	return fileModePtr, nil
+}
+
 func lowerAndSplit(csv string) (values []string) {
 	csv = strings.ToLower(csv)
 	return strings.Split(csv, ",")
@@ -1,6 +1,7 @@
 package env
 
 import (
+	"io/fs"
 	"os"
 	"testing"
 
@@ -20,3 +21,54 @@ func setTestEnv(t *testing.T, key, value string) {
 	})
 	require.NoError(t, err)
 }
+
+func Test_envToFileModePtr(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value      string
+		fileMode   *fs.FileMode
+		errWrapped error
+		errMessage string
+	}{
+		"empty string": {},
+		"valid octal": {
+			value:    "0640",
+			fileMode: fileModePtr(0640),
+		},
+		"valid octal without leading zero": {
+			value:    "600",
+			fileMode: fileModePtr(0600),
+		},
+		"not octal": {
+			value:      "0688",
+			errWrapped: ErrFileModeNotValid,
+			errMessage: `file mode is not valid: strconv.ParseUint: parsing "0688": invalid syntax`,
+		},
+		"too large": {
+			value:      "01777",
+			errWrapped: ErrFileModeNotValid,
+			errMessage: "file mode is not valid: 01777 must be at most 0777",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			envKey := "FILE_MODE_" + t.Name()
+			setTestEnv(t, envKey, testCase.value)
+
+			fileMode, err := envToFileModePtr(envKey)
+
+			assert.Equal(t, testCase.fileMode, fileMode)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func fileModePtr(fileMode fs.FileMode) *fs.FileMode { return &fileMode }
@@ -0,0 +1,26 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readHooks() (hooks settings.Hooks, err error) {
+	hooks.PreUp = envToStringPtr("VPN_HOOK_PRE_UP")
+	hooks.PostUp = envToStringPtr("VPN_HOOK_POST_UP")
+	hooks.PreDown = envToStringPtr("VPN_HOOK_PRE_DOWN")
+	hooks.PostDown = envToStringPtr("VPN_HOOK_POST_DOWN")
+
+	timeout, err := envToDurationPtr("VPN_HOOK_TIMEOUT")
+	if err != nil {
+		return hooks, fmt.Errorf("environment variable VPN_HOOK_TIMEOUT: %w", err)
+	} else if timeout != nil {
+		hooks.Timeout = *timeout
+	}
+
+	hooks.FailurePolicy = strings.ToLower(getCleanedEnv("VPN_HOOK_FAILURE_POLICY"))
+
+	return hooks, nil
+}
@@ -27,6 +27,26 @@ func (s *Source) readHTTPProxy() (httpProxy settings.HTTPProxy, err error) {
 		return httpProxy, err
 	}
 
+	httpProxy.ProviderSOCKSServer = envToStringPtr("HTTPPROXY_PROVIDER_SOCKS_SERVER")
+
+	httpProxy.AllowedDestinations = envToCSV("HTTPPROXY_ALLOWED_DESTINATIONS")
+	httpProxy.DeniedDestinations = envToCSV("HTTPPROXY_DENIED_DESTINATIONS")
+
+	httpProxy.AllowedPorts, err = stringsToPorts(envToCSV("HTTPPROXY_ALLOWED_PORTS"))
+	if err != nil {
+		return httpProxy, fmt.Errorf("environment variable HTTPPROXY_ALLOWED_PORTS: %w", err)
+	}
+
+	httpProxy.DeniedPorts, err = stringsToPorts(envToCSV("HTTPPROXY_DENIED_PORTS"))
+	if err != nil {
+		return httpProxy, fmt.Errorf("environment variable HTTPPROXY_DENIED_PORTS: %w", err)
+	}
+
+	httpProxy.ConnectPorts, err = stringsToPorts(envToCSV("HTTPPROXY_CONNECT_PORTS"))
+	if err != nil {
+		return httpProxy, fmt.Errorf("environment variable HTTPPROXY_CONNECT_PORTS: %w", err)
+	}
+
 	return httpProxy, nil
 }
 
@@ -0,0 +1,21 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readLANGateway() (lanGateway settings.LANGateway, err error) {
+	lanGateway.Enabled, err = envToBoolPtr("LAN_GATEWAY")
+	if err != nil {
+		return lanGateway, fmt.Errorf("environment variable LAN_GATEWAY: %w", err)
+	}
+
+	lanGateway.Subnets, err = stringsToNetipPrefixes(envToCSV("LAN_GATEWAY_SUBNETS"))
+	if err != nil {
+		return lanGateway, fmt.Errorf("environment variable LAN_GATEWAY_SUBNETS: %w", err)
+	}
+
+	return lanGateway, nil
+}
@@ -15,9 +15,49 @@ func readLog() (log settings.Log, err error) {
 		return log, err
 	}
 
+	log.Syslog = readSyslog()
+
+	log.File, err = readLogFile()
+	if err != nil {
+		return log, err
+	}
+
 	return log, nil
 }
 
+func readLogFile() (file settings.LogFile, err error) {
+	file.Path = envToStringPtr("LOG_FILE_PATH")
+
+	file.MaxSize, err = envToSizePtr("LOG_FILE_MAX_SIZE")
+	if err != nil {
+		return file, fmt.Errorf("environment variable LOG_FILE_MAX_SIZE: %w", err)
+	}
+
+	file.MaxAge, err = envToDurationPtr("LOG_FILE_MAX_AGE")
+	if err != nil {
+		return file, fmt.Errorf("environment variable LOG_FILE_MAX_AGE: %w", err)
+	}
+
+	file.MaxBackups, err = envToUint8Ptr("LOG_FILE_MAX_BACKUPS")
+	if err != nil {
+		return file, fmt.Errorf("environment variable LOG_FILE_MAX_BACKUPS: %w", err)
+	}
+
+	file.Stdout, err = envToBoolPtr("LOG_FILE_STDOUT")
+	if err != nil {
+		return file, fmt.Errorf("environment variable LOG_FILE_STDOUT: %w", err)
+	}
+
+	return file, nil
+}
+
+func readSyslog() (syslog settings.Syslog) {
+	syslog.Address = envToStringPtr("LOG_SYSLOG_ADDRESS")
+	syslog.Protocol = strings.ToLower(getCleanedEnv("LOG_SYSLOG_PROTOCOL"))
+	syslog.Facility = strings.ToLower(getCleanedEnv("LOG_SYSLOG_FACILITY"))
+	return syslog
+}
+
 func readLogLevel() (level *log.Level, err error) {
 	s := getCleanedEnv("LOG_LEVEL")
 	if s == "" {
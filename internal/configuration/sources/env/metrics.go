@@ -0,0 +1,23 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readMetrics() (metrics settings.Metrics, err error) {
+	metrics.Address = envToStringPtr("METRICS_ADDRESS")
+	metrics.Format = strings.ToLower(getCleanedEnv("METRICS_FORMAT"))
+	metrics.Prefix = getCleanedEnv("METRICS_PREFIX")
+
+	period, err := envToDurationPtr("METRICS_PERIOD")
+	if err != nil {
+		return metrics, fmt.Errorf("environment variable METRICS_PERIOD: %w", err)
+	} else if period != nil {
+		metrics.Period = *period
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,39 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readNotify() (notify settings.Notify, err error) {
+	notify.TelegramToken = envToStringPtr("NOTIFY_TELEGRAM_TOKEN")
+	notify.TelegramChatID = envToStringPtr("NOTIFY_TELEGRAM_CHAT_ID")
+	notify.DiscordWebhookURL = envToStringPtr("NOTIFY_DISCORD_WEBHOOK_URL")
+	notify.WebhookURL = envToStringPtr("NOTIFY_WEBHOOK_URL")
+
+	// URLs are case sensitive so envToCSV cannot be used.
+	if urls := getCleanedEnv("NOTIFICATION_URLS"); urls != "" {
+		notify.URLs = strings.Split(urls, ",")
+		for i, url := range notify.URLs {
+			notify.URLs[i] = strings.TrimSpace(url)
+		}
+	}
+
+	tunnelDownAfter, err := envToDurationPtr("NOTIFY_TUNNEL_DOWN_AFTER")
+	if err != nil {
+		return notify, fmt.Errorf("environment variable NOTIFY_TUNNEL_DOWN_AFTER: %w", err)
+	} else if tunnelDownAfter != nil {
+		notify.TunnelDownAfter = *tunnelDownAfter
+	}
+
+	authFailures, err := envToUint8Ptr("NOTIFY_AUTH_FAILURES")
+	if err != nil {
+		return notify, fmt.Errorf("environment variable NOTIFY_AUTH_FAILURES: %w", err)
+	} else if authFailures != nil {
+		notify.AuthFailures = *authFailures
+	}
+
+	return notify, nil
+}
@@ -12,7 +12,8 @@ func (s *Source) readOpenVPN() (
 	openVPN settings.OpenVPN, err error) {
 	defer func() {
 		err = unsetEnvKeys([]string{"OPENVPN_KEY", "OPENVPN_CERT",
-			"OPENVPN_KEY_PASSPHRASE", "OPENVPN_ENCRYPTED_KEY"}, err)
+			"OPENVPN_KEY_PASSPHRASE", "OPENVPN_ENCRYPTED_KEY",
+			"OPENVPN_TLS_CRYPT_V2", "OPENVPN_STATIC_KEY"}, err)
 	}()
 
 	openVPN.Version = getCleanedEnv("OPENVPN_VERSION")
@@ -34,6 +35,8 @@ func (s *Source) readOpenVPN() (
 	openVPN.Cert = envToStringPtr("OPENVPN_CERT")
 	openVPN.Key = envToStringPtr("OPENVPN_KEY")
 	openVPN.EncryptedKey = envToStringPtr("OPENVPN_ENCRYPTED_KEY")
+	openVPN.TLSCryptV2 = envToStringPtr("OPENVPN_TLS_CRYPT_V2")
+	openVPN.StaticKey = envToStringPtr("OPENVPN_STATIC_KEY")
 
 	openVPN.KeyPassphrase = s.readOpenVPNKeyPassphrase()
 
@@ -44,6 +47,16 @@ func (s *Source) readOpenVPN() (
 		return openVPN, fmt.Errorf("environment variable OPENVPN_MSSFIX: %w", err)
 	}
 
+	openVPN.Fragment, err = envToUint16Ptr("OPENVPN_FRAGMENT")
+	if err != nil {
+		return openVPN, fmt.Errorf("environment variable OPENVPN_FRAGMENT: %w", err)
+	}
+
+	openVPN.TunMTU, err = envToUint16Ptr("OPENVPN_TUN_MTU")
+	if err != nil {
+		return openVPN, fmt.Errorf("environment variable OPENVPN_TUN_MTU: %w", err)
+	}
+
 	_, openVPN.Interface = s.getEnvWithRetro("VPN_INTERFACE", "OPENVPN_INTERFACE")
 
 	openVPN.ProcessUser, err = s.readOpenVPNProcessUser()
@@ -2,12 +2,17 @@ package env
 
 import (
 	"fmt"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
 func (s *Source) readPortForward() (
 	portForwarding settings.PortForwarding, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"VPN_PORT_FORWARDING_PASSWORD"}, err)
+	}()
+
 	key, _ := s.getEnvWithRetro(
 		"VPN_PORT_FORWARDING",
 		"PRIVATE_INTERNET_ACCESS_VPN_PORT_FORWARDING",
@@ -25,5 +30,42 @@ func (s *Source) readPortForward() (
 		portForwarding.Filepath = stringPtr(value)
 	}
 
+	const permissionsKey = "VPN_PORT_FORWARDING_STATUS_FILE_PERMISSIONS"
+	portForwarding.FilePermissions, err = envToFileModePtr(permissionsKey)
+	if err != nil {
+		return portForwarding, fmt.Errorf("environment variable %s: %w", permissionsKey, err)
+	}
+
+	portForwarding.Username = envToStringPtr("VPN_PORT_FORWARDING_USERNAME")
+	portForwarding.Password = envToStringPtr("VPN_PORT_FORWARDING_PASSWORD")
+
+	portForwarding.DNATDestination, err = readPortForwardDNATDestination()
+	if err != nil {
+		return portForwarding, fmt.Errorf("environment variable VPN_PORT_FORWARDING_DNAT_DESTINATION: %w", err)
+	}
+
 	return portForwarding, nil
 }
+
+// readPortForwardDNATDestination reads the DNAT destination
+// in the format ip[:port], where the port defaults to 0 to
+// indicate the forwarded port should be used.
+func readPortForwardDNATDestination() (destination *netip.AddrPort, err error) {
+	s := getCleanedEnv("VPN_PORT_FORWARDING_DNAT_DESTINATION")
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	destination = new(netip.AddrPort)
+	*destination, err = netip.ParseAddrPort(s)
+	if err == nil {
+		return destination, nil
+	}
+
+	ip, ipErr := netip.ParseAddr(s)
+	if ipErr != nil {
+		return nil, err
+	}
+	*destination = netip.AddrPortFrom(ip, 0)
+	return destination, nil
+}
@@ -26,6 +26,11 @@ func (s *Source) readProvider(vpnType string) (provider settings.Provider, err e
 		return provider, fmt.Errorf("port forwarding: %w", err)
 	}
 
+	provider.CredentialsCheck, err = envToBoolPtr("VPN_CREDENTIALS_CHECK")
+	if err != nil {
+		return provider, fmt.Errorf("environment variable VPN_CREDENTIALS_CHECK: %w", err)
+	}
+
 	return provider, nil
 }
 
@@ -1,7 +1,9 @@
 package env
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
@@ -15,9 +17,69 @@ func (s *Source) readPublicIP() (publicIP settings.PublicIP, err error) {
 
 	publicIP.IPFilepath = s.readPublicIPFilepath()
 
+	publicIP.IPFilePermissions, err = envToFileModePtr("PUBLICIP_FILE_PERMISSIONS")
+	if err != nil {
+		return publicIP, fmt.Errorf("environment variable PUBLICIP_FILE_PERMISSIONS: %w", err)
+	}
+
+	publicIP.Timeout, err = envToDurationPtr("PUBLICIP_TIMEOUT")
+	if err != nil {
+		return publicIP, fmt.Errorf("environment variable PUBLICIP_TIMEOUT: %w", err)
+	}
+
+	publicIP.Retries, err = envToUint8Ptr("PUBLICIP_RETRIES")
+	if err != nil {
+		return publicIP, fmt.Errorf("environment variable PUBLICIP_RETRIES: %w", err)
+	}
+
+	publicIP.Jitter, err = envToDurationPtr("PUBLICIP_JITTER")
+	if err != nil {
+		return publicIP, fmt.Errorf("environment variable PUBLICIP_JITTER: %w", err)
+	}
+
+	publicIP.API, err = parsePublicIPAPIKeys(getCleanedEnv("PUBLICIP_API_KEYS"))
+	if err != nil {
+		return publicIP, fmt.Errorf("environment variable PUBLICIP_API_KEYS: %w", err)
+	}
+	publicIP.API.URL = envToStringPtr("PUBLICIP_API_URL")
+
 	return publicIP, nil
 }
 
+var ErrPublicIPAPIKeyNotValid = errors.New("public IP API key mapping is not valid")
+
+// parsePublicIPAPIKeys parses a comma separated list of
+// field:key mappings, for example "country:country_iso,city:location.city",
+// where each field is one of ip, country, region or city,
+// and each key is the JSON key holding its value.
+func parsePublicIPAPIKeys(s string) (api settings.PublicIPAPI, err error) {
+	if s == "" {
+		return api, nil
+	}
+
+	fieldToKey := map[string]*string{
+		"ip":      &api.IPKey,
+		"country": &api.CountryKey,
+		"region":  &api.RegionKey,
+		"city":    &api.CityKey,
+	}
+	for _, mapping := range strings.Split(s, ",") {
+		mapping = strings.TrimSpace(mapping)
+		field, key, ok := strings.Cut(mapping, ":")
+		if !ok || key == "" {
+			return api, fmt.Errorf("%w: %q does not have the format field:key",
+				ErrPublicIPAPIKeyNotValid, mapping)
+		}
+		keyPtr, ok := fieldToKey[strings.ToLower(field)]
+		if !ok {
+			return api, fmt.Errorf("%w: field %q must be one of ip, country, region or city",
+				ErrPublicIPAPIKeyNotValid, field)
+		}
+		*keyPtr = key
+	}
+	return api, nil
+}
+
 func readPublicIPPeriod() (period *time.Duration, err error) {
 	s := getCleanedEnv("PUBLICIP_PERIOD")
 	if s == "" {
@@ -25,7 +87,7 @@ func readPublicIPPeriod() (period *time.Duration, err error) {
 	}
 
 	period = new(time.Duration)
-	*period, err = time.ParseDuration(s)
+	*period, err = parseDuration(s)
 	if err != nil {
 		return nil, fmt.Errorf("environment variable PUBLICIP_PERIOD: %w", err)
 	}
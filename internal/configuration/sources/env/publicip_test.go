@@ -0,0 +1,54 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePublicIPAPIKeys(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		api        settings.PublicIPAPI
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"keys set": {
+			s: "IP:address, country:country_iso,city:location.city",
+			api: settings.PublicIPAPI{
+				IPKey:      "address",
+				CountryKey: "country_iso",
+				CityKey:    "location.city",
+			},
+		},
+		"missing key": {
+			s:          "city:",
+			errWrapped: ErrPublicIPAPIKeyNotValid,
+			errMessage: `public IP API key mapping is not valid: "city:" does not have the format field:key`,
+		},
+		"unknown field": {
+			s:          "asn:asn",
+			errWrapped: ErrPublicIPAPIKeyNotValid,
+			errMessage: `public IP API key mapping is not valid: field "asn" must be one of ip, country, region or city`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			api, err := parsePublicIPAPIKeys(testCase.s)
+
+			assert.Equal(t, testCase.api, api)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -31,7 +31,7 @@ func (s *Source) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
-	settings.System, err = s.readSystem()
+	settings.System, err = s.ReadSystem()
 	if err != nil {
 		return settings, err
 	}
@@ -46,16 +46,53 @@ func (s *Source) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	settings.LANGateway, err = readLANGateway()
+	if err != nil {
+		return settings, err
+	}
+
 	settings.Log, err = readLog()
 	if err != nil {
 		return settings, err
 	}
 
+	settings.Metrics, err = readMetrics()
+	if err != nil {
+		return settings, err
+	}
+
+	settings.Notify, err = readNotify()
+	if err != nil {
+		return settings, err
+	}
+
+	settings.EventHooks, err = readEventHooks()
+	if err != nil {
+		return settings, err
+	}
+
+	settings.Diagnostics, err = readDiagnostics()
+	if err != nil {
+		return settings, err
+	}
+
+	settings.Shutdown, err = readShutdown()
+	if err != nil {
+		return settings, err
+	}
+
+	settings.SpeedTest, err = readSpeedTest()
+	if err != nil {
+		return settings, err
+	}
+
 	settings.PublicIP, err = s.readPublicIP()
 	if err != nil {
 		return settings, err
 	}
 
+	settings.Tracing = readTracing()
+
 	settings.Updater, err = readUpdater()
 	if err != nil {
 		return settings, err
@@ -71,6 +108,11 @@ func (s *Source) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	settings.WireguardServer, err = s.readWireguardServer()
+	if err != nil {
+		return settings, err
+	}
+
 	settings.DNS, err = s.readDNS()
 	if err != nil {
 		return settings, err
@@ -0,0 +1,138 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readSchedule() (schedule settings.Schedule, err error) {
+	windowStrings := envToCSV("VPN_SCHEDULE")
+	if len(windowStrings) > 0 {
+		schedule.Windows = make([]settings.ScheduleWindow, len(windowStrings))
+		for i, windowString := range windowStrings {
+			schedule.Windows[i], err = parseScheduleWindow(windowString)
+			if err != nil {
+				return schedule, fmt.Errorf("environment variable VPN_SCHEDULE: %w", err)
+			}
+		}
+	}
+
+	schedule.KillSwitch = getCleanedEnv("VPN_SCHEDULE_KILL_SWITCH")
+
+	return schedule, nil
+}
+
+var (
+	ErrScheduleWindowFormat = errors.New("schedule window format is not valid")
+	ErrWeekdayNotValid      = errors.New("weekday is not valid")
+	ErrTimeOfDayNotValid    = errors.New("time of day is not valid")
+)
+
+// parseScheduleWindow parses a window string in the format
+// "[days ]HH:MM-HH:MM", where days is a weekday such as "mon",
+// a range of weekdays such as "mon-fri", or several of these
+// joined with "+" such as "mon+wed+fri-sun".
+func parseScheduleWindow(s string) (window settings.ScheduleWindow, err error) {
+	fields := strings.Fields(s)
+	var timesString string
+	switch len(fields) {
+	case 1:
+		timesString = fields[0]
+	case 2: //nolint:gomnd
+		window.Weekdays, err = parseWeekdays(fields[0])
+		if err != nil {
+			return window, err
+		}
+		timesString = fields[1]
+	default:
+		return window, fmt.Errorf("%w: %s", ErrScheduleWindowFormat, s)
+	}
+
+	startString, endString, ok := strings.Cut(timesString, "-")
+	if !ok {
+		return window, fmt.Errorf("%w: %s", ErrScheduleWindowFormat, s)
+	}
+
+	window.Start, err = parseTimeOfDay(startString)
+	if err != nil {
+		return window, fmt.Errorf("start time: %w", err)
+	}
+
+	window.End, err = parseTimeOfDay(endString)
+	if err != nil {
+		return window, fmt.Errorf("end time: %w", err)
+	}
+
+	return window, nil
+}
+
+func parseWeekdays(s string) (weekdays []time.Weekday, err error) {
+	for _, part := range strings.Split(s, "+") {
+		firstString, lastString, isRange := strings.Cut(part, "-")
+		first, err := parseWeekday(firstString)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isRange {
+			weekdays = append(weekdays, first)
+			continue
+		}
+
+		last, err := parseWeekday(lastString)
+		if err != nil {
+			return nil, err
+		}
+
+		const daysPerWeek = 7
+		for day := first; ; day = (day + 1) % daysPerWeek {
+			weekdays = append(weekdays, day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return weekdays, nil
+}
+
+func parseWeekday(s string) (weekday time.Weekday, err error) {
+	s = strings.ToLower(s)
+	const daysPerWeek = 7
+	for day := time.Sunday; day < daysPerWeek; day++ {
+		const abbreviationLength = 3
+		if s == strings.ToLower(day.String()[:abbreviationLength]) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrWeekdayNotValid, s)
+}
+
+func parseTimeOfDay(s string) (sinceMidnight time.Duration, err error) {
+	hoursString, minutesString, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrTimeOfDayNotValid, s)
+	}
+
+	hours, err := strconv.Atoi(hoursString)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrTimeOfDayNotValid, s)
+	}
+
+	minutes, err := strconv.Atoi(minutesString)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrTimeOfDayNotValid, s)
+	}
+
+	const maxHours, maxMinutes = 24, 59
+	if hours < 0 || hours > maxHours || minutes < 0 || minutes > maxMinutes ||
+		(hours == maxHours && minutes != 0) {
+		return 0, fmt.Errorf("%w: %s", ErrTimeOfDayNotValid, s)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
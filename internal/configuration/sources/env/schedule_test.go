@@ -0,0 +1,77 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseScheduleWindow(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		window     settings.ScheduleWindow
+		errWrapped error
+		errMessage string
+	}{
+		"every day": {
+			s: "08:00-18:30",
+			window: settings.ScheduleWindow{
+				Start: 8 * time.Hour,
+				End:   18*time.Hour + 30*time.Minute,
+			},
+		},
+		"weekdays range wrapping": {
+			s: "fri-mon 22:00-24:00",
+			window: settings.ScheduleWindow{
+				Weekdays: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday},
+				Start:    22 * time.Hour,
+				End:      24 * time.Hour,
+			},
+		},
+		"weekdays list": {
+			s: "Mon+wed 22:00-06:00",
+			window: settings.ScheduleWindow{
+				Weekdays: []time.Weekday{time.Monday, time.Wednesday},
+				Start:    22 * time.Hour,
+				End:      6 * time.Hour,
+			},
+		},
+		"too many fields": {
+			s:          "mon 08:00-10:00 x",
+			errWrapped: ErrScheduleWindowFormat,
+			errMessage: "schedule window format is not valid: mon 08:00-10:00 x",
+		},
+		"bad weekday": {
+			s:          "moon 08:00-10:00",
+			errWrapped: ErrWeekdayNotValid,
+			errMessage: "weekday is not valid: moon",
+		},
+		"bad end time": {
+			s: "08:00-24:30",
+			window: settings.ScheduleWindow{
+				Start: 8 * time.Hour,
+			},
+			errWrapped: ErrTimeOfDayNotValid,
+			errMessage: "end time: time of day is not valid: 24:30",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			window, err := parseScheduleWindow(testCase.s)
+
+			assert.Equal(t, testCase.window, window)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package env
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func (s *Source) readSecondaryTunnel() (secondary settings.SecondaryTunnel, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"SECONDARY_TUNNEL_WIREGUARD_PRIVATE_KEY",
+			"SECONDARY_TUNNEL_WIREGUARD_PRESHARED_KEY"}, err)
+	}()
+
+	secondary.Enabled, err = envToBoolPtr("SECONDARY_TUNNEL")
+	if err != nil {
+		return secondary, fmt.Errorf("environment variable SECONDARY_TUNNEL: %w", err)
+	}
+
+	secondary.Provider = envToStringPtr("SECONDARY_TUNNEL_PROVIDER")
+	if secondary.Provider != nil {
+		*secondary.Provider = strings.ToLower(*secondary.Provider)
+	}
+
+	selection := &secondary.ServerSelection
+	selection.Countries = envToCSV("SECONDARY_TUNNEL_SERVER_COUNTRIES")
+	selection.Cities = envToCSV("SECONDARY_TUNNEL_SERVER_CITIES")
+	selection.Hostnames = envToCSV("SECONDARY_TUNNEL_SERVER_HOSTNAMES")
+	selection.Names = envToCSV("SECONDARY_TUNNEL_SERVER_NAMES")
+	selection.Wireguard.PublicKey = getCleanedEnv("SECONDARY_TUNNEL_WIREGUARD_PUBLIC_KEY")
+
+	if value := getCleanedEnv("SECONDARY_TUNNEL_ENDPOINT_IP"); value != "" {
+		selection.Wireguard.EndpointIP, err = netip.ParseAddr(value)
+		if err != nil {
+			return secondary, fmt.Errorf("environment variable SECONDARY_TUNNEL_ENDPOINT_IP: %w", err)
+		}
+	}
+
+	selection.Wireguard.EndpointPort, err = envToUint16Ptr("SECONDARY_TUNNEL_ENDPOINT_PORT")
+	if err != nil {
+		return secondary, fmt.Errorf("environment variable SECONDARY_TUNNEL_ENDPOINT_PORT: %w", err)
+	}
+
+	secondary.PrivateKey = envToStringPtr("SECONDARY_TUNNEL_WIREGUARD_PRIVATE_KEY")
+	secondary.PreSharedKey = envToStringPtr("SECONDARY_TUNNEL_WIREGUARD_PRESHARED_KEY")
+
+	secondary.Addresses, err = stringsToNetipPrefixes(envToCSV("SECONDARY_TUNNEL_WIREGUARD_ADDRESSES"))
+	if err != nil {
+		return secondary, fmt.Errorf("environment variable SECONDARY_TUNNEL_WIREGUARD_ADDRESSES: %w", err)
+	}
+
+	secondary.Interface = getCleanedEnv("SECONDARY_TUNNEL_INTERFACE")
+
+	secondary.DestinationPorts, err = stringsToPorts(envToCSV("SECONDARY_TUNNEL_PORTS"))
+	if err != nil {
+		return secondary, fmt.Errorf("environment variable SECONDARY_TUNNEL_PORTS: %w", err)
+	}
+
+	secondary.SourceNetworks, err = stringsToNetipPrefixes(envToCSV("SECONDARY_TUNNEL_SOURCE_NETWORKS"))
+	if err != nil {
+		return secondary, fmt.Errorf("environment variable SECONDARY_TUNNEL_SOURCE_NETWORKS: %w", err)
+	}
+
+	return secondary, nil
+}
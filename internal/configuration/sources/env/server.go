@@ -14,6 +14,23 @@ func (s *Source) readControlServer() (controlServer settings.ControlServer, err
 	}
 
 	controlServer.Address = s.readControlServerAddress()
+	controlServer.AuditLogPath = envToStringPtr("HTTP_CONTROL_SERVER_AUDIT_LOG_PATH")
+
+	controlServer.ReadOnlyEnabled, err = envToBoolPtr("HTTP_CONTROL_SERVER_READONLY")
+	if err != nil {
+		return controlServer, fmt.Errorf("environment variable HTTP_CONTROL_SERVER_READONLY: %w", err)
+	}
+	controlServer.ReadOnlyAPIKey = envToStringPtr("HTTP_CONTROL_SERVER_READONLY_API_KEY")
+
+	controlServer.AdminEnabled, err = envToBoolPtr("HTTP_CONTROL_SERVER_ADMIN")
+	if err != nil {
+		return controlServer, fmt.Errorf("environment variable HTTP_CONTROL_SERVER_ADMIN: %w", err)
+	}
+	_, adminAPIKey := s.getEnvWithRetro("HTTP_CONTROL_SERVER_ADMIN_API_KEY",
+		"HTTP_CONTROL_SERVER_API_KEY")
+	if adminAPIKey != "" {
+		controlServer.AdminAPIKey = &adminAPIKey
+	}
 
 	return controlServer, nil
 }
@@ -0,0 +1,21 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readServerBlacklist() (blacklist settings.ServerBlacklist, err error) {
+	blacklist.Failures, err = envToUint8Ptr("VPN_SERVER_BLACKLIST_FAILURES")
+	if err != nil {
+		return blacklist, fmt.Errorf("environment variable VPN_SERVER_BLACKLIST_FAILURES: %w", err)
+	}
+
+	blacklist.Cooldown, err = envToDurationPtr("VPN_SERVER_BLACKLIST_COOLDOWN")
+	if err != nil {
+		return blacklist, fmt.Errorf("environment variable VPN_SERVER_BLACKLIST_COOLDOWN: %w", err)
+	}
+
+	return blacklist, nil
+}
@@ -24,6 +24,13 @@ func (s *Source) readServerSelection(vpnProvider, vpnType string) (
 		return ss, err
 	}
 
+	if value := getCleanedEnv("SERVER_IP"); value != "" {
+		ss.ServerIP, err = netip.ParseAddr(value)
+		if err != nil {
+			return ss, fmt.Errorf("environment variable SERVER_IP: %w", err)
+		}
+	}
+
 	countriesKey, _ := s.getEnvWithRetro("SERVER_COUNTRIES", "COUNTRY")
 	ss.Countries = envToCSV(countriesKey)
 	if vpnProvider == providers.Cyberghost && len(ss.Countries) == 0 {
@@ -48,6 +55,13 @@ func (s *Source) readServerSelection(vpnProvider, vpnType string) (
 	serverNamesKey, _ := s.getEnvWithRetro("SERVER_NAMES", "SERVER_NAME")
 	ss.Names = envToCSV(serverNamesKey)
 
+	ss.ExcludedCountries = envToCSV("SERVER_COUNTRIES_EXCLUDE")
+	ss.ExcludedRegions = envToCSV("SERVER_REGIONS_EXCLUDE")
+	ss.ExcludedCities = envToCSV("SERVER_CITIES_EXCLUDE")
+	ss.ExcludedISPs = envToCSV("ISP_EXCLUDE")
+	ss.ExcludedHostnames = envToCSV("SERVER_HOSTNAMES_EXCLUDE")
+	ss.ExcludedNames = envToCSV("SERVER_NAMES_EXCLUDE")
+
 	if csv := getCleanedEnv("SERVER_NUMBER"); csv != "" {
 		numbersStrings := strings.Split(csv, ",")
 		numbers := make([]uint16, len(numbersStrings))
@@ -96,6 +110,15 @@ func (s *Source) readServerSelection(vpnProvider, vpnType string) (
 		return ss, fmt.Errorf("environment variable STREAM_ONLY: %w", err)
 	}
 
+	ss.Features = envToCSV("SERVER_FEATURES")
+	ss.Filter = getCleanedEnv("SERVER_FILTER")
+
+	// PIA only
+	ss.PortForwardOnly, err = envToBoolPtr("PORT_FORWARD_ONLY")
+	if err != nil {
+		return ss, fmt.Errorf("environment variable PORT_FORWARD_ONLY: %w", err)
+	}
+
 	ss.OpenVPN, err = s.readOpenVPNSelection()
 	if err != nil {
 		return ss, err
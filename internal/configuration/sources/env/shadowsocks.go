@@ -1,6 +1,7 @@
 package env
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,10 @@ import (
 )
 
 func (s *Source) readShadowsocks() (shadowsocks settings.Shadowsocks, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"SHADOWSOCKS_INSTANCES"}, err)
+	}()
+
 	shadowsocks.Enabled, err = envToBoolPtr("SHADOWSOCKS")
 	if err != nil {
 		return shadowsocks, fmt.Errorf("environment variable SHADOWSOCKS: %w", err)
@@ -21,9 +26,54 @@ func (s *Source) readShadowsocks() (shadowsocks settings.Shadowsocks, err error)
 	shadowsocks.CipherName = s.readShadowsocksCipher()
 	shadowsocks.Password = envToStringPtr("SHADOWSOCKS_PASSWORD")
 
+	shadowsocks.UDP, err = envToBoolPtr("SHADOWSOCKS_UDP")
+	if err != nil {
+		return shadowsocks, fmt.Errorf("environment variable SHADOWSOCKS_UDP: %w", err)
+	}
+	shadowsocks.Plugin = getCleanedEnv("SHADOWSOCKS_PLUGIN")
+	shadowsocks.PluginOptions = getCleanedEnv("SHADOWSOCKS_PLUGIN_OPTIONS")
+
+	shadowsocks.Instances, err = parseShadowsocksInstances(getCleanedEnv("SHADOWSOCKS_INSTANCES"))
+	if err != nil {
+		return shadowsocks, fmt.Errorf("environment variable SHADOWSOCKS_INSTANCES: %w", err)
+	}
+
 	return shadowsocks, nil
 }
 
+var ErrShadowsocksInstanceNotValid = errors.New("instance is not valid")
+
+// parseShadowsocksInstances parses a comma separated list of
+// instances, each in the format name:cipher:password@address,
+// where the cipher can be left empty to use the main cipher.
+// The password is case sensitive and can contain colons and @
+// characters, but not commas.
+func parseShadowsocksInstances(s string) (instances []settings.ShadowsocksInstance, err error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(s, ",")
+	instances = make([]settings.ShadowsocksInstance, len(fields))
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		name, rest, _ := strings.Cut(field, ":")
+		cipher, rest, ok := strings.Cut(rest, ":")
+		atIndex := strings.LastIndex(rest, "@")
+		if !ok || atIndex == -1 {
+			return nil, fmt.Errorf("%w: instance %d of %d does not have the format "+
+				"name:cipher:password@address", ErrShadowsocksInstanceNotValid, i+1, len(fields))
+		}
+		instances[i] = settings.ShadowsocksInstance{
+			Name:       name,
+			CipherName: strings.ToLower(cipher),
+			Password:   rest[:atIndex],
+			Address:    rest[atIndex+1:],
+		}
+	}
+	return instances, nil
+}
+
 func (s *Source) readShadowsocksAddress() (address string) {
 	key, value := s.getEnvWithRetro("SHADOWSOCKS_LISTENING_ADDRESS", "SHADOWSOCKS_PORT")
 	if value == "" {
@@ -0,0 +1,55 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseShadowsocksInstances(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		instances  []settings.ShadowsocksInstance
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"instances with and without cipher": {
+			s: "phone::PaSs@:8389, laptop:AES-256-GCM:p@ss:word@0.0.0.0:8390",
+			instances: []settings.ShadowsocksInstance{
+				{Name: "phone", Password: "PaSs", Address: ":8389"},
+				{Name: "laptop", CipherName: "aes-256-gcm", Password: "p@ss:word", Address: "0.0.0.0:8390"},
+			},
+		},
+		"missing cipher field": {
+			s:          "phone:password@:8389",
+			errWrapped: ErrShadowsocksInstanceNotValid,
+			errMessage: "instance is not valid: instance 1 of 1 does not have " +
+				"the format name:cipher:password@address",
+		},
+		"missing address": {
+			s:          "phone::password",
+			errWrapped: ErrShadowsocksInstanceNotValid,
+			errMessage: "instance is not valid: instance 1 of 1 does not have " +
+				"the format name:cipher:password@address",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			instances, err := parseShadowsocksInstances(testCase.s)
+
+			assert.Equal(t, testCase.instances, instances)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
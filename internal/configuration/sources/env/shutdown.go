@@ -0,0 +1,30 @@
+package env
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readShutdown() (shutdown settings.Shutdown, err error) {
+	envKeyToTimeout := map[string]*time.Duration{
+		"SHUTDOWN_TIMEOUT":                 &shutdown.Timeout,
+		"SHUTDOWN_PORT_FORWARDING_TIMEOUT": &shutdown.PortForwardingTimeout,
+		"SHUTDOWN_PROXIES_TIMEOUT":         &shutdown.ProxiesTimeout,
+		"SHUTDOWN_DNS_TIMEOUT":             &shutdown.DNSTimeout,
+		"SHUTDOWN_VPN_TIMEOUT":             &shutdown.VPNTimeout,
+		"SHUTDOWN_FIREWALL_TIMEOUT":        &shutdown.FirewallTimeout,
+	}
+
+	for envKey, timeout := range envKeyToTimeout {
+		value, err := envToDurationPtr(envKey)
+		if err != nil {
+			return shutdown, fmt.Errorf("environment variable %s: %w", envKey, err)
+		} else if value != nil {
+			*timeout = *value
+		}
+	}
+
+	return shutdown, nil
+}
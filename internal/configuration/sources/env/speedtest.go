@@ -0,0 +1,61 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readSpeedTest() (speedTest settings.SpeedTest, err error) {
+	speedTest.DownloadURL = getCleanedEnv("SPEEDTEST_DOWNLOAD_URL")
+	speedTest.UploadURL = envToStringPtr("SPEEDTEST_UPLOAD_URL")
+
+	speedTest.UploadMegabytes, err = readSpeedTestUploadMegabytes()
+	if err != nil {
+		return speedTest, fmt.Errorf("environment variable SPEEDTEST_UPLOAD_MB: %w", err)
+	}
+
+	timeout, err := envToDurationPtr("SPEEDTEST_TIMEOUT")
+	if err != nil {
+		return speedTest, fmt.Errorf("environment variable SPEEDTEST_TIMEOUT: %w", err)
+	} else if timeout != nil {
+		speedTest.Timeout = *timeout
+	}
+
+	return speedTest, nil
+}
+
+var ErrUploadSizeNotValid = errors.New("upload size is not valid")
+
+// readSpeedTestUploadMegabytes reads the upload size either as a
+// number of megabytes such as 10, or as a size such as 10MB or 1GB.
+func readSpeedTestUploadMegabytes() (megabytes uint16, err error) {
+	s := getCleanedEnv("SPEEDTEST_UPLOAD_MB")
+	if s == "" {
+		return 0, nil
+	}
+
+	const base, bitSize = 10, 64
+	value, err := strconv.ParseUint(s, base, bitSize)
+	if err != nil { // not a plain number of megabytes
+		bytes, err := parseSize(s)
+		if err != nil {
+			return 0, err
+		}
+		const megabyte = 1000 * 1000
+		if bytes%megabyte != 0 {
+			return 0, fmt.Errorf("%w: %s must be a multiple of 1MB",
+				ErrUploadSizeNotValid, s)
+		}
+		value = bytes / megabyte
+	}
+
+	if value > math.MaxUint16 {
+		return 0, fmt.Errorf("%w: %s must be at most %dMB",
+			ErrUploadSizeNotValid, s, math.MaxUint16)
+	}
+	return uint16(value), nil
+}
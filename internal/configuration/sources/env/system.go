@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
@@ -14,7 +15,8 @@ var (
 	ErrSystemTimezoneNotValid = errors.New("timezone is not valid")
 )
 
-func (s *Source) readSystem() (system settings.System, err error) {
+// ReadSystem reads the system settings from environment variables.
+func (s *Source) ReadSystem() (system settings.System, err error) {
 	system.PUID, err = s.readID("PUID", "UID")
 	if err != nil {
 		return system, err
@@ -27,6 +29,32 @@ func (s *Source) readSystem() (system settings.System, err error) {
 
 	system.Timezone = getCleanedEnv("TZ")
 
+	system.TimezoneFromPublicIP, err = envToBoolPtr("TZ_FROM_PUBLIC_IP")
+	if err != nil {
+		return system, fmt.Errorf("environment variable TZ_FROM_PUBLIC_IP: %w", err)
+	}
+
+	system.Umask, err = envToFileModePtr("UMASK")
+	if err != nil {
+		return system, fmt.Errorf("environment variable UMASK: %w", err)
+	}
+
+	system.ServersFilePermissions, err = envToFileModePtr("SERVERS_FILE_PERMISSIONS")
+	if err != nil {
+		return system, fmt.Errorf("environment variable SERVERS_FILE_PERMISSIONS: %w", err)
+	}
+
+	system.ServersMergeStrategy = strings.ToLower(getCleanedEnv("SERVERS_MERGE_STRATEGY"))
+
+	system.HostMode, err = envToBoolPtr("HOST_MODE")
+	if err != nil {
+		return system, fmt.Errorf("environment variable HOST_MODE: %w", err)
+	}
+
+	system.ResolvConfPath = envToStringPtr("RESOLV_CONF_PATH")
+	system.DataDirectory = envToStringPtr("DATA_DIRECTORY")
+	system.RuntimeDirectory = envToStringPtr("RUNTIME_DIRECTORY")
+
 	return system, nil
 }
 
@@ -0,0 +1,19 @@
+package env
+
+import (
+	"os"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func (s *Source) readTailscale() (tailscale settings.Tailscale, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"TAILSCALE_AUTH_KEY"}, err)
+	}()
+	tailscale.AuthKey = envToStringPtr("TAILSCALE_AUTH_KEY")
+	tailscale.ExitNode = getCleanedEnv("TAILSCALE_EXIT_NODE")
+	tailscale.Hostname = getCleanedEnv("TAILSCALE_HOSTNAME")
+	tailscale.ControlURL = getCleanedEnv("TAILSCALE_CONTROL_URL")
+	tailscale.Interface = os.Getenv("VPN_INTERFACE")
+	return tailscale, nil
+}
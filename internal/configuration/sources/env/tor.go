@@ -0,0 +1,32 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readTor() (tor settings.Tor, err error) {
+	tor.Chain, err = envToBoolPtr("TOR_CHAIN")
+	if err != nil {
+		return tor, fmt.Errorf("environment variable TOR_CHAIN: %w", err)
+	}
+
+	tor.ExitCountries = envToCSV("TOR_EXIT_COUNTRIES")
+
+	socksPort, err := envToUint16Ptr("TOR_SOCKS_PORT")
+	if err != nil {
+		return tor, fmt.Errorf("environment variable TOR_SOCKS_PORT: %w", err)
+	} else if socksPort != nil {
+		tor.SocksPort = *socksPort
+	}
+
+	transPort, err := envToUint16Ptr("TOR_TRANS_PORT")
+	if err != nil {
+		return tor, fmt.Errorf("environment variable TOR_TRANS_PORT: %w", err)
+	} else if transPort != nil {
+		tor.TransPort = *transPort
+	}
+
+	return tor, nil
+}
@@ -0,0 +1,11 @@
+package env
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readTracing() (tracing settings.Tracing) {
+	tracing.Endpoint = envToStringPtr("TRACING_OTLP_ENDPOINT")
+	tracing.ServiceName = getCleanedEnv("TRACING_SERVICE_NAME")
+	return tracing
+}
@@ -0,0 +1,29 @@
+package env
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readUDP2Raw() (udp2raw settings.UDP2Raw, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"WIREGUARD_UDP2RAW_PASSWORD"}, err)
+	}()
+
+	udp2raw.Enabled, err = envToBoolPtr("WIREGUARD_UDP2RAW")
+	if err != nil {
+		return udp2raw, fmt.Errorf("environment variable WIREGUARD_UDP2RAW: %w", err)
+	}
+
+	udp2raw.ServerPort, err = envToUint16Ptr("WIREGUARD_UDP2RAW_PORT")
+	if err != nil {
+		return udp2raw, fmt.Errorf("environment variable WIREGUARD_UDP2RAW_PORT: %w", err)
+	}
+
+	udp2raw.Password = envToStringPtr("WIREGUARD_UDP2RAW_PASSWORD")
+	udp2raw.RawMode = os.Getenv("WIREGUARD_UDP2RAW_RAW_MODE")
+
+	return udp2raw, nil
+}
@@ -0,0 +1,91 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrDurationNotValid = errors.New("duration is not valid")
+
+// parseDuration parses a duration such as 1h30m, 90s or 2d.
+// On top of the units accepted by time.ParseDuration, it
+// accepts the unit 'd' for days. A bare 0 is also accepted.
+func parseDuration(s string) (duration time.Duration, err error) {
+	days, rest, hasDays := strings.Cut(s, "d")
+	if !hasDays {
+		rest = s
+	} else {
+		const base, bitSize = 10, 64
+		daysCount, err := strconv.ParseUint(days, base, bitSize)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q: days %q must be a positive integer",
+				ErrDurationNotValid, s, days)
+		}
+		const day = 24 * time.Hour
+		if daysCount > uint64(math.MaxInt64/day) {
+			return 0, fmt.Errorf("%w: %q is too large", ErrDurationNotValid, s)
+		}
+		duration = time.Duration(daysCount) * day
+		if rest == "" {
+			return duration, nil
+		}
+	}
+
+	restDuration, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q must be in a format such as 1h30m, 90s or 2d",
+			ErrDurationNotValid, s)
+	}
+	return duration + restDuration, nil
+}
+
+var ErrSizeNotValid = errors.New("size is not valid")
+
+// parseSize parses a size in bytes such as 10MB, 512KiB or 1024.
+// Decimal units (KB, MB, GB) are powers of 1000, binary units
+// (KiB, MiB, GiB) are powers of 1024, and a number without unit
+// is a number of bytes. Units are case insensitive.
+func parseSize(s string) (bytes uint64, err error) {
+	numberEnd := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	if numberEnd == -1 {
+		numberEnd = len(s)
+	}
+	number, unit := s[:numberEnd], strings.TrimSpace(s[numberEnd:])
+
+	const base, bitSize = 10, 64
+	value, err := strconv.ParseUint(number, base, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q must be in a format such as 10MB, 512KiB or 1024",
+			ErrSizeNotValid, s)
+	}
+
+	unitToMultiplier := map[string]uint64{
+		"":    1,
+		"b":   1,
+		"k":   1e3,
+		"kb":  1e3,
+		"m":   1e6,
+		"mb":  1e6,
+		"g":   1e9,
+		"gb":  1e9,
+		"kib": 1 << 10, //nolint:gomnd
+		"mib": 1 << 20, //nolint:gomnd
+		"gib": 1 << 30, //nolint:gomnd
+	}
+	multiplier, ok := unitToMultiplier[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q: unit %q is not one of B, KB, MB, GB, KiB, MiB or GiB",
+			ErrSizeNotValid, s, unit)
+	} else if value > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("%w: %q is too large", ErrSizeNotValid, s)
+	}
+
+	return value * multiplier, nil
+}
@@ -0,0 +1,122 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseDuration(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		duration   time.Duration
+		errWrapped error
+		errMessage string
+	}{
+		"zero": {
+			s: "0",
+		},
+		"hours and minutes": {
+			s:        "1h30m",
+			duration: time.Hour + 30*time.Minute,
+		},
+		"seconds": {
+			s:        "90s",
+			duration: 90 * time.Second,
+		},
+		"days": {
+			s:        "2d",
+			duration: 48 * time.Hour,
+		},
+		"days and hours": {
+			s:        "1d12h",
+			duration: 36 * time.Hour,
+		},
+		"missing unit": {
+			s:          "90",
+			errWrapped: ErrDurationNotValid,
+			errMessage: `duration is not valid: "90" must be in a format such as 1h30m, 90s or 2d`,
+		},
+		"bad days": {
+			s:          "xd",
+			errWrapped: ErrDurationNotValid,
+			errMessage: `duration is not valid: "xd": days "x" must be a positive integer`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			duration, err := parseDuration(testCase.s)
+
+			assert.Equal(t, testCase.duration, duration)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_parseSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		bytes      uint64
+		errWrapped error
+		errMessage string
+	}{
+		"bytes without unit": {
+			s:     "1024",
+			bytes: 1024,
+		},
+		"decimal megabytes": {
+			s:     "10MB",
+			bytes: 10000000,
+		},
+		"binary kibibytes lowercase": {
+			s:     "512kib",
+			bytes: 524288,
+		},
+		"space before unit": {
+			s:     "1 GiB",
+			bytes: 1073741824,
+		},
+		"missing number": {
+			s:          "MB",
+			errWrapped: ErrSizeNotValid,
+			errMessage: `size is not valid: "MB" must be in a format such as 10MB, 512KiB or 1024`,
+		},
+		"unknown unit": {
+			s:          "10TB",
+			errWrapped: ErrSizeNotValid,
+			errMessage: `size is not valid: "10TB": unit "TB" is not one of B, KB, MB, GB, KiB, MiB or GiB`,
+		},
+		"overflow": {
+			s:          "18446744073709551615GB",
+			errWrapped: ErrSizeNotValid,
+			errMessage: `size is not valid: "18446744073709551615GB" is too large`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			bytes, err := parseSize(testCase.s)
+
+			assert.Equal(t, testCase.bytes, bytes)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
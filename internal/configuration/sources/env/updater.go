@@ -1,13 +1,20 @@
 package env
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
 func readUpdater() (updater settings.Updater, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"UPDATER_PROTONVPN_PASSWORD"}, err)
+	}()
+
 	updater.Period, err = readUpdaterPeriod()
 	if err != nil {
 		return updater, err
@@ -23,7 +30,14 @@ func readUpdater() (updater settings.Updater, err error) {
 		return updater, fmt.Errorf("environment variable UPDATER_MIN_RATIO: %w", err)
 	}
 
+	updater.MinServers, err = readUpdaterMinServers()
+	if err != nil {
+		return updater, fmt.Errorf("environment variable UPDATER_MIN_SERVERS: %w", err)
+	}
+
 	updater.Providers = envToCSV("UPDATER_VPN_SERVICE_PROVIDERS")
+	updater.ProtonEmail = envToStringPtr("UPDATER_PROTONVPN_EMAIL")
+	updater.ProtonPassword = envToStringPtr("UPDATER_PROTONVPN_PASSWORD")
 
 	return updater, nil
 }
@@ -34,7 +48,7 @@ func readUpdaterPeriod() (period *time.Duration, err error) {
 		return nil, nil //nolint:nilnil
 	}
 	period = new(time.Duration)
-	*period, err = time.ParseDuration(s)
+	*period, err = parseDuration(s)
 	if err != nil {
 		return nil, fmt.Errorf("environment variable UPDATER_PERIOD: %w", err)
 	}
@@ -49,3 +63,30 @@ func readUpdaterDNSAddress() (address string, err error) {
 	// as it's too much trouble to start another parallel unbound instance for now.
 	return "", nil
 }
+
+var ErrMinServersFormat = errors.New("minimum servers format is not valid")
+
+// readUpdaterMinServers reads minimum numbers of servers per provider
+// from a CSV string such as "nordvpn:100,mullvad:0".
+func readUpdaterMinServers() (minServers map[string]uint, err error) {
+	values := envToCSV("UPDATER_MIN_SERVERS")
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	minServers = make(map[string]uint, len(values))
+	for _, value := range values {
+		provider, countString, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be in the form provider:count",
+				ErrMinServersFormat, value)
+		}
+		const base, bitSize = 10, 32
+		count, err := strconv.ParseUint(countString, base, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", provider, err)
+		}
+		minServers[provider] = uint(count)
+	}
+	return minServers, nil
+}
@@ -13,6 +13,11 @@ func readVersion() (version settings.Version, err error) {
 		return version, err
 	}
 
+	version.UpdateCheckPeriod, err = envToDurationPtr("VERSION_UPDATE_CHECK_PERIOD")
+	if err != nil {
+		return version, fmt.Errorf("environment variable VERSION_UPDATE_CHECK_PERIOD: %w", err)
+	}
+
 	return version, nil
 }
 
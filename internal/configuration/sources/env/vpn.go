@@ -25,5 +25,57 @@ func (s *Source) readVPN() (vpn settings.VPN, err error) {
 		return vpn, fmt.Errorf("wireguard: %w", err)
 	}
 
+	vpn.Tailscale, err = s.readTailscale()
+	if err != nil {
+		return vpn, fmt.Errorf("tailscale: %w", err)
+	}
+
+	vpn.Tor, err = readTor()
+	if err != nil {
+		return vpn, fmt.Errorf("tor: %w", err)
+	}
+
+	vpn.Secondary, err = s.readSecondaryTunnel()
+	if err != nil {
+		return vpn, fmt.Errorf("secondary tunnel: %w", err)
+	}
+
+	vpn.Backoff, err = readBackoff()
+	if err != nil {
+		return vpn, fmt.Errorf("backoff: %w", err)
+	}
+
+	vpn.Schedule, err = readSchedule()
+	if err != nil {
+		return vpn, fmt.Errorf("schedule: %w", err)
+	}
+
+	vpn.AuthFailure, err = readAuthFailure()
+	if err != nil {
+		return vpn, fmt.Errorf("authentication failure: %w", err)
+	}
+
+	vpn.ServerBlacklist, err = readServerBlacklist()
+	if err != nil {
+		return vpn, fmt.Errorf("server blacklist: %w", err)
+	}
+
+	vpn.Hooks, err = readHooks()
+	if err != nil {
+		return vpn, fmt.Errorf("hooks: %w", err)
+	}
+
+	vpn.EgressProxy, err = readEgressProxy()
+	if err != nil {
+		return vpn, fmt.Errorf("egress proxy: %w", err)
+	}
+
+	vpn.OnDemand, err = envToBoolPtr("VPN_ON_DEMAND")
+	if err != nil {
+		return vpn, fmt.Errorf("environment variable VPN_ON_DEMAND: %w", err)
+	}
+
+	vpn.NAT64Prefix = envToStringPtr("NAT64_PREFIX")
+
 	return vpn, nil
 }
@@ -11,9 +11,12 @@ import (
 
 func (s *Source) readWireguard() (wireguard settings.Wireguard, err error) {
 	defer func() {
-		err = unsetEnvKeys([]string{"WIREGUARD_PRIVATE_KEY", "WIREGUARD_PRESHARED_KEY"}, err)
+		err = unsetEnvKeys([]string{"WIREGUARD_PRIVATE_KEY", "WIREGUARD_ACCESS_TOKEN",
+			"WIREGUARD_ACCOUNT_NUMBER", "WIREGUARD_PRESHARED_KEY"}, err)
 	}()
 	wireguard.PrivateKey = envToStringPtr("WIREGUARD_PRIVATE_KEY")
+	wireguard.AccessToken = envToStringPtr("WIREGUARD_ACCESS_TOKEN")
+	wireguard.AccountNumber = envToStringPtr("WIREGUARD_ACCOUNT_NUMBER")
 	wireguard.PreSharedKey = envToStringPtr("WIREGUARD_PRESHARED_KEY")
 	_, wireguard.Interface = s.getEnvWithRetro("VPN_INTERFACE", "WIREGUARD_INTERFACE")
 	wireguard.Implementation = os.Getenv("WIREGUARD_IMPLEMENTATION")
@@ -21,12 +24,24 @@ func (s *Source) readWireguard() (wireguard settings.Wireguard, err error) {
 	if err != nil {
 		return wireguard, err // already wrapped
 	}
+	wireguard.AllowedIPs, err = stringsToNetipPrefixes(envToCSV("WIREGUARD_ALLOWED_IPS"))
+	if err != nil {
+		return wireguard, fmt.Errorf("environment variable WIREGUARD_ALLOWED_IPS: %w", err)
+	}
 	mtuPtr, err := envToUint16Ptr("WIREGUARD_MTU")
 	if err != nil {
 		return wireguard, fmt.Errorf("environment variable WIREGUARD_MTU: %w", err)
 	} else if mtuPtr != nil {
 		wireguard.MTU = *mtuPtr
 	}
+	wireguard.OpenVPNFallbackAttempts, err = envToUint8Ptr("WIREGUARD_OPENVPN_FALLBACK_ATTEMPTS")
+	if err != nil {
+		return wireguard, fmt.Errorf("environment variable WIREGUARD_OPENVPN_FALLBACK_ATTEMPTS: %w", err)
+	}
+	wireguard.UDP2Raw, err = readUDP2Raw()
+	if err != nil {
+		return wireguard, err // already wrapped
+	}
 	return wireguard, nil
 }
 
@@ -21,6 +21,7 @@ func (s *Source) readWireguardSelection() (
 	}
 
 	selection.PublicKey = getCleanedEnv("WIREGUARD_PUBLIC_KEY")
+	selection.Protocol = envToStringPtr("WIREGUARD_PROTOCOL")
 
 	return selection, nil
 }
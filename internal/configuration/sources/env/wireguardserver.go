@@ -0,0 +1,73 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func (s *Source) readWireguardServer() (server settings.WireguardServer, err error) {
+	defer func() {
+		err = unsetEnvKeys([]string{"WIREGUARD_SERVER_PRIVATE_KEY",
+			"WIREGUARD_SERVER_PEERS"}, err)
+	}()
+
+	server.Enabled, err = envToBoolPtr("WIREGUARD_SERVER")
+	if err != nil {
+		return server, fmt.Errorf("environment variable WIREGUARD_SERVER: %w", err)
+	}
+
+	server.PrivateKey = envToStringPtr("WIREGUARD_SERVER_PRIVATE_KEY")
+	server.Interface = getCleanedEnv("WIREGUARD_SERVER_INTERFACE")
+
+	listenPort, err := envToUint16Ptr("WIREGUARD_SERVER_LISTEN_PORT")
+	if err != nil {
+		return server, fmt.Errorf("environment variable WIREGUARD_SERVER_LISTEN_PORT: %w", err)
+	} else if listenPort != nil {
+		server.ListenPort = *listenPort
+	}
+
+	if value := getCleanedEnv("WIREGUARD_SERVER_ADDRESS"); value != "" {
+		server.Address, err = netip.ParsePrefix(value)
+		if err != nil {
+			return server, fmt.Errorf("environment variable WIREGUARD_SERVER_ADDRESS: %w", err)
+		}
+	}
+
+	server.Peers, err = parseWireguardServerPeers(getCleanedEnv("WIREGUARD_SERVER_PEERS"))
+	if err != nil {
+		return server, fmt.Errorf("environment variable WIREGUARD_SERVER_PEERS: %w", err)
+	}
+
+	return server, nil
+}
+
+var ErrWireguardServerPeerNotValid = errors.New("peer is not valid")
+
+// parseWireguardServerPeers parses a comma separated list of peers,
+// each in the format publickey[:presharedkey]@address, where the
+// keys are case sensitive base64 strings.
+func parseWireguardServerPeers(s string) (peers []settings.WireguardServerPeer, err error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(s, ",")
+	peers = make([]settings.WireguardServerPeer, len(fields))
+	for i, field := range fields {
+		keys, address, ok := strings.Cut(strings.TrimSpace(field), "@")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q does not have the format "+
+				"publickey[:presharedkey]@address", ErrWireguardServerPeerNotValid, field)
+		}
+		peers[i].PublicKey, peers[i].PreSharedKey, _ = strings.Cut(keys, ":")
+		peers[i].Address, err = netip.ParsePrefix(address)
+		if err != nil {
+			return nil, fmt.Errorf("peer %d of %d: %w", i+1, len(fields), err)
+		}
+	}
+	return peers, nil
+}
@@ -0,0 +1,49 @@
+package env
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseWireguardServerPeers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		peers      []settings.WireguardServerPeer
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"peers with and without pre-shared key": {
+			s: "AbC=@10.64.0.2/32, dEf=:GhI=@10.64.0.3/32",
+			peers: []settings.WireguardServerPeer{
+				{PublicKey: "AbC=", Address: netip.MustParsePrefix("10.64.0.2/32")},
+				{PublicKey: "dEf=", PreSharedKey: "GhI=", Address: netip.MustParsePrefix("10.64.0.3/32")},
+			},
+		},
+		"missing address": {
+			s:          "AbC=",
+			errWrapped: ErrWireguardServerPeerNotValid,
+			errMessage: `peer is not valid: "AbC=" does not have the format publickey[:presharedkey]@address`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			peers, err := parseWireguardServerPeers(testCase.s)
+
+			assert.Equal(t, testCase.peers, peers)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
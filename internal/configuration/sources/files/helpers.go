@@ -53,3 +53,17 @@ func readPEMFile(filepath string) (base64Ptr *string, err error) {
 
 	return &base64Data, nil
 }
+
+func readStaticKeyFile(filepath string) (hexPtr *string, err error) {
+	content, err := ReadFromFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	if content == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	hexData := extract.StaticKey([]byte(*content))
+	return &hexData, nil
+}
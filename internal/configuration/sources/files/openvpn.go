@@ -2,32 +2,56 @@ package files
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
 const (
-	// OpenVPNClientKeyPath is the OpenVPN client key filepath.
-	OpenVPNClientKeyPath = "/gluetun/client.key"
-	// OpenVPNClientCertificatePath is the OpenVPN client certificate filepath.
-	OpenVPNClientCertificatePath = "/gluetun/client.crt"
-	openVPNEncryptedKey          = "/gluetun/openvpn_encrypted_key"
+	openVPNEncryptedKeyFilename  = "openvpn_encrypted_key"
+	openVPNTLSCryptV2KeyFilename = "openvpn_tls_crypt_v2.key"
+	openVPNStaticKeyFilename     = "openvpn_static.key"
 )
 
+// OpenVPNClientKeyPath returns the OpenVPN client key filepath
+// in the data directory given.
+func OpenVPNClientKeyPath(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "client.key")
+}
+
+// OpenVPNClientCertificatePath returns the OpenVPN client
+// certificate filepath in the data directory given.
+func OpenVPNClientCertificatePath(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "client.crt")
+}
+
 func (s *Source) readOpenVPN() (settings settings.OpenVPN, err error) {
-	settings.Key, err = readPEMFile(OpenVPNClientKeyPath)
+	settings.Key, err = readPEMFile(OpenVPNClientKeyPath(s.dataDirectory))
 	if err != nil {
 		return settings, fmt.Errorf("client key: %w", err)
 	}
 
-	settings.Cert, err = readPEMFile(OpenVPNClientCertificatePath)
+	settings.Cert, err = readPEMFile(OpenVPNClientCertificatePath(s.dataDirectory))
 	if err != nil {
 		return settings, fmt.Errorf("client certificate: %w", err)
 	}
-	settings.EncryptedKey, err = readPEMFile(openVPNEncryptedKey)
+	settings.EncryptedKey, err = readPEMFile(
+		filepath.Join(s.dataDirectory, openVPNEncryptedKeyFilename))
 	if err != nil {
 		return settings, fmt.Errorf("reading encrypted key file: %w", err)
 	}
 
+	settings.TLSCryptV2, err = readPEMFile(
+		filepath.Join(s.dataDirectory, openVPNTLSCryptV2KeyFilename))
+	if err != nil {
+		return settings, fmt.Errorf("tls-crypt-v2 client key: %w", err)
+	}
+
+	settings.StaticKey, err = readStaticKeyFile(
+		filepath.Join(s.dataDirectory, openVPNStaticKeyFilename))
+	if err != nil {
+		return settings, fmt.Errorf("static key: %w", err)
+	}
+
 	return settings, nil
 }
@@ -1,13 +1,20 @@
 package files
 
 import (
+	"fmt"
+
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
-type Source struct{}
+type Source struct {
+	// dataDirectory is the directory holding the files read.
+	dataDirectory string
+}
 
-func New() *Source {
-	return &Source{}
+func New(dataDirectory string) *Source {
+	return &Source{
+		dataDirectory: dataDirectory,
+	}
 }
 
 func (s *Source) String() string { return "files" }
@@ -18,6 +25,12 @@ func (s *Source) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	wireguardConfig, err := ReadWireguardConfig(WireguardConfigPath(s.dataDirectory))
+	if err != nil {
+		return settings, fmt.Errorf("Wireguard configuration: %w", err)
+	}
+	wireguardConfig.Apply(&settings)
+
 	settings.System, err = s.readSystem()
 	if err != nil {
 		return settings, err
@@ -0,0 +1,205 @@
+package files
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// WireguardConfigPath returns the wg-quick configuration filepath
+// in the data directory given.
+func WireguardConfigPath(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "wireguard", "wg0.conf")
+}
+
+// WireguardConfig contains the settings parsed
+// from a wg-quick configuration file.
+type WireguardConfig struct {
+	Wireguard settings.Wireguard
+	Selection settings.WireguardSelection
+	// DNS is the first DNS server address of the
+	// interface section, and is invalid if not set.
+	DNS netip.Addr
+}
+
+// ReadWireguardConfig reads and parses the wg-quick configuration
+// file at the path given. It returns an empty configuration
+// without error if the file does not exist.
+func ReadWireguardConfig(path string) (config WireguardConfig, err error) {
+	content, err := ReadFromFile(path)
+	if err != nil {
+		return config, fmt.Errorf("reading file: %w", err)
+	} else if content == nil {
+		return config, nil
+	}
+
+	config, err = ParseWireguardConfig(*content)
+	if err != nil {
+		return config, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Apply sets the Wireguard, server selection and DNS settings
+// given with the values parsed from the configuration.
+func (c WireguardConfig) Apply(allSettings *settings.Settings) {
+	allSettings.VPN.Wireguard = c.Wireguard
+	allSettings.VPN.Provider.ServerSelection.Wireguard = c.Selection
+	allSettings.DNS.ServerAddress = c.DNS
+}
+
+var (
+	ErrWireguardConfigSectionUnknown = errors.New("section is unknown")
+	ErrWireguardConfigPeersMultiple  = errors.New("only one peer is supported")
+	ErrWireguardConfigLineMalformed  = errors.New("line is malformed")
+	ErrWireguardConfigEndpointHost   = errors.New("endpoint host must be an IP address")
+)
+
+// ParseWireguardConfig parses the Interface and Peer sections of the
+// wg-quick configuration given. Keys not mapping to a setting, such as
+// PostUp or PersistentKeepalive, are ignored.
+func ParseWireguardConfig(content string) (config WireguardConfig, err error) {
+	var section string
+	var peers int
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			switch section {
+			case "interface":
+			case "peer":
+				peers++
+				if peers > 1 {
+					return config, fmt.Errorf("%w", ErrWireguardConfigPeersMultiple)
+				}
+			default:
+				return config, fmt.Errorf("%w: line %d: %s",
+					ErrWireguardConfigSectionUnknown, lineNumber, line)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return config, fmt.Errorf("%w: line %d: %s",
+				ErrWireguardConfigLineMalformed, lineNumber, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch section {
+		case "interface":
+			err = parseWireguardInterfaceKey(&config, key, value)
+		case "peer":
+			err = parseWireguardPeerKey(&config, key, value)
+		default:
+			err = fmt.Errorf("%w: outside of a section", ErrWireguardConfigLineMalformed)
+		}
+		if err != nil {
+			return config, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+func parseWireguardInterfaceKey(config *WireguardConfig, key, value string) (err error) {
+	switch key {
+	case "privatekey":
+		config.Wireguard.PrivateKey = &value
+	case "address":
+		addresses, err := parseCSVPrefixes(value)
+		if err != nil {
+			return fmt.Errorf("address: %w", err)
+		}
+		config.Wireguard.Addresses = append(config.Wireguard.Addresses, addresses...)
+	case "dns":
+		for _, field := range strings.Split(value, ",") {
+			address, err := netip.ParseAddr(strings.TrimSpace(field))
+			if err != nil {
+				continue // search domain
+			}
+			if !config.DNS.IsValid() {
+				config.DNS = address
+			}
+		}
+	case "mtu":
+		mtu, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return fmt.Errorf("MTU: %w", err)
+		}
+		config.Wireguard.MTU = uint16(mtu)
+	}
+	return nil
+}
+
+func parseWireguardPeerKey(config *WireguardConfig, key, value string) (err error) {
+	switch key {
+	case "publickey":
+		config.Selection.PublicKey = value
+	case "presharedkey":
+		config.Wireguard.PreSharedKey = &value
+	case "endpoint":
+		host, portString, err := net.SplitHostPort(value)
+		if err != nil {
+			return fmt.Errorf("endpoint: %w", err)
+		}
+		config.Selection.EndpointIP, err = netip.ParseAddr(host)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrWireguardConfigEndpointHost, host)
+		}
+		port, err := strconv.ParseUint(portString, 10, 16)
+		if err != nil {
+			return fmt.Errorf("endpoint port: %w", err)
+		}
+		endpointPort := uint16(port)
+		config.Selection.EndpointPort = &endpointPort
+	case "allowedips":
+		config.Wireguard.AllowedIPs, err = parseCSVPrefixes(value)
+		if err != nil {
+			return fmt.Errorf("allowed IPs: %w", err)
+		}
+	}
+	return nil
+}
+
+func parseCSVPrefixes(value string) (prefixes []netip.Prefix, err error) {
+	fields := strings.Split(value, ",")
+	prefixes = make([]netip.Prefix, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			address, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(address, address.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
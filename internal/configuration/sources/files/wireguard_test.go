@@ -0,0 +1,93 @@
+package files
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseWireguardConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		config     WireguardConfig
+		errMessage string
+	}{
+		"empty": {},
+		"full": {
+			content: `[Interface]
+# comment
+PrivateKey = wOEI9rqqbDwnN8/Bpp22sVz48T71vJ4fYmFWujulwUU=
+Address = 10.64.222.21/32, fd00::2
+DNS = 1.1.1.1, 1.0.0.1, example.com
+MTU = 1380
+PostUp = ignored
+
+[Peer]
+PublicKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20R0w=
+PresharedKey = YJ680VN+dGrdsWNjSFqZ6vvwuiNhbq502ZL3G7Q3o3g=
+AllowedIPs = 0.0.0.0/0, ::/0
+Endpoint = 1.2.3.4:51820
+`,
+			config: WireguardConfig{
+				Wireguard: settings.Wireguard{
+					PrivateKey:   ptrTo("wOEI9rqqbDwnN8/Bpp22sVz48T71vJ4fYmFWujulwUU="),
+					PreSharedKey: ptrTo("YJ680VN+dGrdsWNjSFqZ6vvwuiNhbq502ZL3G7Q3o3g="),
+					Addresses: []netip.Prefix{
+						netip.MustParsePrefix("10.64.222.21/32"),
+						netip.MustParsePrefix("fd00::2/128"),
+					},
+					AllowedIPs: []netip.Prefix{
+						netip.MustParsePrefix("0.0.0.0/0"),
+						netip.MustParsePrefix("::/0"),
+					},
+					MTU: 1380,
+				},
+				Selection: settings.WireguardSelection{
+					EndpointIP:   netip.MustParseAddr("1.2.3.4"),
+					EndpointPort: ptrTo(uint16(51820)),
+					PublicKey:    "QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20R0w=",
+				},
+				DNS: netip.MustParseAddr("1.1.1.1"),
+			},
+		},
+		"unknown section": {
+			content:    "[Other]",
+			errMessage: "section is unknown: line 1: [Other]",
+		},
+		"multiple peers": {
+			content:    "[Peer]\n[Peer]",
+			errMessage: "only one peer is supported",
+		},
+		"endpoint hostname": {
+			content:    "[Peer]\nEndpoint = vpn.example.com:51820",
+			errMessage: "line 2: endpoint host must be an IP address: vpn.example.com",
+		},
+		"malformed line": {
+			content:    "[Interface]\nPrivateKey",
+			errMessage: "line is malformed: line 2: PrivateKey",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := ParseWireguardConfig(testCase.content)
+
+			if testCase.errMessage != "" {
+				require.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.config, config)
+		})
+	}
+}
+
+func ptrTo[T any](value T) *T { return &value }
@@ -46,3 +46,18 @@ func readPEMSecretFile(secretPathEnvKey, defaultSecretPath string) (
 
 	return &base64Data, nil
 }
+
+func readStaticKeySecretFile(secretPathEnvKey, defaultSecretPath string) (
+	hexPtr *string, err error) {
+	content, err := readSecretFileAsStringPtr(secretPathEnvKey, defaultSecretPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading secret file: %w", err)
+	}
+
+	if content == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	hexData := extract.StaticKey([]byte(*content))
+	return &hexData, nil
+}
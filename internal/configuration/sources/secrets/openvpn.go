@@ -56,5 +56,21 @@ func readOpenVPN() (
 		return settings, fmt.Errorf("reading client certificate file: %w", err)
 	}
 
+	settings.TLSCryptV2, err = readPEMSecretFile(
+		"OPENVPN_TLS_CRYPT_V2_SECRETFILE",
+		"/run/secrets/openvpn_tls_crypt_v2",
+	)
+	if err != nil {
+		return settings, fmt.Errorf("reading tls-crypt-v2 client key file: %w", err)
+	}
+
+	settings.StaticKey, err = readStaticKeySecretFile(
+		"OPENVPN_STATIC_KEY_SECRETFILE",
+		"/run/secrets/openvpn_static_key",
+	)
+	if err != nil {
+		return settings, fmt.Errorf("reading static key file: %w", err)
+	}
+
 	return settings, nil
 }
@@ -1,6 +1,8 @@
 package secrets
 
 import (
+	"fmt"
+
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
@@ -18,6 +20,12 @@ func (s *Source) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	wireguardConfig, err := readWireguardConfig()
+	if err != nil {
+		return settings, fmt.Errorf("Wireguard configuration: %w", err)
+	}
+	wireguardConfig.Apply(&settings)
+
 	settings.HTTPProxy, err = readHTTPProxy()
 	if err != nil {
 		return settings, err
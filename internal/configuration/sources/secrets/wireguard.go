@@ -0,0 +1,13 @@
+package secrets
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/sources/files"
+)
+
+func readWireguardConfig() (config files.WireguardConfig, err error) {
+	path := getCleanedEnv("WIREGUARD_CONF_SECRETFILE")
+	if path == "" {
+		path = "/run/secrets/wg0.conf"
+	}
+	return files.ReadWireguardConfig(path)
+}
@@ -0,0 +1,18 @@
+package features
+
+const (
+	// P2P is for servers allowing peer to peer traffic.
+	P2P = "p2p"
+	// Streaming is for servers optimized for streaming services.
+	Streaming = "streaming"
+	// Stealth is for servers obfuscating the VPN traffic.
+	Stealth = "stealth"
+)
+
+func All() []string {
+	return []string{
+		P2P,
+		Streaming,
+		Stealth,
+	}
+}
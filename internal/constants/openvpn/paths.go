@@ -1,9 +1,20 @@
 package openvpn
 
-const (
-	// AuthConf is the file path to the OpenVPN auth file.
-	AuthConf = "/etc/openvpn/auth.conf"
-	// AskPassPath is the file path to the decryption passphrase for
-	// and encrypted private key, which is pointed by `askpass`.
-	AskPassPath = "/etc/openvpn/askpass" //nolint:gosec
-)
+import "path/filepath"
+
+// AuthConf returns the file path to the OpenVPN auth file.
+func AuthConf(runtimeDirectory string) string {
+	return filepath.Join(runtimeDirectory, "openvpn", "auth.conf")
+}
+
+// AskPassPath returns the file path to the decryption passphrase for
+// and encrypted private key, which is pointed by `askpass`.
+func AskPassPath(runtimeDirectory string) string {
+	return filepath.Join(runtimeDirectory, "openvpn", "askpass")
+}
+
+// SocksAuthConf returns the file path to the credentials file of
+// the SOCKS proxy, which is pointed by `socks-proxy`.
+func SocksAuthConf(runtimeDirectory string) string {
+	return filepath.Join(runtimeDirectory, "openvpn", "socksauth.conf")
+}
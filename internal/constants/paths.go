@@ -1,6 +1,36 @@
 package constants
 
+import "path/filepath"
+
 const (
-	// ServersData is the server information filepath.
-	ServersData = "/gluetun/servers.json"
+	// DefaultDataDirectory is the default directory holding
+	// persisted data. It should be a volume when running with
+	// a read-only root filesystem.
+	DefaultDataDirectory = "/gluetun"
+	// DefaultRuntimeDirectory is the default directory holding
+	// files written at runtime. It can be a tmpfs when running
+	// with a read-only root filesystem.
+	DefaultRuntimeDirectory = "/tmp/gluetun"
 )
+
+// UnboundDirectory returns the Unbound working directory,
+// holding its configuration and root files.
+func UnboundDirectory(runtimeDirectory string) string {
+	return filepath.Join(runtimeDirectory, "unbound")
+}
+
+// ServersData returns the server information filepath.
+func ServersData(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "servers.json")
+}
+
+// BandwidthData returns the cumulative bandwidth totals filepath.
+func BandwidthData(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "bandwidth.json")
+}
+
+// WireguardServerPeers returns the filepath of the Wireguard
+// server peers added at runtime.
+func WireguardServerPeers(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "wgserverpeers.json")
+}
@@ -5,4 +5,7 @@ const (
 	TCP string = "tcp"
 	// UDP is a network protocol (unreliable and faster than TCP).
 	UDP string = "udp"
+	// TLS is a transport over TCP used to tunnel Wireguard
+	// packets, to look like HTTPS traffic to deep packet inspection.
+	TLS string = "tls"
 )
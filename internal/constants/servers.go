@@ -0,0 +1,14 @@
+package constants
+
+const (
+	// ServersMergeNewest uses, for each provider, the servers from the
+	// file if they are more recent than the embedded servers, and the
+	// embedded servers with the servers to keep from the file otherwise.
+	ServersMergeNewest = "newest"
+	// ServersMergeFile uses, for each provider, the servers
+	// from the file if any, and the embedded servers otherwise.
+	ServersMergeFile = "file"
+	// ServersMergeEmbedded uses, for each provider, the embedded
+	// servers with the servers to keep from the file.
+	ServersMergeEmbedded = "embedded"
+)
@@ -0,0 +1,7 @@
+package constants
+
+const (
+	// TailscaleGroupID is the group id the Tailscale daemon runs
+	// with, which is used by the firewall to tell its traffic apart.
+	TailscaleGroupID = 41641
+)
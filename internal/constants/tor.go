@@ -0,0 +1,7 @@
+package constants
+
+const (
+	// TorUser is the system user the Tor client runs as, which is
+	// used by the firewall to tell its traffic apart.
+	TorUser = "tor"
+)
@@ -3,4 +3,6 @@ package vpn
 const (
 	OpenVPN   = "openvpn"
 	Wireguard = "wireguard"
+	Tailscale = "tailscale"
+	Tor       = "tor"
 )
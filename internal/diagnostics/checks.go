@@ -0,0 +1,142 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/sysctl"
+)
+
+var (
+	ErrWireguardKernelNotSupported = errors.New("kernel Wireguard is not supported")
+	ErrDNSEgressNoIP               = errors.New("no IP address resolved")
+)
+
+const tunDevice = "/dev/net/tun"
+
+func (d *Diagnostics) checkTUN(report *Report) {
+	const check = "TUN device"
+	err := d.tun.Check(tunDevice)
+	if err != nil {
+		// The TUN device is created at startup if it is missing.
+		report.warn(check, err.Error()+"; it will be created at startup if possible")
+		return
+	}
+	report.pass(check, tunDevice+" is available")
+}
+
+func (d *Diagnostics) checkFirewall(ctx context.Context, report *Report) {
+	const check = "firewall"
+	iptablesPath, err := firewall.CheckIptablesSupport(ctx, d.cmder)
+	if err != nil {
+		report.fail(check, err)
+		return
+	}
+	report.pass(check, iptablesPath+" can modify rules")
+}
+
+func (d *Diagnostics) checkKernelParameters(report *Report,
+	allSettings settings.Settings) {
+	const check = "kernel parameters"
+	required := sysctl.Required(allSettings.VPN,
+		allSettings.WireguardServer, allSettings.LANGateway)
+	if len(required) == 0 {
+		report.pass(check, "none required")
+		return
+	}
+
+	for _, setting := range required {
+		check := "kernel parameter " + setting.Key
+		current, err := d.sysctl.Get(setting.Key)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			report.warn(check, "not supported by the kernel")
+		case err != nil:
+			report.fail(check, err)
+		case current == setting.Value:
+			report.pass(check, "set to "+current)
+		default:
+			report.warn(check, "set to "+current+" instead of "+setting.Value+
+				"; it will be changed at startup if /proc/sys is writable,"+
+				" otherwise run the container with --sysctl "+setting.String())
+		}
+	}
+}
+
+func (d *Diagnostics) checkIPv6(report *Report, vpnSettings settings.VPN) {
+	const check = "IPv6"
+	supported, err := d.netLinker.IsIPv6Supported()
+	switch {
+	case err != nil:
+		report.fail(check, err)
+	case supported:
+		report.pass(check, "supported")
+	case vpnSettings.Type == vpn.Wireguard && hasIPv6Address(vpnSettings.Wireguard.Addresses):
+		report.warn(check, "not supported but an IPv6 Wireguard address is set")
+	default:
+		report.pass(check, "not supported")
+	}
+}
+
+func hasIPv6Address(addresses []netip.Prefix) bool {
+	for _, address := range addresses {
+		if address.Addr().Is6() {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Diagnostics) checkWireguard(report *Report, vpnSettings settings.VPN) {
+	const check = "kernel Wireguard"
+	if vpnSettings.Type != vpn.Wireguard {
+		report.skip(check, "VPN type is "+vpnSettings.Type)
+		return
+	}
+
+	supported, err := d.netLinker.IsWireguardSupported()
+	switch {
+	case err != nil:
+		report.fail(check, err)
+	case supported:
+		report.pass(check, "supported")
+	case vpnSettings.Wireguard.Implementation == "kernelspace":
+		report.fail(check, fmt.Errorf("%w: and the implementation is set to kernelspace",
+			ErrWireguardKernelNotSupported))
+	default:
+		report.warn(check, "not supported, the userspace implementation will be used")
+	}
+}
+
+func checkDNSEgress(ctx context.Context, report *Report, dnsServer netip.AddrPort) {
+	const check = "DNS egress"
+	dialer := net.Dialer{}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "udp", dnsServer.String())
+		},
+	}
+
+	const timeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	const hostname = "github.com"
+	ips, err := resolver.LookupNetIP(ctx, "ip4", hostname)
+	switch {
+	case err != nil:
+		report.fail(check, fmt.Errorf("resolving %s using %s: %w", hostname, dnsServer, err))
+	case len(ips) == 0:
+		report.fail(check, fmt.Errorf("%w: for %s using %s", ErrDNSEgressNoIP, hostname, dnsServer))
+	default:
+		report.pass(check, "resolved "+hostname+" to "+ips[0].String()+" using "+dnsServer.String())
+	}
+}
@@ -0,0 +1,123 @@
+package diagnostics
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/sysctl"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSysctl map[string]string
+
+func (f fakeSysctl) Get(key string) (value string, err error) {
+	value, ok := f[key]
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	return value, nil
+}
+
+type fakeNetLinker struct {
+	ipv6Supported      bool
+	wireguardSupported bool
+	err                error
+}
+
+func (f *fakeNetLinker) IsIPv6Supported() (bool, error) {
+	return f.ipv6Supported, f.err
+}
+
+func (f *fakeNetLinker) IsWireguardSupported() (bool, error) {
+	return f.wireguardSupported, f.err
+}
+
+func Test_Diagnostics_checkKernelParameters(t *testing.T) {
+	t.Parallel()
+
+	allSettings := settings.Settings{}
+	allSettings.SetDefaults()
+	allSettings.VPN.Type = vpn.Wireguard
+	*allSettings.LANGateway.Enabled = true
+
+	d := &Diagnostics{
+		sysctl: fakeSysctl{
+			sysctl.IPv4Forward:      "1",
+			sysctl.IPv4SrcValidMark: "0",
+		},
+	}
+	var report Report
+	d.checkKernelParameters(&report, allSettings)
+
+	expected := "PASS kernel parameter net.ipv4.ip_forward: set to 1\n" +
+		"WARN kernel parameter net.ipv4.conf.all.src_valid_mark: set to 0 instead of 1; " +
+		"it will be changed at startup if /proc/sys is writable, otherwise run " +
+		"the container with --sysctl net.ipv4.conf.all.src_valid_mark=1"
+	assert.Equal(t, expected, report.String())
+	assert.False(t, report.Failed())
+}
+
+func Test_Diagnostics_checkWireguard(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		vpnType        string
+		implementation string
+		netLinker      *fakeNetLinker
+		result         Result
+	}{
+		"openvpn": {
+			vpnType: vpn.OpenVPN,
+			result: Result{Status: StatusSkip, Check: "kernel Wireguard",
+				Details: "VPN type is openvpn"},
+		},
+		"supported": {
+			vpnType:        vpn.Wireguard,
+			implementation: "auto",
+			netLinker:      &fakeNetLinker{wireguardSupported: true},
+			result: Result{Status: StatusPass, Check: "kernel Wireguard",
+				Details: "supported"},
+		},
+		"unsupported with auto implementation": {
+			vpnType:        vpn.Wireguard,
+			implementation: "auto",
+			netLinker:      &fakeNetLinker{},
+			result: Result{Status: StatusWarn, Check: "kernel Wireguard",
+				Details: "not supported, the userspace implementation will be used"},
+		},
+		"unsupported with kernelspace implementation": {
+			vpnType:        vpn.Wireguard,
+			implementation: "kernelspace",
+			netLinker:      &fakeNetLinker{},
+			result: Result{Status: StatusFail, Check: "kernel Wireguard",
+				Details: "kernel Wireguard is not supported: and the implementation is set to kernelspace"},
+		},
+		"netlink error": {
+			vpnType:   vpn.Wireguard,
+			netLinker: &fakeNetLinker{err: errTest},
+			result: Result{Status: StatusFail, Check: "kernel Wireguard",
+				Details: "test error"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &Diagnostics{netLinker: testCase.netLinker}
+			vpnSettings := settings.VPN{Type: testCase.vpnType}
+			vpnSettings.Wireguard.Implementation = testCase.implementation
+
+			var report Report
+			d.checkWireguard(&report, vpnSettings)
+
+			assert.Equal(t, []Result{testCase.result}, report.Results)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Package diagnostics runs pre-flight checks of the host environment
+// needed to establish the VPN connection, such as the TUN device,
+// iptables, kernel parameters and DNS egress.
+package diagnostics
+
+import (
+	"context"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/golibs/command"
+)
+
+// Diagnostics runs pre-flight checks and reports their results.
+type Diagnostics struct {
+	tun       TunChecker
+	netLinker NetLinker
+	cmder     command.Runner
+	sysctl    SysctlGetter
+}
+
+// New creates a pre-flight diagnostics runner.
+func New(tun TunChecker, netLinker NetLinker,
+	cmder command.Runner, sysctl SysctlGetter) *Diagnostics {
+	return &Diagnostics{
+		tun:       tun,
+		netLinker: netLinker,
+		cmder:     cmder,
+		sysctl:    sysctl,
+	}
+}
+
+// Run runs all the checks relevant to the settings given and
+// returns a report of their results. A failing check does not
+// stop the remaining checks from running.
+func (d *Diagnostics) Run(ctx context.Context, allSettings settings.Settings) (
+	report Report) {
+	d.checkTUN(&report)
+	d.checkFirewall(ctx, &report)
+	d.checkKernelParameters(&report, allSettings)
+	d.checkIPv6(&report, allSettings.VPN)
+	d.checkWireguard(&report, allSettings.VPN)
+	checkDNSEgress(ctx, &report, *allSettings.Diagnostics.DNSServer)
+	return report
+}
@@ -0,0 +1,14 @@
+package diagnostics
+
+type TunChecker interface {
+	Check(path string) error
+}
+
+type NetLinker interface {
+	IsIPv6Supported() (supported bool, err error)
+	IsWireguardSupported() (ok bool, err error)
+}
+
+type SysctlGetter interface {
+	Get(key string) (value string, err error)
+}
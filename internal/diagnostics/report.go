@@ -0,0 +1,66 @@
+package diagnostics
+
+import (
+	"strings"
+)
+
+// Status is the outcome of a diagnostics check.
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP"
+)
+
+// Result is the outcome of a single diagnostics check.
+type Result struct {
+	Status  Status
+	Check   string
+	Details string
+}
+
+func (r Result) String() string {
+	return string(r.Status) + " " + r.Check + ": " + r.Details
+}
+
+// Report contains the results of all the diagnostics checks,
+// in the order they were run.
+type Report struct {
+	Results []Result
+}
+
+// Failed returns true if at least one check failed.
+func (r Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Report) String() string {
+	lines := make([]string, len(r.Results))
+	for i, result := range r.Results {
+		lines[i] = result.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Report) pass(check, details string) {
+	r.Results = append(r.Results, Result{Status: StatusPass, Check: check, Details: details})
+}
+
+func (r *Report) warn(check, details string) {
+	r.Results = append(r.Results, Result{Status: StatusWarn, Check: check, Details: details})
+}
+
+func (r *Report) fail(check string, err error) {
+	r.Results = append(r.Results, Result{Status: StatusFail, Check: check, Details: err.Error()})
+}
+
+func (r *Report) skip(check, reason string) {
+	r.Results = append(r.Results, Result{Status: StatusSkip, Check: check, Details: reason})
+}
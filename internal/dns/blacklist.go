@@ -0,0 +1,58 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// GetBlocklist returns the hosts and IP addresses
+// currently allowed or blocked in addition to the
+// built-in block lists.
+func (l *Loop) GetBlocklist() (blocklist models.DNSBlocklist) {
+	blacklist := l.GetSettings().DoT.Blacklist
+	return models.DNSBlocklist{
+		AllowedHosts:      helpers.CopySlice(blacklist.AllowedHosts),
+		BlockedHosts:      helpers.CopySlice(blacklist.AddBlockedHosts),
+		BlockedIPs:        helpers.CopySlice(blacklist.AddBlockedIPs),
+		BlockedIPPrefixes: helpers.CopySlice(blacklist.AddBlockedIPPrefixes),
+	}
+}
+
+// SetBlocklist overrides the allowed and blocked hosts and IP addresses
+// with the non nil fields of the blocklist given. If Unbound is running,
+// its blacklist is rebuilt and Unbound reloads its configuration without
+// being restarted.
+func (l *Loop) SetBlocklist(ctx context.Context, blocklist models.DNSBlocklist) (
+	outcome string, err error) {
+	blacklist := l.GetSettings().DoT.Blacklist
+	blacklist.AllowedHosts = helpers.OverrideWithSlice(blacklist.AllowedHosts, blocklist.AllowedHosts)
+	blacklist.AddBlockedHosts = helpers.OverrideWithSlice(blacklist.AddBlockedHosts, blocklist.BlockedHosts)
+	blacklist.AddBlockedIPs = helpers.OverrideWithSlice(blacklist.AddBlockedIPs, blocklist.BlockedIPs)
+	blacklist.AddBlockedIPPrefixes = helpers.OverrideWithSlice(blacklist.AddBlockedIPPrefixes,
+		blocklist.BlockedIPPrefixes)
+	err = blacklist.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	l.state.SetBlacklist(blacklist)
+
+	if !*l.GetSettings().DoT.Enabled || l.GetStatus() != constants.Running {
+		return "blocklist updated", nil
+	}
+
+	err = l.makeUnboundConf(ctx)
+	if err != nil {
+		return "", fmt.Errorf("making Unbound configuration: %w", err)
+	}
+
+	err = l.reloader.Reload()
+	if err != nil {
+		return "", err
+	}
+	return "blocklist updated and reloaded", nil
+}
@@ -0,0 +1,147 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+var ErrBlocklistStatusCode = errors.New("bad HTTP status code for blocklist")
+
+// getBlocklistsHostnames fetches and parses the blocklist sources given,
+// each being an HTTP(S) URL or a file path. It returns the unique hostnames
+// found and an error for each source that could not be fetched.
+func getBlocklistsHostnames(ctx context.Context, client *http.Client,
+	sources []string) (hostnames []string, errs []error) {
+	uniqueHostnames := make(map[string]struct{})
+	for _, source := range sources {
+		sourceHostnames, err := getBlocklistHostnames(ctx, client, source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("blocklist %s: %w", source, err))
+			continue
+		}
+		for _, hostname := range sourceHostnames {
+			if _, ok := uniqueHostnames[hostname]; ok {
+				continue
+			}
+			uniqueHostnames[hostname] = struct{}{}
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	return hostnames, errs
+}
+
+func getBlocklistHostnames(ctx context.Context, client *http.Client,
+	source string) (hostnames []string, err error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return parseBlocklist(file)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d %s", ErrBlocklistStatusCode,
+			response.StatusCode, response.Status)
+	}
+
+	return parseBlocklist(response.Body)
+}
+
+// parseBlocklist parses a blocklist where each line is either a hostname,
+// a hosts file entry such as `0.0.0.0 example.com` or a basic AdBlock rule
+// such as `||example.com^`. Comments, AdBlock exception rules and AdBlock
+// rules with options or wildcards are ignored.
+func parseBlocklist(reader io.Reader) (hostnames []string, err error) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "",
+			strings.HasPrefix(line, "#"), // hosts and plain comment
+			strings.HasPrefix(line, "!"), // AdBlock comment
+			strings.HasPrefix(line, "["): // AdBlock header
+			continue
+		case strings.HasPrefix(line, "||"):
+			hostname, ok := parseAdBlockRule(line)
+			if ok {
+				hostnames = append(hostnames, hostname)
+			}
+			continue
+		}
+
+		line, _, _ = strings.Cut(line, "#") // trailing comment
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			fields = []string{"", fields[0]}
+		default:
+			_, err := netip.ParseAddr(fields[0])
+			if err != nil {
+				continue // not a hosts file entry
+			}
+		}
+
+		for _, field := range fields[1:] {
+			hostname, ok := cleanBlocklistHostname(field)
+			if ok {
+				hostnames = append(hostnames, hostname)
+			}
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return hostnames, nil
+}
+
+// parseAdBlockRule parses a basic AdBlock rule `||example.com^`,
+// returning false if the rule is not a basic domain blocking rule.
+func parseAdBlockRule(rule string) (hostname string, ok bool) {
+	rule = strings.TrimPrefix(rule, "||")
+	hostname, ok = strings.CutSuffix(rule, "^")
+	if !ok {
+		return "", false
+	}
+	return cleanBlocklistHostname(hostname)
+}
+
+// cleanBlocklistHostname lowercases the hostname given and returns
+// false if it is not a hostname to block, such as localhost.
+func cleanBlocklistHostname(hostname string) (cleaned string, ok bool) {
+	cleaned = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	switch cleaned {
+	case "", "localhost", "localhost.localdomain", "local",
+		"broadcasthost", "ip6-localhost", "ip6-loopback":
+		return "", false
+	}
+	if strings.ContainsAny(cleaned, "*/:$|^@ ") || !strings.Contains(cleaned, ".") {
+		return "", false
+	} else if _, err := netip.ParseAddr(cleaned); err == nil {
+		return "", false
+	}
+	return cleaned, true
+}
@@ -0,0 +1,40 @@
+package dns
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseBlocklist(t *testing.T) {
+	t.Parallel()
+
+	const blocklist = `[Adblock Plus 2.0]
+! AdBlock comment
+# hosts comment
+127.0.0.1 localhost
+0.0.0.0 0.0.0.0
+0.0.0.0 Ads.Example.com tracker.example.com # trailing comment
+:: ipv6.example.com
+plain.example.com
+||adblock.example.com^
+||options.example.com^$third-party
+@@||allowed.example.com^
+||*.wildcard.example.com^
+not-a-hostname
+`
+
+	hostnames, err := parseBlocklist(strings.NewReader(blocklist))
+
+	require.NoError(t, err)
+	expected := []string{
+		"ads.example.com",
+		"tracker.example.com",
+		"ipv6.example.com",
+		"plain.example.com",
+		"adblock.example.com",
+	}
+	assert.Equal(t, expected, hostnames)
+}
@@ -0,0 +1,28 @@
+package dns
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// writeIncludeConf writes the Unbound configuration file included
+// in the server clause of the main configuration file. It enables
+// query and local action logging, used to count queried and blocked
+// domains, and sets the private domains given, which are allowed to
+// resolve to the private addresses filtered out of answers.
+func writeIncludeConf(fileWriter FileWriter, unboundDir string,
+	privateDomains []string) (err error) {
+	lines := []string{
+		"  log-queries: yes",
+		"  log-local-actions: yes",
+	}
+	for _, domain := range privateDomains {
+		lines = append(lines, `  private-domain: "`+domain+`"`)
+	}
+	content := strings.Join(lines, "\n") + "\n"
+
+	path := filepath.Join(unboundDir, "include.conf")
+	const perms = fs.FileMode(0644)
+	return fileWriter.WriteFile(path, []byte(content), perms)
+}
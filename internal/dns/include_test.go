@@ -0,0 +1,31 @@
+package dns
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeIncludeConf(t *testing.T) {
+	t.Parallel()
+
+	unboundDir := t.TempDir()
+
+	fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+
+	err := writeIncludeConf(fileWriter, unboundDir, []string{"fritz.box", "plex.direct"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(unboundDir, "include.conf"))
+	require.NoError(t, err)
+	const expected = `  log-queries: yes
+  log-local-actions: yes
+  private-domain: "fritz.box"
+  private-domain: "plex.direct"
+`
+	assert.Equal(t, expected, string(content))
+}
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"context"
+	"io/fs"
 
 	"github.com/qdm12/dns/pkg/unbound"
 )
@@ -13,3 +14,11 @@ type Configurator interface {
 		stdoutLines, stderrLines chan string, waitError chan error, err error)
 	Version(ctx context.Context) (version string, err error)
 }
+
+type Reloader interface {
+	Reload() (err error)
+}
+
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
+}
@@ -35,7 +35,13 @@ func (l *Loop) collectLines(ctx context.Context, done chan<- struct{},
 		case line = <-stdout:
 		}
 
+		recorded := l.recordStatsLine(line)
 		line, level := processLogLine(line)
+		if recorded {
+			// Query log lines are only used for statistics
+			// and would flood the info logs.
+			level = levelDebug
+		}
 		switch level {
 		case levelDebug:
 			l.logger.Debug(line)
@@ -3,6 +3,7 @@ package dns
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/qdm12/dns/pkg/blacklist"
@@ -17,25 +18,40 @@ type Loop struct {
 	statusManager *loopstate.State
 	state         *state.State
 	conf          Configurator
-	resolvConf    string
-	blockBuilder  blacklist.Builder
-	client        *http.Client
-	logger        Logger
-	userTrigger   bool
-	start         <-chan struct{}
-	running       chan<- models.LoopStatus
-	stop          <-chan struct{}
-	stopped       chan<- struct{}
-	updateTicker  <-chan struct{}
-	backoffTime   time.Duration
-	timeNow       func() time.Time
-	timeSince     func(time.Time) time.Duration
+	reloader      Reloader
+	confMutex     sync.Mutex
+	// upstreams maps each DoT upstream provider checked
+	// to the result of its last health check.
+	upstreams      map[string]upstreamHealth
+	upstreamsMutex sync.RWMutex
+	stats          *queryStats
+	resolvConf     string
+	unboundDir     string
+	fileWriter     FileWriter
+	blockBuilder   blacklist.Builder
+	client         *http.Client
+	logger         Logger
+	userTrigger    bool
+	start          <-chan struct{}
+	running        chan<- models.LoopStatus
+	stop           <-chan struct{}
+	stopped        chan<- struct{}
+	updateTicker   <-chan struct{}
+	backoffTime    time.Duration
+	timeNow        func() time.Time
+	timeSince      func(time.Time) time.Duration
 }
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLoop(conf Configurator, settings settings.DNS,
-	client *http.Client, logger Logger) *Loop {
+// NewLoop creates a DNS loop. The reloader given signals Unbound to
+// reload its configuration. The resolvConf path given is the
+// resolv.conf file to update with the DNS server address, and
+// can be left empty to not modify any resolv.conf file. The Unbound
+// directory given is where the Unbound include configuration is written.
+func NewLoop(conf Configurator, reloader Reloader, settings settings.DNS,
+	client *http.Client, fileWriter FileWriter, logger Logger,
+	resolvConf, unboundDir string) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
@@ -49,7 +65,12 @@ func NewLoop(conf Configurator, settings settings.DNS,
 		statusManager: statusManager,
 		state:         state,
 		conf:          conf,
-		resolvConf:    "/etc/resolv.conf",
+		reloader:      reloader,
+		upstreams:     make(map[string]upstreamHealth),
+		stats:         newQueryStats(time.Now()),
+		resolvConf:    resolvConf,
+		unboundDir:    unboundDir,
+		fileWriter:    fileWriter,
 		blockBuilder:  blacklist.NewBuilder(client),
 		client:        client,
 		logger:        logger,
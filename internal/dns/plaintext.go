@@ -19,10 +19,7 @@ func (l *Loop) useUnencryptedDNS(fallback bool) {
 			l.logger.Info("using plaintext DNS at address " + targetIP.String())
 		}
 		nameserver.UseDNSInternally(targetIP.AsSlice())
-		err := nameserver.UseDNSSystemWide(l.resolvConf, targetIP.AsSlice(), *settings.KeepNameserver)
-		if err != nil {
-			l.logger.Error(err.Error())
-		}
+		l.useDNSSystemWide(targetIP, *settings.KeepNameserver)
 		return
 	}
 
@@ -39,7 +36,17 @@ func (l *Loop) useUnencryptedDNS(fallback bool) {
 		l.logger.Info("using plaintext DNS at address " + targetIP.String())
 	}
 	nameserver.UseDNSInternally(targetIP.AsSlice())
-	err = nameserver.UseDNSSystemWide(l.resolvConf, targetIP.AsSlice(), *settings.KeepNameserver)
+	l.useDNSSystemWide(targetIP, *settings.KeepNameserver)
+}
+
+// useDNSSystemWide sets the DNS server address in the resolv.conf
+// file, unless no resolv.conf file path is set.
+func (l *Loop) useDNSSystemWide(ip netip.Addr, keepNameserver bool) {
+	if l.resolvConf == "" {
+		return
+	}
+
+	err := nameserver.UseDNSSystemWide(l.resolvConf, ip.AsSlice(), keepNameserver)
 	if err != nil {
 		l.logger.Error(err.Error())
 	}
@@ -0,0 +1,51 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/qdm12/golibs/command"
+)
+
+var ErrUnboundNotStarted = errors.New("unbound is not started")
+
+// Process wraps a command starter to keep track of the last
+// Unbound process started, in order to signal it to reload
+// its configuration without restarting it.
+type Process struct {
+	command.RunStarter
+	cmd   *exec.Cmd
+	mutex sync.Mutex
+}
+
+func NewProcess(cmder command.RunStarter) *Process {
+	return &Process{
+		RunStarter: cmder,
+	}
+}
+
+func (p *Process) Start(cmd command.ExecCmd) (stdoutLines, stderrLines chan string,
+	waitError chan error, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.cmd, _ = cmd.(*exec.Cmd)
+	return p.RunStarter.Start(cmd)
+}
+
+// Reload signals the last Unbound process started
+// to reload its configuration file.
+func (p *Process) Reload() (err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.cmd == nil || p.cmd.Process == nil {
+		return fmt.Errorf("%w", ErrUnboundNotStarted)
+	}
+	err = p.cmd.Process.Signal(syscall.SIGHUP)
+	if err != nil {
+		return fmt.Errorf("signaling unbound to reload: %w", err)
+	}
+	return nil
+}
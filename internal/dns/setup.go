@@ -44,11 +44,7 @@ func (l *Loop) setupUnbound(ctx context.Context) (
 
 	// use Unbound
 	nameserver.UseDNSInternally(settings.ServerAddress.AsSlice())
-	err = nameserver.UseDNSSystemWide(l.resolvConf, settings.ServerAddress.AsSlice(),
-		*settings.KeepNameserver)
-	if err != nil {
-		l.logger.Error(err.Error())
-	}
+	l.useDNSSystemWide(settings.ServerAddress, *settings.KeepNameserver)
 
 	if err := check.WaitForDNS(ctx, net.DefaultResolver); err != nil {
 		cancel()
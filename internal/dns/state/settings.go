@@ -44,3 +44,11 @@ func (s *State) SetSettings(ctx context.Context, settings settings.DNS) (
 	}
 	return outcome
 }
+
+// SetBlacklist sets the DNS blacklist settings without
+// restarting the DNS loop.
+func (s *State) SetBlacklist(blacklist settings.DNSBlacklist) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.settings.DoT.Blacklist = blacklist
+}
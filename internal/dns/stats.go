@@ -0,0 +1,174 @@
+package dns
+
+import (
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// maxStatsDomains is the maximum number of domains tracked
+// for each of the queried and blocked domain counts, to bound
+// the memory used by the statistics.
+const maxStatsDomains = 10000
+
+// queryStats counts the domains queried and blocked by Unbound.
+type queryStats struct {
+	since        time.Time
+	totalQueried uint64
+	totalBlocked uint64
+	queried      map[string]uint64
+	blocked      map[string]uint64
+	mutex        sync.Mutex
+}
+
+func newQueryStats(now time.Time) *queryStats {
+	return &queryStats{
+		since:   now,
+		queried: make(map[string]uint64),
+		blocked: make(map[string]uint64),
+	}
+}
+
+func (s *queryStats) addQueried(domain string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.totalQueried++
+	incrementBounded(s.queried, domain)
+}
+
+func (s *queryStats) addBlocked(domain string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.totalBlocked++
+	incrementBounded(s.blocked, domain)
+}
+
+func (s *queryStats) reset(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.since = now
+	s.totalQueried = 0
+	s.totalBlocked = 0
+	s.queried = make(map[string]uint64)
+	s.blocked = make(map[string]uint64)
+}
+
+func (s *queryStats) top(limit int) (top models.DNSTopDomains) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return models.DNSTopDomains{
+		Since:        s.since,
+		TotalQueried: s.totalQueried,
+		TotalBlocked: s.totalBlocked,
+		TopQueried:   topCounts(s.queried, limit),
+		TopBlocked:   topCounts(s.blocked, limit),
+	}
+}
+
+// incrementBounded increments the count of the domain given.
+// If the domain is not yet tracked and the maximum number of
+// domains is reached, the least counted half of the domains is
+// dropped first, so frequent domains are kept over time.
+func incrementBounded(counts map[string]uint64, domain string) {
+	if _, ok := counts[domain]; !ok && len(counts) >= maxStatsDomains {
+		sorted := topCounts(counts, 0)
+		for _, domainCount := range sorted[len(sorted)/2:] {
+			delete(counts, domainCount.Domain)
+		}
+	}
+	counts[domain]++
+}
+
+// topCounts returns the domain counts sorted by decreasing count,
+// and limited to the limit given if it is not zero.
+func topCounts(counts map[string]uint64, limit int) (top []models.DNSDomainCount) {
+	top = make([]models.DNSDomainCount, 0, len(counts))
+	for domain, count := range counts {
+		top = append(top, models.DNSDomainCount{Domain: domain, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Domain < top[j].Domain
+	})
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// GetTopDomains returns the most queried and most blocked domains
+// since the statistics were last reset, limited to the limit given.
+func (l *Loop) GetTopDomains(limit int) (top models.DNSTopDomains) {
+	return l.stats.top(limit)
+}
+
+// ResetTopDomains resets the queried and blocked domains statistics.
+func (l *Loop) ResetTopDomains() {
+	l.stats.reset(l.timeNow())
+}
+
+// recordStatsLine records the query or blocked query from the
+// Unbound log line given. It returns false if the line is not
+// a query or local action log line.
+func (l *Loop) recordStatsLine(line string) (recorded bool) {
+	line = line[len(unboundPrefix.FindString(line)):]
+	line, ok := strings.CutPrefix(line, "info: ")
+	if !ok {
+		return false
+	}
+
+	domain, blocked, ok := parseStatsLine(line)
+	switch {
+	case !ok:
+		return false
+	case domain == "": // local action of a non-blocking local zone
+	case blocked:
+		l.stats.addBlocked(domain)
+	default:
+		l.stats.addQueried(domain)
+	}
+	return true
+}
+
+// parseStatsLine parses a query log line such as
+// `127.0.0.1 example.com. A IN` or a local action log line such as
+// `example.com. static 127.0.0.1@53533 ads.example.com. A IN`.
+// The domain returned is empty for local action lines of
+// non-blocking local zone types, such as redirect or transparent.
+func parseStatsLine(line string) (domain string, blocked, ok bool) {
+	fields := strings.Fields(line)
+	const queryFields, localActionFields = 4, 6
+	switch len(fields) {
+	case queryFields:
+		_, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return "", false, false
+		}
+		return normalizeDomain(fields[1]), false, true
+	case localActionFields:
+		if !strings.Contains(fields[2], "@") {
+			return "", false, false
+		}
+		switch fields[1] {
+		case "static", "refuse", "deny", "always_refuse",
+			"always_nxdomain", "always_null":
+			return normalizeDomain(fields[3]), true, true
+		}
+		return "", false, true
+	default:
+		return "", false, false
+	}
+}
+
+func normalizeDomain(domain string) string {
+	if domain != "." {
+		domain = strings.TrimSuffix(domain, ".")
+	}
+	return strings.ToLower(domain)
+}
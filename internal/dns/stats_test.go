@@ -0,0 +1,126 @@
+package dns
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseStatsLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line    string
+		domain  string
+		blocked bool
+		ok      bool
+	}{
+		"empty": {},
+		"other info line": {
+			line: "start of service (unbound 1.17.1).",
+		},
+		"query": {
+			line:   "127.0.0.1 Example.com. A IN",
+			domain: "example.com",
+			ok:     true,
+		},
+		"query for root": {
+			line:   "::1 . NS IN",
+			domain: ".",
+			ok:     true,
+		},
+		"blocked query": {
+			line:    "ads.example.com. static 127.0.0.1@53533 ads.example.com. AAAA IN",
+			domain:  "ads.example.com",
+			blocked: true,
+			ok:      true,
+		},
+		"non blocking local action": {
+			line: "localhost. redirect 127.0.0.1@53533 localhost. A IN",
+			ok:   true,
+		},
+		"six fields without address": {
+			line: "generate keytag query _ta-4f66. NULL IN",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			domain, blocked, ok := parseStatsLine(testCase.line)
+
+			assert.Equal(t, testCase.domain, domain)
+			assert.Equal(t, testCase.blocked, blocked)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func Test_Loop_recordStatsLine(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1, 0)
+	loop := &Loop{
+		stats:   newQueryStats(now),
+		timeNow: func() time.Time { return now },
+	}
+
+	lines := []string{
+		"[1594595249] unbound[75:0] info: 127.0.0.1 a.com. A IN",
+		"[1594595249] unbound[75:0] info: 127.0.0.1 b.com. A IN",
+		"[1594595249] unbound[75:0] info: 127.0.0.1 b.com. AAAA IN",
+		"[1594595249] unbound[75:0] info: 127.0.0.1 ads.com. A IN",
+		"[1594595249] unbound[75:0] info: ads.com. static 127.0.0.1@5353 ads.com. A IN",
+	}
+	for _, line := range lines {
+		recorded := loop.recordStatsLine(line)
+		assert.True(t, recorded)
+	}
+	recorded := loop.recordStatsLine("[1594595249] unbound[75:0] notice: init module 0: validator")
+	assert.False(t, recorded)
+
+	expected := models.DNSTopDomains{
+		Since:        now,
+		TotalQueried: 4,
+		TotalBlocked: 1,
+		TopQueried: []models.DNSDomainCount{
+			{Domain: "b.com", Count: 2},
+			{Domain: "a.com", Count: 1},
+		},
+		TopBlocked: []models.DNSDomainCount{
+			{Domain: "ads.com", Count: 1},
+		},
+	}
+	assert.Equal(t, expected, loop.GetTopDomains(2))
+
+	now = time.Unix(2, 0)
+	loop.ResetTopDomains()
+	expected = models.DNSTopDomains{
+		Since:      now,
+		TopQueried: []models.DNSDomainCount{},
+		TopBlocked: []models.DNSDomainCount{},
+	}
+	assert.Equal(t, expected, loop.GetTopDomains(2))
+}
+
+func Test_incrementBounded(t *testing.T) {
+	t.Parallel()
+
+	counts := make(map[string]uint64, maxStatsDomains)
+	for i := 0; i < maxStatsDomains; i++ {
+		domain := fmt.Sprintf("%d.com", i)
+		counts[domain] = 1
+	}
+	counts["0.com"] = 5
+
+	incrementBounded(counts, "new.com")
+
+	assert.Len(t, counts, maxStatsDomains/2+1)
+	assert.Equal(t, uint64(5), counts["0.com"])
+	assert.Equal(t, uint64(1), counts["new.com"])
+}
@@ -1,13 +1,26 @@
 package dns
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 func (l *Loop) updateFiles(ctx context.Context) (err error) {
 	l.logger.Info("downloading DNS over TLS cryptographic files")
 	if err := l.conf.SetupFiles(ctx); err != nil {
 		return err
 	}
+	return l.makeUnboundConf(ctx)
+}
+
+// makeUnboundConf builds the hostnames and IP addresses blacklist
+// and writes the Unbound configuration file.
+func (l *Loop) makeUnboundConf(ctx context.Context) (err error) {
+	l.confMutex.Lock()
+	defer l.confMutex.Unlock()
+
 	settings := l.GetSettings()
+	settings.DoT.Unbound.Providers = l.getActiveUpstreams(settings.DoT.Unbound.Providers)
 
 	unboundSettings, err := settings.DoT.Unbound.ToUnboundFormat()
 	if err != nil {
@@ -20,6 +33,14 @@ func (l *Loop) updateFiles(ctx context.Context) (err error) {
 		return err
 	}
 
+	sourcesHostnames, errs := getBlocklistsHostnames(ctx, l.client,
+		settings.DoT.Blacklist.BlocklistSources)
+	for _, err := range errs {
+		l.logger.Warn(err.Error())
+	}
+	blacklistSettings.AddBlockedHosts = append(blacklistSettings.AddBlockedHosts,
+		sourcesHostnames...)
+
 	blockedHostnames, blockedIPs, blockedIPPrefixes, errs :=
 		l.blockBuilder.All(ctx, blacklistSettings)
 	for _, err := range errs {
@@ -31,5 +52,18 @@ func (l *Loop) updateFiles(ctx context.Context) (err error) {
 	unboundSettings.Blacklist.IPs = blockedIPs
 	unboundSettings.Blacklist.IPPrefixes = blockedIPPrefixes
 
-	return l.conf.MakeUnboundConf(unboundSettings)
+	err = l.conf.MakeUnboundConf(unboundSettings)
+	if err != nil {
+		return err
+	}
+
+	var privateDomains []string
+	if *settings.DoT.Blacklist.RebindingProtection {
+		privateDomains = settings.DoT.Blacklist.RebindingAllowedHosts
+	}
+	err = writeIncludeConf(l.fileWriter, l.unboundDir, privateDomains)
+	if err != nil {
+		return fmt.Errorf("writing Unbound include configuration: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,204 @@
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/qdm12/dns/pkg/provider"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// upstreamHealth is the result of the last health check of a DoT upstream.
+type upstreamHealth struct {
+	healthy   bool
+	checkedAt time.Time
+	err       error
+}
+
+// GetUpstreams returns the health state of each DoT upstream
+// provider, in the order the providers are configured.
+func (l *Loop) GetUpstreams() (upstreams []models.DNSUpstream) {
+	providers := l.GetSettings().DoT.Unbound.Providers
+
+	l.upstreamsMutex.RLock()
+	defer l.upstreamsMutex.RUnlock()
+
+	active := make(map[string]struct{}, len(providers))
+	for _, name := range activeUpstreams(providers, l.upstreams) {
+		active[name] = struct{}{}
+	}
+
+	upstreams = make([]models.DNSUpstream, len(providers))
+	for i, name := range providers {
+		health, checked := l.upstreams[name]
+		_, isActive := active[name]
+		upstreams[i] = models.DNSUpstream{
+			Provider:  name,
+			Healthy:   !checked || health.healthy,
+			Active:    isActive,
+			CheckedAt: health.checkedAt,
+		}
+		if health.err != nil {
+			upstreams[i].Error = health.err.Error()
+		}
+	}
+	return upstreams
+}
+
+// RunUpstreamsCheck health checks the DoT upstream providers periodically
+// if more than one provider is set. Unhealthy upstreams are removed from
+// the Unbound configuration until they recover, and Unbound is reloaded
+// each time the upstreams used change.
+func (l *Loop) RunUpstreamsCheck(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	period := *l.GetSettings().DoT.UpstreamCheckPeriod
+	if period == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := l.checkUpstreams(ctx)
+			if err != nil && ctx.Err() == nil {
+				l.logger.Error("checking upstreams: " + err.Error())
+			}
+		}
+	}
+}
+
+func (l *Loop) checkUpstreams(ctx context.Context) (err error) {
+	settings := l.GetSettings()
+	providers := settings.DoT.Unbound.Providers
+	if !*settings.DoT.Enabled || len(providers) < 2 ||
+		l.GetStatus() != constants.Running {
+		return nil
+	}
+
+	results := make(map[string]upstreamHealth, len(providers))
+	for _, name := range providers {
+		err := checkUpstream(ctx, name)
+		if ctx.Err() != nil {
+			return nil
+		}
+		results[name] = upstreamHealth{
+			healthy:   err == nil,
+			checkedAt: l.timeNow(),
+			err:       err,
+		}
+	}
+
+	l.upstreamsMutex.Lock()
+	previous := activeUpstreams(providers, l.upstreams)
+	for name, health := range results {
+		old, checked := l.upstreams[name]
+		wasHealthy := !checked || old.healthy
+		switch {
+		case wasHealthy && !health.healthy:
+			l.logger.Warn("upstream " + name + " is unhealthy: " + health.err.Error())
+		case !wasHealthy && health.healthy:
+			l.logger.Info("upstream " + name + " is healthy again")
+		}
+		l.upstreams[name] = health
+	}
+	current := activeUpstreams(providers, l.upstreams)
+	l.upstreamsMutex.Unlock()
+
+	if equalStrings(previous, current) {
+		return nil
+	}
+
+	l.logger.Info("using upstreams " + strings.Join(current, ", "))
+	err = l.makeUnboundConf(ctx)
+	if err != nil {
+		return fmt.Errorf("making Unbound configuration: %w", err)
+	}
+	return l.reloader.Reload()
+}
+
+// getActiveUpstreams returns the upstream providers to use
+// in the Unbound configuration.
+func (l *Loop) getActiveUpstreams(providers []string) (active []string) {
+	l.upstreamsMutex.RLock()
+	defer l.upstreamsMutex.RUnlock()
+	return activeUpstreams(providers, l.upstreams)
+}
+
+// activeUpstreams returns the providers given which are healthy or
+// not checked yet, keeping their order. If no provider is healthy,
+// all the providers are returned since there is nothing to fail over to.
+func activeUpstreams(providers []string,
+	health map[string]upstreamHealth) (active []string) {
+	active = make([]string, 0, len(providers))
+	for _, name := range providers {
+		upstream, checked := health[name]
+		if !checked || upstream.healthy {
+			active = append(active, name)
+		}
+	}
+	if len(active) == 0 {
+		return providers
+	}
+	return active
+}
+
+var ErrUpstreamNoIPv4 = errors.New("upstream has no IPv4 address")
+
+// checkUpstream resolves the DoT server name of the provider given
+// using DNS over TLS with the first IPv4 address of the provider.
+func checkUpstream(ctx context.Context, providerName string) (err error) {
+	p, err := provider.Parse(providerName)
+	if err != nil {
+		return err
+	}
+
+	dot := p.DoT()
+	if len(dot.IPv4) == 0 {
+		return fmt.Errorf("%w", ErrUpstreamNoIPv4)
+	}
+	address := net.JoinHostPort(dot.IPv4[0].String(), fmt.Sprint(dot.Port))
+
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName: dot.Name,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		// The TLS connection is not a packet connection
+		// so the resolver uses the TCP DNS message format.
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", address)
+		},
+	}
+
+	const timeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err = resolver.LookupNetIP(ctx, "ip4", dot.Name)
+	return err
+}
+
+func equalStrings(a, b []string) (equal bool) {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_activeUpstreams(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	providers := []string{"cloudflare", "google", "quad9"}
+
+	testCases := map[string]struct {
+		health map[string]upstreamHealth
+		active []string
+	}{
+		"not checked": {
+			active: []string{"cloudflare", "google", "quad9"},
+		},
+		"one unhealthy": {
+			health: map[string]upstreamHealth{
+				"cloudflare": {err: errTest},
+				"google":     {healthy: true},
+			},
+			active: []string{"google", "quad9"},
+		},
+		"all unhealthy": {
+			health: map[string]upstreamHealth{
+				"cloudflare": {err: errTest},
+				"google":     {err: errTest},
+				"quad9":      {err: errTest},
+			},
+			active: []string{"cloudflare", "google", "quad9"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			active := activeUpstreams(providers, testCase.health)
+
+			assert.Equal(t, testCase.active, active)
+		})
+	}
+}
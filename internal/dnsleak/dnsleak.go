@@ -0,0 +1,181 @@
+// Package dnsleak runs DNS leak tests, resolving unique
+// subdomains of a leak test service through the system
+// resolver and reporting which resolvers answered.
+package dnsleak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrAlreadyRunning      = errors.New("DNS leak test is already running")
+	ErrHTTPStatusCodeNotOK = errors.New("HTTP status code is not OK")
+	ErrTestIDEmpty         = errors.New("test ID is empty")
+)
+
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+type Tester struct {
+	client   *http.Client
+	resolver Resolver
+	// baseURL is the leak test service URL, and
+	// domain is its domain the unique subdomains
+	// to resolve are under.
+	baseURL string
+	domain  string
+	running sync.Mutex
+}
+
+// New creates a DNS leak tester using the bash.ws service
+// and the system resolver, which is the resolver configured
+// in the container.
+func New(client *http.Client) *Tester {
+	return &Tester{
+		client:   client,
+		resolver: net.DefaultResolver,
+		baseURL:  "https://bash.ws",
+		domain:   "bash.ws",
+	}
+}
+
+// Run runs a DNS leak test and returns an error
+// if a DNS leak test is already running.
+func (t *Tester) Run(ctx context.Context) (result models.DNSLeakTestResult, err error) {
+	if !t.running.TryLock() {
+		return result, fmt.Errorf("%w", ErrAlreadyRunning)
+	}
+	defer t.running.Unlock()
+
+	body, err := t.get(ctx, t.baseURL+"/id")
+	if err != nil {
+		return result, fmt.Errorf("getting test ID: %w", err)
+	}
+	id := strings.TrimSpace(string(body))
+	if id == "" {
+		return result, fmt.Errorf("%w", ErrTestIDEmpty)
+	}
+
+	t.resolveSubdomains(ctx, id)
+
+	body, err = t.get(ctx, t.baseURL+"/dnsleak/test/"+id+"?json")
+	if err != nil {
+		return result, fmt.Errorf("getting test results: %w", err)
+	}
+
+	var entries []entry
+	err = json.Unmarshal(body, &entries)
+	if err != nil {
+		return result, fmt.Errorf("decoding test results: %w", err)
+	}
+
+	return entriesToResult(entries), nil
+}
+
+// resolveSubdomains resolves unique subdomains of the test ID such that
+// the leak test service records the resolvers asking for them.
+// Resolution errors are expected and ignored.
+func (t *Tester) resolveSubdomains(ctx context.Context, id string) {
+	const queries = 10
+	const queryTimeout = 3 * time.Second
+	var wg sync.WaitGroup
+	for i := 1; i <= queries; i++ {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+			defer cancel()
+			_, _ = t.resolver.LookupHost(ctx, host)
+		}(fmt.Sprintf("%d.%s.%s", i, id, t.domain))
+	}
+	wg.Wait()
+}
+
+func (t *Tester) get(ctx context.Context, url string) (body []byte, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d %s", ErrHTTPStatusCodeNotOK,
+			response.StatusCode, response.Status)
+	}
+
+	body, err = io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	return body, nil
+}
+
+type entry struct {
+	IP          string `json:"ip"`
+	CountryName string `json:"country_name"`
+	ASN         string `json:"asn"`
+	Type        string `json:"type"`
+}
+
+// entriesToResult converts the leak test service entries to a result,
+// flagging resolvers not operated by the network of the public IP
+// address, which is the VPN server network when the VPN is up.
+func entriesToResult(entries []entry) (result models.DNSLeakTestResult) {
+	result.Resolvers = make([]models.DNSLeakTestIP, 0, len(entries))
+	for _, entry := range entries {
+		switch entry.Type {
+		case "ip":
+			ip, err := netip.ParseAddr(entry.IP)
+			if err != nil {
+				continue
+			}
+			result.PublicIP = &models.DNSLeakTestIP{
+				IP:      ip,
+				Country: entry.CountryName,
+				ASN:     entry.ASN,
+			}
+		case "dns":
+			ip, err := netip.ParseAddr(entry.IP)
+			if err != nil {
+				continue
+			}
+			result.Resolvers = append(result.Resolvers, models.DNSLeakTestIP{
+				IP:      ip,
+				Country: entry.CountryName,
+				ASN:     entry.ASN,
+			})
+		case "conclusion":
+			result.Conclusion = entry.IP
+		}
+	}
+
+	if result.PublicIP == nil {
+		return result
+	}
+
+	for i, resolver := range result.Resolvers {
+		if resolver.ASN != result.PublicIP.ASN {
+			result.Resolvers[i].NonVPN = true
+			result.Leaking = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package dnsleak
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	hosts []string
+	mutex sync.Mutex
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) (
+	addrs []string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.hosts = append(r.hosts, host)
+	return nil, nil
+}
+
+func Test_Tester_Run(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/id":
+			_, _ = w.Write([]byte("1234\n"))
+		case "/dnsleak/test/1234":
+			_, _ = w.Write([]byte(`[
+{"ip":"1.2.3.4","country_name":"Sweden","asn":"AS1 VPN","type":"ip"},
+{"ip":"1.2.3.5","country_name":"Sweden","asn":"AS1 VPN","type":"dns"},
+{"ip":"5.6.7.8","country_name":"France","asn":"AS2 ISP","type":"dns"},
+{"ip":"DNS may be leaking.","type":"conclusion"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	resolver := &fakeResolver{}
+	tester := &Tester{
+		client:   server.Client(),
+		resolver: resolver,
+		baseURL:  server.URL,
+		domain:   "example.com",
+	}
+
+	result, err := tester.Run(context.Background())
+	require.NoError(t, err)
+
+	expected := models.DNSLeakTestResult{
+		PublicIP: &models.DNSLeakTestIP{
+			IP:      netip.AddrFrom4([4]byte{1, 2, 3, 4}),
+			Country: "Sweden",
+			ASN:     "AS1 VPN",
+		},
+		Resolvers: []models.DNSLeakTestIP{
+			{IP: netip.AddrFrom4([4]byte{1, 2, 3, 5}), Country: "Sweden", ASN: "AS1 VPN"},
+			{IP: netip.AddrFrom4([4]byte{5, 6, 7, 8}), Country: "France", ASN: "AS2 ISP", NonVPN: true},
+		},
+		Leaking:    true,
+		Conclusion: "DNS may be leaking.",
+	}
+	assert.Equal(t, expected, result)
+
+	sort.Strings(resolver.hosts)
+	require.Len(t, resolver.hosts, 10)
+	assert.Equal(t, "1.1234.example.com", resolver.hosts[0])
+	assert.Equal(t, "10.1234.example.com", resolver.hosts[1])
+}
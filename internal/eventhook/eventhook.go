@@ -0,0 +1,100 @@
+// Package eventhook runs commands on events such as the
+// VPN connecting or a port being forwarded, with the event
+// details set in environment variables.
+package eventhook
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/hook"
+	"github.com/qdm12/golibs/command"
+)
+
+// Runner runs the event hook commands. Its Trigger method
+// is safe for concurrent use and does not block.
+type Runner struct {
+	// Fixed parameters
+	settings settings.EventHooks
+	// Objects
+	cmder  command.Runner
+	logger Logger
+	// Internal state
+	events chan event
+}
+
+type event struct {
+	eventType string
+	env       []string
+}
+
+func New(settings settings.EventHooks, cmder command.Runner,
+	logger Logger) *Runner {
+	const eventsBufferSize = 16
+	return &Runner{
+		settings: settings,
+		cmder:    cmder,
+		logger:   logger,
+		events:   make(chan event, eventsBufferSize),
+	}
+}
+
+// Trigger queues the command set for the event type given, if any.
+// Each detail is set as an environment variable for the command,
+// with its key uppercased and prefixed with the hook environment
+// variables prefix, in addition to the VPN_EVENT environment
+// variable set to the event type.
+func (r *Runner) Trigger(eventType string, details map[string]string) {
+	if r.settings.Commands[eventType] == "" {
+		return
+	}
+
+	env := make([]string, 0, len(details)+1)
+	env = append(env, hook.EnvPrefix+"EVENT="+eventType)
+	for key, value := range details {
+		env = append(env, hook.EnvPrefix+strings.ToUpper(key)+"="+value)
+	}
+	sort.Strings(env[1:])
+
+	select {
+	case r.events <- event{eventType: eventType, env: env}:
+	default:
+		r.logger.Warn("event hook dropped: too many event hooks pending: " + eventType)
+	}
+}
+
+// Run runs the commands of the events triggered, with at most the
+// maximum number of concurrent commands set, until the context is
+// canceled.
+func (r *Runner) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	var wg sync.WaitGroup
+	for i := 0; i < int(r.settings.MaxConcurrent); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case e := <-r.events:
+					r.run(ctx, e)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *Runner) run(ctx context.Context, e event) {
+	argv := strings.Fields(r.settings.Commands[e.eventType])
+	err := hook.Run(ctx, r.cmder, r.logger, e.eventType+" event hook",
+		argv, e.env, r.settings.Timeout)
+	if err != nil {
+		r.logger.Error(e.eventType + " event hook failed: " + err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package eventhook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/golibs/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_Runner_Trigger(t *testing.T) {
+	t.Parallel()
+
+	runner := New(settings.EventHooks{
+		Commands: map[string]string{"connected": "true"},
+	}, command.NewCmder(), noopLogger{})
+
+	runner.Trigger("disconnected", nil)
+	assert.Empty(t, runner.events)
+
+	runner.Trigger("connected", map[string]string{
+		"server_name": "server",
+		"provider":    "mullvad",
+	})
+	expected := event{
+		eventType: "connected",
+		env: []string{
+			"VPN_EVENT=connected",
+			"VPN_PROVIDER=mullvad",
+			"VPN_SERVER_NAME=server",
+		},
+	}
+	assert.Equal(t, expected, <-runner.events)
+}
+
+func Test_Runner_Run(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	outputPath := filepath.Join(directory, "output")
+	scriptPath := filepath.Join(directory, "hook.sh")
+	const script = "#!/bin/sh\n" +
+		`echo "$1 $VPN_EVENT $VPN_PORT_FORWARDED" > "$2"` + "\n"
+	err := os.WriteFile(scriptPath, []byte(script), 0700) //nolint:gosec
+	require.NoError(t, err)
+
+	runner := New(settings.EventHooks{
+		Commands: map[string]string{
+			"port_forwarded": scriptPath + " argument " + outputPath,
+		},
+		Timeout:       time.Second,
+		MaxConcurrent: 1,
+	}, command.NewCmder(), noopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go runner.Run(ctx, done)
+
+	runner.Trigger("port_forwarded", map[string]string{"port_forwarded": "5000"})
+
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(outputPath)
+		return err == nil && string(data) == "argument port_forwarded 5000\n"
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
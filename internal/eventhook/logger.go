@@ -0,0 +1,7 @@
+package eventhook
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+}
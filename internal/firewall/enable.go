@@ -30,6 +30,10 @@ func (c *Config) SetEnabled(ctx context.Context, enabled bool) (err error) {
 
 	c.logger.Info("enabling...")
 
+	if err := c.saveHostRules(ctx); err != nil {
+		return fmt.Errorf("saving host rules: %w", err)
+	}
+
 	if err := c.enable(ctx); err != nil {
 		return fmt.Errorf("enabling firewall: %w", err)
 	}
@@ -49,6 +53,9 @@ func (c *Config) disable(ctx context.Context) (err error) {
 	if err = c.setIPv6AllPolicies(ctx, "ACCEPT"); err != nil {
 		return fmt.Errorf("setting ipv6 policies: %w", err)
 	}
+	if err = c.restoreSavedHostRules(ctx); err != nil {
+		return fmt.Errorf("restoring host rules: %w", err)
+	}
 	return nil
 }
 
@@ -93,21 +100,51 @@ func (c *Config) enable(ctx context.Context) (err error) {
 		return err
 	}
 
-	if err = c.allowVPNIP(ctx); err != nil {
-		return err
+	if !c.paused {
+		if err = c.allowVPNIP(ctx); err != nil {
+			return err
+		}
+
+		if c.secondaryTunnel.intf != "" {
+			if err = c.allowSecondaryTunnel(ctx, remove); err != nil {
+				return err
+			}
+		}
+
+		if err = c.allowLANGatewayForward(ctx, remove); err != nil {
+			return err
+		}
 	}
 
-	for _, network := range c.localNetworks {
-		if err := c.acceptOutputFromIPToSubnet(ctx, network.InterfaceName, network.IP, network.IPNet, remove); err != nil {
+	if c.wireguardServer.intf != "" {
+		if err = c.allowWireguardServer(ctx, remove); err != nil {
 			return err
 		}
+	}
+
+	allowLAN := !c.paused || c.pauseAllowLAN
+	for _, network := range c.localNetworks {
+		if allowLAN {
+			err := c.acceptOutputFromIPToSubnet(ctx, network.InterfaceName, network.IP, network.IPNet, remove)
+			if err != nil {
+				return err
+			}
+		}
 		if err = c.acceptIpv6MulticastOutput(ctx, network.InterfaceName, remove); err != nil {
 			return err
 		}
 	}
 
-	if err = c.allowOutboundSubnets(ctx); err != nil {
-		return err
+	if allowLAN {
+		if err = c.allowOutboundSubnets(ctx); err != nil {
+			return err
+		}
+	}
+
+	if allowLAN && c.multicastDiscovery {
+		if err = c.acceptMulticastDiscovery(ctx, remove); err != nil {
+			return err
+		}
 	}
 
 	// Allows packets from any IP address to go through eth0 / local network
@@ -122,6 +159,10 @@ func (c *Config) enable(ctx context.Context) (err error) {
 		return err
 	}
 
+	if err = c.allowForwardedPortDNAT(ctx, remove); err != nil {
+		return err
+	}
+
 	if err := c.runUserPostRules(ctx, c.customRulesPath, remove); err != nil {
 		return fmt.Errorf("running user defined post firewall rules: %w", err)
 	}
@@ -130,7 +171,7 @@ func (c *Config) enable(ctx context.Context) (err error) {
 }
 
 func (c *Config) allowVPNIP(ctx context.Context) (err error) {
-	if !c.vpnConnection.IP.IsValid() {
+	if !vpnConnectionIsSet(c.vpnConnection) {
 		return nil
 	}
 
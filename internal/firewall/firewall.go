@@ -27,9 +27,23 @@ type Config struct { //nolint:maligned
 	enabled           bool
 	vpnConnection     models.Connection
 	vpnIntf           string
+	secondaryTunnel   secondaryTunnel
+	wireguardServer   wireguardServer
+	forwardedPortDNAT forwardedPortDNAT
+	lanGatewaySubnets []netip.Prefix
 	outboundSubnets   []netip.Prefix
 	allowedInputPorts map[uint16]map[string]struct{} // port to interfaces set mapping
-	stateMutex        sync.Mutex
+	paused            bool
+	pauseAllowLAN     bool
+	// multicastDiscovery is true if multicast discovery
+	// protocols are allowed to and from local networks.
+	multicastDiscovery bool
+	// restoreHostRules is true if the rules present before
+	// the firewall is first enabled are restored when it is
+	// disabled, and hostRules holds these saved rules.
+	restoreHostRules bool
+	hostRules        hostRules
+	stateMutex       sync.Mutex
 }
 
 // NewConfig creates a new Config instance and returns an error
@@ -37,7 +51,7 @@ type Config struct { //nolint:maligned
 func NewConfig(ctx context.Context, logger Logger,
 	runner command.Runner, defaultRoutes []routing.DefaultRoute,
 	localNetworks []routing.LocalNetwork) (config *Config, err error) {
-	iptables, err := checkIptablesSupport(ctx, runner, "iptables", "iptables-nft")
+	iptables, err := CheckIptablesSupport(ctx, runner)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,91 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SetRestoreHostRules sets whether the iptables rules present before
+// the firewall is first enabled are saved and restored every time the
+// firewall is disabled, instead of leaving all chains flushed with
+// accepting policies. This is useful when running directly on a host
+// which has its own firewall rules.
+func (c *Config) SetRestoreHostRules(restore bool) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.restoreHostRules = restore
+}
+
+// saveHostRules saves the current iptables and ip6tables rules, if
+// restoring host rules is enabled and they were not saved already.
+func (c *Config) saveHostRules(ctx context.Context) (err error) {
+	if !c.restoreHostRules || c.hostRules.saved {
+		return nil
+	}
+
+	c.hostRules.ipv4, err = c.runSave(ctx, c.ipTables)
+	if err != nil {
+		return fmt.Errorf("saving IPv4 rules: %w", err)
+	}
+
+	if c.ip6Tables != "" {
+		c.hostRules.ipv6, err = c.runSave(ctx, c.ip6Tables)
+		if err != nil {
+			return fmt.Errorf("saving IPv6 rules: %w", err)
+		}
+	}
+
+	c.hostRules.saved = true
+	return nil
+}
+
+// restoreSavedHostRules restores the iptables and ip6tables rules
+// saved before the firewall was first enabled, if any.
+func (c *Config) restoreSavedHostRules(ctx context.Context) (err error) {
+	if !c.hostRules.saved {
+		return nil
+	}
+
+	err = c.runRestore(ctx, c.ipTables, c.hostRules.ipv4)
+	if err != nil {
+		return fmt.Errorf("restoring IPv4 rules: %w", err)
+	}
+
+	if c.ip6Tables != "" {
+		err = c.runRestore(ctx, c.ip6Tables, c.hostRules.ipv6)
+		if err != nil {
+			return fmt.Errorf("restoring IPv6 rules: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type hostRules struct {
+	saved bool
+	ipv4  string
+	ipv6  string
+}
+
+func (c *Config) runSave(ctx context.Context, iptables string) (rules string, err error) {
+	cmd := exec.CommandContext(ctx, iptables+"-save") // #nosec G204
+	rules, err = c.runner.Run(cmd)
+	if err != nil {
+		return "", fmt.Errorf("command failed: \"%s\": %s: %w",
+			cmd.String(), rules, err)
+	}
+	return rules, nil
+}
+
+func (c *Config) runRestore(ctx context.Context, iptables, rules string) (err error) {
+	cmd := exec.CommandContext(ctx, iptables+"-restore") // #nosec G204
+	cmd.Stdin = strings.NewReader(rules + "\n")
+	output, err := c.runner.Run(cmd)
+	if err != nil {
+		return fmt.Errorf("command failed: \"%s\": %s: %w",
+			cmd.String(), output, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Config_hostRules(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errDummy := errors.New("exit code 1")
+
+	testCases := map[string]struct {
+		restoreHostRules bool
+		ip6Tables        string
+		buildRunner      func(ctrl *gomock.Controller) *MockRunner
+		saveErrMessage   string
+		restoreRules     map[string]string
+	}{
+		"restore host rules disabled": {
+			buildRunner: func(ctrl *gomock.Controller) *MockRunner {
+				return NewMockRunner(ctrl)
+			},
+		},
+		"IPv4 only": {
+			restoreHostRules: true,
+			buildRunner: func(ctrl *gomock.Controller) *MockRunner {
+				runner := NewMockRunner(ctrl)
+				runner.EXPECT().Run(newCmdMatcher("iptables-test-save")).
+					Return("*filter\nCOMMIT", nil)
+				return runner
+			},
+			restoreRules: map[string]string{
+				"iptables-test-restore": "*filter\nCOMMIT\n",
+			},
+		},
+		"IPv4 and IPv6": {
+			restoreHostRules: true,
+			ip6Tables:        "ip6tables-test",
+			buildRunner: func(ctrl *gomock.Controller) *MockRunner {
+				runner := NewMockRunner(ctrl)
+				runner.EXPECT().Run(newCmdMatcher("iptables-test-save")).
+					Return("*filter\n-A INPUT -j ACCEPT\nCOMMIT", nil)
+				runner.EXPECT().Run(newCmdMatcher("ip6tables-test-save")).
+					Return("*filter\nCOMMIT", nil)
+				return runner
+			},
+			restoreRules: map[string]string{
+				"iptables-test-restore":  "*filter\n-A INPUT -j ACCEPT\nCOMMIT\n",
+				"ip6tables-test-restore": "*filter\nCOMMIT\n",
+			},
+		},
+		"save error": {
+			restoreHostRules: true,
+			buildRunner: func(ctrl *gomock.Controller) *MockRunner {
+				runner := NewMockRunner(ctrl)
+				runner.EXPECT().Run(newCmdMatcher("iptables-test-save")).
+					Return("permission denied", errDummy)
+				return runner
+			},
+			saveErrMessage: "saving IPv4 rules: command failed: " +
+				"\"iptables-test-save\": permission denied: exit code 1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			runner := testCase.buildRunner(ctrl)
+			config := &Config{
+				runner:    runner,
+				ipTables:  "iptables-test",
+				ip6Tables: testCase.ip6Tables,
+			}
+			config.SetRestoreHostRules(testCase.restoreHostRules)
+
+			err := config.saveHostRules(ctx)
+			if testCase.saveErrMessage != "" {
+				assert.EqualError(t, err, testCase.saveErrMessage)
+				return
+			}
+			require.NoError(t, err)
+
+			// Saving again is a no-op once the rules are saved.
+			err = config.saveHostRules(ctx)
+			require.NoError(t, err)
+
+			restoredRules := make(map[string]string, len(testCase.restoreRules))
+			runner.EXPECT().Run(gomock.Any()).
+				Times(len(testCase.restoreRules)).
+				DoAndReturn(func(cmd *exec.Cmd) (string, error) {
+					data, err := io.ReadAll(cmd.Stdin)
+					require.NoError(t, err)
+					restoredRules[cmd.Path] = string(data)
+					return "", nil
+				})
+
+			err = config.restoreSavedHostRules(ctx)
+			require.NoError(t, err)
+			if len(testCase.restoreRules) == 0 {
+				assert.Empty(t, restoredRules)
+				return
+			}
+			assert.Equal(t, testCase.restoreRules, restoredRules)
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/command"
 )
@@ -142,9 +144,20 @@ func (c *Config) acceptEstablishedRelatedTraffic(ctx context.Context, remove boo
 
 func (c *Config) acceptOutputTrafficToVPN(ctx context.Context,
 	defaultInterface string, connection models.Connection, remove bool) error {
+	if connection.Type == vpn.Tailscale {
+		return c.acceptOutputFromTailscale(ctx, defaultInterface, remove)
+	} else if connection.Type == vpn.Tor {
+		return c.acceptOutputFromTor(ctx, defaultInterface, remove)
+	}
+
 	instruction := fmt.Sprintf("%s OUTPUT -d %s -o %s -p %s -m %s --dport %d -j ACCEPT",
 		appendOrDelete(remove), connection.IP, defaultInterface, connection.Protocol,
 		connection.Protocol, connection.Port)
+	if connection.Port == 0 {
+		// the connection goes through a proxy with ports not known in advance
+		instruction = fmt.Sprintf("%s OUTPUT -d %s -o %s -j ACCEPT",
+			appendOrDelete(remove), connection.IP, defaultInterface)
+	}
 	if connection.IP.Is4() {
 		return c.runIptablesInstruction(ctx, instruction)
 	} else if c.ip6Tables == "" {
@@ -153,6 +166,26 @@ func (c *Config) acceptOutputTrafficToVPN(ctx context.Context,
 	return c.runIP6tablesInstruction(ctx, instruction)
 }
 
+// acceptOutputFromTailscale accepts output traffic from the Tailscale
+// daemon, which marks its packets sent outside of the tunnel with
+// its bypass mark, since its peers and relays are not known in advance.
+// Only packets from processes running with the Tailscale daemon group
+// are accepted, so other processes cannot bypass the tunnel using the mark.
+func (c *Config) acceptOutputFromTailscale(ctx context.Context,
+	defaultInterface string, remove bool) error {
+	const tailscaleBypassMark = "0x80000/0xff0000"
+	instruction := fmt.Sprintf("%s OUTPUT -o %s -m owner --gid-owner %d -m mark --mark %s -j ACCEPT",
+		appendOrDelete(remove), defaultInterface, constants.TailscaleGroupID, tailscaleBypassMark)
+	err := c.runIptablesInstruction(ctx, instruction)
+	if err != nil {
+		return err
+	}
+	if c.ip6Tables == "" {
+		return nil
+	}
+	return c.runIP6tablesInstruction(ctx, instruction)
+}
+
 // Thanks to @npawelek.
 func (c *Config) acceptOutputFromIPToSubnet(ctx context.Context,
 	intf string, sourceIP netip.Addr, destinationSubnet netip.Prefix, remove bool) error {
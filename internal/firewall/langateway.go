@@ -0,0 +1,83 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+)
+
+// SetLANGateway forwards traffic from the local subnets given through
+// the VPN interface only, masquerading it so replies come back through
+// gluetun. This allows devices on the local network to use gluetun as
+// their default gateway. Rules for previously set subnets are removed.
+func (c *Config) SetLANGateway(ctx context.Context, subnets []netip.Prefix) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if len(c.lanGatewaySubnets) > 0 {
+		const remove = true
+		if c.enabled && !c.paused {
+			err = c.allowLANGatewayForward(ctx, remove)
+			if err != nil {
+				c.logger.Error("cannot remove outdated LAN gateway forwarding rule: " + err.Error())
+			}
+		}
+		err = c.masqueradeLANGateway(ctx, remove)
+		if err != nil {
+			c.logger.Error("cannot remove outdated LAN gateway masquerading rule: " + err.Error())
+		}
+	}
+
+	c.lanGatewaySubnets = make([]netip.Prefix, len(subnets))
+	for i, subnet := range subnets {
+		c.lanGatewaySubnets[i] = subnet.Masked()
+	}
+
+	const remove = false
+	err = c.masqueradeLANGateway(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("masquerading LAN gateway traffic: %w", err)
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal LAN gateway state")
+		return nil
+	} else if c.paused {
+		return nil
+	}
+
+	c.logger.Info("allowing LAN gateway traffic...")
+	err = c.allowLANGatewayForward(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("allowing LAN gateway forwarding: %w", err)
+	}
+
+	return nil
+}
+
+// allowLANGatewayForward accepts traffic forwarded from the LAN gateway
+// subnets through the VPN interface, if it is set.
+func (c *Config) allowLANGatewayForward(ctx context.Context, remove bool) (err error) {
+	if c.vpnIntf == "" {
+		return nil
+	}
+	for _, subnet := range c.lanGatewaySubnets {
+		err = c.acceptForwardFromSubnet(ctx, c.vpnIntf, subnet, remove)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// masqueradeLANGateway masquerades packets from the LAN gateway subnets
+// going outside these subnets. These rules are not in the filter table
+// and are not affected by the firewall being enabled or disabled.
+func (c *Config) masqueradeLANGateway(ctx context.Context, remove bool) (err error) {
+	instructions := make([]string, len(c.lanGatewaySubnets))
+	for i, subnet := range c.lanGatewaySubnets {
+		instructions[i] = fmt.Sprintf("-t nat %s POSTROUTING -s %s ! -d %s -j MASQUERADE",
+			appendOrDelete(remove), subnet, subnet)
+	}
+	return c.runIptablesInstructions(ctx, instructions)
+}
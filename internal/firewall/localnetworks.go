@@ -0,0 +1,49 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/routing"
+)
+
+// AddLocalNetworks adds local networks appearing after the firewall
+// creation, such as when the container is connected to another
+// Docker network, and allows traffic to and from them.
+func (c *Config) AddLocalNetworks(ctx context.Context,
+	networks []routing.LocalNetwork) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if !c.enabled {
+		c.localNetworks = append(c.localNetworks, networks...)
+		return nil
+	}
+
+	const remove = false
+	allowLAN := !c.paused || c.pauseAllowLAN
+	for _, network := range networks {
+		if allowLAN {
+			err = c.acceptOutputFromIPToSubnet(ctx, network.InterfaceName,
+				network.IP, network.IPNet, remove)
+			if err != nil {
+				return fmt.Errorf("allowing output to local network %s: %w", network.IPNet, err)
+			}
+		}
+
+		err = c.acceptIpv6MulticastOutput(ctx, network.InterfaceName, remove)
+		if err != nil {
+			return fmt.Errorf("allowing IPv6 multicast output on %s: %w",
+				network.InterfaceName, err)
+		}
+
+		err = c.acceptInputToSubnet(ctx, network.InterfaceName, network.IPNet, remove)
+		if err != nil {
+			return fmt.Errorf("allowing input from local network %s: %w", network.IPNet, err)
+		}
+
+		c.localNetworks = append(c.localNetworks, network)
+	}
+
+	return nil
+}
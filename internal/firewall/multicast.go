@@ -0,0 +1,87 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+)
+
+// multicastDiscoveryGroup is a multicast group and UDP port
+// used by a local network discovery protocol.
+type multicastDiscoveryGroup struct {
+	address string
+	port    uint16
+	ipv6    bool
+}
+
+//nolint:gochecknoglobals
+var multicastDiscoveryGroups = []multicastDiscoveryGroup{
+	{address: "224.0.0.251", port: 5353},          // mDNS
+	{address: "ff02::fb", port: 5353, ipv6: true}, // mDNS
+	{address: "239.255.255.250", port: 1900},      // SSDP
+	{address: "ff02::c", port: 1900, ipv6: true},  // SSDP
+	{address: "ff05::c", port: 1900, ipv6: true},  // SSDP site-local
+}
+
+// SetMulticastDiscovery sets whether multicast discovery protocols such
+// as mDNS and SSDP are allowed to and from the local networks, such
+// that services behind the firewall remain discoverable locally.
+func (c *Config) SetMulticastDiscovery(ctx context.Context, enabled bool) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if enabled == c.multicastDiscovery {
+		return nil
+	}
+
+	if !c.enabled || (c.paused && !c.pauseAllowLAN) {
+		c.logger.Info("firewall disabled or paused, only updating multicast discovery internal state")
+		c.multicastDiscovery = enabled
+		return nil
+	}
+
+	remove := !enabled
+	err = c.acceptMulticastDiscovery(ctx, remove)
+	if err != nil && enabled {
+		return fmt.Errorf("allowing multicast discovery: %w", err)
+	} else if err != nil {
+		c.logger.Error("cannot remove multicast discovery rules: " + err.Error())
+	}
+	c.multicastDiscovery = enabled
+	return nil
+}
+
+// acceptMulticastDiscovery accepts multicast discovery traffic
+// to and from each local network interface.
+func (c *Config) acceptMulticastDiscovery(ctx context.Context, remove bool) (err error) {
+	for _, network := range c.localNetworks {
+		for _, group := range multicastDiscoveryGroups {
+			err = c.acceptMulticastGroup(ctx, network.InterfaceName, group, remove)
+			if err != nil {
+				return fmt.Errorf("multicast group %s on interface %s: %w",
+					group.address, network.InterfaceName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) acceptMulticastGroup(ctx context.Context, intf string,
+	group multicastDiscoveryGroup, remove bool) (err error) {
+	instructions := []string{
+		fmt.Sprintf("%s OUTPUT -o %s -d %s -p udp --dport %d -j ACCEPT",
+			appendOrDelete(remove), intf, group.address, group.port),
+		fmt.Sprintf("%s INPUT -i %s -d %s -p udp --dport %d -j ACCEPT",
+			appendOrDelete(remove), intf, group.address, group.port),
+	}
+	for _, instruction := range instructions {
+		if group.ipv6 {
+			err = c.runIP6tablesInstruction(ctx, instruction)
+		} else {
+			err = c.runIptablesInstruction(ctx, instruction)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -12,8 +12,8 @@ func (c *Config) SetOutboundSubnets(ctx context.Context, subnets []netip.Prefix)
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
-	if !c.enabled {
-		c.logger.Info("firewall disabled, only updating allowed subnets internal list")
+	if !c.enabled || (c.paused && !c.pauseAllowLAN) {
+		c.logger.Info("firewall disabled or paused, only updating allowed subnets internal list")
 		c.outboundSubnets = make([]netip.Prefix, len(subnets))
 		copy(c.outboundSubnets, subnets)
 		return nil
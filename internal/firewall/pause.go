@@ -0,0 +1,123 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetPaused sets the firewall in a paused state where traffic to the VPN
+// server and through the VPN interfaces is blocked, as well as outbound
+// traffic to local networks and outbound subnets if allowLAN is false.
+// Input traffic from local networks is still allowed such that the
+// control server can be reached to resume the VPN.
+// Setting paused to false restores the rules removed.
+func (c *Config) SetPaused(ctx context.Context, paused, allowLAN bool) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if paused == c.paused {
+		return nil
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal paused state")
+		c.paused = paused
+		c.pauseAllowLAN = allowLAN
+		return nil
+	}
+
+	if paused {
+		c.logger.Info("pausing...")
+		c.pauseAllowLAN = allowLAN
+		const remove = true
+		_ = c.allowWhenNotPaused(ctx, remove)
+		c.paused = true
+		return nil
+	}
+
+	c.logger.Info("resuming...")
+	const remove = false
+	err = c.allowWhenNotPaused(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("restoring rules: %w", err)
+	}
+	c.paused = false
+	return nil
+}
+
+// allowWhenNotPaused adds or removes the rules which must not be present
+// when the firewall is paused. Errors removing rules are only logged.
+func (c *Config) allowWhenNotPaused(ctx context.Context, remove bool) (err error) {
+	failed := func(err error) bool {
+		if err == nil {
+			return false
+		} else if remove {
+			c.logger.Error("cannot remove rule: " + err.Error())
+			return false
+		}
+		return true
+	}
+
+	if vpnConnectionIsSet(c.vpnConnection) {
+		for _, defaultRoute := range c.defaultRoutes {
+			err = c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, c.vpnConnection, remove)
+			if failed(err) {
+				return fmt.Errorf("accepting output traffic to VPN: %w", err)
+			}
+		}
+	}
+
+	if c.vpnIntf != "" {
+		err = c.acceptOutputThroughInterface(ctx, c.vpnIntf, remove)
+		if failed(err) {
+			return fmt.Errorf("accepting output traffic through interface %s: %w", c.vpnIntf, err)
+		}
+	}
+
+	err = c.allowWireguardServerForward(ctx, remove)
+	if failed(err) {
+		return fmt.Errorf("allowing Wireguard server forwarding: %w", err)
+	}
+
+	err = c.allowLANGatewayForward(ctx, remove)
+	if failed(err) {
+		return fmt.Errorf("allowing LAN gateway forwarding: %w", err)
+	}
+
+	if c.secondaryTunnel.intf != "" {
+		err = c.allowSecondaryTunnel(ctx, remove)
+		if failed(err) {
+			return fmt.Errorf("allowing secondary tunnel: %w", err)
+		}
+	}
+
+	if c.pauseAllowLAN {
+		return nil
+	}
+
+	for _, network := range c.localNetworks {
+		err = c.acceptOutputFromIPToSubnet(ctx, network.InterfaceName, network.IP, network.IPNet, remove)
+		if failed(err) {
+			return err
+		}
+	}
+
+	for _, subnet := range c.outboundSubnets {
+		for _, defaultRoute := range c.defaultRoutes {
+			err = c.acceptOutputFromIPToSubnet(ctx, defaultRoute.NetInterface,
+				defaultRoute.AssignedIP, subnet, remove)
+			if failed(err) {
+				return err
+			}
+		}
+	}
+
+	if c.multicastDiscovery {
+		err = c.acceptMulticastDiscovery(ctx, remove)
+		if failed(err) {
+			return err
+		}
+	}
+
+	return nil
+}
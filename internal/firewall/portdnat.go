@@ -0,0 +1,119 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+)
+
+type forwardedPortDNAT struct {
+	intf        string
+	port        uint16
+	destination netip.AddrPort
+}
+
+// SetForwardedPortDNAT redirects traffic received on the VPN forwarded
+// port through the interface given to the destination given, which is
+// typically another container. Traffic is masqueraded so replies come
+// back through gluetun. Rules for a previously set forwarded port are
+// removed, and setting the port to 0 only removes them.
+func (c *Config) SetForwardedPortDNAT(ctx context.Context, intf string,
+	port uint16, destination netip.AddrPort) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.forwardedPortDNAT.port != 0 {
+		const remove = true
+		if c.enabled {
+			err = c.allowForwardedPortDNAT(ctx, remove)
+			if err != nil {
+				c.logger.Error("cannot remove outdated forwarded port DNAT forward rules: " + err.Error())
+			}
+		}
+		err = c.natForwardedPort(ctx, remove)
+		if err != nil {
+			c.logger.Error("cannot remove outdated forwarded port DNAT rules: " + err.Error())
+		}
+		c.forwardedPortDNAT = forwardedPortDNAT{}
+	}
+
+	if port == 0 {
+		return nil
+	}
+
+	c.forwardedPortDNAT = forwardedPortDNAT{
+		intf:        intf,
+		port:        port,
+		destination: destination,
+	}
+
+	c.logger.Info(fmt.Sprintf("redirecting forwarded port %d to %s...",
+		port, destination))
+
+	const remove = false
+	err = c.natForwardedPort(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("setting forwarded port DNAT rules: %w", err)
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal forwarded port DNAT state")
+		return nil
+	}
+
+	err = c.allowForwardedPortDNAT(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("allowing forwarded port DNAT traffic: %w", err)
+	}
+
+	return nil
+}
+
+// allowForwardedPortDNAT accepts traffic forwarded from the forwarded
+// port interface to the DNAT destination, as well as its replies.
+func (c *Config) allowForwardedPortDNAT(ctx context.Context, remove bool) (err error) {
+	if c.forwardedPortDNAT.port == 0 {
+		return nil
+	}
+	instructions := forwardedPortDNATFilterInstructions(c.forwardedPortDNAT, remove)
+	return c.runIptablesInstructions(ctx, instructions)
+}
+
+// natForwardedPort redirects the forwarded port to the DNAT destination
+// and masquerades the redirected traffic. These rules are not in the filter
+// table and are not affected by the firewall being enabled or disabled.
+func (c *Config) natForwardedPort(ctx context.Context, remove bool) (err error) {
+	instructions := forwardedPortDNATNATInstructions(c.forwardedPortDNAT, remove)
+	return c.runIptablesInstructions(ctx, instructions)
+}
+
+func forwardedPortDNATFilterInstructions(dnat forwardedPortDNAT,
+	remove bool) (instructions []string) {
+	ip, port := dnat.destination.Addr(), dnat.destination.Port()
+	instructions = make([]string, 0, 4) //nolint:gomnd
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		instructions = append(instructions,
+			fmt.Sprintf("%s FORWARD -i %s -d %s -p %s --dport %d -j ACCEPT",
+				appendOrDelete(remove), dnat.intf, ip, protocol, port),
+			fmt.Sprintf("%s FORWARD -o %s -s %s -p %s --sport %d "+
+				"-m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+				appendOrDelete(remove), dnat.intf, ip, protocol, port),
+		)
+	}
+	return instructions
+}
+
+func forwardedPortDNATNATInstructions(dnat forwardedPortDNAT,
+	remove bool) (instructions []string) {
+	ip, port := dnat.destination.Addr(), dnat.destination.Port()
+	instructions = make([]string, 0, 4) //nolint:gomnd
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		instructions = append(instructions,
+			fmt.Sprintf("-t nat %s PREROUTING -i %s -p %s --dport %d -j DNAT --to-destination %s",
+				appendOrDelete(remove), dnat.intf, protocol, dnat.port, dnat.destination),
+			fmt.Sprintf("-t nat %s POSTROUTING -d %s -p %s --dport %d -j MASQUERADE",
+				appendOrDelete(remove), ip, protocol, port),
+		)
+	}
+	return instructions
+}
@@ -0,0 +1,114 @@
+package firewall
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_forwardedPortDNATFilterInstructions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		dnat         forwardedPortDNAT
+		remove       bool
+		instructions []string
+	}{
+		"append": {
+			dnat: forwardedPortDNAT{
+				intf:        "tun0",
+				port:        1000,
+				destination: netip.MustParseAddrPort("172.18.0.2:8080"),
+			},
+			instructions: []string{
+				"--append FORWARD -i tun0 -d 172.18.0.2 -p tcp --dport 8080 -j ACCEPT",
+				"--append FORWARD -o tun0 -s 172.18.0.2 -p tcp --sport 8080 " +
+					"-m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+				"--append FORWARD -i tun0 -d 172.18.0.2 -p udp --dport 8080 -j ACCEPT",
+				"--append FORWARD -o tun0 -s 172.18.0.2 -p udp --sport 8080 " +
+					"-m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			},
+		},
+		"delete": {
+			dnat: forwardedPortDNAT{
+				intf:        "wg0",
+				port:        1000,
+				destination: netip.MustParseAddrPort("172.18.0.2:1000"),
+			},
+			remove: true,
+			instructions: []string{
+				"--delete FORWARD -i wg0 -d 172.18.0.2 -p tcp --dport 1000 -j ACCEPT",
+				"--delete FORWARD -o wg0 -s 172.18.0.2 -p tcp --sport 1000 " +
+					"-m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+				"--delete FORWARD -i wg0 -d 172.18.0.2 -p udp --dport 1000 -j ACCEPT",
+				"--delete FORWARD -o wg0 -s 172.18.0.2 -p udp --sport 1000 " +
+					"-m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			instructions := forwardedPortDNATFilterInstructions(testCase.dnat, testCase.remove)
+
+			assert.Equal(t, testCase.instructions, instructions)
+		})
+	}
+}
+
+func Test_forwardedPortDNATNATInstructions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		dnat         forwardedPortDNAT
+		remove       bool
+		instructions []string
+	}{
+		"append": {
+			dnat: forwardedPortDNAT{
+				intf:        "tun0",
+				port:        1000,
+				destination: netip.MustParseAddrPort("172.18.0.2:8080"),
+			},
+			instructions: []string{
+				"-t nat --append PREROUTING -i tun0 -p tcp --dport 1000 " +
+					"-j DNAT --to-destination 172.18.0.2:8080",
+				"-t nat --append POSTROUTING -d 172.18.0.2 -p tcp --dport 8080 -j MASQUERADE",
+				"-t nat --append PREROUTING -i tun0 -p udp --dport 1000 " +
+					"-j DNAT --to-destination 172.18.0.2:8080",
+				"-t nat --append POSTROUTING -d 172.18.0.2 -p udp --dport 8080 -j MASQUERADE",
+			},
+		},
+		"delete": {
+			dnat: forwardedPortDNAT{
+				intf:        "wg0",
+				port:        1000,
+				destination: netip.MustParseAddrPort("172.18.0.2:1000"),
+			},
+			remove: true,
+			instructions: []string{
+				"-t nat --delete PREROUTING -i wg0 -p tcp --dport 1000 " +
+					"-j DNAT --to-destination 172.18.0.2:1000",
+				"-t nat --delete POSTROUTING -d 172.18.0.2 -p tcp --dport 1000 -j MASQUERADE",
+				"-t nat --delete PREROUTING -i wg0 -p udp --dport 1000 " +
+					"-j DNAT --to-destination 172.18.0.2:1000",
+				"-t nat --delete POSTROUTING -d 172.18.0.2 -p udp --dport 1000 -j MASQUERADE",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			instructions := forwardedPortDNATNATInstructions(testCase.dnat, testCase.remove)
+
+			assert.Equal(t, testCase.instructions, instructions)
+		})
+	}
+}
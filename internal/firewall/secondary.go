@@ -0,0 +1,139 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type secondaryTunnel struct {
+	connection models.Connection
+	intf       string
+	mark       int
+	ports      []uint16
+	sources    []netip.Prefix
+}
+
+// SetSecondaryTunnel allows traffic through the secondary tunnel interface
+// and to its endpoint, and marks traffic going to one of the ports given
+// with the mark given, such that it can be routed through the secondary tunnel.
+// Rules for a previously set secondary tunnel are removed.
+func (c *Config) SetSecondaryTunnel(ctx context.Context,
+	connection models.Connection, intf string, mark int,
+	ports []uint16, sources []netip.Prefix) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.secondaryTunnel.intf != "" {
+		const remove = true
+		if c.enabled {
+			err = c.allowSecondaryTunnel(ctx, remove)
+			if err != nil {
+				c.logger.Error("cannot remove outdated secondary tunnel rule: " + err.Error())
+			}
+		}
+		err = c.steerToSecondaryTunnel(ctx, remove)
+		if err != nil {
+			c.logger.Error("cannot remove outdated secondary tunnel steering rule: " + err.Error())
+		}
+	}
+
+	c.secondaryTunnel = secondaryTunnel{
+		connection: connection,
+		intf:       intf,
+		mark:       mark,
+		ports:      make([]uint16, len(ports)),
+		sources:    make([]netip.Prefix, len(sources)),
+	}
+	copy(c.secondaryTunnel.ports, ports)
+	copy(c.secondaryTunnel.sources, sources)
+
+	const remove = false
+	err = c.steerToSecondaryTunnel(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("steering traffic to secondary tunnel: %w", err)
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal secondary tunnel state")
+		return nil
+	}
+
+	c.logger.Info("allowing secondary tunnel connection...")
+	err = c.allowSecondaryTunnel(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("allowing secondary tunnel: %w", err)
+	}
+
+	return nil
+}
+
+// allowSecondaryTunnel accepts output traffic to the secondary tunnel
+// endpoint and through the secondary tunnel interface, as well as traffic
+// forwarded from the source networks through the secondary tunnel interface.
+func (c *Config) allowSecondaryTunnel(ctx context.Context, remove bool) (err error) {
+	tunnel := c.secondaryTunnel
+	for _, defaultRoute := range c.defaultRoutes {
+		err = c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, tunnel.connection, remove)
+		if err != nil {
+			return fmt.Errorf("accepting output traffic to secondary tunnel endpoint: %w", err)
+		}
+	}
+
+	err = c.acceptOutputThroughInterface(ctx, tunnel.intf, remove)
+	if err != nil {
+		return fmt.Errorf("accepting output traffic through interface %s: %w", tunnel.intf, err)
+	}
+
+	for _, source := range tunnel.sources {
+		err = c.acceptForwardFromSubnet(ctx, tunnel.intf, source, remove)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// steerToSecondaryTunnel marks new packets going to one of the secondary
+// tunnel ports so they are routed through the secondary tunnel, and masquerades
+// packets leaving through the secondary tunnel interface since their source address
+// may be the one of the main VPN interface. These rules are not in the filter
+// table and are not affected by the firewall being enabled or disabled.
+func (c *Config) steerToSecondaryTunnel(ctx context.Context, remove bool) (err error) {
+	tunnel := c.secondaryTunnel
+	instructions := make([]string, 0, 2*2*len(tunnel.ports)+1) //nolint:gomnd
+	for _, port := range tunnel.ports {
+		for _, chain := range []string{"OUTPUT", "PREROUTING"} {
+			for _, protocol := range []string{constants.TCP, constants.UDP} {
+				instructions = append(instructions, fmt.Sprintf(
+					"-t mangle %s %s -p %s --dport %d -m mark --mark 0 -j MARK --set-mark %d",
+					appendOrDelete(remove), chain, protocol, port, tunnel.mark))
+			}
+		}
+	}
+	instructions = append(instructions, fmt.Sprintf(
+		"-t nat %s POSTROUTING -o %s -j MASQUERADE",
+		appendOrDelete(remove), tunnel.intf))
+	return c.runIptablesInstructions(ctx, instructions)
+}
+
+func (c *Config) acceptForwardFromSubnet(ctx context.Context, intf string,
+	source netip.Prefix, remove bool) (err error) {
+	instructions := []string{
+		fmt.Sprintf("%s FORWARD -s %s -o %s -j ACCEPT",
+			appendOrDelete(remove), source, intf),
+		fmt.Sprintf("%s FORWARD -i %s -d %s -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			appendOrDelete(remove), intf, source),
+	}
+
+	if source.Addr().Is4() {
+		return c.runIptablesInstructions(ctx, instructions)
+	} else if c.ip6Tables == "" {
+		return fmt.Errorf("accept forward from %s: %w", source, ErrNeedIP6Tables)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
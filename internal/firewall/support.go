@@ -19,6 +19,13 @@ var (
 	ErrIPTablesNotSupported = errors.New("no iptables supported found")
 )
 
+// CheckIptablesSupport returns the path of the first iptables
+// binary able to modify rules, trying iptables and iptables-nft.
+func CheckIptablesSupport(ctx context.Context, runner command.Runner) (
+	iptablesPath string, err error) {
+	return checkIptablesSupport(ctx, runner, "iptables", "iptables-nft")
+}
+
 func checkIptablesSupport(ctx context.Context, runner command.Runner,
 	iptablesPathsToTry ...string) (iptablesPath string, err error) {
 	iptablesPathToUnsupportedMessage := make(map[string]string, len(iptablesPathsToTry))
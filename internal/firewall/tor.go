@@ -0,0 +1,60 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// torRedirectIsSet returns true if the connection is the Tor
+// transparent proxy, which TCP traffic must be redirected to.
+// A Tor connection without port is used when chaining Tor before
+// OpenVPN, in which case no traffic is redirected.
+func torRedirectIsSet(connection models.Connection) bool {
+	return connection.Type == vpn.Tor && connection.Port > 0
+}
+
+// redirectToTor redirects new TCP connections not originating from the
+// Tor client and not going to the loopback or local networks to the Tor
+// transparent proxy port. These rules are in the nat table and are not
+// affected by the firewall being enabled or disabled.
+func (c *Config) redirectToTor(ctx context.Context, port uint16, remove bool) (err error) {
+	instructions := make([]string, 0, len(c.localNetworks)+3) //nolint:gomnd
+	instructions = append(instructions,
+		fmt.Sprintf("-t nat %s OUTPUT -m owner --uid-owner %s -j RETURN",
+			appendOrDelete(remove), constants.TorUser),
+		fmt.Sprintf("-t nat %s OUTPUT -d 127.0.0.0/8 -j RETURN",
+			appendOrDelete(remove)),
+	)
+	for _, network := range c.localNetworks {
+		if !network.IPNet.Addr().Is4() {
+			continue
+		}
+		instructions = append(instructions, fmt.Sprintf(
+			"-t nat %s OUTPUT -d %s -j RETURN",
+			appendOrDelete(remove), network.IPNet))
+	}
+	instructions = append(instructions, fmt.Sprintf(
+		"-t nat %s OUTPUT -p tcp --syn -j REDIRECT --to-ports %d",
+		appendOrDelete(remove), port))
+	return c.runIptablesInstructions(ctx, instructions)
+}
+
+// acceptOutputFromTor accepts output traffic from the Tor client,
+// since the Tor relays it connects to are not known in advance.
+func (c *Config) acceptOutputFromTor(ctx context.Context,
+	defaultInterface string, remove bool) error {
+	instruction := fmt.Sprintf("%s OUTPUT -o %s -m owner --uid-owner %s -j ACCEPT",
+		appendOrDelete(remove), defaultInterface, constants.TorUser)
+	err := c.runIptablesInstruction(ctx, instruction)
+	if err != nil {
+		return err
+	}
+	if c.ip6Tables == "" {
+		return nil
+	}
+	return c.runIP6tablesInstruction(ctx, instruction)
+}
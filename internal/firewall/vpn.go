@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -12,12 +13,26 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	if !c.vpnConnection.Equal(connection) {
+		err = c.updateTorRedirect(ctx, connection)
+		if err != nil {
+			return fmt.Errorf("redirecting traffic to Tor: %w", err)
+		}
+	}
+
 	if !c.enabled {
 		c.logger.Info("firewall disabled, only updating internal VPN connection")
 		c.vpnConnection = connection
 		return nil
 	}
 
+	if c.paused {
+		c.logger.Info("firewall paused, only updating internal VPN connection")
+		c.vpnConnection = connection
+		c.vpnIntf = vpnIntf
+		return nil
+	}
+
 	c.logger.Info("allowing VPN connection...")
 
 	if c.vpnConnection.Equal(connection) {
@@ -25,7 +40,7 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 	}
 
 	remove := true
-	if c.vpnConnection.IP.IsValid() {
+	if vpnConnectionIsSet(c.vpnConnection) {
 		for _, defaultRoute := range c.defaultRoutes {
 			if err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, c.vpnConnection, remove); err != nil {
 				c.logger.Error("cannot remove outdated VPN connection rule: " + err.Error())
@@ -38,6 +53,12 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 		if err = c.acceptOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
 			c.logger.Error("cannot remove outdated VPN interface rule: " + err.Error())
 		}
+		if err = c.allowWireguardServerForward(ctx, remove); err != nil {
+			c.logger.Error("cannot remove outdated Wireguard server forwarding rule: " + err.Error())
+		}
+		if err = c.allowLANGatewayForward(ctx, remove); err != nil {
+			c.logger.Error("cannot remove outdated LAN gateway forwarding rule: " + err.Error())
+		}
 	}
 	c.vpnIntf = ""
 
@@ -55,5 +76,37 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 	}
 	c.vpnIntf = vpnIntf
 
+	if err = c.allowWireguardServerForward(ctx, remove); err != nil {
+		return fmt.Errorf("allowing Wireguard server forwarding: %w", err)
+	}
+
+	if err = c.allowLANGatewayForward(ctx, remove); err != nil {
+		return fmt.Errorf("allowing LAN gateway forwarding: %w", err)
+	}
+
 	return nil
 }
+
+func vpnConnectionIsSet(connection models.Connection) bool {
+	return connection.IP.IsValid() || connection.Type == vpn.Tailscale ||
+		connection.Type == vpn.Tor
+}
+
+// updateTorRedirect removes the Tor redirection rules of the current
+// VPN connection, if any, and adds the ones of the new connection given.
+func (c *Config) updateTorRedirect(ctx context.Context,
+	connection models.Connection) (err error) {
+	if torRedirectIsSet(c.vpnConnection) {
+		const remove = true
+		err = c.redirectToTor(ctx, c.vpnConnection.Port, remove)
+		if err != nil {
+			c.logger.Error("cannot remove outdated Tor redirection rule: " + err.Error())
+		}
+	}
+
+	if !torRedirectIsSet(connection) {
+		return nil
+	}
+	const remove = false
+	return c.redirectToTor(ctx, connection.Port, remove)
+}
@@ -0,0 +1,99 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+)
+
+type wireguardServer struct {
+	intf   string
+	subnet netip.Prefix
+}
+
+// SetWireguardServer allows traffic through the Wireguard server interface
+// given, and forwards traffic from the subnet given through the VPN interface
+// only, masquerading it so replies come back through the server interface.
+func (c *Config) SetWireguardServer(ctx context.Context,
+	intf string, subnet netip.Prefix) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.wireguardServer.intf != "" {
+		const remove = true
+		if c.enabled {
+			err = c.allowWireguardServer(ctx, remove)
+			if err != nil {
+				c.logger.Error("cannot remove outdated Wireguard server rule: " + err.Error())
+			}
+		}
+		err = c.masqueradeWireguardServer(ctx, remove)
+		if err != nil {
+			c.logger.Error("cannot remove outdated Wireguard server masquerading rule: " + err.Error())
+		}
+	}
+
+	c.wireguardServer = wireguardServer{
+		intf:   intf,
+		subnet: subnet.Masked(),
+	}
+
+	const remove = false
+	err = c.masqueradeWireguardServer(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("masquerading Wireguard server traffic: %w", err)
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal Wireguard server state")
+		return nil
+	}
+
+	c.logger.Info("allowing Wireguard server traffic...")
+	err = c.allowWireguardServer(ctx, remove)
+	if err != nil {
+		return fmt.Errorf("allowing Wireguard server: %w", err)
+	}
+
+	return nil
+}
+
+// allowWireguardServer accepts traffic through the Wireguard server
+// interface, as well as traffic forwarded from the server subnet through
+// the VPN interface if it is set and the firewall is not paused.
+func (c *Config) allowWireguardServer(ctx context.Context, remove bool) (err error) {
+	server := c.wireguardServer
+	err = c.acceptInputThroughInterface(ctx, server.intf, remove)
+	if err != nil {
+		return fmt.Errorf("accepting input traffic through interface %s: %w", server.intf, err)
+	}
+
+	err = c.acceptOutputThroughInterface(ctx, server.intf, remove)
+	if err != nil {
+		return fmt.Errorf("accepting output traffic through interface %s: %w", server.intf, err)
+	}
+
+	if c.paused {
+		return nil
+	}
+	return c.allowWireguardServerForward(ctx, remove)
+}
+
+// allowWireguardServerForward accepts traffic forwarded from the Wireguard
+// server subnet through the VPN interface, if both interfaces are set.
+func (c *Config) allowWireguardServerForward(ctx context.Context, remove bool) (err error) {
+	if c.wireguardServer.intf == "" || c.vpnIntf == "" {
+		return nil
+	}
+	return c.acceptForwardFromSubnet(ctx, c.vpnIntf, c.wireguardServer.subnet, remove)
+}
+
+// masqueradeWireguardServer masquerades packets from the Wireguard server
+// subnet leaving through another interface. This rule is not in the filter
+// table and is not affected by the firewall being enabled or disabled.
+func (c *Config) masqueradeWireguardServer(ctx context.Context, remove bool) (err error) {
+	server := c.wireguardServer
+	instruction := fmt.Sprintf("-t nat %s POSTROUTING -s %s ! -o %s -j MASQUERADE",
+		appendOrDelete(remove), server.subnet, server.intf)
+	return c.runIptablesInstructions(ctx, []string{instruction})
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
 func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
@@ -16,11 +19,19 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	for {
 		previousErr := s.handler.getErr()
 
-		const healthcheckTimeout = 3 * time.Second
-		healthcheckCtx, healthcheckCancel := context.WithTimeout(
-			ctx, healthcheckTimeout)
+		healthcheckCtx, span := s.tracer.Start(ctx, "health check")
+		span.SetAttribute("health.target", targetAddresses(s.config.Targets))
+		start := time.Now()
 		err := s.healthCheck(healthcheckCtx)
-		healthcheckCancel()
+		span.End(err)
+		if err == nil {
+			latency := time.Since(start)
+			s.metrics.RecordHealthLatency(latency)
+			median, switchServer := s.latency.record(latency, time.Now())
+			if switchServer {
+				s.onDegradedLatencyVPN(ctx, median)
+			}
+		}
 
 		s.handler.setErr(err)
 
@@ -62,28 +73,65 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	}
 }
 
+// healthCheck dials all the health targets concurrently, each
+// with its own timeout, and succeeds as soon as one of them
+// can be dialed. It returns all the dial errors joined together
+// if none of the targets can be dialed.
 func (s *Server) healthCheck(ctx context.Context) (err error) {
 	// TODO use mullvad API if current provider is Mullvad
 
-	address, err := makeAddressToDial(s.config.TargetAddress)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(s.config.Targets))
+	for _, target := range s.config.Targets {
+		go func(target settings.HealthTarget) {
+			errs <- s.checkTarget(ctx, target)
+		}(target)
+	}
+
+	targetErrs := make([]error, 0, len(s.config.Targets))
+	for range s.config.Targets {
+		err := <-errs
+		if err == nil {
+			return nil
+		}
+		targetErrs = append(targetErrs, err)
+	}
+	return errors.Join(targetErrs...)
+}
+
+func (s *Server) checkTarget(ctx context.Context,
+	target settings.HealthTarget) (err error) {
+	address, err := makeAddressToDial(target.Address)
 	if err != nil {
-		return err
+		return fmt.Errorf("target %s: %w", target.Address, err)
 	}
 
-	const dialNetwork = "tcp4"
-	connection, err := s.dialer.DialContext(ctx, dialNetwork, address)
+	ctx, cancel := context.WithTimeout(ctx, target.Timeout)
+	defer cancel()
+
+	connection, err := s.dialer.DialContext(ctx, target.Network, address)
 	if err != nil {
-		return fmt.Errorf("dialing: %w", err)
+		return fmt.Errorf("dialing %s: %w", target.Address, err)
 	}
 
 	err = connection.Close()
 	if err != nil {
-		return fmt.Errorf("closing connection: %w", err)
+		return fmt.Errorf("closing connection to %s: %w", target.Address, err)
 	}
 
 	return nil
 }
 
+func targetAddresses(targets []settings.HealthTarget) (addresses string) {
+	addressesSlice := make([]string, len(targets))
+	for i, target := range targets {
+		addressesSlice[i] = target.Address
+	}
+	return strings.Join(addressesSlice, ",")
+}
+
 func makeAddressToDial(address string) (addressToDial string, err error) {
 	host, port, err := net.SplitHostPort(address)
 	if err != nil {
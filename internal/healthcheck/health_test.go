@@ -24,7 +24,9 @@ func Test_Server_healthCheck(t *testing.T) {
 		server := &Server{
 			dialer: dialer,
 			config: settings.Health{
-				TargetAddress: address,
+				Targets: []settings.HealthTarget{
+					{Address: address, Network: "tcp4", Timeout: time.Second},
+				},
 			},
 		}
 
@@ -53,18 +55,38 @@ func Test_Server_healthCheck(t *testing.T) {
 		server := &Server{
 			dialer: dialer,
 			config: settings.Health{
-				TargetAddress: listeningAddress.String(),
+				Targets: []settings.HealthTarget{
+					{Address: "127.0.0.1:1", Network: "tcp4", Timeout: time.Second},
+					{Address: listeningAddress.String(), Network: "tcp4", Timeout: time.Second},
+				},
 			},
 		}
 
-		const timeout = 100 * time.Millisecond
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-
-		err = server.healthCheck(ctx)
+		err = server.healthCheck(context.Background())
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("all targets timing out", func(t *testing.T) {
+		t.Parallel()
+
+		dialer := &net.Dialer{}
+		server := &Server{
+			dialer: dialer,
+			config: settings.Health{
+				Targets: []settings.HealthTarget{
+					{Address: "10.255.255.1:443", Network: "tcp4", Timeout: time.Nanosecond},
+					{Address: "10.255.255.2:443", Network: "tcp4", Timeout: time.Nanosecond},
+				},
+			},
+		}
+
+		err := server.healthCheck(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dialing 10.255.255.1:443")
+		assert.Contains(t, err.Error(), "dialing 10.255.255.2:443")
+	})
 }
 
 func Test_makeAddressToDial(t *testing.T) {
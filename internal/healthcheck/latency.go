@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"sort"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// latencyTracker tracks the rolling median of the health check
+// latencies to detect a sustained latency degradation.
+type latencyTracker struct {
+	settings settings.HealthLatency
+	// samples is a ring buffer of the latest latencies.
+	samples []time.Duration
+	next    int
+	// degradedSince is the time the rolling median went above
+	// the threshold, and is the zero time if it is not degraded.
+	degradedSince time.Time
+}
+
+func newLatencyTracker(settings settings.HealthLatency) *latencyTracker {
+	return &latencyTracker{
+		settings: settings,
+		samples:  make([]time.Duration, 0, settings.Samples),
+	}
+}
+
+// record records a latency sample at the time given and returns the
+// current rolling median, and whether the VPN server should be switched
+// because the median stayed above the threshold for the period set.
+// The rolling median is only considered recovered once it drops below
+// the threshold multiplied by the recovery ratio.
+func (l *latencyTracker) record(latency time.Duration, now time.Time) (
+	median time.Duration, switchServer bool) {
+	threshold := *l.settings.Threshold
+	if threshold == 0 {
+		return 0, false
+	}
+
+	if len(l.samples) < int(l.settings.Samples) {
+		l.samples = append(l.samples, latency)
+	} else {
+		l.samples[l.next] = latency
+	}
+	l.next = (l.next + 1) % int(l.settings.Samples)
+
+	if len(l.samples) < int(l.settings.Samples) {
+		return 0, false
+	}
+
+	median = medianOf(l.samples)
+	recovered := time.Duration(float64(threshold) * l.settings.RecoveryRatio)
+	switch {
+	case median > threshold:
+		if l.degradedSince.IsZero() {
+			l.degradedSince = now
+		} else if now.Sub(l.degradedSince) >= l.settings.Period {
+			l.reset()
+			return median, true
+		}
+	case median < recovered:
+		l.degradedSince = time.Time{}
+	}
+	return median, false
+}
+
+// reset clears the samples and degraded state, and should
+// be called when the VPN server changes.
+func (l *latencyTracker) reset() {
+	l.samples = l.samples[:0]
+	l.next = 0
+	l.degradedSince = time.Time{}
+}
+
+func medianOf(durations []time.Duration) (median time.Duration) {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	middle := len(sorted) / 2 //nolint:gomnd
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2 //nolint:gomnd
+}
@@ -0,0 +1,58 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_latencyTracker_record(t *testing.T) {
+	t.Parallel()
+
+	threshold := 100 * time.Millisecond
+	tracker := newLatencyTracker(settings.HealthLatency{
+		Threshold:     &threshold,
+		Period:        time.Minute,
+		Samples:       1,
+		RecoveryRatio: 0.5,
+	})
+	start := time.Unix(0, 0)
+
+	type step struct {
+		latency      time.Duration
+		elapsed      time.Duration
+		median       time.Duration
+		switchServer bool
+	}
+	steps := []step{
+		// degraded since now
+		{latency: 200 * time.Millisecond, median: 200 * time.Millisecond},
+		// median below threshold but above recovery level
+		{latency: 80 * time.Millisecond, elapsed: 30 * time.Second, median: 80 * time.Millisecond},
+		// degraded period still counted from the first degradation
+		{latency: 150 * time.Millisecond, elapsed: time.Minute,
+			median: 150 * time.Millisecond, switchServer: true},
+		// degraded state is reset after switching
+		{latency: 150 * time.Millisecond, elapsed: time.Minute, median: 150 * time.Millisecond},
+		// median below recovery level
+		{latency: 40 * time.Millisecond, elapsed: 90 * time.Second, median: 40 * time.Millisecond},
+		{latency: 150 * time.Millisecond, elapsed: 2 * time.Minute, median: 150 * time.Millisecond},
+		{latency: 150 * time.Millisecond, elapsed: 3 * time.Minute,
+			median: 150 * time.Millisecond, switchServer: true},
+	}
+
+	for i, step := range steps {
+		median, switchServer := tracker.record(step.latency, start.Add(step.elapsed))
+		assert.Equal(t, step.median, median, "step %d", i)
+		assert.Equal(t, step.switchServer, switchServer, "step %d", i)
+	}
+}
+
+func Test_medianOf(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 2*time.Second, medianOf([]time.Duration{3 * time.Second, time.Second, 2 * time.Second}))
+	assert.Equal(t, 1500*time.Millisecond, medianOf([]time.Duration{2 * time.Second, time.Second}))
+}
@@ -14,11 +14,29 @@ type vpnHealth struct {
 }
 
 func (s *Server) onUnhealthyVPN(ctx context.Context) {
+	if s.vpn.loop.GetStatus() == constants.Stopped {
+		// VPN stopped on purpose, for example in on demand mode
+		s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
+		return
+	}
 	s.logger.Info("program has been unhealthy for " +
 		s.vpn.healthyWait.String() + ": restarting VPN " +
 		"(see https://github.com/qdm12/gluetun/wiki/Healthcheck)")
+	s.vpn.loop.ReportUnhealthy()
 	_, _ = s.vpn.loop.ApplyStatus(ctx, constants.Stopped)
 	_, _ = s.vpn.loop.ApplyStatus(ctx, constants.Running)
+	s.latency.reset()
 	s.vpn.healthyWait += *s.config.VPN.Addition
 	s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
 }
+
+func (s *Server) onDegradedLatencyVPN(ctx context.Context, median time.Duration) {
+	if s.vpn.loop.GetStatus() == constants.Stopped {
+		return
+	}
+	s.logger.Info("median latency " + median.String() + " has been above " +
+		s.config.Latency.Threshold.String() + " for " +
+		s.config.Latency.Period.String() + ": switching VPN server")
+	_, _ = s.vpn.loop.ApplyStatus(ctx, constants.Stopped)
+	_, _ = s.vpn.loop.ApplyStatus(ctx, constants.Running)
+}
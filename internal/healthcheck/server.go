@@ -3,9 +3,11 @@ package healthcheck
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tracing"
 )
 
 type Server struct {
@@ -14,10 +16,14 @@ type Server struct {
 	dialer  *net.Dialer
 	config  settings.Health
 	vpn     vpnHealth
+	latency *latencyTracker
+	tracer  Tracer
+	metrics Metrics
 }
 
 func NewServer(config settings.Health,
-	logger Logger, vpnLoop StatusApplier) *Server {
+	logger Logger, vpnLoop StatusApplier, tracer Tracer,
+	metrics Metrics) *Server {
 	return &Server{
 		logger:  logger,
 		handler: newHandler(),
@@ -31,10 +37,24 @@ func NewServer(config settings.Health,
 			loop:        vpnLoop,
 			healthyWait: *config.VPN.Initial,
 		},
+		latency: newLatencyTracker(config.Latency),
+		tracer:  tracer,
+		metrics: metrics,
 	}
 }
 
+type Tracer interface {
+	Start(ctx context.Context, name string) (
+		spanCtx context.Context, span *tracing.Span)
+}
+
+type Metrics interface {
+	RecordHealthLatency(latency time.Duration)
+}
+
 type StatusApplier interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
+	GetStatus() (status models.LoopStatus)
+	ReportUnhealthy()
 }
@@ -0,0 +1,50 @@
+// Package hook runs user executables on VPN events, such as
+// lifecycle hooks and event hooks, without using a shell.
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/qdm12/golibs/command"
+)
+
+// EnvPrefix is the prefix of the environment variables
+// set for hook executables.
+const EnvPrefix = "VPN_"
+
+type Logger interface {
+	Info(s string)
+}
+
+// Run runs the executable at argv[0] with the arguments argv[1:] and
+// the environment variables env added to the process environment.
+// The executable is killed if it runs for longer than the timeout
+// given, and its output is logged.
+func Run(ctx context.Context, cmder command.Runner, logger Logger,
+	name string, argv, env []string, timeout time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec
+	cmd.Env = append(os.Environ(), env...)
+	logger.Info("running " + name + " " + argv[0])
+	output, err := cmder.Run(cmd)
+	output = strings.TrimSpace(output)
+	if output != "" {
+		logger.Info(name + " output: " + output)
+	}
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
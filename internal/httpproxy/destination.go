@@ -0,0 +1,191 @@
+package httpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDestinationDenied     = errors.New("destination is denied")
+	ErrDestinationNotAllowed = errors.New("destination is not allowed")
+	ErrConnectPortNotAllowed = errors.New("destination port is not allowed for CONNECT")
+	ErrPortDenied            = errors.New("destination port is denied")
+	ErrPortNotAllowed        = errors.New("destination port is not allowed")
+)
+
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) (
+		ips []netip.Addr, err error)
+}
+
+// DestinationFilter restricts the destinations the HTTP proxy
+// connects to. Hostname rules match the hostname requested and
+// its subdomains, and IP address and CIDR rules match the IP
+// address requested or the IP addresses the hostname resolves to.
+type DestinationFilter struct {
+	allowedHostnames []string
+	allowedPrefixes  []netip.Prefix
+	deniedHostnames  []string
+	deniedPrefixes   []netip.Prefix
+	allowedPorts     []uint16
+	deniedPorts      []uint16
+	connectPorts     []uint16
+	resolver         Resolver
+}
+
+// NewDestinationFilter creates a destination filter from the allowed
+// and denied destinations given, each being a hostname, an IP address
+// or a CIDR, which are assumed to be already validated.
+// CONNECT requests are restricted to port 443 and the connect ports given.
+func NewDestinationFilter(allowed, denied []string,
+	allowedPorts, deniedPorts, connectPorts []uint16,
+	resolver Resolver) *DestinationFilter {
+	const httpsPort = 443
+	f := &DestinationFilter{
+		allowedPorts: allowedPorts,
+		deniedPorts:  deniedPorts,
+		connectPorts: append([]uint16{httpsPort}, connectPorts...),
+		resolver:     resolver,
+	}
+	f.allowedHostnames, f.allowedPrefixes = parseDestinations(allowed)
+	f.deniedHostnames, f.deniedPrefixes = parseDestinations(denied)
+	return f
+}
+
+func parseDestinations(destinations []string) (hostnames []string, prefixes []netip.Prefix) {
+	for _, destination := range destinations {
+		if ip, err := netip.ParseAddr(destination); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+		} else if prefix, err := netip.ParsePrefix(destination); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else {
+			hostnames = append(hostnames, strings.ToLower(destination))
+		}
+	}
+	return hostnames, prefixes
+}
+
+// check returns an error if the destination `host:port` given is denied
+// or not allowed. A hostname is resolved only if there are CIDR rules
+// which may match it; note the proxy dials the hostname again afterwards.
+// If connect is true, the port must also be one of the CONNECT ports.
+func (f *DestinationFilter) check(ctx context.Context, destination string,
+	connect bool) (err error) {
+	host, portString, err := net.SplitHostPort(destination)
+	if err != nil {
+		return fmt.Errorf("splitting host and port: %w", err)
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return fmt.Errorf("parsing port: %w", err)
+	}
+
+	switch {
+	case connect && !portIn(uint16(port), f.connectPorts):
+		return fmt.Errorf("%w: %d", ErrConnectPortNotAllowed, port)
+	case portIn(uint16(port), f.deniedPorts):
+		return fmt.Errorf("%w: %d", ErrPortDenied, port)
+	case len(f.allowedPorts) > 0 && !portIn(uint16(port), f.allowedPorts):
+		return fmt.Errorf("%w: %d", ErrPortNotAllowed, port)
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	var ips []netip.Addr
+	if ip, err := netip.ParseAddr(host); err == nil {
+		ips = []netip.Addr{ip.Unmap()}
+	} else if len(f.deniedPrefixes) > 0 || len(f.allowedPrefixes) > 0 {
+		ips, err = f.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", host, err)
+		}
+	}
+
+	if hostnameIn(host, f.deniedHostnames) || anyIPIn(ips, f.deniedPrefixes) {
+		return fmt.Errorf("%w: %s", ErrDestinationDenied, host)
+	}
+
+	if len(f.allowedHostnames) == 0 && len(f.allowedPrefixes) == 0 {
+		return nil
+	} else if hostnameIn(host, f.allowedHostnames) || allIPsIn(ips, f.allowedPrefixes) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrDestinationNotAllowed, host)
+}
+
+// requestDestination returns the `host:port` destination of the
+// request given, using the default port of the scheme if needed.
+func requestDestination(request *http.Request) (destination string) {
+	if request.Method == http.MethodConnect {
+		return request.Host
+	}
+	host := request.URL.Host
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	port := "80"
+	if request.URL.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
+
+func (h *handler) isDestinationAllowed(responseWriter http.ResponseWriter,
+	request *http.Request) (allowed bool) {
+	err := h.filter.check(request.Context(), requestDestination(request),
+		request.Method == http.MethodConnect)
+	if err != nil {
+		if h.verbose {
+			h.logger.Info(request.RemoteAddr + " refused: " + err.Error())
+		}
+		http.Error(responseWriter, err.Error(), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func portIn(port uint16, ports []uint16) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func hostnameIn(hostname string, hostnames []string) bool {
+	for _, h := range hostnames {
+		if hostname == h || strings.HasSuffix(hostname, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyIPIn(ips []netip.Addr, prefixes []netip.Prefix) bool {
+	for _, ip := range ips {
+		for _, prefix := range prefixes {
+			if prefix.Contains(ip.Unmap()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func allIPsIn(ips []netip.Addr, prefixes []netip.Prefix) bool {
+	if len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !anyIPIn([]netip.Addr{ip}, prefixes) {
+			return false
+		}
+	}
+	return true
+}
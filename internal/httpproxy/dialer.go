@@ -0,0 +1,40 @@
+package httpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"golang.org/x/net/proxy"
+)
+
+type contextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+var ErrSOCKSProxyNotFound = errors.New("SOCKS proxy not found")
+
+// newDialer returns a dialer connecting directly through the VPN
+// tunnel, or through the provider SOCKS5 proxy of the VPN server
+// with the hostname given if it is not empty.
+func newDialer(storage Storage, socksServerHostname string) (
+	dialer contextDialer, err error) {
+	if socksServerHostname == "" {
+		return &net.Dialer{}, nil
+	}
+
+	server, ok := storage.GetServerByHostname(providers.Mullvad, socksServerHostname)
+	if !ok || server.SOCKSProxy == "" {
+		return nil, fmt.Errorf("%w: for server hostname %s",
+			ErrSOCKSProxyNotFound, socksServerHostname)
+	}
+
+	socksDialer, err := proxy.SOCKS5("tcp", server.SOCKSProxy, nil, &net.Dialer{})
+	if err != nil {
+		return nil, fmt.Errorf("creating SOCKS5 dialer: %w", err)
+	}
+
+	return socksDialer.(contextDialer), nil //nolint:forcetypeassert
+}
@@ -9,14 +9,21 @@ import (
 )
 
 func newHandler(ctx context.Context, wg *sync.WaitGroup, logger Logger,
-	stealth, verbose bool, username, password string) http.Handler {
+	stealth, verbose bool, username, password string,
+	dialer contextDialer, filter *DestinationFilter, vpn VPNDemander) http.Handler {
 	const httpTimeout = 24 * time.Hour
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.DialContext = dialer.DialContext
 	return &handler{
 		ctx: ctx,
 		wg:  wg,
 		client: &http.Client{
 			Timeout:       httpTimeout,
+			Transport:     transport,
 			CheckRedirect: returnRedirect},
+		dialer:   dialer,
+		filter:   filter,
+		vpn:      vpn,
 		logger:   logger,
 		verbose:  verbose,
 		stealth:  stealth,
@@ -29,6 +36,9 @@ type handler struct {
 	ctx                context.Context //nolint:containedctx
 	wg                 *sync.WaitGroup
 	client             *http.Client
+	dialer             contextDialer
+	filter             *DestinationFilter
+	vpn                VPNDemander
 	logger             Logger
 	verbose, stealth   bool
 	username, password string
@@ -41,6 +51,10 @@ func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Re
 	if !h.isAuthorized(responseWriter, request) {
 		return
 	}
+	if !h.isDestinationAllowed(responseWriter, request) {
+		return
+	}
+	h.vpn.Demand(h.ctx)
 	request.Header.Del("Proxy-Connection")
 	request.Header.Del("Proxy-Authenticate")
 	request.Header.Del("Proxy-Authorization")
@@ -2,13 +2,11 @@ package httpproxy
 
 import (
 	"io"
-	"net"
 	"net/http"
 )
 
 func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.Request) {
-	dialer := net.Dialer{}
-	destinationConn, err := dialer.DialContext(h.ctx, "tcp", request.Host)
+	destinationConn, err := h.dialer.DialContext(h.ctx, "tcp", request.Host)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), http.StatusServiceUnavailable)
 		return
@@ -0,0 +1,16 @@
+package httpproxy
+
+import (
+	"context"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type Storage interface {
+	GetServerByHostname(provider, hostname string) (
+		server models.Server, ok bool)
+}
+
+type VPNDemander interface {
+	Demand(ctx context.Context)
+}
@@ -15,7 +15,9 @@ type Loop struct {
 	statusManager *loopstate.State
 	state         *state.State
 	// Other objects
-	logger Logger
+	logger  Logger
+	storage Storage
+	vpn     VPNDemander
 	// Internal channels and locks
 	running       chan models.LoopStatus
 	stop, stopped chan struct{}
@@ -26,7 +28,8 @@ type Loop struct {
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLoop(logger Logger, settings settings.HTTPProxy) *Loop {
+func NewLoop(logger Logger, settings settings.HTTPProxy,
+	storage Storage, vpn VPNDemander) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
@@ -40,6 +43,8 @@ func NewLoop(logger Logger, settings settings.HTTPProxy) *Loop {
 		statusManager: statusManager,
 		state:         state,
 		logger:        logger,
+		storage:       storage,
+		vpn:           vpn,
 		start:         start,
 		running:       running,
 		stop:          stop,
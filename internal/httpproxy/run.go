@@ -2,6 +2,7 @@ package httpproxy
 
 import (
 	"context"
+	"net"
 
 	"github.com/qdm12/gluetun/internal/constants"
 )
@@ -21,9 +22,22 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		runCtx, runCancel := context.WithCancel(ctx)
 
 		settings := l.state.GetSettings()
+		dialer, err := newDialer(l.storage, *settings.ProviderSOCKSServer)
+		if err != nil {
+			runCancel()
+			l.statusManager.SetStatus(constants.Crashed)
+			l.logAndWait(ctx, err)
+			continue
+		}
+
+		filter := NewDestinationFilter(settings.AllowedDestinations,
+			settings.DeniedDestinations, settings.AllowedPorts,
+			settings.DeniedPorts, settings.ConnectPorts, net.DefaultResolver)
+
 		server := New(runCtx, settings.ListeningAddress, l.logger,
 			*settings.Stealth, *settings.Log, *settings.User,
-			*settings.Password, settings.ReadHeaderTimeout, settings.ReadTimeout)
+			*settings.Password, settings.ReadHeaderTimeout, settings.ReadTimeout,
+			dialer, filter, l.vpn)
 
 		errorCh := make(chan error)
 		go server.Run(runCtx, errorCh)
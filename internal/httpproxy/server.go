@@ -18,11 +18,13 @@ type Server struct {
 
 func New(ctx context.Context, address string, logger Logger,
 	stealth, verbose bool, username, password string,
-	readHeaderTimeout, readTimeout time.Duration) *Server {
+	readHeaderTimeout, readTimeout time.Duration,
+	dialer contextDialer, filter *DestinationFilter, vpn VPNDemander) *Server {
 	wg := &sync.WaitGroup{}
 	return &Server{
-		address:           address,
-		handler:           newHandler(ctx, wg, logger, stealth, verbose, username, password),
+		address: address,
+		handler: newHandler(ctx, wg, logger, stealth, verbose,
+			username, password, dialer, filter, vpn),
 		logger:            logger,
 		internalWG:        wg,
 		readHeaderTimeout: readHeaderTimeout,
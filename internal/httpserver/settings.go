@@ -4,9 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/capabilities"
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/govalid/address"
@@ -84,7 +84,7 @@ var (
 )
 
 func (s Settings) Validate() (err error) {
-	uid := os.Getuid()
+	uid := capabilities.ListeningUID()
 	_, err = address.Validate(s.Address, address.OptionListening(uid))
 	if err != nil {
 		return err
@@ -0,0 +1,118 @@
+// Package localnetworks periodically checks for local networks
+// added after start, such as when the container is connected to
+// another Docker network, and allows and routes them.
+package localnetworks
+
+import (
+	"context"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/routing"
+)
+
+type Firewall interface {
+	AddLocalNetworks(ctx context.Context, networks []routing.LocalNetwork) (err error)
+}
+
+type Routing interface {
+	ListLocalNetworks() (localNetworks []routing.LocalNetwork, err error)
+	AddLocalRules(subnets []routing.LocalNetwork) (err error)
+}
+
+type Logger interface {
+	Info(s string)
+	Error(s string)
+}
+
+type Watcher struct {
+	period   time.Duration
+	firewall Firewall
+	routing  Routing
+	logger   Logger
+	// known are the local networks already allowed and routed.
+	known []routing.LocalNetwork
+}
+
+// New creates a watcher for local networks, given the local networks
+// already allowed and routed at start. The period can be set to 0 to
+// disable the watcher.
+func New(period time.Duration, known []routing.LocalNetwork,
+	firewall Firewall, routing Routing, logger Logger) *Watcher {
+	return &Watcher{
+		period:   period,
+		firewall: firewall,
+		routing:  routing,
+		logger:   logger,
+		known:    known,
+	}
+}
+
+// Update lists the local networks and allows and routes
+// the ones not already known.
+func (w *Watcher) Update(ctx context.Context) (err error) {
+	localNetworks, err := w.routing.ListLocalNetworks()
+	if err != nil {
+		return err
+	}
+
+	var added []routing.LocalNetwork
+	for _, network := range localNetworks {
+		if isKnown(network, w.known) {
+			continue
+		}
+		w.logger.Info("new local network found: " + network.IPNet.String() +
+			" on interface " + network.InterfaceName)
+		added = append(added, network)
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	err = w.firewall.AddLocalNetworks(ctx, added)
+	if err != nil {
+		return err
+	}
+
+	err = w.routing.AddLocalRules(added)
+	if err != nil {
+		return err
+	}
+
+	w.known = append(w.known, added...)
+	return nil
+}
+
+// Run updates the local networks periodically
+// if the period is not zero.
+func (w *Watcher) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	if w.period == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := w.Update(ctx)
+			if err != nil && ctx.Err() == nil {
+				w.logger.Error("updating local networks: " + err.Error())
+			}
+		}
+	}
+}
+
+func isKnown(network routing.LocalNetwork, known []routing.LocalNetwork) bool {
+	for _, knownNetwork := range known {
+		if network.IPNet == knownNetwork.IPNet &&
+			network.InterfaceName == knownNetwork.InterfaceName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package localnetworks
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRouting struct {
+	localNetworks []routing.LocalNetwork
+	ruled         []routing.LocalNetwork
+}
+
+func (r *fakeRouting) ListLocalNetworks() ([]routing.LocalNetwork, error) {
+	return r.localNetworks, nil
+}
+
+func (r *fakeRouting) AddLocalRules(subnets []routing.LocalNetwork) error {
+	r.ruled = append(r.ruled, subnets...)
+	return nil
+}
+
+type fakeFirewall struct {
+	allowed []routing.LocalNetwork
+}
+
+func (f *fakeFirewall) AddLocalNetworks(_ context.Context, networks []routing.LocalNetwork) error {
+	f.allowed = append(f.allowed, networks...)
+	return nil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Info(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_Watcher_Update(t *testing.T) {
+	t.Parallel()
+
+	eth0 := routing.LocalNetwork{
+		IPNet:         netip.MustParsePrefix("172.17.0.0/16"),
+		InterfaceName: "eth0",
+		IP:            netip.MustParseAddr("172.17.0.2"),
+	}
+	eth1 := routing.LocalNetwork{
+		IPNet:         netip.MustParsePrefix("172.18.0.0/16"),
+		InterfaceName: "eth1",
+		IP:            netip.MustParseAddr("172.18.0.2"),
+	}
+
+	routingFake := &fakeRouting{localNetworks: []routing.LocalNetwork{eth0}}
+	firewall := &fakeFirewall{}
+	watcher := New(0, []routing.LocalNetwork{eth0}, firewall, routingFake, noopLogger{})
+
+	err := watcher.Update(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, firewall.allowed)
+	assert.Empty(t, routingFake.ruled)
+
+	routingFake.localNetworks = []routing.LocalNetwork{eth0, eth1}
+	err = watcher.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []routing.LocalNetwork{eth1}, firewall.allowed)
+	assert.Equal(t, []routing.LocalNetwork{eth1}, routingFake.ruled)
+
+	// Known networks are not added again.
+	err = watcher.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []routing.LocalNetwork{eth1}, firewall.allowed)
+}
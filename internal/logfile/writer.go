@@ -0,0 +1,161 @@
+// Package logfile writes logs to a file, rotating it
+// once it exceeds a maximum size or a maximum age.
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Writer is an io.Writer writing to a log file and rotating it.
+// It is safe for concurrent use.
+type Writer struct {
+	// Fixed parameters
+	path       string
+	maxSize    uint64
+	maxAge     time.Duration
+	maxBackups int
+	// Internal state
+	file      *os.File
+	size      uint64
+	openedAt  time.Time
+	fileMutex sync.Mutex
+	// Mock functions
+	timeNow func() time.Time
+}
+
+// New creates a new log file writer appending to the file at the path
+// given. The file is rotated once writing to it would exceed maxSize
+// bytes, or once it has been open for longer than maxAge, and at most
+// maxBackups rotated files are kept. maxSize and maxAge can be set to
+// 0 to disable their respective rotation.
+func New(path string, maxSize uint64, maxAge time.Duration,
+	maxBackups uint8) (writer *Writer, err error) {
+	writer = &Writer{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: int(maxBackups),
+		timeNow:    time.Now,
+	}
+
+	const dirPerms = 0755
+	err = os.MkdirAll(filepath.Dir(path), dirPerms)
+	if err != nil {
+		return nil, fmt.Errorf("creating log file directory: %w", err)
+	}
+
+	err = writer.open()
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+var regexANSIColor = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Write writes the log line given to the log file, without
+// its color codes, rotating the file first if needed.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	line := regexANSIColor.ReplaceAll(p, nil)
+
+	w.fileMutex.Lock()
+	defer w.fileMutex.Unlock()
+
+	if w.needsRotation(len(line)) {
+		err = w.rotate()
+		if err != nil {
+			return 0, fmt.Errorf("rotating log file: %w", err)
+		}
+	}
+
+	written, err := w.file.Write(line)
+	w.size += uint64(written)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the log file.
+func (w *Writer) Close() (err error) {
+	w.fileMutex.Lock()
+	defer w.fileMutex.Unlock()
+	return w.file.Close()
+}
+
+func (w *Writer) needsRotation(writeSize int) bool {
+	if w.size == 0 {
+		return false
+	}
+	return (w.maxSize > 0 && w.size+uint64(writeSize) > w.maxSize) ||
+		(w.maxAge > 0 && w.timeNow().Sub(w.openedAt) >= w.maxAge)
+}
+
+func (w *Writer) open() (err error) {
+	const perms = 0644
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perms)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("getting log file size: %w", err)
+	}
+
+	w.file = file
+	w.size = uint64(stat.Size())
+	w.openedAt = w.timeNow()
+	return nil
+}
+
+// backupTimeFormat is the time format suffixed to rotated log files,
+// which sorts lexicographically in chronological order.
+const backupTimeFormat = "20060102T150405.000"
+
+func (w *Writer) rotate() (err error) {
+	err = w.file.Close()
+	if err != nil {
+		return fmt.Errorf("closing log file: %w", err)
+	}
+
+	backupPath := w.path + "." + w.timeNow().UTC().Format(backupTimeFormat)
+	err = os.Rename(w.path, backupPath)
+	if err != nil {
+		return fmt.Errorf("renaming log file: %w", err)
+	}
+
+	err = w.removeOldBackups()
+	if err != nil {
+		return err
+	}
+
+	return w.open()
+}
+
+func (w *Writer) removeOldBackups() (err error) {
+	backupPaths, err := filepath.Glob(w.path + ".*")
+	if err != nil {
+		return fmt.Errorf("listing rotated log files: %w", err)
+	}
+
+	sort.Strings(backupPaths)
+	if len(backupPaths) <= w.maxBackups {
+		return nil
+	}
+
+	for _, backupPath := range backupPaths[:len(backupPaths)-w.maxBackups] {
+		err = os.Remove(backupPath)
+		if err != nil {
+			return fmt.Errorf("removing rotated log file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Writer(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "gluetun.log")
+	const maxSize, maxAge, maxBackups = 20, time.Hour, 2
+	writer, err := New(path, maxSize, maxAge, maxBackups)
+	require.NoError(t, err)
+
+	now := time.Date(2023, 10, 15, 12, 0, 0, 0, time.UTC)
+	writer.timeNow = func() time.Time { return now }
+	writer.openedAt = now
+
+	writeLine := func(line string) {
+		t.Helper()
+		n, err := writer.Write([]byte(line))
+		require.NoError(t, err)
+		assert.Equal(t, len(line), n)
+	}
+
+	writeLine("\x1b[32mINFO\x1b[0m line 1\n") // 12 bytes without colors
+	writeLine("line 2\n")                     // 19 bytes
+	now = now.Add(time.Second)
+	writeLine("line 3\n") // exceeds max size, rotated
+	now = now.Add(time.Hour)
+	writeLine("line 4\n") // exceeds max age, rotated
+	now = now.Add(time.Second)
+	writeLine("line 5 is long enough\n") // exceeds max size, rotated
+
+	err = writer.Close()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "line 5 is long enough\n", string(data))
+
+	backupPaths, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	sort.Strings(backupPaths)
+	expectedBackups := map[string]string{
+		path + ".20231015T130001.000": "line 3\n",
+		path + ".20231015T130002.000": "line 4\n",
+	}
+	require.Len(t, backupPaths, len(expectedBackups))
+	for _, backupPath := range backupPaths {
+		data, err := os.ReadFile(backupPath)
+		require.NoError(t, err)
+		assert.Equal(t, expectedBackups[backupPath], string(data))
+	}
+}
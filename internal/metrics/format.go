@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type sample struct {
+	state
+	bandwidth []models.InterfaceBandwidth
+}
+
+// formatStatsD formats the sample using the StatsD format, with
+// one metric per line. Counters are sent as the difference
+// with the previous sample sent.
+func formatStatsD(prefix string, current sample, previous state) string {
+	lines := []string{
+		prefix + ".reconnects:" + fmt.Sprint(current.reconnects()-previous.reconnects()) + "|c",
+		prefix + ".forwarded_port:" + fmt.Sprint(current.forwardedPort) + "|g",
+		prefix + ".forwarded_port_changes:" +
+			fmt.Sprint(current.forwardedPortChanges-previous.forwardedPortChanges) + "|c",
+	}
+
+	if current.healthLatency > 0 {
+		lines = append(lines, prefix+".health_latency_ms:"+
+			fmt.Sprint(current.healthLatency.Milliseconds())+"|g")
+	}
+
+	for _, bandwidth := range current.bandwidth {
+		if bandwidth.Tunnel {
+			lines = append(lines,
+				prefix+".vpn_rx_bytes:"+fmt.Sprint(bandwidth.SessionRxBytes)+"|g",
+				prefix+".vpn_tx_bytes:"+fmt.Sprint(bandwidth.SessionTxBytes)+"|g")
+		}
+		interfacePrefix := prefix + ".interface." + bandwidth.Interface
+		lines = append(lines,
+			interfacePrefix+".session_rx_bytes:"+fmt.Sprint(bandwidth.SessionRxBytes)+"|g",
+			interfacePrefix+".session_tx_bytes:"+fmt.Sprint(bandwidth.SessionTxBytes)+"|g",
+			interfacePrefix+".total_rx_bytes:"+fmt.Sprint(bandwidth.TotalRxBytes)+"|g",
+			interfacePrefix+".total_tx_bytes:"+fmt.Sprint(bandwidth.TotalTxBytes)+"|g")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatInflux formats the sample using the InfluxDB line protocol,
+// with cumulative counters. The first line contains the program
+// metrics, and each following line contains the bandwidth metrics
+// of a network interface, tagged with the interface name.
+func formatInflux(measurement string, current sample, timestamp time.Time) string {
+	fields := []string{
+		"reconnects=" + fmt.Sprint(current.reconnects()) + "i",
+		"forwarded_port=" + fmt.Sprint(current.forwardedPort) + "i",
+		"forwarded_port_changes=" + fmt.Sprint(current.forwardedPortChanges) + "i",
+	}
+
+	if current.healthLatency > 0 {
+		fields = append(fields, "health_latency_ms="+
+			fmt.Sprint(current.healthLatency.Milliseconds())+"i")
+	}
+
+	for _, bandwidth := range current.bandwidth {
+		if bandwidth.Tunnel {
+			fields = append(fields,
+				"vpn_rx_bytes="+fmt.Sprint(bandwidth.SessionRxBytes)+"i",
+				"vpn_tx_bytes="+fmt.Sprint(bandwidth.SessionTxBytes)+"i")
+		}
+	}
+
+	measurement = escapeInfluxMeasurement(measurement)
+	timestampString := strconv.FormatInt(timestamp.UnixNano(), 10)
+	lines := []string{measurement + " " + strings.Join(fields, ",") + " " + timestampString}
+
+	for _, bandwidth := range current.bandwidth {
+		tags := ",interface=" + escapeInfluxMeasurement(bandwidth.Interface) +
+			",tunnel=" + strconv.FormatBool(bandwidth.Tunnel)
+		fields := []string{
+			"session_rx_bytes=" + fmt.Sprint(bandwidth.SessionRxBytes) + "i",
+			"session_tx_bytes=" + fmt.Sprint(bandwidth.SessionTxBytes) + "i",
+			"total_rx_bytes=" + fmt.Sprint(bandwidth.TotalRxBytes) + "i",
+			"total_tx_bytes=" + fmt.Sprint(bandwidth.TotalTxBytes) + "i",
+		}
+		lines = append(lines, measurement+"_bandwidth"+tags+" "+
+			strings.Join(fields, ",")+" "+timestampString)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func escapeInfluxMeasurement(measurement string) string {
+	measurement = strings.ReplaceAll(measurement, ",", `\,`)
+	return strings.ReplaceAll(measurement, " ", `\ `)
+}
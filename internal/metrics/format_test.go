@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatStatsD(t *testing.T) {
+	t.Parallel()
+
+	current := sample{
+		state: state{
+			connections:          4,
+			healthLatency:        25 * time.Millisecond,
+			forwardedPort:        5000,
+			forwardedPortChanges: 2,
+		},
+		bandwidth: []models.InterfaceBandwidth{{
+			Interface:      "tun0",
+			Tunnel:         true,
+			SessionRxBytes: 100,
+			SessionTxBytes: 200,
+			TotalRxBytes:   300,
+			TotalTxBytes:   400,
+		}},
+	}
+	previous := state{
+		connections:          2,
+		forwardedPortChanges: 2,
+	}
+
+	payload := formatStatsD("gluetun", current, previous)
+
+	const expected = "gluetun.reconnects:2|c\n" +
+		"gluetun.forwarded_port:5000|g\n" +
+		"gluetun.forwarded_port_changes:0|c\n" +
+		"gluetun.health_latency_ms:25|g\n" +
+		"gluetun.vpn_rx_bytes:100|g\n" +
+		"gluetun.vpn_tx_bytes:200|g\n" +
+		"gluetun.interface.tun0.session_rx_bytes:100|g\n" +
+		"gluetun.interface.tun0.session_tx_bytes:200|g\n" +
+		"gluetun.interface.tun0.total_rx_bytes:300|g\n" +
+		"gluetun.interface.tun0.total_tx_bytes:400|g"
+	assert.Equal(t, expected, payload)
+}
+
+func Test_formatInflux(t *testing.T) {
+	t.Parallel()
+
+	current := sample{
+		state: state{
+			connections:   1,
+			forwardedPort: 5000,
+		},
+		bandwidth: []models.InterfaceBandwidth{{
+			Interface:    "eth0",
+			TotalRxBytes: 1,
+			TotalTxBytes: 2,
+		}},
+	}
+
+	payload := formatInflux("my gluetun", current, time.Unix(1, 0))
+
+	const expected = `my\ gluetun reconnects=0i,forwarded_port=5000i,` +
+		`forwarded_port_changes=0i 1000000000` + "\n" +
+		`my\ gluetun_bandwidth,interface=eth0,tunnel=false session_rx_bytes=0i,` +
+		`session_tx_bytes=0i,total_rx_bytes=1i,total_tx_bytes=2i 1000000000`
+	assert.Equal(t, expected, payload)
+}
@@ -0,0 +1,15 @@
+package metrics
+
+import "github.com/qdm12/gluetun/internal/models"
+
+type PortForwardedGetter interface {
+	GetPortForwarded() (portForwarded uint16)
+}
+
+type BandwidthGetter interface {
+	GetBandwidth() (bandwidth []models.InterfaceBandwidth)
+}
+
+type Logger interface {
+	Warn(s string)
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// Emitter collects metrics from the rest of the program and
+// periodically pushes them to a StatsD or InfluxDB server over UDP.
+type Emitter struct {
+	// Fixed parameters
+	enabled bool
+	address string
+	format  string
+	prefix  string
+	period  time.Duration
+	// Objects
+	portGetter      PortForwardedGetter
+	bandwidthGetter BandwidthGetter
+	logger          Logger
+	// Internal state
+	state      state
+	lastSent   state
+	stateMutex sync.Mutex
+	// Mock functions
+	timeNow func() time.Time
+}
+
+type state struct {
+	connections          uint64
+	healthLatency        time.Duration
+	forwardedPort        uint16
+	forwardedPortChanges uint64
+}
+
+func New(settings settings.Metrics, portGetter PortForwardedGetter,
+	bandwidthGetter BandwidthGetter, logger Logger) *Emitter {
+	return &Emitter{
+		enabled:         *settings.Address != "",
+		address:         *settings.Address,
+		format:          settings.Format,
+		prefix:          settings.Prefix,
+		period:          settings.Period,
+		portGetter:      portGetter,
+		bandwidthGetter: bandwidthGetter,
+		logger:          logger,
+		timeNow:         time.Now,
+	}
+}
+
+// RecordTunnelUp records the VPN tunnel is up. Every tunnel
+// up event after the first one is counted as a reconnection.
+func (e *Emitter) RecordTunnelUp() {
+	e.stateMutex.Lock()
+	defer e.stateMutex.Unlock()
+	e.state.connections++
+}
+
+// RecordHealthLatency records the latency of the last
+// successful health check.
+func (e *Emitter) RecordHealthLatency(latency time.Duration) {
+	e.stateMutex.Lock()
+	defer e.stateMutex.Unlock()
+	e.state.healthLatency = latency
+}
+
+func (e *Emitter) updateForwardedPort() {
+	port := e.portGetter.GetPortForwarded()
+
+	e.stateMutex.Lock()
+	defer e.stateMutex.Unlock()
+	if port != e.state.forwardedPort {
+		e.state.forwardedPortChanges++
+		e.state.forwardedPort = port
+	}
+}
+
+func (s state) reconnects() uint64 {
+	if s.connections == 0 {
+		return 0
+	}
+	return s.connections - 1
+}
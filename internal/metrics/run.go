@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Run pushes metrics periodically until the context is canceled.
+func (e *Emitter) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	if !e.enabled {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(e.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := e.push(ctx)
+			if err != nil {
+				e.logger.Warn("pushing metrics: " + err.Error())
+			}
+		}
+	}
+}
+
+func (e *Emitter) push(ctx context.Context) (err error) {
+	e.updateForwardedPort()
+
+	e.stateMutex.Lock()
+	current := sample{state: e.state}
+	previous := e.lastSent
+	e.stateMutex.Unlock()
+
+	current.bandwidth = e.bandwidthGetter.GetBandwidth()
+
+	var payload string
+	switch e.format {
+	case "influx":
+		payload = formatInflux(e.prefix, current, e.timeNow())
+	default:
+		payload = formatStatsD(e.prefix, current, previous)
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "udp", e.address)
+	if err != nil {
+		return fmt.Errorf("dialing: %w", err)
+	}
+
+	_, err = conn.Write([]byte(payload))
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("writing: %w", err)
+	}
+
+	err = conn.Close()
+	if err != nil {
+		return fmt.Errorf("closing connection: %w", err)
+	}
+
+	e.stateMutex.Lock()
+	e.lastSent = current.state
+	e.stateMutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package models
+
+// InterfaceBandwidth contains the bytes counted for
+// a network interface.
+type InterfaceBandwidth struct {
+	Interface string `json:"interface"`
+	// Tunnel is true if the interface is the VPN tunnel interface.
+	Tunnel bool `json:"tunnel"`
+	// SessionRxBytes and SessionTxBytes are the bytes received and
+	// sent since the tunnel is up for the tunnel interface, and since
+	// the program started for other interfaces.
+	SessionRxBytes uint64 `json:"session_rx_bytes"`
+	SessionTxBytes uint64 `json:"session_tx_bytes"`
+	// TotalRxBytes and TotalTxBytes are the bytes received and
+	// sent in total, including previous runs of the program.
+	TotalRxBytes uint64 `json:"total_rx_bytes"`
+	TotalTxBytes uint64 `json:"total_tx_bytes"`
+}
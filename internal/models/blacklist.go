@@ -0,0 +1,17 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// BlacklistedServer is a VPN server temporarily excluded from
+// the connection picking after failing repeatedly.
+type BlacklistedServer struct {
+	Name     string     `json:"name,omitempty"`
+	Hostname string     `json:"hostname,omitempty"`
+	IP       netip.Addr `json:"ip"`
+	Failures uint8      `json:"failures"`
+	// Until is the time the server is blacklisted until.
+	Until time.Time `json:"until"`
+}
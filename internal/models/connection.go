@@ -5,7 +5,8 @@ import (
 )
 
 type Connection struct {
-	// Type is the connection type and can be "openvpn" or "wireguard"
+	// Type is the connection type and can be "openvpn", "wireguard"
+	// or "tailscale".
 	Type string `json:"type"`
 	// IP is the VPN server IP address.
 	IP netip.Addr `json:"ip"`
@@ -24,7 +25,8 @@ type Connection struct {
 }
 
 func (c *Connection) Equal(other Connection) bool {
-	return c.IP.Compare(other.IP) == 0 && c.Port == other.Port &&
+	return c.Type == other.Type &&
+		c.IP.Compare(other.IP) == 0 && c.Port == other.Port &&
 		c.Protocol == other.Protocol && c.Hostname == other.Hostname &&
 		c.ServerName == other.ServerName && c.PubKey == other.PubKey
 }
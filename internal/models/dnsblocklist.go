@@ -0,0 +1,13 @@
+package models
+
+import "net/netip"
+
+// DNSBlocklist contains the hosts and IP addresses allowed
+// or blocked by the DNS server, in addition to the built-in
+// block lists.
+type DNSBlocklist struct {
+	AllowedHosts      []string       `json:"allowed_hosts"`
+	BlockedHosts      []string       `json:"blocked_hosts"`
+	BlockedIPs        []netip.Addr   `json:"blocked_ips"`
+	BlockedIPPrefixes []netip.Prefix `json:"blocked_ip_prefixes"`
+}
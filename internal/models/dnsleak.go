@@ -0,0 +1,29 @@
+package models
+
+import "net/netip"
+
+// DNSLeakTestResult contains the results of a DNS leak test.
+type DNSLeakTestResult struct {
+	// PublicIP is the public IP address seen by the leak test
+	// service, which is the VPN server exit IP address when the
+	// VPN is up. It is nil if the service did not report it.
+	PublicIP *DNSLeakTestIP `json:"public_ip,omitempty"`
+	// Resolvers are the DNS resolvers which answered the
+	// queries of the leak test.
+	Resolvers []DNSLeakTestIP `json:"resolvers"`
+	// Leaking is true if at least one resolver is not
+	// operated by the same network as the public IP address.
+	Leaking bool `json:"leaking"`
+	// Conclusion is the conclusion of the leak test service.
+	Conclusion string `json:"conclusion,omitempty"`
+}
+
+// DNSLeakTestIP is an IP address reported by a DNS leak test.
+type DNSLeakTestIP struct {
+	IP      netip.Addr `json:"ip"`
+	Country string     `json:"country,omitempty"`
+	ASN     string     `json:"asn,omitempty"`
+	// NonVPN is true for resolvers not operated by
+	// the same network as the public IP address.
+	NonVPN bool `json:"non_vpn,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DNSTopDomains contains the most queried and most blocked
+// domains since the statistics were last reset.
+type DNSTopDomains struct {
+	Since        time.Time        `json:"since"`
+	TotalQueried uint64           `json:"total_queried"`
+	TotalBlocked uint64           `json:"total_blocked"`
+	TopQueried   []DNSDomainCount `json:"top_queried"`
+	TopBlocked   []DNSDomainCount `json:"top_blocked"`
+}
+
+// DNSDomainCount is the number of times a domain was queried or blocked.
+type DNSDomainCount struct {
+	Domain string `json:"domain"`
+	Count  uint64 `json:"count"`
+}
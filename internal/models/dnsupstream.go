@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// DNSUpstream is the health state of a DNS over TLS upstream provider.
+type DNSUpstream struct {
+	Provider string `json:"provider"`
+	Healthy  bool   `json:"healthy"`
+	// Active is true if the upstream is currently used by Unbound.
+	Active bool `json:"active"`
+	// CheckedAt is the time of the last health check, and is
+	// the zero time if the upstream was not checked yet.
+	CheckedAt time.Time `json:"checked_at"`
+	Error     string    `json:"error,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+const (
+	// PortForwardEventForwarded is the event type when
+	// a port is forwarded by the VPN server.
+	PortForwardEventForwarded = "port_forwarded"
+	// PortForwardEventLost is the event type when port
+	// forwarding fails, with the reason set.
+	PortForwardEventLost = "port_forwarding_lost"
+)
+
+// HookEvents returns the event types an event hook
+// command can be set for.
+func HookEvents() (events []string) {
+	return []string{
+		VPNEventConnecting,
+		VPNEventConnected,
+		VPNEventDisconnected,
+		VPNEventFailed,
+		VPNEventAuthFailed,
+		PortForwardEventForwarded,
+		PortForwardEventLost,
+	}
+}
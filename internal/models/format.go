@@ -0,0 +1,222 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+)
+
+var (
+	ErrColumnNotValid = errors.New("column is not valid")
+	ErrFormatNotValid = errors.New("format is not valid")
+)
+
+// columnToHeader maps column identifiers, matching the server
+// JSON field names, to their header.
+var columnToHeader = map[string]string{ //nolint:gochecknoglobals
+	"city":         cityHeader,
+	"country":      countryHeader,
+	"free":         freeHeader,
+	"hostname":     hostnameHeader,
+	"isp":          ispHeader,
+	"multihop":     multiHopHeader,
+	"server_name":  nameHeader,
+	"number":       numberHeader,
+	"owned":        ownedHeader,
+	"port_forward": portForwardHeader,
+	"premium":      premiumHeader,
+	"region":       regionHeader,
+	"stream":       streamHeader,
+	"tcp":          tcpHeader,
+	"udp":          udpHeader,
+	"vpn":          vpnHeader,
+}
+
+// fieldValue returns the server field value for the header given,
+// which is either a string, a bool or an uint16.
+func (s *Server) fieldValue(header string) (value any) {
+	switch header {
+	case cityHeader:
+		return s.City
+	case countryHeader:
+		return s.Country
+	case freeHeader:
+		return s.Free
+	case hostnameHeader:
+		return s.Hostname
+	case ispHeader:
+		return s.ISP
+	case multiHopHeader:
+		return s.MultiHop
+	case nameHeader:
+		return s.ServerName
+	case numberHeader:
+		return s.Number
+	case ownedHeader:
+		return s.Owned
+	case portForwardHeader:
+		return s.PortForward
+	case premiumHeader:
+		return s.Premium
+	case regionHeader:
+		return s.Region
+	case streamHeader:
+		return s.Stream
+	case tcpHeader:
+		return s.TCP
+	case udpHeader:
+		return s.UDP || s.VPN == vpn.Wireguard
+	case vpnHeader:
+		return s.VPN
+	default:
+		return ""
+	}
+}
+
+func (s *Server) fieldStrings(headers []string) (fields []string) {
+	fields = make([]string, len(headers))
+	for i, header := range headers {
+		fields[i] = fmt.Sprint(s.fieldValue(header))
+	}
+	return fields
+}
+
+// Format formats the servers using the format given, which can be
+// 'markdown', 'json', 'csv' or 'table'. The columns given are the
+// server JSON field names to output, and default to the columns
+// relevant to the VPN provider if left empty.
+func (s *Servers) Format(vpnProvider, format string,
+	columns []string) (formatted string, err error) {
+	headers := getMarkdownHeaders(vpnProvider)
+	if len(columns) > 0 {
+		headers, err = columnsToHeaders(columns)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch format {
+	case "markdown":
+		return s.toMarkdown(headers), nil
+	case "json":
+		return s.toJSON(headers)
+	case "csv":
+		return s.toCSV(headers)
+	case "table":
+		return s.toTable(headers)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrFormatNotValid, format)
+	}
+}
+
+func columnsToHeaders(columns []string) (headers []string, err error) {
+	headers = make([]string, len(columns))
+	for i, column := range columns {
+		header, ok := columnToHeader[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrColumnNotValid, column)
+		}
+		headers[i] = header
+	}
+	return headers, nil
+}
+
+func headerToColumn(header string) (column string) {
+	for column, candidate := range columnToHeader {
+		if candidate == header {
+			return column
+		}
+	}
+	return ""
+}
+
+func (s *Servers) toJSON(headers []string) (formatted string, err error) {
+	objects := make([]map[string]any, len(s.Servers))
+	for i, server := range s.Servers {
+		objects[i] = make(map[string]any, len(headers))
+		for _, header := range headers {
+			objects[i][headerToColumn(header)] = server.fieldValue(header)
+		}
+	}
+
+	data, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding JSON: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+func (s *Servers) toCSV(headers []string) (formatted string, err error) {
+	buffer := bytes.NewBuffer(nil)
+	writer := csv.NewWriter(buffer)
+	columns := make([]string, len(headers))
+	for i, header := range headers {
+		columns[i] = headerToColumn(header)
+	}
+	records := make([][]string, 0, len(s.Servers)+1)
+	records = append(records, columns)
+	for _, server := range s.Servers {
+		records = append(records, server.fieldStrings(headers))
+	}
+
+	err = writer.WriteAll(records)
+	if err != nil {
+		return "", fmt.Errorf("writing CSV: %w", err)
+	}
+	return buffer.String(), nil
+}
+
+func (s *Servers) toTable(headers []string) (formatted string, err error) {
+	buffer := bytes.NewBuffer(nil)
+	const minWidth, tabWidth, padding = 0, 0, 2
+	writer := tabwriter.NewWriter(buffer, minWidth, tabWidth, padding, ' ', 0)
+
+	upperHeaders := make([]string, len(headers))
+	for i, header := range headers {
+		upperHeaders[i] = strings.ToUpper(header)
+	}
+	_, err = fmt.Fprintln(writer, strings.Join(upperHeaders, "\t"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, server := range s.Servers {
+		fields := make([]string, len(headers))
+		for i, header := range headers {
+			switch value := server.fieldValue(header).(type) {
+			case bool:
+				fields[i] = boolToYesNo(value)
+			case string:
+				if value == "" {
+					value = "-"
+				}
+				fields[i] = value
+			default:
+				fields[i] = fmt.Sprint(value)
+			}
+		}
+		_, err = fmt.Fprintln(writer, strings.Join(fields, "\t"))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return "", fmt.Errorf("flushing table: %w", err)
+	}
+	return buffer.String(), nil
+}
+
+func boolToYesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
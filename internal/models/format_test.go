@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Servers_Format(t *testing.T) {
+	t.Parallel()
+
+	servers := Servers{
+		Servers: []Server{
+			{Country: "a", UDP: true, Hostname: "xa"},
+			{Country: "b", TCP: true, Hostname: "xb"},
+		},
+	}
+
+	testCases := map[string]struct {
+		format     string
+		columns    []string
+		formatted  string
+		errWrapped error
+		errMessage string
+	}{
+		"markdown": {
+			format:  "markdown",
+			columns: []string{"hostname", "tcp"},
+			formatted: "| Hostname | TCP |\n" +
+				"| --- | --- |\n" +
+				"| `xa` | ❌ |\n" +
+				"| `xb` | ✅ |\n",
+		},
+		"json": {
+			format:  "json",
+			columns: []string{"country", "udp"},
+			formatted: "[\n" +
+				"  {\n    \"country\": \"a\",\n    \"udp\": true\n  },\n" +
+				"  {\n    \"country\": \"b\",\n    \"udp\": false\n  }\n" +
+				"]\n",
+		},
+		"csv_default_columns": {
+			format: "csv",
+			formatted: "country,hostname,tcp,udp\n" +
+				"a,xa,false,true\n" +
+				"b,xb,true,false\n",
+		},
+		"table": {
+			format:  "table",
+			columns: []string{"Country", "city", "tcp"},
+			formatted: "COUNTRY  CITY  TCP\n" +
+				"a        -     no\n" +
+				"b        -     yes\n",
+		},
+		"invalid_column": {
+			format:     "csv",
+			columns:    []string{"country", "bad"},
+			errWrapped: ErrColumnNotValid,
+			errMessage: "column is not valid: bad",
+		},
+		"invalid_format": {
+			format:     "xml",
+			errWrapped: ErrFormatNotValid,
+			errMessage: "format is not valid: xml",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			formatted, err := servers.Format(providers.Cyberghost,
+				testCase.format, testCase.columns)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.formatted, formatted)
+		})
+	}
+}
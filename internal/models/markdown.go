@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/constants/providers"
-	"github.com/qdm12/gluetun/internal/constants/vpn"
 )
 
 func boolToMarkdown(b bool) string {
@@ -46,39 +45,16 @@ func (s *Server) ToMarkdown(headers ...string) (markdown string) {
 
 	fields := make([]string, len(headers))
 	for i, header := range headers {
-		switch header {
-		case cityHeader:
-			fields[i] = s.City
-		case countryHeader:
-			fields[i] = s.Country
-		case freeHeader:
-			fields[i] = boolToMarkdown(s.Free)
-		case hostnameHeader:
-			fields[i] = fmt.Sprintf("`%s`", s.Hostname)
-		case ispHeader:
-			fields[i] = s.ISP
-		case multiHopHeader:
-			fields[i] = boolToMarkdown(s.MultiHop)
-		case nameHeader:
-			fields[i] = s.ServerName
-		case numberHeader:
-			fields[i] = fmt.Sprint(s.Number)
-		case ownedHeader:
-			fields[i] = boolToMarkdown(s.Owned)
-		case portForwardHeader:
-			fields[i] = boolToMarkdown(s.PortForward)
-		case premiumHeader:
-			fields[i] = boolToMarkdown(s.Premium)
-		case regionHeader:
-			fields[i] = s.Region
-		case streamHeader:
-			fields[i] = boolToMarkdown(s.Stream)
-		case tcpHeader:
-			fields[i] = boolToMarkdown(s.TCP)
-		case udpHeader:
-			fields[i] = boolToMarkdown(s.UDP || s.VPN == vpn.Wireguard)
-		case vpnHeader:
-			fields[i] = s.VPN
+		switch value := s.fieldValue(header).(type) {
+		case bool:
+			fields[i] = boolToMarkdown(value)
+		case string:
+			if header == hostnameHeader {
+				value = fmt.Sprintf("`%s`", value)
+			}
+			fields[i] = value
+		default:
+			fields[i] = fmt.Sprint(value)
 		}
 	}
 
@@ -87,7 +63,10 @@ func (s *Server) ToMarkdown(headers ...string) (markdown string) {
 
 func (s *Servers) ToMarkdown(vpnProvider string) (markdown string) {
 	headers := getMarkdownHeaders(vpnProvider)
+	return s.toMarkdown(headers)
+}
 
+func (s *Servers) toMarkdown(headers []string) (markdown string) {
 	legend := markdownTableHeading(headers...)
 
 	entries := make([]string, len(s.Servers))
@@ -111,6 +90,8 @@ func getMarkdownHeaders(vpnProvider string) (headers []string) {
 		return []string{countryHeader, cityHeader, hostnameHeader, tcpHeader, udpHeader}
 	case providers.Fastestvpn:
 		return []string{countryHeader, hostnameHeader, tcpHeader, udpHeader}
+	case providers.Hideme:
+		return []string{countryHeader, cityHeader, hostnameHeader, tcpHeader, udpHeader}
 	case providers.HideMyAss:
 		return []string{countryHeader, regionHeader, cityHeader, hostnameHeader, tcpHeader, udpHeader}
 	case providers.Ipvanish:
@@ -120,7 +101,9 @@ func getMarkdownHeaders(vpnProvider string) (headers []string) {
 	case providers.Mullvad:
 		return []string{countryHeader, cityHeader, ispHeader, ownedHeader, hostnameHeader, vpnHeader}
 	case providers.Nordvpn:
-		return []string{countryHeader, regionHeader, cityHeader, hostnameHeader}
+		return []string{countryHeader, regionHeader, cityHeader, hostnameHeader, vpnHeader}
+	case providers.Ovpn:
+		return []string{countryHeader, cityHeader, hostnameHeader, vpnHeader}
 	case providers.Perfectprivacy:
 		return []string{cityHeader, tcpHeader, udpHeader}
 	case providers.Privado:
@@ -136,7 +119,7 @@ func getMarkdownHeaders(vpnProvider string) (headers []string) {
 	case providers.SlickVPN:
 		return []string{regionHeader, countryHeader, cityHeader, hostnameHeader}
 	case providers.Surfshark:
-		return []string{regionHeader, countryHeader, cityHeader, hostnameHeader, multiHopHeader, tcpHeader, udpHeader}
+		return []string{regionHeader, countryHeader, cityHeader, hostnameHeader, vpnHeader, multiHopHeader, tcpHeader, udpHeader}
 	case providers.Torguard:
 		return []string{countryHeader, cityHeader, hostnameHeader, tcpHeader, udpHeader}
 	case providers.VPNSecure:
@@ -29,8 +29,11 @@ type Server struct {
 	WgPubKey    string       `json:"wgpubkey,omitempty"`
 	Free        bool         `json:"free,omitempty"`
 	Stream      bool         `json:"stream,omitempty"`
+	P2P         bool         `json:"p2p,omitempty"`
+	Stealth     bool         `json:"stealth,omitempty"`
 	Premium     bool         `json:"premium,omitempty"`
 	PortForward bool         `json:"port_forward,omitempty"`
+	SOCKSProxy  string       `json:"socks_proxy,omitempty"` // Mullvad SOCKS5 proxy reachable through the tunnel
 	Keep        bool         `json:"keep,omitempty"`
 	IPs         []netip.Addr `json:"ips,omitempty"`
 }
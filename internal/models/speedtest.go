@@ -0,0 +1,14 @@
+package models
+
+// SpeedTestResult contains the results of a speed test.
+type SpeedTestResult struct {
+	// LatencyMilliseconds is the time to the first byte
+	// of the download response, in milliseconds.
+	LatencyMilliseconds float64 `json:"latency_ms"`
+	DownloadBytes       int64   `json:"download_bytes"`
+	DownloadMbps        float64 `json:"download_mbps"`
+	// UploadBytes and UploadMbps are zero if the
+	// upload test is disabled.
+	UploadBytes int64   `json:"upload_bytes"`
+	UploadMbps  float64 `json:"upload_mbps"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// VersionInformation contains the build information of
+// the program running, and whether a newer version is
+// available if the periodic update check is enabled.
+type VersionInformation struct {
+	BuildInformation
+	// UpdateAvailable is true if a newer version is available,
+	// and is nil if no update check completed yet.
+	UpdateAvailable *bool `json:"update_available,omitempty"`
+	// Latest is the latest release tag name, or the latest
+	// commit short hash for the latest image, and is empty
+	// if no update check completed yet.
+	Latest string `json:"latest,omitempty"`
+	// CheckedAt is the time of the last successful update check.
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// VPNEvent is a connection event of the VPN loop.
+type VPNEvent struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	VPNType  string    `json:"vpn_type"`
+	Provider string    `json:"provider"`
+	Server   string    `json:"server,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+const (
+	// VPNEventConnecting is the event type when the VPN
+	// connection is being established to a chosen server.
+	VPNEventConnecting = "connecting"
+	// VPNEventConnected is the event type when the VPN tunnel is up.
+	VPNEventConnected = "connected"
+	// VPNEventDisconnected is the event type when the VPN
+	// connection is terminated, with the reason set.
+	VPNEventDisconnected = "disconnected"
+	// VPNEventFailed is the event type when the VPN connection
+	// fails to be set up or to start, with the reason set.
+	VPNEventFailed = "failed"
+	// VPNEventAuthFailed is the event type when the VPN connection
+	// fails because the credentials are rejected, with the reason set.
+	VPNEventAuthFailed = "auth_failed"
+)
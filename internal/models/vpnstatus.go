@@ -0,0 +1,46 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// VPNStatus is the detailed status of the VPN loop.
+type VPNStatus struct {
+	Status LoopStatus `json:"status"`
+	// Paused is true if the VPN was paused through the control
+	// server and is not resumed yet.
+	Paused   bool   `json:"paused"`
+	VPNType  string `json:"vpn_type,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	// Server is the VPN server currently used, and is
+	// nil if no connection was set up yet.
+	Server   *VPNStatusServer `json:"server,omitempty"`
+	Protocol string           `json:"protocol,omitempty"`
+	// ConnectedSince is the time the VPN tunnel went up,
+	// and is nil if the VPN tunnel is not up.
+	ConnectedSince *time.Time `json:"connected_since,omitempty"`
+	// Uptime is the duration since the VPN tunnel went up.
+	Uptime string `json:"uptime,omitempty"`
+	// LastReconnectReason is the reason of the last
+	// disconnection or failure of the VPN connection.
+	LastReconnectReason string `json:"last_reconnect_reason,omitempty"`
+	// IPv6Leak is the result of the last IPv6 leak check done
+	// when the tunnel went up, and can be "blocked" if there is
+	// no IPv6 egress, "tunneled" if IPv6 egress only goes through
+	// the tunnel, or "leaking" if IPv6 egress bypasses the tunnel.
+	// It is empty if no check completed yet.
+	IPv6Leak string `json:"ipv6_leak,omitempty"`
+}
+
+// VPNStatusServer contains information on the VPN server
+// currently used.
+type VPNStatusServer struct {
+	Name     string     `json:"name,omitempty"`
+	Hostname string     `json:"hostname,omitempty"`
+	Country  string     `json:"country,omitempty"`
+	Region   string     `json:"region,omitempty"`
+	City     string     `json:"city,omitempty"`
+	IP       netip.Addr `json:"ip"`
+	Port     uint16     `json:"port"`
+}
@@ -0,0 +1,107 @@
+// Package nat64 embeds IPv4 addresses in NAT64 IPv6 prefixes as
+// described in RFC 6052, and discovers the NAT64 prefix of the
+// network using DNS64 as described in RFC 7050.
+package nat64
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+var (
+	ErrPrefixNotValid    = errors.New("NAT64 prefix is not valid")
+	ErrPrefixNotDetected = errors.New("NAT64 prefix not detected")
+)
+
+// uOctetIndex is the index of the byte of the IPv6 address
+// which must be left to zero, as defined in RFC 6052.
+const uOctetIndex = 8
+
+// CheckPrefix returns an error if the prefix given is not
+// an IPv6 prefix with a length allowed by RFC 6052.
+func CheckPrefix(prefix netip.Prefix) (err error) {
+	if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+		return fmt.Errorf("%w: %s is not an IPv6 prefix", ErrPrefixNotValid, prefix)
+	}
+	switch prefix.Bits() {
+	case 32, 40, 48, 56, 64, 96: //nolint:gomnd
+		return nil
+	default:
+		return fmt.Errorf("%w: %s must have a length of 32, 40, 48, 56, 64 or 96 bits",
+			ErrPrefixNotValid, prefix)
+	}
+}
+
+// Embed returns the IPv6 address made of the NAT64 prefix and the IPv4
+// address given. If the address given is not an IPv4 address, it is
+// returned as is. The prefix must be valid according to CheckPrefix.
+func Embed(prefix netip.Prefix, ip netip.Addr) (embedded netip.Addr) {
+	if !ip.Is4() {
+		return ip
+	}
+
+	bytes := prefix.Masked().Addr().As16()
+	ipv4 := ip.As4()
+	index := prefix.Bits() / 8 //nolint:gomnd
+	for _, b := range ipv4 {
+		if index == uOctetIndex {
+			index++
+		}
+		bytes[index] = b
+		index++
+	}
+	return netip.AddrFrom16(bytes)
+}
+
+// extract returns the IPv4 address embedded in the IPv6
+// address given, for a NAT64 prefix of the bits given.
+func extract(ip netip.Addr, bits int) (ipv4 netip.Addr) {
+	bytes := ip.As16()
+	var ipv4Bytes [4]byte
+	index := bits / 8 //nolint:gomnd
+	for i := range ipv4Bytes {
+		if index == uOctetIndex {
+			index++
+		}
+		ipv4Bytes[i] = bytes[index]
+		index++
+	}
+	return netip.AddrFrom4(ipv4Bytes)
+}
+
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) (ips []netip.Addr, err error)
+}
+
+// Discover discovers the NAT64 prefix of the network by resolving the
+// IPv6 addresses of the well known name ipv4only.arpa, which are
+// synthesized by DNS64 resolvers from its well known IPv4 addresses.
+func Discover(ctx context.Context, resolver Resolver) (prefix netip.Prefix, err error) {
+	ips, err := resolver.LookupNetIP(ctx, "ip6", "ipv4only.arpa")
+	if err != nil {
+		return prefix, fmt.Errorf("resolving ipv4only.arpa: %w", err)
+	}
+
+	wellKnownIPs := []netip.Addr{
+		netip.AddrFrom4([4]byte{192, 0, 0, 170}), //nolint:gomnd
+		netip.AddrFrom4([4]byte{192, 0, 0, 171}), //nolint:gomnd
+	}
+	prefixBits := []int{96, 64, 56, 48, 40, 32}
+	for _, ip := range ips {
+		if !ip.Is6() || ip.Is4In6() {
+			continue
+		}
+		for _, bits := range prefixBits {
+			extracted := extract(ip, bits)
+			for _, wellKnownIP := range wellKnownIPs {
+				if extracted == wellKnownIP {
+					return netip.PrefixFrom(ip, bits).Masked(), nil
+				}
+			}
+		}
+	}
+
+	return prefix, fmt.Errorf("%w: from IP addresses %v", ErrPrefixNotDetected, ips)
+}
@@ -0,0 +1,103 @@
+package nat64
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Embed(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		prefix   netip.Prefix
+		ip       netip.Addr
+		embedded netip.Addr
+	}{
+		"ipv6_address_unchanged": {
+			prefix:   netip.MustParsePrefix("64:ff9b::/96"),
+			ip:       netip.MustParseAddr("2001:db8::1"),
+			embedded: netip.MustParseAddr("2001:db8::1"),
+		},
+		"well_known_prefix": {
+			prefix:   netip.MustParsePrefix("64:ff9b::/96"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("64:ff9b::c000:221"),
+		},
+		// RFC 6052 section 2.4 examples
+		"32_bits": {
+			prefix:   netip.MustParsePrefix("2001:db8::/32"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("2001:db8:c000:221::"),
+		},
+		"40_bits": {
+			prefix:   netip.MustParsePrefix("2001:db8:100::/40"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("2001:db8:1c0:2:21::"),
+		},
+		"48_bits": {
+			prefix:   netip.MustParsePrefix("2001:db8:122::/48"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("2001:db8:122:c000:2:2100::"),
+		},
+		"56_bits": {
+			prefix:   netip.MustParsePrefix("2001:db8:122:300::/56"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("2001:db8:122:3c0:0:221::"),
+		},
+		"64_bits": {
+			prefix:   netip.MustParsePrefix("2001:db8:122:344::/64"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("2001:db8:122:344:c0:2:2100::"),
+		},
+		"96_bits": {
+			prefix:   netip.MustParsePrefix("2001:db8:122:344::/96"),
+			ip:       netip.MustParseAddr("192.0.2.33"),
+			embedded: netip.MustParseAddr("2001:db8:122:344::192.0.2.33"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			embedded := Embed(testCase.prefix, testCase.ip)
+
+			assert.Equal(t, testCase.embedded, embedded)
+			if testCase.ip.Is4() {
+				extracted := extract(embedded, testCase.prefix.Bits())
+				assert.Equal(t, testCase.ip, extracted)
+			}
+		})
+	}
+}
+
+type fakeResolver struct {
+	ips []netip.Addr
+}
+
+func (r *fakeResolver) LookupNetIP(context.Context, string, string) (
+	ips []netip.Addr, err error) {
+	return r.ips, nil
+}
+
+func Test_Discover(t *testing.T) {
+	t.Parallel()
+
+	resolver := &fakeResolver{ips: []netip.Addr{
+		netip.MustParseAddr("2001:db8:122:344:c0:0:aa00:0"),
+	}}
+
+	prefix, err := Discover(context.Background(), resolver)
+
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("2001:db8:122:344::/64"), prefix)
+
+	resolver.ips = []netip.Addr{netip.MustParseAddr("2001:db8::1")}
+	_, err = Discover(context.Background(), resolver)
+	assert.ErrorIs(t, err, ErrPrefixNotDetected)
+}
@@ -0,0 +1,5 @@
+package notify
+
+type Logger interface {
+	Warn(s string)
+}
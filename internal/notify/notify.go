@@ -0,0 +1,271 @@
+// Package notify sends notifications to Telegram, Discord,
+// generic webhooks and services set with Shoutrrr URLs on
+// critical events.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/notify/shoutrrr"
+)
+
+// Notifier sends notifications on critical events.
+// All its event methods are safe for concurrent use
+// and do not block.
+type Notifier struct {
+	// Fixed parameters
+	settings settings.Notify
+	services []shoutrrr.Service
+	// Objects
+	client *http.Client
+	logger Logger
+	// Internal state
+	messages        chan string
+	retry           chan struct{}
+	stateMutex      sync.Mutex
+	tunnelDownTimer *time.Timer
+	tunnelDownSent  bool
+	authFailures    uint8
+}
+
+func New(settings settings.Notify, client *http.Client,
+	logger Logger) *Notifier {
+	const messagesBufferSize = 16
+	return &Notifier{
+		settings: settings,
+		services: makeServices(settings, logger),
+		client:   client,
+		logger:   logger,
+		messages: make(chan string, messagesBufferSize),
+		retry:    make(chan struct{}, 1),
+	}
+}
+
+func makeServices(settings settings.Notify, logger Logger) (
+	services []shoutrrr.Service) {
+	if *settings.TelegramToken != "" {
+		services = append(services, shoutrrr.NewTelegram(*settings.TelegramToken,
+			[]string{*settings.TelegramChatID}))
+	}
+
+	if *settings.DiscordWebhookURL != "" {
+		services = append(services, shoutrrr.NewDiscord(*settings.DiscordWebhookURL))
+	}
+
+	if *settings.WebhookURL != "" {
+		services = append(services, shoutrrr.NewGeneric(*settings.WebhookURL))
+	}
+
+	for i, url := range settings.URLs {
+		service, err := shoutrrr.Parse(url)
+		if err != nil { // URLs are validated in the settings
+			logger.Warn(fmt.Sprintf("notification URL %d: %s", i+1, err))
+			continue
+		}
+		services = append(services, service)
+	}
+
+	return services
+}
+
+// TunnelUp signals the VPN tunnel is up. It cancels any pending
+// tunnel down notification, retries notifications which failed
+// to be sent, such as the tunnel down notification, and sends a
+// recovery notification if a tunnel down notification was sent before.
+func (n *Notifier) TunnelUp() {
+	n.stateMutex.Lock()
+	defer n.stateMutex.Unlock()
+
+	select {
+	case n.retry <- struct{}{}:
+	default: // retry already signaled
+	}
+
+	n.authFailures = 0
+	if n.tunnelDownTimer != nil {
+		n.tunnelDownTimer.Stop()
+		n.tunnelDownTimer = nil
+	}
+
+	if n.tunnelDownSent {
+		n.tunnelDownSent = false
+		n.enqueue("VPN tunnel is back up")
+	}
+}
+
+// TunnelDown signals the VPN tunnel is down. A notification is
+// sent if TunnelUp is not called within the tunnel down duration
+// configured. Calling it again while the tunnel is down has no effect.
+func (n *Notifier) TunnelDown() {
+	n.stateMutex.Lock()
+	defer n.stateMutex.Unlock()
+
+	if n.tunnelDownTimer != nil || n.tunnelDownSent {
+		return
+	}
+
+	downAfter := n.settings.TunnelDownAfter
+	n.tunnelDownTimer = time.AfterFunc(downAfter, func() {
+		n.stateMutex.Lock()
+		defer n.stateMutex.Unlock()
+		if n.tunnelDownTimer == nil { // tunnel up in the meantime
+			return
+		}
+		n.tunnelDownTimer = nil
+		n.tunnelDownSent = true
+		n.enqueue("VPN tunnel has been down for more than " + downAfter.String())
+	})
+}
+
+// AuthFailed signals the VPN authentication failed. A notification
+// is sent once the number of consecutive authentication failures
+// reaches the threshold configured.
+func (n *Notifier) AuthFailed() {
+	n.stateMutex.Lock()
+	defer n.stateMutex.Unlock()
+
+	if n.authFailures == n.settings.AuthFailures {
+		return // already notified
+	}
+
+	n.authFailures++
+	if n.authFailures == n.settings.AuthFailures {
+		n.enqueue(fmt.Sprintf("VPN authentication failed %d times in a row, "+
+			"your credentials might be wrong", n.authFailures))
+	}
+}
+
+// PortForwardingLost signals port forwarding failed.
+func (n *Notifier) PortForwardingLost(err error) {
+	n.enqueue("port forwarding lost: " + err.Error())
+}
+
+// UpdaterFailed signals the servers updater failed.
+func (n *Notifier) UpdaterFailed(err error) {
+	n.enqueue("servers update failed: " + err.Error())
+}
+
+// UpdateAvailable signals a newer version is available.
+func (n *Notifier) UpdateAvailable(latest string) {
+	n.enqueue("a newer version of gluetun is available: " + latest)
+}
+
+func (n *Notifier) enqueue(message string) {
+	if len(n.services) == 0 {
+		return
+	}
+
+	select {
+	case n.messages <- message:
+	default:
+		n.logger.Warn("notification dropped: too many notifications pending: " + message)
+	}
+}
+
+// failedMessage is a message which failed to be sent
+// to some services, to be sent to them again later.
+type failedMessage struct {
+	message  string
+	services []shoutrrr.Service
+}
+
+// Run sends notifications queued until the context is canceled.
+// Notifications which fail to be sent, for example because the VPN
+// tunnel is down, are retried with an exponential backoff, when the
+// tunnel is back up, and before sending any new notification.
+func (n *Notifier) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	const initialBackoff, maxBackoff = 10 * time.Second, 10 * time.Minute
+	backoff := initialBackoff
+	retryTimer := time.NewTimer(time.Hour)
+	stopTimer(retryTimer)
+
+	var failed []failedMessage
+	for {
+		select {
+		case <-ctx.Done():
+			stopTimer(retryTimer)
+			return
+		case message := <-n.messages:
+			failed = n.retryFailed(ctx, failed)
+			failed = n.sendToServices(ctx, failed, failedMessage{
+				message:  message,
+				services: n.services,
+			})
+		case <-n.retry:
+			backoff = initialBackoff
+			failed = n.retryFailed(ctx, failed)
+		case <-retryTimer.C:
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			failed = n.retryFailed(ctx, failed)
+		}
+
+		stopTimer(retryTimer)
+		if len(failed) == 0 {
+			backoff = initialBackoff
+			continue
+		}
+		retryTimer.Reset(backoff)
+	}
+}
+
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
+
+// retryFailed sends the failed messages given again, in order,
+// and returns the messages which failed again.
+func (n *Notifier) retryFailed(ctx context.Context,
+	failed []failedMessage) (stillFailed []failedMessage) {
+	for _, message := range failed {
+		stillFailed = n.sendToServices(ctx, stillFailed, message)
+	}
+	return stillFailed
+}
+
+// sendToServices sends the message to each of its services, and
+// appends the message with the services it failed to be sent to
+// to the failed messages given, if any.
+func (n *Notifier) sendToServices(ctx context.Context, failed []failedMessage,
+	message failedMessage) (updatedFailed []failedMessage) {
+	const sendTimeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	var failedServices []shoutrrr.Service
+	for _, service := range message.services {
+		err := service.Send(ctx, n.client, message.message)
+		if err != nil {
+			n.logger.Warn("sending " + service.Name() + " notification: " + err.Error())
+			failedServices = append(failedServices, service)
+		}
+	}
+
+	if len(failedServices) == 0 {
+		return failed
+	}
+
+	const maxFailed = 16
+	if len(failed) == maxFailed {
+		n.logger.Warn("notification dropped: too many notifications failed: " + failed[0].message)
+		failed = failed[1:]
+	}
+	return append(failed, failedMessage{
+		message:  message.message,
+		services: failedServices,
+	})
+}
@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptrTo[T any](value T) *T { return &value }
+
+type noopLogger struct{}
+
+func (noopLogger) Warn(string) {}
+
+func newTestNotifier(webhookURL string) *Notifier {
+	return New(settings.Notify{
+		TelegramToken:     ptrTo(""),
+		TelegramChatID:    ptrTo(""),
+		DiscordWebhookURL: ptrTo(""),
+		WebhookURL:        ptrTo(webhookURL),
+		TunnelDownAfter:   time.Millisecond,
+		AuthFailures:      2,
+	}, http.DefaultClient, noopLogger{})
+}
+
+func Test_Notifier_events(t *testing.T) {
+	t.Parallel()
+
+	notifier := newTestNotifier("http://localhost")
+
+	notifier.AuthFailed()
+	assert.Empty(t, notifier.messages)
+	notifier.AuthFailed()
+	assert.Equal(t, "VPN authentication failed 2 times in a row, "+
+		"your credentials might be wrong", <-notifier.messages)
+	notifier.AuthFailed()
+	assert.Empty(t, notifier.messages)
+
+	notifier.TunnelDown()
+	assert.Equal(t, "VPN tunnel has been down for more than 1ms", <-notifier.messages)
+	notifier.TunnelDown()
+	notifier.TunnelUp()
+	assert.Equal(t, "VPN tunnel is back up", <-notifier.messages)
+	assert.Empty(t, notifier.messages)
+}
+
+func Test_Notifier_Run(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		err := json.NewDecoder(r.Body).Decode(&body)
+		assert.NoError(t, err)
+		w.WriteHeader(http.StatusNoContent)
+		bodies <- body
+	}))
+	t.Cleanup(server.Close)
+
+	notifier := newTestNotifier(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go notifier.Run(ctx, done)
+
+	notifier.UpdaterFailed(assert.AnError)
+	body := <-bodies
+	cancel()
+	<-done
+
+	require.Contains(t, body, "message")
+	assert.Equal(t, "servers update failed: "+assert.AnError.Error(), body["message"])
+	assert.Equal(t, "gluetun", body["source"])
+}
+
+func Test_Notifier_Run_retryFailed(t *testing.T) {
+	t.Parallel()
+
+	messages := make(chan string, 3) //nolint:gomnd
+	firstFailed := make(chan struct{})
+	failFirst := true // only accessed by the sequential requests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		err := json.NewDecoder(r.Body).Decode(&body)
+		assert.NoError(t, err)
+		if failFirst { // tunnel down
+			failFirst = false
+			w.WriteHeader(http.StatusBadGateway)
+			close(firstFailed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		messages <- body["message"].(string) //nolint:forcetypeassert
+	}))
+	t.Cleanup(server.Close)
+
+	notifier := newTestNotifier(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go notifier.Run(ctx, done)
+
+	notifier.TunnelDown()
+	<-firstFailed
+	notifier.TunnelUp()
+
+	assert.Equal(t, "VPN tunnel has been down for more than 1ms", <-messages)
+	assert.Equal(t, "VPN tunnel is back up", <-messages)
+	cancel()
+	<-done
+	assert.Empty(t, messages)
+}
@@ -0,0 +1,52 @@
+package shoutrrr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+var (
+	ErrDiscordTokenMissing     = errors.New("Discord webhook token is missing")
+	ErrDiscordWebhookIDMissing = errors.New("Discord webhook ID is missing")
+)
+
+type discord struct {
+	webhookURL string
+}
+
+// NewDiscord returns a service sending messages
+// to the Discord webhook URL given.
+func NewDiscord(webhookURL string) Service {
+	return &discord{
+		webhookURL: webhookURL,
+	}
+}
+
+// parseDiscord parses an URL such as discord://token@webhookid
+func parseDiscord(u *url.URL) (service Service, err error) {
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("%w", ErrDiscordTokenMissing)
+	} else if u.Host == "" {
+		return nil, fmt.Errorf("%w", ErrDiscordWebhookIDMissing)
+	}
+	webhookURL := "https://discord.com/api/webhooks/" +
+		u.Host + "/" + u.User.Username()
+	return NewDiscord(webhookURL), nil
+}
+
+func (d *discord) Name() string { return "Discord" }
+
+func (d *discord) Send(ctx context.Context, client *http.Client,
+	message string) (err error) {
+	body := struct {
+		Username string `json:"username"`
+		Content  string `json:"content"`
+	}{
+		Username: "gluetun",
+		Content:  message,
+	}
+	return postJSON(ctx, client, d.webhookURL, body)
+}
@@ -0,0 +1,55 @@
+package shoutrrr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var ErrGenericHostMissing = errors.New("generic webhook host is missing")
+
+type generic struct {
+	url string
+}
+
+// NewGeneric returns a service sending messages as JSON
+// encoded HTTP POST requests to the URL given.
+func NewGeneric(url string) Service {
+	return &generic{
+		url: url,
+	}
+}
+
+// parseGeneric parses an URL such as generic://example.com/path
+// which is sent to over HTTPS, or generic+http://example.com/path
+// which is sent to over plaintext HTTP.
+func parseGeneric(u *url.URL) (service Service, err error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w", ErrGenericHostMissing)
+	}
+	webhookURL := *u
+	webhookURL.Scheme = "https"
+	if u.Scheme == "generic+http" {
+		webhookURL.Scheme = "http"
+	}
+	return NewGeneric(webhookURL.String()), nil
+}
+
+func (g *generic) Name() string { return "webhook" }
+
+func (g *generic) Send(ctx context.Context, client *http.Client,
+	message string) (err error) {
+	body := struct {
+		Source  string    `json:"source"`
+		Message string    `json:"message"`
+		Time    time.Time `json:"time"`
+	}{
+		Source:  "gluetun",
+		Message: message,
+		Time:    time.Now(),
+	}
+	return postJSON(ctx, client, g.url, body)
+}
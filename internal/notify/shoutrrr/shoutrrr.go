@@ -0,0 +1,131 @@
+// Package shoutrrr parses notification service URLs written in the
+// Shoutrrr URL notation, such as slack://token-a/token-b/token-c,
+// and sends messages to these services.
+package shoutrrr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Service is a notification service messages can be sent to.
+type Service interface {
+	// Name returns the name of the service, which
+	// contains no secret and can be logged.
+	Name() string
+	Send(ctx context.Context, client *http.Client, message string) (err error)
+}
+
+var ErrServiceNotSupported = errors.New("notification service is not supported")
+
+// Parse parses the Shoutrrr URL given into a notification service.
+// Errors returned do not contain the URL since it usually contains
+// secrets such as tokens or passwords.
+func Parse(rawURL string) (service Service, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		urlErr := new(url.Error)
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("parsing URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "discord":
+		return parseDiscord(u)
+	case "generic", "generic+http", "generic+https":
+		return parseGeneric(u)
+	case "matrix":
+		return parseMatrix(u)
+	case "pushover":
+		return parsePushover(u)
+	case "slack":
+		return parseSlack(u)
+	case "smtp":
+		return parseSMTP(u)
+	case "telegram":
+		return parseTelegram(u)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrServiceNotSupported, u.Scheme)
+	}
+}
+
+// queryCSV returns the comma separated values of the query parameter given.
+func queryCSV(u *url.URL, key string) (values []string) {
+	csv := u.Query().Get(key)
+	if csv == "" {
+		return nil
+	}
+	values = strings.Split(csv, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+var ErrHTTPStatusCodeNotOK = errors.New("HTTP status code is not OK")
+
+func postJSON(ctx context.Context, client *http.Client,
+	url string, body any) (err error) {
+	return sendJSON(ctx, client, http.MethodPost, url, nil, body, nil)
+}
+
+// sendJSON sends the body given JSON encoded to the URL given and,
+// if result is not nil, decodes the JSON response body into it.
+func sendJSON(ctx context.Context, client *http.Client, method, url string,
+	header http.Header, body, result any) (err error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request body: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	return do(client, request, result)
+}
+
+// do sends the request given, checks the response status code and,
+// if result is not nil, decodes the JSON response body into it.
+func do(client *http.Client, request *http.Request, result any) (err error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		return fmt.Errorf("%w: %d %s: %s", ErrHTTPStatusCodeNotOK,
+			response.StatusCode, response.Status, string(responseBody))
+	}
+
+	if result != nil {
+		err = json.NewDecoder(response.Body).Decode(result)
+		if err != nil {
+			_ = response.Body.Close()
+			return fmt.Errorf("decoding response body: %w", err)
+		}
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("closing response body: %w", err)
+	}
+
+	return nil
+}
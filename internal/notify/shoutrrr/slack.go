@@ -0,0 +1,57 @@
+package shoutrrr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrSlackTokenNotValid = errors.New("Slack token is not valid")
+
+type slack struct {
+	webhookURL string
+	botName    string
+}
+
+// parseSlack parses an URL such as
+// slack://[botname@]token-a/token-b/token-c
+func parseSlack(u *url.URL) (service Service, err error) {
+	tokens := append([]string{u.Host}, strings.Split(strings.Trim(u.Path, "/"), "/")...)
+	const tokenParts = 3
+	if len(tokens) != tokenParts {
+		return nil, fmt.Errorf("%w: must be in the form token-a/token-b/token-c",
+			ErrSlackTokenNotValid)
+	}
+	for _, token := range tokens {
+		if token == "" {
+			return nil, fmt.Errorf("%w: token part is empty", ErrSlackTokenNotValid)
+		}
+	}
+
+	botName := "gluetun"
+	if u.User != nil && u.User.Username() != "" {
+		botName = u.User.Username()
+	}
+
+	return &slack{
+		webhookURL: "https://hooks.slack.com/services/" + strings.Join(tokens, "/"),
+		botName:    botName,
+	}, nil
+}
+
+func (s *slack) Name() string { return "Slack" }
+
+func (s *slack) Send(ctx context.Context, client *http.Client,
+	message string) (err error) {
+	body := struct {
+		Username string `json:"username"`
+		Text     string `json:"text"`
+	}{
+		Username: s.botName,
+		Text:     message,
+	}
+	return postJSON(ctx, client, s.webhookURL, body)
+}
@@ -0,0 +1,70 @@
+package shoutrrr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+var (
+	ErrTelegramTokenMissing = errors.New("Telegram token is missing")
+	ErrTelegramChatsMissing = errors.New("Telegram chats are missing")
+)
+
+type telegram struct {
+	token   string
+	chatIDs []string
+}
+
+// NewTelegram returns a service sending messages with the
+// Telegram bot token given to each of the chat IDs given.
+func NewTelegram(token string, chatIDs []string) Service {
+	return &telegram{
+		token:   token,
+		chatIDs: chatIDs,
+	}
+}
+
+// parseTelegram parses an URL such as
+// telegram://token@telegram?chats=@channel-1,chat-id-1
+func parseTelegram(u *url.URL) (service Service, err error) {
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("%w", ErrTelegramTokenMissing)
+	}
+	// The token is in the form 110201543:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw
+	// so it is split in the URL username and password.
+	token := u.User.Username()
+	if password, set := u.User.Password(); set {
+		token += ":" + password
+	}
+
+	chatIDs := queryCSV(u, "chats")
+	if len(chatIDs) == 0 {
+		return nil, fmt.Errorf("%w", ErrTelegramChatsMissing)
+	}
+
+	return NewTelegram(token, chatIDs), nil
+}
+
+func (t *telegram) Name() string { return "Telegram" }
+
+func (t *telegram) Send(ctx context.Context, client *http.Client,
+	message string) (err error) {
+	url := "https://api.telegram.org/bot" + t.token + "/sendMessage"
+	for _, chatID := range t.chatIDs {
+		body := struct {
+			ChatID string `json:"chat_id"`
+			Text   string `json:"text"`
+		}{
+			ChatID: chatID,
+			Text:   "gluetun: " + message,
+		}
+		err = postJSON(ctx, client, url, body)
+		if err != nil {
+			return fmt.Errorf("chat %s: %w", chatID, err)
+		}
+	}
+	return nil
+}
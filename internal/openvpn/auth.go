@@ -6,49 +6,46 @@ import (
 	"strings"
 )
 
-// WriteAuthFile writes the OpenVPN auth file to disk with the right permissions.
-func (c *Configurator) WriteAuthFile(user, password string) error {
+// WriteAuthFile writes the OpenVPN auth file to disk with the
+// right permissions, and returns its file path.
+func (c *Configurator) WriteAuthFile(user, password string) (path string, err error) {
 	content := strings.Join([]string{user, password}, "\n")
-	return writeIfDifferent(c.authFilePath, content, c.puid, c.pgid)
+	return c.authFilePath, c.writeIfDifferent(c.authFilePath, content)
 }
 
-// WriteAskPassFile writes the OpenVPN askpass file to disk with the right permissions.
-func (c *Configurator) WriteAskPassFile(passphrase string) error {
-	return writeIfDifferent(c.askPassPath, passphrase, c.puid, c.pgid)
+// WriteAskPassFile writes the OpenVPN askpass file to disk with the
+// right permissions, and returns its file path.
+func (c *Configurator) WriteAskPassFile(passphrase string) (path string, err error) {
+	return c.askPassPath, c.writeIfDifferent(c.askPassPath, passphrase)
 }
 
-func writeIfDifferent(path, content string, puid, pgid int) (err error) {
+// WriteSocksAuthFile writes the OpenVPN SOCKS proxy credentials file
+// to disk with the right permissions, and returns its file path.
+func (c *Configurator) WriteSocksAuthFile(user, password string) (path string, err error) {
+	content := strings.Join([]string{user, password}, "\n")
+	return c.socksAuthPath, c.writeIfDifferent(c.socksAuthPath, content)
+}
+
+func (c *Configurator) writeIfDifferent(path, content string) (err error) {
 	fileStat, err := os.Stat(path)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("obtaining file information: %w", err)
 	}
 
 	const perm = os.FileMode(0400)
-	var writeData, setChown bool
-	if os.IsNotExist(err) {
-		writeData = true
-		setChown = true
-	} else {
+	if !os.IsNotExist(err) {
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return fmt.Errorf("reading file: %w", err)
 		}
-		writeData = string(data) != content
-		setChown = fileStat.Mode().Perm() != perm
-	}
-
-	if writeData {
-		err = os.WriteFile(path, []byte(content), perm)
-		if err != nil {
-			return fmt.Errorf("writing file: %w", err)
+		if string(data) == content && fileStat.Mode().Perm() == perm {
+			return nil
 		}
 	}
 
-	if setChown {
-		err = os.Chown(path, puid, pgid)
-		if err != nil {
-			return fmt.Errorf("setting file permissions: %w", err)
-		}
+	err = c.fileWriter.WriteFile(path, []byte(content), perm)
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
 	}
 
 	return nil
@@ -1,26 +1,14 @@
 package openvpn
 
 import (
-	"os"
 	"strings"
 )
 
-func (c *Configurator) WriteConfig(lines []string) error {
-	file, err := os.OpenFile(c.configPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	_, err = file.WriteString(strings.Join(lines, "\n"))
-	if err != nil {
-		_ = file.Close()
-		return err
-	}
-
-	err = file.Chown(c.puid, c.pgid)
-	if err != nil {
-		_ = file.Close()
-		return err
-	}
-
-	return file.Close()
+// WriteConfig writes the OpenVPN configuration lines to disk
+// with the right permissions, and returns its file path.
+func (c *Configurator) WriteConfig(lines []string) (path string, err error) {
+	// The configuration can contain inline keys,
+	// so it is only readable by its owner.
+	const perm = 0600
+	return c.configPath, c.fileWriter.WriteFile(c.configPath, []byte(strings.Join(lines, "\n")), perm)
 }
@@ -0,0 +1,22 @@
+package extract
+
+import (
+	"strings"
+)
+
+// StaticKey extracts the hex encoded data of an OpenVPN static
+// key V1 file content, ignoring comment lines and the BEGIN and
+// END marker lines.
+func StaticKey(b []byte) (hexData string) {
+	lines := strings.Split(string(b), "\n")
+	var builder strings.Builder
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, "-----") {
+			continue
+		}
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
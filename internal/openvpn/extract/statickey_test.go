@@ -0,0 +1,24 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StaticKey(t *testing.T) {
+	t.Parallel()
+
+	const content = `#
+# 2048 bit OpenVPN static key
+#
+-----BEGIN OpenVPN Static key V1-----
+0123abcd
+4567ef89
+-----END OpenVPN Static key V1-----
+`
+
+	hexData := StaticKey([]byte(content))
+
+	assert.Equal(t, "0123abcd4567ef89", hexData)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/qdm12/gluetun/internal/constants"
 )
 
+const authFailedLine = "AUTH: Received control message: AUTH_FAILED"
+
 type logLevel uint8
 
 const (
@@ -39,7 +41,7 @@ func processLogLine(s string) (filtered string, level logLevel) {
 		level = levelError
 	case s == "Initialization Sequence Completed":
 		return color.HiGreenString(s), levelInfo
-	case s == "AUTH: Received control message: AUTH_FAILED":
+	case s == authFailedLine:
 		filtered = s + `
 
 Your credentials might be wrong 🤨
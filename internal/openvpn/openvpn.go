@@ -6,23 +6,24 @@ import (
 )
 
 type Configurator struct {
-	logger       Infoer
-	cmder        command.RunStarter
-	configPath   string
-	authFilePath string
-	askPassPath  string
-	puid, pgid   int
+	logger        Infoer
+	cmder         command.RunStarter
+	configPath    string
+	authFilePath  string
+	askPassPath   string
+	socksAuthPath string
+	fileWriter    FileWriter
 }
 
 func New(logger Infoer, cmder command.RunStarter,
-	puid, pgid int) *Configurator {
+	fileWriter FileWriter, runtimeDirectory string) *Configurator {
 	return &Configurator{
-		logger:       logger,
-		cmder:        cmder,
-		configPath:   configPath,
-		authFilePath: openvpn.AuthConf,
-		askPassPath:  openvpn.AskPassPath,
-		puid:         puid,
-		pgid:         pgid,
+		logger:        logger,
+		cmder:         cmder,
+		configPath:    ConfigPath(runtimeDirectory),
+		authFilePath:  openvpn.AuthConf(runtimeDirectory),
+		askPassPath:   openvpn.AskPassPath(runtimeDirectory),
+		socksAuthPath: openvpn.SocksAuthConf(runtimeDirectory),
+		fileWriter:    fileWriter,
 	}
 }
@@ -1,3 +1,9 @@
 package openvpn
 
-const configPath = "/etc/openvpn/target.ovpn"
+import "path/filepath"
+
+// ConfigPath returns the file path of the OpenVPN
+// configuration in the runtime directory given.
+func ConfigPath(runtimeDirectory string) string {
+	return filepath.Join(runtimeDirectory, "openvpn", "target.ovpn")
+}
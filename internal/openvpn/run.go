@@ -2,28 +2,37 @@ package openvpn
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/golibs/command"
 )
 
+// ErrAuthFailed is wrapped in the error returned when OpenVPN
+// exits after the server rejected the authentication.
+var ErrAuthFailed = errors.New("authentication failed")
+
 type Runner struct {
-	settings settings.OpenVPN
-	starter  command.Starter
-	logger   Logger
+	settings   settings.OpenVPN
+	configPath string
+	starter    command.Starter
+	logger     Logger
 }
 
-func NewRunner(settings settings.OpenVPN, starter command.Starter,
-	logger Logger) *Runner {
+func NewRunner(settings settings.OpenVPN, configPath string,
+	starter command.Starter, logger Logger) *Runner {
 	return &Runner{
-		starter:  starter,
-		logger:   logger,
-		settings: settings,
+		starter:    starter,
+		logger:     logger,
+		settings:   settings,
+		configPath: configPath,
 	}
 }
 
 func (r *Runner) Run(ctx context.Context, errCh chan<- error, ready chan<- struct{}) {
-	stdoutLines, stderrLines, waitError, err := start(ctx, r.starter, r.settings.Version, r.settings.Flags)
+	stdoutLines, stderrLines, waitError, err := start(ctx, r.starter, r.settings.Version,
+		r.configPath, r.settings.Flags)
 	if err != nil {
 		errCh <- err
 		return
@@ -31,8 +40,9 @@ func (r *Runner) Run(ctx context.Context, errCh chan<- error, ready chan<- struc
 
 	streamCtx, streamCancel := context.WithCancel(context.Background())
 	streamDone := make(chan struct{})
+	authFailed := make(chan struct{}, 1)
 	go streamLines(streamCtx, streamDone, r.logger,
-		stdoutLines, stderrLines, ready)
+		stdoutLines, stderrLines, ready, authFailed)
 
 	select {
 	case <-ctx.Done():
@@ -45,6 +55,11 @@ func (r *Runner) Run(ctx context.Context, errCh chan<- error, ready chan<- struc
 		close(waitError)
 		streamCancel()
 		<-streamDone
+		select {
+		case <-authFailed:
+			err = fmt.Errorf("%w: %s", ErrAuthFailed, err)
+		default:
+		}
 		errCh <- err
 	}
 }
@@ -0,0 +1,179 @@
+// Package sanitize cleans arbitrary OpenVPN configuration files
+// so they can be used as custom configuration files with gluetun.
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Action is the action taken on a directive of the configuration.
+type Action string
+
+const (
+	// ActionKept is for directives kept as they are.
+	ActionKept Action = "kept"
+	// ActionOverridden is for directives kept in the configuration
+	// but overridden by gluetun at runtime.
+	ActionOverridden Action = "overridden"
+	// ActionRemoved is for directives not supported by gluetun,
+	// which are removed from the configuration.
+	ActionRemoved Action = "removed"
+	// ActionExtracted is for inline blocks extracted to files.
+	ActionExtracted Action = "extracted"
+)
+
+// Directive is a report entry for a directive of the configuration.
+type Directive struct {
+	Line   int
+	Name   string
+	Action Action
+	Reason string
+}
+
+// File is a file extracted from an inline block of the configuration.
+type File struct {
+	Name    string
+	Content string
+}
+
+// Result is the result of sanitizing an OpenVPN configuration.
+type Result struct {
+	// Lines are the lines of the sanitized configuration.
+	Lines []string
+	// Files are the files extracted from inline blocks.
+	Files []File
+	// Directives is a report of the actions taken on each directive.
+	Directives []Directive
+	// AuthUserPass is true if the configuration requires
+	// a username and password.
+	AuthUserPass bool
+}
+
+var (
+	ErrInlineBlockNotClosed = errors.New("inline block is not closed")
+	ErrRemoteNotFound       = errors.New("remote directive not found")
+)
+
+// Sanitize sanitizes the OpenVPN configuration lines given.
+// Inline blocks are extracted to files and replaced by
+// directives referencing these files in the directory given.
+func Sanitize(lines []string, filesDirectory string) (result Result, err error) {
+	remoteFound := false
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if tag, ok := openingTag(line); ok {
+			closing := "</" + tag + ">"
+			blockLines := []string{}
+			closed := false
+			for i++; i < len(lines); i++ {
+				blockLine := strings.TrimSpace(lines[i])
+				if blockLine == closing {
+					closed = true
+					break
+				}
+				blockLines = append(blockLines, blockLine)
+			}
+			if !closed {
+				return result, fmt.Errorf("%w: %s starting on line %d",
+					ErrInlineBlockNotClosed, tag, lineNumber)
+			}
+
+			filename := inlineFilename(tag)
+			result.Files = append(result.Files, File{
+				Name:    filename,
+				Content: strings.Join(blockLines, "\n") + "\n",
+			})
+			result.Lines = append(result.Lines, tag+" "+path.Join(filesDirectory, filename))
+			result.Directives = append(result.Directives, Directive{
+				Line:   lineNumber,
+				Name:   tag,
+				Action: ActionExtracted,
+				Reason: "inline block written to " + filename,
+			})
+			continue
+		}
+
+		name := strings.Fields(line)[0]
+		action, reason := classify(name)
+		switch name {
+		case "remote":
+			remoteFound = true
+		case "auth-user-pass":
+			result.AuthUserPass = true
+		}
+		result.Directives = append(result.Directives, Directive{
+			Line:   lineNumber,
+			Name:   name,
+			Action: action,
+			Reason: reason,
+		})
+		if action != ActionRemoved {
+			result.Lines = append(result.Lines, line)
+		}
+	}
+
+	if !remoteFound {
+		return result, fmt.Errorf("%w", ErrRemoteNotFound)
+	}
+
+	result.Lines = append(result.Lines, "") // trailing line
+	return result, nil
+}
+
+func openingTag(line string) (tag string, ok bool) {
+	if !strings.HasPrefix(line, "<") || !strings.HasSuffix(line, ">") ||
+		strings.HasPrefix(line, "</") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(line, "<"), ">"), true
+}
+
+func inlineFilename(tag string) (filename string) {
+	switch tag {
+	case "ca":
+		return "ca.crt"
+	case "cert":
+		return "client.crt"
+	case "key":
+		return "client.key"
+	case "extra-certs":
+		return "extra-certs.crt"
+	case "dh", "crl-verify":
+		return tag + ".pem"
+	default:
+		return tag + ".key"
+	}
+}
+
+func classify(name string) (action Action, reason string) {
+	switch name {
+	case "remote", "proto":
+		return ActionOverridden, "only the first remote and protocol are used"
+	case "dev", "verb", "auth-user-pass", "user", "group",
+		"mute-replay-warnings", "auth-nocache", "auth-retry",
+		"suppress-timestamps", "persist-tun", "persist-key":
+		return ActionOverridden, "set by gluetun"
+	case "up", "down", "route-up", "route-pre-down", "ipchange",
+		"script-security", "auth-user-pass-verify", "tls-verify":
+		return ActionRemoved, "scripts are not supported"
+	case "daemon", "log", "log-append", "status", "writepid",
+		"management", "management-hold", "management-query-passwords":
+		return ActionRemoved, "gluetun manages the OpenVPN process"
+	case "block-outside-dns", "register-dns", "ip-win32", "route-method",
+		"route-delay", "tap-sleep", "dhcp-renew", "dhcp-release", "win-sys",
+		"service", "cryptoapicert", "show-net-up":
+		return ActionRemoved, "Windows only option"
+	case "dhcp-option":
+		return ActionRemoved, "gluetun manages DNS"
+	default:
+		return ActionKept, ""
+	}
+}
@@ -0,0 +1,87 @@
+package sanitize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		lines      []string
+		result     Result
+		errWrapped error
+		errMessage string
+	}{
+		"remote_missing": {
+			lines:      []string{"client"},
+			errWrapped: ErrRemoteNotFound,
+			errMessage: "remote directive not found",
+		},
+		"inline_block_not_closed": {
+			lines:      []string{"<ca>", "abc"},
+			errWrapped: ErrInlineBlockNotClosed,
+			errMessage: "inline block is not closed: ca starting on line 1",
+		},
+		"full": {
+			lines: []string{
+				"# comment",
+				"client",
+				"remote 1.2.3.4 1194",
+				"auth-user-pass",
+				"script-security 2",
+				"up /etc/openvpn/update-resolv-conf",
+				"block-outside-dns",
+				"<ca>",
+				"-----BEGIN CERTIFICATE-----",
+				"-----END CERTIFICATE-----",
+				"</ca>",
+				"",
+			},
+			result: Result{
+				Lines: []string{
+					"client",
+					"remote 1.2.3.4 1194",
+					"auth-user-pass",
+					"ca /gluetun/ca.crt",
+					"",
+				},
+				Files: []File{{
+					Name:    "ca.crt",
+					Content: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n",
+				}},
+				Directives: []Directive{
+					{Line: 2, Name: "client", Action: ActionKept},
+					{Line: 3, Name: "remote", Action: ActionOverridden,
+						Reason: "only the first remote and protocol are used"},
+					{Line: 4, Name: "auth-user-pass", Action: ActionOverridden, Reason: "set by gluetun"},
+					{Line: 5, Name: "script-security", Action: ActionRemoved, Reason: "scripts are not supported"},
+					{Line: 6, Name: "up", Action: ActionRemoved, Reason: "scripts are not supported"},
+					{Line: 7, Name: "block-outside-dns", Action: ActionRemoved, Reason: "Windows only option"},
+					{Line: 8, Name: "ca", Action: ActionExtracted, Reason: "inline block written to ca.crt"},
+				},
+				AuthUserPass: true,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := Sanitize(testCase.lines, "/gluetun")
+
+			assert.True(t, errors.Is(err, testCase.errWrapped))
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.result, result)
+		})
+	}
+}
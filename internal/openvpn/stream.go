@@ -7,7 +7,7 @@ import (
 
 func streamLines(ctx context.Context, done chan<- struct{},
 	logger Logger, stdout, stderr chan string,
-	tunnelReady chan<- struct{}) {
+	tunnelReady, authFailed chan<- struct{}) {
 	defer close(done)
 
 	var line string
@@ -25,6 +25,12 @@ func streamLines(ctx context.Context, done chan<- struct{},
 		case line = <-stderr:
 			errLine = true
 		}
+		if line == authFailedLine {
+			select {
+			case authFailed <- struct{}{}:
+			default: // already signaled
+			}
+		}
 		line, level := processLogLine(line)
 		if line == "" {
 			continue // filtered out
@@ -0,0 +1,142 @@
+// Package outbound resolves outbound hostnames periodically and
+// keeps the firewall and routing outbound subnets up to date with
+// the static outbound subnets and the IP addresses resolved.
+package outbound
+
+import (
+	"context"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) (
+		ips []netip.Addr, err error)
+}
+
+type Firewall interface {
+	SetOutboundSubnets(ctx context.Context, subnets []netip.Prefix) (err error)
+}
+
+type Routing interface {
+	SetOutboundRoutes(outboundSubnets []netip.Prefix) error
+}
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+}
+
+type Updater struct {
+	subnets   []netip.Prefix
+	hostnames []string
+	period    time.Duration
+	resolver  Resolver
+	firewall  Firewall
+	routing   Routing
+	logger    Logger
+	// resolved maps each hostname to its last resolved
+	// IP addresses, which are kept if resolving fails.
+	resolved map[string][]netip.Addr
+}
+
+func New(settings settings.Firewall, resolver Resolver,
+	firewall Firewall, routing Routing, logger Logger) *Updater {
+	return &Updater{
+		subnets:   settings.OutboundSubnets,
+		hostnames: settings.OutboundHostnames,
+		period:    *settings.OutboundHostnamesPeriod,
+		resolver:  resolver,
+		firewall:  firewall,
+		routing:   routing,
+		logger:    logger,
+		resolved:  make(map[string][]netip.Addr, len(settings.OutboundHostnames)),
+	}
+}
+
+// Update resolves the outbound hostnames and sets the outbound subnets
+// of the firewall and routing. A hostname failing to resolve keeps its
+// previously resolved IP addresses, and only a warning is logged.
+func (u *Updater) Update(ctx context.Context) (err error) {
+	for _, hostname := range u.hostnames {
+		ips, err := u.resolver.LookupNetIP(ctx, "ip4", hostname)
+		if err != nil {
+			u.logger.Warn("cannot resolve outbound hostname: " + err.Error())
+			continue
+		}
+		sort.Slice(ips, func(i, j int) bool { return ips[i].Less(ips[j]) })
+		if !equalAddresses(ips, u.resolved[hostname]) {
+			u.logger.Info("outbound hostname " + hostname + " resolved to " + joinAddresses(ips))
+		}
+		u.resolved[hostname] = ips
+	}
+
+	subnets := make([]netip.Prefix, len(u.subnets), len(u.subnets)+len(u.resolved))
+	copy(subnets, u.subnets)
+	seen := make(map[netip.Addr]struct{})
+	for _, hostname := range u.hostnames {
+		for _, ip := range u.resolved[hostname] {
+			if _, ok := seen[ip]; ok {
+				continue
+			}
+			seen[ip] = struct{}{}
+			subnets = append(subnets, netip.PrefixFrom(ip, ip.BitLen()))
+		}
+	}
+
+	err = u.firewall.SetOutboundSubnets(ctx, subnets)
+	if err != nil {
+		return err
+	}
+	return u.routing.SetOutboundRoutes(subnets)
+}
+
+// Run updates the outbound subnets periodically if there
+// is any outbound hostname and the period is not zero.
+func (u *Updater) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	if len(u.hostnames) == 0 || u.period == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(u.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := u.Update(ctx)
+			if err != nil && ctx.Err() == nil {
+				u.logger.Error("updating outbound subnets: " + err.Error())
+			}
+		}
+	}
+}
+
+func equalAddresses(a, b []netip.Addr) (equal bool) {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func joinAddresses(ips []netip.Addr) (s string) {
+	for i, ip := range ips {
+		if i > 0 {
+			s += ", "
+		}
+		s += ip.String()
+	}
+	return s
+}
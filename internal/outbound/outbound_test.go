@@ -0,0 +1,82 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	hostToIPs map[string][]netip.Addr
+}
+
+var errNotFound = errors.New("not found")
+
+func (r *fakeResolver) LookupNetIP(_ context.Context, _, host string) (
+	ips []netip.Addr, err error) {
+	ips, ok := r.hostToIPs[host]
+	if !ok {
+		return nil, errNotFound
+	}
+	return ips, nil
+}
+
+type fakeSetter struct {
+	subnets []netip.Prefix
+}
+
+func (s *fakeSetter) SetOutboundSubnets(_ context.Context, subnets []netip.Prefix) error {
+	s.subnets = subnets
+	return nil
+}
+
+func (s *fakeSetter) SetOutboundRoutes(subnets []netip.Prefix) error {
+	s.subnets = subnets
+	return nil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_Updater_Update(t *testing.T) {
+	t.Parallel()
+
+	period := time.Minute
+	firewallSettings := settings.Firewall{
+		OutboundSubnets:         []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		OutboundHostnames:       []string{"a.example.com", "b.example.com"},
+		OutboundHostnamesPeriod: &period,
+	}
+	resolver := &fakeResolver{hostToIPs: map[string][]netip.Addr{
+		"a.example.com": {netip.MustParseAddr("1.2.3.5"), netip.MustParseAddr("1.2.3.4")},
+		"b.example.com": {netip.MustParseAddr("1.2.3.4")},
+	}}
+	firewall := &fakeSetter{}
+	routing := &fakeSetter{}
+	updater := New(firewallSettings, resolver, firewall, routing, noopLogger{})
+
+	err := updater.Update(context.Background())
+	require.NoError(t, err)
+	expected := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("1.2.3.4/32"),
+		netip.MustParsePrefix("1.2.3.5/32"),
+	}
+	assert.Equal(t, expected, firewall.subnets)
+	assert.Equal(t, expected, routing.subnets)
+
+	// Failing resolutions keep the previously resolved addresses.
+	delete(resolver.hostToIPs, "a.example.com")
+	err = updater.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, firewall.subnets)
+}
@@ -1,9 +1,13 @@
 package portforward
 
-import "context"
+import (
+	"context"
+	"net/netip"
+)
 
 // firewallBlockPort obtains the state port thread safely and blocks
-// it in the firewall if it is not the zero value (0).
+// it in the firewall if it is not the zero value (0), removing its
+// DNAT redirection if any.
 func (l *Loop) firewallBlockPort(ctx context.Context) {
 	port := l.state.GetPortForwarded()
 	if port == 0 {
@@ -14,10 +18,16 @@ func (l *Loop) firewallBlockPort(ctx context.Context) {
 	if err != nil {
 		l.logger.Error("cannot block previous port in firewall: " + err.Error())
 	}
+
+	err = l.portAllower.SetForwardedPortDNAT(ctx, "", 0, netip.AddrPort{})
+	if err != nil {
+		l.logger.Error("cannot remove previous port DNAT in firewall: " + err.Error())
+	}
 }
 
 // firewallAllowPort obtains the state port thread safely and allows
-// it in the firewall if it is not the zero value (0).
+// it in the firewall if it is not the zero value (0), redirecting it
+// to the DNAT destination if one is set.
 func (l *Loop) firewallAllowPort(ctx context.Context) {
 	port := l.state.GetPortForwarded()
 	if port == 0 {
@@ -29,4 +39,15 @@ func (l *Loop) firewallAllowPort(ctx context.Context) {
 	if err != nil {
 		l.logger.Error("cannot allow port: " + err.Error())
 	}
+
+	destination := *l.state.GetSettings().DNATDestination
+	if !destination.Addr().IsValid() {
+		return
+	} else if destination.Port() == 0 {
+		destination = netip.AddrPortFrom(destination.Addr(), port)
+	}
+	err = l.portAllower.SetForwardedPortDNAT(ctx, startData.Interface, port, destination)
+	if err != nil {
+		l.logger.Error("cannot redirect port: " + err.Error())
+	}
 }
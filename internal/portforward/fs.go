@@ -14,24 +14,11 @@ func (l *Loop) removePortForwardedFile() {
 }
 
 func (l *Loop) writePortForwardedFile(port uint16) {
-	filepath := *l.state.GetSettings().Filepath
+	settings := l.state.GetSettings()
+	filepath := *settings.Filepath
 	l.logger.Info("writing port file " + filepath)
-	if err := writePortForwardedToFile(filepath, port, l.puid, l.pgid); err != nil {
-		l.logger.Error("writing port forwarded to file: " + err.Error())
-	}
-}
-
-func writePortForwardedToFile(filepath string, port uint16, uid, gid int) (err error) {
-	const perms = os.FileMode(0644)
-	err = os.WriteFile(filepath, []byte(fmt.Sprint(port)), perms)
+	err := l.fileWriter.WriteFile(filepath, []byte(fmt.Sprint(port)), *settings.FilePermissions)
 	if err != nil {
-		return fmt.Errorf("writing file: %w", err)
-	}
-
-	err = os.Chown(filepath, uid, gid)
-	if err != nil {
-		return fmt.Errorf("chowning file: %w", err)
+		l.logger.Error("writing port forwarded to file: " + err.Error())
 	}
-
-	return nil
 }
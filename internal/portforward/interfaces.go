@@ -2,9 +2,36 @@ package portforward
 
 import (
 	"context"
+	"io/fs"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/tracing"
 )
 
+type Tracer interface {
+	Start(ctx context.Context, name string) (
+		spanCtx context.Context, span *tracing.Span)
+}
+
+type Notifier interface {
+	PortForwardingLost(err error)
+}
+
+type EventHooks interface {
+	Trigger(eventType string, details map[string]string)
+}
+
 type PortAllower interface {
 	SetAllowedPort(ctx context.Context, port uint16, intf string) (err error)
 	RemoveAllowedPort(ctx context.Context, port uint16) (err error)
+	SetForwardedPortDNAT(ctx context.Context, intf string,
+		port uint16, destination netip.AddrPort) (err error)
+}
+
+type PortListener interface {
+	SetListenPort(port uint16) (err error)
+}
+
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
 }
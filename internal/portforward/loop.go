@@ -15,13 +15,15 @@ import (
 type Loop struct {
 	statusManager *loopstate.State
 	state         *state.State
-	// Fixed parameters
-	puid int
-	pgid int
 	// Objects
-	client      *http.Client
-	portAllower PortAllower
-	logger      Logger
+	client       *http.Client
+	portAllower  PortAllower
+	logger       Logger
+	tracer       Tracer
+	notifier     Notifier
+	eventHooks   EventHooks
+	fileWriter   FileWriter
+	portListener PortListener
 	// Internal channels and locks
 	start       chan struct{}
 	running     chan models.LoopStatus
@@ -36,7 +38,8 @@ const defaultBackoffTime = 5 * time.Second
 
 func NewLoop(settings settings.PortForwarding,
 	client *http.Client, portAllower PortAllower,
-	logger Logger, puid, pgid int) *Loop {
+	logger Logger, tracer Tracer, notifier Notifier, eventHooks EventHooks,
+	fileWriter FileWriter, portListener PortListener) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
@@ -48,17 +51,20 @@ func NewLoop(settings settings.PortForwarding,
 	return &Loop{
 		statusManager: statusManager,
 		state:         state,
-		puid:          puid,
-		pgid:          pgid,
 		// Objects
-		client:      client,
-		portAllower: portAllower,
-		logger:      logger,
-		start:       start,
-		running:     running,
-		stop:        stop,
-		stopped:     stopped,
-		userTrigger: true,
-		backoffTime: defaultBackoffTime,
+		client:       client,
+		portAllower:  portAllower,
+		logger:       logger,
+		tracer:       tracer,
+		notifier:     notifier,
+		eventHooks:   eventHooks,
+		fileWriter:   fileWriter,
+		portListener: portListener,
+		start:        start,
+		running:      running,
+		stop:         stop,
+		stopped:      stopped,
+		userTrigger:  true,
+		backoffTime:  defaultBackoffTime,
 	}
 }
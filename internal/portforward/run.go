@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
 func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
@@ -23,10 +25,22 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		errorCh := make(chan error)
 
 		startData := l.state.GetStartData()
+		settings := l.state.GetSettings()
+		objects := utils.PortForwardObjects{
+			Logger:     l.logger,
+			Gateway:    startData.Gateway,
+			Client:     l.client,
+			ServerName: startData.ServerName,
+			Username:   *settings.Username,
+			Password:   *settings.Password,
+			FileWriter: l.fileWriter,
+		}
 
 		go func(ctx context.Context, startData StartData) {
-			port, err := startData.PortForwarder.PortForward(ctx, l.client, l.logger,
-				startData.Gateway, startData.ServerName)
+			spanCtx, span := l.tracer.Start(ctx, "port forwarding")
+			span.SetAttribute("vpn.server", startData.ServerName)
+			port, err := startData.PortForwarder.PortForward(spanCtx, objects)
+			span.End(err)
 			if err != nil {
 				errorCh <- err
 				return
@@ -34,8 +48,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			portCh <- port
 
 			// Infinite loop
-			err = startData.PortForwarder.KeepPortForward(ctx,
-				startData.Gateway, startData.ServerName)
+			err = startData.PortForwarder.KeepPortForward(ctx, objects)
 			errorCh <- err
 		}(pfCtx, startData)
 
@@ -84,11 +97,22 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 				l.state.SetPortForwarded(port)
 				l.firewallAllowPort(ctx)
 				l.writePortForwardedFile(port)
+				err := l.portListener.SetListenPort(port)
+				if err != nil {
+					l.logger.Error("cannot listen on port forwarded: " + err.Error())
+				}
+				l.eventHooks.Trigger(models.PortForwardEventForwarded, map[string]string{
+					"port_forwarded": strconv.Itoa(int(port)),
+				})
 			case err := <-errorCh:
 				pfCancel()
 				close(errorCh)
 				close(portCh)
 				l.statusManager.SetStatus(constants.Crashed)
+				l.notifier.PortForwardingLost(err)
+				l.eventHooks.Trigger(models.PortForwardEventLost, map[string]string{
+					"reason": err.Error(),
+				})
 				l.logAndWait(ctx, err)
 				stayHere = false
 			}
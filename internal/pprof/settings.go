@@ -28,6 +28,8 @@ type Settings struct {
 
 func (s *Settings) SetDefaults() {
 	s.Enabled = helpers.DefaultPointer(s.Enabled, false)
+	s.BlockProfileRate = helpers.DefaultPointer(s.BlockProfileRate, 0)
+	s.MutexProfileRate = helpers.DefaultPointer(s.MutexProfileRate, 0)
 	s.HTTPServer.Address = helpers.DefaultString(s.HTTPServer.Address, "localhost:6060")
 	const defaultReadTimeout = 5 * time.Minute // for CPU profiling
 	s.HTTPServer.ReadTimeout = helpers.DefaultNumber(s.HTTPServer.ReadTimeout, defaultReadTimeout)
@@ -37,8 +39,8 @@ func (s *Settings) SetDefaults() {
 func (s Settings) Copy() (copied Settings) {
 	return Settings{
 		Enabled:          helpers.CopyPointer(s.Enabled),
-		BlockProfileRate: s.BlockProfileRate,
-		MutexProfileRate: s.MutexProfileRate,
+		BlockProfileRate: helpers.CopyPointer(s.BlockProfileRate),
+		MutexProfileRate: helpers.CopyPointer(s.MutexProfileRate),
 		HTTPServer:       s.HTTPServer.Copy(),
 	}
 }
@@ -19,7 +19,9 @@ func Test_Settings_SetDefaults(t *testing.T) {
 	}{
 		"empty settings": {
 			expected: Settings{
-				Enabled: boolPtr(false),
+				Enabled:          boolPtr(false),
+				BlockProfileRate: intPtr(0),
+				MutexProfileRate: intPtr(0),
 				HTTPServer: httpserver.Settings{
 					Address:           "localhost:6060",
 					ReadHeaderTimeout: 3 * time.Second,
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
-	"github.com/qdm12/gluetun/internal/constants/openvpn"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
@@ -57,6 +57,8 @@ func modifyConfig(lines []string, connection models.Connection,
 				"cipher ", "ncp-ciphers ", "data-ciphers ", "data-ciphers-fallback "),
 			*settings.Auth != "" && strings.HasPrefix(line, "auth "),
 			*settings.MSSFix > 0 && strings.HasPrefix(line, "mssfix "),
+			*settings.Fragment > 0 && strings.HasPrefix(line, "fragment "),
+			*settings.TunMTU > 0 && strings.HasPrefix(line, "tun-mtu "),
 			!ipv6Supported && hasPrefixOneOf(line, "tun-ipv6",
 				`pull-filter ignore "route-ipv6"`,
 				`pull-filter ignore "ifconfig-ipv6"`):
@@ -74,9 +76,6 @@ func modifyConfig(lines []string, connection models.Connection,
 	modified = append(modified, "pull-filter ignore \"auth-token\"") // prevent auth failed loop
 	modified = append(modified, "auth-retry nointeract")
 	modified = append(modified, "suppress-timestamps")
-	if *settings.User != "" {
-		modified = append(modified, "auth-user-pass "+openvpn.AuthConf)
-	}
 	modified = append(modified, "verb "+strconv.Itoa(*settings.Verbosity))
 	if len(settings.Ciphers) > 0 {
 		modified = append(modified, utils.CipherLines(settings.Ciphers)...)
@@ -87,6 +86,12 @@ func modifyConfig(lines []string, connection models.Connection,
 	if *settings.MSSFix > 0 {
 		modified = append(modified, "mssfix "+strconv.Itoa(int(*settings.MSSFix)))
 	}
+	if *settings.Fragment > 0 && connection.Protocol == constants.UDP {
+		modified = append(modified, "fragment "+strconv.Itoa(int(*settings.Fragment)))
+	}
+	if *settings.TunMTU > 0 {
+		modified = append(modified, "tun-mtu "+strconv.Itoa(int(*settings.TunMTU)))
+	}
 	if !ipv6Supported {
 		modified = append(modified, `pull-filter ignore "route-ipv6"`)
 		modified = append(modified, `pull-filter ignore "ifconfig-ipv6"`)
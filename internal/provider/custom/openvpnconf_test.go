@@ -35,12 +35,15 @@ func Test_modifyConfig(t *testing.T) {
 				"tun-ipv6",
 				"keep me here",
 				"auth bla",
+				"tun-mtu 1500",
 			},
 			settings: settings.OpenVPN{
 				User:        stringPtr("user"),
 				Ciphers:     []string{"cipher"},
 				Auth:        stringPtr("auth"),
 				MSSFix:      uint16Ptr(1000),
+				Fragment:    uint16Ptr(1300),
+				TunMTU:      uint16Ptr(1400),
 				ProcessUser: "procuser",
 				Interface:   "tun3",
 				Verbosity:   intPtr(0),
@@ -62,12 +65,13 @@ func Test_modifyConfig(t *testing.T) {
 				"pull-filter ignore \"auth-token\"",
 				"auth-retry nointeract",
 				"suppress-timestamps",
-				"auth-user-pass /etc/openvpn/auth.conf",
 				"verb 0",
 				"data-ciphers-fallback cipher",
 				"data-ciphers cipher",
 				"auth auth",
 				"mssfix 1000",
+				"fragment 1300",
+				"tun-mtu 1400",
 				"pull-filter ignore \"route-ipv6\"",
 				"pull-filter ignore \"ifconfig-ipv6\"",
 				"user procuser",
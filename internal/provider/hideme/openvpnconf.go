@@ -0,0 +1,29 @@
+package hideme
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/openvpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+func (p *Provider) OpenVPNConfig(connection models.Connection,
+	settings settings.OpenVPN, ipv6Supported bool) (lines []string) {
+	//nolint:gomnd
+	providerSettings := utils.OpenVPNProviderSettings{
+		AuthUserPass: true,
+		Ciphers: []string{
+			openvpn.AES256gcm,
+			openvpn.AES128gcm,
+		},
+		Ping:           10,
+		RemoteCertTLS:  true,
+		VerifyX509Type: "name",
+		ExtraLines: []string{
+			// Hide.me servers certificates are signed by a
+			// public certificate authority.
+			"ca /etc/ssl/certs/ca-certificates.crt",
+		},
+	}
+	return utils.OpenVPNConfig(providerSettings, connection, settings, ipv6Supported)
+}
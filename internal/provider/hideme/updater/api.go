@@ -0,0 +1,56 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	errHTTPStatusCodeNotOK = errors.New("HTTP status code not OK")
+)
+
+// apiServer is a server location entry from the Hide.me API.
+// A location can contain children locations, for example
+// a country containing multiple cities.
+type apiServer struct {
+	Hostname    string      `json:"hostname"`
+	DisplayName string      `json:"displayName"`
+	Flag        string      `json:"flag"`
+	Children    []apiServer `json:"children"`
+}
+
+func fetchAPI(ctx context.Context, client *http.Client) (
+	data []apiServer, err error) {
+	const url = "https://api.hide.me/v1/network/paid/en"
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("%w: %d %s",
+			errHTTPStatusCodeNotOK, response.StatusCode, response.Status)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	if err := decoder.Decode(&data); err != nil {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	if err := response.Body.Close(); err != nil {
+		return nil, fmt.Errorf("closing response body: %w", err)
+	}
+
+	return data, nil
+}
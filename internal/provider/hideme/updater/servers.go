@@ -0,0 +1,86 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/common"
+)
+
+func (u *Updater) FetchServers(ctx context.Context, minServers int) (
+	servers []models.Server, err error) {
+	data, err := fetchAPI(ctx, u.client)
+	if err != nil {
+		return nil, fmt.Errorf("fetching API: %w", err)
+	}
+
+	countryCodes := constants.CountryCodes()
+	hostToServer := make(map[string]models.Server)
+	for _, countryData := range data {
+		country, warning := codeToCountry(countryData.Flag, countryCodes)
+		if warning != "" {
+			u.warner.Warn(warning)
+		}
+
+		locations := countryData.Children
+		if len(locations) == 0 {
+			locations = []apiServer{countryData}
+		}
+
+		for _, location := range locations {
+			if location.Hostname == "" {
+				continue
+			}
+			server := models.Server{
+				VPN:      vpn.OpenVPN,
+				Country:  country,
+				Hostname: location.Hostname,
+				TCP:      true,
+				UDP:      true,
+			}
+			if len(countryData.Children) > 0 {
+				server.City = location.DisplayName
+			}
+			hostToServer[location.Hostname] = server
+		}
+	}
+
+	if len(hostToServer) < minServers {
+		return nil, fmt.Errorf("%w: %d and expected at least %d",
+			common.ErrNotEnoughServers, len(hostToServer), minServers)
+	}
+
+	hosts := make([]string, 0, len(hostToServer))
+	for host := range hostToServer {
+		hosts = append(hosts, host)
+	}
+
+	resolveSettings := parallelResolverSettings(hosts)
+	hostToIPs, warnings, err := u.parallelResolver.Resolve(ctx, resolveSettings)
+	for _, warning := range warnings {
+		u.warner.Warn(warning)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolving hosts: %w", err)
+	}
+
+	if len(hostToIPs) < minServers {
+		return nil, fmt.Errorf("%w: %d and expected at least %d",
+			common.ErrNotEnoughServers, len(hostToIPs), minServers)
+	}
+
+	servers = make([]models.Server, 0, len(hostToIPs))
+	for host, IPs := range hostToIPs {
+		server := hostToServer[host]
+		server.IPs = IPs
+		servers = append(servers, server)
+	}
+
+	sort.Sort(models.SortableServers(servers))
+
+	return servers, nil
+}
@@ -0,0 +1,58 @@
+package mullvad
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var ErrAccountExpired = errors.New("account expired")
+
+// CheckAccount checks the account number exists and
+// is not expired using the Mullvad API.
+func CheckAccount(ctx context.Context, client *http.Client,
+	accountNumber string) (err error) {
+	accountURL := "https://api.mullvad.net/www/accounts/" + url.PathEscape(accountNumber) + "/"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, accountURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnauthorized:
+		return fmt.Errorf("%w: account number not found", utils.ErrCredentialsNotValid)
+	default:
+		return fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	var data struct {
+		Account struct {
+			Expires time.Time `json:"expires"`
+		} `json:"account"`
+	}
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&data)
+	if err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+
+	if !data.Account.Expires.IsZero() && data.Account.Expires.Before(time.Now()) {
+		return fmt.Errorf("%w: on %s", ErrAccountExpired,
+			data.Account.Expires.Format(time.RFC1123))
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package mullvad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var (
+	ErrHTTPStatusCodeNotOK = errors.New("HTTP status code not OK")
+	ErrMaxDevicesReached   = errors.New("maximum number of devices reached")
+	ErrDeviceNotFound      = errors.New("device not found")
+)
+
+const apiBaseURL = "https://api.mullvad.net"
+
+// Device is a device registered on a Mullvad account,
+// each device having its own Wireguard key and addresses.
+type Device struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	PublicKey   string       `json:"pubkey"`
+	Created     time.Time    `json:"created"`
+	IPv4Address netip.Prefix `json:"ipv4_address"`
+	IPv6Address netip.Prefix `json:"ipv6_address"`
+}
+
+// Addresses returns the valid Wireguard addresses of the device.
+func (d Device) Addresses() (addresses []netip.Prefix) {
+	for _, address := range []netip.Prefix{d.IPv4Address, d.IPv6Address} {
+		if address.IsValid() {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// FetchAccessToken obtains an access token for the Mullvad
+// account number given, to be used with the devices API.
+func FetchAccessToken(ctx context.Context, client *http.Client,
+	accountNumber string) (accessToken string, err error) {
+	requestData := struct {
+		AccountNumber string `json:"account_number"`
+	}{AccountNumber: accountNumber}
+	var responseData struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = doAPIRequest(ctx, client, http.MethodPost, "/auth/v1/token",
+		"", requestData, &responseData)
+	if err != nil {
+		return "", err
+	}
+	return responseData.AccessToken, nil
+}
+
+// ListDevices lists the devices registered on the account
+// of the access token given.
+func ListDevices(ctx context.Context, client *http.Client,
+	accessToken string) (devices []Device, err error) {
+	err = doAPIRequest(ctx, client, http.MethodGet, "/accounts/v1/devices",
+		accessToken, nil, &devices)
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// AddDevice registers a new device with the Wireguard public key
+// given on the account of the access token given, and returns
+// the device created with its assigned Wireguard addresses.
+func AddDevice(ctx context.Context, client *http.Client,
+	accessToken, publicKey string) (device Device, err error) {
+	requestData := struct {
+		PublicKey string `json:"pubkey"`
+		HijackDNS bool   `json:"hijack_dns"`
+	}{PublicKey: publicKey}
+	err = doAPIRequest(ctx, client, http.MethodPost, "/accounts/v1/devices",
+		accessToken, requestData, &device)
+	if err != nil {
+		return device, err
+	}
+	return device, nil
+}
+
+// RegisterDevice registers a new device with the Wireguard public key
+// given on the Mullvad account number given, and returns the device
+// created with its assigned Wireguard addresses.
+func RegisterDevice(ctx context.Context, client *http.Client,
+	accountNumber, publicKey string) (device Device, err error) {
+	accessToken, err := FetchAccessToken(ctx, client, accountNumber)
+	if err != nil {
+		return device, fmt.Errorf("fetching access token: %w", err)
+	}
+
+	device, err = AddDevice(ctx, client, accessToken, publicKey)
+	if err != nil {
+		return device, fmt.Errorf("adding device: %w", err)
+	}
+	return device, nil
+}
+
+// RemoveDevice removes the device with the ID given from the
+// account of the access token given.
+func RemoveDevice(ctx context.Context, client *http.Client,
+	accessToken, deviceID string) (err error) {
+	return doAPIRequest(ctx, client, http.MethodDelete,
+		"/accounts/v1/devices/"+url.PathEscape(deviceID), accessToken, nil, nil)
+}
+
+// RotateDeviceKey replaces the Wireguard public key of the device
+// with the ID given, and returns the updated device.
+func RotateDeviceKey(ctx context.Context, client *http.Client,
+	accessToken, deviceID, publicKey string) (device Device, err error) {
+	requestData := struct {
+		PublicKey string `json:"pubkey"`
+	}{PublicKey: publicKey}
+	err = doAPIRequest(ctx, client, http.MethodPut,
+		"/accounts/v1/devices/"+url.PathEscape(deviceID)+"/pubkey",
+		accessToken, requestData, &device)
+	if err != nil {
+		return device, err
+	}
+	return device, nil
+}
+
+// doAPIRequest sends a request to the Mullvad API with the request
+// data given encoded as JSON if not nil, and decodes the JSON response
+// body into the response data given if not nil.
+func doAPIRequest(ctx context.Context, client *http.Client,
+	method, path, accessToken string, requestData, responseData any) (err error) {
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		encoder := json.NewEncoder(buffer)
+		err = encoder.Encode(requestData)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if requestData != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return makeAPIError(response)
+	}
+
+	if responseData == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(responseData)
+	if err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+	return nil
+}
+
+// makeAPIError returns an error describing the failed response
+// given, using the error code from the response body if any.
+func makeAPIError(response *http.Response) (err error) {
+	var data struct {
+		Code   string `json:"code"`
+		Detail string `json:"detail"`
+	}
+	b, _ := io.ReadAll(response.Body)
+	_ = json.Unmarshal(b, &data)
+
+	switch data.Code {
+	case "INVALID_ACCOUNT", "INVALID_AUTH_HEADER", "INVALID_ACCESS_TOKEN":
+		return fmt.Errorf("%w: %s", utils.ErrCredentialsNotValid, data.Code)
+	case "MAX_DEVICES_REACHED":
+		return fmt.Errorf("%w: remove a device from the account first", ErrMaxDevicesReached)
+	case "DEVICE_NOT_FOUND":
+		return fmt.Errorf("%w", ErrDeviceNotFound)
+	}
+
+	details := data.Detail
+	if details == "" {
+		details = strings.TrimSpace(string(b))
+	}
+	return fmt.Errorf("%w: %s: %s", ErrHTTPStatusCodeNotOK, response.Status, details)
+}
@@ -0,0 +1,124 @@
+package mullvad
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Device_decoding(t *testing.T) {
+	t.Parallel()
+
+	const data = `{
+		"id": "f2d2a1d4-6c0d-4a5c-a3b8-6b0a8e3f6c1e",
+		"name": "happy seal",
+		"pubkey": "oPLm2S5yDaqU0cYylZpGD0KBzs0bqVBvgPZjx0/zj2g=",
+		"hijack_dns": false,
+		"created": "2023-04-01T10:00:00+00:00",
+		"ipv4_address": "10.64.0.1/32",
+		"ipv6_address": "fc00:bbbb:bbbb:bb01::1/128",
+		"ports": []
+	}`
+
+	var device Device
+	err := json.Unmarshal([]byte(data), &device)
+	require.NoError(t, err)
+
+	expected := Device{
+		ID:          "f2d2a1d4-6c0d-4a5c-a3b8-6b0a8e3f6c1e",
+		Name:        "happy seal",
+		PublicKey:   "oPLm2S5yDaqU0cYylZpGD0KBzs0bqVBvgPZjx0/zj2g=",
+		Created:     time.Date(2023, time.April, 1, 10, 0, 0, 0, time.UTC),
+		IPv4Address: netip.MustParsePrefix("10.64.0.1/32"),
+		IPv6Address: netip.MustParsePrefix("fc00:bbbb:bbbb:bb01::1/128"),
+	}
+	assert.Equal(t, expected.ID, device.ID)
+	assert.Equal(t, expected.Name, device.Name)
+	assert.Equal(t, expected.PublicKey, device.PublicKey)
+	assert.True(t, expected.Created.Equal(device.Created))
+	assert.Equal(t, []netip.Prefix{expected.IPv4Address, expected.IPv6Address},
+		device.Addresses())
+}
+
+func Test_makeAPIError(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		status     string
+		body       string
+		errMessage string
+	}{
+		"invalid account": {
+			status:     "400 Bad Request",
+			body:       `{"code":"INVALID_ACCOUNT","detail":"Invalid account"}`,
+			errMessage: "wrong credentials: INVALID_ACCOUNT",
+		},
+		"max devices": {
+			status:     "400 Bad Request",
+			body:       `{"code":"MAX_DEVICES_REACHED","detail":"Too many devices"}`,
+			errMessage: "maximum number of devices reached: remove a device from the account first",
+		},
+		"unknown code": {
+			status:     "500 Internal Server Error",
+			body:       `{"code":"INTERNAL","detail":"Something broke"}`,
+			errMessage: "HTTP status code not OK: 500 Internal Server Error: Something broke",
+		},
+		"not JSON": {
+			status:     "502 Bad Gateway",
+			body:       "bad gateway\n",
+			errMessage: "HTTP status code not OK: 502 Bad Gateway: bad gateway",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			response := &http.Response{
+				Status: testCase.status,
+				Body:   io.NopCloser(strings.NewReader(testCase.body)),
+			}
+
+			err := makeAPIError(response)
+
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
+
+func Test_DeviceState(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "device.json")
+
+	state, err := ReadDeviceState(path)
+	require.NoError(t, err)
+	assert.Equal(t, DeviceState{}, state)
+
+	expected := DeviceState{
+		ID:         "f2d2a1d4-6c0d-4a5c-a3b8-6b0a8e3f6c1e",
+		PrivateKey: "aPLm2S5yDaqU0cYylZpGD0KBzs0bqVBvgPZjx0/zj2g=",
+	}
+	fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+	err = WriteDeviceState(fileWriter, path, expected)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	state, err = ReadDeviceState(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+}
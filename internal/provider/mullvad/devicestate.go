@@ -0,0 +1,163 @@
+package mullvad
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var ErrDeviceStateNotFound = errors.New("device state not found")
+
+// DeviceStatePath returns the file path in the data directory given
+// where the device registered by the program is stored, so the same
+// device is reused on restarts instead of registering a new device
+// each time.
+func DeviceStatePath(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "mullvaddevice.json")
+}
+
+// DeviceState is the device registered by the program,
+// with the Wireguard private key matching its public key.
+type DeviceState struct {
+	ID         string `json:"id"`
+	PrivateKey string `json:"private_key"`
+}
+
+// ReadDeviceState reads the device state stored at the path given.
+// It returns an empty state if the file does not exist.
+func ReadDeviceState(path string) (state DeviceState, err error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(b, &state)
+	if err != nil {
+		return state, fmt.Errorf("decoding device state: %w", err)
+	}
+	return state, nil
+}
+
+// WriteDeviceState writes the device state given to the path given
+// using the file writer given, readable only by its owner since it
+// contains the private key.
+func WriteDeviceState(fileWriter FileWriter, path string, state DeviceState) (err error) {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding device state: %w", err)
+	}
+	const permissions = 0600
+	return fileWriter.WriteFile(path, b, permissions)
+}
+
+// SetupDevice returns the device stored at the state path given and its
+// private key, if the device is still registered on the account with the
+// same public key. Otherwise, it registers a new device on the account
+// with a newly generated private key, and stores it at the state path.
+func SetupDevice(ctx context.Context, client *http.Client, fileWriter FileWriter,
+	accountNumber, statePath string) (device Device, privateKey string, err error) {
+	accessToken, err := FetchAccessToken(ctx, client, accountNumber)
+	if err != nil {
+		return device, "", fmt.Errorf("fetching access token: %w", err)
+	}
+
+	state, err := ReadDeviceState(statePath)
+	if err != nil {
+		return device, "", fmt.Errorf("reading device state: %w", err)
+	}
+
+	if state.ID != "" {
+		device, found, err := findStateDevice(ctx, client, accessToken, state)
+		if err != nil {
+			return device, "", err
+		} else if found {
+			return device, state.PrivateKey, nil
+		}
+	}
+
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return device, "", fmt.Errorf("generating private key: %w", err)
+	}
+
+	device, err = AddDevice(ctx, client, accessToken, key.PublicKey().String())
+	if err != nil {
+		return device, "", fmt.Errorf("adding device: %w", err)
+	}
+
+	state = DeviceState{ID: device.ID, PrivateKey: key.String()}
+	err = WriteDeviceState(fileWriter, statePath, state)
+	if err != nil {
+		return device, "", fmt.Errorf("writing device state: %w", err)
+	}
+
+	return device, state.PrivateKey, nil
+}
+
+// RotateDevice replaces the Wireguard key of the device stored at the
+// state path given with a newly generated key, and stores the new
+// private key at the state path.
+func RotateDevice(ctx context.Context, client *http.Client, fileWriter FileWriter,
+	accountNumber, statePath string) (device Device, err error) {
+	state, err := ReadDeviceState(statePath)
+	if err != nil {
+		return device, fmt.Errorf("reading device state: %w", err)
+	} else if state.ID == "" {
+		return device, fmt.Errorf("%w: at %s", ErrDeviceStateNotFound, statePath)
+	}
+
+	accessToken, err := FetchAccessToken(ctx, client, accountNumber)
+	if err != nil {
+		return device, fmt.Errorf("fetching access token: %w", err)
+	}
+
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return device, fmt.Errorf("generating private key: %w", err)
+	}
+
+	device, err = RotateDeviceKey(ctx, client, accessToken, state.ID, key.PublicKey().String())
+	if err != nil {
+		return device, fmt.Errorf("rotating device key: %w", err)
+	}
+
+	state.PrivateKey = key.String()
+	err = WriteDeviceState(fileWriter, statePath, state)
+	if err != nil {
+		return device, fmt.Errorf("writing device state: %w", err)
+	}
+
+	return device, nil
+}
+
+// findStateDevice returns the device of the state given from the
+// devices registered on the account, if it exists and its public
+// key matches the private key of the state.
+func findStateDevice(ctx context.Context, client *http.Client,
+	accessToken string, state DeviceState) (device Device, found bool, err error) {
+	privateKey, err := wgtypes.ParseKey(state.PrivateKey)
+	if err != nil {
+		return device, false, fmt.Errorf("parsing stored private key: %w", err)
+	}
+	publicKey := privateKey.PublicKey().String()
+
+	devices, err := ListDevices(ctx, client, accessToken)
+	if err != nil {
+		return device, false, fmt.Errorf("listing devices: %w", err)
+	}
+
+	for _, device := range devices {
+		if device.ID == state.ID && device.PublicKey == publicKey {
+			return device, true, nil
+		}
+	}
+	return device, false, nil
+}
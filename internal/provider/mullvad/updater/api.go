@@ -24,6 +24,10 @@ type serverData struct {
 	IPv6     string `json:"ipv6_addr_in"`
 	Type     string `json:"type"`
 	PubKey   string `json:"pubkey"` // Wireguard public key
+	// SOCKSName is the hostname of the SOCKS5 proxy
+	// only reachable through the Wireguard tunnel.
+	SOCKSName string `json:"socks_name"`
+	SOCKSPort uint16 `json:"socks_port"`
 }
 
 func fetchAPI(ctx context.Context, client *http.Client) (data []serverData, err error) {
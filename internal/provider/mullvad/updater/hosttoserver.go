@@ -3,6 +3,7 @@ package updater
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/netip"
 	"strings"
 
@@ -73,6 +74,9 @@ func (hts hostToServer) add(data serverData) (err error) {
 	server.ISP = data.Provider
 	server.Owned = data.Owned
 	server.WgPubKey = data.PubKey
+	if data.SOCKSName != "" && data.SOCKSPort != 0 {
+		server.SOCKSProxy = net.JoinHostPort(data.SOCKSName, fmt.Sprint(data.SOCKSPort))
+	}
 
 	hts[data.Hostname] = server
 
@@ -0,0 +1,90 @@
+package nordvpn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var (
+	ErrHTTPStatusCodeNotOK = errors.New("HTTP status code not OK")
+	ErrPrivateKeyNotFound  = errors.New("private key not found")
+)
+
+// FetchWireguardPrivateKey fetches the NordLynx Wireguard private key
+// of the user from the NordVPN API, using the user access token.
+func FetchWireguardPrivateKey(ctx context.Context, client *http.Client,
+	accessToken string) (privateKey string, err error) {
+	credentials, err := fetchServiceCredentials(ctx, client, accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	if credentials.PrivateKey == "" {
+		return "", fmt.Errorf("%w", ErrPrivateKeyNotFound)
+	}
+
+	return credentials.PrivateKey, nil
+}
+
+// CheckCredentials checks the access token is valid and, if the
+// OpenVPN username given is not empty, that the OpenVPN username and
+// password match the service credentials from the NordVPN API.
+func CheckCredentials(ctx context.Context, client *http.Client,
+	accessToken, openvpnUsername, openvpnPassword string) (err error) {
+	credentials, err := fetchServiceCredentials(ctx, client, accessToken)
+	if err != nil {
+		return err
+	}
+
+	if openvpnUsername == "" {
+		return nil
+	}
+
+	if openvpnUsername != credentials.Username ||
+		openvpnPassword != credentials.Password {
+		return fmt.Errorf("%w: OpenVPN username and password do not match "+
+			"the service credentials", utils.ErrCredentialsNotValid)
+	}
+	return nil
+}
+
+type serviceCredentials struct {
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	PrivateKey string `json:"nordlynx_private_key"`
+}
+
+func fetchServiceCredentials(ctx context.Context, client *http.Client,
+	accessToken string) (credentials serviceCredentials, err error) {
+	const url = "https://api.nordvpn.com/v1/users/services/credentials"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return credentials, fmt.Errorf("creating request: %w", err)
+	}
+	request.SetBasicAuth("token", accessToken)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return credentials, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		return credentials, fmt.Errorf("%w: access token rejected", utils.ErrCredentialsNotValid)
+	} else if response.StatusCode != http.StatusOK {
+		return credentials, fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&credentials)
+	if err != nil {
+		return credentials, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	return credentials, nil
+}
@@ -13,18 +13,36 @@ var (
 )
 
 type serverData struct {
-	Domain    string `json:"domain"`
-	IPAddress string `json:"ip_address"`
 	Name      string `json:"name"`
-	Country   string `json:"country"`
-	Features  struct {
-		UDP bool `json:"openvpn_udp"`
-		TCP bool `json:"openvpn_tcp"`
-	} `json:"features"`
+	Hostname  string `json:"hostname"`
+	Station   string `json:"station"` // IPv4 address
+	Status    string `json:"status"`
+	Locations []struct {
+		Country struct {
+			Name string `json:"name"`
+			City struct {
+				Name string `json:"name"`
+			} `json:"city"`
+		} `json:"country"`
+	} `json:"locations"`
+	Technologies []technology `json:"technologies"`
+	Groups       []struct {
+		Identifier string `json:"identifier"`
+	} `json:"groups"`
+}
+
+type technology struct {
+	Identifier string     `json:"identifier"`
+	Metadata   []metadata `json:"metadata"`
+}
+
+type metadata struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 func fetchAPI(ctx context.Context, client *http.Client) (data []serverData, err error) {
-	const url = "https://nordvpn.com/api/server"
+	const url = "https://api.nordvpn.com/v1/servers?limit=0"
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
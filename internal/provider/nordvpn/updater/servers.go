@@ -26,12 +26,22 @@ func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 	servers = make([]models.Server, 0, len(data))
 
 	for _, jsonServer := range data {
-		if !jsonServer.Features.TCP && !jsonServer.Features.UDP {
-			u.warner.Warn("server does not support TCP and UDP for openvpn: " + jsonServer.Name)
+		if jsonServer.Status != "online" {
 			continue
 		}
 
-		ip, err := parseIPv4(jsonServer.IPAddress)
+		technologies, err := parseTechnologies(jsonServer.Technologies)
+		if err != nil {
+			u.warner.Warn(err.Error() + " for server " + jsonServer.Name)
+		}
+
+		if !technologies.openvpnTCP && !technologies.openvpnUDP &&
+			!technologies.wireguard {
+			u.warner.Warn("server does not support OpenVPN nor Wireguard: " + jsonServer.Name)
+			continue
+		}
+
+		ip, err := parseIPv4(jsonServer.Station)
 		if err != nil {
 			return nil, fmt.Errorf("%w for server %s", err, jsonServer.Name)
 		}
@@ -42,15 +52,39 @@ func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 		}
 
 		server := models.Server{
-			VPN:      vpn.OpenVPN,
-			Region:   jsonServer.Country,
-			Hostname: jsonServer.Domain,
+			Hostname: jsonServer.Hostname,
 			Number:   number,
 			IPs:      []netip.Addr{ip},
-			TCP:      jsonServer.Features.TCP,
-			UDP:      jsonServer.Features.UDP,
 		}
-		servers = append(servers, server)
+		for _, group := range jsonServer.Groups {
+			switch group.Identifier {
+			case "legacy_p2p":
+				server.P2P = true
+			case "legacy_obfuscated_servers":
+				server.Stealth = true
+			}
+		}
+		if len(jsonServer.Locations) > 0 {
+			location := jsonServer.Locations[0]
+			server.Region = location.Country.Name
+			server.City = location.Country.City.Name
+		}
+
+		if technologies.openvpnTCP || technologies.openvpnUDP {
+			openvpnServer := server
+			openvpnServer.VPN = vpn.OpenVPN
+			openvpnServer.TCP = technologies.openvpnTCP
+			openvpnServer.UDP = technologies.openvpnUDP
+			servers = append(servers, openvpnServer)
+		}
+
+		if technologies.wireguardPubKey != "" {
+			wireguardServer := server
+			wireguardServer.VPN = vpn.Wireguard
+			wireguardServer.UDP = true
+			wireguardServer.WgPubKey = technologies.wireguardPubKey
+			servers = append(servers, wireguardServer)
+		}
 	}
 
 	if len(servers) < minServers {
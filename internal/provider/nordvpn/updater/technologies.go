@@ -0,0 +1,41 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrWireguardPublicKeyNotFound = errors.New("wireguard public key not found")
+)
+
+type serverTechnologies struct {
+	openvpnTCP      bool
+	openvpnUDP      bool
+	wireguard       bool
+	wireguardPubKey string
+}
+
+func parseTechnologies(technologies []technology) (
+	parsed serverTechnologies, err error) {
+	for _, technology := range technologies {
+		switch technology.Identifier {
+		case "openvpn_tcp":
+			parsed.openvpnTCP = true
+		case "openvpn_udp":
+			parsed.openvpnUDP = true
+		case "wireguard_udp":
+			parsed.wireguard = true
+			for _, data := range technology.Metadata {
+				if data.Name == "public_key" {
+					parsed.wireguardPubKey = data.Value
+					break
+				}
+			}
+			if parsed.wireguardPubKey == "" {
+				return parsed, fmt.Errorf("%w", ErrWireguardPublicKeyNotFound)
+			}
+		}
+	}
+	return parsed, nil
+}
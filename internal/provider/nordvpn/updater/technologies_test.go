@@ -0,0 +1,64 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseTechnologies(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		technologies []technology
+		parsed       serverTechnologies
+		errWrapped   error
+		errMessage   string
+	}{
+		"empty": {},
+		"openvpn_and_wireguard": {
+			technologies: []technology{
+				{Identifier: "openvpn_udp"},
+				{Identifier: "openvpn_tcp"},
+				{Identifier: "ikev2"},
+				{
+					Identifier: "wireguard_udp",
+					Metadata: []metadata{
+						{Name: "public_key", Value: "key"},
+					},
+				},
+			},
+			parsed: serverTechnologies{
+				openvpnTCP:      true,
+				openvpnUDP:      true,
+				wireguard:       true,
+				wireguardPubKey: "key",
+			},
+		},
+		"wireguard_without_public_key": {
+			technologies: []technology{
+				{Identifier: "wireguard_udp"},
+			},
+			parsed: serverTechnologies{
+				wireguard: true,
+			},
+			errWrapped: ErrWireguardPublicKeyNotFound,
+			errMessage: "wireguard public key not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := parseTechnologies(testCase.technologies)
+
+			assert.Equal(t, testCase.parsed, parsed)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
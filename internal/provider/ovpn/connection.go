@@ -0,0 +1,24 @@
+package ovpn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var ErrOpenVPNNotSupported = errors.New("OpenVPN is not supported")
+
+func (p *Provider) GetConnection(selection settings.ServerSelection, ipv6Supported bool) (
+	connection models.Connection, err error) {
+	if selection.VPN == vpn.OpenVPN {
+		return connection, fmt.Errorf("%w: for %s", ErrOpenVPNNotSupported, p.Name())
+	}
+
+	defaults := utils.NewConnectionDefaults(0, 0, 9929) //nolint:gomnd
+	return utils.GetConnection(p.Name(),
+		p.storage, selection, defaults, ipv6Supported, p.randSource)
+}
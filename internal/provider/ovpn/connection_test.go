@@ -0,0 +1,26 @@
+package ovpn
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_GetConnection_OpenVPN(t *testing.T) {
+	t.Parallel()
+
+	provider := New(nil, nil, nil)
+	selection := settings.ServerSelection{
+		VPN: vpn.OpenVPN,
+	}.WithDefaults(providers.Ovpn)
+
+	connection, err := provider.GetConnection(selection, false)
+
+	assert.ErrorIs(t, err, ErrOpenVPNNotSupported)
+	assert.EqualError(t, err, "OpenVPN is not supported: for ovpn")
+	assert.Equal(t, models.Connection{}, connection)
+}
@@ -0,0 +1,14 @@
+package ovpn
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// OpenVPNConfig returns no configuration line since only Wireguard
+// is supported for OVPN. Settings validation rejects OpenVPN for OVPN,
+// and GetConnection returns an error for an OpenVPN server selection,
+// so this is never reached with a valid connection.
+func (p *Provider) OpenVPNConfig(models.Connection, settings.OpenVPN, bool) []string {
+	return nil
+}
@@ -0,0 +1,53 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/common"
+)
+
+var errAPINotSuccessful = errors.New("API response is not successful")
+
+func (u *Updater) FetchServers(ctx context.Context, minServers int) (
+	servers []models.Server, err error) {
+	data, err := fetchAPI(ctx, u.client)
+	if err != nil {
+		return nil, fmt.Errorf("fetching API: %w", err)
+	} else if !data.Success {
+		return nil, fmt.Errorf("%w", errAPINotSuccessful)
+	}
+
+	for _, dataCenter := range data.DataCenters {
+		for _, serverData := range dataCenter.Servers {
+			if !serverData.Online || serverData.PublicKey == "" ||
+				!serverData.IP.IsValid() {
+				continue
+			}
+			server := models.Server{
+				VPN:      vpn.Wireguard,
+				Country:  dataCenter.CountryName,
+				City:     dataCenter.City,
+				Hostname: serverData.Hostname,
+				IPs:      []netip.Addr{serverData.IP},
+				UDP:      true,
+				WgPubKey: serverData.PublicKey,
+			}
+			servers = append(servers, server)
+		}
+	}
+
+	if len(servers) < minServers {
+		return nil, fmt.Errorf("%w: %d and expected at least %d",
+			common.ErrNotEnoughServers, len(servers), minServers)
+	}
+
+	sort.Sort(models.SortableServers(servers))
+
+	return servers, nil
+}
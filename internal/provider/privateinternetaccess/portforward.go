@@ -12,6 +12,7 @@ import (
 	"net/netip"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -28,9 +29,9 @@ var (
 )
 
 // PortForward obtains a VPN server side port forwarded from PIA.
-func (p *Provider) PortForward(ctx context.Context, client *http.Client,
-	logger utils.Logger, gateway netip.Addr, serverName string) (
-	port uint16, err error) {
+func (p *Provider) PortForward(ctx context.Context,
+	objects utils.PortForwardObjects) (port uint16, err error) {
+	logger, gateway, serverName := objects.Logger, objects.Gateway, objects.ServerName
 	server, ok := p.storage.GetServerByName(providers.PrivateInternetAccess, serverName)
 	if !ok {
 		return 0, fmt.Errorf("%w: %s", ErrServerNameNotFound, serverName)
@@ -52,7 +53,12 @@ func (p *Provider) PortForward(ctx context.Context, client *http.Client,
 		return 0, fmt.Errorf("creating custom HTTP client: %w", err)
 	}
 
-	data, err := readPIAPortForwardData(p.portForwardPath)
+	username, password, err := getOpenvpnCredentials(p.authFilePath)
+	if err != nil {
+		return 0, fmt.Errorf("getting username and password: %w", err)
+	}
+
+	data, err := readPIAPortForwardData(p.portForwardPath, username, password)
 	if err != nil {
 		return 0, fmt.Errorf("reading saved port forwarded data: %w", err)
 	}
@@ -70,8 +76,8 @@ func (p *Provider) PortForward(ctx context.Context, client *http.Client,
 	}
 
 	if !dataFound || expired {
-		data, err = refreshPIAPortForwardData(ctx, client, privateIPClient, gateway,
-			p.portForwardPath, p.authFilePath)
+		data, err = refreshPIAPortForwardData(ctx, objects.Client, privateIPClient, gateway,
+			p.portForwardPath, p.tokenCachePath, username, password, objects.FileWriter, logger)
 		if err != nil {
 			return 0, fmt.Errorf("refreshing port forward data: %w", err)
 		}
@@ -92,13 +98,18 @@ var (
 )
 
 func (p *Provider) KeepPortForward(ctx context.Context,
-	gateway netip.Addr, serverName string) (err error) {
-	privateIPClient, err := newHTTPClient(serverName)
+	objects utils.PortForwardObjects) (err error) {
+	privateIPClient, err := newHTTPClient(objects.ServerName)
 	if err != nil {
 		return fmt.Errorf("creating custom HTTP client: %w", err)
 	}
 
-	data, err := readPIAPortForwardData(p.portForwardPath)
+	username, password, err := getOpenvpnCredentials(p.authFilePath)
+	if err != nil {
+		return fmt.Errorf("getting username and password: %w", err)
+	}
+
+	data, err := readPIAPortForwardData(p.portForwardPath, username, password)
 	if err != nil {
 		return fmt.Errorf("reading saved port forwarded data: %w", err)
 	}
@@ -120,7 +131,7 @@ func (p *Provider) KeepPortForward(ctx context.Context,
 			}
 			return ctx.Err()
 		case <-keepAliveTimer.C:
-			err := bindPort(ctx, privateIPClient, gateway, data)
+			err := bindPort(ctx, privateIPClient, objects.Gateway, data)
 			if err != nil {
 				return fmt.Errorf("binding port: %w", err)
 			}
@@ -133,24 +144,51 @@ func (p *Provider) KeepPortForward(ctx context.Context,
 }
 
 func refreshPIAPortForwardData(ctx context.Context, client, privateIPClient *http.Client,
-	gateway netip.Addr, portForwardPath, authFilePath string) (data piaPortForwardData, err error) {
-	data.Token, err = fetchToken(ctx, client, authFilePath)
+	gateway netip.Addr, portForwardPath, tokenCachePath, username, password string,
+	fileWriter utils.FileWriter,
+	logger utils.Logger) (data piaPortForwardData, err error) {
+	var cached bool
+	data.Token, cached, err = getToken(ctx, client, fileWriter, logger,
+		tokenCachePath, username, password)
 	if err != nil {
 		return data, fmt.Errorf("fetching token: %w", err)
 	}
 
 	data.Port, data.Signature, data.Expiration, err = fetchPortForwardData(ctx, privateIPClient, gateway, data.Token)
+	if err != nil && cached && isTokenRejected(err) {
+		// The cached token may have been revoked before its expiry,
+		// so drop it and request a new token once.
+		logger.Warn("cached token rejected, requesting a new one: " + err.Error())
+		err = utils.RemoveCachedToken(tokenCachePath)
+		if err != nil {
+			logger.Warn("cannot remove cached token: " + err.Error())
+		}
+
+		data.Token, _, err = getToken(ctx, client, fileWriter, logger,
+			tokenCachePath, username, password)
+		if err != nil {
+			return data, fmt.Errorf("fetching token: %w", err)
+		}
+		data.Port, data.Signature, data.Expiration, err = fetchPortForwardData(ctx, privateIPClient, gateway, data.Token)
+	}
 	if err != nil {
 		return data, fmt.Errorf("fetching port forwarding data: %w", err)
 	}
 
-	if err := writePIAPortForwardData(portForwardPath, data); err != nil {
+	err = writePIAPortForwardData(fileWriter, portForwardPath, username, password, data)
+	if err != nil {
 		return data, fmt.Errorf("persisting port forwarding data: %w", err)
 	}
 
 	return data, nil
 }
 
+// isTokenRejected returns true if the error given comes from
+// the PIA gateway responding, as opposed to a connection error.
+func isTokenRejected(err error) bool {
+	return errors.Is(err, ErrHTTPStatusCodeNotOK) || errors.Is(err, ErrBadResponse)
+}
+
 type piaPayload struct {
 	Token      string    `json:"token"`
 	Port       uint16    `json:"port"`
@@ -164,37 +202,33 @@ type piaPortForwardData struct {
 	Expiration time.Time `json:"expires_at"`
 }
 
-func readPIAPortForwardData(portForwardPath string) (data piaPortForwardData, err error) {
-	file, err := os.Open(portForwardPath)
-	if os.IsNotExist(err) {
-		return data, nil
-	} else if err != nil {
-		return data, err
-	}
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&data); err != nil {
-		_ = file.Close()
+// readPIAPortForwardData reads the port forwarding data saved at the
+// path given, decrypting it with the credentials given since it
+// contains the authentication token. It returns empty data if the
+// file does not exist or was written using other credentials.
+func readPIAPortForwardData(portForwardPath, username, password string) (
+	data piaPortForwardData, err error) {
+	plaintext, err := utils.ReadEncryptedFile(portForwardPath,
+		credentialsSecret(username, password))
+	if err != nil || plaintext == nil {
 		return data, err
 	}
 
-	return data, file.Close()
+	err = json.Unmarshal(plaintext, &data)
+	return data, err
 }
 
-func writePIAPortForwardData(portForwardPath string, data piaPortForwardData) (err error) {
-	file, err := os.OpenFile(portForwardPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+// writePIAPortForwardData saves the port forwarding data given to the
+// path given, encrypted with the credentials given.
+func writePIAPortForwardData(fileWriter utils.FileWriter, portForwardPath,
+	username, password string, data piaPortForwardData) (err error) {
+	plaintext, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	encoder := json.NewEncoder(file)
-
-	if err := encoder.Encode(data); err != nil {
-		_ = file.Close()
-		return err
-	}
-
-	return file.Close()
+	return utils.WriteEncryptedFile(fileWriter, portForwardPath,
+		credentialsSecret(username, password), plaintext)
 }
 
 func unpackPayload(payload string) (port uint16, token string, expiration time.Time, err error) {
@@ -233,13 +267,63 @@ var (
 	errEmptyToken = errors.New("token received is empty")
 )
 
-func fetchToken(ctx context.Context, client *http.Client,
-	authFilePath string) (token string, err error) {
-	username, password, err := getOpenvpnCredentials(authFilePath)
+// CheckCredentials checks the username and password are valid
+// by requesting an authentication token from the PIA API, unless
+// a token obtained with the same credentials is still cached.
+func CheckCredentials(ctx context.Context, client *http.Client,
+	fileWriter utils.FileWriter, logger utils.Logger,
+	tokenCachePath, username, password string) (err error) {
+	_, _, err = getToken(ctx, client, fileWriter, logger,
+		tokenCachePath, username, password)
+	return err
+}
+
+// TokenCachePath returns the file path in the data directory given
+// where the authentication token is cached, encrypted with the
+// credentials, to avoid requesting a token on each restart and
+// triggering rate limits from the PIA API.
+func TokenCachePath(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "piatoken")
+}
+
+// getToken returns the authentication token cached for the credentials
+// given if it is not expired, and requests and caches a new token otherwise.
+// Failing to read or write the cache only makes the token to be requested.
+func getToken(ctx context.Context, client *http.Client,
+	fileWriter utils.FileWriter, logger utils.Logger,
+	tokenCachePath, username, password string) (token string, cached bool, err error) {
+	secret := credentialsSecret(username, password)
+	token, err = utils.ReadCachedToken(tokenCachePath, secret, time.Now())
 	if err != nil {
-		return "", fmt.Errorf("getting username and password: %w", err)
+		logger.Warn("cannot read cached token: " + err.Error())
+	} else if token != "" {
+		return token, true, nil
 	}
 
+	token, err = requestToken(ctx, client, username, password)
+	if err != nil {
+		return "", false, err
+	}
+
+	// PIA tokens are valid for 24 hours, keep a margin
+	// so a cached token does not expire while in use.
+	const tokenLifetime = 23 * time.Hour
+	err = utils.WriteCachedToken(fileWriter, tokenCachePath, secret, token,
+		time.Now().Add(tokenLifetime))
+	if err != nil {
+		logger.Warn("cannot cache token: " + err.Error())
+	}
+	return token, false, nil
+}
+
+// credentialsSecret returns the secret used to derive the key
+// encrypting the files containing the authentication token.
+func credentialsSecret(username, password string) (secret string) {
+	return username + "\n" + password
+}
+
+func requestToken(ctx context.Context, client *http.Client,
+	username, password string) (token string, err error) {
 	errSubstitutions := map[string]string{
 		url.QueryEscape(username): "<username>",
 		url.QueryEscape(password): "<password>",
@@ -266,7 +350,10 @@ func fetchToken(ctx context.Context, client *http.Client,
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
+	if response.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("%w: %s", utils.ErrCredentialsNotValid,
+			makeNOKStatusError(response, errSubstitutions))
+	} else if response.StatusCode != http.StatusOK {
 		return "", makeNOKStatusError(response, errSubstitutions)
 	}
 
@@ -4,9 +4,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/system"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -105,3 +108,39 @@ func Test_replaceInString(t *testing.T) {
 		})
 	}
 }
+
+func Test_PIAPortForwardData(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "piaportforward.json")
+	fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+	data := piaPortForwardData{
+		Port:       1234,
+		Token:      "token",
+		Signature:  "signature",
+		Expiration: time.Unix(1000, 0).UTC(),
+	}
+
+	err := writePIAPortForwardData(fileWriter, path, "user", "password", data)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "token")
+
+	readData, err := readPIAPortForwardData(path, "user", "password")
+	require.NoError(t, err)
+	assert.Equal(t, data, readData)
+
+	readData, err = readPIAPortForwardData(path, "user", "other password")
+	require.NoError(t, err)
+	assert.Empty(t, readData)
+
+	// Data saved in plaintext by previous versions is ignored
+	const plaintextPerms = 0600
+	err = os.WriteFile(path, []byte(`{"port":1234,"token":"token"}`), plaintextPerms)
+	require.NoError(t, err)
+	readData, err = readPIAPortForwardData(path, "user", "password")
+	require.NoError(t, err)
+	assert.Empty(t, readData)
+}
@@ -3,6 +3,7 @@ package privateinternetaccess
 import (
 	"math/rand"
 	"net/http"
+	"path/filepath"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants/openvpn"
@@ -18,18 +19,21 @@ type Provider struct {
 	common.Fetcher
 	// Port forwarding
 	portForwardPath string
+	tokenCachePath  string
 	authFilePath    string
 }
 
 func New(storage common.Storage, randSource rand.Source,
-	timeNow func() time.Time, client *http.Client) *Provider {
-	const jsonPortForwardPath = "/gluetun/piaportforward.json"
+	timeNow func() time.Time, client *http.Client,
+	dataDirectory, runtimeDirectory string) *Provider {
+	jsonPortForwardPath := filepath.Join(dataDirectory, "piaportforward.json")
 	return &Provider{
 		storage:         storage,
 		timeNow:         timeNow,
 		randSource:      randSource,
 		portForwardPath: jsonPortForwardPath,
-		authFilePath:    openvpn.AuthConf,
+		tokenCachePath:  TokenCachePath(dataDirectory),
+		authFilePath:    openvpn.AuthConf(runtimeDirectory),
 		Fetcher:         updater.New(client),
 	}
 }
@@ -0,0 +1,16 @@
+package protonvpn
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/provider/protonvpn/updater"
+)
+
+// FetchOpenVPNCredentials fetches the OpenVPN username and password
+// of the Proton account from the Proton API, logging in with the
+// account email and password given.
+func FetchOpenVPNCredentials(ctx context.Context, client *http.Client,
+	email, password string) (openvpnUser, openvpnPassword string, err error) {
+	return updater.FetchOpenVPNCredentials(ctx, client, email, password)
+}
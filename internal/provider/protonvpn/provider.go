@@ -18,12 +18,13 @@ type Provider struct {
 }
 
 func New(storage common.Storage, randSource rand.Source,
-	client *http.Client, updaterWarner common.Warner) *Provider {
+	client *http.Client, updaterWarner common.Warner,
+	email, password string) *Provider {
 	return &Provider{
 		storage:         storage,
 		randSource:      randSource,
 		NoPortForwarder: utils.NewNoPortForwarding(providers.Protonvpn),
-		Fetcher:         updater.New(client, updaterWarner),
+		Fetcher:         updater.New(client, updaterWarner, email, password),
 	}
 }
 
@@ -22,18 +22,37 @@ type logicalServer struct {
 	ExitCountry string
 	Region      *string
 	City        *string
-	Servers     []physicalServer
+	// Tier is 0 for free servers and 2 for paid servers.
+	Tier uint8
+	// Features is a bit mask of the logical server features.
+	Features uint16
+	Servers  []physicalServer
 }
 
+const (
+	featureP2P       = 4
+	featureStreaming = 8
+)
+
 type physicalServer struct {
-	EntryIP netip.Addr
-	ExitIP  netip.Addr
-	Domain  string
-	Status  uint8
+	EntryIP         netip.Addr
+	ExitIP          netip.Addr
+	Domain          string
+	Status          uint8
+	X25519PublicKey string
 }
 
-func fetchAPI(ctx context.Context, client *http.Client) (
+// fetchAPI fetches the logical servers from the Proton API, using
+// the session given if it is set to obtain the servers available to
+// the account, which is required for the full list of servers.
+func fetchAPI(ctx context.Context, client *http.Client, session apiSession) (
 	data apiData, err error) {
+	if session.uid != "" {
+		err = doAPIRequest(ctx, client, http.MethodGet,
+			"/vpn/v1/logicals?SecureCoreFilter=all", session, nil, &data)
+		return data, err
+	}
+
 	const url = "https://api.protonmail.ch/vpn/logicals"
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -0,0 +1,196 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var (
+	ErrTwoFactorNotSupported = errors.New("two factor authentication is not supported")
+	ErrAPIResponseCodeNotOK  = errors.New("API response code is not OK")
+)
+
+const (
+	apiBaseURL = "https://vpn-api.proton.me"
+	// apiAppVersion is the application version header value,
+	// which the Proton API requires to be set.
+	apiAppVersion = "linux-vpn@4.1.10"
+)
+
+// apiSession is an authenticated Proton API session.
+type apiSession struct {
+	uid         string
+	accessToken string
+}
+
+// login authenticates with the Proton account email and password
+// given using the Proton SRP protocol, and returns the session.
+func login(ctx context.Context, client *http.Client,
+	email, password string) (session apiSession, err error) {
+	// An unauthenticated session is required for the auth calls.
+	var sessionData struct {
+		UID         string
+		AccessToken string
+	}
+	err = doAPIRequest(ctx, client, http.MethodPost, "/auth/v4/sessions",
+		session, nil, &sessionData)
+	if err != nil {
+		return session, fmt.Errorf("creating unauthenticated session: %w", err)
+	}
+	session = apiSession{uid: sessionData.UID, accessToken: sessionData.AccessToken}
+
+	var info struct {
+		Version         uint8
+		Modulus         string
+		ServerEphemeral string
+		Salt            string
+		SRPSession      string
+	}
+	infoRequest := struct {
+		Username string
+		Intent   string
+	}{Username: email, Intent: "Proton"}
+	err = doAPIRequest(ctx, client, http.MethodPost, "/core/v4/auth/info",
+		session, infoRequest, &info)
+	if err != nil {
+		return session, fmt.Errorf("fetching auth info: %w", err)
+	}
+
+	proofs, err := computeSRPProofs(info.Version, info.Modulus,
+		info.ServerEphemeral, info.Salt, []byte(password))
+	if err != nil {
+		return session, fmt.Errorf("computing SRP proofs: %w", err)
+	}
+
+	authRequest := struct {
+		Username          string
+		ClientEphemeral   string
+		ClientProof       string
+		SRPSession        string
+		PersistentCookies uint8
+	}{
+		Username:        email,
+		ClientEphemeral: base64.StdEncoding.EncodeToString(proofs.clientEphemeral),
+		ClientProof:     base64.StdEncoding.EncodeToString(proofs.clientProof),
+		SRPSession:      info.SRPSession,
+	}
+	var auth struct {
+		UID         string
+		AccessToken string
+		ServerProof string
+		TwoFactor   struct {
+			Enabled uint8
+		} `json:"2FA"`
+	}
+	err = doAPIRequest(ctx, client, http.MethodPost, "/core/v4/auth",
+		session, authRequest, &auth)
+	if err != nil {
+		return session, fmt.Errorf("authenticating: %w", err)
+	}
+
+	serverProof, err := base64.StdEncoding.DecodeString(auth.ServerProof)
+	if err != nil {
+		return session, fmt.Errorf("decoding server proof: %w", err)
+	} else if subtle.ConstantTimeCompare(serverProof, proofs.expectedServerProof) != 1 {
+		return session, fmt.Errorf("%w", ErrSRPServerProofMismatch)
+	}
+
+	session = apiSession{uid: auth.UID, accessToken: auth.AccessToken}
+	if auth.TwoFactor.Enabled != 0 {
+		_ = logout(ctx, client, session)
+		return session, fmt.Errorf("%w", ErrTwoFactorNotSupported)
+	}
+
+	return session, nil
+}
+
+// logout revokes the session given.
+func logout(ctx context.Context, client *http.Client, session apiSession) (err error) {
+	return doAPIRequest(ctx, client, http.MethodDelete, "/auth/v4", session, nil, nil)
+}
+
+// doAPIRequest sends a request to the Proton API using the session
+// given if it is set, with the request data given encoded as JSON if
+// not nil, and decodes the JSON response body into the response data
+// given if not nil.
+func doAPIRequest(ctx context.Context, client *http.Client, method, path string,
+	session apiSession, requestData, responseData any) (err error) {
+	var body io.Reader
+	if requestData != nil {
+		b, err := json.Marshal(requestData)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("x-pm-appversion", apiAppVersion)
+	if requestData != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if session.uid != "" {
+		request.Header.Set("x-pm-uid", session.uid)
+		request.Header.Set("Authorization", "Bearer "+session.accessToken)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	var apiError struct {
+		Code  uint
+		Error string
+	}
+	_ = json.Unmarshal(b, &apiError)
+	const (
+		codeOK              = 1000
+		codeWrongPassword   = 8002
+		codeAccountNotFound = 2011
+	)
+	switch {
+	case apiError.Code == codeWrongPassword || apiError.Code == codeAccountNotFound ||
+		response.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", utils.ErrCredentialsNotValid, apiError.Error)
+	case response.StatusCode != http.StatusOK:
+		details := apiError.Error
+		if details == "" {
+			details = strings.TrimSpace(string(b))
+		}
+		return fmt.Errorf("%w: %d %s: %s", ErrHTTPStatusCodeNotOK,
+			response.StatusCode, response.Status, details)
+	case apiError.Code != 0 && apiError.Code != codeOK:
+		return fmt.Errorf("%w: %d: %s", ErrAPIResponseCodeNotOK,
+			apiError.Code, apiError.Error)
+	}
+
+	if responseData == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(b, responseData)
+	if err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+	return nil
+}
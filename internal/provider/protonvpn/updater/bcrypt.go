@@ -0,0 +1,55 @@
+package updater
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+var ErrBcryptPasswordTooLong = errors.New("password is longer than 72 bytes")
+
+// bcryptEncoding is the base64 encoding used by bcrypt.
+var bcryptEncoding = base64.NewEncoding( //nolint:gochecknoglobals
+	"./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").
+	WithPadding(base64.NoPadding)
+
+// bcryptHash returns the bcrypt hash string of the password using
+// the 16 bytes salt and the cost given. This is needed since the
+// golang.org/x/crypto/bcrypt package does not allow to set the salt,
+// which the Proton password hashing requires.
+func bcryptHash(password, salt []byte, cost uint8) (hash string, err error) {
+	const maxPasswordLength = 72
+	if len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w", ErrBcryptPasswordTooLong)
+	}
+
+	// Use the trailing NULL in the key for compatibility
+	// with C bcrypt implementations.
+	key := append(password[:len(password):len(password)], 0)
+	cipher, err := blowfish.NewSaltedCipher(key, salt)
+	if err != nil {
+		return "", fmt.Errorf("creating blowfish cipher: %w", err)
+	}
+	rounds := uint64(1) << cost
+	for i := uint64(0); i < rounds; i++ {
+		blowfish.ExpandKey(key, cipher)
+		blowfish.ExpandKey(salt, cipher)
+	}
+
+	cipherData := []byte("OrpheanBeholderScryDoubt")
+	const blockSize, encryptions = 8, 64
+	for i := 0; i < len(cipherData); i += blockSize {
+		for j := 0; j < encryptions; j++ {
+			cipher.Encrypt(cipherData[i:i+blockSize], cipherData[i:i+blockSize])
+		}
+	}
+
+	// Only 23 of the 24 bytes are encoded for compatibility
+	// with C bcrypt implementations.
+	const encodedLength = 23
+	return fmt.Sprintf("$2y$%02d$%s%s", cost,
+		bcryptEncoding.EncodeToString(salt),
+		bcryptEncoding.EncodeToString(cipherData[:encodedLength])), nil
+}
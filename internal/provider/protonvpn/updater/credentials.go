@@ -0,0 +1,34 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// FetchOpenVPNCredentials logs in with the Proton account email
+// and password given, and returns the OpenVPN credentials of the
+// account, which differ from the account credentials.
+func FetchOpenVPNCredentials(ctx context.Context, client *http.Client,
+	email, password string) (openvpnUser, openvpnPassword string, err error) {
+	session, err := login(ctx, client, email, password)
+	if err != nil {
+		return "", "", fmt.Errorf("logging in: %w", err)
+	}
+	defer func() {
+		_ = logout(ctx, client, session)
+	}()
+
+	var data struct {
+		VPN struct {
+			Name     string
+			Password string
+		}
+	}
+	err = doAPIRequest(ctx, client, http.MethodGet, "/vpn/v2", session, nil, &data)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching VPN information: %w", err)
+	}
+
+	return data.VPN.Name, data.VPN.Password, nil
+}
@@ -9,26 +9,43 @@ import (
 
 type ipToServer map[string]models.Server
 
+// add adds an OpenVPN server and, if the Wireguard public key
+// given is not empty, a Wireguard server for the entry IP address.
+// Wireguard servers can be reached over UDP or over TLS (Stealth).
 func (its ipToServer) add(country, region, city, name, hostname string,
-	free bool, entryIP netip.Addr) {
-	key := entryIP.String()
-
-	server, ok := its[key]
-	if ok {
-		return
+	free, p2p, stream bool, entryIP netip.Addr, wgPubKey string) {
+	vpnTypes := []string{vpn.OpenVPN}
+	if wgPubKey != "" {
+		vpnTypes = append(vpnTypes, vpn.Wireguard)
 	}
 
-	server.VPN = vpn.OpenVPN
-	server.Country = country
-	server.Region = region
-	server.City = city
-	server.ServerName = name
-	server.Hostname = hostname
-	server.Free = free
-	server.UDP = true
-	server.TCP = true
-	server.IPs = []netip.Addr{entryIP}
-	its[key] = server
+	for _, vpnType := range vpnTypes {
+		key := vpnType + entryIP.String()
+
+		server, ok := its[key]
+		if ok {
+			continue
+		}
+
+		server.VPN = vpnType
+		server.Country = country
+		server.Region = region
+		server.City = city
+		server.ServerName = name
+		server.Hostname = hostname
+		server.Free = free
+		server.P2P = p2p
+		server.Stream = stream
+		if vpnType == vpn.OpenVPN {
+			server.UDP = true
+			server.TCP = true
+		} else {
+			server.WgPubKey = wgPubKey
+			server.Stealth = true
+		}
+		server.IPs = []netip.Addr{entryIP}
+		its[key] = server
+	}
 }
 
 func (its ipToServer) toServersSlice() (servers []models.Server) {
@@ -13,7 +13,18 @@ import (
 
 func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 	servers []models.Server, err error) {
-	data, err := fetchAPI(ctx, u.client)
+	var session apiSession
+	if u.email != "" {
+		session, err = login(ctx, u.client, u.email, u.password)
+		if err != nil {
+			return nil, fmt.Errorf("logging in: %w", err)
+		}
+		defer func() {
+			_ = logout(ctx, u.client, session)
+		}()
+	}
+
+	data, err := fetchAPI(ctx, u.client, session)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +48,8 @@ func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 		// TODO v4 remove `name` field because of
 		// https://github.com/qdm12/gluetun/issues/1018#issuecomment-1151750179
 		name := logicalServer.Name
+		p2p := logicalServer.Features&featureP2P != 0
+		stream := logicalServer.Features&featureStreaming != 0
 		for _, physicalServer := range logicalServer.Servers {
 			if physicalServer.Status == 0 { // disabled so skip server
 				u.warner.Warn("ignoring server " + physicalServer.Domain + " with status 0")
@@ -46,10 +59,12 @@ func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 			hostname := physicalServer.Domain
 			entryIP := physicalServer.EntryIP
 
-			lowerCaseName := strings.ToLower(name)
 			var free bool
-			if strings.Contains(hostname, "free") || strings.Contains(lowerCaseName, "free") {
-				free = true
+			if session.uid != "" {
+				free = logicalServer.Tier == 0
+			} else {
+				lowerCaseName := strings.ToLower(name)
+				free = strings.Contains(hostname, "free") || strings.Contains(lowerCaseName, "free")
 			}
 
 			// Note: for multi-hop use the server name or hostname
@@ -60,7 +75,8 @@ func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 				u.warner.Warn(warning)
 			}
 
-			ipToServer.add(country, region, city, name, hostname, free, entryIP)
+			ipToServer.add(country, region, city, name, hostname,
+				free, p2p, stream, entryIP, physicalServer.X25519PublicKey)
 		}
 	}
 
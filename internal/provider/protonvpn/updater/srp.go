@@ -0,0 +1,209 @@
+package updater
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrSRPVersionNotSupported  = errors.New("SRP auth version is not supported")
+	ErrSRPModulusNotValid      = errors.New("SRP modulus is not valid")
+	ErrSRPServerEphemeralValue = errors.New("SRP server ephemeral value is not valid")
+	ErrSRPServerProofMismatch  = errors.New("SRP server proof does not match")
+)
+
+// srpBitLength is the bit length of the Proton SRP modulus.
+const srpBitLength = 2048
+
+// srpProofs contains the client values to send to the server,
+// and the server proof expected in return.
+type srpProofs struct {
+	clientEphemeral     []byte
+	clientProof         []byte
+	expectedServerProof []byte
+}
+
+// computeSRPProofs computes the Proton SRP proofs from the auth info
+// returned by the server and the password given. The modulus must be
+// the base64 encoded modulus, possibly inside a PGP signed message, and
+// the server ephemeral and salt must be base64 encoded.
+func computeSRPProofs(version uint8, signedModulus, serverEphemeral,
+	salt string, password []byte) (proofs srpProofs, err error) {
+	modulusBytes, err := parseSRPModulus(signedModulus)
+	if err != nil {
+		return proofs, err
+	}
+
+	serverEphemeralBytes, err := base64.StdEncoding.DecodeString(serverEphemeral)
+	if err != nil {
+		return proofs, fmt.Errorf("decoding server ephemeral: %w", err)
+	}
+
+	hashedPassword, err := hashSRPPassword(version, password, salt, modulusBytes)
+	if err != nil {
+		return proofs, fmt.Errorf("hashing password: %w", err)
+	}
+
+	clientSecret, err := generateSRPClientSecret(leBytesToInt(modulusBytes))
+	if err != nil {
+		return proofs, fmt.Errorf("generating client secret: %w", err)
+	}
+
+	return computeSRPProofsWithSecret(modulusBytes, serverEphemeralBytes,
+		hashedPassword, clientSecret)
+}
+
+func computeSRPProofsWithSecret(modulusBytes, serverEphemeralBytes,
+	hashedPassword []byte, clientSecret *big.Int) (proofs srpProofs, err error) {
+	modulus := leBytesToInt(modulusBytes)
+	modulusMinusOne := new(big.Int).Sub(modulus, big.NewInt(1))
+	generator := big.NewInt(2) //nolint:gomnd
+
+	serverEphemeral := leBytesToInt(serverEphemeralBytes)
+	if serverEphemeral.Cmp(big.NewInt(1)) <= 0 || serverEphemeral.Cmp(modulusMinusOne) >= 0 {
+		return proofs, fmt.Errorf("%w", ErrSRPServerEphemeralValue)
+	}
+
+	multiplier := leBytesToInt(expandHash(
+		append(intToLEBytes(generator), modulusBytes...)))
+	multiplier.Mod(multiplier, modulus)
+
+	clientEphemeral := new(big.Int).Exp(generator, clientSecret, modulus)
+	clientEphemeralBytes := intToLEBytes(clientEphemeral)
+
+	scramblingParam := leBytesToInt(expandHash(
+		append(clientEphemeralBytes, serverEphemeralBytes...)))
+	if scramblingParam.Sign() == 0 {
+		return proofs, fmt.Errorf("%w: scrambling parameter is zero",
+			ErrSRPServerEphemeralValue)
+	}
+
+	hashedPasswordInt := leBytesToInt(hashedPassword)
+
+	// S = (B - k*g^x) ^ (a + u*x) mod N
+	subtracted := new(big.Int).Exp(generator, hashedPasswordInt, modulus)
+	subtracted.Mul(subtracted, multiplier)
+	subtracted.Sub(serverEphemeral, subtracted)
+	subtracted.Mod(subtracted, modulus)
+	exponent := new(big.Int).Mul(scramblingParam, hashedPasswordInt)
+	exponent.Add(exponent, clientSecret)
+	exponent.Mod(exponent, modulusMinusOne)
+	sharedSession := new(big.Int).Exp(subtracted, exponent, modulus)
+	sharedSessionBytes := intToLEBytes(sharedSession)
+
+	proofs.clientEphemeral = clientEphemeralBytes
+	proofs.clientProof = expandHash(bytes.Join([][]byte{
+		clientEphemeralBytes, serverEphemeralBytes, sharedSessionBytes}, nil))
+	proofs.expectedServerProof = expandHash(bytes.Join([][]byte{
+		clientEphemeralBytes, sharedSessionBytes, proofs.clientProof}, nil))
+	return proofs, nil
+}
+
+// parseSRPModulus extracts and decodes the base64 modulus from the
+// PGP signed message given. The signature is not verified, and the
+// modulus is only checked to have the expected bit length.
+func parseSRPModulus(signedModulus string) (modulus []byte, err error) {
+	encoded := signedModulus
+	const signatureHeader = "-----BEGIN PGP SIGNATURE-----"
+	if strings.HasPrefix(signedModulus, "-----BEGIN PGP SIGNED MESSAGE-----") {
+		_, body, found := strings.Cut(signedModulus, "\n\n")
+		if !found {
+			return nil, fmt.Errorf("%w: signed message body not found", ErrSRPModulusNotValid)
+		}
+		encoded, _, found = strings.Cut(body, signatureHeader)
+		if !found {
+			return nil, fmt.Errorf("%w: signature not found", ErrSRPModulusNotValid)
+		}
+	}
+
+	modulus, err = base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSRPModulusNotValid, err)
+	}
+
+	const expectedLength = srpBitLength / 8
+	if len(modulus) != expectedLength {
+		return nil, fmt.Errorf("%w: %d bytes instead of %d",
+			ErrSRPModulusNotValid, len(modulus), expectedLength)
+	}
+	return modulus, nil
+}
+
+// hashSRPPassword hashes the password as done by Proton for
+// auth versions 3 and 4, using bcrypt with the salt given
+// suffixed with "proton", and expanding the result with the modulus.
+func hashSRPPassword(version uint8, password []byte, salt string,
+	modulus []byte) (hashed []byte, err error) {
+	const minVersion = 3
+	if version < minVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSRPVersionNotSupported, version)
+	}
+
+	decodedSalt, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("decoding salt: %w", err)
+	}
+
+	const cost = 10
+	bcryptSalt := append(decodedSalt, []byte("proton")...)
+	crypted, err := bcryptHash(password, bcryptSalt, cost)
+	if err != nil {
+		return nil, err
+	}
+
+	return expandHash(append([]byte(crypted), modulus...)), nil
+}
+
+// generateSRPClientSecret generates a random client secret
+// larger than twice the bit length and smaller than the modulus
+// minus one.
+func generateSRPClientSecret(modulus *big.Int) (secret *big.Int, err error) {
+	lowerBound := big.NewInt(srpBitLength * 2) //nolint:gomnd
+	upperBound := new(big.Int).Sub(modulus, big.NewInt(1))
+	for {
+		secret, err = rand.Int(rand.Reader, upperBound)
+		if err != nil {
+			return nil, err
+		}
+		if secret.Cmp(lowerBound) > 0 {
+			return secret, nil
+		}
+	}
+}
+
+// expandHash returns the concatenation of the SHA512 hashes
+// of the data given suffixed with the bytes 0, 1, 2 and 3.
+func expandHash(data []byte) (expanded []byte) {
+	const parts = 4
+	expanded = make([]byte, 0, parts*sha512.Size)
+	for i := byte(0); i < parts; i++ {
+		sum := sha512.Sum512(append(data[:len(data):len(data)], i))
+		expanded = append(expanded, sum[:]...)
+	}
+	return expanded
+}
+
+// leBytesToInt converts the little endian bytes given to an integer.
+func leBytesToInt(b []byte) (n *big.Int) {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(reversed)
+}
+
+// intToLEBytes converts the integer given to little endian bytes
+// padded to the SRP bit length.
+func intToLEBytes(n *big.Int) (b []byte) {
+	b = n.FillBytes(make([]byte, srpBitLength/8)) //nolint:gomnd
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
@@ -0,0 +1,127 @@
+package updater
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_bcryptHash(t *testing.T) {
+	t.Parallel()
+
+	password := []byte("correct horse battery staple")
+	salt := []byte("0123456789proton")
+	const cost = 4
+
+	hash, err := bcryptHash(password, salt, cost)
+	require.NoError(t, err)
+
+	assert.Equal(t, "$2y$04$", hash[:7])
+	err = bcrypt.CompareHashAndPassword([]byte(hash), password)
+	assert.NoError(t, err)
+	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte("wrong password"))
+	assert.ErrorIs(t, err, bcrypt.ErrMismatchedHashAndPassword)
+}
+
+func Test_parseSRPModulus(t *testing.T) {
+	t.Parallel()
+
+	modulus := bytes.Repeat([]byte{0xab}, srpBitLength/8)
+	encoded := base64.StdEncoding.EncodeToString(modulus)
+
+	testCases := map[string]struct {
+		signedModulus string
+		modulus       []byte
+		errMessage    string
+	}{
+		"plain": {
+			signedModulus: encoded,
+			modulus:       modulus,
+		},
+		"signed message": {
+			signedModulus: "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\n" +
+				encoded + "\n-----BEGIN PGP SIGNATURE-----\nVersion: x\n\nabc\n" +
+				"-----END PGP SIGNATURE-----\n",
+			modulus: modulus,
+		},
+		"signature missing": {
+			signedModulus: "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\n" + encoded,
+			errMessage:    "SRP modulus is not valid: signature not found",
+		},
+		"wrong length": {
+			signedModulus: base64.StdEncoding.EncodeToString([]byte{1, 2, 3}),
+			errMessage:    "SRP modulus is not valid: 3 bytes instead of 256",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			modulus, err := parseSRPModulus(testCase.signedModulus)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.modulus, modulus)
+		})
+	}
+}
+
+// Test_computeSRPProofs runs the server side of the SRP exchange
+// to check the client proofs match the ones computed by the server.
+func Test_computeSRPProofs(t *testing.T) {
+	t.Parallel()
+
+	modulus, err := rand.Prime(rand.Reader, srpBitLength)
+	require.NoError(t, err)
+	modulusBytes := intToLEBytes(modulus)
+	generator := big.NewInt(2)
+
+	password := []byte("password")
+	salt := base64.StdEncoding.EncodeToString([]byte("0123456789"))
+	const version = 4
+	hashedPassword, err := hashSRPPassword(version, password, salt, modulusBytes)
+	require.NoError(t, err)
+	verifier := new(big.Int).Exp(generator, leBytesToInt(hashedPassword), modulus)
+
+	serverSecret, err := generateSRPClientSecret(modulus)
+	require.NoError(t, err)
+	multiplier := leBytesToInt(expandHash(append(intToLEBytes(generator), modulusBytes...)))
+	multiplier.Mod(multiplier, modulus)
+	serverEphemeral := new(big.Int).Mul(multiplier, verifier)
+	serverEphemeral.Add(serverEphemeral, new(big.Int).Exp(generator, serverSecret, modulus))
+	serverEphemeral.Mod(serverEphemeral, modulus)
+	serverEphemeralBytes := intToLEBytes(serverEphemeral)
+
+	proofs, err := computeSRPProofs(version,
+		base64.StdEncoding.EncodeToString(modulusBytes),
+		base64.StdEncoding.EncodeToString(serverEphemeralBytes),
+		salt, password)
+	require.NoError(t, err)
+
+	// S = (A * v^u) ^ b mod N
+	clientEphemeral := leBytesToInt(proofs.clientEphemeral)
+	scramblingParam := leBytesToInt(expandHash(
+		append(proofs.clientEphemeral, serverEphemeralBytes...)))
+	sharedSession := new(big.Int).Exp(verifier, scramblingParam, modulus)
+	sharedSession.Mul(sharedSession, clientEphemeral)
+	sharedSession.Exp(sharedSession, serverSecret, modulus)
+	sharedSessionBytes := intToLEBytes(sharedSession)
+
+	expectedClientProof := expandHash(bytes.Join([][]byte{
+		proofs.clientEphemeral, serverEphemeralBytes, sharedSessionBytes}, nil))
+	assert.Equal(t, expectedClientProof, proofs.clientProof)
+	serverProof := expandHash(bytes.Join([][]byte{
+		proofs.clientEphemeral, sharedSessionBytes, expectedClientProof}, nil))
+	assert.Equal(t, serverProof, proofs.expectedServerProof)
+}
@@ -9,11 +9,18 @@ import (
 type Updater struct {
 	client *http.Client
 	warner common.Warner
+	// email and password are the optional Proton account
+	// credentials used to fetch servers from the API.
+	email    string
+	password string
 }
 
-func New(client *http.Client, warner common.Warner) *Updater {
+func New(client *http.Client, warner common.Warner,
+	email, password string) *Updater {
 	return &Updater{
-		client: client,
-		warner: warner,
+		client:   client,
+		warner:   warner,
+		email:    email,
+		password: password,
 	}
 }
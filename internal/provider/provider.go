@@ -2,8 +2,6 @@ package provider
 
 import (
 	"context"
-	"net/http"
-	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
@@ -21,9 +19,7 @@ type Provider interface {
 }
 
 type PortForwarder interface {
-	PortForward(ctx context.Context, client *http.Client,
-		logger utils.Logger, gateway netip.Addr, serverName string) (
+	PortForward(ctx context.Context, objects utils.PortForwardObjects) (
 		port uint16, err error)
-	KeepPortForward(ctx context.Context, gateway netip.Addr,
-		serverName string) (err error)
+	KeepPortForward(ctx context.Context, objects utils.PortForwardObjects) (err error)
 }
@@ -0,0 +1,7 @@
+package utils
+
+import "errors"
+
+// ErrCredentialsNotValid is returned when the provider API
+// rejects the credentials configured.
+var ErrCredentialsNotValid = errors.New("wrong credentials")
@@ -81,11 +81,46 @@ func filterServer(server models.Server,
 		return true
 	}
 
+	if filterByExclusions(server.Country, selection.ExcludedCountries) {
+		return true
+	}
+
+	if filterByExclusions(server.Region, selection.ExcludedRegions) {
+		return true
+	}
+
+	if filterByExclusions(server.City, selection.ExcludedCities) {
+		return true
+	}
+
+	if filterByExclusions(server.ISP, selection.ExcludedISPs) {
+		return true
+	}
+
+	if filterByExclusions(server.ServerName, selection.ExcludedNames) {
+		return true
+	}
+
+	if filterByExclusions(server.Hostname, selection.ExcludedHostnames) {
+		return true
+	}
+
 	// TODO filter port forward server for PIA
 
 	return false
 }
 
+// filterByExclusions returns true if the value is one of the
+// exclusions given. Exclusions are applied after the filters above.
+func filterByExclusions(value string, exclusions []string) (filtered bool) {
+	for _, exclusion := range exclusions {
+		if strings.EqualFold(value, exclusion) {
+			return true
+		}
+	}
+	return false
+}
+
 func filterByPossibilities[T string | uint16](value T, possibilities []T) (filtered bool) {
 	if len(possibilities) == 0 {
 		return false
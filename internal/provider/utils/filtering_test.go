@@ -218,6 +218,33 @@ func Test_FilterServers(t *testing.T) {
 				{Hostname: "b", VPN: vpn.OpenVPN, UDP: true},
 			},
 		},
+		"exclude by country": {
+			selection: settings.ServerSelection{
+				ExcludedCountries: []string{"B", "c"},
+			}.WithDefaults(providers.Mullvad),
+			servers: []models.Server{
+				{Country: "a", VPN: vpn.OpenVPN, UDP: true},
+				{Country: "b", VPN: vpn.OpenVPN, UDP: true},
+				{Country: "c", VPN: vpn.OpenVPN, UDP: true},
+			},
+			filtered: []models.Server{
+				{Country: "a", VPN: vpn.OpenVPN, UDP: true},
+			},
+		},
+		"filter and exclude by hostname": {
+			selection: settings.ServerSelection{
+				Countries:         []string{"a"},
+				ExcludedHostnames: []string{"x"},
+			}.WithDefaults(providers.Mullvad),
+			servers: []models.Server{
+				{Country: "a", Hostname: "x", VPN: vpn.OpenVPN, UDP: true},
+				{Country: "a", Hostname: "y", VPN: vpn.OpenVPN, UDP: true},
+				{Country: "b", Hostname: "z", VPN: vpn.OpenVPN, UDP: true},
+			},
+			filtered: []models.Server{
+				{Country: "a", Hostname: "y", VPN: vpn.OpenVPN, UDP: true},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
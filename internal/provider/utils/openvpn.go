@@ -6,7 +6,6 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
-	"github.com/qdm12/gluetun/internal/constants/openvpn"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn/pkcs8"
 )
@@ -66,10 +65,6 @@ func OpenVPNConfig(provider OpenVPNProviderSettings,
 	lines.add("proto", connection.Protocol)
 	lines.add("remote", connection.IP.String(), fmt.Sprint(connection.Port))
 
-	if *settings.User != "" {
-		lines.add("auth-user-pass", openvpn.AuthConf)
-	}
-
 	if !provider.AuthToken {
 		lines.add("pull-filter", "ignore", `"auth-token"`) // prevent auth failed loops
 	}
@@ -127,8 +122,9 @@ func OpenVPNConfig(provider OpenVPNProviderSettings,
 		lines.add("auth", auth)
 	}
 
-	if provider.TunMTU > 0 {
-		lines.add("tun-mtu", fmt.Sprint(provider.TunMTU))
+	tunMTU := defaultUint16(*settings.TunMTU, provider.TunMTU)
+	if tunMTU > 0 {
+		lines.add("tun-mtu", fmt.Sprint(tunMTU))
 	}
 
 	if provider.TunMTUExtra > 0 {
@@ -140,8 +136,9 @@ func OpenVPNConfig(provider OpenVPNProviderSettings,
 		lines.add("mssfix", fmt.Sprint(mssFix))
 	}
 
-	if provider.Fragment > 0 {
-		lines.add("fragment", fmt.Sprint(provider.Fragment))
+	fragment := defaultUint16(*settings.Fragment, provider.Fragment)
+	if fragment > 0 && connection.Protocol == constants.UDP {
+		lines.add("fragment", fmt.Sprint(fragment))
 	}
 
 	if provider.SndBuf > 0 {
@@ -189,10 +186,16 @@ func OpenVPNConfig(provider OpenVPNProviderSettings,
 	if provider.RSAKey != "" {
 		lines.addLines(WrapOpenvpnRSAKey(provider.RSAKey))
 	}
-	if provider.TLSAuth != "" {
+	switch {
+	case *settings.TLSCryptV2 != "":
+		lines.addLines(WrapOpenvpnTLSCryptV2(*settings.TLSCryptV2))
+	case *settings.StaticKey != "" && provider.TLSAuth != "":
+		lines.addLines(WrapOpenvpnTLSAuth(*settings.StaticKey))
+	case *settings.StaticKey != "":
+		lines.addLines(WrapOpenvpnTLSCrypt(*settings.StaticKey))
+	case provider.TLSAuth != "":
 		lines.addLines(WrapOpenvpnTLSAuth(provider.TLSAuth))
-	}
-	if provider.TLSCrypt != "" {
+	case provider.TLSCrypt != "":
 		lines.addLines(WrapOpenvpnTLSCrypt(provider.TLSCrypt))
 	}
 
@@ -207,7 +210,6 @@ func OpenVPNConfig(provider OpenVPNProviderSettings,
 			// TODO return an error instead.
 			panic(fmt.Sprintf("upgrading encrypted key: %s", err))
 		}
-		lines.add("askpass", openvpn.AskPassPath)
 		lines.addLines(WrapOpenvpnEncryptedKey(encryptedBase64DERKey))
 	}
 
@@ -344,3 +346,13 @@ func WrapOpenvpnTLSCrypt(staticKeyV1 string) (lines []string) {
 		"</tls-crypt>",
 	}
 }
+
+func WrapOpenvpnTLSCryptV2(clientKey string) (lines []string) {
+	return []string{
+		"<tls-crypt-v2>",
+		"-----BEGIN OpenVPN tls-crypt-v2 client key-----",
+		clientKey,
+		"-----END OpenVPN tls-crypt-v2 client key-----",
+		"</tls-crypt-v2>",
+	}
+}
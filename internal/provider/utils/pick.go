@@ -14,6 +14,9 @@ import (
 var ErrNoConnectionToPickFrom = errors.New("no connection to pick from")
 
 // pickConnection picks a connection from a pool of connections.
+// If the server IP is set, it finds the connection corresponding
+// to this server IP, such that its hostname and public key are
+// the ones of the server from the servers data.
 // If the VPN protocol is Wireguard and the target IP is set,
 // it finds the connection corresponding to this target IP.
 // Otherwise, it picks a random connection from the pool of connections
@@ -25,6 +28,16 @@ func pickConnection(connections []models.Connection,
 		return connection, ErrNoConnectionToPickFrom
 	}
 
+	serverIPSet := selection.ServerIP.IsValid() && !selection.ServerIP.IsUnspecified()
+	if serverIPSet {
+		connection, err = getTargetIPConnection(connections, selection.ServerIP)
+		if err != nil {
+			return connection, fmt.Errorf("pinned server IP address %s: %w",
+				selection.ServerIP, err)
+		}
+		return connection, nil
+	}
+
 	targetIPSet := selection.TargetIP.IsValid() && !selection.TargetIP.IsUnspecified()
 
 	if targetIPSet && selection.VPN == vpn.Wireguard {
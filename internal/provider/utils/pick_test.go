@@ -2,8 +2,10 @@ package utils
 
 import (
 	"math/rand"
+	"net/netip"
 	"testing"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -24,3 +26,26 @@ func Test_pickRandomConnection(t *testing.T) {
 	connection = pickRandomConnection(connections, source)
 	assert.Equal(t, models.Connection{Port: 2}, connection)
 }
+
+func Test_pickConnection_serverIP(t *testing.T) {
+	t.Parallel()
+	connections := []models.Connection{
+		{IP: netip.AddrFrom4([4]byte{1, 1, 1, 1}), Hostname: "a"},
+		{IP: netip.AddrFrom4([4]byte{2, 2, 2, 2}), Hostname: "b"},
+	}
+	selection := settings.ServerSelection{
+		TargetIP: netip.IPv4Unspecified(),
+		ServerIP: netip.AddrFrom4([4]byte{2, 2, 2, 2}),
+	}
+	source := rand.NewSource(0)
+
+	connection, err := pickConnection(connections, selection, source)
+	assert.NoError(t, err)
+	assert.Equal(t, connections[1], connection)
+
+	selection.ServerIP = netip.AddrFrom4([4]byte{3, 3, 3, 3})
+	_, err = pickConnection(connections, selection, source)
+	assert.ErrorIs(t, err, errTargetIPNotFound)
+	assert.EqualError(t, err, "pinned server IP address 3.3.3.3: "+
+		"target IP address not found: in 2 filtered connections")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 )
 
@@ -15,6 +16,10 @@ func getPort(selection settings.ServerSelection,
 		if customPort > 0 {
 			return customPort
 		}
+		if *selection.Wireguard.Protocol == constants.TLS {
+			const defaultWireguardTLS = 443
+			return defaultWireguardTLS
+		}
 		checkDefined("Wireguard", defaultWireguard)
 		return defaultWireguard
 	default: // OpenVPN
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"net/http"
+	"net/netip"
+)
+
+// PortForwardObjects contains fields that may or may not need to be set
+// depending on the port forwarding code of each provider.
+type PortForwardObjects struct {
+	// Logger is used to log information and warnings.
+	Logger Logger
+	// Gateway is the VPN gateway IP address, used by
+	// Private Internet Access.
+	Gateway netip.Addr
+	// Client is the HTTP client to use for API requests.
+	Client *http.Client
+	// ServerName is the VPN server name, used by
+	// Private Internet Access.
+	ServerName string
+	// Username is the account username, used by Windscribe.
+	Username string
+	// Password is the account password, used by Windscribe.
+	Password string
+	// FileWriter is used to write files owned by the
+	// configured user, such as cached tokens.
+	FileWriter FileWriter
+}
@@ -7,8 +7,16 @@ import (
 )
 
 func getProtocol(selection settings.ServerSelection) (protocol string) {
-	if selection.VPN == vpn.OpenVPN && *selection.OpenVPN.TCP {
-		return constants.TCP
+	switch selection.VPN {
+	case vpn.OpenVPN:
+		if *selection.OpenVPN.TCP {
+			return constants.TCP
+		}
+	case vpn.Wireguard:
+		if *selection.Wireguard.Protocol == constants.TLS {
+			// Wireguard packets are tunneled through TLS over TCP
+			return constants.TCP
+		}
 	}
 	return constants.UDP
 }
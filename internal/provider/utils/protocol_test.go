@@ -40,9 +40,18 @@ func Test_getProtocol(t *testing.T) {
 		"Wireguard": {
 			selection: settings.ServerSelection{
 				VPN: vpn.Wireguard,
-			},
+			}.WithDefaults(""),
 			protocol: constants.UDP,
 		},
+		"Wireguard TLS": {
+			selection: settings.ServerSelection{
+				VPN: vpn.Wireguard,
+				Wireguard: settings.WireguardSelection{
+					Protocol: stringPtr(constants.TLS),
+				},
+			}.WithDefaults(""),
+			protocol: constants.TCP,
+		},
 	}
 
 	for name, testCase := range testCases {
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
+}
+
+var ErrCachedTokenMalformed = errors.New("cached token is malformed")
+
+type cachedToken struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// ReadCachedToken reads the provider token cached at the file path given,
+// decrypting it with a key derived from the secret given, which is usually
+// the provider credentials. It returns an empty token without error if
+// the file does not exist, the token expired or the token was cached
+// using another secret.
+func ReadCachedToken(path, secret string, now time.Time) (token string, err error) {
+	plaintext, err := ReadEncryptedFile(path, secret)
+	if err != nil {
+		return "", err
+	} else if plaintext == nil {
+		return "", nil
+	}
+
+	var data cachedToken
+	err = json.Unmarshal(plaintext, &data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrCachedTokenMalformed, err)
+	}
+
+	if !now.Before(data.Expiry) {
+		return "", nil
+	}
+	return data.Token, nil
+}
+
+// WriteCachedToken encrypts the token given together with its expiry
+// time using a key derived from the secret given, and writes it to
+// the file path given using the file writer given, only readable by
+// its owner.
+func WriteCachedToken(fileWriter FileWriter, path, secret, token string,
+	expiry time.Time) (err error) {
+	plaintext, err := json.Marshal(cachedToken{
+		Token:  token,
+		Expiry: expiry,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding token: %w", err)
+	}
+
+	return WriteEncryptedFile(fileWriter, path, secret, plaintext)
+}
+
+// ReadEncryptedFile reads and decrypts the file at the path given
+// with a key derived from the secret given. It returns nil data
+// without error if the file does not exist or was encrypted using
+// another secret, for example if it was written in plaintext by
+// an older version of the program.
+func ReadEncryptedFile(path, secret string) (data []byte, err error) {
+	encrypted, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	aead, err := newTokenAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(encrypted) < nonceSize {
+		return nil, fmt.Errorf("%w: %d bytes only", ErrCachedTokenMalformed, len(encrypted))
+	}
+	nonce, ciphertext := encrypted[:nonceSize], encrypted[nonceSize:]
+	data, err = aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, nil //nolint:nilerr // encrypted with another secret
+	}
+	return data, nil
+}
+
+// WriteEncryptedFile encrypts the data given using a key derived from
+// the secret given, and writes it to the file path given using the file
+// writer given, only readable by its owner.
+func WriteEncryptedFile(fileWriter FileWriter, path, secret string,
+	data []byte) (err error) {
+	aead, err := newTokenAEAD(secret)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	encrypted := aead.Seal(nonce, nonce, data, nil)
+
+	const permissions = 0600
+	return fileWriter.WriteFile(path, encrypted, permissions)
+}
+
+// RemoveCachedToken removes the token cached at the file path given,
+// for example once the provider rejected it. It returns no error if
+// the file does not exist.
+func RemoveCachedToken(path string) (err error) {
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func newTokenAEAD(secret string) (aead cipher.AEAD, err error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating GCM: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CachedToken(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "token")
+	now := time.Unix(1000, 0)
+
+	token, err := ReadCachedToken(path, "secret", now)
+	require.NoError(t, err)
+	assert.Empty(t, token)
+
+	fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+	err = WriteCachedToken(fileWriter, path, "secret", "token", now.Add(time.Hour))
+	require.NoError(t, err)
+
+	token, err = ReadCachedToken(path, "secret", now)
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	token, err = ReadCachedToken(path, "other secret", now)
+	require.NoError(t, err)
+	assert.Empty(t, token)
+
+	token, err = ReadCachedToken(path, "secret", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, token)
+
+	err = RemoveCachedToken(path)
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	err = RemoveCachedToken(path)
+	assert.NoError(t, err)
+}
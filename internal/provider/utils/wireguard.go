@@ -4,6 +4,7 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/wireguard"
 )
@@ -18,10 +19,16 @@ func BuildWireguardSettings(connection models.Connection,
 	settings.MTU = userSettings.MTU
 	settings.IPv6 = &ipv6Supported
 
-	const rulePriority = 101 // 100 is to receive external connections
+	// 100 is to receive external connections and 101 is
+	// to steer traffic through the secondary tunnel.
+	const rulePriority = 102
 	settings.RulePriority = rulePriority
 
 	settings.Endpoint = netip.AddrPortFrom(connection.IP, connection.Port)
+	if connection.Protocol == constants.TCP {
+		settings.TLS = true
+		settings.TLSServerName = connection.Hostname
+	}
 
 	settings.Addresses = make([]netip.Prefix, 0, len(userSettings.Addresses))
 	for _, address := range userSettings.Addresses {
@@ -32,5 +39,12 @@ func BuildWireguardSettings(connection models.Connection,
 		settings.Addresses = append(settings.Addresses, addressCopy)
 	}
 
+	for _, allowedIP := range userSettings.AllowedIPs {
+		if !ipv6Supported && allowedIP.Addr().Is6() {
+			continue
+		}
+		settings.AllowedIPs = append(settings.AllowedIPs, allowedIP)
+	}
+
 	return settings
 }
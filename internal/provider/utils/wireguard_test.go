@@ -46,7 +46,7 @@ func Test_BuildWireguardSettings(t *testing.T) {
 				Addresses: []netip.Prefix{
 					netip.PrefixFrom(netip.AddrFrom4([4]byte{1, 1, 1, 1}), 32),
 				},
-				RulePriority: 101,
+				RulePriority: 102,
 				IPv6:         boolPtr(false),
 			},
 		},
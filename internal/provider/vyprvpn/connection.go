@@ -6,6 +6,9 @@ import (
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
+// GetConnection returns an OpenVPN connection to a VyprVPN server.
+// Wireguard is not supported since VyprVPN publishes no source for
+// its Wireguard server public keys the updater could store per server.
 func (p *Provider) GetConnection(selection settings.ServerSelection, ipv6Supported bool) (
 	connection models.Connection, err error) {
 	defaults := utils.NewConnectionDefaults(0, 443, 0) //nolint:gomnd
@@ -0,0 +1,155 @@
+package windscribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrHTTPStatusCodeNotOK = errors.New("HTTP status code not OK")
+	ErrAPIError            = errors.New("API error")
+	ErrSessionTokenEmpty   = errors.New("session token received is empty")
+	ErrPortNotValid        = errors.New("port received is not valid")
+)
+
+const apiURL = "https://api.windscribe.com"
+
+type apiError struct {
+	ErrorCode    int    `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+func (a apiError) err() error {
+	if a.ErrorCode == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d: %s", ErrAPIError, a.ErrorCode, a.ErrorMessage)
+}
+
+// fetchSessionToken logs in to the Windscribe API using
+// the account username and password, and returns the
+// session authentication hash.
+func fetchSessionToken(ctx context.Context, client *http.Client,
+	username, password string) (token string, err error) {
+	form := url.Values{}
+	form.Add("username", username)
+	form.Add("password", password)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		apiURL+"/Session", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var data struct {
+		apiError
+		Data struct {
+			SessionAuthHash string `json:"session_auth_hash"`
+		} `json:"data"`
+	}
+	err = doRequest(client, request, &data)
+	if err != nil {
+		return "", err
+	} else if err = data.err(); err != nil {
+		return "", err
+	} else if data.Data.SessionAuthHash == "" {
+		return "", ErrSessionTokenEmpty
+	}
+
+	return data.Data.SessionAuthHash, nil
+}
+
+// deleteEphemeralPort removes the ephemeral port of the account,
+// and succeeds if no ephemeral port exists.
+func deleteEphemeralPort(ctx context.Context, client *http.Client,
+	sessionToken string) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		apiURL+"/StaticIps/EphemeralPort", nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+sessionToken)
+
+	var data apiError
+	err = doRequest(client, request, &data)
+	if err != nil {
+		return err
+	}
+	return data.err()
+}
+
+// requestEphemeralPort requests an ephemeral port with matching
+// external and internal ports, and returns the port and its
+// expiration time. Ephemeral ports are valid for 7 days.
+func requestEphemeralPort(ctx context.Context, client *http.Client,
+	sessionToken string) (port uint16, expiration time.Time, err error) {
+	form := url.Values{}
+	form.Add("port", "0") // matching external and internal ports
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		apiURL+"/StaticIps/EphemeralPort", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, expiration, fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+sessionToken)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var data struct {
+		apiError
+		Data struct {
+			EphemeralPort struct {
+				External       uint16 `json:"ext"`
+				Internal       uint16 `json:"int"`
+				StartTimestamp int64  `json:"start_ts"`
+			} `json:"epf"`
+		} `json:"data"`
+	}
+	err = doRequest(client, request, &data)
+	if err != nil {
+		return 0, expiration, err
+	} else if err = data.err(); err != nil {
+		return 0, expiration, err
+	}
+
+	ephemeralPort := data.Data.EphemeralPort
+	if ephemeralPort.External == 0 || ephemeralPort.External != ephemeralPort.Internal {
+		return 0, expiration, fmt.Errorf("%w: external port %d and internal port %d",
+			ErrPortNotValid, ephemeralPort.External, ephemeralPort.Internal)
+	}
+
+	const validity = 7 * 24 * time.Hour
+	expiration = time.Unix(ephemeralPort.StartTimestamp, 0).Add(validity)
+	return ephemeralPort.External, expiration, nil
+}
+
+func doRequest(client *http.Client, request *http.Request,
+	data interface{}) (err error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(data)
+	if err != nil {
+		_ = response.Body.Close()
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("closing response body: %w", err)
+	}
+
+	return nil
+}
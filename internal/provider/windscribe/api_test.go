@@ -0,0 +1,84 @@
+package windscribe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_requestEphemeralPort(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		responseStatus int
+		responseBody   string
+		port           uint16
+		expiration     time.Time
+		errWrapped     error
+		errMessage     string
+	}{
+		"http response status not ok": {
+			responseStatus: http.StatusUnauthorized,
+			errWrapped:     ErrHTTPStatusCodeNotOK,
+			errMessage:     "HTTP status code not OK: Unauthorized",
+		},
+		"api error": {
+			responseStatus: http.StatusOK,
+			responseBody:   `{"errorCode":701,"errorMessage":"Session is invalid"}`,
+			errWrapped:     ErrAPIError,
+			errMessage:     "API error: 701: Session is invalid",
+		},
+		"ports not matching": {
+			responseStatus: http.StatusOK,
+			responseBody:   `{"data":{"epf":{"ext":1000,"int":2000,"start_ts":1}}}`,
+			errWrapped:     ErrPortNotValid,
+			errMessage:     "port received is not valid: external port 1000 and internal port 2000",
+		},
+		"success": {
+			responseStatus: http.StatusOK,
+			responseBody:   `{"data":{"epf":{"ext":54321,"int":54321,"start_ts":1700000000}}}`,
+			port:           54321,
+			expiration:     time.Unix(1700000000, 0).Add(7 * 24 * time.Hour),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPost, r.Method)
+					assert.Equal(t, "https://api.windscribe.com/StaticIps/EphemeralPort", r.URL.String())
+					assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+					return &http.Response{
+						StatusCode: testCase.responseStatus,
+						Status:     http.StatusText(testCase.responseStatus),
+						Body:       io.NopCloser(strings.NewReader(testCase.responseBody)),
+					}, nil
+				}),
+			}
+
+			port, expiration, err := requestEphemeralPort(context.Background(), client, "token")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.port, port)
+			assert.Equal(t, testCase.expiration, expiration)
+		})
+	}
+}
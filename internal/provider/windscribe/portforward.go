@@ -0,0 +1,140 @@
+package windscribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/qdm12/golibs/format"
+)
+
+// renewalMargin is the duration before the ephemeral port expiration
+// at which the port is renewed.
+const renewalMargin = 24 * time.Hour
+
+// PortForward obtains an ephemeral port forwarded from Windscribe,
+// re-using the port saved to file if it is not about to expire.
+func (p *Provider) PortForward(ctx context.Context,
+	objects utils.PortForwardObjects) (port uint16, err error) {
+	logger := objects.Logger
+
+	data, err := readPortForwardData(p.portForwardPath)
+	if err != nil {
+		return 0, fmt.Errorf("reading saved port forwarded data: %w", err)
+	}
+
+	dataFound := data.Port > 0
+	durationToRenewal := data.Expiration.Add(-renewalMargin).Sub(p.timeNow())
+	expiring := durationToRenewal <= 0
+
+	if dataFound {
+		logger.Info("Found saved forwarded port data for port " + strconv.Itoa(int(data.Port)))
+		if expiring {
+			logger.Warn("Forwarded port data expires on " +
+				data.Expiration.Format(time.RFC1123) + ", getting another one")
+		}
+	}
+
+	if !dataFound || expiring {
+		data, err = refreshPortForwardData(ctx, objects, p.portForwardPath)
+		if err != nil {
+			return 0, fmt.Errorf("refreshing port forward data: %w", err)
+		}
+	}
+
+	durationToExpiration := data.Expiration.Sub(p.timeNow())
+	logger.Info("Port forwarded data expires in " + format.FriendlyDuration(durationToExpiration))
+
+	return data.Port, nil
+}
+
+var ErrPortForwardedExpiring = errors.New("port forwarded is expiring")
+
+// KeepPortForward waits until the ephemeral port is about to expire,
+// and returns an error so a new port is obtained with PortForward.
+func (p *Provider) KeepPortForward(ctx context.Context,
+	_ utils.PortForwardObjects) (err error) {
+	data, err := readPortForwardData(p.portForwardPath)
+	if err != nil {
+		return fmt.Errorf("reading saved port forwarded data: %w", err)
+	}
+
+	durationToRenewal := data.Expiration.Add(-renewalMargin).Sub(p.timeNow())
+	renewalTimer := time.NewTimer(durationToRenewal)
+
+	select {
+	case <-ctx.Done():
+		if !renewalTimer.Stop() {
+			<-renewalTimer.C
+		}
+		return ctx.Err()
+	case <-renewalTimer.C:
+		return fmt.Errorf("%w: on %s", ErrPortForwardedExpiring,
+			data.Expiration.Format(time.RFC1123))
+	}
+}
+
+func refreshPortForwardData(ctx context.Context, objects utils.PortForwardObjects,
+	portForwardPath string) (data portForwardData, err error) {
+	sessionToken, err := fetchSessionToken(ctx, objects.Client,
+		objects.Username, objects.Password)
+	if err != nil {
+		return data, fmt.Errorf("fetching session token: %w", err)
+	}
+
+	// Only one ephemeral port can exist per account,
+	// so remove any previous one before requesting another one.
+	err = deleteEphemeralPort(ctx, objects.Client, sessionToken)
+	if err != nil {
+		return data, fmt.Errorf("deleting previous ephemeral port: %w", err)
+	}
+
+	data.Port, data.Expiration, err = requestEphemeralPort(ctx, objects.Client, sessionToken)
+	if err != nil {
+		return data, fmt.Errorf("requesting ephemeral port: %w", err)
+	}
+
+	err = writePortForwardData(objects.FileWriter, portForwardPath, data)
+	if err != nil {
+		return data, fmt.Errorf("persisting port forwarding data: %w", err)
+	}
+
+	return data, nil
+}
+
+type portForwardData struct {
+	Port       uint16    `json:"port"`
+	Expiration time.Time `json:"expires_at"`
+}
+
+func readPortForwardData(portForwardPath string) (data portForwardData, err error) {
+	file, err := os.Open(portForwardPath)
+	if os.IsNotExist(err) {
+		return data, nil
+	} else if err != nil {
+		return data, err
+	}
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		_ = file.Close()
+		return data, err
+	}
+
+	return data, file.Close()
+}
+
+func writePortForwardData(fileWriter utils.FileWriter, portForwardPath string,
+	data portForwardData) (err error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding data: %w", err)
+	}
+	const permissions = 0644
+	return fileWriter.WriteFile(portForwardPath, b, permissions)
+}
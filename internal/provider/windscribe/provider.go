@@ -3,26 +3,32 @@ package windscribe
 import (
 	"math/rand"
 	"net/http"
+	"path/filepath"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/provider/common"
-	"github.com/qdm12/gluetun/internal/provider/utils"
 	"github.com/qdm12/gluetun/internal/provider/windscribe/updater"
 )
 
 type Provider struct {
 	storage    common.Storage
 	randSource rand.Source
-	utils.NoPortForwarder
+	timeNow    func() time.Time
 	common.Fetcher
+	// Port forwarding
+	portForwardPath string
 }
 
 func New(storage common.Storage, randSource rand.Source,
-	client *http.Client, updaterWarner common.Warner) *Provider {
+	timeNow func() time.Time, client *http.Client,
+	updaterWarner common.Warner, dataDirectory string) *Provider {
+	jsonPortForwardPath := filepath.Join(dataDirectory, "windscribeportforward.json")
 	return &Provider{
 		storage:         storage,
 		randSource:      randSource,
-		NoPortForwarder: utils.NewNoPortForwarding(providers.Windscribe),
+		timeNow:         timeNow,
+		portForwardPath: jsonPortForwardPath,
 		Fetcher:         updater.New(client, updaterWarner),
 	}
 }
@@ -0,0 +1,73 @@
+// Package echoip fetches the public IP address information from
+// a custom echo API, such as a self-hosted ipify or echoip instance.
+package echoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
+)
+
+type Fetch struct {
+	client   *http.Client
+	settings settings.PublicIPAPI
+}
+
+func New(client *http.Client, settings settings.PublicIPAPI) *Fetch {
+	return &Fetch{
+		client:   client,
+		settings: settings,
+	}
+}
+
+var (
+	ErrIPLookupNotSupported = errors.New("looking up another IP address is not supported")
+	ErrBadHTTPStatus        = errors.New("bad HTTP status received")
+)
+
+// FetchInfo obtains information on the public IP address of the machine
+// using the echo API URL. The ip argument must be the zero value since
+// echo APIs only report the IP address of the client.
+func (f *Fetch) FetchInfo(ctx context.Context, ip netip.Addr) (
+	result ipinfo.Response, err error) {
+	if ip.IsValid() {
+		return result, fmt.Errorf("%w: %s", ErrIPLookupNotSupported, ip)
+	}
+
+	url := *f.settings.URL
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return result, err
+	}
+	request.Header.Set("Accept", "application/json, text/plain")
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return result, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return result, fmt.Errorf("%w from %s: %d %s",
+			ipinfo.ErrTooManyRequests, url, response.StatusCode, response.Status)
+	default:
+		return result, fmt.Errorf("%w from %s: %d %s",
+			ErrBadHTTPStatus, url, response.StatusCode, response.Status)
+	}
+
+	const maxBodySize = 1 << 16
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		return result, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return parseResponse(body, f.settings)
+}
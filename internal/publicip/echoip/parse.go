@@ -0,0 +1,84 @@
+package echoip
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
+)
+
+var (
+	ErrResponseNotValid = errors.New("response is not a plain text IP address or a JSON object")
+	ErrIPKeyMissing     = errors.New("IP address key is missing")
+	ErrIPNotValid       = errors.New("IP address is not valid")
+)
+
+// parseResponse parses the response body, which is either an IP
+// address in plain text, or a JSON object with the IP address
+// information at the keys set in the settings.
+func parseResponse(body []byte, settings settings.PublicIPAPI) (
+	result ipinfo.Response, err error) {
+	body = bytes.TrimSpace(body)
+	ip, err := netip.ParseAddr(string(body))
+	if err == nil {
+		result.IP = ip
+		return result, nil
+	}
+
+	var object map[string]any
+	err = json.Unmarshal(body, &object)
+	if err != nil {
+		return result, fmt.Errorf("%w: %s", ErrResponseNotValid, err)
+	}
+
+	ipString := lookupString(object, settings.IPKey)
+	if ipString == "" {
+		return result, fmt.Errorf("%w: %s", ErrIPKeyMissing, settings.IPKey)
+	}
+	result.IP, err = netip.ParseAddr(ipString)
+	if err != nil {
+		return result, fmt.Errorf("%w: %s", ErrIPNotValid, err)
+	}
+
+	result.Country = lookupString(object, settings.CountryKey)
+	country, ok := constants.CountryCodes()[strings.ToLower(result.Country)]
+	if ok {
+		result.Country = country
+	}
+	result.Region = lookupString(object, settings.RegionKey)
+	result.City = lookupString(object, settings.CityKey)
+
+	return result, nil
+}
+
+// lookupString returns the string value at the key given, where nested
+// keys are separated by dots. It returns the empty string if the key
+// is not found or if its value is not a string.
+func lookupString(object map[string]any, key string) (value string) {
+	parents, last, nested := cutLast(key, ".")
+	if nested {
+		for _, parent := range strings.Split(parents, ".") {
+			child, ok := object[parent].(map[string]any)
+			if !ok {
+				return ""
+			}
+			object = child
+		}
+	}
+	value, _ = object[last].(string)
+	return value
+}
+
+func cutLast(s, separator string) (before, after string, found bool) {
+	i := strings.LastIndex(s, separator)
+	if i == -1 {
+		return "", s, false
+	}
+	return s[:i], s[i+len(separator):], true
+}
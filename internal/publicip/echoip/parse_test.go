@@ -0,0 +1,87 @@
+package echoip
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseResponse(t *testing.T) {
+	t.Parallel()
+
+	defaultKeys := settings.PublicIPAPI{
+		IPKey:      "ip",
+		CountryKey: "country",
+		RegionKey:  "region",
+		CityKey:    "city",
+	}
+
+	testCases := map[string]struct {
+		body       string
+		settings   settings.PublicIPAPI
+		result     ipinfo.Response
+		errWrapped error
+		errMessage string
+	}{
+		"plain text": {
+			body:     "1.2.3.4\n",
+			settings: defaultKeys,
+			result:   ipinfo.Response{IP: netip.MustParseAddr("1.2.3.4")},
+		},
+		"json with default keys": {
+			body:     `{"ip":"1.2.3.4","country":"FR","region":"Ile-de-France","city":"Paris"}`,
+			settings: defaultKeys,
+			result: ipinfo.Response{
+				IP:      netip.MustParseAddr("1.2.3.4"),
+				Country: "France",
+				Region:  "Ile-de-France",
+				City:    "Paris",
+			},
+		},
+		"json with nested keys": {
+			body: `{"address":"::1","location":{"country":"Germany","city":"Berlin"}}`,
+			settings: settings.PublicIPAPI{
+				IPKey:      "address",
+				CountryKey: "location.country",
+				RegionKey:  "location.region",
+				CityKey:    "location.city",
+			},
+			result: ipinfo.Response{
+				IP:      netip.MustParseAddr("::1"),
+				Country: "Germany",
+				City:    "Berlin",
+			},
+		},
+		"not valid": {
+			body:       "<html></html>",
+			settings:   defaultKeys,
+			errWrapped: ErrResponseNotValid,
+			errMessage: "response is not a plain text IP address or a JSON object: " +
+				"invalid character '<' looking for beginning of value",
+		},
+		"ip key missing": {
+			body:       `{"country":"FR"}`,
+			settings:   defaultKeys,
+			errWrapped: ErrIPKeyMissing,
+			errMessage: "IP address key is missing: ip",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := parseResponse([]byte(testCase.body), testCase.settings)
+
+			assert.Equal(t, testCase.result, result)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package publicip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
+)
+
+// fetch fetches the public IP address information, waiting a random
+// delay up to the jitter set before each attempt, and retrying on
+// failure up to the number of retries set, since the routes may not
+// be settled yet right after a VPN reconnection.
+func (l *Loop) fetch(ctx context.Context) (result ipinfo.Response, err error) {
+	settings := l.state.GetSettings()
+	maxAttempts := int(*settings.Retries) + 1
+	for attempt := 1; ; attempt++ {
+		err = sleep(ctx, randomDuration(*settings.Jitter))
+		if err != nil {
+			return result, err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, *settings.Timeout)
+		result, err = l.fetcher.FetchInfo(attemptCtx, netip.Addr{})
+		cancel()
+		switch {
+		case err == nil:
+			return result, nil
+		case ctx.Err() != nil:
+			return result, ctx.Err()
+		case errors.Is(err, ipinfo.ErrTooManyRequests):
+			return result, err
+		case attempt == maxAttempts:
+			if maxAttempts > 1 {
+				err = fmt.Errorf("after %d attempts: %w", maxAttempts, err)
+			}
+			return result, err
+		}
+
+		l.logger.Debug("attempt " + strconv.Itoa(attempt) + " of " +
+			strconv.Itoa(maxAttempts) + " failed: " + err.Error())
+		retryDelay := time.Duration(attempt) * time.Second
+		err = sleep(ctx, retryDelay)
+		if err != nil {
+			return result, err
+		}
+	}
+}
+
+// randomDuration returns a random duration between 0 included
+// and maxDuration excluded, or 0 if maxDuration is 0.
+func randomDuration(maxDuration time.Duration) time.Duration {
+	if maxDuration <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(maxDuration))) //nolint:gosec
+}
+
+func sleep(ctx context.Context, duration time.Duration) (err error) {
+	if duration == 0 {
+		return nil
+	}
+	timer := time.NewTimer(duration)
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		if !timer.Stop() {
+			<-timer.C
+		}
+		return ctx.Err()
+	}
+}
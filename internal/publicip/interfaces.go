@@ -2,7 +2,9 @@ package publicip
 
 import (
 	"context"
+	"io/fs"
 	"net/netip"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/publicip/ipinfo"
 )
@@ -11,3 +13,11 @@ type Fetcher interface {
 	FetchInfo(ctx context.Context, ip netip.Addr) (
 		result ipinfo.Response, err error)
 }
+
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
+}
+
+type TimezoneSetter interface {
+	SetLocation(location *time.Location)
+}
@@ -1,6 +1,7 @@
 package publicip
 
 type Logger interface {
+	Debug(s string)
 	Info(s string)
 	Warn(s string)
 	Error(s string)
@@ -14,11 +14,12 @@ type Loop struct {
 	statusManager *loopstate.State
 	state         *state.State
 	// Objects
-	fetcher Fetcher
-	logger  Logger
-	// Fixed settings
-	puid int
-	pgid int
+	fetcher    Fetcher
+	logger     Logger
+	fileWriter FileWriter
+	timezone   TimezoneSetter
+	// Internal state
+	setTimezone bool
 	// Internal channels and locks
 	start        chan struct{}
 	running      chan models.LoopStatus
@@ -34,7 +35,8 @@ type Loop struct {
 const defaultBackoffTime = 5 * time.Second
 
 func NewLoop(fetcher Fetcher, logger Logger,
-	settings settings.PublicIP, puid, pgid int) *Loop {
+	settings settings.PublicIP, fileWriter FileWriter,
+	timezone TimezoneSetter, setTimezone bool) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
@@ -50,8 +52,9 @@ func NewLoop(fetcher Fetcher, logger Logger,
 		// Objects
 		fetcher:      fetcher,
 		logger:       logger,
-		puid:         puid,
-		pgid:         pgid,
+		fileWriter:   fileWriter,
+		timezone:     timezone,
+		setTimezone:  setTimezone,
 		start:        start,
 		running:      running,
 		stop:         stop,
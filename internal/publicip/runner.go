@@ -3,7 +3,6 @@ package publicip
 import (
 	"context"
 	"errors"
-	"net/netip"
 	"os"
 
 	"github.com/qdm12/gluetun/internal/constants"
@@ -27,7 +26,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		resultCh := make(chan models.PublicIP)
 		errorCh := make(chan error)
 		go func() {
-			result, err := l.fetcher.FetchInfo(getCtx, netip.Addr{})
+			result, err := l.fetch(getCtx)
 			if err != nil {
 				if getCtx.Err() == nil {
 					errorCh <- err
@@ -76,8 +75,19 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 
 				l.state.SetData(result)
 
+				if l.setTimezone && result.Timezone != "" {
+					err := l.setTimezoneFromName(result.Timezone)
+					if err != nil {
+						l.logger.Warn("setting timezone from public IP data: " + err.Error())
+					} else {
+						l.logger.Info("timezone set to " + result.Timezone + " from public IP data")
+					}
+					l.setTimezone = false
+				}
+
 				filepath := *l.state.GetSettings().IPFilepath
-				err := persistPublicIP(filepath, result.IP.String(), l.puid, l.pgid)
+				perms := *l.state.GetSettings().IPFilePermissions
+				err := l.fileWriter.WriteFile(filepath, []byte(result.IP.String()), perms)
 				if err != nil {
 					l.logger.Error(err.Error())
 				}
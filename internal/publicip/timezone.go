@@ -0,0 +1,18 @@
+package publicip
+
+import (
+	"fmt"
+	"time"
+)
+
+// setTimezoneFromName sets the timezone of the program, used
+// for log timestamps and schedule windows, to the IANA timezone
+// name given. Timezone data is embedded in the program with time/tzdata.
+func (l *Loop) setTimezoneFromName(name string) (err error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("loading timezone: %w", err)
+	}
+	l.timezone.SetLocation(location)
+	return nil
+}
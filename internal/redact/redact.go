@@ -0,0 +1,97 @@
+// Package redact scrubs known secrets from data written,
+// for example to avoid leaking credentials in logs.
+package redact
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const placeholder = "[redacted]"
+
+// Redactor replaces known secrets with a placeholder.
+// It is safe for concurrent use.
+type Redactor struct {
+	secrets  map[string]struct{}
+	replacer *strings.Replacer
+	mutex    sync.RWMutex
+}
+
+func New() *Redactor {
+	return &Redactor{
+		secrets:  make(map[string]struct{}),
+		replacer: strings.NewReplacer(),
+	}
+}
+
+// AddSecrets adds secrets to be redacted.
+// Secrets shorter than 4 characters are ignored, since they
+// are either placeholder values (such as the Mullvad "m" password)
+// or would redact most of the log lines.
+func (r *Redactor) AddSecrets(secrets ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	const minSecretLength = 4
+	for _, secret := range secrets {
+		if len(secret) < minSecretLength {
+			continue
+		}
+		r.secrets[secret] = struct{}{}
+	}
+
+	// Sort secrets by decreasing length so a secret containing
+	// another secret is fully redacted.
+	sortedSecrets := make([]string, 0, len(r.secrets))
+	for secret := range r.secrets {
+		sortedSecrets = append(sortedSecrets, secret)
+	}
+	sort.Slice(sortedSecrets, func(i, j int) bool {
+		return len(sortedSecrets[i]) > len(sortedSecrets[j])
+	})
+
+	oldNew := make([]string, 0, 2*len(sortedSecrets)) //nolint:gomnd
+	for _, secret := range sortedSecrets {
+		oldNew = append(oldNew, secret, placeholder)
+	}
+	r.replacer = strings.NewReplacer(oldNew...)
+}
+
+// Redact returns the string given with all known
+// secrets replaced by a placeholder.
+func (r *Redactor) Redact(s string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.replacer.Replace(s)
+}
+
+// Wrap returns an io.Writer redacting secrets from data
+// before writing it to the writer given.
+func (r *Redactor) Wrap(writer io.Writer) *Writer {
+	return &Writer{
+		redactor: r,
+		writer:   writer,
+	}
+}
+
+// Writer is an io.Writer redacting known secrets
+// before writing to its underlying writer.
+type Writer struct {
+	redactor *Redactor
+	writer   io.Writer
+}
+
+// Write writes the data given to the underlying writer,
+// with secrets redacted. It returns the length of the data
+// given and no error if the write succeeds, to respect
+// the io.Writer interface.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	redacted := w.redactor.Redact(string(p))
+	_, err = io.WriteString(w.writer, redacted)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,28 @@
+package redact
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Redactor(t *testing.T) {
+	t.Parallel()
+
+	redactor := New()
+
+	const line = "password is secret and key is secretkey\n"
+	assert.Equal(t, line, redactor.Redact(line))
+
+	redactor.AddSecrets("", "is", "secret", "secretkey")
+
+	buffer := bytes.NewBuffer(nil)
+	writer := redactor.Wrap(buffer)
+	n, err := writer.Write([]byte(line))
+	require.NoError(t, err)
+
+	assert.Equal(t, len(line), n)
+	assert.Equal(t, "password is [redacted] and key is [redacted]\n", buffer.String())
+}
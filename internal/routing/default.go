@@ -44,6 +44,9 @@ func (r *Routing) DefaultRoutes() (defaultRoutes []DefaultRoute, err error) {
 			return nil, fmt.Errorf("obtaining link by index: for default route at index %d: %w", linkIndex, err)
 		}
 		attributes := link.Attrs()
+		if !r.interfaceAllowed(attributes.Name) {
+			continue
+		}
 		defaultRoute.NetInterface = attributes.Name
 		family := netlink.FAMILY_V6
 		if route.Gw.To4() != nil {
@@ -0,0 +1,24 @@
+package routing
+
+// SetInterfaces restricts the network interfaces considered
+// for default routes and local networks to the ones given.
+// An empty slice means all network interfaces are considered,
+// which is the default.
+func (r *Routing) SetInterfaces(interfaces []string) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+	r.interfaces = make(map[string]struct{}, len(interfaces))
+	for _, intf := range interfaces {
+		r.interfaces[intf] = struct{}{}
+	}
+}
+
+func (r *Routing) interfaceAllowed(intf string) (allowed bool) {
+	r.stateMutex.RLock()
+	defer r.stateMutex.RUnlock()
+	if len(r.interfaces) == 0 {
+		return true
+	}
+	_, allowed = r.interfaces[intf]
+	return allowed
+}
@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Routing_interfaceAllowed(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		interfaces []string
+		intf       string
+		allowed    bool
+	}{
+		"all interfaces": {
+			intf:    "docker0",
+			allowed: true,
+		},
+		"interface set": {
+			interfaces: []string{"eth0", "eth1"},
+			intf:       "eth1",
+			allowed:    true,
+		},
+		"interface not set": {
+			interfaces: []string{"eth0"},
+			intf:       "docker0",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			routing := &Routing{}
+			routing.SetInterfaces(testCase.interfaces)
+
+			allowed := routing.interfaceAllowed(testCase.intf)
+
+			assert.Equal(t, testCase.allowed, allowed)
+		})
+	}
+}
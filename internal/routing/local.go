@@ -20,7 +20,20 @@ type LocalNetwork struct {
 	IP            netip.Addr
 }
 
+// LocalNetworks returns the local networks of the ethernet links,
+// logging each local link and network found.
 func (r *Routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
+	return r.localNetworks(r.logger.Info)
+}
+
+// ListLocalNetworks returns the local networks of the
+// ethernet links, without logging them.
+func (r *Routing) ListLocalNetworks() (localNetworks []LocalNetwork, err error) {
+	return r.localNetworks(func(string) {})
+}
+
+func (r *Routing) localNetworks(logInfo func(s string)) (
+	localNetworks []LocalNetwork, err error) {
 	links, err := r.netLinker.LinkList()
 	if err != nil {
 		return localNetworks, fmt.Errorf("listing links: %w", err)
@@ -29,12 +42,13 @@ func (r *Routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
 	localLinks := make(map[int]struct{})
 
 	for _, link := range links {
-		if link.Attrs().EncapType != "ether" {
+		if link.Attrs().EncapType != "ether" ||
+			!r.interfaceAllowed(link.Attrs().Name) {
 			continue
 		}
 
 		localLinks[link.Attrs().Index] = struct{}{}
-		r.logger.Info("local ethernet link found: " + link.Attrs().Name)
+		logInfo("local ethernet link found: " + link.Attrs().Name)
 	}
 
 	if len(localLinks) == 0 {
@@ -56,7 +70,7 @@ func (r *Routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
 		var localNet LocalNetwork
 
 		localNet.IPNet = netIPNetToNetipPrefix(*route.Dst)
-		r.logger.Info("local ipnet found: " + localNet.IPNet.String())
+		logInfo("local ipnet found: " + localNet.IPNet.String())
 
 		link, err := r.netLinker.LinkByIndex(route.LinkIndex)
 		if err != nil {
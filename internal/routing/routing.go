@@ -49,7 +49,11 @@ type Routing struct {
 	netLinker       NetLinker
 	logger          Logger
 	outboundSubnets []netip.Prefix
-	stateMutex      sync.RWMutex
+	// interfaces is the set of network interfaces considered
+	// for default routes and local networks, and all network
+	// interfaces are considered if it is empty.
+	interfaces map[string]struct{}
+	stateMutex sync.RWMutex
 }
 
 // New creates a new routing instance.
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type auditEntry struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Route    string    `json:"route"`
+	SourceIP string    `json:"source_ip"`
+	Identity string    `json:"identity,omitempty"`
+	Payload  string    `json:"payload,omitempty"`
+	Status   int       `json:"status"`
+}
+
+// auditLog records state changing requests in memory and,
+// if filepath is set, appends them as JSON lines to a file.
+type auditLog struct {
+	filepath   string
+	fileWriter FileWriter
+	logger     warner
+	entries    []auditEntry
+	mutex      sync.RWMutex
+	timeNow    func() time.Time
+}
+
+func newAuditLog(filepath string, fileWriter FileWriter, logger warner) *auditLog {
+	return &auditLog{
+		filepath:   filepath,
+		fileWriter: fileWriter,
+		logger:     logger,
+		timeNow:    time.Now,
+	}
+}
+
+func (a *auditLog) record(entry auditEntry) {
+	a.mutex.Lock()
+	const maxEntries = 1000
+	if len(a.entries) == maxEntries {
+		copy(a.entries, a.entries[1:])
+		a.entries = a.entries[:len(a.entries)-1]
+	}
+	a.entries = append(a.entries, entry)
+	a.mutex.Unlock()
+
+	if a.filepath == "" {
+		return
+	}
+
+	err := a.appendToFile(entry)
+	if err != nil {
+		a.logger.Warn("writing audit log entry: " + err.Error())
+	}
+}
+
+func (a *auditLog) appendToFile(entry auditEntry) (err error) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	const perms = 0600
+	return a.fileWriter.AppendFile(a.filepath, line, perms)
+}
+
+// lastEntries returns the last entries recorded, up to limit
+// entries if limit is strictly positive.
+func (a *auditLog) lastEntries(limit int) (entries []auditEntry) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	start := 0
+	if limit > 0 && limit < len(a.entries) {
+		start = len(a.entries) - limit
+	}
+	entries = make([]auditEntry, len(a.entries)-start)
+	copy(entries, a.entries[start:])
+	return entries
+}
+
+func withAuditMiddleware(childHandler http.Handler, auditLog *auditLog) *auditMiddleware {
+	return &auditMiddleware{
+		childHandler: childHandler,
+		auditLog:     auditLog,
+	}
+}
+
+type auditMiddleware struct {
+	childHandler http.Handler
+	auditLog     *auditLog
+}
+
+func (m *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	entry := auditEntry{
+		Time:     m.auditLog.timeNow(),
+		Method:   r.Method,
+		Route:    r.RequestURI, // child handlers modify the request URI
+		SourceIP: r.RemoteAddr,
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		entry.SourceIP = host
+	}
+	entry.Identity = identityFromRequest(r)
+
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			http.Error(w, "reading request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		entry.Payload = summarizePayload(body)
+	}
+
+	statefulWriter := &statefulResponseWriter{httpWriter: w}
+	m.childHandler.ServeHTTP(statefulWriter, r)
+	entry.Status = statefulWriter.statusCode
+	if entry.Status == 0 {
+		entry.Status = http.StatusOK
+	}
+
+	m.auditLog.record(entry)
+}
+
+// summarizePayload returns a short summary of a request body.
+// For a JSON object, it lists its top level fields with scalar
+// values, with values of fields looking sensitive redacted.
+// For any other body, only its size is returned.
+func summarizePayload(body []byte) (summary string) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var object map[string]json.RawMessage
+	err := json.Unmarshal(body, &object)
+	if err != nil {
+		return strconv.Itoa(len(body)) + " bytes"
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		value := string(object[key])
+		switch {
+		case isSensitiveField(key):
+			value = "[redacted]"
+		case strings.HasPrefix(value, "{"):
+			value = "{...}"
+		case strings.HasPrefix(value, "["):
+			value = "[...]"
+		}
+		fields[i] = key + "=" + value
+	}
+
+	summary = strings.Join(fields, ", ")
+	const maxSummaryLength = 256
+	if len(summary) > maxSummaryLength {
+		summary = summary[:maxSummaryLength] + "..."
+	}
+	return summary
+}
+
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range []string{"password", "key", "token", "secret"} {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func newAuditHandler(auditLog *auditLog, warner warner) http.Handler {
+	return &auditHandler{
+		auditLog: auditLog,
+		warner:   warner,
+	}
+}
+
+type auditHandler struct {
+	auditLog *auditLog
+	warner   warner
+}
+
+func (h *auditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		return
+	}
+
+	var limit int
+	if limitString := r.URL.Query().Get("limit"); limitString != "" {
+		var err error
+		limit, err = strconv.Atoi(limitString)
+		if err != nil || limit < 0 {
+			http.Error(w, "limit query parameter is not valid: "+limitString, http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries := h.auditLog.lastEntries(limit)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(entries); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
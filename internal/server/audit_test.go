@@ -0,0 +1,36 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_summarizePayload(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body    string
+		summary string
+	}{
+		"empty": {},
+		"not JSON object": {
+			body:    "[1,2]",
+			summary: "5 bytes",
+		},
+		"JSON object": {
+			body: `{"status":"running","openvpn":{"password":"x"},` +
+				`"wireguard_private_key":"y","ports":[1]}`,
+			summary: `openvpn={...}, ports=[...], status="running", wireguard_private_key=[redacted]`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			summary := summarizePayload([]byte(testCase.body))
+			assert.Equal(t, testCase.summary, summary)
+		})
+	}
+}
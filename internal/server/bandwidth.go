@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func newBandwidthHandler(getter BandwidthGetter, w warner) http.Handler {
+	return &bandwidthHandler{
+		getter: getter,
+		warner: w,
+	}
+}
+
+type bandwidthHandler struct {
+	getter BandwidthGetter
+	warner warner
+}
+
+func (h *bandwidthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/bandwidth")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getBandwidth(w)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	default:
+		http.Error(w, "route "+r.RequestURI+" not supported", http.StatusBadRequest)
+	}
+}
+
+func (h *bandwidthHandler) getBandwidth(w http.ResponseWriter) {
+	bandwidth := h.getter.GetBandwidth()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(bandwidth); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
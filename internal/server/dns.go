@@ -4,26 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 func newDNSHandler(ctx context.Context, loop DNSLoop,
-	warner warner) http.Handler {
+	leakTester DNSLeakTester, warner warner) http.Handler {
 	return &dnsHandler{
-		ctx:    ctx,
-		loop:   loop,
-		warner: warner,
+		ctx:        ctx,
+		loop:       loop,
+		leakTester: leakTester,
+		warner:     warner,
 	}
 }
 
 type dnsHandler struct {
-	ctx    context.Context //nolint:containedctx
-	loop   DNSLoop
-	warner warner
+	ctx        context.Context //nolint:containedctx
+	loop       DNSLoop
+	leakTester DNSLeakTester
+	warner     warner
 }
 
 func (h *dnsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/dns")
+	r.RequestURI, _, _ = strings.Cut(r.RequestURI, "?")
 	switch r.RequestURI {
 	case "/status": //nolint:goconst
 		switch r.Method {
@@ -34,6 +40,39 @@ func (h *dnsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
 		}
+	case "/blocklist":
+		switch r.Method {
+		case http.MethodGet:
+			h.getBlocklist(w)
+		case http.MethodPut:
+			h.setBlocklist(w, r)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/upstreams":
+		switch r.Method {
+		case http.MethodGet:
+			h.getUpstreams(w)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/top":
+		switch r.Method {
+		case http.MethodGet:
+			h.getTopDomains(w, r)
+		case http.MethodDelete:
+			h.loop.ResetTopDomains()
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/leaktest":
+		switch r.Method {
+		case http.MethodPost:
+			h.runLeakTest(w, r)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
 	default:
 		http.Error(w, "route "+r.RequestURI+" not supported", http.StatusBadRequest)
 	}
@@ -74,3 +113,79 @@ func (h *dnsHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *dnsHandler) getBlocklist(w http.ResponseWriter) {
+	blocklist := h.loop.GetBlocklist()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(blocklist); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) setBlocklist(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var blocklist models.DNSBlocklist
+	if err := decoder.Decode(&blocklist); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	outcome, err := h.loop.SetBlocklist(h.ctx, blocklist)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) getUpstreams(w http.ResponseWriter) {
+	upstreams := h.loop.GetUpstreams()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(upstreams); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) getTopDomains(w http.ResponseWriter, r *http.Request) {
+	const defaultLimit = 10
+	limit := defaultLimit
+	if limitString := r.URL.Query().Get("limit"); limitString != "" {
+		var err error
+		limit, err = strconv.Atoi(limitString)
+		if err != nil || limit < 0 {
+			http.Error(w, "limit query parameter is not valid: "+limitString, http.StatusBadRequest)
+			return
+		}
+	}
+
+	top := h.loop.GetTopDomains(limit)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(top); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) runLeakTest(w http.ResponseWriter, r *http.Request) {
+	result, err := h.leakTester.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(result); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -4,32 +4,47 @@ import (
 	"context"
 	"net/http"
 	"strings"
-
-	"github.com/qdm12/gluetun/internal/models"
 )
 
 func newHandler(ctx context.Context, logger infoWarner, logging bool,
-	buildInfo models.BuildInformation,
+	auditLogPath string, fileWriter FileWriter,
+	version VersionGetter,
 	vpnLooper VPNLooper,
 	pfGetter PortForwardedGetter,
 	unboundLooper DNSLoop,
 	updaterLooper UpdaterLooper,
 	publicIPLooper PublicIPLoop,
+	bandwidthGetter BandwidthGetter,
+	speedTester SpeedTester,
+	dnsLeakTester DNSLeakTester,
+	wireguardServer WireguardServer,
 	storage Storage,
+	lifecycle Lifecycle,
+	readOnly, admin RouteGroup,
 	ipv6Supported bool,
 ) http.Handler {
 	handler := &handler{}
 
 	vpn := newVPNHandler(ctx, vpnLooper, storage, ipv6Supported, logger)
 	openvpn := newOpenvpnHandler(ctx, vpnLooper, pfGetter, logger)
-	dns := newDNSHandler(ctx, unboundLooper, logger)
+	dns := newDNSHandler(ctx, unboundLooper, dnsLeakTester, logger)
 	updater := newUpdaterHandler(ctx, updaterLooper, logger)
 	publicip := newPublicIPHandler(publicIPLooper, logger)
+	bandwidth := newBandwidthHandler(bandwidthGetter, logger)
+	speedTest := newSpeedTestHandler(speedTester, logger)
+	wgServer := newWireguardServerHandler(wireguardServer, logger)
+	system := newSystemHandler(lifecycle, admin.APIKey, logger)
+	auditLog := newAuditLog(auditLogPath, fileWriter, logger)
+	audit := newAuditHandler(auditLog, logger)
+	servers := newServersHandler(storage, logger)
 
 	handler.v0 = newHandlerV0(ctx, logger, vpnLooper, unboundLooper, updaterLooper)
-	handler.v1 = newHandlerV1(logger, buildInfo, vpn, openvpn, dns, updater, publicip)
+	handler.v1 = newHandlerV1(logger, version, vpn, openvpn, dns, updater, publicip,
+		bandwidth, speedTest, wgServer, system, audit, servers)
 
-	handlerWithLog := withLogMiddleware(handler, logger, logging)
+	handlerWithAudit := withAuditMiddleware(handler, auditLog)
+	handlerWithGroups := withRouteGroupsMiddleware(handlerWithAudit, readOnly, admin)
+	handlerWithLog := withLogMiddleware(handlerWithGroups, logger, logging)
 	handler.setLogEnabled = handlerWithLog.setEnabled
 
 	return handlerWithLog
@@ -5,31 +5,42 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-
-	"github.com/qdm12/gluetun/internal/models"
 )
 
-func newHandlerV1(w warner, buildInfo models.BuildInformation,
-	vpn, openvpn, dns, updater, publicip http.Handler) http.Handler {
+func newHandlerV1(w warner, version VersionGetter,
+	vpn, openvpn, dns, updater, publicip, bandwidth, speedTest, wgServer,
+	system, audit, servers http.Handler) http.Handler {
 	return &handlerV1{
 		warner:    w,
-		buildInfo: buildInfo,
+		version:   version,
 		vpn:       vpn,
 		openvpn:   openvpn,
 		dns:       dns,
 		updater:   updater,
 		publicip:  publicip,
+		bandwidth: bandwidth,
+		speedTest: speedTest,
+		wgServer:  wgServer,
+		system:    system,
+		audit:     audit,
+		servers:   servers,
 	}
 }
 
 type handlerV1 struct {
 	warner    warner
-	buildInfo models.BuildInformation
+	version   VersionGetter
 	vpn       http.Handler
 	openvpn   http.Handler
 	dns       http.Handler
 	updater   http.Handler
 	publicip  http.Handler
+	bandwidth http.Handler
+	speedTest http.Handler
+	wgServer  http.Handler
+	system    http.Handler
+	audit     http.Handler
+	servers   http.Handler
 }
 
 func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +57,18 @@ func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.updater.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/publicip"):
 		h.publicip.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/bandwidth"):
+		h.bandwidth.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/speedtest"):
+		h.speedTest.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/wgserver"):
+		h.wgServer.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/system"):
+		h.system.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/audit"):
+		h.audit.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/servers"):
+		h.servers.ServeHTTP(w, r)
 	default:
 		errString := fmt.Sprintf("%s %s not found", r.Method, r.RequestURI)
 		http.Error(w, errString, http.StatusBadRequest)
@@ -54,7 +77,7 @@ func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (h *handlerV1) getVersion(w http.ResponseWriter) {
 	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(h.buildInfo); err != nil {
+	if err := encoder.Encode(h.version.GetVersion()); err != nil {
 		h.warner.Warn(err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 	}
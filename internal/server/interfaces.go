@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"io/fs"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
@@ -9,26 +10,71 @@ import (
 
 type VPNLooper interface {
 	GetStatus() (status models.LoopStatus)
+	GetDetailedStatus() (status models.VPNStatus)
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	GetSettings() (settings settings.VPN)
 	SetSettings(ctx context.Context, settings settings.VPN) (outcome string)
+	GetHistory() (events []models.VPNEvent)
+	Pause(ctx context.Context) (outcome string, err error)
+	Resume(ctx context.Context) (outcome string, err error)
+	GetServerBlacklist() (servers []models.BlacklistedServer)
+	ClearServerBlacklist()
+}
+
+type VersionGetter interface {
+	GetVersion() (version models.VersionInformation)
 }
 
 type DNSLoop interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	GetStatus() (status models.LoopStatus)
+	GetBlocklist() (blocklist models.DNSBlocklist)
+	SetBlocklist(ctx context.Context, blocklist models.DNSBlocklist) (
+		outcome string, err error)
+	GetUpstreams() (upstreams []models.DNSUpstream)
+	GetTopDomains(limit int) (top models.DNSTopDomains)
+	ResetTopDomains()
 }
 
 type PortForwardedGetter interface {
 	GetPortForwarded() (portForwarded uint16)
 }
 
+type BandwidthGetter interface {
+	GetBandwidth() (bandwidth []models.InterfaceBandwidth)
+}
+
+type SpeedTester interface {
+	Run(ctx context.Context) (result models.SpeedTestResult, err error)
+}
+
+type DNSLeakTester interface {
+	Run(ctx context.Context) (result models.DNSLeakTestResult, err error)
+}
+
 type PublicIPLoop interface {
 	GetData() (data models.PublicIP)
 }
 
 type Storage interface {
+	GetServers(provider string) (servers []models.Server)
 	GetFilterChoices(provider string) models.FilterChoices
+	GetPortForwardFilterChoices(provider string) models.FilterChoices
+}
+
+type FileWriter interface {
+	AppendFile(path string, data []byte, perm fs.FileMode) (err error)
+}
+
+type WireguardServer interface {
+	GetPeers() (peers []settings.WireguardServerPeer)
+	AddPeer(peer settings.WireguardServerPeer) (err error)
+	RemovePeer(publicKey string) (err error)
+}
+
+type Lifecycle interface {
+	Stop() (outcome string)
+	Restart() (outcome string)
 }
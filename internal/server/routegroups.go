@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RouteGroup contains settings for a group of control server routes.
+type RouteGroup struct {
+	// Enabled is true if the routes of the group can be used.
+	Enabled bool
+	// APIKey is the key clients must set in the X-API-Key header
+	// to use the routes of the group. If it is empty, no key is required.
+	APIKey string
+}
+
+func withRouteGroupsMiddleware(childHandler http.Handler,
+	readOnly, admin RouteGroup) *routeGroupsMiddleware {
+	return &routeGroupsMiddleware{
+		childHandler: childHandler,
+		readOnly:     readOnly,
+		admin:        admin,
+	}
+}
+
+// routeGroupsMiddleware restricts access to the read-only and
+// admin route groups depending on their settings.
+type routeGroupsMiddleware struct {
+	childHandler http.Handler
+	readOnly     RouteGroup
+	admin        RouteGroup
+}
+
+func (m *routeGroupsMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	apiKey := r.Header.Get("X-API-Key")
+
+	if isAdminRequest(r) {
+		switch {
+		case !m.admin.Enabled:
+			http.Error(w, "admin routes are disabled", http.StatusForbidden)
+		case m.admin.APIKey == "" && isSystemRequest(r):
+			http.Error(w, "system routes require an admin API key to be set", http.StatusForbidden)
+		case !keyMatches(apiKey, m.admin.APIKey):
+			http.Error(w, "API key is not valid for admin routes", http.StatusUnauthorized)
+		default:
+			identity := makeIdentity("admin", m.admin.APIKey, "admin")
+			m.childHandler.ServeHTTP(w, withIdentity(r, identity))
+		}
+		return
+	}
+
+	// The admin API key, if set, also gives access to the read-only routes.
+	adminKeyMatches := m.admin.Enabled && m.admin.APIKey != "" &&
+		keyMatches(apiKey, m.admin.APIKey)
+	switch {
+	case !m.readOnly.Enabled:
+		http.Error(w, "read-only routes are disabled", http.StatusForbidden)
+	case adminKeyMatches:
+		identity := makeIdentity("read-only", m.admin.APIKey, "admin")
+		m.childHandler.ServeHTTP(w, withIdentity(r, identity))
+	case !keyMatches(apiKey, m.readOnly.APIKey):
+		http.Error(w, "API key is not valid for read-only routes", http.StatusUnauthorized)
+	default:
+		identity := makeIdentity("read-only", m.readOnly.APIKey, "read-only")
+		m.childHandler.ServeHTTP(w, withIdentity(r, identity))
+	}
+}
+
+// makeIdentity returns the identity of a request authenticated
+// for the route group given, using the API key of the key group given.
+func makeIdentity(routeGroup, apiKey, keyGroup string) (identity string) {
+	if apiKey == "" {
+		return routeGroup + " routes without API key"
+	}
+	return routeGroup + " routes with " + keyGroup + " API key"
+}
+
+type identityContextKey struct{}
+
+// withIdentity returns a shallow copy of the request with the
+// identity given set in its context, to be used by the audit log.
+func withIdentity(r *http.Request, identity string) *http.Request {
+	ctx := context.WithValue(r.Context(), identityContextKey{}, identity)
+	return r.WithContext(ctx)
+}
+
+// identityFromRequest returns the identity set in the request
+// context by the route groups middleware, or the empty string.
+func identityFromRequest(r *http.Request) (identity string) {
+	identity, _ = r.Context().Value(identityContextKey{}).(string)
+	return identity
+}
+
+// isAdminRequest returns true if the request is for an admin route,
+// which are the routes changing state, the legacy v0 routes, which
+// all trigger actions, the audit log routes and the Wireguard server
+// routes, which expose the peers configuration.
+func isAdminRequest(r *http.Request) (admin bool) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return true
+	}
+
+	path := requestPath(r)
+	if !strings.HasPrefix(path, "/v1/") {
+		return true
+	}
+	return strings.HasPrefix(path, "/v1/audit") ||
+		strings.HasPrefix(path, "/v1/wgserver")
+}
+
+// isSystemRequest returns true if the request is for a system route,
+// which stop or restart the program and are only usable with an
+// admin API key set.
+func isSystemRequest(r *http.Request) (system bool) {
+	return strings.HasPrefix(requestPath(r), "/v1/system")
+}
+
+// requestPath returns the request URI without its query string and
+// trailing slash, which is the value the handlers route on.
+func requestPath(r *http.Request) (path string) {
+	path, _, _ = strings.Cut(r.RequestURI, "?")
+	return strings.TrimSuffix(path, "/")
+}
+
+// keyMatches returns true if the expected key is empty,
+// or if the key given is equal to the expected key.
+func keyMatches(key, expected string) (matches bool) {
+	if expected == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(expected)) == 1
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_routeGroupsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		readOnly RouteGroup
+		admin    RouteGroup
+		method   string
+		path     string
+		apiKey   string
+		status   int
+		identity string
+	}{
+		"read-only route without keys": {
+			readOnly: RouteGroup{Enabled: true},
+			admin:    RouteGroup{Enabled: true},
+			method:   http.MethodGet,
+			path:     "/v1/publicip/ip",
+			status:   http.StatusOK,
+			identity: "read-only routes without API key",
+		},
+		"read-only routes disabled": {
+			admin:  RouteGroup{Enabled: true},
+			method: http.MethodGet,
+			path:   "/v1/vpn/status",
+			status: http.StatusForbidden,
+		},
+		"read-only route with wrong key": {
+			readOnly: RouteGroup{Enabled: true, APIKey: "read"},
+			admin:    RouteGroup{Enabled: true},
+			method:   http.MethodGet,
+			path:     "/v1/vpn/status",
+			apiKey:   "wrong",
+			status:   http.StatusUnauthorized,
+		},
+		"read-only route with admin key": {
+			readOnly: RouteGroup{Enabled: true, APIKey: "read"},
+			admin:    RouteGroup{Enabled: true, APIKey: "admin"},
+			method:   http.MethodGet,
+			path:     "/v1/vpn/status",
+			apiKey:   "admin",
+			status:   http.StatusOK,
+			identity: "read-only routes with admin API key",
+		},
+		"admin routes disabled": {
+			readOnly: RouteGroup{Enabled: true},
+			method:   http.MethodPut,
+			path:     "/v1/vpn/status",
+			status:   http.StatusForbidden,
+		},
+		"admin route with read-only key": {
+			readOnly: RouteGroup{Enabled: true, APIKey: "read"},
+			admin:    RouteGroup{Enabled: true, APIKey: "admin"},
+			method:   http.MethodPut,
+			path:     "/v1/vpn/status",
+			apiKey:   "read",
+			status:   http.StatusUnauthorized,
+		},
+		"admin route with admin key": {
+			readOnly: RouteGroup{Enabled: true, APIKey: "read"},
+			admin:    RouteGroup{Enabled: true, APIKey: "admin"},
+			method:   http.MethodPut,
+			path:     "/v1/vpn/status",
+			apiKey:   "admin",
+			status:   http.StatusOK,
+			identity: "admin routes with admin API key",
+		},
+		"v0 route is admin": {
+			readOnly: RouteGroup{Enabled: true},
+			method:   http.MethodGet,
+			path:     "/openvpn/actions/restart",
+			status:   http.StatusForbidden,
+		},
+		"audit route is admin": {
+			readOnly: RouteGroup{Enabled: true},
+			method:   http.MethodGet,
+			path:     "/v1/audit",
+			status:   http.StatusForbidden,
+		},
+		"system route without admin key": {
+			readOnly: RouteGroup{Enabled: true},
+			admin:    RouteGroup{Enabled: true},
+			method:   http.MethodPut,
+			path:     "/v1/system/stop",
+			status:   http.StatusForbidden,
+		},
+		"system route with admin key": {
+			readOnly: RouteGroup{Enabled: true},
+			admin:    RouteGroup{Enabled: true, APIKey: "admin"},
+			method:   http.MethodPut,
+			path:     "/v1/system/restart/",
+			apiKey:   "admin",
+			status:   http.StatusOK,
+			identity: "admin routes with admin API key",
+		},
+		"admin route with query string": {
+			readOnly: RouteGroup{Enabled: true},
+			method:   http.MethodGet,
+			path:     "/v1/audit?limit=1",
+			status:   http.StatusForbidden,
+		},
+		"wireguard server route is admin": {
+			readOnly: RouteGroup{Enabled: true},
+			method:   http.MethodGet,
+			path:     "/v1/wgserver/peers",
+			status:   http.StatusForbidden,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var identity string
+			childHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identity = identityFromRequest(r)
+				w.WriteHeader(http.StatusOK)
+			})
+			middleware := withRouteGroupsMiddleware(childHandler,
+				testCase.readOnly, testCase.admin)
+
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			if testCase.apiKey != "" {
+				request.Header.Set("X-API-Key", testCase.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.identity, identity)
+		})
+	}
+}
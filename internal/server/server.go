@@ -5,18 +5,22 @@ import (
 	"fmt"
 
 	"github.com/qdm12/gluetun/internal/httpserver"
-	"github.com/qdm12/gluetun/internal/models"
 )
 
-func New(ctx context.Context, address string, logEnabled bool, logger Logger,
-	buildInfo models.BuildInformation, openvpnLooper VPNLooper,
+func New(ctx context.Context, address string, logEnabled bool,
+	auditLogPath string, fileWriter FileWriter, logger Logger,
+	version VersionGetter, openvpnLooper VPNLooper,
 	pfGetter PortForwardedGetter, unboundLooper DNSLoop,
-	updaterLooper UpdaterLooper, publicIPLooper PublicIPLoop, storage Storage,
-	ipv6Supported bool) (
+	updaterLooper UpdaterLooper, publicIPLooper PublicIPLoop,
+	bandwidthGetter BandwidthGetter, speedTester SpeedTester,
+	dnsLeakTester DNSLeakTester, wireguardServer WireguardServer,
+	storage Storage, lifecycle Lifecycle,
+	readOnly, admin RouteGroup, ipv6Supported bool) (
 	server *httpserver.Server, err error) {
-	handler := newHandler(ctx, logger, logEnabled, buildInfo,
+	handler := newHandler(ctx, logger, logEnabled, auditLogPath, fileWriter, version,
 		openvpnLooper, pfGetter, unboundLooper, updaterLooper, publicIPLooper,
-		storage, ipv6Supported)
+		bandwidthGetter, speedTester, dnsLeakTester, wireguardServer, storage,
+		lifecycle, readOnly, admin, ipv6Supported)
 
 	httpServerSettings := httpserver.Settings{
 		Address: address,
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/validation"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+func newServersHandler(storage Storage, w warner) http.Handler {
+	return &serversHandler{
+		storage: storage,
+		warner:  w,
+	}
+}
+
+type serversHandler struct {
+	storage Storage
+	warner  warner
+}
+
+// serversChoices contains the distinct selection values of
+// the servers matching the filters of the request.
+type serversChoices struct {
+	Countries []string `json:"countries"`
+	Regions   []string `json:"regions"`
+	Cities    []string `json:"cities"`
+	ISPs      []string `json:"isps"`
+	Names     []string `json:"names"`
+	Hostnames []string `json:"hostnames"`
+}
+
+func (h *serversHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	provider := strings.ToLower(query.Get("provider"))
+	if provider == "" {
+		http.Error(w, "provider query parameter is missing", http.StatusBadRequest)
+		return
+	} else if !isProvider(provider) {
+		http.Error(w, "provider query parameter is not valid: "+provider, http.StatusBadRequest)
+		return
+	}
+
+	filters := map[string]func(server models.Server) string{
+		"vpn":      func(server models.Server) string { return server.VPN },
+		"country":  func(server models.Server) string { return server.Country },
+		"region":   func(server models.Server) string { return server.Region },
+		"city":     func(server models.Server) string { return server.City },
+		"isp":      func(server models.Server) string { return server.ISP },
+		"name":     func(server models.Server) string { return server.ServerName },
+		"hostname": func(server models.Server) string { return server.Hostname },
+	}
+
+	allServers := h.storage.GetServers(provider)
+	servers := make([]models.Server, 0, len(allServers))
+	for _, server := range allServers {
+		if serverMatches(server, query, filters) {
+			servers = append(servers, server)
+		}
+	}
+
+	choices := serversChoices{
+		Countries: validation.ExtractCountries(servers),
+		Regions:   validation.ExtractRegions(servers),
+		Cities:    validation.ExtractCities(servers),
+		ISPs:      validation.ExtractISPs(servers),
+		Names:     validation.ExtractServerNames(servers),
+		Hostnames: validation.ExtractHostnames(servers),
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(choices); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func isProvider(provider string) (ok bool) {
+	for _, existing := range providers.All() {
+		if provider == existing {
+			return true
+		}
+	}
+	return false
+}
+
+// serverMatches returns true if the server matches all the filters
+// set in the query. Each filter value is a comma separated list
+// of values matched case insensitively.
+func serverMatches(server models.Server, query url.Values,
+	filters map[string]func(server models.Server) string) (ok bool) {
+	for key, getValue := range filters {
+		csv := strings.Join(query[key], ",")
+		if csv == "" {
+			continue
+		}
+
+		serverValue := getValue(server)
+		matched := false
+		for _, value := range strings.Split(csv, ",") {
+			if strings.EqualFold(strings.TrimSpace(value), serverValue) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
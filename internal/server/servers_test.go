@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_serverMatches(t *testing.T) {
+	t.Parallel()
+
+	filters := map[string]func(server models.Server) string{
+		"country": func(server models.Server) string { return server.Country },
+		"city":    func(server models.Server) string { return server.City },
+	}
+	server := models.Server{Country: "Germany", City: "Berlin"}
+
+	testCases := map[string]struct {
+		rawQuery string
+		ok       bool
+	}{
+		"no filter": {
+			ok: true,
+		},
+		"matching case insensitive": {
+			rawQuery: "country=germany",
+			ok:       true,
+		},
+		"matching one of values": {
+			rawQuery: "country=France,%20Germany&city=berlin",
+			ok:       true,
+		},
+		"not matching one filter": {
+			rawQuery: "country=germany&city=munich",
+		},
+		"unknown filter ignored": {
+			rawQuery: "other=value",
+			ok:       true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := url.ParseQuery(testCase.rawQuery)
+			assert.NoError(t, err)
+
+			ok := serverMatches(server, query, filters)
+
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func newSpeedTestHandler(tester SpeedTester, w warner) http.Handler {
+	return &speedTestHandler{
+		tester: tester,
+		warner: w,
+	}
+}
+
+type speedTestHandler struct {
+	tester SpeedTester
+	warner warner
+}
+
+func (h *speedTestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/speedtest")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodPost:
+			h.runSpeedTest(w, r)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	default:
+		http.Error(w, "route "+r.RequestURI+" not supported", http.StatusBadRequest)
+	}
+}
+
+func (h *speedTestHandler) runSpeedTest(w http.ResponseWriter, r *http.Request) {
+	result, err := h.tester.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(result); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func newSystemHandler(lifecycle Lifecycle, apiKey string, w warner) http.Handler {
+	return &systemHandler{
+		lifecycle: lifecycle,
+		apiKey:    apiKey,
+		warner:    w,
+	}
+}
+
+type systemHandler struct {
+	lifecycle Lifecycle
+	// apiKey is the key clients must set in the X-API-Key header
+	// to stop or restart. If it is empty, all requests are refused.
+	apiKey string
+	warner warner
+}
+
+func (h *systemHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case h.apiKey == "":
+		http.Error(w, "an admin API key must be set to stop or restart", http.StatusForbidden)
+		return
+	case subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-Key")), []byte(h.apiKey)) != 1:
+		http.Error(w, "API key is not valid", http.StatusUnauthorized)
+		return
+	}
+
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/system")
+	switch r.RequestURI {
+	case "/stop":
+		switch r.Method {
+		case http.MethodPut:
+			h.writeOutcome(w, h.lifecycle.Stop())
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/restart":
+		switch r.Method {
+		case http.MethodPut:
+			h.writeOutcome(w, h.lifecycle.Restart())
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	default:
+		http.Error(w, "route "+r.RequestURI+" not supported", http.StatusBadRequest)
+	}
+}
+
+func (h *systemHandler) writeOutcome(w http.ResponseWriter, outcome string) {
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
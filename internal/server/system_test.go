@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLifecycle struct {
+	stopped bool
+}
+
+func (l *testLifecycle) Stop() (outcome string) {
+	l.stopped = true
+	return "stopping"
+}
+
+func (l *testLifecycle) Restart() (outcome string) {
+	return "restarting"
+}
+
+type testWarner struct{}
+
+func (testWarner) Warn(string) {}
+
+func Test_systemHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		handlerAPIKey string
+		apiKey        string
+		status        int
+		body          string
+		stopped       bool
+	}{
+		"missing handler key": {
+			apiKey: "key",
+			status: http.StatusForbidden,
+			body:   "an admin API key must be set to stop or restart\n",
+		},
+		"missing request key": {
+			handlerAPIKey: "key",
+			status:        http.StatusUnauthorized,
+			body:          "API key is not valid\n",
+		},
+		"wrong key": {
+			handlerAPIKey: "key",
+			apiKey:        "wrong",
+			status:        http.StatusUnauthorized,
+			body:          "API key is not valid\n",
+		},
+		"valid key": {
+			handlerAPIKey: "key",
+			apiKey:        "key",
+			status:        http.StatusOK,
+			body:          "{\"outcome\":\"stopping\"}\n",
+			stopped:       true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lifecycle := &testLifecycle{}
+			handler := newSystemHandler(lifecycle, testCase.handlerAPIKey, testWarner{})
+
+			request := httptest.NewRequest(http.MethodPut, "/system/stop", nil)
+			if testCase.apiKey != "" {
+				request.Header.Set("X-API-Key", testCase.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.body, recorder.Body.String())
+			assert.Equal(t, testCase.stopped, lifecycle.stopped)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
 )
 
 func newVPNHandler(ctx context.Context, looper VPNLooper,
@@ -49,15 +50,50 @@ func (h *vpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
 		}
+	case "/pause":
+		switch r.Method {
+		case http.MethodPut:
+			h.pause(w)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/resume":
+		switch r.Method {
+		case http.MethodPut:
+			h.resume(w)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/history":
+		switch r.Method {
+		case http.MethodGet:
+			h.getHistory(w)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	case "/blacklist":
+		switch r.Method {
+		case http.MethodGet:
+			h.getBlacklist(w)
+		case http.MethodDelete:
+			h.clearBlacklist(w)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
 	default:
 		http.Error(w, "route "+r.RequestURI+" not supported", http.StatusBadRequest)
 	}
 }
 
 func (h *vpnHandler) getStatus(w http.ResponseWriter) {
-	status := h.looper.GetStatus()
+	data := vpnStatusWrapper{
+		VPNStatus: h.looper.GetDetailedStatus(),
+	}
+	if vpnSettings := h.looper.GetSettings(); vpnSettings.Type != vpn.Tailscale &&
+		vpnSettings.Type != vpn.Tor {
+		data.Filters = &vpnSettings.Provider.ServerSelection
+	}
 	encoder := json.NewEncoder(w)
-	data := statusWrapper{Status: string(status)}
 	if err := encoder.Encode(data); err != nil {
 		h.warner.Warn(err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
@@ -65,6 +101,36 @@ func (h *vpnHandler) getStatus(w http.ResponseWriter) {
 	}
 }
 
+func (h *vpnHandler) getHistory(w http.ResponseWriter) {
+	events := h.looper.GetHistory()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(events); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getBlacklist(w http.ResponseWriter) {
+	servers := h.looper.GetServerBlacklist()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(servers); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) clearBlacklist(w http.ResponseWriter) {
+	h.looper.ClearServerBlacklist()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: "cleared"}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *vpnHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
 	var data statusWrapper
@@ -128,3 +194,31 @@ func (h *vpnHandler) patchSettings(w http.ResponseWriter, r *http.Request) {
 		h.warner.Warn("writing response: " + err.Error())
 	}
 }
+
+func (h *vpnHandler) pause(w http.ResponseWriter) {
+	outcome, err := h.looper.Pause(h.ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) resume(w http.ResponseWriter) {
+	outcome, err := h.looper.Resume(h.ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func newWireguardServerHandler(server WireguardServer, w warner) http.Handler {
+	return &wireguardServerHandler{
+		server: server,
+		warner: w,
+	}
+}
+
+type wireguardServerHandler struct {
+	server WireguardServer
+	warner warner
+}
+
+func (h *wireguardServerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/wgserver")
+	switch r.RequestURI {
+	case "/peers":
+		switch r.Method {
+		case http.MethodGet:
+			h.getPeers(w)
+		case http.MethodPost:
+			h.addPeer(w, r)
+		case http.MethodDelete:
+			h.removePeer(w, r)
+		default:
+			http.Error(w, "method "+r.Method+" not supported", http.StatusBadRequest)
+		}
+	default:
+		http.Error(w, "route "+r.RequestURI+" not supported", http.StatusBadRequest)
+	}
+}
+
+// wireguardServerPeer is a Wireguard server peer as returned
+// by the API, without its pre-shared key which is a secret.
+type wireguardServerPeer struct {
+	PublicKey string       `json:"public_key"`
+	Address   netip.Prefix `json:"address"`
+}
+
+func (h *wireguardServerHandler) getPeers(w http.ResponseWriter) {
+	peers := h.server.GetPeers()
+	response := make([]wireguardServerPeer, len(peers))
+	for i, peer := range peers {
+		response[i] = wireguardServerPeer{
+			PublicKey: peer.PublicKey,
+			Address:   peer.Address,
+		}
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(response); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *wireguardServerHandler) addPeer(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var peer settings.WireguardServerPeer
+	if err := decoder.Decode(&peer); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.AddPeer(peer); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: "added"}); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *wireguardServerHandler) removePeer(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data struct {
+		PublicKey string `json:"public_key"`
+	}
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.RemovePeer(data.PublicKey); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: "removed"}); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 )
@@ -14,6 +15,13 @@ type statusWrapper struct {
 
 var errInvalidStatus = errors.New("invalid status")
 
+type vpnStatusWrapper struct {
+	models.VPNStatus
+	// Filters are the server selection filters in effect,
+	// and are nil for Tailscale.
+	Filters *settings.ServerSelection `json:"filters,omitempty"`
+}
+
 func (sw *statusWrapper) getStatus() (status models.LoopStatus, err error) {
 	status = models.LoopStatus(sw.Status)
 	switch status {
@@ -0,0 +1,68 @@
+package serverfilter
+
+import (
+	"sort"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type fieldKind uint8
+
+const (
+	fieldString fieldKind = iota
+	fieldNumber
+	fieldBool
+)
+
+type field struct {
+	kind      fieldKind
+	getString func(server models.Server) string
+	getNumber func(server models.Server) int
+	getBool   func(server models.Server) bool
+}
+
+func stringField(getter func(server models.Server) string) field {
+	return field{kind: fieldString, getString: getter}
+}
+
+func numberField(getter func(server models.Server) int) field {
+	return field{kind: fieldNumber, getNumber: getter}
+}
+
+func boolField(getter func(server models.Server) bool) field {
+	return field{kind: fieldBool, getBool: getter}
+}
+
+// fields are the server fields usable in expressions. Server load is
+// not part of the servers data, so there is no load field.
+//
+//nolint:gochecknoglobals
+var fields = map[string]field{
+	"vpn":         stringField(func(s models.Server) string { return s.VPN }),
+	"country":     stringField(func(s models.Server) string { return s.Country }),
+	"region":      stringField(func(s models.Server) string { return s.Region }),
+	"city":        stringField(func(s models.Server) string { return s.City }),
+	"isp":         stringField(func(s models.Server) string { return s.ISP }),
+	"name":        stringField(func(s models.Server) string { return s.ServerName }),
+	"hostname":    stringField(func(s models.Server) string { return s.Hostname }),
+	"number":      numberField(func(s models.Server) int { return int(s.Number) }),
+	"owned":       boolField(func(s models.Server) bool { return s.Owned }),
+	"tcp":         boolField(func(s models.Server) bool { return s.TCP }),
+	"udp":         boolField(func(s models.Server) bool { return s.UDP }),
+	"multihop":    boolField(func(s models.Server) bool { return s.MultiHop }),
+	"free":        boolField(func(s models.Server) bool { return s.Free }),
+	"premium":     boolField(func(s models.Server) bool { return s.Premium }),
+	"stream":      boolField(func(s models.Server) bool { return s.Stream }),
+	"p2p":         boolField(func(s models.Server) bool { return s.P2P }),
+	"stealth":     boolField(func(s models.Server) bool { return s.Stealth }),
+	"portforward": boolField(func(s models.Server) bool { return s.PortForward }),
+}
+
+func fieldNames() (names []string) {
+	names = make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,114 @@
+package serverfilter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind uint8
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLeftParenthesis
+	tokenRightParenthesis
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	// position is the byte position of the token in the expression.
+	position int
+}
+
+var (
+	ErrStringNotTerminated = errors.New("string is not terminated")
+	ErrCharacterNotValid   = errors.New("character is not valid")
+)
+
+func tokenize(expression string) (tokens []token, err error) {
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParenthesis, value: "(", position: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParenthesis, value: ")", position: i})
+			i++
+		case hasPrefix(runes[i:], "&&"):
+			tokens = append(tokens, token{kind: tokenAnd, value: "&&", position: i})
+			i += 2
+		case hasPrefix(runes[i:], "||"):
+			tokens = append(tokens, token{kind: tokenOr, value: "||", position: i})
+			i += 2
+		case hasPrefix(runes[i:], "=="), hasPrefix(runes[i:], "!="),
+			hasPrefix(runes[i:], "<="), hasPrefix(runes[i:], ">="):
+			tokens = append(tokens, token{kind: tokenOperator, value: string(runes[i : i+2]), position: i})
+			i += 2
+		case r == '<' || r == '>':
+			tokens = append(tokens, token{kind: tokenOperator, value: string(r), position: i})
+			i++
+		case r == '!':
+			tokens = append(tokens, token{kind: tokenNot, value: "!", position: i})
+			i++
+		case r == '"':
+			start := i
+			i++
+			var builder strings.Builder
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("%w: at position %d", ErrStringNotTerminated, start)
+			}
+			i++ // closing double quote
+			tokens = append(tokens, token{kind: tokenString, value: builder.String(), position: start})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) ||
+				unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, value: string(runes[start:i]), position: start})
+		default:
+			return nil, fmt.Errorf("%w: %q at position %d", ErrCharacterNotValid, r, i)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, position: len(runes)})
+	return tokens, nil
+}
+
+func hasPrefix(runes []rune, prefix string) bool {
+	prefixRunes := []rune(prefix)
+	if len(runes) < len(prefixRunes) {
+		return false
+	}
+	for i, r := range prefixRunes {
+		if runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,258 @@
+package serverfilter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// Expression is a parsed server filter expression.
+type Expression struct {
+	match matcher
+}
+
+type matcher func(server models.Server) bool
+
+// Match returns true if the server matches the expression.
+// The zero value Expression matches all servers.
+func (e Expression) Match(server models.Server) bool {
+	if e.match == nil {
+		return true
+	}
+	return e.match(server)
+}
+
+var (
+	ErrTokenUnexpected = errors.New("unexpected token")
+	ErrFieldUnknown    = errors.New("field is unknown")
+	ErrFieldNotBoolean = errors.New("field is not boolean")
+	ErrValueNotValid   = errors.New("value is not valid")
+	ErrOperatorInvalid = errors.New("operator is not valid for field")
+)
+
+// Parse parses an expression such as
+// `country=="Netherlands" && (p2p || !free) && number>=10`.
+// Strings are compared case insensitively.
+func Parse(expression string) (parsed Expression, err error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return parsed, fmt.Errorf("tokenizing: %w", err)
+	}
+
+	p := &parser{tokens: tokens}
+	match, err := p.parseOr()
+	if err != nil {
+		return parsed, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return parsed, unexpectedTokenError(next)
+	}
+
+	return Expression{match: match}, nil
+}
+
+type parser struct {
+	tokens []token
+	index  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEOF {
+		p.index++
+	}
+	return t
+}
+
+func unexpectedTokenError(t token) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%w: end of expression", ErrTokenUnexpected)
+	}
+	return fmt.Errorf("%w: %q at position %d", ErrTokenUnexpected, t.value, t.position)
+}
+
+func (p *parser) parseOr() (match matcher, err error) {
+	match, err = p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		left := match
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		match = func(server models.Server) bool {
+			return left(server) || right(server)
+		}
+	}
+	return match, nil
+}
+
+func (p *parser) parseAnd() (match matcher, err error) {
+	match, err = p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+		left := match
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		match = func(server models.Server) bool {
+			return left(server) && right(server)
+		}
+	}
+	return match, nil
+}
+
+func (p *parser) parseUnary() (match matcher, err error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(server models.Server) bool {
+			return !operand(server)
+		}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (match matcher, err error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLeftParenthesis:
+		match, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParenthesis {
+			return nil, unexpectedTokenError(closing)
+		}
+		return match, nil
+	case tokenIdentifier:
+		return p.parseField(t)
+	default:
+		return nil, unexpectedTokenError(t)
+	}
+}
+
+func (p *parser) parseField(identifier token) (match matcher, err error) {
+	name := strings.ToLower(identifier.value)
+	field, ok := fields[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, valid fields are %s",
+			ErrFieldUnknown, identifier.value, strings.Join(fieldNames(), ", "))
+	}
+
+	if p.peek().kind != tokenOperator {
+		if field.kind != fieldBool {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotBoolean, name)
+		}
+		return field.getBool, nil
+	}
+
+	operator := p.next()
+	value := p.next()
+
+	switch field.kind {
+	case fieldString:
+		return stringMatcher(field.getString, name, operator, value)
+	case fieldNumber:
+		return numberMatcher(field.getNumber, name, operator, value)
+	default:
+		return boolMatcher(field.getBool, name, operator, value)
+	}
+}
+
+func stringMatcher(getter func(models.Server) string, name string,
+	operator, value token) (match matcher, err error) {
+	if value.kind != tokenString {
+		return nil, fmt.Errorf("%w: %q for string field %s",
+			ErrValueNotValid, value.value, name)
+	}
+
+	switch operator.value {
+	case "==":
+		return func(server models.Server) bool {
+			return strings.EqualFold(getter(server), value.value)
+		}, nil
+	case "!=":
+		return func(server models.Server) bool {
+			return !strings.EqualFold(getter(server), value.value)
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s %s", ErrOperatorInvalid, name, operator.value)
+	}
+}
+
+func numberMatcher(getter func(models.Server) int, name string,
+	operator, value token) (match matcher, err error) {
+	if value.kind != tokenNumber {
+		return nil, fmt.Errorf("%w: %q for number field %s",
+			ErrValueNotValid, value.value, name)
+	}
+
+	number, err := strconv.Atoi(value.value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValueNotValid, err)
+	}
+
+	var compare func(a, b int) bool
+	switch operator.value {
+	case "==":
+		compare = func(a, b int) bool { return a == b }
+	case "!=":
+		compare = func(a, b int) bool { return a != b }
+	case "<":
+		compare = func(a, b int) bool { return a < b }
+	case "<=":
+		compare = func(a, b int) bool { return a <= b }
+	case ">":
+		compare = func(a, b int) bool { return a > b }
+	case ">=":
+		compare = func(a, b int) bool { return a >= b }
+	}
+
+	return func(server models.Server) bool {
+		return compare(getter(server), number)
+	}, nil
+}
+
+func boolMatcher(getter func(models.Server) bool, name string,
+	operator, value token) (match matcher, err error) {
+	if value.kind != tokenIdentifier ||
+		(value.value != "true" && value.value != "false") {
+		return nil, fmt.Errorf("%w: %q for boolean field %s",
+			ErrValueNotValid, value.value, name)
+	}
+	expected := value.value == "true"
+
+	switch operator.value {
+	case "==":
+		return func(server models.Server) bool {
+			return getter(server) == expected
+		}, nil
+	case "!=":
+		return func(server models.Server) bool {
+			return getter(server) != expected
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s %s", ErrOperatorInvalid, name, operator.value)
+	}
+}
@@ -0,0 +1,156 @@
+package serverfilter
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		errWrapped error
+		errMessage string
+	}{
+		"string not terminated": {
+			expression: `country=="Netherlands`,
+			errWrapped: ErrStringNotTerminated,
+			errMessage: "tokenizing: string is not terminated: at position 9",
+		},
+		"invalid character": {
+			expression: `country=Netherlands`,
+			errWrapped: ErrCharacterNotValid,
+			errMessage: "tokenizing: character is not valid: '=' at position 7",
+		},
+		"unknown field": {
+			expression: `load<30`,
+			errWrapped: ErrFieldUnknown,
+			errMessage: "field is unknown: load, valid fields are city, country, " +
+				"free, hostname, isp, multihop, name, number, owned, p2p, " +
+				"portforward, premium, region, stealth, stream, tcp, udp, vpn",
+		},
+		"string field used as boolean": {
+			expression: `country`,
+			errWrapped: ErrFieldNotBoolean,
+			errMessage: "field is not boolean: country",
+		},
+		"string field with number": {
+			expression: `country==1`,
+			errWrapped: ErrValueNotValid,
+			errMessage: `value is not valid: "1" for string field country`,
+		},
+		"string field with ordering operator": {
+			expression: `country<"a"`,
+			errWrapped: ErrOperatorInvalid,
+			errMessage: "operator is not valid for field: country <",
+		},
+		"missing closing parenthesis": {
+			expression: `(p2p || free`,
+			errWrapped: ErrTokenUnexpected,
+			errMessage: "unexpected token: end of expression",
+		},
+		"trailing token": {
+			expression: `p2p free`,
+			errWrapped: ErrTokenUnexpected,
+			errMessage: `unexpected token: "free" at position 4`,
+		},
+		"valid": {
+			expression: `country=="Netherlands" && (p2p || !free) && number>=10`,
+		},
+		"valid documented example": {
+			expression: `country=="Netherlands" && number<30 && portforward`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(testCase.expression)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Expression_Match(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		server     models.Server
+		match      bool
+	}{
+		"empty expression": {
+			server: models.Server{Country: "Netherlands"},
+			match:  true,
+		},
+		"string equal case insensitive": {
+			expression: `country=="netherlands"`,
+			server:     models.Server{Country: "Netherlands"},
+			match:      true,
+		},
+		"string not equal": {
+			expression: `city!="Amsterdam"`,
+			server:     models.Server{City: "Amsterdam"},
+		},
+		"number comparison": {
+			expression: `number>=10 && number<20`,
+			server:     models.Server{Number: 15},
+			match:      true,
+		},
+		"boolean field": {
+			expression: `portforward`,
+			server:     models.Server{PortForward: true},
+			match:      true,
+		},
+		"boolean comparison": {
+			expression: `free==false`,
+			server:     models.Server{Free: true},
+		},
+		"precedence": {
+			expression: `p2p || free && stream`,
+			server:     models.Server{P2P: true},
+			match:      true,
+		},
+		"documented example": {
+			expression: `country=="Netherlands" && number<30 && portforward`,
+			server:     models.Server{Country: "Netherlands", Number: 12, PortForward: true},
+			match:      true,
+		},
+		"documented example without port forwarding": {
+			expression: `country=="Netherlands" && number<30 && portforward`,
+			server:     models.Server{Country: "Netherlands", Number: 12},
+		},
+		"negated group": {
+			expression: `!(p2p || stream)`,
+			server:     models.Server{Stream: true},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var expression Expression
+			if testCase.expression != "" {
+				var err error
+				expression, err = Parse(testCase.expression)
+				require.NoError(t, err)
+			}
+
+			match := expression.Match(testCase.server)
+
+			assert.Equal(t, testCase.match, match)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package shadowsocks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+)
+
+// demandRelay listens for TCP connections on its address and relays
+// them to the target address, demanding the VPN for each connection
+// accepted, such that the first client connection starts the VPN when
+// it is in on demand mode.
+type demandRelay struct {
+	address string
+	target  string
+	vpn     VPNDemander
+	logger  Logger
+}
+
+func (d *demandRelay) Listen(ctx context.Context) (err error) {
+	listenConfig := net.ListenConfig{}
+	listener, err := listenConfig.Listen(ctx, "tcp", d.address)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		if err := listener.Close(); err != nil {
+			d.logger.Error(err.Error())
+		}
+	}()
+
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			d.logger.Error("cannot accept connection on TCP listener: " + err.Error())
+			continue
+		}
+		d.vpn.Demand(ctx)
+		go d.relay(ctx, connection)
+	}
+}
+
+func (d *demandRelay) relay(ctx context.Context, connection net.Conn) {
+	defer connection.Close()
+
+	var dialer net.Dialer
+	targetConnection, err := dialer.DialContext(ctx, "tcp", d.target)
+	if err != nil {
+		d.logger.Error("cannot connect to Shadowsocks server: " + err.Error())
+		return
+	}
+	defer targetConnection.Close()
+
+	errCh := make(chan error)
+	go copyAndCloseWrite(targetConnection, connection, errCh)
+	go copyAndCloseWrite(connection, targetConnection, errCh)
+	for i := 0; i < 2; i++ {
+		err := <-errCh
+		if err != nil && !errors.Is(err, net.ErrClosed) {
+			d.logger.Debug("relay error: " + err.Error())
+		}
+	}
+}
+
+// copyAndCloseWrite copies from source to destination, and closes
+// the writing side of the destination once source is exhausted.
+func copyAndCloseWrite(destination, source net.Conn, errCh chan<- error) {
+	_, err := io.Copy(destination, source)
+	if tcpConnection, ok := destination.(*net.TCPConn); ok {
+		_ = tcpConnection.CloseWrite()
+	}
+	errCh <- err
+}
@@ -0,0 +1,88 @@
+package shadowsocks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDemander struct {
+	demands atomic.Int32
+}
+
+func (d *testDemander) Demand(context.Context) { d.demands.Add(1) }
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_demandRelay(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Echo server standing for the Shadowsocks TCP server
+	echoListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer echoListener.Close()
+	go func() {
+		for {
+			connection, err := echoListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer connection.Close()
+				_, _ = io.Copy(connection, connection)
+			}()
+		}
+	}()
+
+	relayPort, err := getFreeLocalPort()
+	require.NoError(t, err)
+	relayAddress := net.JoinHostPort("127.0.0.1", fmt.Sprint(relayPort))
+
+	demander := &testDemander{}
+	relay := &demandRelay{
+		address: relayAddress,
+		target:  echoListener.Addr().String(),
+		vpn:     demander,
+		logger:  noopLogger{},
+	}
+	listenErr := make(chan error)
+	go func() {
+		listenErr <- relay.Listen(ctx)
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", relayAddress)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = connection.Write([]byte("hello"))
+	require.NoError(t, err)
+	err = connection.(*net.TCPConn).CloseWrite() //nolint:forcetypeassert
+	require.NoError(t, err)
+	data, err := io.ReadAll(connection)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	err = connection.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), demander.demands.Load())
+
+	cancel()
+	err = <-listenErr
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,7 @@
+package shadowsocks
+
+import "context"
+
+type VPNDemander interface {
+	Demand(ctx context.Context)
+}
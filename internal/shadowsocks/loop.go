@@ -8,13 +8,15 @@ import (
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
-	shadowsockslib "github.com/qdm12/ss-server/pkg/tcpudp"
+	"github.com/qdm12/golibs/command"
 )
 
 type Loop struct {
 	state state
 	// Other objects
-	logger Logger
+	starter command.Starter
+	vpn     VPNDemander
+	logger  Logger
 	// Internal channels and locks
 	loopLock      sync.Mutex
 	running       chan models.LoopStatus
@@ -41,12 +43,18 @@ func (l *Loop) logAndWait(ctx context.Context, err error) {
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLoop(settings settings.Shadowsocks, logger Logger) *Loop {
+// NewLoop creates a Shadowsocks server loop. The VPN demander vpn
+// is called on each TCP client connection, and should be nil if
+// the VPN is not in on demand mode.
+func NewLoop(settings settings.Shadowsocks, starter command.Starter,
+	vpn VPNDemander, logger Logger) *Loop {
 	return &Loop{
 		state: state{
 			status:   constants.Stopped,
 			settings: settings,
 		},
+		starter:     starter,
+		vpn:         vpn,
 		logger:      logger,
 		start:       make(chan struct{}),
 		running:     make(chan models.LoopStatus),
@@ -75,7 +83,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 
 	for ctx.Err() == nil {
 		settings := l.GetSettings()
-		server, err := shadowsockslib.NewServer(settings.Settings, l.logger)
+		server, err := newServer(settings, l.starter, l.vpn, l.logger)
 		if err != nil {
 			crashed = true
 			l.logAndWait(ctx, err)
@@ -0,0 +1,220 @@
+package shadowsocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/golibs/command"
+	"github.com/qdm12/ss-server/pkg/tcp"
+	"github.com/qdm12/ss-server/pkg/udp"
+)
+
+var (
+	ErrPluginStart = errors.New("cannot start plugin")
+	ErrPluginExit  = errors.New("plugin exited")
+)
+
+type listener interface {
+	Listen(ctx context.Context) (err error)
+}
+
+// server runs the Shadowsocks TCP server, the UDP relay if enabled
+// and the SIP003 plugin if set, until one of them fails or the
+// context is canceled.
+type server struct {
+	tcp listener
+	udp listener
+	// demandRelay relays TCP connections to the TCP server, demanding
+	// the VPN for each of them, and is nil if the VPN is not in on
+	// demand mode.
+	demandRelay listener
+	// pluginPath and pluginEnv are the SIP003 plugin program and its
+	// environment variables, and pluginPath is empty if no plugin is set.
+	pluginPath string
+	pluginEnv  []string
+	starter    command.Starter
+	logger     Logger
+}
+
+func newServer(settings settings.Shadowsocks, starter command.Starter,
+	vpn VPNDemander, logger Logger) (s *server, err error) {
+	ssSettings := settings.Settings.Copy()
+	ssSettings.SetDefaults()
+
+	s = &server{
+		starter: starter,
+		logger:  logger,
+	}
+
+	if settings.Plugin != "" {
+		// The plugin listens on the listening address and relays
+		// TCP traffic to the server listening on a local port.
+		localPort, err := getFreeLocalPort()
+		if err != nil {
+			return nil, fmt.Errorf("getting free local port for plugin: %w", err)
+		}
+		remoteHost, remotePort, err := net.SplitHostPort(ssSettings.TCP.Address)
+		if err != nil {
+			return nil, fmt.Errorf("splitting listening address: %w", err)
+		}
+		if remoteHost == "" {
+			remoteHost = "0.0.0.0"
+		}
+		const localHost = "127.0.0.1"
+		ssSettings.TCP.Address = net.JoinHostPort(localHost, fmt.Sprint(localPort))
+		s.pluginPath = settings.Plugin
+		s.pluginEnv = makePluginEnv(settings.PluginOptions,
+			remoteHost, remotePort, localHost, localPort)
+	}
+
+	if vpn != nil {
+		// The demand relay listens on the listening address, or on the
+		// local address the plugin relays to, and relays TCP traffic
+		// to the server listening on another local port.
+		localPort, err := getFreeLocalPort()
+		if err != nil {
+			return nil, fmt.Errorf("getting free local port for demand relay: %w", err)
+		}
+		target := net.JoinHostPort("127.0.0.1", fmt.Sprint(localPort))
+		s.demandRelay = &demandRelay{
+			address: ssSettings.TCP.Address,
+			target:  target,
+			vpn:     vpn,
+			logger:  logger,
+		}
+		ssSettings.TCP.Address = target
+	}
+
+	s.tcp, err = tcp.NewServer(ssSettings.TCP, logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating TCP server: %w", err)
+	}
+
+	if *settings.UDP {
+		s.udp, err = udp.NewServer(ssSettings.UDP, logger)
+		if err != nil {
+			return nil, fmt.Errorf("creating UDP server: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *server) Listen(ctx context.Context) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	listeners := []listener{s.tcp}
+	if s.udp != nil {
+		listeners = append(listeners, s.udp)
+	}
+	if s.demandRelay != nil {
+		listeners = append(listeners, s.demandRelay)
+	}
+
+	errCh := make(chan error)
+	for _, l := range listeners {
+		go func(l listener) {
+			errCh <- l.Listen(ctx)
+		}(l)
+	}
+	running := len(listeners)
+
+	if s.pluginPath != "" {
+		cmd := exec.CommandContext(ctx, s.pluginPath) //nolint:gosec
+		cmd.Env = s.pluginEnv
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		stdoutLines, stderrLines, waitError, err := s.starter.Start(cmd)
+		if err != nil {
+			cancel()
+			for ; running > 0; running-- {
+				<-errCh
+			}
+			return fmt.Errorf("%w: %s", ErrPluginStart, err)
+		}
+		s.logger.Info("started plugin " + s.pluginPath)
+
+		streamCtx, streamCancel := context.WithCancel(context.Background())
+		streamDone := make(chan struct{})
+		go streamLines(streamCtx, streamDone, s.logger, stdoutLines, stderrLines)
+		defer func() {
+			streamCancel()
+			<-streamDone
+		}()
+
+		go func() {
+			err := <-waitError
+			close(waitError)
+			if err == nil || ctx.Err() != nil {
+				err = fmt.Errorf("%w", ErrPluginExit)
+			} else {
+				err = fmt.Errorf("%w: %s", ErrPluginExit, err)
+			}
+			errCh <- err
+		}()
+		running++
+	}
+
+	// Return the first error and wait for the others to exit.
+	err = <-errCh
+	running--
+	cancel()
+	for ; running > 0; running-- {
+		<-errCh
+	}
+	return err
+}
+
+// makePluginEnv returns the environment variables
+// configuring a SIP003 plugin.
+func makePluginEnv(options, remoteHost, remotePort,
+	localHost string, localPort uint16) (env []string) {
+	return append(os.Environ(),
+		"SS_REMOTE_HOST="+remoteHost,
+		"SS_REMOTE_PORT="+remotePort,
+		"SS_LOCAL_HOST="+localHost,
+		fmt.Sprintf("SS_LOCAL_PORT=%d", localPort),
+		"SS_PLUGIN_OPTIONS="+options,
+	)
+}
+
+// getFreeLocalPort returns a TCP port currently free on the loopback
+// interface, for the server to listen on behind the plugin.
+func getFreeLocalPort() (port uint16, err error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	port = uint16(listener.Addr().(*net.TCPAddr).Port) //nolint:forcetypeassert
+	err = listener.Close()
+	if err != nil {
+		return 0, err
+	}
+	return port, nil
+}
+
+func streamLines(ctx context.Context, done chan<- struct{},
+	logger Logger, stdout, stderr chan string) {
+	defer close(done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Context should only be canceled after stdout and stderr are done
+			// being written to.
+			close(stdout)
+			close(stderr)
+			return
+		case line := <-stdout:
+			logger.Info(line)
+		case line := <-stderr:
+			logger.Error(line)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package speedtest measures the latency and throughput
+// of the network connection, which is the VPN tunnel when
+// it is up.
+package speedtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrAlreadyRunning      = errors.New("speed test is already running")
+	ErrHTTPStatusCodeNotOK = errors.New("HTTP status code is not OK")
+)
+
+type Tester struct {
+	settings settings.SpeedTest
+	client   *http.Client
+	running  sync.Mutex
+	// Mock functions
+	timeNow func() time.Time
+}
+
+func New(settings settings.SpeedTest, client *http.Client) *Tester {
+	return &Tester{
+		settings: settings,
+		client:   client,
+		timeNow:  time.Now,
+	}
+}
+
+// Run runs the download and upload tests one after the other,
+// and returns an error if a test is already running.
+func (t *Tester) Run(ctx context.Context) (result models.SpeedTestResult, err error) {
+	if !t.running.TryLock() {
+		return result, fmt.Errorf("%w", ErrAlreadyRunning)
+	}
+	defer t.running.Unlock()
+
+	latency, downloadBytes, downloadDuration, err := t.download(ctx)
+	if err != nil {
+		return result, fmt.Errorf("downloading: %w", err)
+	}
+	result.LatencyMilliseconds = durationToMilliseconds(latency)
+	result.DownloadBytes = downloadBytes
+	result.DownloadMbps = toMbps(downloadBytes, downloadDuration)
+
+	if *t.settings.UploadURL == "" {
+		return result, nil
+	}
+
+	uploadBytes, uploadDuration, err := t.upload(ctx)
+	if err != nil {
+		return result, fmt.Errorf("uploading: %w", err)
+	}
+	result.UploadBytes = uploadBytes
+	result.UploadMbps = toMbps(uploadBytes, uploadDuration)
+
+	return result, nil
+}
+
+// download downloads from the download URL and returns the time to
+// the first byte of the response as latency, the number of bytes
+// downloaded and the duration of the body download.
+func (t *Tester) download(ctx context.Context) (latency time.Duration,
+	bytesRead int64, duration time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(ctx, t.settings.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, t.settings.DownloadURL, nil)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	start := t.timeNow()
+	response, err := t.client.Do(request)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer response.Body.Close()
+	firstByteTime := t.timeNow()
+	latency = firstByteTime.Sub(start)
+
+	if response.StatusCode != http.StatusOK {
+		return 0, 0, 0, fmt.Errorf("%w: %d %s", ErrHTTPStatusCodeNotOK,
+			response.StatusCode, response.Status)
+	}
+
+	bytesRead, err = io.Copy(io.Discard, response.Body)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("reading response body: %w", err)
+	}
+	duration = t.timeNow().Sub(firstByteTime)
+
+	err = response.Body.Close()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("closing response body: %w", err)
+	}
+
+	return latency, bytesRead, duration, nil
+}
+
+func (t *Tester) upload(ctx context.Context) (bytesSent int64,
+	duration time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(ctx, t.settings.Timeout)
+	defer cancel()
+
+	const megabyte = 1000 * 1000
+	size := int64(t.settings.UploadMegabytes) * megabyte
+	body := io.LimitReader(zeroReader{}, size)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, *t.settings.UploadURL, body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("creating request: %w", err)
+	}
+	request.ContentLength = size
+	request.Header.Set("Content-Type", "application/octet-stream")
+
+	start := t.timeNow()
+	response, err := t.client.Do(request)
+	if err != nil {
+		return 0, 0, err
+	}
+	duration = t.timeNow().Sub(start)
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	err = response.Body.Close()
+	if err != nil {
+		return 0, 0, fmt.Errorf("closing response body: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("%w: %d %s", ErrHTTPStatusCodeNotOK,
+			response.StatusCode, response.Status)
+	}
+
+	return size, duration, nil
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (n int, err error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func toMbps(bytes int64, duration time.Duration) (mbps float64) {
+	if duration <= 0 {
+		return 0
+	}
+	const bitsPerByte, bitsPerMegabit = 8, 1000 * 1000
+	return float64(bytes*bitsPerByte) / bitsPerMegabit / duration.Seconds()
+}
+
+func durationToMilliseconds(duration time.Duration) (milliseconds float64) {
+	return float64(duration) / float64(time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package speedtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tester_Run(t *testing.T) {
+	t.Parallel()
+
+	var uploaded int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = io.Copy(w, strings.NewReader(strings.Repeat("x", 1000)))
+		case http.MethodPost:
+			uploaded, _ = io.Copy(io.Discard, r.Body)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	uploadURL := server.URL
+	tester := New(settings.SpeedTest{
+		DownloadURL:     server.URL,
+		UploadURL:       &uploadURL,
+		UploadMegabytes: 1,
+		Timeout:         time.Second,
+	}, server.Client())
+	// Each call to timeNow advances the time by one second.
+	now := time.Unix(0, 0)
+	tester.timeNow = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	result, err := tester.Run(context.Background())
+
+	require.NoError(t, err)
+	expected := models.SpeedTestResult{
+		LatencyMilliseconds: 1000,
+		DownloadBytes:       1000,
+		DownloadMbps:        0.008,
+		UploadBytes:         1000000,
+		UploadMbps:          8,
+	}
+	assert.Equal(t, expected, result)
+	assert.Equal(t, int64(1000000), uploaded)
+}
+
+func Test_Tester_Run_alreadyRunning(t *testing.T) {
+	t.Parallel()
+
+	tester := New(settings.SpeedTest{}, nil)
+	tester.running.Lock()
+
+	_, err := tester.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrAlreadyRunning)
+}
@@ -15,7 +15,30 @@ func (s *Storage) GetFilterChoices(provider string) models.FilterChoices {
 	defer s.mergedMutex.RUnlock()
 
 	serversObject := s.getMergedServersObject(provider)
-	servers := serversObject.Servers
+	return extractFilterChoices(serversObject.Servers)
+}
+
+// GetPortForwardFilterChoices returns the filter choices for
+// the servers of the provider supporting port forwarding.
+func (s *Storage) GetPortForwardFilterChoices(provider string) models.FilterChoices {
+	if provider == providers.Custom {
+		return models.FilterChoices{}
+	}
+
+	s.mergedMutex.RLock()
+	defer s.mergedMutex.RUnlock()
+
+	serversObject := s.getMergedServersObject(provider)
+	servers := make([]models.Server, 0, len(serversObject.Servers))
+	for _, server := range serversObject.Servers {
+		if server.PortForward {
+			servers = append(servers, server)
+		}
+	}
+	return extractFilterChoices(servers)
+}
+
+func extractFilterChoices(servers []models.Server) models.FilterChoices {
 	return models.FilterChoices{
 		Countries: validation.ExtractCountries(servers),
 		Regions:   validation.ExtractRegions(servers),
@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/features"
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/serverfilter"
 )
 
 // FilterServers filter servers for the given provider and according
@@ -29,8 +31,17 @@ func (s *Storage) FilterServers(provider string, selection settings.ServerSelect
 		return nil, ErrNoServerFound
 	}
 
+	var expression serverfilter.Expression
+	if selection.Filter != "" {
+		expression, err = serverfilter.Parse(selection.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("parsing server filter: %w", err)
+		}
+	}
+
 	for _, server := range allServers {
-		if filterServer(server, selection) {
+		if filterServer(server, selection) ||
+			!expression.Match(server) {
 			continue
 		}
 
@@ -74,6 +85,14 @@ func filterServer(server models.Server,
 		return true
 	}
 
+	if *selection.PortForwardOnly && !server.PortForward {
+		return true
+	}
+
+	if filterByFeatures(server, selection.Features) {
+		return true
+	}
+
 	if filterByPossibilities(server.Country, selection.Countries) {
 		return true
 	}
@@ -102,8 +121,41 @@ func filterServer(server models.Server,
 		return true
 	}
 
-	// TODO filter port forward server for PIA
+	if filterByExclusions(server.Country, selection.ExcludedCountries) {
+		return true
+	}
+
+	if filterByExclusions(server.Region, selection.ExcludedRegions) {
+		return true
+	}
+
+	if filterByExclusions(server.City, selection.ExcludedCities) {
+		return true
+	}
+
+	if filterByExclusions(server.ISP, selection.ExcludedISPs) {
+		return true
+	}
+
+	if filterByExclusions(server.ServerName, selection.ExcludedNames) {
+		return true
+	}
+
+	if filterByExclusions(server.Hostname, selection.ExcludedHostnames) {
+		return true
+	}
+
+	return false
+}
 
+// filterByExclusions returns true if the value is one of the
+// exclusions given. Exclusions are applied after the filters above.
+func filterByExclusions(value string, exclusions []string) (filtered bool) {
+	for _, exclusion := range exclusions {
+		if strings.EqualFold(value, exclusion) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -119,6 +171,28 @@ func filterByPossibilities[T string | uint16](value T, possibilities []T) (filte
 	return true
 }
 
+func filterByFeatures(server models.Server, features []string) (filtered bool) {
+	for _, feature := range features {
+		if !serverHasFeature(server, feature) {
+			return true
+		}
+	}
+	return false
+}
+
+func serverHasFeature(server models.Server, feature string) (ok bool) {
+	switch strings.ToLower(feature) {
+	case features.P2P:
+		return server.P2P
+	case features.Streaming:
+		return server.Stream
+	case features.Stealth:
+		return server.Stealth
+	default:
+		return false
+	}
+}
+
 func filterByProtocol(selection settings.ServerSelection,
 	serverTCP, serverUDP bool) (filtered bool) {
 	switch selection.VPN {
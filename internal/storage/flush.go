@@ -2,8 +2,6 @@ package storage
 
 import (
 	"encoding/json"
-	"os"
-	"path/filepath"
 	"sort"
 
 	"github.com/qdm12/gluetun/internal/models"
@@ -21,28 +19,15 @@ func (s *Storage) FlushToFile(path string) error {
 // flushToFile flushes the merged servers data to the file
 // specified by path, as indented JSON. It is not thread-safe.
 func (s *Storage) flushToFile(path string) error {
-	dirPath := filepath.Dir(path)
-	if err := os.MkdirAll(dirPath, 0644); err != nil {
-		return err
-	}
-
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		return err
-	}
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-
 	for _, obj := range s.mergedServers.ProviderToServers {
 		sort.Sort(models.SortableServers(obj.Servers))
 	}
 
-	err = encoder.Encode(&s.mergedServers)
+	data, err := json.MarshalIndent(&s.mergedServers, "", "  ")
 	if err != nil {
-		_ = file.Close()
 		return err
 	}
+	data = append(data, '\n')
 
-	return file.Close()
+	return s.fileWriter.WriteFile(path, data, s.filePermissions)
 }
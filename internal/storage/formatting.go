@@ -61,6 +61,24 @@ func noServerFoundError(selection settings.ServerSelection) (err error) {
 		messageParts = append(messageParts, "owned servers only")
 	}
 
+	if *selection.PortForwardOnly {
+		messageParts = append(messageParts, "port forwarding servers only")
+	}
+
+	if selection.Filter != "" {
+		messageParts = append(messageParts, "filter "+selection.Filter)
+	}
+
+	switch len(selection.Features) {
+	case 0:
+	case 1:
+		part := "feature " + selection.Features[0]
+		messageParts = append(messageParts, part)
+	default:
+		part := "features " + commaJoin(selection.Features)
+		messageParts = append(messageParts, part)
+	}
+
 	switch len(selection.ISPs) {
 	case 0:
 	case 1:
@@ -118,6 +136,17 @@ func noServerFoundError(selection settings.ServerSelection) (err error) {
 		messageParts = append(messageParts, "premium tier only")
 	}
 
+	exclusions := [][]string{selection.ExcludedCountries, selection.ExcludedRegions,
+		selection.ExcludedCities, selection.ExcludedISPs, selection.ExcludedNames,
+		selection.ExcludedHostnames}
+	var excluded []string
+	for _, exclusion := range exclusions {
+		excluded = append(excluded, exclusion...)
+	}
+	if len(excluded) > 0 {
+		messageParts = append(messageParts, "excluding "+commaJoin(excluded))
+	}
+
 	message := "for " + strings.Join(messageParts, "; ")
 
 	return fmt.Errorf("%w: %s", ErrNoServerFound, message)
@@ -4,6 +4,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/format"
@@ -28,6 +29,20 @@ func (s *Storage) mergeServers(hardcoded, persisted models.AllServers) models.Al
 
 func (s *Storage) mergeProviderServers(provider string,
 	hardcoded, persisted models.Servers) (merged models.Servers) {
+	if len(persisted.Servers) == 0 {
+		return hardcoded
+	}
+
+	switch s.mergeStrategy {
+	case constants.ServersMergeFile:
+		s.logger.Info("Using " + provider + " servers from file")
+		return persisted
+	case constants.ServersMergeEmbedded:
+		s.logger.Info("Using " + provider + " embedded servers, " +
+			"keeping servers marked to keep from file")
+		return mergeKeptServers(hardcoded, persisted)
+	}
+
 	if persisted.Timestamp > hardcoded.Timestamp {
 		diff := time.Unix(persisted.Timestamp, 0).Sub(time.Unix(hardcoded.Timestamp, 0))
 		if diff < 0 {
@@ -41,6 +56,14 @@ func (s *Storage) mergeProviderServers(provider string,
 		return persisted
 	}
 
+	s.logger.Info("Using " + provider + " embedded servers which are more recent, " +
+		"keeping servers marked to keep from file")
+	return mergeKeptServers(hardcoded, persisted)
+}
+
+// mergeKeptServers returns the hardcoded servers where the persisted
+// servers marked to keep replace or are added to the hardcoded servers.
+func mergeKeptServers(hardcoded, persisted models.Servers) (merged models.Servers) {
 	persistedServerKeyToServer := make(map[string]models.Server)
 	for _, persistedServer := range persisted.Servers {
 		if persistedServer.Keep {
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Storage_mergeProviderServers(t *testing.T) {
+	t.Parallel()
+
+	hardcoded := models.Servers{
+		Timestamp: 2,
+		Servers: []models.Server{
+			{Hostname: "a"},
+			{Hostname: "b"},
+		},
+	}
+	persisted := models.Servers{
+		Timestamp: 1,
+		Servers: []models.Server{
+			{Hostname: "b", Keep: true, City: "edited"},
+			{Hostname: "c"},
+		},
+	}
+	hardcodedWithKept := models.Servers{
+		Timestamp: 2,
+		Servers: []models.Server{
+			{Hostname: "a"},
+			{Hostname: "b", Keep: true, City: "edited"},
+		},
+	}
+
+	testCases := map[string]struct {
+		mergeStrategy string
+		hardcoded     models.Servers
+		persisted     models.Servers
+		logMessage    string
+		merged        models.Servers
+	}{
+		"no persisted servers": {
+			mergeStrategy: constants.ServersMergeFile,
+			hardcoded:     hardcoded,
+			merged:        hardcoded,
+		},
+		"newest with older file": {
+			mergeStrategy: constants.ServersMergeNewest,
+			hardcoded:     hardcoded,
+			persisted:     persisted,
+			logMessage: "Using provider embedded servers which are more recent, " +
+				"keeping servers marked to keep from file",
+			merged: hardcodedWithKept,
+		},
+		"file with older file": {
+			mergeStrategy: constants.ServersMergeFile,
+			hardcoded:     hardcoded,
+			persisted:     persisted,
+			logMessage:    "Using provider servers from file",
+			merged:        persisted,
+		},
+		"embedded": {
+			mergeStrategy: constants.ServersMergeEmbedded,
+			hardcoded:     hardcoded,
+			persisted:     persisted,
+			logMessage: "Using provider embedded servers, " +
+				"keeping servers marked to keep from file",
+			merged: hardcodedWithKept,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := NewMockInfoer(ctrl)
+			if testCase.logMessage != "" {
+				logger.EXPECT().Info(testCase.logMessage)
+			}
+			storage := &Storage{
+				logger:        logger,
+				mergeStrategy: testCase.mergeStrategy,
+			}
+
+			merged := storage.mergeProviderServers("provider",
+				testCase.hardcoded, testCase.persisted)
+
+			assert.Equal(t, testCase.merged, merged)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// ParseServers parses the servers data given, in the format of the
+// servers.json file or, if provider is not empty, in the format of
+// the servers of this single provider. Providers whose servers have
+// a version different from the embedded servers version are discarded.
+func (s *Storage) ParseServers(data []byte, provider string) (
+	servers models.AllServers, err error) {
+	if provider != "" {
+		data, err = json.Marshal(map[string]json.RawMessage{
+			provider: data,
+		})
+		if err != nil {
+			return servers, fmt.Errorf("encoding provider servers: %w", err)
+		}
+	}
+
+	hardcodedVersions := make(map[string]uint16, len(s.hardcodedServers.ProviderToServers))
+	for provider, servers := range s.hardcodedServers.ProviderToServers {
+		hardcodedVersions[provider] = servers.Version
+	}
+
+	return s.extractServersFromBytes(data, hardcodedVersions)
+}
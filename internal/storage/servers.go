@@ -2,6 +2,7 @@ package storage
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants/providers"
@@ -56,6 +57,45 @@ func (s *Storage) GetServerByName(provider, name string) (
 	return server, false
 }
 
+// GetServerByHostname returns a deep copy of the server
+// for the provider given matching the hostname given.
+func (s *Storage) GetServerByHostname(provider, hostname string) (
+	server models.Server, ok bool) {
+	if provider == providers.Custom {
+		return server, false
+	}
+
+	s.mergedMutex.RLock()
+	defer s.mergedMutex.RUnlock()
+
+	serversObject := s.getMergedServersObject(provider)
+	for _, server := range serversObject.Servers {
+		if strings.EqualFold(server.Hostname, hostname) {
+			return copyServer(server), true
+		}
+	}
+
+	return server, false
+}
+
+// GetServers returns a deep copy of all the servers
+// for the provider given.
+func (s *Storage) GetServers(provider string) (servers []models.Server) {
+	if provider == providers.Custom {
+		return nil
+	}
+
+	s.mergedMutex.RLock()
+	defer s.mergedMutex.RUnlock()
+
+	serversObject := s.getMergedServersObject(provider)
+	servers = make([]models.Server, len(serversObject.Servers))
+	for i, server := range serversObject.Servers {
+		servers[i] = copyServer(server)
+	}
+	return servers
+}
+
 // GetServersCount returns the number of servers for the provider given.
 func (s *Storage) GetServersCount(provider string) (count int) {
 	if provider == providers.Custom {
@@ -84,6 +124,22 @@ func (s *Storage) FormatToMarkdown(provider string) (formatted string) {
 	return formatted
 }
 
+// FormatServers formats the servers for the provider given using
+// the format and columns given, and returns the resulting string.
+// See models.Servers Format method for the formats and columns accepted.
+func (s *Storage) FormatServers(provider, format string, columns []string) (
+	formatted string, err error) {
+	if provider == providers.Custom {
+		return "", nil
+	}
+
+	s.mergedMutex.RLock()
+	defer s.mergedMutex.RUnlock()
+
+	serversObject := s.getMergedServersObject(provider)
+	return serversObject.Format(provider, format, columns)
+}
+
 // GetServersCount returns the number of servers for the provider given.
 func (s *Storage) ServersAreEqual(provider string, servers []models.Server) (equal bool) {
 	if provider == providers.Custom {
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"io/fs"
 	"sync"
 
 	"github.com/qdm12/gluetun/internal/models"
@@ -15,16 +16,28 @@ type Storage struct {
 	hardcodedServers models.AllServers
 	logger           Infoer
 	filepath         string
+	filePermissions  fs.FileMode
+	fileWriter       FileWriter
+	mergeStrategy    string
 }
 
 type Infoer interface {
 	Info(s string)
 }
 
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
+}
+
 // New creates a new storage and reads the servers from the
 // embedded servers file and the file on disk.
 // Passing an empty filepath disables writing servers to a file.
-func New(logger Infoer, filepath string) (storage *Storage, err error) {
+// The file is written with the file permissions given.
+// The merge strategy is one of the constants.ServersMerge* values
+// and decides, for each provider, which of the embedded servers and
+// the servers from the file are used.
+func New(logger Infoer, filepath string, filePermissions fs.FileMode,
+	fileWriter FileWriter, mergeStrategy string) (storage *Storage, err error) {
 	// A unit test prevents any error from being returned
 	// and ensures all providers are part of the servers returned.
 	hardcodedServers, _ := parseHardcodedServers()
@@ -33,6 +46,9 @@ func New(logger Infoer, filepath string) (storage *Storage, err error) {
 		hardcodedServers: hardcodedServers,
 		logger:           logger,
 		filepath:         filepath,
+		filePermissions:  filePermissions,
+		fileWriter:       fileWriter,
+		mergeStrategy:    mergeStrategy,
 	}
 
 	if err := storage.syncServers(); err != nil {
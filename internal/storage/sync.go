@@ -39,8 +39,8 @@ func (s *Storage) syncServers() (err error) {
 		s.mergedServers = s.hardcodedServers
 	} else {
 		s.logger.Info(fmt.Sprintf(
-			"merging by most recent %d hardcoded servers and %d servers read from %s",
-			hardcodedCount, countOnFile, s.filepath))
+			"merging %d hardcoded servers and %d servers read from %s with the %s strategy",
+			hardcodedCount, countOnFile, s.filepath, s.mergeStrategy))
 
 		s.mergedServers = s.mergeServers(s.hardcodedServers, serversOnFile)
 	}
@@ -0,0 +1,6 @@
+package sysctl
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
+}
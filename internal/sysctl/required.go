@@ -0,0 +1,60 @@
+package sysctl
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	vpntype "github.com/qdm12/gluetun/internal/constants/vpn"
+)
+
+// Required returns the kernel parameters needed for
+// the VPN, Wireguard server and LAN gateway settings given.
+func Required(vpnSettings settings.VPN,
+	wireguardServer settings.WireguardServer,
+	lanGateway settings.LANGateway) (sysctlSettings []Setting) {
+	portForwarding := vpnSettings.Provider.PortForwarding
+	forwardedPortDNAT := *portForwarding.Enabled &&
+		portForwarding.DNATDestination.Addr().IsValid()
+	if *wireguardServer.Enabled || *lanGateway.Enabled || forwardedPortDNAT {
+		// Packets from the Wireguard server peers or from the
+		// LAN gateway subnets are forwarded through the VPN interface,
+		// and packets received on the forwarded port are forwarded
+		// to its DNAT destination.
+		sysctlSettings = append(sysctlSettings, Setting{
+			Key: IPv4Forward, Value: "1",
+		})
+	}
+
+	if vpnSettings.Type != vpntype.Wireguard {
+		return sysctlSettings
+	}
+
+	// Packets marked by the Wireguard interface must be
+	// accepted by the reverse path filter.
+	sysctlSettings = append(sysctlSettings, Setting{
+		Key: IPv4SrcValidMark, Value: "1",
+	})
+
+	for _, address := range vpnSettings.Wireguard.Addresses {
+		if address.Addr().Is6() {
+			sysctlSettings = append(sysctlSettings, Setting{
+				Key: IPv6Disable, Value: "0",
+			})
+			break
+		}
+	}
+
+	if *vpnSettings.Secondary.Enabled {
+		// Replies to packets steered through the secondary tunnel
+		// arrive on an interface different from the one the
+		// reverse path filter expects, so use its loose mode.
+		sysctlSettings = append(sysctlSettings, Setting{
+			Key: IPv4RPFilter, Value: "2",
+		})
+		if len(vpnSettings.Secondary.SourceNetworks) > 0 {
+			sysctlSettings = append(sysctlSettings, Setting{
+				Key: IPv4Forward, Value: "1",
+			})
+		}
+	}
+
+	return sysctlSettings
+}
@@ -0,0 +1,137 @@
+// Package sysctl manages kernel parameters through /proc/sys,
+// recording their original values so they can be restored.
+package sysctl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Kernel parameter keys used by the program.
+const (
+	IPv4Forward      = "net.ipv4.ip_forward"
+	IPv4RPFilter     = "net.ipv4.conf.all.rp_filter"
+	IPv4SrcValidMark = "net.ipv4.conf.all.src_valid_mark"
+	IPv6Disable      = "net.ipv6.conf.all.disable_ipv6"
+)
+
+// Setting is a kernel parameter key and value,
+// for example net.ipv4.ip_forward and 1.
+type Setting struct {
+	Key   string
+	Value string
+}
+
+func (s Setting) String() string {
+	return s.Key + "=" + s.Value
+}
+
+// Sysctl applies kernel parameters and restores their
+// original values.
+type Sysctl struct {
+	procSysPath string
+	logger      Logger
+	originals   []Setting
+	mutex       sync.Mutex
+	// Mock functions
+	readFile  func(path string) ([]byte, error)
+	writeFile func(path string, data []byte, perm os.FileMode) error
+}
+
+// New creates a kernel parameters manager.
+func New(logger Logger) *Sysctl {
+	return &Sysctl{
+		procSysPath: "/proc/sys",
+		logger:      logger,
+		readFile:    os.ReadFile,
+		writeFile:   os.WriteFile,
+	}
+}
+
+// Get returns the current value of the kernel parameter key.
+func (s *Sysctl) Get(key string) (value string, err error) {
+	data, err := s.readFile(s.keyToPath(key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Apply sets each setting given whose value differs from the
+// current kernel value, recording the original value so it can
+// be restored with Restore. If a setting cannot be written because
+// /proc/sys is read-only, a warning with the remediation is logged
+// and the remaining settings are still applied.
+func (s *Sysctl) Apply(settings []Setting) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, setting := range settings {
+		current, err := s.Get(setting.Key)
+		if errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("cannot set " + setting.String() +
+				" since it is not supported by the kernel")
+			continue
+		} else if err != nil {
+			return fmt.Errorf("getting %s: %w", setting.Key, err)
+		} else if current == setting.Value {
+			continue
+		}
+
+		err = s.set(setting.Key, setting.Value)
+		if isReadOnly(err) {
+			s.logger.Warn("cannot set " + setting.String() + " (current value " +
+				current + ") since /proc/sys is read-only: " + err.Error() +
+				"; set it when running the container with --sysctl " + setting.String())
+			continue
+		} else if err != nil {
+			return fmt.Errorf("setting %s: %w", setting, err)
+		}
+
+		s.logger.Info("set " + setting.String() + " (previously " + current + ")")
+		s.originals = append(s.originals, Setting{Key: setting.Key, Value: current})
+	}
+
+	return nil
+}
+
+// Restore restores the original values of the kernel parameters
+// changed with Apply, in the reverse order they were applied.
+func (s *Sysctl) Restore() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var errs []error
+	for i := len(s.originals) - 1; i >= 0; i-- {
+		original := s.originals[i]
+		err = s.set(original.Key, original.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", original, err))
+			continue
+		}
+		s.logger.Info("restored " + original.String())
+	}
+	s.originals = nil
+
+	return errors.Join(errs...)
+}
+
+func (s *Sysctl) set(key, value string) (err error) {
+	const perm = 0644
+	return s.writeFile(s.keyToPath(key), []byte(value), perm)
+}
+
+func (s *Sysctl) keyToPath(key string) (path string) {
+	return filepath.Join(s.procSysPath, strings.ReplaceAll(key, ".", "/"))
+}
+
+func isReadOnly(err error) bool {
+	return errors.Is(err, syscall.EROFS) ||
+		errors.Is(err, syscall.EACCES) ||
+		errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,89 @@
+package sysctl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLogger struct {
+	infos    []string
+	warnings []string
+}
+
+func (l *testLogger) Info(s string) { l.infos = append(l.infos, s) }
+func (l *testLogger) Warn(s string) { l.warnings = append(l.warnings, s) }
+
+func Test_Sysctl(t *testing.T) {
+	t.Parallel()
+
+	procSysPath := t.TempDir()
+	writeKey := func(key, value string) {
+		path := filepath.Join(procSysPath, strings.ReplaceAll(key, ".", "/"))
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		require.NoError(t, err)
+		err = os.WriteFile(path, []byte(value+"\n"), 0644)
+		require.NoError(t, err)
+	}
+	writeKey(IPv4Forward, "1")
+	writeKey(IPv4SrcValidMark, "0")
+
+	logger := &testLogger{}
+	sysctl := New(logger)
+	sysctl.procSysPath = procSysPath
+
+	err := sysctl.Apply([]Setting{
+		{Key: IPv4Forward, Value: "1"},
+		{Key: IPv4SrcValidMark, Value: "1"},
+		{Key: IPv6Disable, Value: "0"},
+	})
+	require.NoError(t, err)
+
+	value, err := sysctl.Get(IPv4SrcValidMark)
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+	assert.Equal(t, []string{"set net.ipv4.conf.all.src_valid_mark=1 (previously 0)"}, logger.infos)
+	assert.Equal(t, []string{"cannot set net.ipv6.conf.all.disable_ipv6=0 " +
+		"since it is not supported by the kernel"}, logger.warnings)
+
+	err = sysctl.Restore()
+	require.NoError(t, err)
+	value, err = sysctl.Get(IPv4SrcValidMark)
+	require.NoError(t, err)
+	assert.Equal(t, "0", value)
+	value, err = sysctl.Get(IPv4Forward)
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+}
+
+func Test_Sysctl_Apply_readOnly(t *testing.T) {
+	t.Parallel()
+
+	logger := &testLogger{}
+	sysctl := New(logger)
+	sysctl.readFile = func(path string) ([]byte, error) {
+		assert.Equal(t, "/proc/sys/net/ipv4/ip_forward", path)
+		return []byte("0\n"), nil
+	}
+	sysctl.writeFile = func(path string, data []byte, perm os.FileMode) error {
+		return &os.PathError{Op: "open", Path: path, Err: syscall.EROFS}
+	}
+
+	err := sysctl.Apply([]Setting{{Key: IPv4Forward, Value: "1"}})
+	require.NoError(t, err)
+
+	assert.Empty(t, logger.infos)
+	assert.Equal(t, []string{"cannot set net.ipv4.ip_forward=1 (current value 0) " +
+		"since /proc/sys is read-only: " +
+		"open /proc/sys/net/ipv4/ip_forward: read-only file system; " +
+		"set it when running the container with --sysctl net.ipv4.ip_forward=1"},
+		logger.warnings)
+
+	err = sysctl.Restore()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,52 @@
+package syslog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//nolint:gochecknoglobals
+var facilityNameToCode = map[string]uint8{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// FacilityNames returns the sorted list of syslog facility names
+// which can be used.
+func FacilityNames() (names []string) {
+	names = make([]string, 0, len(facilityNameToCode))
+	for name := range facilityNameToCode {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var ErrFacilityNotValid = errors.New("facility is not valid")
+
+func parseFacility(name string) (code uint8, err error) {
+	code, ok := facilityNameToCode[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrFacilityNotValid, name)
+	}
+	return code, nil
+}
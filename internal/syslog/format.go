@@ -0,0 +1,84 @@
+package syslog
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severities as defined in RFC5424 section 6.2.1.
+const (
+	severityError   uint8 = 3
+	severityWarning uint8 = 4
+	severityInfo    uint8 = 6
+	severityDebug   uint8 = 7
+)
+
+var regexANSIColor = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// parseLogLine parses a line written by the qdm12/log logger,
+// in the format `[time ]LEVEL [[component] ]message`, and returns
+// its time, severity and message. If the line cannot be parsed,
+// the severity defaults to info, the time to the zero time and
+// the message to the whole line.
+func parseLogLine(line string) (logTime time.Time,
+	severity uint8, message string) {
+	line = regexANSIColor.ReplaceAllString(line, "")
+	line = strings.TrimRight(line, "\r\n")
+	message = line
+	severity = severityInfo
+
+	first, rest, found := strings.Cut(line, " ")
+	if found {
+		parsedTime, err := time.Parse(time.RFC3339, first)
+		if err == nil {
+			logTime = parsedTime
+			line = rest
+		}
+	}
+
+	level, rest, found := strings.Cut(line, " ")
+	if !found {
+		return logTime, severity, message
+	}
+
+	switch level {
+	case "ERROR":
+		severity = severityError
+	case "WARN":
+		severity = severityWarning
+	case "INFO":
+		severity = severityInfo
+	case "DEBUG":
+		severity = severityDebug
+	default:
+		return logTime, severity, message
+	}
+
+	return logTime, severity, rest
+}
+
+// formatRFC5424 formats a syslog message as defined in RFC5424.
+// No message ID nor structured data is set.
+func formatRFC5424(facility, severity uint8, timestamp time.Time,
+	hostname, appName string, processID int, message string) string {
+	const version = "1"
+	const nilValue = "-"
+	priority := uint(facility)*8 + uint(severity) //nolint:gomnd
+	return "<" + strconv.FormatUint(uint64(priority), 10) + ">" + version + " " +
+		timestamp.Format("2006-01-02T15:04:05.000000Z07:00") + " " +
+		orNil(hostname) + " " +
+		orNil(appName) + " " +
+		strconv.Itoa(processID) + " " +
+		nilValue + " " + // message ID
+		nilValue + " " + // structured data
+		message
+}
+
+func orNil(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
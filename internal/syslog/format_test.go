@@ -0,0 +1,65 @@
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseLogLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line     string
+		logTime  time.Time
+		severity uint8
+		message  string
+	}{
+		"empty line": {
+			severity: severityInfo,
+		},
+		"unknown format": {
+			line:     "some message\n",
+			severity: severityInfo,
+			message:  "some message",
+		},
+		"full line": {
+			line:     "2023-05-01T10:11:12Z WARN [vpn] some message\n",
+			logTime:  time.Date(2023, 5, 1, 10, 11, 12, 0, time.UTC),
+			severity: severityWarning,
+			message:  "[vpn] some message",
+		},
+		"colored line without time": {
+			line:     "\x1b[91mERROR\x1b[0m some error\n",
+			severity: severityError,
+			message:  "some error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logTime, severity, message := parseLogLine(testCase.line)
+
+			assert.Equal(t, testCase.logTime, logTime)
+			assert.Equal(t, testCase.severity, severity)
+			assert.Equal(t, testCase.message, message)
+		})
+	}
+}
+
+func Test_formatRFC5424(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2023, 5, 1, 10, 11, 12, 13000, time.UTC)
+	const facilityDaemon = 3
+
+	message := formatRFC5424(facilityDaemon, severityWarning, timestamp,
+		"host", "gluetun", 1, "[vpn] some message")
+
+	const expected = "<28>1 2023-05-01T10:11:12.000013Z host gluetun 1 - - [vpn] some message"
+	assert.Equal(t, expected, message)
+}
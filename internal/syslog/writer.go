@@ -0,0 +1,204 @@
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Writer is an io.Writer sending each line written to it
+// to a remote syslog server, formatted as defined in RFC5424.
+// Lines are queued and sent in the background, such that
+// writing never blocks on the syslog server.
+// It is safe for concurrent use.
+type Writer struct {
+	// Fixed parameters
+	network  string
+	address  string
+	facility uint8
+	hostname string
+	appName  string
+	pid      int
+	// Internal state
+	messages  chan string
+	conn      net.Conn // only accessed by the run goroutine
+	retryAt   time.Time
+	cancel    context.CancelFunc
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	// Mock functions
+	timeNow func() time.Time
+}
+
+var (
+	ErrProtocolNotValid = errors.New("protocol is not valid")
+	ErrQueueFull        = errors.New("queue of messages to send is full")
+)
+
+// New creates a new syslog writer sending to the address given
+// using the protocol given, which can be 'udp', 'tcp' or 'tls'.
+// The facility must be one of the names returned by FacilityNames.
+// The connection is established in the background on the first
+// write, and messages are dropped while the syslog server cannot
+// be reached.
+func New(protocol, address, facility string) (writer *Writer, err error) {
+	switch protocol {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrProtocolNotValid, protocol)
+	}
+
+	facilityCode, err := parseFacility(facility)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("getting hostname: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	const queueSize = 1000
+	writer = &Writer{
+		network:  protocol,
+		address:  address,
+		facility: facilityCode,
+		hostname: hostname,
+		appName:  "gluetun",
+		pid:      os.Getpid(),
+		messages: make(chan string, queueSize),
+		cancel:   cancel,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		timeNow:  time.Now,
+	}
+	go writer.run(ctx)
+	return writer, nil
+}
+
+// Write parses the log line given and queues it to be sent to
+// the remote syslog server. It returns an error without blocking
+// if the queue is full, for example if the server is unreachable.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	logTime, severity, message := parseLogLine(string(p))
+	if logTime.IsZero() {
+		logTime = w.timeNow()
+	}
+	message = formatRFC5424(w.facility, severity, logTime,
+		w.hostname, w.appName, w.pid, message)
+
+	if w.network != "udp" {
+		// Octet counting framing as defined in RFC5425 and RFC6587.
+		message = strconv.Itoa(len(message)) + " " + message
+	}
+
+	select {
+	case w.messages <- message:
+		return len(p), nil
+	default:
+		return 0, ErrQueueFull
+	}
+}
+
+// run sends the queued messages until the writer is closed,
+// at which point it sends the messages left in the queue.
+func (w *Writer) run(ctx context.Context) {
+	defer close(w.done)
+	for {
+		select {
+		case message := <-w.messages:
+			w.send(ctx, message)
+		case <-w.stop:
+			for {
+				select {
+				case message := <-w.messages:
+					w.send(ctx, message)
+				default:
+					if w.conn != nil {
+						w.closeConn()
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// send sends the message to the syslog server, connecting to it
+// if needed. On failure, the connection is closed and the message
+// is dropped, and no connection is attempted for a few seconds to
+// avoid dialing for every message while the server is unreachable.
+func (w *Writer) send(ctx context.Context, message string) {
+	const timeout = 3 * time.Second
+	if w.conn == nil {
+		if w.timeNow().Before(w.retryAt) {
+			return
+		}
+		conn, err := w.dial(ctx, timeout)
+		if err != nil {
+			const retryPeriod = 5 * time.Second
+			w.retryAt = w.timeNow().Add(retryPeriod)
+			return
+		}
+		w.conn = conn
+	}
+
+	err := w.conn.SetWriteDeadline(w.timeNow().Add(timeout))
+	if err != nil {
+		w.closeConn()
+		return
+	}
+
+	_, err = w.conn.Write([]byte(message))
+	if err != nil {
+		w.closeConn()
+	}
+}
+
+func (w *Writer) dial(ctx context.Context, timeout time.Duration) (
+	conn net.Conn, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if w.network != "tls" {
+		dialer := &net.Dialer{}
+		return dialer.DialContext(ctx, w.network, w.address)
+	}
+
+	host, _, err := net.SplitHostPort(w.address)
+	if err != nil {
+		return nil, fmt.Errorf("splitting host and port: %w", err)
+	}
+
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	return dialer.DialContext(ctx, "tcp", w.address)
+}
+
+func (w *Writer) closeConn() {
+	_ = w.conn.Close()
+	w.conn = nil
+}
+
+// Close sends the messages left in the queue, without attempting
+// any new connection, and closes the connection to the syslog server.
+func (w *Writer) Close() (err error) {
+	w.closeOnce.Do(func() {
+		w.cancel()
+		close(w.stop)
+	})
+	<-w.done
+	return nil
+}
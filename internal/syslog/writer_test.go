@@ -0,0 +1,113 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Writer_TCP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string)
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer connection.Close()
+		reader := bufio.NewReader(connection)
+		for {
+			// Read the octet counting length prefix
+			lengthString, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			length, err := strconv.Atoi(strings.TrimSpace(lengthString))
+			if err != nil {
+				return
+			}
+			message := make([]byte, length)
+			_, err = io.ReadFull(reader, message)
+			if err != nil {
+				return
+			}
+			received <- string(message)
+		}
+	}()
+
+	writer, err := New("tcp", listener.Addr().String(), "user")
+	require.NoError(t, err)
+	writer.hostname = "host"
+	writer.pid = 1
+
+	_, err = writer.Write([]byte("2023-05-01T10:11:12Z WARN [vpn] some message\n"))
+	require.NoError(t, err)
+
+	select {
+	case message := <-received:
+		assert.True(t, strings.HasSuffix(message, "[vpn] some message"), message)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+
+	err = writer.Close()
+	require.NoError(t, err)
+}
+
+func Test_Writer_unresponsiveServer(t *testing.T) {
+	t.Parallel()
+
+	// The listener accepts TCP connections but never completes
+	// the TLS handshake, blocking the writer dial.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		var connections []net.Conn
+		defer func() {
+			for _, connection := range connections {
+				_ = connection.Close()
+			}
+		}()
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			connections = append(connections, connection)
+		}
+	}()
+
+	writer, err := New("tls", listener.Addr().String(), "user")
+	require.NoError(t, err)
+
+	start := time.Now()
+	const queueSize = 1000
+	for i := 0; i < queueSize; i++ {
+		_, err = writer.Write([]byte("some message\n"))
+		require.NoError(t, err)
+	}
+	// Sending the first message may already be in progress,
+	// so fill the queue up to one extra message.
+	_, _ = writer.Write([]byte("some message\n"))
+	_, err = writer.Write([]byte("some message\n"))
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Less(t, time.Since(start), time.Second)
+
+	start = time.Now()
+	err = writer.Close()
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,136 @@
+// Package system contains helpers to interact with the
+// operating system, such as writing files owned by a
+// given user and group.
+package system
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileWriter writes files and creates directories,
+// setting their ownership to the user and group ids given.
+type FileWriter struct {
+	uid int
+	gid int
+}
+
+// NewFileWriter creates a file writer setting the ownership
+// of files and directories it creates to the uid and gid given.
+func NewFileWriter(uid, gid int) *FileWriter {
+	return &FileWriter{
+		uid: uid,
+		gid: gid,
+	}
+}
+
+// WriteFile writes the data to the file at the given path,
+// creating its parent directories if needed, and sets the
+// ownership of the file to the writer user and group ids.
+// The permissions of the file are set to perm, regardless
+// of the process umask and of the existing file permissions.
+// Parent directories created are given the permissions of
+// the file with the execute bit set where the read bit is set.
+func (w *FileWriter) WriteFile(path string, data []byte, perm fs.FileMode) (err error) {
+	err = w.MkdirAll(filepath.Dir(path), dirPermFromFilePerm(perm))
+	if err != nil {
+		return fmt.Errorf("creating parent directory: %w", err)
+	}
+
+	err = os.WriteFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+
+	return w.setOwnershipAndPermissions(path, perm)
+}
+
+// AppendFile appends the data to the file at the given path,
+// creating it and its parent directories if needed, and sets
+// the ownership of a newly created file to the writer user and
+// group ids.
+func (w *FileWriter) AppendFile(path string, data []byte, perm fs.FileMode) (err error) {
+	_, err = os.Stat(path)
+	created := errors.Is(err, fs.ErrNotExist)
+	if created {
+		err = w.MkdirAll(filepath.Dir(path), dirPermFromFilePerm(perm))
+		if err != nil {
+			return fmt.Errorf("creating parent directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	if created {
+		return w.setOwnershipAndPermissions(path, perm)
+	}
+	return nil
+}
+
+// MkdirAll creates the directory at the given path together
+// with any missing parent directory, and sets the ownership of
+// each directory created to the writer user and group ids.
+// Existing directories are left untouched.
+func (w *FileWriter) MkdirAll(path string, perm fs.FileMode) (err error) {
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w: %s", fs.ErrExist, path)
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	parent := filepath.Dir(path)
+	if parent != path {
+		err = w.MkdirAll(parent, perm)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Mkdir(path, perm)
+	if err != nil {
+		return err
+	}
+
+	return w.setOwnershipAndPermissions(path, perm)
+}
+
+func (w *FileWriter) setOwnershipAndPermissions(path string, perm fs.FileMode) (err error) {
+	err = os.Chown(path, w.uid, w.gid)
+	if err != nil {
+		return fmt.Errorf("setting ownership: %w", err)
+	}
+
+	err = os.Chmod(path, perm)
+	if err != nil {
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	return nil
+}
+
+func dirPermFromFilePerm(filePerm fs.FileMode) (dirPerm fs.FileMode) {
+	const readBits, executeShift = 0444, 2
+	return filePerm | (filePerm&readBits)>>executeShift
+}
@@ -0,0 +1,57 @@
+package system
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FileWriter(t *testing.T) {
+	t.Parallel()
+
+	uid, gid := os.Getuid(), os.Getgid()
+	writer := NewFileWriter(uid, gid)
+	root := t.TempDir()
+
+	path := filepath.Join(root, "a", "b", "file")
+	err := writer.WriteFile(path, []byte("data"), 0600)
+	require.NoError(t, err)
+	assertOwnership(t, path, uid, gid, 0600)
+	assertOwnership(t, filepath.Join(root, "a"), uid, gid, fs.ModeDir|0700)
+	assertOwnership(t, filepath.Join(root, "a", "b"), uid, gid, fs.ModeDir|0700)
+
+	err = writer.AppendFile(path, []byte("more"), 0644)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "datamore", string(data))
+	assertOwnership(t, path, uid, gid, 0600)
+
+	err = writer.MkdirAll(path, 0755)
+	assert.ErrorIs(t, err, fs.ErrExist)
+}
+
+func assertOwnership(t *testing.T, path string, uid, gid int,
+	mode fs.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, mode, info.Mode())
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	require.True(t, ok)
+	assert.Equal(t, uint32(uid), stat.Uid)
+	assert.Equal(t, uint32(gid), stat.Gid)
+}
+
+func Test_dirPermFromFilePerm(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, fs.FileMode(0700), dirPermFromFilePerm(0600))
+	assert.Equal(t, fs.FileMode(0755), dirPermFromFilePerm(0644))
+	assert.Equal(t, fs.FileMode(0750), dirPermFromFilePerm(0640))
+}
@@ -0,0 +1,56 @@
+package system
+
+// LifecycleRequest is a request to change the program lifecycle.
+type LifecycleRequest uint8
+
+const (
+	// RequestStop requests the program to shut down and exit.
+	RequestStop LifecycleRequest = iota
+	// RequestRestart requests the program to shut down and
+	// start again in the same process.
+	RequestRestart
+)
+
+func (r LifecycleRequest) String() string {
+	if r == RequestRestart {
+		return "restart"
+	}
+	return "stop"
+}
+
+// Lifecycle receives requests to stop or restart the program,
+// which the main function waits for to shut down cleanly.
+type Lifecycle struct {
+	requests chan LifecycleRequest
+}
+
+// NewLifecycle creates a lifecycle accepting a single request.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{
+		requests: make(chan LifecycleRequest, 1),
+	}
+}
+
+// Stop requests the program to shut down and exit.
+func (l *Lifecycle) Stop() (outcome string) {
+	return l.request(RequestStop)
+}
+
+// Restart requests the program to shut down and start again.
+func (l *Lifecycle) Restart() (outcome string) {
+	return l.request(RequestRestart)
+}
+
+func (l *Lifecycle) request(request LifecycleRequest) (outcome string) {
+	select {
+	case l.requests <- request:
+		return request.String() + " requested"
+	default:
+		return "shutdown already requested"
+	}
+}
+
+// Requests returns the channel the lifecycle request is sent on.
+func (l *Lifecycle) Requests() <-chan LifecycleRequest {
+	return l.requests
+}
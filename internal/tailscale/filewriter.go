@@ -0,0 +1,7 @@
+package tailscale
+
+import "io/fs"
+
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
+}
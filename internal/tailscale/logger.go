@@ -0,0 +1,7 @@
+package tailscale
+
+type Logger interface {
+	Debug(s string)
+	Info(s string)
+	Error(s string)
+}
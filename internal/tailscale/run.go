@@ -0,0 +1,150 @@
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+var (
+	ErrDaemonStart = errors.New("cannot start tailscaled")
+	ErrDaemonExit  = errors.New("tailscaled exited")
+	ErrUp          = errors.New("cannot bring Tailscale up")
+)
+
+func (t *Tailscale) Run(ctx context.Context, waitError chan<- error, ready chan<- struct{}) {
+	daemonCtx, daemonCancel := context.WithCancel(context.Background())
+	defer daemonCancel()
+
+	cmd := t.daemonCommand(daemonCtx)
+	stdoutLines, stderrLines, daemonWaitError, err := t.cmder.Start(cmd)
+	if err != nil {
+		waitError <- fmt.Errorf("%w: %s", ErrDaemonStart, err)
+		return
+	}
+
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	streamDone := make(chan struct{})
+	go streamLines(streamCtx, streamDone, t.logger, stdoutLines, stderrLines)
+	stopDaemon := func() {
+		daemonCancel()
+		<-daemonWaitError
+		close(daemonWaitError)
+		streamCancel()
+		<-streamDone
+	}
+
+	err = t.up(ctx, daemonWaitError)
+	if err != nil {
+		if errors.Is(err, ErrDaemonExit) {
+			// daemon wait error already received
+			close(daemonWaitError)
+			streamCancel()
+			<-streamDone
+		} else {
+			stopDaemon()
+		}
+		waitError <- err
+		return
+	}
+
+	t.logger.Info("Tailscale is up using exit node " + t.settings.ExitNode)
+	select {
+	case ready <- struct{}{}:
+	case <-ctx.Done():
+	}
+
+	select {
+	case <-ctx.Done():
+		stopDaemon()
+		waitError <- ctx.Err()
+	case err := <-daemonWaitError:
+		close(daemonWaitError)
+		streamCancel()
+		<-streamDone
+		waitError <- fmt.Errorf("%w: %s", ErrDaemonExit, err)
+	}
+}
+
+func (t *Tailscale) daemonCommand(ctx context.Context) (cmd *exec.Cmd) {
+	cmd = exec.CommandContext(ctx, "tailscaled",
+		"--tun="+t.settings.Interface,
+		"--statedir="+t.stateDirectory,
+		"--socket="+t.socketPath,
+		"--no-logs-no-support",
+	)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+		// The daemon runs with its own group so the firewall
+		// only lets its own marked traffic bypass the tunnel.
+		Credential: &syscall.Credential{
+			Uid:         uint32(os.Getuid()),
+			Gid:         constants.TailscaleGroupID,
+			NoSetGroups: true,
+		},
+	}
+	// Interrupt the daemon so it removes its routes and rules.
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	const waitDelay = 2 * time.Second
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// up waits for the daemon socket to be available and then
+// brings the node up, using the exit node for all traffic.
+func (t *Tailscale) up(ctx context.Context, daemonWaitError <-chan error) (err error) {
+	const retryPeriod = 200 * time.Millisecond
+	ticker := time.NewTicker(retryPeriod)
+	defer ticker.Stop()
+	for {
+		_, err = os.Stat(t.socketPath)
+		if err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-daemonWaitError:
+			return fmt.Errorf("%w: %s", ErrDaemonExit, err)
+		case <-ticker.C:
+		}
+	}
+
+	const authKeyPerms = 0600
+	err = t.fileWriter.WriteFile(t.authKeyPath, []byte(*t.settings.AuthKey), authKeyPerms)
+	if err != nil {
+		return fmt.Errorf("writing auth key file: %w", err)
+	}
+	defer func() {
+		removeErr := os.Remove(t.authKeyPath)
+		if removeErr != nil {
+			t.logger.Error("cannot remove auth key file: " + removeErr.Error())
+		}
+	}()
+
+	args := []string{"--socket=" + t.socketPath, "up", "--reset",
+		"--auth-key=file:" + t.authKeyPath,
+		"--login-server=" + t.settings.ControlURL,
+		"--exit-node=" + t.settings.ExitNode,
+		// gluetun DNS and firewall are used instead
+		"--accept-dns=false",
+		"--netfilter-mode=off",
+	}
+	if t.settings.Hostname != "" {
+		args = append(args, "--hostname="+t.settings.Hostname)
+	}
+	cmd := exec.CommandContext(ctx, "tailscale", args...)
+	output, err := t.cmder.Run(cmd)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrUp, err, output)
+	}
+	return nil
+}
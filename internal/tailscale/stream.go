@@ -0,0 +1,26 @@
+package tailscale
+
+import (
+	"context"
+)
+
+func streamLines(ctx context.Context, done chan<- struct{},
+	logger Logger, stdout, stderr chan string) {
+	defer close(done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Context should only be canceled after stdout and stderr are done
+			// being written to.
+			close(stdout)
+			close(stderr)
+			return
+		case line := <-stdout:
+			logger.Debug(line)
+		case line := <-stderr:
+			// tailscaled logs everything to stderr
+			logger.Debug(line)
+		}
+	}
+}
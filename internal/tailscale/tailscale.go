@@ -0,0 +1,39 @@
+// Package tailscale runs the Tailscale daemon to route
+// all traffic through a Tailscale exit node.
+package tailscale
+
+import (
+	"path/filepath"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/golibs/command"
+)
+
+type Tailscale struct {
+	settings settings.Tailscale
+	// stateDirectory is the directory where the Tailscale
+	// node state is persisted, so the node does not need to
+	// be registered again on a restart.
+	stateDirectory string
+	socketPath     string
+	// authKeyPath is the file holding the auth key while
+	// the node is brought up, to keep it out of the
+	// command line arguments.
+	authKeyPath string
+	cmder       command.RunStarter
+	fileWriter  FileWriter
+	logger      Logger
+}
+
+func New(settings settings.Tailscale, dataDirectory, runtimeDirectory string,
+	cmder command.RunStarter, fileWriter FileWriter, logger Logger) *Tailscale {
+	return &Tailscale{
+		settings:       settings,
+		stateDirectory: filepath.Join(dataDirectory, "tailscale"),
+		socketPath:     filepath.Join(runtimeDirectory, "tailscaled.sock"),
+		authKeyPath:    filepath.Join(runtimeDirectory, "tailscale-authkey"),
+		cmder:          cmder,
+		fileWriter:     fileWriter,
+		logger:         logger,
+	}
+}
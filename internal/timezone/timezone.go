@@ -0,0 +1,76 @@
+// Package timezone holds the timezone used for the times shown
+// to the user, such as log timestamps and schedule windows.
+// Unlike time.Local, its location can be changed safely at runtime,
+// for example once the location of the VPN server is known.
+package timezone
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Timezone holds a time location which can be changed at runtime.
+// It is safe for concurrent use.
+type Timezone struct {
+	location *time.Location
+	mutex    sync.RWMutex
+	timeNow  func() time.Time
+}
+
+func New(location *time.Location) *Timezone {
+	return &Timezone{
+		location: location,
+		timeNow:  time.Now,
+	}
+}
+
+// Location returns the current time location.
+func (t *Timezone) Location() *time.Location {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.location
+}
+
+// SetLocation sets the time location.
+func (t *Timezone) SetLocation(location *time.Location) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.location = location
+}
+
+// Now returns the current time in the current time location.
+func (t *Timezone) Now() time.Time {
+	return t.timeNow().In(t.Location())
+}
+
+// Wrap returns an io.Writer prefixing each data written with
+// the current time in the current time location, in the RFC3339
+// format, before writing it to the writer given. It is meant to
+// be used for log writers, with logger timestamps disabled.
+func (t *Timezone) Wrap(writer io.Writer) *Writer {
+	return &Writer{
+		timezone: t,
+		writer:   writer,
+	}
+}
+
+// Writer is an io.Writer prefixing data with a timestamp
+// before writing it to its underlying writer.
+type Writer struct {
+	timezone *Timezone
+	writer   io.Writer
+}
+
+// Write writes the data given prefixed with a timestamp to the
+// underlying writer. It returns the length of the data given
+// and no error if the write succeeds, to respect the io.Writer
+// interface.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	timestamp := w.timezone.Now().Format(time.RFC3339)
+	_, err = io.WriteString(w.writer, timestamp+" "+string(p))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
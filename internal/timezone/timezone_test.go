@@ -0,0 +1,37 @@
+package timezone
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Timezone(t *testing.T) {
+	t.Parallel()
+
+	timezone := New(time.UTC)
+	timezone.timeNow = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	writer := timezone.Wrap(buffer)
+
+	const line = "INFO message\n"
+	n, err := writer.Write([]byte(line))
+	require.NoError(t, err)
+	assert.Equal(t, len(line), n)
+	assert.Equal(t, "2024-01-02T03:04:05Z INFO message\n", buffer.String())
+
+	location := time.FixedZone("UTC+2", 2*60*60) //nolint:gomnd
+	timezone.SetLocation(location)
+	assert.Equal(t, location, timezone.Location())
+
+	buffer.Reset()
+	_, err = writer.Write([]byte(line))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-02T05:04:05+02:00 INFO message\n", buffer.String())
+}
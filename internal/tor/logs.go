@@ -0,0 +1,33 @@
+package tor
+
+import "strings"
+
+type logLevel uint8
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// processLogLine removes the timestamp and severity prefix from a
+// Tor log line such as "Oct 15 12:00:00.000 [notice] Bootstrapped 5%",
+// and returns the message with its log level.
+func processLogLine(s string) (filtered string, level logLevel) {
+	prefixToLevel := map[string]logLevel{
+		"[debug] ":  levelDebug,
+		"[info] ":   levelDebug,
+		"[notice] ": levelInfo,
+		"[warn] ":   levelWarn,
+		"[err] ":    levelError,
+	}
+	for prefix, prefixLevel := range prefixToLevel {
+		i := strings.Index(s, prefix)
+		if i == -1 {
+			continue
+		}
+		return s[i+len(prefix):], prefixLevel
+	}
+	return s, levelInfo
+}
@@ -0,0 +1,53 @@
+package tor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_processLogLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s        string
+		filtered string
+		level    logLevel
+	}{
+		"empty string": {
+			level: levelInfo,
+		},
+		"no prefix": {
+			s:        "some line",
+			filtered: "some line",
+			level:    levelInfo,
+		},
+		"notice": {
+			s:        "Oct 15 12:00:00.000 [notice] Bootstrapped 100% (done): Done",
+			filtered: "Bootstrapped 100% (done): Done",
+			level:    levelInfo,
+		},
+		"warn": {
+			s:        "Oct 15 12:00:00.000 [warn] Problem bootstrapping.",
+			filtered: "Problem bootstrapping.",
+			level:    levelWarn,
+		},
+		"err": {
+			s:        "Oct 15 12:00:00.000 [err] Reading config failed",
+			filtered: "Reading config failed",
+			level:    levelError,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered, level := processLogLine(testCase.s)
+
+			assert.Equal(t, testCase.filtered, filtered)
+			assert.Equal(t, testCase.level, level)
+		})
+	}
+}
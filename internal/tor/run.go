@@ -0,0 +1,84 @@
+package tor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+var (
+	ErrStart = errors.New("cannot start tor")
+	ErrExit  = errors.New("tor exited")
+)
+
+func (t *Tor) Run(ctx context.Context, waitError chan<- error, ready chan<- struct{}) {
+	cmd := t.command(ctx)
+	stdoutLines, stderrLines, torWaitError, err := t.starter.Start(cmd)
+	if err != nil {
+		waitError <- fmt.Errorf("%w: %s", ErrStart, err)
+		return
+	}
+
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	streamDone := make(chan struct{})
+	go streamLines(streamCtx, streamDone, t.logger,
+		stdoutLines, stderrLines, ready)
+
+	select {
+	case <-ctx.Done():
+		<-torWaitError
+		close(torWaitError)
+		streamCancel()
+		<-streamDone
+		waitError <- ctx.Err()
+	case err := <-torWaitError:
+		close(torWaitError)
+		streamCancel()
+		<-streamDone
+		waitError <- fmt.Errorf("%w: %s", ErrExit, err)
+	}
+}
+
+func (t *Tor) command(ctx context.Context) (cmd *exec.Cmd) {
+	transPort := "0"
+	if t.transparent {
+		transPort = "127.0.0.1:" + strconv.Itoa(int(t.settings.TransPort))
+	}
+
+	args := []string{
+		// ignore any torrc file so only the arguments below apply
+		"-f", "/dev/null", "--ignore-missing-torrc",
+		"--DataDirectory", t.dataDirectory,
+		"--SocksPort", "127.0.0.1:" + strconv.Itoa(int(t.settings.SocksPort)),
+		"--TransPort", transPort,
+		"--Log", "notice stdout",
+	}
+	if os.Geteuid() == 0 {
+		// Tor can only switch user when running as root.
+		args = append(args, "--User", constants.TorUser)
+	}
+	if len(t.settings.ExitCountries) > 0 {
+		exitNodes := make([]string, len(t.settings.ExitCountries))
+		for i, country := range t.settings.ExitCountries {
+			exitNodes[i] = "{" + country + "}"
+		}
+		args = append(args,
+			"--ExitNodes", strings.Join(exitNodes, ","),
+			"--StrictNodes", "1")
+	}
+
+	cmd = exec.CommandContext(ctx, "tor", args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	const waitDelay = 2 * time.Second
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
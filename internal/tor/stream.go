@@ -0,0 +1,51 @@
+package tor
+
+import (
+	"context"
+	"strings"
+)
+
+func streamLines(ctx context.Context, done chan<- struct{},
+	logger Logger, stdout, stderr chan string,
+	ready chan<- struct{}) {
+	defer close(done)
+
+	var line string
+	readySent := false
+
+	for {
+		errLine := false
+		select {
+		case <-ctx.Done():
+			// Context should only be canceled after stdout and stderr are done
+			// being written to.
+			close(stdout)
+			close(stderr)
+			return
+		case line = <-stdout:
+		case line = <-stderr:
+			errLine = true
+		}
+		line, level := processLogLine(line)
+		if errLine {
+			level = levelError
+		}
+		switch level {
+		case levelDebug:
+			logger.Debug(line)
+		case levelInfo:
+			logger.Info(line)
+		case levelWarn:
+			logger.Warn(line)
+		case levelError:
+			logger.Error(line)
+		}
+		if !readySent && strings.HasPrefix(line, "Bootstrapped 100%") {
+			readySent = true
+			select {
+			case ready <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package tor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_streamLines_readyOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stdout, stderr := make(chan string), make(chan string)
+	ready := make(chan struct{}, 2) //nolint:gomnd
+	go streamLines(ctx, done, noopLogger{}, stdout, stderr, ready)
+
+	stdout <- "Oct 15 10:00:00.000 [notice] Bootstrapped 100% (done): Done"
+	stdout <- "Oct 15 10:05:00.000 [notice] Bootstrapped 100% (done): Done"
+	cancel()
+	<-done
+
+	assert.Len(t, ready, 1)
+}
@@ -0,0 +1,36 @@
+// Package tor runs the Tor client, either as a transparent proxy
+// to route all traffic through the Tor network, or as a SOCKS proxy
+// to connect to the OpenVPN server through the Tor network.
+package tor
+
+import (
+	"path/filepath"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/golibs/command"
+)
+
+type Tor struct {
+	settings settings.Tor
+	// transparent is true to listen on the transparent proxy port,
+	// and false to only listen on the SOCKS proxy port.
+	transparent bool
+	// dataDirectory is the directory where Tor keeps its state,
+	// such as the consensus and guard relays, so bootstrapping
+	// is faster on a restart. Tor creates it and sets its
+	// ownership to the Tor user.
+	dataDirectory string
+	starter       command.Starter
+	logger        Logger
+}
+
+func New(settings settings.Tor, transparent bool, dataDirectory string,
+	starter command.Starter, logger Logger) *Tor {
+	return &Tor{
+		settings:      settings,
+		transparent:   transparent,
+		dataDirectory: filepath.Join(dataDirectory, "tor"),
+		starter:       starter,
+		logger:        logger,
+	}
+}
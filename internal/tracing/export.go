@@ -0,0 +1,168 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrHTTPStatusCodeNotOK = errors.New("HTTP status code is not OK")
+
+func (t *Tracer) export(ctx context.Context, spans []*Span) (err error) {
+	request := makeExportRequest(t.serviceName, spans)
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encoding request body: %w", err)
+	}
+
+	url := strings.TrimSuffix(t.endpoint, "/") + "/v1/traces"
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	response, err := t.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		return fmt.Errorf("%w: %d %s: %s", ErrHTTPStatusCodeNotOK,
+			response.StatusCode, response.Status, string(responseBody))
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("closing response body: %w", err)
+	}
+
+	return nil
+}
+
+// The types below follow the OTLP JSON encoding of the
+// ExportTraceServiceRequest protobuf message.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []jsonSpan `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type jsonSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+func makeExportRequest(serviceName string, spans []*Span) (request exportRequest) {
+	jsonSpans := make([]jsonSpan, len(spans))
+	for i, span := range spans {
+		jsonSpans[i] = span.toJSON()
+	}
+
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{
+					{Key: "service.name", Value: anyValue{StringValue: serviceName}},
+				},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "github.com/qdm12/gluetun"},
+				Spans: jsonSpans,
+			}},
+		}},
+	}
+}
+
+func (s *Span) toJSON() (span jsonSpan) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	span = jsonSpan{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Status:            status{Code: statusCodeOK},
+	}
+
+	if s.parentSpanID != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parentSpanID[:])
+	}
+
+	if s.err != nil {
+		span.Status = status{
+			Code:    statusCodeError,
+			Message: s.err.Error(),
+		}
+	}
+
+	keys := make([]string, 0, len(s.attributes))
+	for key := range s.attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		span.Attributes = append(span.Attributes, keyValue{
+			Key:   key,
+			Value: anyValue{StringValue: s.attributes[key]},
+		})
+	}
+
+	return span
+}
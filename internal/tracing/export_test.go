@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeExportRequest(t *testing.T) {
+	t.Parallel()
+
+	tracer := &Tracer{
+		enabled: true,
+		timeNow: func() time.Time { return time.Unix(1, 0) },
+	}
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	child.SetAttribute("key", "value")
+	child.End(errors.New("test error"))
+	child.End(nil) // ignored
+	parent.End(nil)
+
+	spans := tracer.popSpans()
+	require.Len(t, spans, 2)
+
+	request := makeExportRequest("gluetun", spans)
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	var decoded exportRequest
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+
+	jsonSpans := decoded.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, jsonSpans, 2)
+	childSpan, parentSpan := jsonSpans[0], jsonSpans[1]
+
+	assert.Equal(t, "child", childSpan.Name)
+	assert.Equal(t, parentSpan.TraceID, childSpan.TraceID)
+	assert.Equal(t, parentSpan.SpanID, childSpan.ParentSpanID)
+	assert.Equal(t, status{Code: statusCodeError, Message: "test error"}, childSpan.Status)
+	assert.Equal(t, []keyValue{{Key: "key", Value: anyValue{StringValue: "value"}}},
+		childSpan.Attributes)
+	assert.Equal(t, "1000000000", childSpan.StartTimeUnixNano)
+
+	assert.Empty(t, parentSpan.ParentSpanID)
+	assert.Equal(t, status{Code: statusCodeOK}, parentSpan.Status)
+}
+
+func Test_Start_noParent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	childCtx, span := Start(ctx, "name")
+
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, childCtx)
+	span.SetAttribute("key", "value") // no-op on nil span
+	span.End(nil)
+}
@@ -0,0 +1,5 @@
+package tracing
+
+type Logger interface {
+	Warn(s string)
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Span is a timed operation part of a trace.
+// A nil *Span is valid and all its methods are no-op,
+// which is the case when tracing is disabled.
+type Span struct {
+	tracer       *Tracer
+	traceID      [16]byte
+	spanID       [8]byte
+	parentSpanID [8]byte
+	name         string
+	start        time.Time
+	end          time.Time
+	attributes   map[string]string
+	err          error
+	ended        bool
+	mutex        sync.Mutex
+}
+
+// SetAttribute sets a string attribute on the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attributes[key] = value
+}
+
+// End ends the span, marking it as failed if err is not nil.
+// Only the first call to End is taken into account, so it is
+// safe to call it multiple times on different code paths.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = s.tracer.timeNow()
+	s.err = err
+	s.mutex.Unlock()
+
+	s.tracer.record(s)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of the context given
+// holding the span given, such that spans started with
+// this context are children of the span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+func spanFromContext(ctx context.Context) (span *Span) {
+	span, _ = ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// Start starts a child span of the span contained in the
+// context given. If the context contains no span, a nil
+// span is returned, which is a no-op span.
+func Start(ctx context.Context, name string) (
+	childCtx context.Context, span *Span) {
+	parent := spanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name)
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// Tracer creates spans and exports them in batches to an
+// OpenTelemetry collector using OTLP over HTTP with JSON encoding.
+type Tracer struct {
+	// Fixed parameters
+	enabled     bool
+	endpoint    string
+	serviceName string
+	// Objects
+	client *http.Client
+	logger Logger
+	// Internal state
+	spans      []*Span
+	spansMutex sync.Mutex
+	// Mock functions
+	timeNow func() time.Time
+}
+
+func New(settings settings.Tracing, client *http.Client,
+	logger Logger) *Tracer {
+	return &Tracer{
+		enabled:     *settings.Endpoint != "",
+		endpoint:    *settings.Endpoint,
+		serviceName: settings.ServiceName,
+		client:      client,
+		logger:      logger,
+		timeNow:     time.Now,
+	}
+}
+
+// Start starts a new span, child of the span contained in the
+// context given if any. It returns a context containing the new span,
+// to be used to create children spans. If tracing is disabled, the
+// span returned is nil and is a no-op span.
+func (t *Tracer) Start(ctx context.Context, name string) (
+	spanCtx context.Context, span *Span) {
+	if !t.enabled {
+		return ctx, nil
+	}
+
+	span = &Span{
+		tracer:     t,
+		name:       name,
+		start:      t.timeNow(),
+		attributes: make(map[string]string),
+	}
+
+	parent := spanFromContext(ctx)
+	if parent == nil {
+		_, _ = rand.Read(span.traceID[:])
+	} else {
+		span.traceID = parent.traceID
+		span.parentSpanID = parent.spanID
+	}
+	_, _ = rand.Read(span.spanID[:])
+
+	return ContextWithSpan(ctx, span), span
+}
+
+func (t *Tracer) record(span *Span) {
+	t.spansMutex.Lock()
+	defer t.spansMutex.Unlock()
+
+	const maxBufferedSpans = 2048
+	if len(t.spans) == maxBufferedSpans {
+		// drop the oldest span if the collector cannot keep up
+		copy(t.spans, t.spans[1:])
+		t.spans = t.spans[:len(t.spans)-1]
+	}
+	t.spans = append(t.spans, span)
+}
+
+func (t *Tracer) popSpans() (spans []*Span) {
+	t.spansMutex.Lock()
+	defer t.spansMutex.Unlock()
+	spans = t.spans
+	t.spans = nil
+	return spans
+}
+
+// Run periodically exports ended spans to the collector,
+// until the context is canceled, at which point it tries
+// to export the remaining spans one last time.
+func (t *Tracer) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	if !t.enabled {
+		<-ctx.Done()
+		return
+	}
+
+	const exportPeriod = 5 * time.Second
+	ticker := time.NewTicker(exportPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			const flushTimeout = 300 * time.Millisecond
+			flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			t.exportSpans(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			t.exportSpans(ctx)
+		}
+	}
+}
+
+func (t *Tracer) exportSpans(ctx context.Context) {
+	spans := t.popSpans()
+	if len(spans) == 0 {
+		return
+	}
+
+	err := t.export(ctx, spans)
+	if err != nil {
+		t.logger.Warn("exporting " + fmt.Sprint(len(spans)) + " spans: " + err.Error())
+	}
+}
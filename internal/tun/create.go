@@ -1,6 +1,7 @@
 package tun
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,8 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+var ErrTUNCreate = errors.New("creating TUN device file node")
+
 // Create creates a TUN device at the path specified.
 func (t *Tun) Create(path string) error {
 	parentDir := filepath.Dir(path)
@@ -22,7 +25,7 @@ func (t *Tun) Create(path string) error {
 	dev := unix.Mkdev(major, minor)
 	err := t.mknod(path, unix.S_IFCHR, int(dev))
 	if err != nil {
-		return fmt.Errorf("creating TUN device file node: %w", err)
+		return fmt.Errorf("%w: %s", ErrTUNCreate, err)
 	}
 
 	fd, err := unix.Open(path, 0, 0)
@@ -30,10 +33,9 @@ func (t *Tun) Create(path string) error {
 		return fmt.Errorf("unix opening TUN device file: %w", err)
 	}
 
-	const nonBlocking = true
-	err = unix.SetNonblock(fd, nonBlocking)
+	err = unix.Close(fd)
 	if err != nil {
-		return fmt.Errorf("setting non block to TUN device file descriptor: %w", err)
+		return fmt.Errorf("closing TUN device file descriptor: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,93 @@
+package tun
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	ErrTUNInterface             = errors.New("creating test TUN interface")
+	ErrTUNInterfaceNotPermitted = errors.New("creating test TUN interface is not permitted")
+	ErrTUNNotUsable             = errors.New("TUN device is not usable")
+)
+
+type Logger interface {
+	Info(message string)
+}
+
+// Setup ensures the TUN device at the path given is usable.
+// It creates the device if it does not exist and if permitted,
+// and verifies it can be used to create a TUN interface.
+// If the device is not usable, the error returned contains a
+// remediation hint to fix the problem.
+func (t *Tun) Setup(path string, logger Logger) (err error) {
+	err = t.Check(path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Info(err.Error() + "; creating it...")
+		err = t.Create(path)
+		if err == nil {
+			err = t.Check(path)
+		}
+	}
+	if err == nil {
+		err = t.testOpen(path)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s%s", ErrTUNNotUsable, err, remediation(err))
+	}
+	return nil
+}
+
+// testOpen opens the TUN device and creates a transient TUN
+// interface with it, to verify the device is fully usable.
+// The interface is removed by the kernel when the file
+// descriptor is closed.
+func (t *Tun) testOpen(path string) (err error) {
+	fd, err := unix.Open(path, unix.O_RDWR|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("opening TUN device: %w", err)
+	}
+	defer unix.Close(fd)
+
+	ifreq, err := unix.NewIfreq("")
+	if err != nil {
+		return fmt.Errorf("creating interface request: %w", err)
+	}
+	ifreq.SetUint16(unix.IFF_TUN | unix.IFF_NO_PI)
+
+	err = t.ioctlIfreq(fd, unix.TUNSETIFF, ifreq)
+	if errors.Is(err, unix.EPERM) {
+		return fmt.Errorf("%w: %s", ErrTUNInterfaceNotPermitted, err)
+	} else if err != nil {
+		return fmt.Errorf("%w: %s", ErrTUNInterface, err)
+	}
+
+	return nil
+}
+
+func remediation(err error) (hint string) {
+	switch {
+	case errors.Is(err, ErrTUNBadRdev):
+		return "; the file is not a TUN character device, remove it " +
+			"or pass the TUN device to the container with --device /dev/net/tun"
+	case errors.Is(err, ErrTUNInterfaceNotPermitted):
+		return "; the NET_ADMIN capability is required, " +
+			"run the container with --cap-add=NET_ADMIN"
+	case errors.Is(err, ErrTUNCreate), errors.Is(err, unix.EPERM):
+		return "; access to the device is not permitted, " +
+			"pass the TUN device to the container with --device /dev/net/tun"
+	case errors.Is(err, unix.EACCES):
+		return fmt.Sprintf("; the device has wrong permissions for user id %d, "+
+			"make it readable and writable, for example with chmod 0666 /dev/net/tun",
+			os.Geteuid())
+	case errors.Is(err, unix.ENXIO), errors.Is(err, unix.ENODEV),
+		errors.Is(err, unix.EBADFD):
+		return "; the tun kernel module is likely not loaded, " +
+			"run 'modprobe tun' on the host"
+	default:
+		return ""
+	}
+}
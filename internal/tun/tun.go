@@ -3,11 +3,13 @@ package tun
 import "golang.org/x/sys/unix"
 
 type Tun struct {
-	mknod func(path string, mode uint32, dev int) (err error)
+	mknod      func(path string, mode uint32, dev int) (err error)
+	ioctlIfreq func(fd int, req uint, value *unix.Ifreq) (err error)
 }
 
 func New() *Tun {
 	return &Tun{
-		mknod: unix.Mknod,
+		mknod:      unix.Mknod,
+		ioctlIfreq: unix.IoctlIfreq,
 	}
 }
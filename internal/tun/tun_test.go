@@ -1,10 +1,14 @@
 package tun
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 func Test_Tun(t *testing.T) {
@@ -66,3 +70,94 @@ func getTempPath(t *testing.T) (path string) {
 	require.NoError(t, err)
 	return path
 }
+
+func Test_Tun_Setup(t *testing.T) {
+	t.Parallel()
+
+	path := getTempPath(t)
+	defer func() {
+		err := os.RemoveAll(path)
+		require.NoError(t, err)
+	}()
+
+	tun := New()
+	tun.ioctlIfreq = func(fd int, req uint, value *unix.Ifreq) error {
+		return unix.EPERM
+	}
+
+	logger := &testLogger{}
+	err := tun.Setup(path, logger)
+	require.ErrorIs(t, err, ErrTUNNotUsable)
+	assert.EqualError(t, err, "TUN device is not usable: "+
+		"creating test TUN interface is not permitted: operation not permitted; "+
+		"the NET_ADMIN capability is required, run the container with --cap-add=NET_ADMIN")
+	assert.Equal(t, []string{"TUN device is not available: open " + path +
+		": no such file or directory; creating it..."}, logger.messages)
+
+	tun.ioctlIfreq = func(fd int, req uint, value *unix.Ifreq) error {
+		assert.Equal(t, uint(unix.TUNSETIFF), req)
+		assert.Equal(t, uint16(unix.IFF_TUN|unix.IFF_NO_PI), value.Uint16())
+		return nil
+	}
+	logger.messages = nil
+	err = tun.Setup(path, logger)
+	require.NoError(t, err)
+	assert.Empty(t, logger.messages)
+}
+
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Info(message string) {
+	l.messages = append(l.messages, message)
+}
+
+func Test_remediation(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err  error
+		hint string
+	}{
+		"unknown error": {
+			err: errors.New("test"),
+		},
+		"bad rdev": {
+			err: ErrTUNBadRdev,
+			hint: "; the file is not a TUN character device, remove it " +
+				"or pass the TUN device to the container with --device /dev/net/tun",
+		},
+		"mknod not permitted": {
+			err: fmt.Errorf("%w: %s", ErrTUNCreate, unix.EPERM),
+			hint: "; access to the device is not permitted, " +
+				"pass the TUN device to the container with --device /dev/net/tun",
+		},
+		"interface creation not permitted": {
+			err: fmt.Errorf("%w: %s", ErrTUNInterfaceNotPermitted, unix.EPERM),
+			hint: "; the NET_ADMIN capability is required, " +
+				"run the container with --cap-add=NET_ADMIN",
+		},
+		"device open not permitted": {
+			err: &os.PathError{Op: "open", Path: "/dev/net/tun", Err: unix.EPERM},
+			hint: "; access to the device is not permitted, " +
+				"pass the TUN device to the container with --device /dev/net/tun",
+		},
+		"module not loaded": {
+			err: &os.PathError{Op: "open", Path: "/dev/net/tun", Err: unix.ENODEV},
+			hint: "; the tun kernel module is likely not loaded, " +
+				"run 'modprobe tun' on the host",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hint := remediation(testCase.err)
+
+			assert.Equal(t, testCase.hint, hint)
+		})
+	}
+}
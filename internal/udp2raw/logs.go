@@ -0,0 +1,34 @@
+package udp2raw
+
+import "strings"
+
+type logLevel uint8
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// processLogLine removes the timestamp and severity prefix from a
+// udp2raw log line such as "[2023-10-15 12:00:00][INFO]message",
+// and returns the message with its log level.
+func processLogLine(s string) (filtered string, level logLevel) {
+	prefixToLevel := map[string]logLevel{
+		"[TRACE]": levelDebug,
+		"[DEBUG]": levelDebug,
+		"[INFO]":  levelInfo,
+		"[WARN]":  levelWarn,
+		"[ERROR]": levelError,
+		"[FATAL]": levelError,
+	}
+	for prefix, prefixLevel := range prefixToLevel {
+		i := strings.Index(s, prefix)
+		if i == -1 {
+			continue
+		}
+		return strings.TrimSpace(s[i+len(prefix):]), prefixLevel
+	}
+	return s, levelInfo
+}
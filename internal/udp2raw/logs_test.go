@@ -0,0 +1,53 @@
+package udp2raw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_processLogLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s        string
+		filtered string
+		level    logLevel
+	}{
+		"empty string": {
+			level: levelInfo,
+		},
+		"no prefix": {
+			s:        "some line",
+			filtered: "some line",
+			level:    levelInfo,
+		},
+		"info": {
+			s:        "[2023-10-15 12:00:00][INFO]changed state to client_ready",
+			filtered: "changed state to client_ready",
+			level:    levelInfo,
+		},
+		"warn": {
+			s:        "[2023-10-15 12:00:00][WARN]state_changed to client_idle",
+			filtered: "state_changed to client_idle",
+			level:    levelWarn,
+		},
+		"fatal": {
+			s:        "[2023-10-15 12:00:00][FATAL]root or cap_net_raw capability not found",
+			filtered: "root or cap_net_raw capability not found",
+			level:    levelError,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered, level := processLogLine(testCase.s)
+
+			assert.Equal(t, testCase.filtered, filtered)
+			assert.Equal(t, testCase.level, level)
+		})
+	}
+}
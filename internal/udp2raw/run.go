@@ -0,0 +1,84 @@
+package udp2raw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+var (
+	ErrStart = errors.New("cannot start udp2raw")
+	ErrExit  = errors.New("udp2raw exited")
+)
+
+func (u *UDP2Raw) Run(ctx context.Context, waitError chan<- error, ready chan<- struct{}) {
+	const configPerms = 0600
+	config := []byte("-k " + *u.settings.Password + "\n")
+	err := u.fileWriter.WriteFile(u.configPath, config, configPerms)
+	if err != nil {
+		waitError <- fmt.Errorf("writing configuration file: %w", err)
+		return
+	}
+	defer func() {
+		removeErr := os.Remove(u.configPath)
+		if removeErr != nil {
+			u.logger.Error("cannot remove configuration file: " + removeErr.Error())
+		}
+	}()
+
+	cmd := u.command(ctx)
+	stdoutLines, stderrLines, udp2rawWaitError, err := u.starter.Start(cmd)
+	if err != nil {
+		waitError <- fmt.Errorf("%w: %s", ErrStart, err)
+		return
+	}
+
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	streamDone := make(chan struct{})
+	go streamLines(streamCtx, streamDone, u.logger,
+		stdoutLines, stderrLines, ctx.Done(), ready)
+
+	select {
+	case <-ctx.Done():
+		<-udp2rawWaitError
+		close(udp2rawWaitError)
+		streamCancel()
+		<-streamDone
+		waitError <- ctx.Err()
+	case err := <-udp2rawWaitError:
+		close(udp2rawWaitError)
+		streamCancel()
+		<-streamDone
+		waitError <- fmt.Errorf("%w: %s", ErrExit, err)
+	}
+}
+
+func (u *UDP2Raw) command(ctx context.Context) (cmd *exec.Cmd) {
+	cmd = exec.CommandContext(ctx, "udp2raw", u.args()...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	const waitDelay = 2 * time.Second
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+func (u *UDP2Raw) args() (args []string) {
+	return []string{
+		"-c",
+		"-l", u.localAddress.String(),
+		"-r", u.server.String(),
+		"--conf-file", u.configPath,
+		"--raw-mode", u.settings.RawMode,
+		// add an iptables rule so the kernel does not reset
+		// the fake TCP connection, which it does not know about.
+		"-a",
+		// send packets directly at the link layer, such that they
+		// are not routed back into the Wireguard tunnel.
+		"--lower-level", "auto",
+		"--disable-color",
+	}
+}
@@ -0,0 +1,51 @@
+package udp2raw
+
+import (
+	"context"
+	"strings"
+)
+
+func streamLines(ctx context.Context, done chan<- struct{},
+	logger Logger, stdout, stderr chan string,
+	runDone <-chan struct{}, ready chan<- struct{}) {
+	defer close(done)
+
+	var line string
+
+	for {
+		errLine := false
+		select {
+		case <-ctx.Done():
+			// Context should only be canceled after stdout and stderr are done
+			// being written to.
+			close(stdout)
+			close(stderr)
+			return
+		case line = <-stdout:
+		case line = <-stderr:
+			errLine = true
+		}
+		line, level := processLogLine(line)
+		if errLine {
+			level = levelError
+		}
+		switch level {
+		case levelDebug:
+			logger.Debug(line)
+		case levelInfo:
+			logger.Info(line)
+		case levelWarn:
+			logger.Warn(line)
+		case levelError:
+			logger.Error(line)
+		}
+		// the client reaches this state again after reconnecting
+		// to the server, so ready is signaled each time.
+		if strings.HasSuffix(line, "changed state to client_ready") {
+			select {
+			case ready <- struct{}{}:
+			case <-runDone:
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package udp2raw runs a udp2raw client, disguising the UDP packets
+// it receives locally as TCP packets sent to a udp2raw server.
+package udp2raw
+
+import (
+	"net/netip"
+	"path/filepath"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/golibs/command"
+)
+
+type UDP2Raw struct {
+	settings settings.UDP2Raw
+	// server is the address of the udp2raw server.
+	server netip.AddrPort
+	// localAddress is the local UDP address the client
+	// listens on, for example for Wireguard to connect to.
+	localAddress netip.AddrPort
+	// configPath is the file holding the udp2raw password while
+	// udp2raw runs, to keep it out of the command line arguments.
+	configPath string
+	starter    command.Starter
+	fileWriter FileWriter
+	logger     Logger
+}
+
+func New(settings settings.UDP2Raw, server, localAddress netip.AddrPort,
+	runtimeDirectory string, starter command.Starter, fileWriter FileWriter,
+	logger Logger) *UDP2Raw {
+	return &UDP2Raw{
+		settings:     settings,
+		server:       server,
+		localAddress: localAddress,
+		configPath:   filepath.Join(runtimeDirectory, "udp2raw.conf"),
+		starter:      starter,
+		fileWriter:   fileWriter,
+		logger:       logger,
+	}
+}
@@ -9,18 +9,24 @@ import (
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tracing"
 	"github.com/qdm12/gluetun/internal/updater"
 )
 
 type Updater interface {
-	UpdateServers(ctx context.Context, providers []string, minRatio float64) (err error)
+	UpdateServers(ctx context.Context, providers []string,
+		minRatio float64, minServers map[string]uint) (err error)
 }
 
 type Loop struct {
 	state state
 	// Objects
-	updater Updater
-	logger  Logger
+	updater     Updater
+	storage     updater.Storage
+	vpnSettings VPNSettingsGetter
+	logger      Logger
+	tracer      Tracer
+	notifier    Notifier
 	// Internal channels and locks
 	loopLock     sync.Mutex
 	start        chan struct{}
@@ -36,6 +42,15 @@ type Loop struct {
 
 const defaultBackoffTime = 5 * time.Second
 
+type Tracer interface {
+	Start(ctx context.Context, name string) (
+		spanCtx context.Context, span *tracing.Span)
+}
+
+type Notifier interface {
+	UpdaterFailed(err error)
+}
+
 type Logger interface {
 	Info(s string)
 	Warn(s string)
@@ -43,14 +58,19 @@ type Logger interface {
 }
 
 func NewLoop(settings settings.Updater, providers updater.Providers,
-	storage updater.Storage, client *http.Client, logger Logger) *Loop {
+	storage updater.Storage, vpnSettings VPNSettingsGetter,
+	client *http.Client, logger Logger, tracer Tracer, notifier Notifier) *Loop {
 	return &Loop{
 		state: state{
 			status:   constants.Stopped,
 			settings: settings,
 		},
 		updater:      updater.New(client, storage, providers, logger),
+		storage:      storage,
+		vpnSettings:  vpnSettings,
 		logger:       logger,
+		tracer:       tracer,
+		notifier:     notifier,
 		start:        make(chan struct{}),
 		running:      make(chan models.LoopStatus),
 		stop:         make(chan struct{}),
@@ -97,13 +117,18 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		runWg.Add(1)
 		go func() {
 			defer runWg.Done()
-			err := l.updater.UpdateServers(updateCtx, settings.Providers, settings.MinRatio)
+			spanCtx, span := l.tracer.Start(updateCtx, "updater")
+			pinned := l.getPinnedServer(settings.Providers)
+			err := l.updater.UpdateServers(spanCtx, settings.Providers,
+				settings.MinRatio, settings.MinServers)
+			span.End(err)
 			if err != nil {
 				if updateCtx.Err() == nil {
 					errorCh <- err
 				}
 				return
 			}
+			l.warnPinnedServerChange(pinned, settings.Providers)
 			l.state.setStatusWithLock(constants.Completed)
 		}()
 
@@ -136,6 +161,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			case err := <-errorCh:
 				runWg.Wait()
 				l.state.setStatusWithLock(constants.Crashed)
+				l.notifier.UpdaterFailed(err)
 				l.logAndWait(ctx, err)
 				crashed = true
 				stayHere = false
@@ -0,0 +1,81 @@
+package loop
+
+import (
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type VPNSettingsGetter interface {
+	GetSettings() (settings settings.VPN)
+}
+
+// pinnedServer is the server pinned by IP address in the VPN
+// settings, as found in the servers data.
+type pinnedServer struct {
+	provider string
+	ip       netip.Addr
+	server   models.Server
+	found    bool
+}
+
+// getPinnedServer returns the server pinned by IP address in the
+// VPN settings, if the IP address is set and the provider is one of
+// the providers given.
+func (l *Loop) getPinnedServer(providers []string) (pinned pinnedServer) {
+	vpnSettings := l.vpnSettings.GetSettings()
+	pinned.ip = vpnSettings.Provider.ServerSelection.ServerIP
+	if !pinned.ip.IsValid() || pinned.ip.IsUnspecified() {
+		return pinnedServer{}
+	}
+
+	pinned.provider = *vpnSettings.Provider.Name
+	updated := false
+	for _, provider := range providers {
+		if provider == pinned.provider {
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		return pinnedServer{}
+	}
+
+	for _, server := range l.storage.GetServers(pinned.provider) {
+		for _, ip := range server.IPs {
+			if ip == pinned.ip {
+				pinned.server = server
+				pinned.found = true
+				return pinned
+			}
+		}
+	}
+	return pinned
+}
+
+// warnPinnedServerChange logs a warning if the pinned server found
+// before the update is no longer in the servers data, or if its
+// hostname or Wireguard public key changed.
+func (l *Loop) warnPinnedServerChange(before pinnedServer, providers []string) {
+	if !before.found {
+		return
+	}
+
+	after := l.getPinnedServer(providers)
+	switch {
+	case after.provider != before.provider || after.ip != before.ip:
+		// VPN settings changed during the update
+	case !after.found:
+		l.logger.Warn("pinned server IP address " + before.ip.String() +
+			" is no longer in the " + before.provider +
+			" servers data, the VPN will fail to connect to it")
+	case after.server.Hostname != before.server.Hostname:
+		l.logger.Warn("pinned server IP address " + before.ip.String() +
+			" changed hostname from " + before.server.Hostname +
+			" to " + after.server.Hostname)
+	case after.server.WgPubKey != before.server.WgPubKey:
+		l.logger.Warn("pinned server IP address " + before.ip.String() +
+			" changed Wireguard public key")
+	}
+}
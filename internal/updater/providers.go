@@ -17,10 +17,9 @@ type Provider interface {
 var ErrServerHasNotEnoughInformation = errors.New("server has not enough information")
 
 func (u *Updater) updateProvider(ctx context.Context, provider Provider,
-	minRatio float64) (err error) {
+	minRatio float64, providerToMinServers map[string]uint) (err error) {
 	providerName := provider.Name()
-	existingServersCount := u.storage.GetServersCount(providerName)
-	minServers := int(minRatio * float64(existingServersCount))
+	minServers := u.minServers(providerName, minRatio, providerToMinServers)
 	servers, err := provider.FetchServers(ctx, minServers)
 	if err != nil {
 		return fmt.Errorf("getting servers: %w", err)
@@ -51,3 +50,16 @@ func (u *Updater) updateProvider(ctx context.Context, provider Provider,
 	}
 	return nil
 }
+
+// minServers returns the minimum number of servers to find for
+// the provider given. It is the number set for the provider if any,
+// and otherwise the minimum ratio of the current number of servers.
+func (u *Updater) minServers(providerName string, minRatio float64,
+	providerToMinServers map[string]uint) (minServers int) {
+	providerMinServers, ok := providerToMinServers[providerName]
+	if ok {
+		return int(providerMinServers)
+	}
+	existingServersCount := u.storage.GetServersCount(providerName)
+	return int(minRatio * float64(existingServersCount))
+}
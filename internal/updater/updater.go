@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/tracing"
 	"github.com/qdm12/gluetun/internal/updater/unzip"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -37,7 +38,7 @@ func New(httpClient *http.Client, storage Storage,
 }
 
 func (u *Updater) UpdateServers(ctx context.Context, providers []string,
-	minRatio float64) (err error) {
+	minRatio float64, minServers map[string]uint) (err error) {
 	caser := cases.Title(language.English)
 	for _, providerName := range providers {
 		u.logger.Info("updating " + caser.String(providerName) + " servers...")
@@ -45,7 +46,10 @@ func (u *Updater) UpdateServers(ctx context.Context, providers []string,
 		fetcher := u.providers.Get(providerName)
 		// TODO support servers offering only TCP or only UDP
 		// for NordVPN and PureVPN
-		err := u.updateProvider(ctx, fetcher, minRatio)
+		providerCtx, span := tracing.Start(ctx, "update provider")
+		span.SetAttribute("vpn.provider", providerName)
+		err := u.updateProvider(providerCtx, fetcher, minRatio, minServers)
+		span.End(err)
 		if err == nil {
 			continue
 		}
@@ -0,0 +1,140 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type Notifier interface {
+	UpdateAvailable(latest string)
+}
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
+}
+
+// Checker periodically checks Github for a newer version
+// than the one running, notifying once for each newer
+// version found.
+type Checker struct {
+	buildInfo models.BuildInformation
+	period    time.Duration
+	client    *http.Client
+	notifier  Notifier
+	logger    Logger
+
+	latest          string
+	updateAvailable *bool
+	checkedAt       time.Time
+	mutex           sync.RWMutex
+}
+
+func NewChecker(buildInfo models.BuildInformation, period time.Duration,
+	client *http.Client, notifier Notifier, logger Logger) *Checker {
+	return &Checker{
+		buildInfo: buildInfo,
+		period:    period,
+		client:    client,
+		notifier:  notifier,
+		logger:    logger,
+	}
+}
+
+// GetVersion returns the build information together with
+// the result of the last update check.
+func (c *Checker) GetVersion() (version models.VersionInformation) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	version.BuildInformation = c.buildInfo
+	if c.updateAvailable == nil {
+		return version
+	}
+	updateAvailable := *c.updateAvailable
+	version.UpdateAvailable = &updateAvailable
+	version.Latest = c.latest
+	checkedAt := c.checkedAt
+	version.CheckedAt = &checkedAt
+	return version
+}
+
+// Run checks for a newer version periodically if the period is
+// not zero, until the context is canceled. A failed check is retried
+// after a minute, for example if the VPN is not connected yet.
+func (c *Checker) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	if c.period == 0 {
+		return
+	}
+
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			if !timer.Stop() {
+				<-timer.C
+			}
+			return
+		case <-timer.C:
+		}
+
+		err := c.check(ctx)
+		switch {
+		case err == nil:
+			timer.Reset(c.period)
+		case ctx.Err() == nil:
+			c.logger.Warn("checking for a newer version: " + err.Error())
+			const retryPeriod = time.Minute
+			timer.Reset(retryPeriod)
+		}
+	}
+}
+
+func (c *Checker) check(ctx context.Context) (err error) {
+	latest, updateAvailable, err := getLatest(ctx, c.client, c.buildInfo)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	notify := updateAvailable && latest != c.latest
+	c.latest = latest
+	c.updateAvailable = &updateAvailable
+	c.checkedAt = time.Now()
+	c.mutex.Unlock()
+
+	if notify {
+		c.logger.Info("a newer version is available: " + latest)
+		c.notifier.UpdateAvailable(latest)
+	}
+	return nil
+}
+
+// getLatest returns the latest release tag name, or the latest commit
+// short hash if the version running is the latest image, and whether
+// it is newer than the version running.
+func getLatest(ctx context.Context, client *http.Client,
+	buildInfo models.BuildInformation) (latest string, updateAvailable bool, err error) {
+	if buildInfo.Version == "latest" {
+		commits, err := getGithubCommits(ctx, client)
+		if err != nil {
+			return "", false, err
+		} else if len(commits) == 0 {
+			return "", false, errCommitNotFound
+		}
+		const shortHashLength = 7
+		latest = commits[0].Sha[:shortHashLength]
+		return latest, latest != buildInfo.Commit, nil
+	}
+
+	latest, _, _, err = getLatestRelease(ctx, client)
+	if err != nil {
+		return "", false, err
+	}
+	return latest, latest != buildInfo.Version, nil
+}
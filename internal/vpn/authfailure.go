@@ -0,0 +1,136 @@
+package vpn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var ErrAuthFailedTooManyTimes = errors.New("authentication failed too many times")
+
+type authFailureState struct {
+	// failures is the number of consecutive authentication failures.
+	failures uint8
+	// connection is the connection of the last authentication
+	// failure, and is only set if the last attempt failed
+	// authenticating.
+	connection models.Connection
+	fatal      chan error
+}
+
+// isAuthFailure returns true if the error given is caused by the
+// VPN server or the VPN provider rejecting the credentials.
+// Wireguard servers do not reject handshakes explicitly, so
+// Wireguard failures are never authentication failures.
+func isAuthFailure(err error) bool {
+	return errors.Is(err, openvpn.ErrAuthFailed) ||
+		errors.Is(err, utils.ErrCredentialsNotValid)
+}
+
+// failedEventType returns the history event type to
+// record for the connection failure error given.
+func failedEventType(defaultType string, err error) (eventType string) {
+	if isAuthFailure(err) {
+		return models.VPNEventAuthFailed
+	}
+	return defaultType
+}
+
+// recordAuthFailure records the error given for the connection given,
+// resetting the consecutive authentication failures count if the error
+// is not an authentication failure. For the exit policy, the error is
+// sent to the fatal channel once the number of attempts set is reached.
+func (l *Loop) recordAuthFailure(settings settings.AuthFailure,
+	connection models.Connection, err error) {
+	if !isAuthFailure(err) {
+		l.authFailure.failures = 0
+		l.authFailure.connection = models.Connection{}
+		return
+	}
+
+	l.authFailure.failures++
+	l.authFailure.connection = connection
+	l.logger.Warn(fmt.Sprintf("authentication failed %d time(s) in a row, applying %s policy",
+		l.authFailure.failures, settings.Policy))
+
+	if settings.Policy != "exit" || l.authFailure.failures < settings.Attempts {
+		return
+	}
+
+	select {
+	case l.authFailure.fatal <- fmt.Errorf("%w: %d times in a row: %s",
+		ErrAuthFailedTooManyTimes, l.authFailure.failures, err):
+	default: // error already sent
+	}
+}
+
+// Fatal returns a channel receiving an error when the
+// program should exit because of the VPN loop.
+func (l *Loop) Fatal() <-chan error {
+	return l.authFailure.fatal
+}
+
+// authFailureProvider returns a provider picking connections according
+// to the authentication failure policy if the last connection attempt
+// failed authenticating, and returns the provider given otherwise.
+func (l *Loop) authFailureProvider(settings settings.AuthFailure,
+	providerConf provider.Provider) provider.Provider {
+	if l.authFailure.failures == 0 || !l.authFailure.connection.IP.IsValid() {
+		return providerConf
+	}
+
+	switch settings.Policy {
+	case "retry":
+		return &retryProvider{
+			Provider:   providerConf,
+			connection: l.authFailure.connection,
+		}
+	case "rotate":
+		return &rotateProvider{
+			Provider: providerConf,
+			failed:   l.authFailure.connection,
+		}
+	default:
+		return providerConf
+	}
+}
+
+// retryProvider returns the connection which failed
+// authenticating, to retry connecting to the same server.
+type retryProvider struct {
+	provider.Provider
+	connection models.Connection
+}
+
+func (p *retryProvider) GetConnection(settings.ServerSelection, bool) (
+	connection models.Connection, err error) {
+	return p.connection, nil
+}
+
+// rotateProvider picks a connection to a different server than
+// the connection which failed authenticating, if possible.
+type rotateProvider struct {
+	provider.Provider
+	failed models.Connection
+}
+
+func (p *rotateProvider) GetConnection(selection settings.ServerSelection,
+	ipv6Supported bool) (connection models.Connection, err error) {
+	const maxTries = 10
+	for i := 0; i < maxTries; i++ {
+		connection, err = p.Provider.GetConnection(selection, ipv6Supported)
+		if err != nil {
+			return connection, err
+		} else if connection.IP != p.failed.IP {
+			return connection, nil
+		}
+	}
+	// Only the failed server matches the server selection,
+	// or the provider picks connections deterministically.
+	return connection, nil
+}
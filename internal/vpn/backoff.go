@@ -0,0 +1,30 @@
+package vpn
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// nextBackoffTime returns the backoff time to use after a failed
+// reconnection attempt, capped to the backoff maximum if it is set.
+func nextBackoffTime(current time.Duration,
+	backoff settings.Backoff) (next time.Duration) {
+	next = time.Duration(float64(current) * backoff.Multiplier)
+	if *backoff.Maximum > 0 && next > *backoff.Maximum {
+		next = *backoff.Maximum
+	}
+	return next
+}
+
+// applyJitter randomly increases or decreases the duration given
+// by up to the jitter fraction of it, rounded to the millisecond.
+func applyJitter(duration time.Duration, jitter float64) (jittered time.Duration) {
+	if jitter == 0 {
+		return duration
+	}
+	factor := 1 + jitter*(2*rand.Float64()-1) //nolint:gosec,gomnd
+	jittered = time.Duration(float64(duration) * factor)
+	return jittered.Round(time.Millisecond)
+}
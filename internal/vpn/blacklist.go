@@ -0,0 +1,154 @@
+package vpn
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+type serverBlacklistState struct {
+	// servers maps each VPN server IP address to its failure
+	// score, and contains servers which failed recently.
+	servers map[netip.Addr]serverFailures
+	mutex   sync.Mutex
+}
+
+type serverFailures struct {
+	name        string
+	hostname    string
+	failures    uint8
+	lastFailure time.Time
+	// until is the time the server is blacklisted until,
+	// and is the zero time if the server is not blacklisted.
+	until time.Time
+}
+
+// recordServerFailure increases the failure score of the server of
+// the connection given, and blacklists the server for the cooldown
+// period once its score reaches the failures threshold. The score
+// is reset if the last failure is older than the cooldown period.
+func (l *Loop) recordServerFailure(settings settings.ServerBlacklist,
+	connection models.Connection, reason string) {
+	if *settings.Failures == 0 || !connection.IP.IsValid() {
+		return
+	}
+
+	now := time.Now()
+	l.serverBlacklist.mutex.Lock()
+	defer l.serverBlacklist.mutex.Unlock()
+	if l.serverBlacklist.servers == nil {
+		l.serverBlacklist.servers = make(map[netip.Addr]serverFailures)
+	}
+
+	server := l.serverBlacklist.servers[connection.IP]
+	if now.Sub(server.lastFailure) > *settings.Cooldown {
+		server = serverFailures{}
+	}
+	server.name = connection.ServerName
+	server.hostname = connection.Hostname
+	server.failures++
+	server.lastFailure = now
+	if server.failures >= *settings.Failures {
+		server.until = now.Add(*settings.Cooldown)
+		l.logger.Warn(fmt.Sprintf("blacklisting server %s for %s after %d failure(s): %s",
+			connection.IP, *settings.Cooldown, server.failures, reason))
+	}
+	l.serverBlacklist.servers[connection.IP] = server
+}
+
+// isServerBlacklisted returns true if the server IP address
+// given is blacklisted and its cooldown period is not over.
+func (l *Loop) isServerBlacklisted(ip netip.Addr) bool {
+	l.serverBlacklist.mutex.Lock()
+	defer l.serverBlacklist.mutex.Unlock()
+	return time.Now().Before(l.serverBlacklist.servers[ip].until)
+}
+
+// ReportUnhealthy records a failure for the VPN server
+// currently used, for example when the healthcheck fails.
+func (l *Loop) ReportUnhealthy() {
+	l.connection.mutex.RLock()
+	connection := models.Connection{
+		ServerName: l.connection.server.Name,
+		Hostname:   l.connection.server.Hostname,
+		IP:         l.connection.server.IP,
+	}
+	l.connection.mutex.RUnlock()
+	l.recordServerFailure(l.GetSettings().ServerBlacklist, connection, "unhealthy")
+}
+
+// GetServerBlacklist returns the servers currently blacklisted,
+// sorted by the time they are blacklisted until.
+func (l *Loop) GetServerBlacklist() (servers []models.BlacklistedServer) {
+	now := time.Now()
+	l.serverBlacklist.mutex.Lock()
+	defer l.serverBlacklist.mutex.Unlock()
+	servers = make([]models.BlacklistedServer, 0, len(l.serverBlacklist.servers))
+	for ip, server := range l.serverBlacklist.servers {
+		if !now.Before(server.until) {
+			continue
+		}
+		servers = append(servers, models.BlacklistedServer{
+			Name:     server.name,
+			Hostname: server.hostname,
+			IP:       ip,
+			Failures: server.failures,
+			Until:    server.until,
+		})
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Until.Before(servers[j].Until)
+	})
+	return servers
+}
+
+// ClearServerBlacklist removes all servers from the
+// blacklist and resets all the failure scores.
+func (l *Loop) ClearServerBlacklist() {
+	l.serverBlacklist.mutex.Lock()
+	defer l.serverBlacklist.mutex.Unlock()
+	l.serverBlacklist.servers = nil
+}
+
+// blacklistProvider returns a provider avoiding blacklisted servers
+// if the server blacklist is enabled, and returns the provider given
+// otherwise.
+func (l *Loop) blacklistProvider(settings settings.ServerBlacklist,
+	providerConf provider.Provider) provider.Provider {
+	if *settings.Failures == 0 {
+		return providerConf
+	}
+	return &blacklistProviderWrapper{
+		Provider:      providerConf,
+		isBlacklisted: l.isServerBlacklisted,
+	}
+}
+
+// blacklistProviderWrapper picks a connection to a server
+// which is not blacklisted, if possible.
+type blacklistProviderWrapper struct {
+	provider.Provider
+	isBlacklisted func(ip netip.Addr) bool
+}
+
+func (p *blacklistProviderWrapper) GetConnection(selection settings.ServerSelection,
+	ipv6Supported bool) (connection models.Connection, err error) {
+	const maxTries = 10
+	for i := 0; i < maxTries; i++ {
+		connection, err = p.Provider.GetConnection(selection, ipv6Supported)
+		if err != nil {
+			return connection, err
+		} else if !p.isBlacklisted(connection.IP) {
+			return connection, nil
+		}
+	}
+	// Only blacklisted servers match the server selection,
+	// or the provider picks connections deterministically.
+	return connection, nil
+}
@@ -0,0 +1,106 @@
+package vpn
+
+import (
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type connectionState struct {
+	set      bool
+	vpnType  string
+	provider string
+	protocol string
+	server   models.VPNStatusServer
+	mutex    sync.RWMutex
+}
+
+// setConnection records the connection set up, finding the
+// corresponding server in the storage to get its location.
+func (l *Loop) setConnection(settings settings.VPN, connection models.Connection) {
+	server := models.VPNStatusServer{
+		Name:     connection.ServerName,
+		Hostname: connection.Hostname,
+		IP:       connection.IP,
+		Port:     connection.Port,
+	}
+
+	var provider string
+	if settings.Type != vpn.Tailscale && settings.Type != vpn.Tor {
+		provider = *settings.Provider.Name
+		servers, err := l.storage.FilterServers(provider, settings.Provider.ServerSelection)
+		if err == nil {
+			fillServerLocation(&server, servers, connection)
+		}
+	}
+
+	l.connection.mutex.Lock()
+	defer l.connection.mutex.Unlock()
+	l.connection.set = true
+	l.connection.vpnType = settings.Type
+	l.connection.provider = provider
+	l.connection.protocol = connection.Protocol
+	l.connection.server = server
+}
+
+func fillServerLocation(server *models.VPNStatusServer,
+	servers []models.Server, connection models.Connection) {
+	for _, candidate := range servers {
+		for _, ip := range candidate.IPs {
+			if ip != connection.IP {
+				continue
+			}
+			if server.Hostname == "" {
+				server.Hostname = candidate.Hostname
+			}
+			server.Country = candidate.Country
+			server.Region = candidate.Region
+			server.City = candidate.City
+			return
+		}
+	}
+}
+
+// GetDetailedStatus returns the status of the VPN loop together
+// with information on the current connection.
+func (l *Loop) GetDetailedStatus() (status models.VPNStatus) {
+	status.Status = l.GetStatus()
+	status.Paused = l.isPaused()
+	status.IPv6Leak = l.getIPv6Leak()
+
+	l.connection.mutex.RLock()
+	if l.connection.set {
+		status.VPNType = l.connection.vpnType
+		status.Provider = l.connection.provider
+		status.Protocol = l.connection.protocol
+		server := l.connection.server
+		status.Server = &server
+	}
+	l.connection.mutex.RUnlock()
+
+	events := l.GetHistory()
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.Type == models.VPNEventDisconnected ||
+			event.Type == models.VPNEventFailed ||
+			event.Type == models.VPNEventAuthFailed {
+			status.LastReconnectReason = event.Reason
+			break
+		}
+	}
+
+	if status.Status == constants.Running && len(events) > 0 {
+		lastEvent := events[len(events)-1]
+		if lastEvent.Type == models.VPNEventConnected {
+			connectedSince := lastEvent.Time
+			status.ConnectedSince = &connectedSince
+			status.Uptime = time.Since(connectedSince).Round(time.Second).String()
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,18 @@
+package vpn
+
+import (
+	"context"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// Demand starts the VPN if the VPN is in on demand mode and was not
+// started yet. It is meant to be called when a client needs the VPN,
+// such as an HTTP proxy or Shadowsocks client, and is a no-op otherwise.
+func (l *Loop) Demand(ctx context.Context) {
+	if !l.awaitingDemand.CompareAndSwap(true, false) {
+		return
+	}
+	l.logger.Info("VPN demanded by a client, starting it")
+	_, _ = l.ApplyStatus(ctx, constants.Running)
+}
@@ -0,0 +1,18 @@
+package vpn
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// egressProxyConnection returns the connection to allow through the
+// firewall when connecting to the VPN server through the egress proxy.
+// Its port is left to zero to allow all traffic to the proxy, since the
+// port of its UDP relay is only known once the association is made.
+func egressProxyConnection(vpnType string,
+	egressProxy settings.EgressProxy) (connection models.Connection) {
+	return models.Connection{
+		Type: vpnType,
+		IP:   egressProxy.Address.Addr(),
+	}
+}
@@ -0,0 +1,50 @@
+package vpn
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/wireguard"
+)
+
+// handshakeTimeout is the duration to wait for the first Wireguard
+// handshake if the OpenVPN fallback is enabled. It is shorter than
+// the default healthcheck initial duration of 6 seconds, so the failed
+// handshake is detected before the healthcheck restarts the VPN.
+const handshakeTimeout = 5 * time.Second
+
+// applyOpenVPNFallback returns the OpenVPN fallback settings if
+// the fallback was triggered, and the settings given otherwise.
+func (l *Loop) applyOpenVPNFallback(settings settings.VPN) settings.VPN {
+	if !l.openvpnFallback || settings.Type != vpn.Wireguard {
+		return settings
+	}
+	return settings.OpenVPNFallback()
+}
+
+// recordHandshakeFailure counts consecutive Wireguard handshake
+// failures, and triggers the OpenVPN fallback once the number of
+// fallback attempts set is reached.
+func (l *Loop) recordHandshakeFailure(settings settings.VPN, err error) {
+	if settings.Type != vpn.Wireguard || *settings.Wireguard.OpenVPNFallbackAttempts == 0 {
+		return
+	}
+
+	if !errors.Is(err, wireguard.ErrHandshakeTimeout) {
+		l.handshakeFailures = 0
+		return
+	}
+
+	l.handshakeFailures++
+	if l.handshakeFailures < *settings.Wireguard.OpenVPNFallbackAttempts {
+		return
+	}
+
+	l.handshakeFailures = 0
+	l.openvpnFallback = true
+	l.logger.Warn(fmt.Sprintf("Wireguard handshake failed %d times in a row, "+
+		"falling back to OpenVPN over TCP until the program restarts", *settings.Wireguard.OpenVPNFallbackAttempts))
+}
@@ -2,10 +2,12 @@ package vpn
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
 )
 
 // waitForError waits 100ms for an error in the waitError channel.
@@ -32,9 +34,17 @@ func (l *Loop) waitForError(ctx context.Context,
 
 func (l *Loop) crashed(ctx context.Context, err error) {
 	l.signalOrSetStatus(constants.Crashed)
+	l.notifyTunnelDown(err)
 	l.logAndWait(ctx, err)
 }
 
+func (l *Loop) notifyTunnelDown(err error) {
+	l.notifier.TunnelDown()
+	if errors.Is(err, openvpn.ErrAuthFailed) {
+		l.notifier.AuthFailed()
+	}
+}
+
 func (l *Loop) signalOrSetStatus(status models.LoopStatus) {
 	if l.userTrigger {
 		l.userTrigger = false
@@ -51,9 +61,11 @@ func (l *Loop) logAndWait(ctx context.Context, err error) {
 	if err != nil {
 		l.logger.Error(err.Error())
 	}
-	l.logger.Info("retrying in " + l.backoffTime.String())
-	timer := time.NewTimer(l.backoffTime)
-	l.backoffTime *= 2
+	backoff := l.state.GetSettings().Backoff
+	wait := applyJitter(l.backoffTime, *backoff.Jitter)
+	l.logger.Info("retrying in " + wait.String())
+	timer := time.NewTimer(wait)
+	l.backoffTime = nextBackoffTime(l.backoffTime, backoff)
 	select {
 	case <-timer.C:
 	case <-ctx.Done():
@@ -0,0 +1,50 @@
+package vpn
+
+import (
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type history struct {
+	events []models.VPNEvent
+	mutex  sync.RWMutex
+}
+
+// GetHistory returns the last connection events, oldest first.
+func (l *Loop) GetHistory() (events []models.VPNEvent) {
+	l.history.mutex.RLock()
+	defer l.history.mutex.RUnlock()
+	events = make([]models.VPNEvent, len(l.history.events))
+	copy(events, l.history.events)
+	return events
+}
+
+func (l *Loop) recordEvent(eventType, server, reason string) {
+	settings := l.state.GetSettings()
+	event := models.VPNEvent{
+		Time:     time.Now(),
+		Type:     eventType,
+		VPNType:  settings.Type,
+		Provider: *settings.Provider.Name,
+		Server:   server,
+		Reason:   reason,
+	}
+
+	l.eventHooks.Trigger(eventType, map[string]string{
+		"type":        event.VPNType,
+		"provider":    event.Provider,
+		"server_name": event.Server,
+		"reason":      event.Reason,
+	})
+
+	l.history.mutex.Lock()
+	defer l.history.mutex.Unlock()
+	const maxEvents = 100
+	if len(l.history.events) == maxEvents {
+		copy(l.history.events, l.history.events[1:])
+		l.history.events = l.history.events[:len(l.history.events)-1]
+	}
+	l.history.events = append(l.history.events, event)
+}
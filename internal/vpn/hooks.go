@@ -0,0 +1,95 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/hook"
+)
+
+var ErrHookFailed = errors.New("hook failed")
+
+// runHook runs the hook executable at path, if set, with environment
+// variables describing the current connection. The hook is killed if it
+// runs for longer than the hooks timeout.
+func (l *Loop) runHook(ctx context.Context, hooks settings.Hooks,
+	name, path, vpnInterface string) (err error) {
+	if path == "" {
+		return nil
+	}
+
+	err = hook.Run(ctx, l.cmder, l.logger, name+" hook", []string{path},
+		l.hookEnv(name, vpnInterface), hooks.Timeout)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrHookFailed, name, err)
+	}
+	return nil
+}
+
+// runHookLogError runs the hook executable at path, if set,
+// and logs any error encountered.
+func (l *Loop) runHookLogError(ctx context.Context, hooks settings.Hooks,
+	name, path, vpnInterface string) {
+	err := l.runHook(ctx, hooks, name, path, vpnInterface)
+	if err != nil {
+		l.logger.Error(err.Error())
+	}
+}
+
+// hookEnv returns environment variables in the form KEY=value
+// describing the current connection, for the hook executables.
+func (l *Loop) hookEnv(hookName, vpnInterface string) (env []string) {
+	l.connection.mutex.RLock()
+	vpnType := l.connection.vpnType
+	provider := l.connection.provider
+	protocol := l.connection.protocol
+	server := l.connection.server
+	l.connection.mutex.RUnlock()
+
+	env = []string{
+		hook.EnvPrefix + "HOOK=" + hookName,
+		hook.EnvPrefix + "TYPE=" + vpnType,
+		hook.EnvPrefix + "PROVIDER=" + provider,
+		hook.EnvPrefix + "PROTOCOL=" + protocol,
+		hook.EnvPrefix + "INTERFACE=" + vpnInterface,
+		hook.EnvPrefix + "SERVER_NAME=" + server.Name,
+		hook.EnvPrefix + "SERVER_HOSTNAME=" + server.Hostname,
+	}
+
+	if server.IP.IsValid() {
+		env = append(env, hook.EnvPrefix+"SERVER_IP="+server.IP.String())
+	}
+
+	if server.Port != 0 {
+		env = append(env, hook.EnvPrefix+"SERVER_PORT="+strconv.Itoa(int(server.Port)))
+	}
+
+	port := l.portForward.GetPortForwarded()
+	if port != 0 {
+		env = append(env, hook.EnvPrefix+"PORT_FORWARDED="+strconv.Itoa(int(port)))
+	}
+
+	return env
+}
+
+// waitForPortForwarded waits for the forwarded port to be set,
+// until the timeout given elapses or the context is canceled.
+func (l *Loop) waitForPortForwarded(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	const checkPeriod = 100 * time.Millisecond
+	ticker := time.NewTicker(checkPeriod)
+	defer ticker.Stop()
+	for l.portForward.GetPortForwarded() == 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package vpn
+
+import (
+	"context"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/portforward"
+	"github.com/qdm12/golibs/command"
+	"github.com/qdm12/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePortForward struct {
+	port uint16
+}
+
+func (f *fakePortForward) Start(context.Context, portforward.StartData) (string, error) {
+	return "", nil
+}
+
+func (f *fakePortForward) Stop(context.Context) (string, error) { return "", nil }
+
+func (f *fakePortForward) GetPortForwarded() (port uint16) { return f.port }
+
+// writeFakeHook writes an executable shell script running
+// the script body given to a temporary directory.
+func writeFakeHook(t *testing.T, body string) (path string) {
+	t.Helper()
+	path = filepath.Join(t.TempDir(), "hook.sh")
+	const perm = 0700
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), perm)
+	require.NoError(t, err)
+	return path
+}
+
+func Test_Loop_runHook(t *testing.T) {
+	t.Parallel()
+
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh available to run fake hooks")
+	}
+
+	testCases := map[string]struct {
+		body       string
+		noPath     bool
+		timeout    time.Duration
+		envLines   []string
+		errWrapped error
+		errMessage string
+	}{
+		"no hook": {
+			noPath: true,
+		},
+		"success": {
+			body: `env | grep -E "^VPN_(HOOK|TYPE|PROVIDER|PROTOCOL|INTERFACE|SERVER_|PORT_)" | ` +
+				`sort > "$(dirname "$0")/env"`,
+			envLines: []string{
+				"VPN_HOOK=post-up",
+				"VPN_INTERFACE=tun0",
+				"VPN_PORT_FORWARDED=5000",
+				"VPN_PROTOCOL=udp",
+				"VPN_PROVIDER=mullvad",
+				"VPN_SERVER_HOSTNAME=se-sto-001.mullvad.net",
+				"VPN_SERVER_IP=1.2.3.4",
+				"VPN_SERVER_NAME=se-sto-001",
+				"VPN_SERVER_PORT=1194",
+				"VPN_TYPE=openvpn",
+			},
+		},
+		"exit code": {
+			body:       "exit 3",
+			errWrapped: ErrHookFailed,
+			errMessage: "hook failed: post-up: exit status 3",
+		},
+		"timeout": {
+			body:       "exec sleep 5",
+			timeout:    50 * time.Millisecond,
+			errWrapped: ErrHookFailed,
+			errMessage: "hook failed: post-up: timed out after 50ms: signal: killed",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		// Subtests do not run in parallel, since executing a hook
+		// written while another subtest forks can fail with
+		// "text file busy".
+		t.Run(name, func(t *testing.T) {
+			var path string
+			if !testCase.noPath {
+				path = writeFakeHook(t, testCase.body)
+			}
+
+			timeout := testCase.timeout
+			if timeout == 0 {
+				timeout = time.Second
+			}
+			hooks := settings.Hooks{Timeout: timeout}
+
+			loop := &Loop{
+				cmder:       command.NewCmder(),
+				logger:      log.New(log.SetWriters(io.Discard)),
+				portForward: &fakePortForward{port: 5000},
+			}
+			loop.connection.vpnType = "openvpn"
+			loop.connection.provider = "mullvad"
+			loop.connection.protocol = "udp"
+			loop.connection.server = models.VPNStatusServer{
+				Name:     "se-sto-001",
+				Hostname: "se-sto-001.mullvad.net",
+				IP:       netip.MustParseAddr("1.2.3.4"),
+				Port:     1194,
+			}
+
+			err := loop.runHook(context.Background(), hooks, "post-up", path, "tun0")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+
+			if len(testCase.envLines) > 0 {
+				data, err := os.ReadFile(filepath.Join(filepath.Dir(path), "env"))
+				require.NoError(t, err)
+				envLines := strings.Split(strings.TrimSpace(string(data)), "\n")
+				assert.Equal(t, testCase.envLines, envLines)
+			}
+		})
+	}
+}
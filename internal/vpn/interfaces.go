@@ -2,34 +2,44 @@ package vpn
 
 import (
 	"context"
+	"io/fs"
 	"net/netip"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
 	"github.com/qdm12/gluetun/internal/portforward"
 	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/gluetun/internal/tracing"
 )
 
 type Firewall interface {
 	SetVPNConnection(ctx context.Context, connection models.Connection, interfaceName string) error
+	SetSecondaryTunnel(ctx context.Context, connection models.Connection, interfaceName string,
+		mark int, ports []uint16, sources []netip.Prefix) error
 	SetAllowedPort(ctx context.Context, port uint16, interfaceName string) error
 	RemoveAllowedPort(ctx context.Context, port uint16) error
+	SetPaused(ctx context.Context, paused, allowLAN bool) error
 }
 
 type Routing interface {
 	VPNLocalGatewayIP(vpnInterface string) (gateway netip.Addr, err error)
+	DefaultRoutes() (defaultRoutes []routing.DefaultRoute, err error)
 }
 
 type PortForward interface {
 	Start(ctx context.Context, data portforward.StartData) (outcome string, err error)
 	Stop(ctx context.Context) (outcome string, err error)
+	GetPortForwarded() (port uint16)
 }
 
 type OpenVPN interface {
-	WriteConfig(lines []string) error
-	WriteAuthFile(user, password string) error
-	WriteAskPassFile(passphrase string) error
+	WriteConfig(lines []string) (path string, err error)
+	WriteAuthFile(user, password string) (path string, err error)
+	WriteAskPassFile(passphrase string) (path string, err error)
+	WriteSocksAuthFile(user, password string) (path string, err error)
 }
 
 type Providers interface {
@@ -75,8 +85,39 @@ type DNSLoop interface {
 	GetSettings() (settings settings.DNS)
 }
 
+type Tracer interface {
+	Start(ctx context.Context, name string) (
+		spanCtx context.Context, span *tracing.Span)
+}
+
+type Metrics interface {
+	RecordTunnelUp()
+}
+
+type Bandwidth interface {
+	SetTunnelInterface(name string)
+}
+
+type Notifier interface {
+	TunnelUp()
+	TunnelDown()
+	AuthFailed()
+}
+
+type EventHooks interface {
+	Trigger(eventType string, details map[string]string)
+}
+
 type PublicIPLoop interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	SetData(data models.PublicIP)
 }
+
+type Timezone interface {
+	Location() *time.Location
+}
+
+type FileWriter interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) (err error)
+}
@@ -0,0 +1,107 @@
+package vpn
+
+import (
+	"context"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ipv6LeakBlocked  = "blocked"
+	ipv6LeakTunneled = "tunneled"
+	ipv6LeakLeaking  = "leaking"
+)
+
+type ipv6LeakState struct {
+	// result is the result of the last IPv6 leak check, and
+	// is the empty string if no check completed yet.
+	result string
+	mutex  sync.RWMutex
+}
+
+// checkIPv6Leak verifies IPv6 egress is either fully blocked or only
+// going through the VPN tunnel, by attempting an IPv6 connection bound
+// to each default route interface outside the tunnel, which must fail,
+// and then an IPv6 connection through the routing table.
+// Some hosts leak IPv6 traffic despite IPv6 being disabled with sysctl.
+func (l *Loop) checkIPv6Leak(ctx context.Context, vpnInterface string) {
+	result, err := l.probeIPv6Leak(ctx, vpnInterface)
+	if err != nil {
+		l.logger.Warn("cannot check for IPv6 leaks: " + err.Error())
+		return
+	}
+
+	switch result {
+	case ipv6LeakLeaking:
+		l.logger.Warn("IPv6 traffic is leaking outside the VPN tunnel! " +
+			"Please disable IPv6 on your host or enable the firewall.")
+	default:
+		l.logger.Info("IPv6 leak check: " + result)
+	}
+
+	l.ipv6Leak.mutex.Lock()
+	l.ipv6Leak.result = result
+	l.ipv6Leak.mutex.Unlock()
+}
+
+func (l *Loop) probeIPv6Leak(ctx context.Context, vpnInterface string) (
+	result string, err error) {
+	defaultRoutes, err := l.routing.DefaultRoutes()
+	if err != nil {
+		return "", err
+	}
+
+	for _, defaultRoute := range defaultRoutes {
+		if defaultRoute.NetInterface == vpnInterface {
+			continue
+		}
+		err = l.dialIPv6Probe(ctx, defaultRoute.NetInterface)
+		if err == nil {
+			return ipv6LeakLeaking, nil
+		}
+	}
+
+	err = l.dialIPv6Probe(ctx, "")
+	if err == nil {
+		return ipv6LeakTunneled, nil
+	}
+	return ipv6LeakBlocked, nil
+}
+
+// dialIPv6Probe dials a TCP connection to a well known IPv6 address,
+// bound to the network interface given if it is not empty.
+func dialIPv6Probe(ctx context.Context, netInterface string) (err error) {
+	const timeout = 3 * time.Second
+	dialer := net.Dialer{Timeout: timeout}
+	if netInterface != "" {
+		dialer.Control = func(_, _ string, rawConn syscall.RawConn) error {
+			var bindErr error
+			err := rawConn.Control(func(fd uintptr) {
+				bindErr = unix.BindToDevice(int(fd), netInterface)
+			})
+			if err != nil {
+				return err
+			}
+			return bindErr
+		}
+	}
+
+	const probeAddress = "[2606:4700:4700::1111]:443"
+	connection, err := dialer.DialContext(ctx, "tcp6", probeAddress)
+	if err != nil {
+		return err
+	}
+	_ = connection.Close()
+	return nil
+}
+
+// getIPv6Leak returns the result of the last IPv6 leak check.
+func (l *Loop) getIPv6Leak() (result string) {
+	l.ipv6Leak.mutex.RLock()
+	defer l.ipv6Leak.mutex.RUnlock()
+	return l.ipv6Leak.result
+}
@@ -0,0 +1,93 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"syscall"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRouting struct {
+	defaultRoutes []routing.DefaultRoute
+	err           error
+}
+
+func (f *fakeRouting) VPNLocalGatewayIP(string) (gateway netip.Addr, err error) {
+	return netip.Addr{}, nil
+}
+
+func (f *fakeRouting) DefaultRoutes() (defaultRoutes []routing.DefaultRoute, err error) {
+	return f.defaultRoutes, f.err
+}
+
+func Test_Loop_probeIPv6Leak(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	defaultRoutes := []routing.DefaultRoute{
+		{NetInterface: "eth0"},
+		{NetInterface: "tun0"},
+	}
+
+	testCases := map[string]struct {
+		routing    *fakeRouting
+		dialErrors map[string]error
+		result     string
+		errWrapped error
+		errMessage string
+	}{
+		"default routes error": {
+			routing:    &fakeRouting{err: errTest},
+			errWrapped: errTest,
+			errMessage: "test error",
+		},
+		"leak": {
+			routing: &fakeRouting{defaultRoutes: defaultRoutes},
+			result:  ipv6LeakLeaking,
+		},
+		"no leak": {
+			routing: &fakeRouting{defaultRoutes: defaultRoutes},
+			dialErrors: map[string]error{
+				"eth0": syscall.EACCES,
+			},
+			result: ipv6LeakTunneled,
+		},
+		"no IPv6 route": {
+			routing: &fakeRouting{defaultRoutes: defaultRoutes},
+			dialErrors: map[string]error{
+				"eth0": syscall.ENETUNREACH,
+				"":     syscall.ENETUNREACH,
+			},
+			result: ipv6LeakBlocked,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var dialedInterfaces []string
+			loop := &Loop{
+				routing: testCase.routing,
+				dialIPv6Probe: func(_ context.Context, netInterface string) (err error) {
+					dialedInterfaces = append(dialedInterfaces, netInterface)
+					return testCase.dialErrors[netInterface]
+				},
+			}
+
+			result, err := loop.probeIPv6Leak(context.Background(), "tun0")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.result, result)
+			assert.NotContains(t, dialedInterfaces, "tun0")
+		})
+	}
+}
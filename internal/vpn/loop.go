@@ -1,7 +1,9 @@
 package vpn
 
 import (
+	"context"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
@@ -23,6 +25,11 @@ type Loop struct {
 	versionInfo   bool
 	ipv6Supported bool
 	vpnInputPorts []uint16 // TODO make changeable through stateful firewall
+	pauseAllowLAN bool
+	// dataDirectory and runtimeDirectory are the directories
+	// holding files of Tor, Tailscale and udp2raw.
+	dataDirectory    string
+	runtimeDirectory string
 	// Configurators
 	openvpnConf OpenVPN
 	netLinker   NetLinker
@@ -31,29 +38,46 @@ type Loop struct {
 	portForward PortForward
 	publicip    PublicIPLoop
 	dnsLooper   DNSLoop
+	tracer      Tracer
+	metrics     Metrics
+	notifier    Notifier
+	eventHooks  EventHooks
+	bandwidth   Bandwidth
 	// Other objects
-	starter command.Starter // for OpenVPN
-	logger  log.LoggerInterface
-	client  *http.Client
+	cmder      command.RunStarter // for OpenVPN and Tailscale
+	fileWriter FileWriter         // for Tailscale
+	logger     log.LoggerInterface
+	client     *http.Client
 	// Internal channels and values
-	stop        <-chan struct{}
-	stopped     chan<- struct{}
-	start       <-chan struct{}
-	running     chan<- models.LoopStatus
-	userTrigger bool
-	// Internal constant values
-	backoffTime time.Duration
+	stop            <-chan struct{}
+	stopped         chan<- struct{}
+	start           <-chan struct{}
+	running         chan<- models.LoopStatus
+	userTrigger     bool
+	history         history
+	connection      connectionState
+	pause           pauseState
+	authFailure     authFailureState
+	serverBlacklist serverBlacklistState
+	ipv6Leak        ipv6LeakState
+	dialIPv6Probe   func(ctx context.Context, netInterface string) (err error)
+	nat64           nat64State
+	// awaitingDemand is true in on demand mode until
+	// the VPN is started for the first time.
+	awaitingDemand atomic.Bool
+	backoffTime    time.Duration
+	// OpenVPN fallback state
+	handshakeFailures uint8
+	openvpnFallback   bool
 }
 
-const (
-	defaultBackoffTime = 15 * time.Second
-)
-
 func NewLoop(vpnSettings settings.VPN, ipv6Supported bool, vpnInputPorts []uint16,
+	pauseAllowLAN bool, dataDirectory, runtimeDirectory string,
 	providers Providers, storage Storage, openvpnConf OpenVPN,
 	netLinker NetLinker, fw Firewall, routing Routing,
-	portForward PortForward, starter command.Starter,
-	publicip PublicIPLoop, dnsLooper DNSLoop,
+	portForward PortForward, cmder command.RunStarter,
+	publicip PublicIPLoop, dnsLooper DNSLoop, tracer Tracer, metrics Metrics,
+	notifier Notifier, eventHooks EventHooks, bandwidth Bandwidth, fileWriter FileWriter,
 	logger log.LoggerInterface, client *http.Client,
 	buildInfo models.BuildInformation, versionInfo bool) *Loop {
 	start := make(chan struct{})
@@ -64,30 +88,45 @@ func NewLoop(vpnSettings settings.VPN, ipv6Supported bool, vpnInputPorts []uint1
 	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
 	state := state.New(statusManager, vpnSettings)
 
-	return &Loop{
-		statusManager: statusManager,
-		state:         state,
-		providers:     providers,
-		storage:       storage,
-		buildInfo:     buildInfo,
-		versionInfo:   versionInfo,
-		ipv6Supported: ipv6Supported,
-		vpnInputPorts: vpnInputPorts,
-		openvpnConf:   openvpnConf,
-		netLinker:     netLinker,
-		fw:            fw,
-		routing:       routing,
-		portForward:   portForward,
-		publicip:      publicip,
-		dnsLooper:     dnsLooper,
-		starter:       starter,
-		logger:        logger,
-		client:        client,
-		start:         start,
-		running:       running,
-		stop:          stop,
-		stopped:       stopped,
-		userTrigger:   true,
-		backoffTime:   defaultBackoffTime,
+	loop := &Loop{
+		statusManager:    statusManager,
+		state:            state,
+		providers:        providers,
+		storage:          storage,
+		buildInfo:        buildInfo,
+		versionInfo:      versionInfo,
+		ipv6Supported:    ipv6Supported,
+		vpnInputPorts:    vpnInputPorts,
+		pauseAllowLAN:    pauseAllowLAN,
+		dataDirectory:    dataDirectory,
+		runtimeDirectory: runtimeDirectory,
+		openvpnConf:      openvpnConf,
+		netLinker:        netLinker,
+		fw:               fw,
+		routing:          routing,
+		portForward:      portForward,
+		publicip:         publicip,
+		dnsLooper:        dnsLooper,
+		tracer:           tracer,
+		metrics:          metrics,
+		notifier:         notifier,
+		eventHooks:       eventHooks,
+		bandwidth:        bandwidth,
+		cmder:            cmder,
+		fileWriter:       fileWriter,
+		logger:           logger,
+		client:           client,
+		start:            start,
+		running:          running,
+		stop:             stop,
+		stopped:          stopped,
+		userTrigger:      true,
+		backoffTime:      vpnSettings.Backoff.Initial,
+		authFailure: authFailureState{
+			fatal: make(chan error, 1),
+		},
+		dialIPv6Probe: dialIPv6Probe,
 	}
+	loop.awaitingDemand.Store(*vpnSettings.OnDemand)
+	return loop
 }
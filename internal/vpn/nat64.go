@@ -0,0 +1,66 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/nat64"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+type nat64State struct {
+	// discovered is the NAT64 prefix discovered using DNS64,
+	// and is the zero prefix if it is not discovered yet.
+	discovered netip.Prefix
+}
+
+// nat64Provider returns a provider translating IPv4 VPN server addresses
+// to IPv6 addresses using the NAT64 prefix setting given, or returns the
+// provider given if the NAT64 prefix setting is empty. For the "auto"
+// setting, the prefix is discovered using DNS64 once and then cached.
+func (l *Loop) nat64Provider(ctx context.Context, nat64Prefix string,
+	providerConf provider.Provider) (wrapped provider.Provider, err error) {
+	var prefix netip.Prefix
+	switch nat64Prefix {
+	case "":
+		return providerConf, nil
+	case "auto":
+		if !l.nat64.discovered.IsValid() {
+			l.nat64.discovered, err = nat64.Discover(ctx, net.DefaultResolver)
+			if err != nil {
+				return nil, fmt.Errorf("discovering NAT64 prefix: %w", err)
+			}
+			l.logger.Info("discovered NAT64 prefix " + l.nat64.discovered.String())
+		}
+		prefix = l.nat64.discovered
+	default:
+		// the prefix is validated when reading settings
+		prefix = netip.MustParsePrefix(nat64Prefix)
+	}
+
+	return &nat64ProviderWrapper{
+		Provider: providerConf,
+		prefix:   prefix,
+	}, nil
+}
+
+// nat64ProviderWrapper translates IPv4 VPN server addresses
+// to IPv6 addresses reachable through the NAT64 prefix.
+type nat64ProviderWrapper struct {
+	provider.Provider
+	prefix netip.Prefix
+}
+
+func (p *nat64ProviderWrapper) GetConnection(selection settings.ServerSelection,
+	ipv6Supported bool) (connection models.Connection, err error) {
+	connection, err = p.Provider.GetConnection(selection, ipv6Supported)
+	if err != nil {
+		return connection, err
+	}
+	connection.IP = nat64.Embed(p.prefix, connection.IP)
+	return connection, nil
+}
@@ -5,47 +5,94 @@ import (
 	"fmt"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/tor"
 	"github.com/qdm12/golibs/command"
 )
 
 // setupOpenVPN sets OpenVPN up using the configurators and settings given.
-// It returns a serverName for port forwarding (PIA) and an error if it fails.
+// It returns the connection chosen and an error if it fails.
+// If Tor chaining is enabled, the runner returned runs Tor before OpenVPN,
+// and OpenVPN connects to the server through the Tor SOCKS proxy.
+// If the egress proxy is enabled, OpenVPN connects to the server
+// through it instead. The data directory given is where Tor keeps its state.
 func setupOpenVPN(ctx context.Context, fw Firewall,
 	openvpnConf OpenVPN, providerConf provider.Provider,
-	settings settings.VPN, ipv6Supported bool, starter command.Starter,
-	logger openvpn.Logger) (runner *openvpn.Runner, serverName string, err error) {
-	connection, err := providerConf.GetConnection(settings.Provider.ServerSelection, ipv6Supported)
+	settings settings.VPN, ipv6Supported bool, dataDirectory string,
+	starter command.Starter,
+	logger openvpn.Logger, torLogger tor.Logger) (runner tunnelRunner,
+	connection models.Connection, err error) {
+	connection, err = providerConf.GetConnection(settings.Provider.ServerSelection, ipv6Supported)
 	if err != nil {
-		return nil, "", fmt.Errorf("finding a valid server connection: %w", err)
+		return nil, models.Connection{}, fmt.Errorf("finding a valid server connection: %w", err)
 	}
 
 	lines := providerConf.OpenVPNConfig(connection, settings.OpenVPN, ipv6Supported)
-
-	if err := openvpnConf.WriteConfig(lines); err != nil {
-		return nil, "", fmt.Errorf("writing configuration to file: %w", err)
+	torChain := *settings.Tor.Chain
+	egressProxy := settings.EgressProxy
+	switch {
+	case torChain:
+		lines = append(lines, fmt.Sprintf("socks-proxy 127.0.0.1 %d", settings.Tor.SocksPort))
+	case egressProxy.Enabled():
+		line := fmt.Sprintf("socks-proxy %s %d",
+			egressProxy.Address.Addr(), egressProxy.Address.Port())
+		if *egressProxy.Username != "" {
+			path, err := openvpnConf.WriteSocksAuthFile(*egressProxy.Username, *egressProxy.Password)
+			if err != nil {
+				return nil, models.Connection{}, fmt.Errorf("writing SOCKS proxy auth to file: %w", err)
+			}
+			line += " " + path
+		}
+		lines = append(lines, line)
 	}
 
 	if *settings.OpenVPN.User != "" {
-		err := openvpnConf.WriteAuthFile(*settings.OpenVPN.User, *settings.OpenVPN.Password)
+		path, err := openvpnConf.WriteAuthFile(*settings.OpenVPN.User, *settings.OpenVPN.Password)
 		if err != nil {
-			return nil, "", fmt.Errorf("writing auth to file: %w", err)
+			return nil, models.Connection{}, fmt.Errorf("writing auth to file: %w", err)
 		}
+		lines = append(lines, "auth-user-pass "+path)
 	}
 
 	if *settings.OpenVPN.KeyPassphrase != "" {
-		err := openvpnConf.WriteAskPassFile(*settings.OpenVPN.KeyPassphrase)
+		path, err := openvpnConf.WriteAskPassFile(*settings.OpenVPN.KeyPassphrase)
 		if err != nil {
-			return nil, "", fmt.Errorf("writing askpass file: %w", err)
+			return nil, models.Connection{}, fmt.Errorf("writing askpass file: %w", err)
+		}
+		if *settings.OpenVPN.EncryptedKey != "" {
+			lines = append(lines, "askpass "+path)
 		}
 	}
 
-	if err := fw.SetVPNConnection(ctx, connection, settings.OpenVPN.Interface); err != nil {
-		return nil, "", fmt.Errorf("allowing VPN connection through firewall: %w", err)
+	configPath, err := openvpnConf.WriteConfig(lines)
+	if err != nil {
+		return nil, models.Connection{}, fmt.Errorf("writing configuration to file: %w", err)
 	}
 
-	runner = openvpn.NewRunner(settings.OpenVPN, starter, logger)
+	firewallConnection := connection
+	switch {
+	case torChain:
+		// only the Tor client connects out, to relays not known in advance
+		firewallConnection = models.Connection{Type: vpn.Tor}
+	case egressProxy.Enabled():
+		firewallConnection = egressProxyConnection(connection.Type, egressProxy)
+	}
+	if err := fw.SetVPNConnection(ctx, firewallConnection, settings.OpenVPN.Interface); err != nil {
+		return nil, models.Connection{}, fmt.Errorf("allowing VPN connection through firewall: %w", err)
+	}
+
+	runner = openvpn.NewRunner(settings.OpenVPN, configPath, starter, logger)
+	if torChain {
+		const transparent = false
+		runner = &chainRunner{
+			name:   "tor",
+			first:  tor.New(settings.Tor, transparent, dataDirectory, starter, torLogger),
+			tunnel: runner,
+		}
+	}
 
-	return runner, connection.ServerName, nil
+	return runner, connection, nil
 }
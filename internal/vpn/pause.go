@@ -0,0 +1,93 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+type pauseState struct {
+	paused bool
+	mutex  sync.Mutex
+}
+
+var ErrVPNNotStopped = errors.New("VPN is not stopped")
+
+// Pause stops the VPN and sets the firewall in its paused kill switch
+// state, blocking all outbound traffic or only allowing outbound
+// traffic to local networks. The VPN cannot be started again until
+// it is resumed, unless its settings are changed.
+func (l *Loop) Pause(ctx context.Context) (outcome string, err error) {
+	return l.pauseWith(ctx, l.pauseAllowLAN)
+}
+
+func (l *Loop) pauseWith(ctx context.Context, allowLAN bool) (
+	outcome string, err error) {
+	l.pause.mutex.Lock()
+	defer l.pause.mutex.Unlock()
+
+	if l.pause.paused {
+		return "already paused", nil
+	}
+
+	_, err = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+	if err != nil {
+		return "", fmt.Errorf("stopping VPN: %w", err)
+	}
+
+	status := l.statusManager.GetStatus()
+	if status != constants.Stopped {
+		return "", fmt.Errorf("%w: status is %s", ErrVPNNotStopped, status)
+	}
+
+	err = l.fw.SetPaused(ctx, true, allowLAN)
+	if err != nil {
+		return "", fmt.Errorf("pausing firewall: %w", err)
+	}
+	l.pause.paused = true
+
+	return "paused", nil
+}
+
+// Resume restores the firewall rules removed when pausing the VPN
+// and starts the VPN again.
+func (l *Loop) Resume(ctx context.Context) (outcome string, err error) {
+	l.pause.mutex.Lock()
+	defer l.pause.mutex.Unlock()
+
+	if !l.pause.paused {
+		return "not paused", nil
+	}
+
+	err = l.unpause(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return l.statusManager.ApplyStatus(ctx, constants.Running)
+}
+
+// unpause restores the firewall rules removed when pausing the VPN.
+// The pause mutex must be locked when calling it.
+func (l *Loop) unpause(ctx context.Context) (err error) {
+	if !l.pause.paused {
+		return nil
+	}
+
+	const allowLAN = false // ignored when resuming
+	err = l.fw.SetPaused(ctx, false, allowLAN)
+	if err != nil {
+		return fmt.Errorf("resuming firewall: %w", err)
+	}
+	l.pause.paused = false
+	return nil
+}
+
+func (l *Loop) isPaused() (paused bool) {
+	l.pause.mutex.Lock()
+	defer l.pause.mutex.Unlock()
+	return l.pause.paused
+}
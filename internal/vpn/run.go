@@ -5,6 +5,8 @@ import (
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tracing"
 	"github.com/qdm12/log"
 )
 
@@ -18,27 +20,77 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 	}
 
 	for ctx.Err() == nil {
-		settings := l.state.GetSettings()
+		settings := l.applyOpenVPNFallback(l.state.GetSettings())
 
-		providerConf := l.providers.Get(*settings.Provider.Name)
+		providerConf, err := l.nat64Provider(ctx, *settings.NAT64Prefix,
+			l.providers.Get(*settings.Provider.Name))
+		if err != nil {
+			l.recordEvent(models.VPNEventFailed, "", err.Error())
+			l.crashed(ctx, err)
+			continue
+		}
+		providerConf = l.blacklistProvider(settings.ServerBlacklist, providerConf)
+		providerConf = l.authFailureProvider(settings.AuthFailure, providerConf)
+
+		connectCtx, connectSpan := l.tracer.Start(ctx, "vpn connect")
+		connectSpan.SetAttribute("vpn.type", settings.Type)
+		connectSpan.SetAttribute("vpn.provider", *settings.Provider.Name)
 
 		portForwarding := *settings.Provider.PortForwarding.Enabled
-		var vpnRunner interface {
-			Run(ctx context.Context, waitError chan<- error, tunnelReady chan<- struct{})
-		}
-		var serverName, vpnInterface string
-		var err error
+		var vpnRunner tunnelRunner
+		var vpnInterface string
+		var connection models.Connection
 		subLogger := l.logger.New(log.SetComponent(settings.Type))
-		if settings.Type == vpn.OpenVPN {
+		setupCtx, setupSpan := tracing.Start(connectCtx, "vpn setup")
+		switch settings.Type {
+		case vpn.OpenVPN:
 			vpnInterface = settings.OpenVPN.Interface
-			vpnRunner, serverName, err = setupOpenVPN(ctx, l.fw,
-				l.openvpnConf, providerConf, settings, l.ipv6Supported, l.starter, subLogger)
-		} else { // Wireguard
+			vpnRunner, connection, err = setupOpenVPN(setupCtx, l.fw,
+				l.openvpnConf, providerConf, settings, l.ipv6Supported, l.dataDirectory,
+				l.cmder, subLogger,
+				l.logger.New(log.SetComponent(vpn.Tor)))
+		case vpn.Wireguard:
 			vpnInterface = settings.Wireguard.Interface
-			vpnRunner, serverName, err = setupWireguard(ctx, l.netLinker, l.fw,
-				providerConf, settings, l.ipv6Supported, subLogger)
+			vpnRunner, connection, err = setupWireguard(setupCtx, l.netLinker, l.fw,
+				providerConf, settings, l.ipv6Supported, l.runtimeDirectory,
+				l.cmder, l.fileWriter, subLogger,
+				l.logger.New(log.SetComponent("udp2raw")))
+		case vpn.Tailscale:
+			vpnInterface = settings.Tailscale.Interface
+			vpnRunner, connection, err = setupTailscale(setupCtx, l.fw,
+				settings, l.dataDirectory, l.runtimeDirectory,
+				l.cmder, l.fileWriter, subLogger)
+		case vpn.Tor:
+			vpnInterface = torInterface
+			vpnRunner, connection, err = setupTor(setupCtx, l.fw,
+				settings, l.dataDirectory, l.cmder, subLogger)
 		}
+		if err == nil && *settings.Secondary.Enabled {
+			secondaryLogger := l.logger.New(log.SetComponent("secondary tunnel"))
+			secondaryProvider := l.providers.Get(*settings.Secondary.Provider)
+			vpnRunner, err = setupSecondaryTunnel(setupCtx, l.netLinker, l.fw,
+				secondaryProvider, vpnRunner, settings.Secondary, secondaryLogger)
+		}
+		setupSpan.End(err)
 		if err != nil {
+			connectSpan.End(err)
+			l.recordEvent(failedEventType(models.VPNEventFailed, err), "", err.Error())
+			l.recordAuthFailure(settings.AuthFailure, connection, err)
+			l.recordServerFailure(settings.ServerBlacklist, connection, err.Error())
+			l.crashed(ctx, err)
+			continue
+		}
+		serverName := connection.ServerName
+		l.setConnection(settings, connection)
+		connectSpan.SetAttribute("vpn.server", serverName)
+		l.recordEvent(models.VPNEventConnecting, serverName, "")
+		hooks := settings.Hooks
+		err = l.runHook(connectCtx, hooks, "pre-up", *hooks.PreUp, vpnInterface)
+		if err != nil && hooks.FailurePolicy != "abort" {
+			l.logger.Error(err.Error())
+		} else if err != nil {
+			connectSpan.End(err)
+			l.recordEvent(models.VPNEventFailed, serverName, err.Error())
 			l.crashed(ctx, err)
 			continue
 		}
@@ -47,53 +99,96 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			serverName:     serverName,
 			portForwarder:  providerConf,
 			vpnIntf:        vpnInterface,
+			hooks:          hooks,
+			connectSpan:    connectSpan,
 		}
 
 		openvpnCtx, openvpnCancel := context.WithCancel(context.Background())
 		waitError := make(chan error)
 		tunnelReady := make(chan struct{})
 
+		_, tunnelWaitSpan := tracing.Start(connectCtx, "vpn tunnel wait")
 		go vpnRunner.Run(openvpnCtx, waitError, tunnelReady)
 
 		if err := l.waitForError(ctx, waitError); err != nil {
 			openvpnCancel()
+			tunnelWaitSpan.End(err)
+			connectSpan.End(err)
+			l.recordEvent(failedEventType(models.VPNEventFailed, err), serverName, err.Error())
+			l.recordAuthFailure(settings.AuthFailure, connection, err)
+			l.recordServerFailure(settings.ServerBlacklist, connection, err.Error())
 			l.crashed(ctx, err)
 			continue
 		}
 
-		l.backoffTime = defaultBackoffTime
+		l.backoffTime = settings.Backoff.Initial
 		l.signalOrSetStatus(constants.Running)
 
 		stayHere := true
+		stopped := false
 		for stayHere {
 			select {
 			case <-tunnelReady:
+				tunnelWaitSpan.End(nil)
+				l.authFailure.failures = 0
 				go l.onTunnelUp(openvpnCtx, tunnelUpData)
 			case <-ctx.Done():
-				l.cleanup(context.Background(), portForwarding)
+				tunnelWaitSpan.End(ctx.Err())
+				connectSpan.End(ctx.Err())
+				if !stopped {
+					l.recordEvent(models.VPNEventDisconnected, serverName, "program shutting down")
+				}
+				// port forwarding is already stopped before the VPN
+				// when the program shuts down.
+				const stopPortForwarding = false
+				l.runHookLogError(context.Background(), hooks, "pre-down", *hooks.PreDown, vpnInterface)
+				l.cleanup(context.Background(), stopPortForwarding)
 				openvpnCancel()
 				<-waitError
 				close(waitError)
+				l.runHookLogError(context.Background(), hooks, "post-down", *hooks.PostDown, vpnInterface)
 				return
 			case <-l.stop:
 				l.userTrigger = true
 				l.logger.Info("stopping")
+				tunnelWaitSpan.End(nil)
+				connectSpan.End(nil)
+				l.recordEvent(models.VPNEventDisconnected, serverName, "stop requested")
+				stopped = true
+				l.runHookLogError(ctx, hooks, "pre-down", *hooks.PreDown, vpnInterface)
 				l.cleanup(context.Background(), portForwarding)
 				openvpnCancel()
 				<-waitError
+				l.runHookLogError(ctx, hooks, "post-down", *hooks.PostDown, vpnInterface)
 				// do not close waitError or the waitError
 				// select case will trigger
 				l.stopped <- struct{}{}
 			case <-l.start:
 				l.userTrigger = true
 				l.logger.Info("starting")
+				if !stopped {
+					l.recordEvent(models.VPNEventDisconnected, serverName, "restart requested")
+					l.runHookLogError(ctx, hooks, "pre-down", *hooks.PreDown, vpnInterface)
+					openvpnCancel()
+					<-waitError
+					l.runHookLogError(ctx, hooks, "post-down", *hooks.PostDown, vpnInterface)
+				}
 				stayHere = false
 			case err := <-waitError: // unexpected error
 				l.statusManager.Lock() // prevent SetStatus from running in parallel
 
+				tunnelWaitSpan.End(err)
+				connectSpan.End(err)
+				l.recordEvent(failedEventType(models.VPNEventDisconnected, err), serverName, err.Error())
+
 				l.cleanup(context.Background(), portForwarding)
 				openvpnCancel()
+				l.runHookLogError(ctx, hooks, "post-down", *hooks.PostDown, vpnInterface)
+				l.recordHandshakeFailure(settings, err)
+				l.recordAuthFailure(settings.AuthFailure, connection, err)
+				l.recordServerFailure(settings.ServerBlacklist, connection, err.Error())
 				l.statusManager.SetStatus(constants.Crashed)
+				l.notifyTunnelDown(err)
 				l.logAndWait(ctx, err)
 				stayHere = false
 
@@ -0,0 +1,78 @@
+package vpn
+
+import (
+	"context"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// RunSchedule pauses the VPN when the current time, in the current
+// location of the timezone given, leaves the schedule windows, and resumes it when the current
+// time enters a schedule window. The VPN state is only changed on
+// window transitions, such that the VPN can still be paused or
+// resumed through the control server within or outside windows.
+func (l *Loop) RunSchedule(ctx context.Context, done chan<- struct{},
+	timezone Timezone) {
+	defer close(done)
+
+	firstCheck := true
+	var connected, scheduledDown bool
+	for {
+		schedule := l.state.GetSettings().Schedule
+		now := time.Now().In(timezone.Location())
+		switch {
+		case len(schedule.Windows) == 0:
+			if scheduledDown {
+				l.logger.Info("schedule removed: resuming VPN")
+				l.resumeScheduled(ctx)
+				scheduledDown = false
+			}
+			firstCheck = true
+		case firstCheck || connected != inSchedule(schedule, now):
+			firstCheck = false
+			connected = inSchedule(schedule, now)
+			if connected && scheduledDown {
+				l.logger.Info("schedule window started: resuming VPN")
+				l.resumeScheduled(ctx)
+				scheduledDown = false
+			} else if !connected {
+				l.logger.Info("outside of schedule windows: pausing VPN")
+				allowLAN := schedule.KillSwitch == "allow_lan"
+				_, err := l.pauseWith(ctx, allowLAN)
+				if err != nil {
+					l.logger.Error("pausing VPN: " + err.Error())
+					firstCheck = true // retry at next check
+				} else {
+					scheduledDown = true
+				}
+			}
+		}
+
+		// Check again at the start of the next minute
+		nextCheck := now.Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(nextCheck.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func inSchedule(schedule settings.Schedule, t time.Time) bool {
+	for _, window := range schedule.Windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Loop) resumeScheduled(ctx context.Context) {
+	_, err := l.Resume(ctx)
+	if err != nil {
+		l.logger.Error("resuming VPN: " + err.Error())
+	}
+}
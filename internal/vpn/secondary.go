@@ -0,0 +1,137 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/wireguard"
+)
+
+type tunnelRunner interface {
+	Run(ctx context.Context, waitError chan<- error, tunnelReady chan<- struct{})
+}
+
+const (
+	// secondaryMark is the firewall mark of packets to steer
+	// through the secondary tunnel, and its routing table number.
+	secondaryMark = 51821
+	// secondaryRulePriority is the priority of the secondary tunnel
+	// rules, which must be evaluated after the inbound rule (100) and
+	// before the main Wireguard tunnel rule (102).
+	secondaryRulePriority = 101
+	// mainFirewallMark is the firewall mark of the main Wireguard
+	// tunnel packets, which is also set on the secondary tunnel
+	// packets such that they are not routed through the main tunnel.
+	// This is why the main tunnel must be a Wireguard tunnel.
+	mainFirewallMark = 51820
+)
+
+// setupSecondaryTunnel sets up the secondary Wireguard tunnel to a
+// server of the provider given, and returns a runner running the
+// main tunnel runner given together with it.
+func setupSecondaryTunnel(ctx context.Context, netlinker NetLinker,
+	fw Firewall, providerConf provider.Provider, mainRunner tunnelRunner,
+	settings settings.SecondaryTunnel, logger wireguard.Logger) (
+	runner tunnelRunner, err error) {
+	const ipv6Supported = false // only IPv4 traffic is steered
+	connection, err := providerConf.GetConnection(settings.ServerSelection, ipv6Supported)
+	if err != nil {
+		return nil, fmt.Errorf("finding a VPN server: %w", err)
+	}
+	logger.Info("connecting to " + connection.IP.String() + " of " + *settings.Provider)
+
+	ipv6 := ipv6Supported
+	wireguardSettings := wireguard.Settings{
+		InterfaceName:   settings.Interface,
+		PrivateKey:      *settings.PrivateKey,
+		PublicKey:       connection.PubKey,
+		PreSharedKey:    *settings.PreSharedKey,
+		Endpoint:        netip.AddrPortFrom(connection.IP, connection.Port),
+		Addresses:       settings.Addresses,
+		FirewallMark:    mainFirewallMark,
+		RulePriority:    secondaryRulePriority,
+		SteeringMark:    secondaryMark,
+		SteeringSources: settings.SourceNetworks,
+		IPv6:            &ipv6,
+	}
+
+	secondary, err := wireguard.New(wireguardSettings, netlinker, logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating Wireguard: %w", err)
+	}
+
+	err = fw.SetSecondaryTunnel(ctx, connection, settings.Interface,
+		secondaryMark, settings.DestinationPorts, settings.SourceNetworks)
+	if err != nil {
+		return nil, fmt.Errorf("setting firewall: %w", err)
+	}
+
+	return &dualRunner{
+		main:      mainRunner,
+		secondary: secondary,
+	}, nil
+}
+
+// dualRunner runs the main tunnel and, once it is ready,
+// the secondary tunnel. If either tunnel exits, the other one
+// is stopped as well and the error is sent to the wait error channel.
+type dualRunner struct {
+	main      tunnelRunner
+	secondary tunnelRunner
+}
+
+func (d *dualRunner) Run(ctx context.Context, waitError chan<- error,
+	tunnelReady chan<- struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mainError := make(chan error)
+	mainReady := make(chan struct{})
+	go d.main.Run(ctx, mainError, mainReady)
+
+	var secondaryError chan error
+	var secondaryReady chan struct{}
+
+	for {
+		select {
+		case <-mainReady:
+			if secondaryError == nil {
+				secondaryError = make(chan error)
+				secondaryReady = make(chan struct{})
+				go d.secondary.Run(ctx, secondaryError, secondaryReady)
+			}
+			select {
+			case tunnelReady <- struct{}{}:
+			case <-ctx.Done():
+			}
+		case <-secondaryReady:
+		case err := <-mainError:
+			cancel()
+			if secondaryError != nil {
+				_ = waitRunnerError(secondaryReady, secondaryError)
+			}
+			waitError <- err
+			return
+		case err := <-secondaryError:
+			cancel()
+			_ = waitRunnerError(mainReady, mainError)
+			waitError <- fmt.Errorf("secondary tunnel: %w", err)
+			return
+		}
+	}
+}
+
+// waitRunnerError waits for the error of a runner, discarding
+// any tunnel ready signal it may send in the meantime.
+func waitRunnerError(ready <-chan struct{}, waitError <-chan error) (err error) {
+	for {
+		select {
+		case <-ready:
+		case err = <-waitError:
+			return err
+		}
+	}
+}
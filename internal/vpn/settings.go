@@ -13,5 +13,11 @@ func (l *Loop) GetSettings() (settings settings.VPN) {
 func (l *Loop) SetSettings(ctx context.Context,
 	vpn settings.VPN) (
 	outcome string) {
+	l.pause.mutex.Lock()
+	defer l.pause.mutex.Unlock()
+	err := l.unpause(ctx)
+	if err != nil {
+		return "failed resuming: " + err.Error()
+	}
 	return l.state.SetSettings(ctx, vpn)
 }
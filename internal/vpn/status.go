@@ -3,6 +3,7 @@ package vpn
 import (
 	"context"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -12,5 +13,13 @@ func (l *Loop) GetStatus() (status models.LoopStatus) {
 
 func (l *Loop) ApplyStatus(ctx context.Context, status models.LoopStatus) (
 	outcome string, err error) {
+	// The VPN is not started when paused, so it can only be
+	// resumed explicitly and not by the healthcheck for example.
+	if status == constants.Running && l.isPaused() {
+		return "paused", nil
+	}
+	if status == constants.Running {
+		l.awaitingDemand.Store(false)
+	}
 	return l.statusManager.ApplyStatus(ctx, status)
 }
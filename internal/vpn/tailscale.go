@@ -0,0 +1,33 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tailscale"
+	"github.com/qdm12/golibs/command"
+)
+
+// setupTailscale sets Tailscale up using the settings given.
+// It returns a connection with the exit node as server name
+// and an error if it fails.
+func setupTailscale(ctx context.Context, fw Firewall,
+	settings settings.VPN, dataDirectory, runtimeDirectory string,
+	cmder command.RunStarter,
+	fileWriter FileWriter, logger tailscale.Logger) (runner *tailscale.Tailscale,
+	connection models.Connection, err error) {
+	connection = models.Connection{Type: vpn.Tailscale}
+	err = fw.SetVPNConnection(ctx, connection, settings.Tailscale.Interface)
+	if err != nil {
+		return nil, models.Connection{}, fmt.Errorf("allowing Tailscale through firewall: %w", err)
+	}
+
+	runner = tailscale.New(settings.Tailscale, dataDirectory, runtimeDirectory,
+		cmder, fileWriter, logger)
+
+	connection.ServerName = settings.Tailscale.ExitNode
+	return runner, connection, nil
+}
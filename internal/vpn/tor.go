@@ -0,0 +1,92 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tor"
+	"github.com/qdm12/golibs/command"
+)
+
+// torInterface is the interface traffic goes through once
+// redirected to the Tor transparent proxy.
+const torInterface = "lo"
+
+// setupTor sets the Tor transparent proxy up using the settings given.
+// It returns a connection with the transparent proxy port and
+// an error if it fails.
+func setupTor(ctx context.Context, fw Firewall,
+	settings settings.VPN, dataDirectory string, starter command.Starter,
+	logger tor.Logger) (runner *tor.Tor,
+	connection models.Connection, err error) {
+	connection = models.Connection{
+		Type:       vpn.Tor,
+		Port:       settings.Tor.TransPort,
+		Protocol:   constants.TCP,
+		ServerName: "tor",
+	}
+	err = fw.SetVPNConnection(ctx, connection, torInterface)
+	if err != nil {
+		return nil, models.Connection{}, fmt.Errorf("allowing Tor through firewall: %w", err)
+	}
+
+	const transparent = true
+	runner = tor.New(settings.Tor, transparent, dataDirectory, starter, logger)
+
+	return runner, connection, nil
+}
+
+// chainRunner runs a first runner, such as the Tor client, and once
+// it is ready, the tunnel connecting through it. If either exits, the
+// other one is stopped as well and the error is sent to the wait error
+// channel, prefixed with the name of the first runner if it failed.
+type chainRunner struct {
+	name   string
+	first  tunnelRunner
+	tunnel tunnelRunner
+}
+
+func (c *chainRunner) Run(ctx context.Context, waitError chan<- error,
+	tunnelReady chan<- struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	firstError := make(chan error)
+	firstReady := make(chan struct{})
+	go c.first.Run(ctx, firstError, firstReady)
+
+	var tunnelError chan error
+	var tunnelReadyInner chan struct{}
+
+	for {
+		select {
+		case <-firstReady:
+			if tunnelError == nil {
+				tunnelError = make(chan error)
+				tunnelReadyInner = make(chan struct{})
+				go c.tunnel.Run(ctx, tunnelError, tunnelReadyInner)
+			}
+		case <-tunnelReadyInner:
+			select {
+			case tunnelReady <- struct{}{}:
+			case <-ctx.Done():
+			}
+		case err := <-firstError:
+			cancel()
+			if tunnelError != nil {
+				_ = waitRunnerError(tunnelReadyInner, tunnelError)
+			}
+			waitError <- fmt.Errorf("%s: %w", c.name, err)
+			return
+		case err := <-tunnelError:
+			cancel()
+			_ = waitRunnerError(firstReady, firstError)
+			waitError <- err
+			return
+		}
+	}
+}
@@ -3,8 +3,11 @@ package vpn
 import (
 	"context"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/tracing"
 	"github.com/qdm12/gluetun/internal/version"
 )
 
@@ -14,10 +17,24 @@ type tunnelUpData struct {
 	vpnIntf        string
 	serverName     string
 	portForwarder  provider.PortForwarder
+	// Lifecycle hooks
+	hooks settings.Hooks
+	// Tracing
+	connectSpan *tracing.Span
 }
 
 func (l *Loop) onTunnelUp(ctx context.Context, data tunnelUpData) {
+	ctx = tracing.ContextWithSpan(ctx, data.connectSpan)
+	ctx, span := tracing.Start(ctx, "vpn tunnel up")
+	defer data.connectSpan.End(nil)
+	defer span.End(nil)
+
+	l.metrics.RecordTunnelUp()
+	l.bandwidth.SetTunnelInterface(data.vpnIntf)
+	l.notifier.TunnelUp()
+	l.recordEvent(models.VPNEventConnected, data.serverName, "")
 	l.client.CloseIdleConnections()
+	go l.checkIPv6Leak(ctx, data.vpnIntf)
 
 	for _, vpnPort := range l.vpnInputPorts {
 		err := l.fw.SetAllowedPort(ctx, vpnPort, data.vpnIntf)
@@ -27,7 +44,9 @@ func (l *Loop) onTunnelUp(ctx context.Context, data tunnelUpData) {
 	}
 
 	if *l.dnsLooper.GetSettings().DoT.Enabled {
-		_, _ = l.dnsLooper.ApplyStatus(ctx, constants.Running)
+		dnsCtx, dnsSpan := tracing.Start(ctx, "dns start")
+		_, err := l.dnsLooper.ApplyStatus(dnsCtx, constants.Running)
+		dnsSpan.End(err)
 	}
 
 	// Runs the Public IP getter job once
@@ -42,8 +61,18 @@ func (l *Loop) onTunnelUp(ctx context.Context, data tunnelUpData) {
 		}
 	}
 
-	err := l.startPortForwarding(ctx, data)
+	pfCtx, pfSpan := tracing.Start(ctx, "port forwarding start")
+	err := l.startPortForwarding(pfCtx, data)
+	pfSpan.End(err)
 	if err != nil {
 		l.logger.Error(err.Error())
 	}
+
+	if *data.hooks.PostUp != "" {
+		if err == nil && data.portForwarding {
+			// give the hook the forwarded port if it is obtained in time
+			l.waitForPortForwarded(ctx, data.hooks.Timeout)
+		}
+		l.runHookLogError(ctx, data.hooks, "post-up", *data.hooks.PostUp, data.vpnIntf)
+	}
 }
@@ -3,39 +3,91 @@ package vpn
 import (
 	"context"
 	"fmt"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/qdm12/gluetun/internal/udp2raw"
 	"github.com/qdm12/gluetun/internal/wireguard"
+	"github.com/qdm12/golibs/command"
 )
 
+// udp2rawListenPort is the local UDP port the udp2raw client
+// listens on, and which Wireguard connects to if udp2raw is enabled.
+const udp2rawListenPort = 51821
+
 // setupWireguard sets Wireguard up using the configurators and settings given.
-// It returns a serverName for port forwarding (PIA) and an error if it fails.
+// It returns the connection chosen and an error if it fails.
 func setupWireguard(ctx context.Context, netlinker NetLinker,
 	fw Firewall, providerConf provider.Provider,
-	settings settings.VPN, ipv6Supported bool, logger wireguard.Logger) (
-	wireguarder *wireguard.Wireguard, serverName string, err error) {
-	connection, err := providerConf.GetConnection(settings.Provider.ServerSelection, ipv6Supported)
+	settings settings.VPN, ipv6Supported bool, runtimeDirectory string,
+	starter command.Starter, fileWriter FileWriter, logger wireguard.Logger,
+	udp2rawLogger udp2raw.Logger) (
+	runner tunnelRunner, connection models.Connection, err error) {
+	connection, err = providerConf.GetConnection(settings.Provider.ServerSelection, ipv6Supported)
 	if err != nil {
-		return nil, "", fmt.Errorf("finding a VPN server: %w", err)
+		return nil, models.Connection{}, fmt.Errorf("finding a VPN server: %w", err)
 	}
 
 	wireguardSettings := utils.BuildWireguardSettings(connection, settings.Wireguard, ipv6Supported)
+	if *settings.Wireguard.OpenVPNFallbackAttempts > 0 {
+		wireguardSettings.HandshakeTimeout = handshakeTimeout
+	}
+
+	udp2rawSettings := settings.Wireguard.UDP2Raw
+	firewallConnection := connection
+	var udp2rawRunner *udp2raw.UDP2Raw
+	if *udp2rawSettings.Enabled {
+		serverPort := *udp2rawSettings.ServerPort
+		if serverPort == 0 {
+			serverPort = connection.Port
+		}
+		server := netip.AddrPortFrom(connection.IP, serverPort)
+		localAddress := netip.AddrPortFrom(netip.AddrFrom4([4]byte{127, 0, 0, 1}), udp2rawListenPort)
+		udp2rawRunner = udp2raw.New(udp2rawSettings, server, localAddress,
+			runtimeDirectory, starter, fileWriter, udp2rawLogger)
+		// Wireguard sends its packets to the local udp2raw client,
+		// which is the only one connecting out to the server.
+		wireguardSettings.Endpoint = localAddress
+		firewallConnection.Port = serverPort
+		if udp2rawSettings.RawMode == "faketcp" {
+			firewallConnection.Protocol = constants.TCP
+		}
+	}
+
+	if settings.EgressProxy.Enabled() {
+		wireguardSettings.SocksProxy = wireguard.SocksProxy{
+			Address:  *settings.EgressProxy.Address,
+			Username: *settings.EgressProxy.Username,
+			Password: *settings.EgressProxy.Password,
+		}
+		firewallConnection = egressProxyConnection(connection.Type, settings.EgressProxy)
+	}
 
 	logger.Debug("Wireguard server public key: " + wireguardSettings.PublicKey)
 	logger.Debug("Wireguard client private key: " + wireguardSettings.PrivateKey)
 	logger.Debug("Wireguard pre-shared key: " + wireguardSettings.PreSharedKey)
 
-	wireguarder, err = wireguard.New(wireguardSettings, netlinker, logger)
+	wireguarder, err := wireguard.New(wireguardSettings, netlinker, logger)
 	if err != nil {
-		return nil, "", fmt.Errorf("creating Wireguard: %w", err)
+		return nil, models.Connection{}, fmt.Errorf("creating Wireguard: %w", err)
 	}
 
-	err = fw.SetVPNConnection(ctx, connection, settings.Wireguard.Interface)
+	err = fw.SetVPNConnection(ctx, firewallConnection, settings.Wireguard.Interface)
 	if err != nil {
-		return nil, "", fmt.Errorf("setting firewall: %w", err)
+		return nil, models.Connection{}, fmt.Errorf("setting firewall: %w", err)
+	}
+
+	if udp2rawRunner != nil {
+		return &chainRunner{
+			name:   "udp2raw",
+			first:  udp2rawRunner,
+			tunnel: wireguarder,
+		}, connection, nil
 	}
 
-	return wireguarder, connection.ServerName, nil
+	return wireguarder, connection, nil
 }
@@ -59,4 +59,6 @@ const (
 	stepSix
 	// stepSeven closes the bind connection and the TUN device file.
 	stepSeven
+	// stepEight closes the TLS relay connections.
+	stepEight
 )
@@ -3,7 +3,9 @@ package wireguard
 import (
 	"fmt"
 	"net"
+	"net/netip"
 
+	"github.com/qdm12/gluetun/internal/routing"
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 )
@@ -50,12 +52,9 @@ func makeDeviceConfig(settings Settings) (config wgtypes.Config, err error) {
 		FirewallMark: &firewallMark,
 		Peers: []wgtypes.PeerConfig{
 			{
-				PublicKey:    publicKey,
-				PresharedKey: preSharedKey,
-				AllowedIPs: []net.IPNet{
-					*allIPv4(),
-					*allIPv6(),
-				},
+				PublicKey:         publicKey,
+				PresharedKey:      preSharedKey,
+				AllowedIPs:        allowedIPNets(settings.AllowedIPs),
 				ReplaceAllowedIPs: true,
 				Endpoint: &net.UDPAddr{
 					IP:   settings.Endpoint.Addr().AsSlice(),
@@ -68,6 +67,40 @@ func makeDeviceConfig(settings Settings) (config wgtypes.Config, err error) {
 	return config, nil
 }
 
+// allowedIPNets returns the allowed IP networks given
+// converted to net.IPNet, or all IPv4 and IPv6 networks
+// if no allowed IP network is given.
+func allowedIPNets(allowedIPs []netip.Prefix) (ipNets []net.IPNet) {
+	if len(allowedIPs) == 0 {
+		return []net.IPNet{*allIPv4(), *allIPv6()}
+	}
+
+	ipNets = make([]net.IPNet, len(allowedIPs))
+	for i := range allowedIPs {
+		ipNets[i] = *routing.NetipPrefixToIPNet(&allowedIPs[i])
+	}
+	return ipNets
+}
+
+// routeDestinations returns the IPv4 or IPv6 destinations
+// to route through the tunnel, given the allowed IP networks.
+func routeDestinations(allowedIPs []netip.Prefix, ipv6 bool) (destinations []*net.IPNet) {
+	if len(allowedIPs) == 0 {
+		if ipv6 {
+			return []*net.IPNet{allIPv6()}
+		}
+		return []*net.IPNet{allIPv4()}
+	}
+
+	for i := range allowedIPs {
+		if allowedIPs[i].Addr().Is6() != ipv6 {
+			continue
+		}
+		destinations = append(destinations, routing.NetipPrefixToIPNet(&allowedIPs[i]))
+	}
+	return destinations
+}
+
 func allIPv4() (ipNet *net.IPNet) {
 	return &net.IPNet{
 		IP:   net.IPv4(0, 0, 0, 0),
@@ -122,3 +122,47 @@ func Test_allIPv6(t *testing.T) {
 	ipNet := allIPv6()
 	assert.Equal(t, "::/0", ipNet.String())
 }
+
+func Test_routeDestinations(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		allowedIPs   []netip.Prefix
+		ipv6         bool
+		destinations []*net.IPNet
+	}{
+		"no allowed IPs IPv4": {
+			destinations: []*net.IPNet{allIPv4()},
+		},
+		"no allowed IPs IPv6": {
+			ipv6:         true,
+			destinations: []*net.IPNet{allIPv6()},
+		},
+		"allowed IPs IPv4": {
+			allowedIPs: []netip.Prefix{
+				netip.MustParsePrefix("10.0.0.0/8"),
+				netip.MustParsePrefix("fd00::/8"),
+			},
+			destinations: []*net.IPNet{
+				{IP: net.IPv4(10, 0, 0, 0), Mask: net.IPv4Mask(255, 0, 0, 0)},
+			},
+		},
+		"allowed IPs IPv4 only for IPv6": {
+			allowedIPs: []netip.Prefix{
+				netip.MustParsePrefix("10.0.0.0/8"),
+			},
+			ipv6: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			destinations := routeDestinations(testCase.allowedIPs, testCase.ipv6)
+
+			assert.Equal(t, testCase.destinations, destinations)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.zx2c4.com/wireguard/wgctrl"
+)
+
+// ErrHandshakeTimeout is returned by Run if no handshake with the
+// Wireguard server completed within the handshake timeout set.
+var ErrHandshakeTimeout = errors.New("handshake did not complete")
+
+// waitForHandshake waits for the first handshake with the peer of
+// the Wireguard interface to complete, and returns ErrHandshakeTimeout
+// if it does not complete within the timeout given. It returns nil if
+// the context is canceled.
+func waitForHandshake(ctx context.Context, interfaceName string,
+	timeout time.Duration) (err error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWgctrlOpen, err)
+	}
+	defer client.Close()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	const pollPeriod = 500 * time.Millisecond
+	ticker := time.NewTicker(pollPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			return fmt.Errorf("%w after %s", ErrHandshakeTimeout, timeout)
+		case <-ticker.C:
+		}
+
+		device, err := client.Device(interfaceName)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrDeviceInfo, err)
+		}
+
+		for _, peer := range device.Peers {
+			if !peer.LastHandshakeTime.IsZero() {
+				return nil
+			}
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package wireguard
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/netlink"
+	"golang.org/x/sys/unix"
 )
 
 func (w *Wireguard) addRule(rulePriority, firewallMark, family int) (
@@ -27,3 +31,72 @@ func (w *Wireguard) addRule(rulePriority, firewallMark, family int) (
 	}
 	return cleanup, nil
 }
+
+// addRules adds the IP rules for the given family, which are either the
+// rule routing all packets not marked with the firewall mark through the
+// tunnel, or the steering rules if a steering mark is set.
+func (w *Wireguard) addRules(family int) (cleanup func() error, err error) {
+	if w.settings.SteeringMark == 0 {
+		return w.addRule(w.settings.RulePriority,
+			w.settings.FirewallMark, family)
+	}
+	return w.addSteeringRules(w.settings.RulePriority,
+		w.settings.SteeringMark, w.settings.SteeringSources, family)
+}
+
+// routingTable returns the routing table number to use
+// for the routes going through the tunnel.
+func (w *Wireguard) routingTable() (table int) {
+	if w.settings.SteeringMark == 0 {
+		return w.settings.FirewallMark
+	}
+	return w.settings.SteeringMark
+}
+
+func (w *Wireguard) addSteeringRules(rulePriority, steeringMark int,
+	sources []netip.Prefix, family int) (cleanup func() error, err error) {
+	markRule := netlink.NewRule()
+	markRule.Priority = rulePriority
+	markRule.Mark = steeringMark
+	markRule.Table = steeringMark
+	markRule.Family = family
+	rules := []*netlink.Rule{markRule}
+
+	for _, source := range sources {
+		if (family == unix.AF_INET) != source.Addr().Is4() {
+			continue
+		}
+		sourceRule := netlink.NewRule()
+		sourceRule.Priority = rulePriority
+		sourceRule.Src = &net.IPNet{
+			IP:   source.Addr().AsSlice(),
+			Mask: net.CIDRMask(source.Bits(), source.Addr().BitLen()),
+		}
+		sourceRule.Table = steeringMark
+		sourceRule.Family = family
+		rules = append(rules, sourceRule)
+	}
+
+	added := make([]*netlink.Rule, 0, len(rules))
+	cleanup = func() error {
+		var errs []error
+		for i := len(added) - 1; i >= 0; i-- {
+			err := w.netlink.RuleDel(added[i])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("deleting rule %s: %w", added[i], err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, rule := range rules {
+		err = w.netlink.RuleAdd(rule)
+		if err != nil {
+			_ = cleanup()
+			return nil, fmt.Errorf("adding rule %s: %w", rule, err)
+		}
+		added = append(added, rule)
+	}
+
+	return cleanup, nil
+}
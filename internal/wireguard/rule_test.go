@@ -2,6 +2,8 @@ package wireguard
 
 import (
 	"errors"
+	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -109,3 +111,69 @@ func Test_Wireguard_addRule(t *testing.T) {
 		})
 	}
 }
+
+func Test_Wireguard_addSteeringRules(t *testing.T) {
+	t.Parallel()
+
+	const rulePriority = 100
+	const steeringMark = 51821
+	const family = unix.AF_INET
+
+	ctrl := gomock.NewController(t)
+	netLinker := NewMockNetLinker(ctrl)
+	wg := Wireguard{
+		netlink: netLinker,
+	}
+
+	sources := []netip.Prefix{
+		netip.MustParsePrefix("172.18.0.0/16"),
+		netip.MustParsePrefix("fd00::/64"),
+	}
+
+	markRule := &netlink.Rule{
+		Priority:          rulePriority,
+		Mark:              steeringMark,
+		Table:             steeringMark,
+		Mask:              -1,
+		Goto:              -1,
+		Flow:              -1,
+		SuppressIfgroup:   -1,
+		SuppressPrefixlen: -1,
+		Family:            family,
+	}
+	sourceRule := &netlink.Rule{
+		Priority: rulePriority,
+		Src: &net.IPNet{
+			IP:   net.IP{172, 18, 0, 0},
+			Mask: net.CIDRMask(16, 32),
+		},
+		Mark:              -1,
+		Table:             steeringMark,
+		Mask:              -1,
+		Goto:              -1,
+		Flow:              -1,
+		SuppressIfgroup:   -1,
+		SuppressPrefixlen: -1,
+		Family:            family,
+	}
+
+	errDummy := errors.New("dummy")
+	netLinker.EXPECT().RuleAdd(markRule).Return(nil)
+	netLinker.EXPECT().RuleAdd(sourceRule).Return(errDummy)
+	netLinker.EXPECT().RuleDel(markRule).Return(nil)
+	_, err := wg.addSteeringRules(rulePriority, steeringMark, sources, family)
+	require.Error(t, err)
+	assert.EqualError(t, err, "adding rule ip rule 100: from 172.18.0.0/16 to all table 51821: dummy")
+
+	netLinker.EXPECT().RuleAdd(markRule).Return(nil)
+	netLinker.EXPECT().RuleAdd(sourceRule).Return(nil)
+	cleanup, err := wg.addSteeringRules(rulePriority, steeringMark, sources, family)
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		netLinker.EXPECT().RuleDel(sourceRule).Return(nil),
+		netLinker.EXPECT().RuleDel(markRule).Return(errDummy),
+	)
+	err = cleanup()
+	assert.EqualError(t, err, "deleting rule ip rule 100: from all to all table 51821: dummy")
+}
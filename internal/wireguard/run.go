@@ -32,10 +32,15 @@ var (
 	ErrRouteAdd          = errors.New("cannot add route for interface")
 	ErrDeviceWaited      = errors.New("device waited for")
 	ErrKernelSupport     = errors.New("kernel does not support Wireguard")
+	ErrTLSRelay          = errors.New("cannot start TLS relay")
+	ErrSocksRelay        = errors.New("cannot start SOCKS relay")
 )
 
 // See https://git.zx2c4.com/wireguard-go/tree/main.go
 func (w *Wireguard) Run(ctx context.Context, waitError chan<- error, ready chan<- struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	kernelSupported, err := w.netlink.IsWireguardSupported()
 	if err != nil {
 		waitError <- fmt.Errorf("%w: %s", ErrDetectKernel, err)
@@ -86,8 +91,32 @@ func (w *Wireguard) Run(ctx context.Context, waitError chan<- error, ready chan<
 		return
 	}
 
+	deviceSettings := w.settings
+	switch {
+	case w.settings.TLS:
+		w.logger.Info("Tunneling through TLS to " + w.settings.Endpoint.String())
+		relay, err := startTLSRelay(ctx, w.settings.Endpoint, w.settings.TLSServerName,
+			w.settings.SocksProxy, w.settings.FirewallMark, w.logger)
+		if err != nil {
+			waitError <- fmt.Errorf("%w: %s", ErrTLSRelay, err)
+			return
+		}
+		closers.add("closing TLS relay", stepEight, relay.close)
+		deviceSettings.Endpoint = relay.localAddress()
+	case w.settings.SocksProxy.Enabled():
+		w.logger.Info("Relaying through SOCKS proxy " + w.settings.SocksProxy.Address.String())
+		relay, err := startSocksRelay(ctx, w.settings.Endpoint,
+			w.settings.SocksProxy, w.settings.FirewallMark, w.logger)
+		if err != nil {
+			waitError <- fmt.Errorf("%w: %s", ErrSocksRelay, err)
+			return
+		}
+		closers.add("closing SOCKS relay", stepEight, relay.close)
+		deviceSettings.Endpoint = relay.localAddress()
+	}
+
 	w.logger.Info("Connecting to " + w.settings.Endpoint.String())
-	err = configureDevice(client, w.settings)
+	err = configureDevice(client, deviceSettings)
 	if err != nil {
 		waitError <- fmt.Errorf("%w: %s", ErrConfigure, err)
 		return
@@ -101,10 +130,12 @@ func (w *Wireguard) Run(ctx context.Context, waitError chan<- error, ready chan<
 		return w.netlink.LinkSetDown(link)
 	})
 
-	err = w.addRoute(link, allIPv4(), w.settings.FirewallMark)
-	if err != nil {
-		waitError <- fmt.Errorf("%w: %s", ErrRouteAdd, err)
-		return
+	for _, destination := range routeDestinations(w.settings.AllowedIPs, false) {
+		err = w.addRoute(link, destination, w.routingTable())
+		if err != nil {
+			waitError <- fmt.Errorf("%w: %s", ErrRouteAdd, err)
+			return
+		}
 	}
 
 	if *w.settings.IPv6 {
@@ -116,8 +147,7 @@ func (w *Wireguard) Run(ctx context.Context, waitError chan<- error, ready chan<
 		}
 	}
 
-	ruleCleanup, err := w.addRule(w.settings.RulePriority,
-		w.settings.FirewallMark, unix.AF_INET)
+	ruleCleanup, err := w.addRules(unix.AF_INET)
 	if err != nil {
 		waitError <- fmt.Errorf("adding IPv4 rule: %w", err)
 		return
@@ -127,14 +157,34 @@ func (w *Wireguard) Run(ctx context.Context, waitError chan<- error, ready chan<
 	w.logger.Info("Wireguard is up")
 	ready <- struct{}{}
 
-	waitError <- waitAndCleanup()
+	handshakeErr := make(chan error, 1)
+	if w.settings.HandshakeTimeout > 0 {
+		go func() {
+			err := waitForHandshake(ctx, w.settings.InterfaceName,
+				w.settings.HandshakeTimeout)
+			if err != nil {
+				cancel()
+			}
+			handshakeErr <- err
+		}()
+	} else {
+		handshakeErr <- nil
+	}
+
+	err = waitAndCleanup()
+	if handshakeErr := <-handshakeErr; handshakeErr != nil {
+		err = handshakeErr
+	}
+	waitError <- err
 }
 
 func (w *Wireguard) setupIPv6(link netlink.Link, closers *closers) (err error) {
 	// requires net.ipv6.conf.all.disable_ipv6=0
-	err = w.addRoute(link, allIPv6(), w.settings.FirewallMark)
-	if err != nil {
-		if strings.Contains(err.Error(), "permission denied") {
+	for _, destination := range routeDestinations(w.settings.AllowedIPs, true) {
+		err = w.addRoute(link, destination, w.routingTable())
+		if err == nil {
+			continue
+		} else if strings.Contains(err.Error(), "permission denied") {
 			w.logger.Errorf("cannot add route for IPv6 due to a permission denial. "+
 				"Ignoring and continuing execution; "+
 				"Please report to https://github.com/qdm12/gluetun/issues/998 if you find a fix. "+
@@ -144,9 +194,7 @@ func (w *Wireguard) setupIPv6(link netlink.Link, closers *closers) (err error) {
 		return fmt.Errorf("%w: %s", ErrRouteAdd, err)
 	}
 
-	ruleCleanup6, ruleErr := w.addRule(
-		w.settings.RulePriority, w.settings.FirewallMark,
-		unix.AF_INET6)
+	ruleCleanup6, ruleErr := w.addRules(unix.AF_INET6)
 	if ruleErr != nil {
 		return fmt.Errorf("adding IPv6 rule: %w", err)
 	}
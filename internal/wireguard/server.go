@@ -0,0 +1,382 @@
+package wireguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/netlink"
+	"github.com/qdm12/gluetun/internal/routing"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var (
+	ErrPeerAddressInUse = errors.New("peer address is already in use")
+	ErrPeerExists       = errors.New("peer already exists")
+	ErrPeerNotFound     = errors.New("peer not found")
+)
+
+// Server is an inbound Wireguard server, where peers connect to
+// the server listening port and have their traffic forwarded.
+type Server struct {
+	settings   settings.WireguardServer
+	peersPath  string
+	fileWriter FileWriter
+	netlink    NetLinker
+	logger     Logger
+
+	// peers is the current list of peers and listenPort is the
+	// current listening port, which can both be modified at runtime.
+	// running indicates if the Wireguard device is configured and
+	// can be updated.
+	peers      []settings.WireguardServerPeer
+	listenPort uint16
+	running    bool
+	peersMutex sync.RWMutex
+}
+
+// NewServer creates a Wireguard server. Peers added at runtime are
+// persisted to the peers file path given, and peers from that file
+// are added to the peers from the settings when the server starts.
+func NewServer(serverSettings settings.WireguardServer, peersPath string,
+	fileWriter FileWriter, netlink NetLinker, logger Logger) *Server {
+	peers := make([]settings.WireguardServerPeer, len(serverSettings.Peers))
+	copy(peers, serverSettings.Peers)
+	return &Server{
+		settings:   serverSettings,
+		peersPath:  peersPath,
+		fileWriter: fileWriter,
+		netlink:    netlink,
+		logger:     logger,
+		peers:      peers,
+		listenPort: serverSettings.ListenPort,
+	}
+}
+
+// Run creates and configures the Wireguard server interface if the server
+// is enabled, and tears it down once the context is canceled.
+func (s *Server) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	if !*s.settings.Enabled {
+		return
+	}
+
+	err := s.loadPeers()
+	if err != nil {
+		s.logger.Error("cannot load persisted peers: " + err.Error())
+	}
+
+	err = s.run(ctx)
+	if err != nil && ctx.Err() == nil {
+		s.logger.Error(err.Error())
+	}
+}
+
+func (s *Server) run(ctx context.Context) (err error) {
+	kernelSupported, err := s.netlink.IsWireguardSupported()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDetectKernel, err)
+	}
+
+	setupFunction := setupUserSpace
+	if kernelSupported {
+		setupFunction = setupKernelSpace
+	}
+
+	client, err := wgctrl.New()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWgctrlOpen, err)
+	}
+
+	var closers closers
+	closers.add("closing controller client", stepOne, client.Close)
+	defer closers.cleanup(s.logger)
+
+	const mtu = 1420
+	link, waitAndCleanup, err := setupFunction(ctx,
+		s.settings.Interface, s.netlink, mtu, &closers, s.logger)
+	if err != nil {
+		return err
+	}
+
+	address := s.settings.Address
+	err = s.netlink.AddrAdd(link, &netlink.Addr{IPNet: routing.NetipPrefixToIPNet(&address)})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrAddAddress, err)
+	}
+
+	s.peersMutex.Lock()
+	config, err := makeServerDeviceConfig(*s.settings.PrivateKey,
+		s.listenPort, s.peers)
+	listenPort := s.listenPort
+	if err == nil {
+		err = client.ConfigureDevice(s.settings.Interface, config)
+	}
+	s.running = err == nil
+	s.peersMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigure, err)
+	}
+	defer func() {
+		s.peersMutex.Lock()
+		s.running = false
+		s.peersMutex.Unlock()
+	}()
+
+	err = s.netlink.LinkSetUp(link)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrIfaceUp, err)
+	}
+	closers.add("shutting down link", stepFour, func() error {
+		return s.netlink.LinkSetDown(link)
+	})
+
+	s.logger.Info(fmt.Sprintf("Wireguard server listening on port %d with %d peer(s)",
+		listenPort, len(s.GetPeers())))
+
+	return waitAndCleanup()
+}
+
+// GetPeers returns a copy of the current peers.
+func (s *Server) GetPeers() (peers []settings.WireguardServerPeer) {
+	s.peersMutex.RLock()
+	defer s.peersMutex.RUnlock()
+	peers = make([]settings.WireguardServerPeer, len(s.peers))
+	copy(peers, s.peers)
+	return peers
+}
+
+// SetListenPort sets the port the server listens on, reconfiguring
+// the Wireguard device if the server is running. It is used to listen
+// on the port forwarded by the VPN provider once it is known.
+func (s *Server) SetListenPort(port uint16) (err error) {
+	s.peersMutex.Lock()
+	defer s.peersMutex.Unlock()
+
+	if port == s.listenPort {
+		return nil
+	}
+
+	if s.running {
+		listenPort := int(port)
+		err = s.configureDevice(wgtypes.Config{ListenPort: &listenPort})
+		if err != nil {
+			return err
+		}
+		s.logger.Info(fmt.Sprintf("Wireguard server listening on port %d", port))
+	}
+
+	s.listenPort = port
+	return nil
+}
+
+// AddPeer validates and adds a peer, configuring the
+// Wireguard device with it if the server is running,
+// and persists it to the peers file.
+func (s *Server) AddPeer(peer settings.WireguardServerPeer) (err error) {
+	err = peer.Validate(s.settings.Address)
+	if err != nil {
+		return err
+	}
+
+	s.peersMutex.Lock()
+	defer s.peersMutex.Unlock()
+
+	err = checkPeerConflict(s.peers, peer)
+	if err != nil {
+		return err
+	}
+
+	if s.running {
+		peerConfig, err := makeServerPeerConfig(peer)
+		if err != nil {
+			return err
+		}
+		err = s.configureDevice(wgtypes.Config{Peers: []wgtypes.PeerConfig{peerConfig}})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.peers = append(s.peers, peer)
+	s.savePeers()
+	return nil
+}
+
+// RemovePeer removes the peer with the public key given,
+// removing it from the Wireguard device if the server is running,
+// and from the peers file.
+func (s *Server) RemovePeer(publicKey string) (err error) {
+	s.peersMutex.Lock()
+	defer s.peersMutex.Unlock()
+
+	index := -1
+	for i, peer := range s.peers {
+		if peer.PublicKey == publicKey {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, publicKey)
+	}
+
+	if s.running {
+		key, err := wgtypes.ParseKey(publicKey)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrPublicKeyInvalid, publicKey)
+		}
+		err = s.configureDevice(wgtypes.Config{Peers: []wgtypes.PeerConfig{
+			{PublicKey: key, Remove: true},
+		}})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.peers = append(s.peers[:index], s.peers[index+1:]...)
+	s.savePeers()
+	return nil
+}
+
+// loadPeers adds the peers persisted in the peers file, if it exists,
+// to the current peers. Peers conflicting with the current peers or
+// not valid for the server address are skipped.
+func (s *Server) loadPeers() (err error) {
+	data, err := os.ReadFile(s.peersPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var persistedPeers []settings.WireguardServerPeer
+	err = json.Unmarshal(data, &persistedPeers)
+	if err != nil {
+		return fmt.Errorf("decoding JSON data: %w", err)
+	}
+
+	s.peersMutex.Lock()
+	defer s.peersMutex.Unlock()
+	for _, peer := range persistedPeers {
+		err = peer.Validate(s.settings.Address)
+		if err == nil {
+			err = checkPeerConflict(s.peers, peer)
+		}
+		if err != nil {
+			if !errors.Is(err, ErrPeerExists) {
+				s.logger.Error("skipping persisted peer: " + err.Error())
+			}
+			continue
+		}
+		s.peers = append(s.peers, peer)
+	}
+	return nil
+}
+
+// savePeers writes the current peers not defined in the settings
+// to the peers file, and must be called with the peers mutex locked.
+func (s *Server) savePeers() {
+	runtimePeers := make([]settings.WireguardServerPeer, 0, len(s.peers))
+	for _, peer := range s.peers {
+		if checkPeerConflict(s.settings.Peers, peer) == nil {
+			runtimePeers = append(runtimePeers, peer)
+		}
+	}
+
+	data, err := json.Marshal(runtimePeers)
+	if err != nil {
+		s.logger.Error("cannot encode peers: " + err.Error())
+		return
+	}
+
+	const perms = 0600
+	err = s.fileWriter.WriteFile(s.peersPath, data, perms)
+	if err != nil {
+		s.logger.Error("cannot persist peers: " + err.Error())
+	}
+}
+
+func checkPeerConflict(peers []settings.WireguardServerPeer,
+	peer settings.WireguardServerPeer) (err error) {
+	for _, existing := range peers {
+		switch {
+		case existing.PublicKey == peer.PublicKey:
+			return fmt.Errorf("%w: %s", ErrPeerExists, peer.PublicKey)
+		case existing.Address.Addr() == peer.Address.Addr():
+			return fmt.Errorf("%w: %s", ErrPeerAddressInUse, peer.Address.Addr())
+		}
+	}
+	return nil
+}
+
+func (s *Server) configureDevice(config wgtypes.Config) (err error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWgctrlOpen, err)
+	}
+	defer client.Close()
+
+	err = client.ConfigureDevice(s.settings.Interface, config)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigure, err)
+	}
+	return nil
+}
+
+func makeServerDeviceConfig(privateKey string, listenPort uint16,
+	peers []settings.WireguardServerPeer) (config wgtypes.Config, err error) {
+	key, err := wgtypes.ParseKey(privateKey)
+	if err != nil {
+		return config, ErrPrivateKeyInvalid
+	}
+
+	port := int(listenPort)
+	config = wgtypes.Config{
+		PrivateKey:   &key,
+		ListenPort:   &port,
+		ReplacePeers: true,
+		Peers:        make([]wgtypes.PeerConfig, len(peers)),
+	}
+	for i, peer := range peers {
+		config.Peers[i], err = makeServerPeerConfig(peer)
+		if err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+func makeServerPeerConfig(peer settings.WireguardServerPeer) (
+	config wgtypes.PeerConfig, err error) {
+	publicKey, err := wgtypes.ParseKey(peer.PublicKey)
+	if err != nil {
+		return config, fmt.Errorf("%w: %s", ErrPublicKeyInvalid, peer.PublicKey)
+	}
+
+	var preSharedKey *wgtypes.Key
+	if peer.PreSharedKey != "" {
+		preSharedKeyValue, err := wgtypes.ParseKey(peer.PreSharedKey)
+		if err != nil {
+			return config, ErrPreSharedKeyInvalid
+		}
+		preSharedKey = &preSharedKeyValue
+	}
+
+	// Each peer is only allowed its own single address.
+	allowedIP := netip.PrefixFrom(peer.Address.Addr(), peer.Address.Addr().BitLen())
+	return wgtypes.PeerConfig{
+		PublicKey:         publicKey,
+		PresharedKey:      preSharedKey,
+		ReplaceAllowedIPs: true,
+		AllowedIPs:        []net.IPNet{*routing.NetipPrefixToIPNet(&allowedIP)},
+	}, nil
+}
@@ -0,0 +1,61 @@
+package wireguard
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func Test_Server_persistPeers(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	makePeer := func(address string) settings.WireguardServerPeer {
+		privateKey, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		return settings.WireguardServerPeer{
+			PublicKey: privateKey.PublicKey().String(),
+			Address:   netip.MustParsePrefix(address),
+		}
+	}
+	settingsPeer := makePeer("10.64.0.2/32")
+	runtimePeer := makePeer("10.64.0.3/32")
+
+	serverSettings := settings.WireguardServer{
+		Address: netip.MustParsePrefix("10.64.0.1/24"),
+		Peers:   []settings.WireguardServerPeer{settingsPeer},
+	}
+	peersPath := filepath.Join(t.TempDir(), "peers.json")
+	fileWriter := system.NewFileWriter(os.Getuid(), os.Getgid())
+	logger := NewMockLogger(ctrl)
+
+	server := NewServer(serverSettings, peersPath, fileWriter, nil, logger)
+	err := server.AddPeer(runtimePeer)
+	require.NoError(t, err)
+
+	// Peers from the settings are not persisted.
+	data, err := os.ReadFile(peersPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"public_key":"`+runtimePeer.PublicKey+
+		`","address":"10.64.0.3/32"}]`, string(data))
+
+	server = NewServer(serverSettings, peersPath, fileWriter, nil, logger)
+	err = server.loadPeers()
+	require.NoError(t, err)
+	assert.Equal(t, []settings.WireguardServerPeer{settingsPeer, runtimePeer},
+		server.GetPeers())
+
+	err = server.RemovePeer(runtimePeer.PublicKey)
+	require.NoError(t, err)
+	data, err = os.ReadFile(peersPath)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+}
@@ -6,6 +6,7 @@ import (
 	"net/netip"
 	"regexp"
 	"strings"
+	"time"
 
 	"golang.zx2c4.com/wireguard/device"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -23,9 +24,24 @@ type Settings struct {
 	PreSharedKey string
 	// Wireguard server endpoint to connect to.
 	Endpoint netip.AddrPort
+	// TLS is true if the Wireguard packets should be tunneled
+	// through a TLS over TCP connection to the endpoint, such
+	// as for ProtonVPN Stealth. It defaults to false.
+	TLS bool
+	// TLSServerName is the server name to use for the
+	// TLS handshake, and is only used if TLS is true.
+	TLSServerName string
+	// SocksProxy contains settings of a SOCKS5 proxy to connect
+	// to the endpoint through. It is not used if its address is
+	// not set.
+	SocksProxy SocksProxy
 	// Addresses assigned to the client.
 	// Note IPv6 addresses are ignored if IPv6 is not supported.
 	Addresses []netip.Prefix
+	// AllowedIPs are the IP networks allowed for the peer and
+	// routed through the tunnel. If empty, all IPv4 and IPv6
+	// traffic is routed through the tunnel.
+	AllowedIPs []netip.Prefix
 	// FirewallMark to be used in routing tables and IP rules.
 	// It defaults to 51820 if left to 0.
 	FirewallMark int
@@ -35,12 +51,24 @@ type Settings struct {
 	// RulePriority is the priority for the rule created with the
 	// FirewallMark.
 	RulePriority int
+	// SteeringMark, if not zero, restricts the tunnel to only route
+	// packets marked with it or coming from one of SteeringSources,
+	// instead of routing all packets not marked with FirewallMark.
+	// It is also used as the routing table number.
+	SteeringMark int
+	// SteeringSources are source networks to route through the
+	// tunnel, and are only used if SteeringMark is set.
+	SteeringSources []netip.Prefix
 	// IPv6 can bet set to true if IPv6 should be handled.
 	// It defaults to false if left unset.
 	IPv6 *bool
 	// Implementation is the implementation to use.
 	// It can be auto, kernelspace or userspace, and defaults to auto.
 	Implementation string
+	// HandshakeTimeout is the maximum duration to wait for the first
+	// handshake with the server once the interface is up, after which
+	// Run exits with ErrHandshakeTimeout. It is disabled if left to 0.
+	HandshakeTimeout time.Duration
 }
 
 func (s *Settings) SetDefaults() {
@@ -85,6 +113,7 @@ var (
 	ErrEndpointPortMissing   = errors.New("endpoint port is missing")
 	ErrAddressMissing        = errors.New("interface address is missing")
 	ErrAddressNotValid       = errors.New("interface address is not valid")
+	ErrAllowedIPNotValid     = errors.New("allowed IP network is not valid")
 	ErrFirewallMarkMissing   = errors.New("firewall mark is missing")
 	ErrMTUMissing            = errors.New("MTU is missing")
 	ErrImplementationInvalid = errors.New("invalid implementation")
@@ -132,6 +161,13 @@ func (s *Settings) Check() (err error) {
 		}
 	}
 
+	for i, allowedIP := range s.AllowedIPs {
+		if !allowedIP.IsValid() {
+			return fmt.Errorf("%w: for allowed IP network %d of %d",
+				ErrAllowedIPNotValid, i+1, len(s.AllowedIPs))
+		}
+	}
+
 	if s.FirewallMark == 0 {
 		return fmt.Errorf("%w", ErrFirewallMarkMissing)
 	}
@@ -212,6 +248,14 @@ func (s Settings) ToLines(settings ToLinesSettings) (lines []string) {
 	}
 	lines = append(lines, fieldPrefix+"Endpoint: "+endpointStr)
 
+	if s.TLS {
+		lines = append(lines, fieldPrefix+"Tunneled through TLS: yes")
+	}
+
+	if s.SocksProxy.Enabled() {
+		lines = append(lines, fieldPrefix+"SOCKS proxy: "+s.SocksProxy.Address.String())
+	}
+
 	ipv6Status := "disabled"
 	if *s.IPv6 {
 		ipv6Status = "enabled"
@@ -230,10 +274,25 @@ func (s Settings) ToLines(settings ToLinesSettings) (lines []string) {
 		lines = append(lines, fieldPrefix+"Rule priority: "+fmt.Sprint(s.RulePriority))
 	}
 
+	if s.SteeringMark != 0 {
+		lines = append(lines, fieldPrefix+"Steering mark: "+fmt.Sprint(s.SteeringMark))
+		for _, source := range s.SteeringSources {
+			lines = append(lines, fieldPrefix+"Steering source: "+source.String())
+		}
+	}
+
+	for _, allowedIP := range s.AllowedIPs {
+		lines = append(lines, fieldPrefix+"Allowed IP: "+allowedIP.String())
+	}
+
 	if s.Implementation != "auto" {
 		lines = append(lines, fieldPrefix+"Implementation: "+s.Implementation)
 	}
 
+	if s.HandshakeTimeout > 0 {
+		lines = append(lines, fieldPrefix+"Handshake timeout: "+s.HandshakeTimeout.String())
+	}
+
 	if len(s.Addresses) == 0 {
 		lines = append(lines, lastFieldPrefix+"Addresses: "+notSet)
 	} else {
@@ -0,0 +1,292 @@
+package wireguard
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// SocksProxy contains settings of a SOCKS5 proxy
+// to connect to the Wireguard endpoint through.
+type SocksProxy struct {
+	// Address is the address of the SOCKS5 proxy.
+	// The proxy is not used if it is the zero value.
+	Address netip.AddrPort
+	// Username is the username to authenticate with
+	// the proxy, and can be empty to not authenticate.
+	Username string
+	// Password is the password to authenticate with the proxy.
+	Password string
+}
+
+// Enabled returns true if the proxy address is set.
+func (s SocksProxy) Enabled() bool {
+	return s.Address.IsValid()
+}
+
+// newMarkedDialer returns a dialer marking its sockets with the
+// firewall mark given, so they are routed outside the tunnel.
+func newMarkedDialer(firewallMark int) *net.Dialer {
+	const dialTimeout = 10 * time.Second
+	return &net.Dialer{
+		Timeout: dialTimeout,
+		Control: func(_, _ string, rawConn syscall.RawConn) (err error) {
+			controlErr := rawConn.Control(func(fd uintptr) {
+				err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_MARK, firewallMark)
+			})
+			if controlErr != nil {
+				return controlErr
+			}
+			return err
+		},
+	}
+}
+
+const (
+	socksVersion = 5
+
+	socksMethodNoAuth       = 0x00
+	socksMethodUserPass     = 0x02
+	socksMethodNoAcceptable = 0xff
+	socksUserPassVersion    = 1
+
+	socksCommandConnect      = 0x01
+	socksCommandUDPAssociate = 0x03
+
+	socksAddressIPv4   = 0x01
+	socksAddressDomain = 0x03
+	socksAddressIPv6   = 0x04
+)
+
+var (
+	ErrSocksVersionNotValid      = errors.New("SOCKS version is not valid")
+	ErrSocksNoAcceptableMethod   = errors.New("no acceptable SOCKS authentication method")
+	ErrSocksMethodNotValid       = errors.New("SOCKS authentication method is not valid")
+	ErrSocksAuthenticationFailed = errors.New("SOCKS authentication failed")
+	ErrSocksRequestFailed        = errors.New("SOCKS request failed")
+	ErrSocksAddressTypeNotValid  = errors.New("SOCKS address type is not valid")
+	ErrSocksFragmentNotSupported = errors.New("SOCKS UDP fragmentation is not supported")
+	ErrSocksDatagramTooShort     = errors.New("SOCKS UDP datagram is too short")
+)
+
+// socksNegotiate authenticates with the SOCKS5 proxy and sends it the
+// command given for the destination address given, with a timeout
+// bounding the whole negotiation. It returns the address bound by the
+// proxy, which is the UDP relay address for a UDP associate command.
+func socksNegotiate(ctx context.Context, conn net.Conn, proxy SocksProxy,
+	command byte, destination netip.AddrPort) (bound netip.AddrPort, err error) {
+	const negotiationTimeout = 10 * time.Second
+	deadline := time.Now().Add(negotiationTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return bound, fmt.Errorf("setting deadline: %w", err)
+	}
+
+	err = socksAuthenticate(conn, proxy.Username, proxy.Password)
+	if err != nil {
+		return bound, fmt.Errorf("authenticating: %w", err)
+	}
+
+	bound, err = socksRequest(conn, command, destination)
+	if err != nil {
+		return bound, err
+	}
+
+	err = conn.SetDeadline(time.Time{})
+	if err != nil {
+		return bound, fmt.Errorf("resetting deadline: %w", err)
+	}
+	return bound, nil
+}
+
+// socksAuthenticate negotiates the authentication method with the
+// proxy, and authenticates with the username and password given if
+// the username is not empty.
+func socksAuthenticate(stream io.ReadWriter, username, password string) (err error) {
+	methods := []byte{socksMethodNoAuth}
+	if username != "" {
+		methods = append(methods, socksMethodUserPass)
+	}
+	greeting := append([]byte{socksVersion, byte(len(methods))}, methods...)
+	_, err = stream.Write(greeting)
+	if err != nil {
+		return fmt.Errorf("writing greeting: %w", err)
+	}
+
+	response := make([]byte, 2) //nolint:gomnd
+	_, err = io.ReadFull(stream, response)
+	if err != nil {
+		return fmt.Errorf("reading method selection: %w", err)
+	}
+	if response[0] != socksVersion {
+		return fmt.Errorf("%w: %d", ErrSocksVersionNotValid, response[0])
+	}
+
+	switch response[1] {
+	case socksMethodNoAuth:
+		return nil
+	case socksMethodUserPass:
+		if username == "" {
+			return fmt.Errorf("%w: username and password method not offered",
+				ErrSocksMethodNotValid)
+		}
+	case socksMethodNoAcceptable:
+		return fmt.Errorf("%w", ErrSocksNoAcceptableMethod)
+	default:
+		return fmt.Errorf("%w: 0x%02x", ErrSocksMethodNotValid, response[1])
+	}
+
+	// See RFC 1929
+	request := make([]byte, 0, 3+len(username)+len(password)) //nolint:gomnd
+	request = append(request, socksUserPassVersion, byte(len(username)))
+	request = append(request, username...)
+	request = append(request, byte(len(password)))
+	request = append(request, password...)
+	_, err = stream.Write(request)
+	if err != nil {
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+
+	_, err = io.ReadFull(stream, response)
+	if err != nil {
+		return fmt.Errorf("reading authentication status: %w", err)
+	}
+	if response[1] != 0 {
+		return fmt.Errorf("%w: status 0x%02x", ErrSocksAuthenticationFailed, response[1])
+	}
+	return nil
+}
+
+// socksRequest sends the command given for the destination address
+// given to the proxy, and returns the address bound by the proxy.
+func socksRequest(stream io.ReadWriter, command byte,
+	destination netip.AddrPort) (bound netip.AddrPort, err error) {
+	request := append([]byte{socksVersion, command, 0}, encodeSocksAddress(destination)...)
+	_, err = stream.Write(request)
+	if err != nil {
+		return bound, fmt.Errorf("writing request: %w", err)
+	}
+
+	const headerLength = 3
+	header := make([]byte, headerLength)
+	_, err = io.ReadFull(stream, header)
+	if err != nil {
+		return bound, fmt.Errorf("reading reply: %w", err)
+	}
+	if header[0] != socksVersion {
+		return bound, fmt.Errorf("%w: %d", ErrSocksVersionNotValid, header[0])
+	} else if header[1] != 0 {
+		return bound, fmt.Errorf("%w: reply code 0x%02x", ErrSocksRequestFailed, header[1])
+	}
+
+	bound, err = readSocksAddress(stream)
+	if err != nil {
+		return bound, fmt.Errorf("reading bound address: %w", err)
+	}
+	return bound, nil
+}
+
+func encodeSocksAddress(address netip.AddrPort) (encoded []byte) {
+	ip := address.Addr().Unmap()
+	if ip.Is4() {
+		encoded = append([]byte{socksAddressIPv4}, ip.AsSlice()...)
+	} else {
+		encoded = append([]byte{socksAddressIPv6}, ip.AsSlice()...)
+	}
+	return binary.BigEndian.AppendUint16(encoded, address.Port())
+}
+
+// readSocksAddress reads an IP address and port from the reader.
+// Domain name addresses are read but returned as the zero value,
+// since they cannot be resolved before the tunnel is up.
+func readSocksAddress(reader io.Reader) (address netip.AddrPort, err error) {
+	addressType := make([]byte, 1)
+	_, err = io.ReadFull(reader, addressType)
+	if err != nil {
+		return address, err
+	}
+
+	var length int
+	switch addressType[0] {
+	case socksAddressIPv4:
+		length = net.IPv4len
+	case socksAddressIPv6:
+		length = net.IPv6len
+	case socksAddressDomain:
+		_, err = io.ReadFull(reader, addressType)
+		if err != nil {
+			return address, err
+		}
+		length = int(addressType[0])
+	default:
+		return address, fmt.Errorf("%w: 0x%02x", ErrSocksAddressTypeNotValid, addressType[0])
+	}
+
+	const portLength = 2
+	data := make([]byte, length+portLength)
+	_, err = io.ReadFull(reader, data)
+	if err != nil {
+		return address, err
+	}
+	port := binary.BigEndian.Uint16(data[length:])
+
+	if addressType[0] == socksAddressDomain {
+		return netip.AddrPortFrom(netip.Addr{}, port), nil
+	}
+	ip, _ := netip.AddrFromSlice(data[:length])
+	return netip.AddrPortFrom(ip, port), nil
+}
+
+// encodeSocksDatagram prefixes the packet with the SOCKS5
+// UDP request header for the destination given.
+func encodeSocksDatagram(destination netip.AddrPort, packet []byte) (datagram []byte) {
+	address := encodeSocksAddress(destination)
+	const reservedAndFragmentLength = 3
+	datagram = make([]byte, reservedAndFragmentLength, reservedAndFragmentLength+len(address)+len(packet))
+	datagram = append(datagram, address...)
+	return append(datagram, packet...)
+}
+
+// decodeSocksDatagram returns the packet contained in the
+// SOCKS5 UDP datagram given, without its header.
+func decodeSocksDatagram(datagram []byte) (packet []byte, err error) {
+	const reservedAndFragmentLength = 3
+	if len(datagram) < reservedAndFragmentLength+1 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrSocksDatagramTooShort, len(datagram))
+	} else if datagram[2] != 0 {
+		return nil, fmt.Errorf("%w", ErrSocksFragmentNotSupported)
+	}
+
+	const portLength = 2
+	headerLength := reservedAndFragmentLength + 1 + portLength
+	switch datagram[reservedAndFragmentLength] {
+	case socksAddressIPv4:
+		headerLength += net.IPv4len
+	case socksAddressIPv6:
+		headerLength += net.IPv6len
+	case socksAddressDomain:
+		if len(datagram) < reservedAndFragmentLength+2 { //nolint:gomnd
+			return nil, fmt.Errorf("%w: %d bytes", ErrSocksDatagramTooShort, len(datagram))
+		}
+		headerLength += 1 + int(datagram[reservedAndFragmentLength+1])
+	default:
+		return nil, fmt.Errorf("%w: 0x%02x", ErrSocksAddressTypeNotValid,
+			datagram[reservedAndFragmentLength])
+	}
+
+	if len(datagram) < headerLength {
+		return nil, fmt.Errorf("%w: %d bytes", ErrSocksDatagramTooShort, len(datagram))
+	}
+	return datagram[headerLength:], nil
+}
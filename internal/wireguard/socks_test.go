@@ -0,0 +1,176 @@
+package wireguard
+
+import (
+	"bytes"
+	"io"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStream reads from the reader and records writes to the writer.
+type fakeStream struct {
+	io.Reader
+	io.Writer
+}
+
+func Test_socksAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		username   string
+		password   string
+		response   []byte
+		written    []byte
+		errWrapped error
+		errMessage string
+	}{
+		"no authentication": {
+			response: []byte{5, 0},
+			written:  []byte{5, 1, 0},
+		},
+		"username and password": {
+			username: "user",
+			password: "pass",
+			response: []byte{5, 2, 1, 0},
+			written: []byte{5, 2, 0, 2,
+				1, 4, 'u', 's', 'e', 'r', 4, 'p', 'a', 's', 's'},
+		},
+		"authentication failed": {
+			username:   "user",
+			response:   []byte{5, 2, 1, 1},
+			written:    []byte{5, 2, 0, 2, 1, 4, 'u', 's', 'e', 'r', 0},
+			errWrapped: ErrSocksAuthenticationFailed,
+			errMessage: "SOCKS authentication failed: status 0x01",
+		},
+		"no acceptable method": {
+			response:   []byte{5, 0xff},
+			written:    []byte{5, 1, 0},
+			errWrapped: ErrSocksNoAcceptableMethod,
+			errMessage: "no acceptable SOCKS authentication method",
+		},
+		"method not offered": {
+			response:   []byte{5, 2},
+			written:    []byte{5, 1, 0},
+			errWrapped: ErrSocksMethodNotValid,
+			errMessage: "SOCKS authentication method is not valid: " +
+				"username and password method not offered",
+		},
+		"bad version": {
+			response:   []byte{4, 0},
+			written:    []byte{5, 1, 0},
+			errWrapped: ErrSocksVersionNotValid,
+			errMessage: "SOCKS version is not valid: 4",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			written := bytes.NewBuffer(nil)
+			stream := fakeStream{
+				Reader: bytes.NewReader(testCase.response),
+				Writer: written,
+			}
+
+			err := socksAuthenticate(stream, testCase.username, testCase.password)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.written, written.Bytes())
+		})
+	}
+}
+
+func Test_socksRequest(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		command     byte
+		destination netip.AddrPort
+		response    []byte
+		written     []byte
+		bound       netip.AddrPort
+		errWrapped  error
+		errMessage  string
+	}{
+		"connect IPv4": {
+			command:     socksCommandConnect,
+			destination: netip.MustParseAddrPort("1.2.3.4:443"),
+			response:    []byte{5, 0, 0, 1, 10, 0, 0, 1, 0x1f, 0x90},
+			written:     []byte{5, 1, 0, 1, 1, 2, 3, 4, 0x01, 0xbb},
+			bound:       netip.MustParseAddrPort("10.0.0.1:8080"),
+		},
+		"UDP associate IPv6": {
+			command:     socksCommandUDPAssociate,
+			destination: netip.MustParseAddrPort("[::1]:0"),
+			response: []byte{5, 0, 0, 4,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 53},
+			written: []byte{5, 3, 0, 4,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0},
+			bound: netip.MustParseAddrPort("[::2]:53"),
+		},
+		"domain bound address": {
+			command:     socksCommandUDPAssociate,
+			destination: netip.MustParseAddrPort("0.0.0.0:0"),
+			response:    []byte{5, 0, 0, 3, 3, 'a', '.', 'b', 0, 53},
+			written:     []byte{5, 3, 0, 1, 0, 0, 0, 0, 0, 0},
+			bound:       netip.AddrPortFrom(netip.Addr{}, 53),
+		},
+		"request failed": {
+			command:     socksCommandConnect,
+			destination: netip.MustParseAddrPort("1.2.3.4:443"),
+			response:    []byte{5, 5, 0},
+			written:     []byte{5, 1, 0, 1, 1, 2, 3, 4, 0x01, 0xbb},
+			errWrapped:  ErrSocksRequestFailed,
+			errMessage:  "SOCKS request failed: reply code 0x05",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			written := bytes.NewBuffer(nil)
+			stream := fakeStream{
+				Reader: bytes.NewReader(testCase.response),
+				Writer: written,
+			}
+
+			bound, err := socksRequest(stream, testCase.command, testCase.destination)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.bound, bound)
+			assert.Equal(t, testCase.written, written.Bytes())
+		})
+	}
+}
+
+func Test_encodeSocksDatagram_decodeSocksDatagram(t *testing.T) {
+	t.Parallel()
+
+	destination := netip.MustParseAddrPort("1.2.3.4:51820")
+	datagram := encodeSocksDatagram(destination, []byte{9, 8, 7})
+	expected := []byte{0, 0, 0, 1, 1, 2, 3, 4, 0xca, 0x6c, 9, 8, 7}
+	assert.Equal(t, expected, datagram)
+
+	packet, err := decodeSocksDatagram(datagram)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{9, 8, 7}, packet)
+
+	_, err = decodeSocksDatagram([]byte{0, 0, 1, 1, 1, 2, 3, 4, 0, 1})
+	assert.ErrorIs(t, err, ErrSocksFragmentNotSupported)
+
+	_, err = decodeSocksDatagram([]byte{0, 0, 0, 4, 1, 2})
+	assert.ErrorIs(t, err, ErrSocksDatagramTooShort)
+}
@@ -0,0 +1,152 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+)
+
+// socksRelay relays Wireguard UDP packets received on a local
+// UDP socket to the server through the UDP relay of a SOCKS5 proxy,
+// and relays packets received from the proxy back to the local
+// Wireguard socket. The UDP association lasts as long as the
+// TCP control connection to the proxy is open.
+type socksRelay struct {
+	udpConn     *net.UDPConn
+	proxyConn   net.Conn
+	controlConn net.Conn
+	endpoint    netip.AddrPort
+	logger      Logger
+	// peer is the address of the local Wireguard socket,
+	// learned from the first packet it sends.
+	peer      *net.UDPAddr
+	peerMutex sync.RWMutex
+	wg        sync.WaitGroup
+}
+
+func startSocksRelay(ctx context.Context, endpoint netip.AddrPort,
+	proxy SocksProxy, firewallMark int, logger Logger) (
+	relay *socksRelay, err error) {
+	dialer := newMarkedDialer(firewallMark)
+	controlConn, err := dialer.DialContext(ctx, "tcp", proxy.Address.String())
+	if err != nil {
+		return nil, fmt.Errorf("dialing SOCKS proxy: %w", err)
+	}
+
+	// The client address is unknown since the UDP socket to the
+	// relay is only created once the association is made.
+	unspecified := netip.AddrPortFrom(netip.IPv4Unspecified(), 0)
+	relayAddress, err := socksNegotiate(ctx, controlConn, proxy,
+		socksCommandUDPAssociate, unspecified)
+	if err != nil {
+		_ = controlConn.Close()
+		return nil, fmt.Errorf("associating UDP: %w", err)
+	}
+	if !relayAddress.Addr().IsValid() || relayAddress.Addr().IsUnspecified() {
+		// the relay listens on the same address as the proxy
+		relayAddress = netip.AddrPortFrom(proxy.Address.Addr(), relayAddress.Port())
+	}
+
+	proxyConn, err := dialer.DialContext(ctx, "udp", relayAddress.String())
+	if err != nil {
+		_ = controlConn.Close()
+		return nil, fmt.Errorf("dialing SOCKS UDP relay: %w", err)
+	}
+
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}) //nolint:gomnd
+	if err != nil {
+		_ = proxyConn.Close()
+		_ = controlConn.Close()
+		return nil, fmt.Errorf("listening on local UDP socket: %w", err)
+	}
+
+	relay = &socksRelay{
+		udpConn:     udpConn,
+		proxyConn:   proxyConn,
+		controlConn: controlConn,
+		endpoint:    endpoint,
+		logger:      logger,
+	}
+
+	const goroutines = 2
+	relay.wg.Add(goroutines)
+	go relay.relayToServer()
+	go relay.relayToLocal()
+
+	return relay, nil
+}
+
+func (r *socksRelay) localAddress() (address netip.AddrPort) {
+	return r.udpConn.LocalAddr().(*net.UDPAddr).AddrPort() //nolint:forcetypeassert
+}
+
+func (r *socksRelay) close() (err error) {
+	udpErr := r.udpConn.Close()
+	proxyErr := r.proxyConn.Close()
+	controlErr := r.controlConn.Close()
+	r.wg.Wait()
+	return errors.Join(udpErr, proxyErr, controlErr)
+}
+
+func (r *socksRelay) relayToServer() {
+	defer r.wg.Done()
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, peer, err := r.udpConn.ReadFromUDP(buffer)
+		if err != nil {
+			r.logError("reading from Wireguard", err)
+			return
+		}
+
+		r.peerMutex.Lock()
+		r.peer = peer
+		r.peerMutex.Unlock()
+
+		_, err = r.proxyConn.Write(encodeSocksDatagram(r.endpoint, buffer[:n]))
+		if err != nil {
+			r.logError("writing to proxy", err)
+			return
+		}
+	}
+}
+
+func (r *socksRelay) relayToLocal() {
+	defer r.wg.Done()
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, err := r.proxyConn.Read(buffer)
+		if err != nil {
+			r.logError("reading from proxy", err)
+			return
+		}
+
+		packet, err := decodeSocksDatagram(buffer[:n])
+		if err != nil {
+			r.logError("decoding datagram", err)
+			continue
+		}
+
+		r.peerMutex.RLock()
+		peer := r.peer
+		r.peerMutex.RUnlock()
+		if peer == nil {
+			continue // Wireguard did not send any packet yet
+		}
+
+		_, err = r.udpConn.WriteToUDP(packet, peer)
+		if err != nil {
+			r.logError("writing to Wireguard", err)
+			return
+		}
+	}
+}
+
+func (r *socksRelay) logError(operation string, err error) {
+	if errors.Is(err, net.ErrClosed) {
+		return // relay closed
+	}
+	r.logger.Error("SOCKS relay: " + operation + ": " + err.Error())
+}
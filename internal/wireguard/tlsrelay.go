@@ -0,0 +1,176 @@
+package wireguard
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+)
+
+// tlsRelay relays Wireguard UDP packets received on a local
+// UDP socket through a TLS over TCP connection to the server,
+// and relays packets received from the server back to the
+// local Wireguard socket. Each packet is prefixed with its
+// length as a 2 bytes big endian unsigned integer in the TLS stream.
+type tlsRelay struct {
+	udpConn *net.UDPConn
+	tlsConn *tls.Conn
+	logger  Logger
+	// peer is the address of the local Wireguard socket,
+	// learned from the first packet it sends.
+	peer      *net.UDPAddr
+	peerMutex sync.RWMutex
+	wg        sync.WaitGroup
+}
+
+func startTLSRelay(ctx context.Context, endpoint netip.AddrPort,
+	serverName string, proxy SocksProxy, firewallMark int, logger Logger) (
+	relay *tlsRelay, err error) {
+	// Mark the connection so it is routed outside the tunnel.
+	dialer := newMarkedDialer(firewallMark)
+	dialAddress := endpoint
+	if proxy.Enabled() {
+		dialAddress = proxy.Address
+	}
+	rawConn, err := dialer.DialContext(ctx, "tcp", dialAddress.String())
+	if err != nil {
+		return nil, fmt.Errorf("dialing TCP connection: %w", err)
+	}
+
+	if proxy.Enabled() {
+		_, err = socksNegotiate(ctx, rawConn, proxy, socksCommandConnect, endpoint)
+		if err != nil {
+			_ = rawConn.Close()
+			return nil, fmt.Errorf("connecting through SOCKS proxy: %w", err)
+		}
+	}
+
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+		// TLS is only used to disguise the traffic, the Wireguard
+		// protocol authenticates the server with its public key.
+		InsecureSkipVerify: true, //nolint:gosec
+	})
+	err = conn.HandshakeContext(ctx)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}) //nolint:gomnd
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("listening on local UDP socket: %w", err)
+	}
+
+	relay = &tlsRelay{
+		udpConn: udpConn,
+		tlsConn: conn,
+		logger:  logger,
+	}
+
+	const goroutines = 2
+	relay.wg.Add(goroutines)
+	go relay.relayToServer()
+	go relay.relayToLocal()
+
+	return relay, nil
+}
+
+func (r *tlsRelay) localAddress() (address netip.AddrPort) {
+	return r.udpConn.LocalAddr().(*net.UDPAddr).AddrPort() //nolint:forcetypeassert
+}
+
+func (r *tlsRelay) close() (err error) {
+	udpErr := r.udpConn.Close()
+	tlsErr := r.tlsConn.Close()
+	r.wg.Wait()
+	return errors.Join(udpErr, tlsErr)
+}
+
+func (r *tlsRelay) relayToServer() {
+	defer r.wg.Done()
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, peer, err := r.udpConn.ReadFromUDP(buffer)
+		if err != nil {
+			r.logError("reading from Wireguard", err)
+			return
+		}
+
+		r.peerMutex.Lock()
+		r.peer = peer
+		r.peerMutex.Unlock()
+
+		err = writeFrame(r.tlsConn, buffer[:n])
+		if err != nil {
+			r.logError("writing to server", err)
+			return
+		}
+	}
+}
+
+func (r *tlsRelay) relayToLocal() {
+	defer r.wg.Done()
+	buffer := make([]byte, maxPacketSize)
+	for {
+		packet, err := readFrame(r.tlsConn, buffer)
+		if err != nil {
+			r.logError("reading from server", err)
+			return
+		}
+
+		r.peerMutex.RLock()
+		peer := r.peer
+		r.peerMutex.RUnlock()
+		if peer == nil {
+			continue // Wireguard did not send any packet yet
+		}
+
+		_, err = r.udpConn.WriteToUDP(packet, peer)
+		if err != nil {
+			r.logError("writing to Wireguard", err)
+			return
+		}
+	}
+}
+
+func (r *tlsRelay) logError(operation string, err error) {
+	if errors.Is(err, net.ErrClosed) {
+		return // relay closed
+	}
+	r.logger.Error("TLS relay: " + operation + ": " + err.Error())
+}
+
+const maxPacketSize = 65535
+
+func writeFrame(writer io.Writer, packet []byte) (err error) {
+	const lengthSize = 2
+	frame := make([]byte, lengthSize+len(packet))
+	binary.BigEndian.PutUint16(frame, uint16(len(packet)))
+	copy(frame[lengthSize:], packet)
+	_, err = writer.Write(frame)
+	return err
+}
+
+func readFrame(reader io.Reader, buffer []byte) (packet []byte, err error) {
+	const lengthSize = 2
+	_, err = io.ReadFull(reader, buffer[:lengthSize])
+	if err != nil {
+		return nil, fmt.Errorf("reading packet length: %w", err)
+	}
+	length := int(binary.BigEndian.Uint16(buffer[:lengthSize]))
+
+	packet = buffer[:length]
+	_, err = io.ReadFull(reader, packet)
+	if err != nil {
+		return nil, fmt.Errorf("reading packet: %w", err)
+	}
+	return packet, nil
+}
@@ -0,0 +1,33 @@
+package wireguard
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeFrame_readFrame(t *testing.T) {
+	t.Parallel()
+
+	stream := bytes.NewBuffer(nil)
+
+	err := writeFrame(stream, []byte{1, 2, 3})
+	require.NoError(t, err)
+	err = writeFrame(stream, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 3, 1, 2, 3, 0, 0}, stream.Bytes())
+
+	buffer := make([]byte, maxPacketSize)
+	packet, err := readFrame(stream, buffer)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, packet)
+
+	packet, err = readFrame(stream, buffer)
+	require.NoError(t, err)
+	assert.Empty(t, packet)
+
+	_, err = readFrame(stream, buffer)
+	assert.EqualError(t, err, "reading packet length: EOF")
+}